@@ -49,7 +49,7 @@ func Start(cfg *static.Config) (*Cmd, error) {
 	scriptEngine := engine.New(watcher, app, cfg, true)
 
 	http := server.NewHttpManager(scriptEngine, certStore, app)
-	kafka := server.NewKafkaManager(scriptEngine, app)
+	kafka := server.NewKafkaManager(scriptEngine, certStore, app)
 	mailManager := server.NewMailManager(app, scriptEngine, certStore)
 	ldap := server.NewLdapDirectoryManager(scriptEngine, certStore, app)
 
@@ -143,7 +143,7 @@ func (suite *PetStoreSuite) TestGetOrderById() {
 		map[string]string{"Accept": "application/json"},
 		try.HasStatusCode(http.StatusOK),
 		// properties like id or petId are optional
-		try.HasBody(`{"id":93761,"petId":83318,"quantity":27,"shipDate":"2014-02-04T10:00:17Z","status":"placed","complete":true}`))
+		try.HasBody(`{"petId":59049,"quantity":73,"shipDate":"2039-02-18T21:13:08Z","status":"placed","complete":true}`))
 }
 
 func (suite *PetStoreSuite) TestTls() {
@@ -23,6 +23,7 @@ type Handler interface {
 	http.Handler
 	RegisterHealthHandler(path string, h http.Handler)
 	RegisterMcpHandler(path string, h http.Handler)
+	RegisterConfigProvider(p ConfigProvider)
 }
 
 type handler struct {
@@ -36,6 +37,7 @@ type handler struct {
 	healthHandler http.Handler
 	mcpPath       string
 	mcpHandler    http.Handler
+	configs       ConfigProvider
 	router        *mux.Router
 }
 
@@ -137,8 +139,14 @@ func (h *handler) RegisterMcpHandler(path string, handler http.Handler) {
 	h.mcpHandler = handler
 }
 
+func (h *handler) RegisterConfigProvider(p ConfigProvider) {
+	h.configs = p
+}
+
 func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" && r.Method != "POST" {
+	switch r.Method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete:
+	default:
 		http.Error(w, fmt.Sprintf("method %v is not allowed", r.Method), http.StatusMethodNotAllowed)
 		return
 	}
@@ -160,8 +168,12 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.getDashboard(w, r)
 	case strings.HasPrefix(p, "/api/metrics"):
 		h.getMetrics(w, r)
+	case p == "/metrics":
+		h.getPrometheusMetrics(w, r)
 	case strings.HasPrefix(p, "/api/events"):
 		h.getEvents(w, r)
+	case p == "/api/verify":
+		h.verify(w, r)
 	case p == "/api/schema/example":
 		h.getExampleData(w, r)
 	case p == "/api/schema/validate":
@@ -169,6 +181,10 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case strings.HasPrefix(p, "/api/system/"):
 		h.serveSystem(w, r)
 	case strings.HasPrefix(p, "/api/configs"):
+		if r.Method != http.MethodGet {
+			// config changes must not be allowed from other sites
+			w.Header().Del("Access-Control-Allow-Origin")
+		}
 		h.handleConfig(w, r)
 	case strings.HasPrefix(p, "/api/faker/tree"):
 		h.handleFakerTree(w, r)
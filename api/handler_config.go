@@ -1,10 +1,15 @@
 package api
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"mime"
 	"mokapi/config/dynamic"
+	"mokapi/config/dynamic/provider/memory"
+	"mokapi/media"
 	"net/http"
+	"net/url"
 	"path/filepath"
 	"strings"
 	"time"
@@ -28,8 +33,20 @@ type config struct {
 	Tags     []string     `json:"tags,omitempty"`
 }
 
+// ConfigProvider stores configs uploaded through the API
+type ConfigProvider interface {
+	Create(name string, raw []byte, contentType string) (*dynamic.Config, error)
+	Update(id string, raw []byte, contentType string) (*dynamic.Config, error)
+	Delete(id string) error
+}
+
 func (h *handler) handleConfig(w http.ResponseWriter, r *http.Request) {
 	segments := strings.Split(r.URL.Path, "/")
+	if r.Method != http.MethodGet {
+		h.writeConfig(w, r, segments)
+		return
+	}
+
 	if len(segments) == 3 {
 		h.getConfigs(w)
 	} else if len(segments) == 4 {
@@ -104,6 +121,104 @@ func (h *handler) getConfigData(w http.ResponseWriter, r *http.Request, key stri
 	}
 }
 
+func (h *handler) writeConfig(w http.ResponseWriter, r *http.Request, segments []string) {
+	if !h.config.ConfigWrite || h.configs == nil {
+		writeError(w, fmt.Errorf("changing configs is disabled, enable it with --api-config-write"), http.StatusForbidden)
+		return
+	}
+	if err := checkConfigWriteRequest(r); err != nil {
+		writeError(w, err, http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && len(segments) == 3:
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, err, http.StatusBadRequest)
+			return
+		}
+		c, err := h.configs.Create(r.URL.Query().Get("name"), raw, r.Header.Get("Content-Type"))
+		if err != nil {
+			writeError(w, err, configErrorStatus(err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		writeJsonBody(w, toConfig(c))
+	case r.Method == http.MethodPut && len(segments) == 4:
+		if !h.isConfigWritable(w, segments[3]) {
+			return
+		}
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, err, http.StatusBadRequest)
+			return
+		}
+		c, err := h.configs.Update(segments[3], raw, r.Header.Get("Content-Type"))
+		if err != nil {
+			writeError(w, err, configErrorStatus(err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		writeJsonBody(w, toConfig(c))
+	case r.Method == http.MethodDelete && len(segments) == 4:
+		if !h.isConfigWritable(w, segments[3]) {
+			return
+		}
+		if err := h.configs.Delete(segments[3]); err != nil {
+			writeError(w, err, configErrorStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, fmt.Sprintf("method %v is not allowed", r.Method), http.StatusMethodNotAllowed)
+	}
+}
+
+// checkConfigWriteRequest rejects requests that a browser may send from a
+// foreign website. Such requests carry a foreign Origin or, without a CORS
+// preflight, neither a JSON content type nor a custom header.
+func checkConfigWriteRequest(r *http.Request) error {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host != r.Host {
+			return fmt.Errorf("changing configs from origin %v is not allowed", origin)
+		}
+	}
+
+	ct := media.ParseContentType(r.Header.Get("Content-Type"))
+	isJson := ct.Subtype == "json" || strings.HasSuffix(ct.Subtype, "+json")
+	if !isJson && r.Header.Get("X-Requested-With") == "" {
+		return fmt.Errorf("changing configs requires header X-Requested-With or a JSON content type")
+	}
+	return nil
+}
+
+// isConfigWritable rejects changes to configs that are not uploaded
+// through the API, e.g. files of the file provider.
+func (h *handler) isConfigWritable(w http.ResponseWriter, key string) bool {
+	c := h.app.FindConfig(key)
+	if c != nil && c.Info.Provider != memory.Scheme {
+		writeError(w, fmt.Errorf("config %v is provided by %v and cannot be changed through the API", key, c.Info.Provider), http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func configErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, memory.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, memory.ErrExists):
+		return http.StatusConflict
+	case errors.Is(err, memory.ErrNotStarted):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadRequest
+	}
+}
+
 func getConfigInfos(list []*dynamic.Config) []configInfo {
 	var result []configInfo
 	for _, c := range list {
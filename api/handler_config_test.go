@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"mokapi/config/dynamic"
+	"mokapi/config/dynamic/dynamictest"
+	"mokapi/config/dynamic/provider/memory"
 	"mokapi/config/static"
 	"mokapi/runtime"
 	"mokapi/try"
@@ -13,6 +15,8 @@ import (
 	"net/url"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestHandler_Config(t *testing.T) {
@@ -358,3 +362,150 @@ func TestHandler_Config(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_ConfigWrite(t *testing.T) {
+	mustUrl := func(s string) *url.URL {
+		u, err := url.Parse(s)
+		if err != nil {
+			panic(err)
+		}
+		return u
+	}
+	fileConfig := &dynamic.Config{
+		Info: dynamic.ConfigInfo{Provider: "file", Url: mustUrl("file:/foo.yaml")},
+	}
+
+	header := map[string]string{"X-Requested-With": "test"}
+
+	testcases := []struct {
+		name string
+		cfg  static.Api
+		test func(t *testing.T, h http.Handler, p *memory.Provider)
+	}{
+		{
+			name: "disabled",
+			cfg:  static.Api{},
+			test: func(t *testing.T, h http.Handler, p *memory.Provider) {
+				try.Handler(t, http.MethodPost, "http://foo.api/api/configs?name=foo.js", nil, "", h,
+					try.HasStatusCode(http.StatusForbidden),
+					try.HasBody(`{"message":"changing configs is disabled, enable it with --api-config-write"}`),
+				)
+			},
+		},
+		{
+			name: "create",
+			cfg:  static.Api{ConfigWrite: true},
+			test: func(t *testing.T, h http.Handler, p *memory.Provider) {
+				try.Handler(t, http.MethodPost, "http://foo.api/api/configs?name=foo.js", header, "export default function() {}", h,
+					try.HasStatusCode(http.StatusCreated),
+					try.BodyContains(`"url":"memory://configs/foo.js","provider":"memory"`),
+				)
+				require.Len(t, p.List(), 1)
+
+				try.Handler(t, http.MethodPost, "http://foo.api/api/configs?name=foo.js", header, "", h,
+					try.HasStatusCode(http.StatusConflict),
+					try.HasBody(`{"message":"config already exists: foo.js"}`),
+				)
+			},
+		},
+		{
+			name: "create without custom header",
+			cfg:  static.Api{ConfigWrite: true},
+			test: func(t *testing.T, h http.Handler, p *memory.Provider) {
+				try.Handler(t, http.MethodPost, "http://foo.api/api/configs?name=foo.js", map[string]string{"Content-Type": "text/plain"}, "export default function() {}", h,
+					try.HasStatusCode(http.StatusForbidden),
+					try.HasBody(`{"message":"changing configs requires header X-Requested-With or a JSON content type"}`),
+					try.HasHeader("Access-Control-Allow-Origin", ""),
+				)
+				require.Len(t, p.List(), 0)
+			},
+		},
+		{
+			name: "create with JSON content type",
+			cfg:  static.Api{ConfigWrite: true},
+			test: func(t *testing.T, h http.Handler, p *memory.Provider) {
+				try.Handler(t, http.MethodPost, "http://foo.api/api/configs?name=foo.json", map[string]string{"Content-Type": "application/json"}, `{"foo":"bar"}`, h,
+					try.HasStatusCode(http.StatusCreated),
+				)
+				require.Len(t, p.List(), 1)
+			},
+		},
+		{
+			name: "create from foreign origin",
+			cfg:  static.Api{ConfigWrite: true},
+			test: func(t *testing.T, h http.Handler, p *memory.Provider) {
+				try.Handler(t, http.MethodPost, "http://foo.api/api/configs?name=foo.js", map[string]string{"X-Requested-With": "test", "Origin": "http://evil.example"}, "export default function() {}", h,
+					try.HasStatusCode(http.StatusForbidden),
+					try.HasBody(`{"message":"changing configs from origin http://evil.example is not allowed"}`),
+				)
+				require.Len(t, p.List(), 0)
+
+				try.Handler(t, http.MethodPost, "http://foo.api/api/configs?name=foo.js", map[string]string{"X-Requested-With": "test", "Origin": "http://foo.api"}, "export default function() {}", h,
+					try.HasStatusCode(http.StatusCreated),
+				)
+			},
+		},
+		{
+			name: "create with parse error",
+			cfg:  static.Api{ConfigWrite: true},
+			test: func(t *testing.T, h http.Handler, p *memory.Provider) {
+				try.Handler(t, http.MethodPost, "http://foo.api/api/configs?name=foo.json", header, `{"foo":`, h,
+					try.HasStatusCode(http.StatusBadRequest),
+					try.BodyContains(`"message":`),
+				)
+				require.Len(t, p.List(), 0)
+			},
+		},
+		{
+			name: "update and delete",
+			cfg:  static.Api{ConfigWrite: true},
+			test: func(t *testing.T, h http.Handler, p *memory.Provider) {
+				c, err := p.Create("foo.js", []byte("export default function() {}"), "")
+				require.NoError(t, err)
+				id := c.Info.Key()
+
+				try.Handler(t, http.MethodPut, "http://foo.api/api/configs/"+id, header, "export default function() { console.log('foo') }", h,
+					try.HasStatusCode(http.StatusOK),
+					try.BodyContains(`"id":"`+id+`"`),
+				)
+				u, _ := url.Parse("memory://configs/foo.js")
+				r, err := p.Read(u)
+				require.NoError(t, err)
+				require.Equal(t, "export default function() { console.log('foo') }", string(r.Raw))
+
+				try.Handler(t, http.MethodDelete, "http://foo.api/api/configs/"+id, header, "", h,
+					try.HasStatusCode(http.StatusNoContent),
+				)
+				require.Len(t, p.List(), 0)
+
+				try.Handler(t, http.MethodDelete, "http://foo.api/api/configs/"+id, header, "", h,
+					try.HasStatusCode(http.StatusNotFound),
+				)
+			},
+		},
+		{
+			name: "config of other provider",
+			cfg:  static.Api{ConfigWrite: true},
+			test: func(t *testing.T, h http.Handler, p *memory.Provider) {
+				try.Handler(t, http.MethodDelete, "http://foo.api/api/configs/"+fileConfig.Info.Key(), header, "", h,
+					try.HasStatusCode(http.StatusForbidden),
+					try.HasBody(`{"message":"config `+fileConfig.Info.Key()+` is provided by file and cannot be changed through the API"}`),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ch := make(chan dynamic.ConfigEvent, 10)
+			p := memory.New(&dynamictest.Reader{})
+			require.NoError(t, p.Start(ch, nil))
+
+			app := &runtime.App{Configs: map[string]*dynamic.Config{fileConfig.Info.Key(): fileConfig}}
+			h := New(app, tc.cfg)
+			h.RegisterConfigProvider(p)
+
+			tc.test(t, h, p)
+		})
+	}
+}
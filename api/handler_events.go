@@ -1,12 +1,23 @@
 package api
 
 import (
+	"encoding/json"
+	"fmt"
 	"mokapi/runtime/events"
 	"net/http"
 	"strings"
 )
 
+type resetResult struct {
+	Removed int `json:"removed"`
+}
+
 func (h *handler) getEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodDelete {
+		h.resetEvents(w, r)
+		return
+	}
+
 	var data interface{}
 
 	segments := strings.Split(r.URL.Path, "/")
@@ -30,3 +41,44 @@ func (h *handler) getEvents(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	writeJsonBody(w, data)
 }
+
+// resetEvents removes all events matching the traits given as query
+// parameters, e.g. DELETE /api/events?namespace=http&name=Petstore
+func (h *handler) resetEvents(w http.ResponseWriter, r *http.Request) {
+	if len(strings.Split(r.URL.Path, "/")) != 3 {
+		http.Error(w, fmt.Sprintf("method %v is not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	traits := events.NewTraits()
+	for k := range r.URL.Query() {
+		traits.With(k, r.URL.Query().Get(k))
+	}
+	n := h.app.Events.ClearEvents(traits)
+
+	w.Header().Set("Content-Type", "application/json")
+	writeJsonBody(w, resetResult{Removed: n})
+}
+
+// verify returns the number of events and the events matching the
+// matcher in the request body
+func (h *handler) verify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, fmt.Sprintf("method %v is not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var m events.Matcher
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		writeError(w, fmt.Errorf("invalid matcher: %w", err), http.StatusBadRequest)
+		return
+	}
+	result, err := h.app.Events.Verify(m)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	writeJsonBody(w, result)
+}
@@ -205,6 +205,63 @@ func TestHandler_Events(t *testing.T) {
 					try.HasStatusCode(404))
 			},
 		},
+		{
+			name: "verify http events",
+			fn: func(t *testing.T, h http.Handler, sm *events.StoreManager) {
+				err := sm.Push(&openapi.HttpLog{
+					Request:  &openapi.HttpRequestLog{Method: "POST", Url: "http://localhost/orders", Body: `{"id":1}`},
+					Response: &openapi.HttpResponseLog{StatusCode: 201},
+				}, events.NewTraits().WithNamespace("http").WithName("shop"))
+				require.NoError(t, err)
+
+				try.Handler(t,
+					http.MethodPost,
+					"http://foo.api/api/verify",
+					nil,
+					`{"http":{"method":"POST","path":"/orders","body":{"$.id":1}}}`,
+					h,
+					try.HasStatusCode(200),
+					try.BodyContains(`{"count":1,"events":[{"id":`))
+				try.Handler(t,
+					http.MethodPost,
+					"http://foo.api/api/verify",
+					nil,
+					`{"http":{"body":{"$.id":2}}}`,
+					h,
+					try.HasStatusCode(200),
+					try.HasBody(`{"count":0,"events":[]}`))
+			},
+		},
+		{
+			name: "verify with invalid matcher",
+			fn: func(t *testing.T, h http.Handler, sm *events.StoreManager) {
+				try.Handler(t,
+					http.MethodPost,
+					"http://foo.api/api/verify",
+					nil,
+					`{"http":{"body":{"id":1}}}`,
+					h,
+					try.HasStatusCode(400),
+					try.HasBody(`{"message":"invalid JSONPath 'id': must start with $"}`))
+			},
+		},
+		{
+			name: "reset events",
+			fn: func(t *testing.T, h http.Handler, sm *events.StoreManager) {
+				require.NoError(t, sm.Push(&eventstest.Event{Name: "foo"}, events.NewTraits().WithNamespace("http").WithName("foo")))
+				require.NoError(t, sm.Push(&eventstest.Event{Name: "bar"}, events.NewTraits().WithNamespace("http").WithName("bar")))
+
+				try.Handler(t,
+					http.MethodDelete,
+					"http://foo.api/api/events?namespace=http&name=foo",
+					nil,
+					"",
+					h,
+					try.HasStatusCode(200),
+					try.HasBody(`{"removed":1}`))
+				require.Len(t, sm.GetEvents(events.NewTraits().WithNamespace("http")), 1)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -134,13 +134,16 @@ type messageConfig struct {
 }
 
 type kafkaBindings struct {
-	Partitions            int   `json:"partitions,omitempty"`
-	RetentionBytes        int64 `json:"retentionBytes,omitempty"`
-	RetentionMs           int64 `json:"retentionMs,omitempty"`
-	SegmentBytes          int64 `json:"segmentBytes,omitempty"`
-	SegmentMs             int64 `json:"segmentMs,omitempty"`
-	ValueSchemaValidation bool  `json:"valueSchemaValidation,omitempty"`
-	KeySchemaValidation   bool  `json:"keySchemaValidation,omitempty"`
+	Partitions            int      `json:"partitions,omitempty"`
+	RetentionBytes        int64    `json:"retentionBytes,omitempty"`
+	RetentionMs           int64    `json:"retentionMs,omitempty"`
+	SegmentBytes          int64    `json:"segmentBytes,omitempty"`
+	SegmentMs             int64    `json:"segmentMs,omitempty"`
+	CompressionType       string   `json:"compressionType,omitempty"`
+	CleanupPolicy         []string `json:"cleanupPolicy,omitempty"`
+	DeleteRetentionMs     int64    `json:"deleteRetentionMs,omitempty"`
+	ValueSchemaValidation bool     `json:"valueSchemaValidation,omitempty"`
+	KeySchemaValidation   bool     `json:"keySchemaValidation,omitempty"`
 }
 
 type kafkaProduceRequest struct {
@@ -251,7 +254,7 @@ func (h *handler) getKafkaInfo(w http.ResponseWriter, r *http.Request) {
 	for it := ki.Servers.Iter(); it.Next(); {
 		name := it.Key()
 		s := it.Value()
-		if s == nil || s.Value == nil || strings.ToLower(s.Value.Protocol) != "kafka" {
+		if s == nil || s.Value == nil || !s.Value.IsKafka() {
 			continue
 		}
 
@@ -316,23 +319,12 @@ func (h *handler) getKafkaTopic(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	for n, ch := range ki.Channels {
-		if ch.Value == nil {
-			continue
-		}
-		addr := ch.Value.Address
-		if addr == "" {
-			addr = n
-		}
-		if addr == t.Name {
-			r := newTopic(t, ki, ch.Value, h.app.Monitor.Kafka)
-			write(w, r)
-			return
-		}
-
+	if t.Config == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("kafka topic not found"))
+		return
 	}
-	w.WriteHeader(http.StatusNotFound)
-	_, _ = w.Write([]byte("kafka topic not found"))
+	write(w, newTopic(t, ki, t.Config, h.app.Monitor.Kafka))
 }
 
 func (h *handler) getKafkaPartitions(w http.ResponseWriter, r *http.Request) {
@@ -465,7 +457,7 @@ func (h *handler) getKafkaGroup(w http.ResponseWriter, r *http.Request) {
 	} else {
 		grp.Generation = -1
 	}
-	for topicName := range g.Commits {
+	for topicName := range g.CommittedOffsets() {
 		grp.Topics = append(grp.Topics, topicName)
 	}
 	sort.Slice(grp.Topics, func(i, j int) bool {
@@ -567,23 +559,18 @@ func (h *handler) produceKafkaMessage(w http.ResponseWriter, r *http.Request) {
 }
 
 func getTopicInfos(ki *runtime.KafkaInfo, m *monitor.Kafka) []kafkaTopicInfo {
-	topics := make([]kafkaTopicInfo, 0, len(ki.Config.Channels))
-	for name, ch := range ki.Config.Channels {
-		if ch.Value == nil {
+	// topics of the broker include topics created or deleted by Kafka clients
+	storeTopics := ki.Store.Topics()
+	topics := make([]kafkaTopicInfo, 0, len(storeTopics))
+	for _, t := range storeTopics {
+		if t.Config == nil {
 			continue
 		}
-		if !ch.Value.IsChannelAvailable("kafka") {
-			continue
-		}
-		addr := ch.Value.Address
-		if addr == "" {
-			addr = name
-		}
 
 		ti := kafkaTopicInfo{
-			Name:    addr,
-			Summary: ch.Value.Summary,
-			Tags:    getKafkaTags(ch.Value),
+			Name:    t.Name,
+			Summary: t.Config.Summary,
+			Tags:    getKafkaTags(t.Config),
 		}
 
 		ti.Metrics = kafkaTopicMetric{
@@ -608,6 +595,7 @@ func newTopic(t *store.Topic, ki *runtime.KafkaInfo, ch *asyncapi3.Channel, m *m
 		return partitions[i].Id < partitions[j].Id
 	})
 
+	bindings := t.Bindings()
 	result := kafkaTopic{
 		Name:        t.Name,
 		Title:       ch.Title,
@@ -615,13 +603,16 @@ func newTopic(t *store.Topic, ki *runtime.KafkaInfo, ch *asyncapi3.Channel, m *m
 		Description: ch.Description,
 		Partitions:  partitions,
 		Bindings: kafkaBindings{
-			Partitions:            t.Config.Bindings.Kafka.Partitions,
-			RetentionBytes:        t.Config.Bindings.Kafka.RetentionBytes,
-			RetentionMs:           t.Config.Bindings.Kafka.RetentionMs,
-			SegmentBytes:          t.Config.Bindings.Kafka.SegmentBytes,
-			SegmentMs:             t.Config.Bindings.Kafka.SegmentMs,
-			ValueSchemaValidation: t.Config.Bindings.Kafka.ValueSchemaValidation,
-			KeySchemaValidation:   t.Config.Bindings.Kafka.KeySchemaValidation,
+			Partitions:            bindings.Partitions,
+			RetentionBytes:        bindings.RetentionBytes,
+			RetentionMs:           bindings.RetentionMs,
+			SegmentBytes:          bindings.SegmentBytes,
+			SegmentMs:             bindings.SegmentMs,
+			CompressionType:       bindings.CompressionType,
+			CleanupPolicy:         bindings.CleanupPolicy,
+			DeleteRetentionMs:     bindings.DeleteRetentionMs,
+			ValueSchemaValidation: bindings.ValueSchemaValidation,
+			KeySchemaValidation:   bindings.KeySchemaValidation,
 		},
 		Tags:   getKafkaTags(ch),
 		Groups: getGroupInfos(ki, t.Name, m),
@@ -638,7 +629,7 @@ func getGroupInfos(ki *runtime.KafkaInfo, topic string, m *monitor.Kafka) []kafk
 Groups:
 	for _, g := range groups {
 		if topic != "" {
-			for topicName := range g.Commits {
+			for topicName := range g.CommittedOffsets() {
 				if topicName != topic {
 					continue Groups
 				}
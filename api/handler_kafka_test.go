@@ -1180,7 +1180,9 @@ func getKafkaInfo(config *asyncapi3.Config) *runtime.KafkaInfo {
 func getKafkaInfoWithGroup(config *asyncapi3.Config, group *store.Group) *runtime.KafkaInfo {
 	s := store.New(config, enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
 	g := s.GetOrCreateGroup(group.Name, &store.Broker{})
-	*g = *group
+	g.State = group.State
+	g.Generation = group.Generation
+	g.Commits = group.Commits
 	return &runtime.KafkaInfo{
 		Config: config,
 		Store:  s,
@@ -4,6 +4,8 @@ import (
 	"mokapi/runtime/metrics"
 	"net/http"
 	"strings"
+
+	log "github.com/sirupsen/logrus"
 )
 
 func (h *handler) getMetrics(w http.ResponseWriter, r *http.Request) {
@@ -31,3 +33,12 @@ func (h *handler) getMetrics(w http.ResponseWriter, r *http.Request) {
 
 	writeJsonBody(w, result)
 }
+
+// getPrometheusMetrics serves all metrics in the Prometheus text exposition format
+func (h *handler) getPrometheusMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", metrics.TextContentType)
+	err := metrics.WriteText(w, h.app.Monitor.FindAll())
+	if err != nil {
+		log.Errorf("write metrics failed: %v", err)
+	}
+}
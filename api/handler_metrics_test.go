@@ -85,6 +85,31 @@ func TestHandler_Metrics(t *testing.T) {
 					try.HasBody(`[{"name":"kafka_messages_total{service=\"foo\",topic=\"bar\"}","value":1}]`))
 			},
 		},
+		{
+			name: "/metrics in prometheus format",
+			app: &runtime.App{
+				Monitor: monitor.New(),
+			},
+			fn: func(t *testing.T, h http.Handler, app *runtime.App) {
+				app.Monitor.StartTime.Set(1700000000)
+				app.Monitor.Kafka.Messages.WithLabel("foo", "bar").Add(1)
+				app.Monitor.Http.RequestDuration.WithLabel("foo", "/pets", "GET").Observe(0.02)
+				try.Handler(t,
+					http.MethodGet,
+					"http://foo.api/metrics",
+					nil,
+					"",
+					h,
+					try.HasStatusCode(200),
+					try.HasHeader("Content-Type", "text/plain; version=0.0.4; charset=utf-8"),
+					try.BodyContains("# TYPE app_start_timestamp gauge\napp_start_timestamp 1.7e+09\n"),
+					try.BodyContains("kafka_messages_total{service=\"foo\",topic=\"bar\"} 1\n"),
+					try.BodyContains("# TYPE http_request_duration_seconds histogram\n"),
+					try.BodyContains("http_request_duration_seconds_bucket{service=\"foo\",endpoint=\"/pets\",method=\"GET\",le=\"0.025\"} 1\n"),
+					try.BodyContains("http_request_duration_seconds_count{service=\"foo\",endpoint=\"/pets\",method=\"GET\"} 1\n"),
+				)
+			},
+		},
 	}
 
 	t.Parallel()
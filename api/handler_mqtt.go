@@ -132,7 +132,7 @@ func (h *handler) getMqttInfo(w http.ResponseWriter, r *http.Request) {
 	for it := mi.Servers.Iter(); it.Next(); {
 		name := it.Key()
 		s := it.Value()
-		if s == nil || s.Value == nil || strings.ToLower(s.Value.Protocol) != "mqtt" {
+		if s == nil || s.Value == nil || !s.Value.IsMqtt() {
 			continue
 		}
 
@@ -9,6 +9,7 @@ import (
 	"mokapi/schema/encoding"
 	"mokapi/schema/json/generator"
 	jsonSchema "mokapi/schema/json/schema"
+	protobuf "mokapi/schema/protobuf/schema"
 	"net/http"
 )
 
@@ -60,6 +61,8 @@ func getAsyncApiExample(w http.ResponseWriter, r *http.Request, cfg *asyncapi3.C
 		s = openApiSchema.ConvertToJsonSchema(t)
 	case *avro.Schema:
 		s = avro.ConvertToJsonSchema(t)
+	case *protobuf.Message:
+		s = protobuf.ConvertToJsonSchema(t)
 	default:
 		var ok bool
 		s, ok = t.(*jsonSchema.Schema)
@@ -92,6 +95,8 @@ func getAsyncApiExample(w http.ResponseWriter, r *http.Request, cfg *asyncapi3.C
 			http.Error(w, fmt.Sprintf("unsupported schema type: %T", t), http.StatusBadRequest)
 			return
 		}
+	case *protobuf.Message:
+		data, err = msg.Payload.Marshal(rnd, ct)
 	default:
 		data, err = encoding.NewEncoder(s).Write(rnd, ct)
 
@@ -75,7 +75,7 @@ func TestHandler_Schema_Example_Query(t *testing.T) {
 					h,
 					try.HasStatusCode(200),
 					try.HasHeader("Content-Type", "application/json"),
-					try.HasBody(`"Y5elX"`))
+					try.HasBody(`"wcwIugjqssInJi"`))
 
 				try.Handler(t,
 					http.MethodGet,
@@ -164,6 +164,7 @@ func TestHandler_Schema_Example_Query(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			gofakeit.Seed(11)
+			generator.Seed(11)
 
 			log.Info(tc.app)
 			h := New(tc.app, static.Api{})
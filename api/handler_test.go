@@ -49,6 +49,15 @@ func TestHandler_ServeHTTP(t *testing.T) {
 				require.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
 			},
 		},
+		{
+			name: "cors is set on POST",
+			test: func(t *testing.T, h http.Handler) {
+				r := httptest.NewRequest(http.MethodPost, "http://foo.api/api/schema/validate", nil)
+				rr := httptest.NewRecorder()
+				h.ServeHTTP(rr, r)
+				require.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+			},
+		},
 		{
 			name: "info",
 			test: func(t *testing.T, h http.Handler) {
@@ -84,6 +84,7 @@ api:
         enabled: true
         indexPath: ""
         inMemory: false
+    configWrite: false
 health:
     enabled: true
     path: /health
@@ -101,10 +102,21 @@ event:
     store:
         default:
             size: 100
+    storage:
+        type: ""
+        path: ""
+        maxAge: ""
+        maxSize: 0
 certificates:
     static: []
 data-gen:
     optionalProperties: "0.85"
+    deterministic: false
+    locale: en
+http:
+    strictSecurity: false
+kafka:
+    dataDir: ""
 `, out)
 			},
 		},
@@ -377,6 +377,9 @@ func convertChannelBinding(b ChannelBindings) asyncapi3.ChannelBindings {
 		RetentionMs:           b.Kafka.RetentionMs,
 		SegmentBytes:          b.Kafka.SegmentBytes,
 		SegmentMs:             b.Kafka.SegmentMs,
+		CompressionType:       b.Kafka.CompressionType,
+		CleanupPolicy:         b.Kafka.CleanupPolicy,
+		DeleteRetentionMs:     b.Kafka.DeleteRetentionMs,
 		ValueSchemaValidation: b.Kafka.ValueSchemaValidation,
 	}}
 }
@@ -283,6 +283,15 @@ func (t *TopicBindings) Patch(patch TopicBindings) {
 	if t.SegmentMs == 0 {
 		t.SegmentMs = patch.SegmentMs
 	}
+	if t.CompressionType == "" {
+		t.CompressionType = patch.CompressionType
+	}
+	if len(t.CleanupPolicy) == 0 {
+		t.CleanupPolicy = patch.CleanupPolicy
+	}
+	if t.DeleteRetentionMs == 0 {
+		t.DeleteRetentionMs = patch.DeleteRetentionMs
+	}
 }
 
 func (m *KafkaMessageBinding) Patch(patch KafkaMessageBinding) {
@@ -30,6 +30,16 @@ type Parser interface {
 	Parse(config *Config, reader Reader) error
 }
 
+// fileParsers contains parsers of file formats other than JSON and YAML
+// by file extension, e.g. .proto
+var fileParsers = map[string]func(b []byte) (any, error){}
+
+// RegisterFileParser registers a parser for files with the given
+// extension. The parsed data is used as config data.
+func RegisterFileParser(ext string, parse func(b []byte) (any, error)) {
+	fileParsers[ext] = parse
+}
+
 func Parse(c *Config, r Reader) error {
 	var err error
 	c.Data, err = parse(c)
@@ -64,6 +74,10 @@ func parse(c *Config) (interface{}, error) {
 	}
 
 	result := c.Data
+	if parse, ok := fileParsers[filepath.Ext(name)]; ok {
+		return parse(b)
+	}
+
 	switch filepath.Ext(name) {
 	case ".yml", ".yaml":
 		result, err = parseYaml(b, result)
@@ -0,0 +1,201 @@
+package memory
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"mokapi/config/dynamic"
+	"mokapi/safe"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const Scheme = "memory"
+
+var (
+	ErrNotFound   = errors.New("config not found")
+	ErrExists     = errors.New("config already exists")
+	ErrNotStarted = errors.New("memory provider not started")
+)
+
+// Provider holds configs that are uploaded at runtime, for example
+// through the REST API. Configs are validated before they are passed
+// to the config pipeline, so errors can be reported to the caller.
+type Provider struct {
+	reader dynamic.Reader
+	files  map[string]*dynamic.Config
+	ch     chan dynamic.ConfigEvent
+	m      sync.Mutex
+	// serializes changes so events are sent in order; it is not
+	// held by Read which is called while a config is parsed
+	write sync.Mutex
+}
+
+// New creates a memory provider. The reader is used to resolve
+// references while validating a config.
+func New(reader dynamic.Reader) *Provider {
+	return &Provider{
+		reader: reader,
+		files:  map[string]*dynamic.Config{},
+	}
+}
+
+func (p *Provider) Read(u *url.URL) (*dynamic.Config, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	c, ok := p.files[u.String()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, u.String())
+	}
+	return &dynamic.Config{
+		Info:       c.Info,
+		Raw:        c.Raw,
+		SourceType: dynamic.SourceReference,
+	}, nil
+}
+
+func (p *Provider) Start(ch chan dynamic.ConfigEvent, _ *safe.Pool) error {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.ch = ch
+	return nil
+}
+
+// Create adds a new config with the given file name. The file
+// extension determines how the content is parsed.
+func (p *Provider) Create(name string, raw []byte, contentType string) (*dynamic.Config, error) {
+	u, err := getUrl(name)
+	if err != nil {
+		return nil, err
+	}
+
+	p.write.Lock()
+	defer p.write.Unlock()
+
+	p.m.Lock()
+	_, exists := p.files[u.String()]
+	p.m.Unlock()
+	if exists {
+		return nil, fmt.Errorf("%w: %v", ErrExists, name)
+	}
+	return p.apply(u, raw, contentType, dynamic.Create)
+}
+
+// Update replaces the content of the config with the given id
+func (p *Provider) Update(id string, raw []byte, contentType string) (*dynamic.Config, error) {
+	p.write.Lock()
+	defer p.write.Unlock()
+
+	c := p.find(id)
+	if c == nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, id)
+	}
+	return p.apply(c.Info.Url, raw, contentType, dynamic.Update)
+}
+
+// Delete removes the config with the given id
+func (p *Provider) Delete(id string) error {
+	p.write.Lock()
+	defer p.write.Unlock()
+
+	ch := p.channel()
+	if ch == nil {
+		return ErrNotStarted
+	}
+	c := p.find(id)
+	if c == nil {
+		return fmt.Errorf("%w: %v", ErrNotFound, id)
+	}
+	p.m.Lock()
+	delete(p.files, c.Info.Url.String())
+	p.m.Unlock()
+	ch <- dynamic.ConfigEvent{Name: c.Info.Url.String(), Config: c, Event: dynamic.Delete}
+	return nil
+}
+
+// List returns all configs of this provider ordered by name
+func (p *Provider) List() []*dynamic.Config {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	var result []*dynamic.Config
+	for _, c := range p.files {
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Info.Url.String() < result[j].Info.Url.String()
+	})
+	return result
+}
+
+func (p *Provider) apply(u *url.URL, raw []byte, contentType string, event dynamic.Event) (*dynamic.Config, error) {
+	ch := p.channel()
+	if ch == nil {
+		return nil, ErrNotStarted
+	}
+
+	checksum := sha256.Sum256(raw)
+	c := &dynamic.Config{
+		Info: dynamic.ConfigInfo{
+			Provider:    Scheme,
+			Url:         u,
+			Checksum:    checksum[:],
+			Time:        time.Now(),
+			ContentType: contentType,
+		},
+		Raw: raw,
+	}
+
+	// parse a copy, the config pipeline parses the config itself
+	v := &dynamic.Config{Info: c.Info, Raw: raw}
+	if err := dynamic.Parse(v, p.reader); err != nil {
+		return nil, err
+	}
+	if err := dynamic.Validate(v); err != nil {
+		return nil, fmt.Errorf("validation of %v failed: %w", u.String(), err)
+	}
+
+	p.m.Lock()
+	p.files[u.String()] = c
+	p.m.Unlock()
+	ch <- dynamic.ConfigEvent{Name: u.String(), Config: c, Event: event}
+
+	// Data is only used to report the parsed type to the caller
+	return &dynamic.Config{Info: c.Info, Raw: raw, Data: v.Data}, nil
+}
+
+func (p *Provider) channel() chan dynamic.ConfigEvent {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return p.ch
+}
+
+func (p *Provider) find(id string) *dynamic.Config {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for _, c := range p.files {
+		if c.Info.Key() == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func getUrl(name string) (*url.URL, error) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" || name == "." {
+		return nil, fmt.Errorf("config name is required")
+	}
+	if path.Ext(name) == "" {
+		return nil, fmt.Errorf("config name '%v' requires a file extension, e.g. .yaml, .json, .js or .ldif", name)
+	}
+	return url.Parse(fmt.Sprintf("%v://configs/%v", Scheme, name))
+}
@@ -0,0 +1,138 @@
+package memory_test
+
+import (
+	"mokapi/config/dynamic"
+	"mokapi/config/dynamic/dynamictest"
+	"mokapi/config/dynamic/provider/memory"
+	"mokapi/providers/openapi"
+	"mokapi/safe"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider(t *testing.T) {
+	dynamic.Register("openapi", dynamic.AnyVersion, &openapi.Config{})
+
+	testcases := []struct {
+		name string
+		test func(t *testing.T, p *memory.Provider, ch chan dynamic.ConfigEvent)
+	}{
+		{
+			name: "create",
+			test: func(t *testing.T, p *memory.Provider, ch chan dynamic.ConfigEvent) {
+				c, err := p.Create("foo.yaml", []byte("openapi: 3.1.0\ninfo:\n  title: foo"), "")
+				require.NoError(t, err)
+				require.Equal(t, "memory://configs/foo.yaml", c.Info.Url.String())
+				require.Equal(t, "memory", c.Info.Provider)
+				require.IsType(t, &openapi.Config{}, c.Data)
+
+				e := receive(t, ch)
+				require.Equal(t, dynamic.Create, e.Event)
+				require.Equal(t, "memory://configs/foo.yaml", e.Name)
+				require.Nil(t, e.Config.Data)
+				require.Len(t, p.List(), 1)
+			},
+		},
+		{
+			name: "create twice",
+			test: func(t *testing.T, p *memory.Provider, ch chan dynamic.ConfigEvent) {
+				_, err := p.Create("foo.js", []byte("export default function() {}"), "")
+				require.NoError(t, err)
+				receive(t, ch)
+
+				_, err = p.Create("/foo.js", []byte(""), "")
+				require.ErrorIs(t, err, memory.ErrExists)
+			},
+		},
+		{
+			name: "name without extension",
+			test: func(t *testing.T, p *memory.Provider, ch chan dynamic.ConfigEvent) {
+				_, err := p.Create("foo", []byte("{}"), "")
+				require.EqualError(t, err, "config name 'foo' requires a file extension, e.g. .yaml, .json, .js or .ldif")
+			},
+		},
+		{
+			name: "parse error",
+			test: func(t *testing.T, p *memory.Provider, ch chan dynamic.ConfigEvent) {
+				_, err := p.Create("foo.json", []byte(`{"openapi": "3.1.0",`), "")
+				require.Error(t, err)
+				require.Len(t, p.List(), 0)
+			},
+		},
+		{
+			name: "validation error",
+			test: func(t *testing.T, p *memory.Provider, ch chan dynamic.ConfigEvent) {
+				_, err := p.Create("foo.yaml", []byte("openapi: 3.1.0\ninfo:\n  title: foo\nx-proxy:\n  mode: foo\n"), "")
+				require.EqualError(t, err, "validation of memory://configs/foo.yaml failed: x-proxy: unsupported mode 'foo'")
+				require.Len(t, p.List(), 0)
+			},
+		},
+		{
+			name: "update and delete",
+			test: func(t *testing.T, p *memory.Provider, ch chan dynamic.ConfigEvent) {
+				c, err := p.Create("foo.js", []byte("export default function() {}"), "")
+				require.NoError(t, err)
+				receive(t, ch)
+
+				updated, err := p.Update(c.Info.Key(), []byte("export default function() { console.log('foo') }"), "")
+				require.NoError(t, err)
+				require.NotEqual(t, c.Info.Checksum, updated.Info.Checksum)
+				e := receive(t, ch)
+				require.Equal(t, dynamic.Update, e.Event)
+
+				r, err := p.Read(c.Info.Url)
+				require.NoError(t, err)
+				require.Equal(t, "export default function() { console.log('foo') }", string(r.Raw))
+
+				err = p.Delete(c.Info.Key())
+				require.NoError(t, err)
+				e = receive(t, ch)
+				require.Equal(t, dynamic.Delete, e.Event)
+				require.Len(t, p.List(), 0)
+
+				_, err = p.Update(c.Info.Key(), []byte(""), "")
+				require.ErrorIs(t, err, memory.ErrNotFound)
+				require.ErrorIs(t, p.Delete(c.Info.Key()), memory.ErrNotFound)
+			},
+		},
+		{
+			name: "read unknown config",
+			test: func(t *testing.T, p *memory.Provider, ch chan dynamic.ConfigEvent) {
+				u, _ := url.Parse("memory://configs/foo.yaml")
+				_, err := p.Read(u)
+				require.ErrorIs(t, err, memory.ErrNotFound)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := memory.New(&dynamictest.Reader{})
+			ch := make(chan dynamic.ConfigEvent, 1)
+			pool := safe.NewPool(t.Context())
+			defer pool.Stop()
+			require.NoError(t, p.Start(ch, pool))
+
+			tc.test(t, p, ch)
+		})
+	}
+}
+
+func TestProvider_NotStarted(t *testing.T) {
+	p := memory.New(&dynamictest.Reader{})
+	_, err := p.Create("foo.js", []byte(""), "")
+	require.ErrorIs(t, err, memory.ErrNotStarted)
+}
+
+func receive(t *testing.T, ch chan dynamic.ConfigEvent) dynamic.ConfigEvent {
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for config event")
+	}
+	return dynamic.ConfigEvent{}
+}
@@ -30,6 +30,8 @@ type Config struct {
 	Event            Event             `json:"event" yaml:"event"`
 	Certificates     CertificateStore  `json:"certificates" yaml:"certificates"`
 	DataGen          DataGen           `json:"data-gen" yaml:"data-gen" name:"data-gen"`
+	Http             Http              `json:"http" yaml:"http"`
+	Kafka            Kafka             `json:"kafka" yaml:"kafka"`
 	Args             []string          `json:"args" yaml:"-" aliases:"args"` // positional arguments
 }
 
@@ -57,6 +59,7 @@ func NewConfig() *Config {
 	cfg.Providers.File.SkipPrefix = []string{"_"}
 	cfg.Event.Store = map[string]Store{"default": {Size: 100}}
 	cfg.DataGen.OptionalProperties = "0.85"
+	cfg.DataGen.Locale = "en"
 	return cfg
 }
 
@@ -78,6 +81,9 @@ type Api struct {
 	Base      string
 	Dashboard bool
 	Search    Search
+	// ConfigWrite allows uploading, replacing and deleting
+	// configs through the API
+	ConfigWrite bool `yaml:"configWrite" json:"configWrite" flag:"config-write"`
 }
 
 type Search struct {
@@ -152,7 +158,19 @@ type NpmPackage struct {
 }
 
 type Event struct {
-	Store map[string]Store
+	Store   map[string]Store
+	Storage EventStorage
+}
+
+type EventStorage struct {
+	// Specifies where events are kept: memory (default) or file
+	Type string
+	// Directory of the event log when type is file
+	Path string
+	// Events older than this duration are discarded, e.g. 72h
+	MaxAge string `yaml:"maxAge" json:"maxAge" flag:"max-age"`
+	// Maximum size of the event log in bytes
+	MaxSize int64 `yaml:"maxSize" json:"maxSize" flag:"max-size"`
 }
 
 type Store struct {
@@ -170,8 +188,23 @@ type Certificate struct {
 	Key  tls.FileOrContent `yaml:"key" json:"key"`
 }
 
+type Http struct {
+	StrictSecurity bool `yaml:"strictSecurity" json:"strictSecurity" flag:"strict-security"`
+}
+
+type Kafka struct {
+	// Directory where partitions, consumer group offsets and producer
+	// states are stored. Kafka data is kept in memory only if empty.
+	DataDir string `yaml:"dataDir" json:"dataDir" flag:"data-dir"`
+}
+
 type DataGen struct {
 	OptionalProperties string `yaml:"optionalProperties" json:"optionalProperties" name:"optional-properties"`
+	// Deterministic seeds the generator per request from API, path
+	// and path parameters, so the same request returns the same data.
+	Deterministic bool `yaml:"deterministic" json:"deterministic" name:"deterministic"`
+	// Locale of generated names, addresses, phone numbers etc., e.g. de-DE
+	Locale string `yaml:"locale" json:"locale" name:"locale"`
 }
 
 func (c *Configs) UnmarshalJSON(b []byte) error {
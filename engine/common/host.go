@@ -3,6 +3,7 @@ package common
 import (
 	"fmt"
 	"mokapi/config/dynamic"
+	"mokapi/runtime/events"
 	"mokapi/schema/json/generator"
 	"net/http"
 	"strings"
@@ -43,6 +44,8 @@ type Host interface {
 	On(event string, do EventHandler, args EventArgs)
 
 	KafkaClient() KafkaClient
+	WebSocketClient() WebSocketClient
+	EventVerifier() EventVerifier
 	HttpClient(HttpClientOptions) HttpClient
 
 	Name() string
@@ -109,6 +112,24 @@ type KafkaMessageResult struct {
 	Partition int
 }
 
+type WebSocketClient interface {
+	Send(args *WebSocketSendArgs) (int, error)
+}
+
+type WebSocketSendArgs struct {
+	Api       string
+	Channel   string
+	SessionId string
+	Data      []byte
+	Binary    bool
+}
+
+// EventVerifier verifies the traffic recorded as events
+type EventVerifier interface {
+	Verify(m events.Matcher) (events.VerifyResult, error)
+	ClearEvents(traits events.Traits) int
+}
+
 type HttpClient interface {
 	Do(r *http.Request) (*http.Response, error)
 }
@@ -11,10 +11,19 @@ type HttpEventResponse struct {
 	StatusCode int            `json:"statusCode"`
 	Body       string         `json:"body"`
 	Data       any            `json:"data"`
+	// Stream contains the events of a streaming response like
+	// text/event-stream which are written one after another
+	Stream *HttpStream `json:"stream,omitempty"`
 
 	Rebuild func(statusCode int, contentType string) `json:"-"`
 }
 
+type HttpStream struct {
+	Events []any `json:"events"`
+	// Interval between two events in milliseconds
+	Interval int64 `json:"interval"`
+}
+
 type HttpEventRequest struct {
 	Method      string         `json:"method"`
 	Url         Url            `json:"url"`
@@ -28,6 +37,12 @@ type HttpEventRequest struct {
 	Api         string `json:"api"`
 	Key         string `json:"key"`
 	OperationId string `json:"operationId"`
+
+	// Security contains the names of the security schemes of the
+	// satisfied security requirement
+	Security []string `json:"security"`
+	// Principal is the authenticated user, if known
+	Principal string `json:"principal"`
 }
 
 type Url struct {
@@ -18,18 +18,20 @@ import (
 type Options func(e *Engine)
 
 type Engine struct {
-	scripts     map[string]*scriptHost
-	scheduler   Scheduler
-	logger      common.Logger
-	reader      dynamic.Reader
-	kafkaClient common.KafkaClient
-	m           sync.Mutex
-	loader      ScriptLoader
-	parallel    bool
-	cfgEvent    static.Event
-	jobCounter  *metrics.Counter
-	sm          *events.StoreManager
-	store       *Store
+	scripts       map[string]*scriptHost
+	scheduler     Scheduler
+	logger        common.Logger
+	reader        dynamic.Reader
+	kafkaClient   common.KafkaClient
+	wsClient      common.WebSocketClient
+	m             sync.Mutex
+	loader        ScriptLoader
+	parallel      bool
+	cfgEvent      static.Event
+	jobCounter    *metrics.Counter
+	eventDuration *metrics.HistogramMap
+	sm            *events.StoreManager
+	store         *Store
 }
 
 type Store struct {
@@ -39,17 +41,19 @@ type Store struct {
 
 func New(reader dynamic.Reader, app *runtime.App, config *static.Config, parallel bool) *Engine {
 	return &Engine{
-		scripts:     make(map[string]*scriptHost),
-		scheduler:   NewDefaultScheduler(),
-		logger:      newLogger(log.StandardLogger()),
-		reader:      reader,
-		kafkaClient: NewKafkaClient(app),
-		parallel:    parallel,
-		loader:      NewDefaultScriptLoader(config),
-		cfgEvent:    config.Event,
-		jobCounter:  app.Monitor.JobCounter,
-		sm:          app.Events,
-		store:       &Store{data: make(map[string]any)},
+		scripts:       make(map[string]*scriptHost),
+		scheduler:     NewDefaultScheduler(),
+		logger:        newLogger(log.StandardLogger()),
+		reader:        reader,
+		kafkaClient:   NewKafkaClient(app),
+		wsClient:      app.WebSocket,
+		parallel:      parallel,
+		loader:        NewDefaultScriptLoader(config),
+		cfgEvent:      config.Event,
+		jobCounter:    app.Monitor.JobCounter,
+		eventDuration: app.Monitor.EventHandlerDuration,
+		sm:            app.Events,
+		store:         &Store{data: make(map[string]any)},
 	}
 }
 
@@ -27,6 +27,8 @@ type Host struct {
 	HttpClientTest     *HttpClient
 	HttpClientFunc     func(opts common.HttpClientOptions) common.HttpClient
 	KafkaClientTest    *KafkaClient
+	WebSocketTest      *WebSocketClient
+	EventVerifierTest  common.EventVerifier
 	EveryFunc          func(every string, do func(), opt common.JobOptions)
 	CronFunc           func(every string, do func(), opt common.JobOptions)
 	OnFunc             func(event string, do common.EventHandler, args common.EventArgs)
@@ -45,6 +47,10 @@ type KafkaClient struct {
 	ProduceFunc func(args *common.KafkaProduceArgs) (*common.KafkaProduceResult, error)
 }
 
+type WebSocketClient struct {
+	SendFunc func(args *common.WebSocketSendArgs) (int, error)
+}
+
 func (h *Host) Info(args ...interface{}) {
 	if h.InfoFunc != nil {
 		h.InfoFunc(args...)
@@ -160,6 +166,14 @@ func (h *Host) KafkaClient() common.KafkaClient {
 	return h.KafkaClientTest
 }
 
+func (h *Host) WebSocketClient() common.WebSocketClient {
+	return h.WebSocketTest
+}
+
+func (h *Host) EventVerifier() common.EventVerifier {
+	return h.EventVerifierTest
+}
+
 func (h *Host) Store() common.Store {
 	if h.StoreTest == nil {
 		h.StoreTest = engine.NewStore()
@@ -182,6 +196,13 @@ func (c *KafkaClient) Produce(args *common.KafkaProduceArgs) (*common.KafkaProdu
 	return nil, nil
 }
 
+func (c *WebSocketClient) Send(args *common.WebSocketSendArgs) (int, error) {
+	if c.SendFunc != nil {
+		return c.SendFunc(args)
+	}
+	return 0, nil
+}
+
 func (h *Host) AddCleanupFunc(f func()) {
 	h.CleanupFuncs = append(h.CleanupFuncs, f)
 }
@@ -19,7 +19,11 @@ func (e *Engine) Run(event string, args ...interface{}) []*common.Action {
 	var result []*common.Action
 
 	for _, eh := range ehs {
+		start := time.Now()
 		a := runEventHandler(eh, args...)
+		if e.eventDuration != nil {
+			e.eventDuration.WithLabel(event).Observe(time.Since(start).Seconds())
+		}
 		if a != nil {
 			result = append(result, a)
 		}
@@ -284,6 +284,17 @@ func (sh *scriptHost) KafkaClient() common.KafkaClient {
 	return sh.engine.kafkaClient
 }
 
+func (sh *scriptHost) WebSocketClient() common.WebSocketClient {
+	return sh.engine.wsClient
+}
+
+func (sh *scriptHost) EventVerifier() common.EventVerifier {
+	if sh.engine.sm == nil {
+		return nil
+	}
+	return sh.engine.sm
+}
+
 func (sh *scriptHost) HttpClient(opts common.HttpClientOptions) common.HttpClient {
 	transport := http.DefaultTransport.(*http.Transport).Clone()
 
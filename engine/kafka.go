@@ -16,6 +16,7 @@ import (
 	"mokapi/schema/encoding"
 	"mokapi/schema/json/generator"
 	"mokapi/schema/json/schema"
+	protobuf "mokapi/schema/protobuf/schema"
 	"slices"
 	"time"
 
@@ -218,6 +219,8 @@ func createValue(r *asyncapi3.SchemaRef) (value interface{}, err error) {
 	case *avro.Schema:
 		jsSchema := avro.ConvertToJsonSchema(v)
 		value, err = generator.New(&generator.Request{Schema: jsSchema})
+	case *protobuf.Message:
+		value, err = generator.New(&generator.Request{Schema: protobuf.ConvertToJsonSchema(v)})
 	default:
 		err = fmt.Errorf("schema format not supported: %T", r.Value)
 	}
@@ -320,6 +323,9 @@ func valueMatchMessagePayload(value any, msg *asyncapi3.Message) error {
 		jsSchema := avro.ConvertToJsonSchema(v)
 		_, err := encoding.NewEncoder(jsSchema).Write(value, ct)
 		return err
+	case *protobuf.Message:
+		_, err := v.Marshal(value)
+		return err
 	default:
 		return nil
 	}
@@ -74,7 +74,7 @@ func TestKafkaClient(t *testing.T) {
 				b, errCode := app.Kafka.Get("foo").Store.Topic("foo").Partition(0).Read(0, 1000)
 				require.Equal(t, kafka.None, errCode)
 				require.NotNil(t, b)
-				require.Equal(t, "EZyvmtlRf", kafka.BytesToString(b.Records[0].Key))
+				require.Equal(t, "vfhxUGOIwKfcGci", kafka.BytesToString(b.Records[0].Key))
 				require.Equal(t, `"XidZuoWq "`, kafka.BytesToString(b.Records[0].Value))
 			},
 		},
@@ -99,7 +99,7 @@ func TestKafkaClient(t *testing.T) {
 				b, errCode := app.Kafka.Get("foo").Store.Topic("foo").Partition(0).Read(0, 1000)
 				require.Equal(t, kafka.None, errCode)
 				require.NotNil(t, b)
-				require.Equal(t, "EZyvmtlRf", kafka.BytesToString(b.Records[0].Key))
+				require.Equal(t, "vfhxUGOIwKfcGci", kafka.BytesToString(b.Records[0].Key))
 				require.Equal(t, `"XidZuoWq "`, kafka.BytesToString(b.Records[0].Value))
 			},
 		},
@@ -653,8 +653,8 @@ func TestKafkaClient(t *testing.T) {
 				b, errCode = app.Kafka.Get("foo").Store.Topic("foo").Partition(0).Read(1, 1000)
 				require.Equal(t, kafka.None, errCode)
 				require.NotNil(t, b)
-				require.Equal(t, "ijbptapwy", kafka.BytesToString(b.Records[0].Key))
-				require.Equal(t, `""`, kafka.BytesToString(b.Records[0].Value))
+				require.Equal(t, "mdcprrkjd", kafka.BytesToString(b.Records[0].Key))
+				require.Equal(t, `true`, kafka.BytesToString(b.Records[0].Value))
 			},
 		},
 		{
@@ -703,8 +703,8 @@ func TestKafkaClient(t *testing.T) {
 				b, errCode = app.Kafka.Get("foo").Store.Topic("foo").Partition(0).Read(1, 1000)
 				require.Equal(t, kafka.None, errCode)
 				require.NotNil(t, b)
-				require.Equal(t, "ijbptapwy", kafka.BytesToString(b.Records[0].Key))
-				require.Equal(t, `<foo id="98173564-6619-4557-888e-65b16bb5def5"></foo>`, kafka.BytesToString(b.Records[0].Value))
+				require.Equal(t, "mdcprrkjd", kafka.BytesToString(b.Records[0].Key))
+				require.Equal(t, `<foo id="64f486f4-a97f-4c80-acd4-7cb8c5dae22a"></foo>`, kafka.BytesToString(b.Records[0].Value))
 			},
 		},
 		{
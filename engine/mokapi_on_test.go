@@ -8,6 +8,7 @@ import (
 	"mokapi/engine/common"
 	"mokapi/engine/enginetest"
 	"mokapi/js/mokapi"
+	"mokapi/providers/asyncapi3/mqtt/store"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -292,6 +293,91 @@ export default () => {
 	}
 }
 
+func TestEventHandler_Mqtt(t *testing.T) {
+	testcases := []struct {
+		name   string
+		script string
+		run    func(evt common.EventEmitter) []*common.Action
+		test   func(t *testing.T, actions []*common.Action, err error)
+	}{
+		{
+			name: "rewrite payload and user properties",
+			script: `import { on } from 'mokapi'
+export default () => {
+	on('mqtt', (msg, ctx) => {
+		if (ctx.type === 'publish' && ctx.clientId === 'foo') {
+			msg.payload = msg.payload.toUpperCase()
+			msg.userProperties['source'] = 'mokapi'
+		}
+	})
+}
+`,
+			run: func(evt common.EventEmitter) []*common.Action {
+				msg := &store.EventMessage{Topic: "foo", Payload: "hello", UserProperties: map[string]string{}}
+				actions := evt.Emit("mqtt", msg, &store.EventContext{ClientId: "foo", Type: "publish"})
+				require.Equal(t, "HELLO", msg.Payload)
+				require.Equal(t, map[string]string{"source": "mokapi"}, msg.UserProperties)
+				return actions
+			},
+			test: func(t *testing.T, actions []*common.Action, err error) {
+				require.NoError(t, err)
+				require.Len(t, actions, 1)
+				require.Nil(t, actions[0].Error)
+			},
+		},
+		{
+			name: "drop message",
+			script: `import { on } from 'mokapi'
+export default () => {
+	on('mqtt', (msg) => {
+		msg.drop = msg.topic.startsWith('internal/')
+	})
+}
+`,
+			run: func(evt common.EventEmitter) []*common.Action {
+				msg := &store.EventMessage{Topic: "internal/foo"}
+				actions := evt.Emit("mqtt", msg, &store.EventContext{})
+				require.True(t, msg.Drop)
+				return actions
+			},
+			test: func(t *testing.T, actions []*common.Action, err error) {
+				require.NoError(t, err)
+				require.Len(t, actions, 1)
+			},
+		},
+		{
+			name: "no changes are not tracked",
+			script: `import { on } from 'mokapi'
+export default () => {
+	on('mqtt', (msg) => {})
+}
+`,
+			run: func(evt common.EventEmitter) []*common.Action {
+				return evt.Emit("mqtt", &store.EventMessage{Topic: "foo"}, &store.EventContext{})
+			},
+			test: func(t *testing.T, actions []*common.Action, err error) {
+				require.NoError(t, err)
+				require.Len(t, actions, 0)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			logrus.SetOutput(io.Discard)
+
+			e := enginetest.NewEngine()
+			err := e.AddScript(newScript("test.js", tc.script))
+
+			var actions []*common.Action
+			if err == nil {
+				actions = tc.run(e)
+			}
+			tc.test(t, actions, err)
+		})
+	}
+}
+
 func TestEventHandler_Priority(t *testing.T) {
 	testcases := []struct {
 		name    string
@@ -25,6 +25,12 @@ func WithKafkaClient(client common.KafkaClient) Options {
 	}
 }
 
+func WithWebSocketClient(client common.WebSocketClient) Options {
+	return func(e *Engine) {
+		e.wsClient = client
+	}
+}
+
 func WithScheduler(scheduler Scheduler) Options {
 	return func(e *Engine) {
 		e.scheduler = scheduler
@@ -46,6 +52,7 @@ func WithLogger(logger common.Logger) Options {
 func WithApp(app *runtime.App) Options {
 	return func(e *Engine) {
 		e.jobCounter = app.Monitor.JobCounter
+		e.eventDuration = app.Monitor.EventHandlerDuration
 		e.sm = app.Events
 	}
 }
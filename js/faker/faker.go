@@ -34,18 +34,18 @@ func Require(rt *goja.Runtime, module *goja.Object) {
 	_ = obj.Set("ROOT_NAME", generator.RootName)
 }
 
-func (m *Module) Fake(v goja.Value) interface{} {
-	i, err := m.fake(v)
+func (m *Module) Fake(v goja.Value, opts goja.Value) interface{} {
+	i, err := m.fake(v, opts)
 	if err != nil {
 		panic(m.vm.ToValue(err.Error()))
 	}
 	return i
 }
 
-func (m *Module) FakeAsync(v goja.Value) *goja.Promise {
+func (m *Module) FakeAsync(v goja.Value, opts goja.Value) *goja.Promise {
 	p, resolve, reject := m.vm.NewPromise()
 	go func() {
-		i, err := m.fake(v)
+		i, err := m.fake(v, opts)
 		if err != nil {
 			m.loop.Run(func(vm *goja.Runtime) {
 				_ = reject(err)
@@ -59,7 +59,7 @@ func (m *Module) FakeAsync(v goja.Value) *goja.Promise {
 	return p
 }
 
-func (m *Module) fake(v goja.Value) (interface{}, error) {
+func (m *Module) fake(v goja.Value, opts goja.Value) (interface{}, error) {
 	if v == nil {
 		return nil, nil
 	}
@@ -84,5 +84,12 @@ func (m *Module) fake(v goja.Value) (interface{}, error) {
 		r.Schema = s
 	}
 
+	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
+		o := opts.ToObject(m.vm)
+		if locale := o.Get("locale"); locale != nil && !goja.IsUndefined(locale) {
+			r.Locale = locale.String()
+		}
+	}
+
 	return generator.New(r)
 }
@@ -48,6 +48,18 @@ func TestModule(t *testing.T) {
 				r.Equal(t, "XidZuoWq ", v.Export())
 			},
 		},
+		{
+			name: "fake with locale",
+			test: func(t *testing.T, vm *goja.Runtime, _ *enginetest.Host) {
+				v, err := vm.RunString(`
+					const m = require('faker')
+					m.fake({ type: 'object', properties: { address: { type: 'object' } } }, { locale: 'fr-FR' })
+				`)
+				r.NoError(t, err)
+				address := v.Export().(map[string]any)["address"].(map[string]any)
+				r.Equal(t, "France", address["country"])
+			},
+		},
 		{
 			name: "fake with example",
 			test: func(t *testing.T, vm *goja.Runtime, _ *enginetest.Host) {
@@ -185,6 +197,7 @@ func TestModule(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			gofakeit.Seed(11)
+			generator.Seed(11)
 
 			reg, err := require.NewRegistry()
 			reg.RegisterNativeModule("faker", faker.Require)
@@ -263,6 +263,7 @@ func TestNode(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			gofakeit.Seed(11)
+			generator.Seed(11)
 
 			reg, err := require.NewRegistry()
 			reg.RegisterNativeModule("faker", faker.Require)
@@ -169,6 +169,8 @@ func ArgToJs(arg any, vm *goja.Runtime) goja.Value {
 					p.KeyNormalizer = http.CanonicalHeaderKey
 				case "rebuild":
 					return rebuild(vm, v)
+				case "stream":
+					return stream(vm, v)
 				}
 
 				switch val.(type) {
@@ -208,3 +210,34 @@ func rebuild(vm *goja.Runtime, res *common.HttpEventResponse) goja.Value {
 		res.Rebuild(int(s), c)
 	})
 }
+
+func stream(vm *goja.Runtime, res *common.HttpEventResponse) goja.Value {
+	return vm.ToValue(func(events goja.Value, opts goja.Value) {
+		if res.Stream == nil {
+			res.Stream = &common.HttpStream{}
+		}
+
+		if events != nil && !goja.IsUndefined(events) {
+			v := events.Export()
+			if arr, ok := v.([]any); ok {
+				res.Stream.Events = append(res.Stream.Events, arr...)
+			} else {
+				res.Stream.Events = append(res.Stream.Events, v)
+			}
+		}
+
+		if opts == nil || goja.IsUndefined(opts) || goja.IsNull(opts) {
+			return
+		}
+		if opts.ExportType().Kind() != reflect.Map {
+			panic(fmt.Sprintf("response.stream failed: options must be an object: got %v", util.JsType(opts.Export())))
+		}
+		interval := opts.ToObject(vm).Get("interval")
+		if interval != nil && !goja.IsUndefined(interval) {
+			if k := interval.ExportType().Kind(); k != reflect.Int64 && k != reflect.Float64 {
+				panic(fmt.Sprintf("response.stream failed: interval must be a number: got %v", util.JsType(interval.Export())))
+			}
+			res.Stream.Interval = interval.ToInteger()
+		}
+	})
+}
@@ -695,6 +695,49 @@ m.on('http', (req, res) => {
 				r.Equal(t, "response.rebuild failed: contentType must be a string: got Integer", actions[0].Error.Message)
 			},
 		},
+		{
+			name: "stream events",
+			script: `
+const m = require('mokapi')
+m.on('http', (req, res) => {
+	res.stream(['Hello', ' world']);
+	res.stream({ event: 'done', data: '' }, { interval: 50 });
+})
+`,
+			run: func(evt common.EventEmitter) []*common.Action {
+				return evt.Emit("http", &common.HttpEventRequest{}, &common.HttpEventResponse{})
+			},
+			test: func(t *testing.T, actions []*common.Action, err error) {
+				r.NoError(t, err)
+
+				r.Nil(t, actions[0].Error)
+
+				var res *common.HttpEventResponse
+				err = json.Unmarshal([]byte(actions[0].Parameters[1].(string)), &res)
+				r.Equal(t, &common.HttpStream{
+					Events:   []any{"Hello", " world", map[string]any{"event": "done", "data": ""}},
+					Interval: 50,
+				}, res.Stream)
+			},
+		},
+		{
+			name: "stream wrong type interval",
+			script: `
+const m = require('mokapi')
+m.on('http', (req, res) => {
+	res.stream('foo', { interval: '1s' });
+})
+`,
+			run: func(evt common.EventEmitter) []*common.Action {
+				return evt.Emit("http", &common.HttpEventRequest{}, &common.HttpEventResponse{})
+			},
+			test: func(t *testing.T, actions []*common.Action, err error) {
+				r.NoError(t, err)
+
+				r.NotNil(t, actions[0].Error)
+				r.Equal(t, "response.stream failed: interval must be a number: got String", actions[0].Error.Message)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -17,6 +17,8 @@ import (
 	"mokapi/js/mustache"
 	"mokapi/js/process"
 	"mokapi/js/require"
+	"mokapi/js/verify"
+	"mokapi/js/websocket"
 	"mokapi/js/yaml"
 	"reflect"
 	"strings"
@@ -281,6 +283,8 @@ func RegisterNativeModules(registry *require.Registry) {
 	registry.RegisterNativeModule("mokapi/ldap", ldap.Require)
 	registry.RegisterNativeModule("mokapi/encoding", encoding.Require)
 	registry.RegisterNativeModule("mokapi/file", file.Require)
+	registry.RegisterNativeModule("mokapi/websocket", websocket.Require)
+	registry.RegisterNativeModule("mokapi/verify", verify.Require)
 }
 
 func isClosingError(err error) bool {
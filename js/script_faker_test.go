@@ -238,6 +238,7 @@ func TestScript_Faker(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			gofakeit.Seed(11)
+			generator.Seed(11)
 			tc.test(t, &enginetest.Host{})
 		})
 	}
@@ -6,6 +6,7 @@ import (
 	"mokapi/engine/enginetest"
 	"mokapi/js"
 	"mokapi/js/jstest"
+	"mokapi/schema/json/generator"
 	"testing"
 )
 
@@ -63,6 +64,7 @@ func Test_Mustache(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			gofakeit.Seed(11)
+			generator.Seed(11)
 			tc.test(t)
 		})
 	}
@@ -0,0 +1,81 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"mokapi/engine/common"
+	"mokapi/runtime/events"
+
+	"github.com/dop251/goja"
+)
+
+type Module struct {
+	host common.Host
+	rt   *goja.Runtime
+}
+
+func Require(vm *goja.Runtime, module *goja.Object) {
+	o := vm.Get("mokapi/internal").(*goja.Object)
+	host := o.Get("host").Export().(common.Host)
+	m := &Module{
+		rt:   vm,
+		host: host,
+	}
+	obj := module.Get("exports").(*goja.Object)
+	_ = obj.Set("verify", m.Verify)
+	_ = obj.Set("reset", m.Reset)
+}
+
+// Verify returns the number of events and the events matching
+// the given matcher, newest first.
+func (m *Module) Verify(v goja.Value) goja.Value {
+	var matcher events.Matcher
+	if err := m.decode(v, &matcher); err != nil {
+		panic(m.rt.ToValue(fmt.Sprintf("invalid matcher: %v", err)))
+	}
+
+	result, err := m.verifier().Verify(matcher)
+	if err != nil {
+		panic(m.rt.ToValue(err.Error()))
+	}
+
+	// use the JSON representation so events look the same as in the REST API
+	b, err := json.Marshal(result)
+	if err != nil {
+		panic(m.rt.ToValue(err.Error()))
+	}
+	var r map[string]any
+	if err = json.Unmarshal(b, &r); err != nil {
+		panic(m.rt.ToValue(err.Error()))
+	}
+	return m.rt.ToValue(r)
+}
+
+// Reset removes all events matching the given traits and returns the
+// number of removed events. Without traits, all events are removed.
+func (m *Module) Reset(v goja.Value) int {
+	traits := events.NewTraits()
+	if err := m.decode(v, &traits); err != nil {
+		panic(m.rt.ToValue(fmt.Sprintf("invalid traits: %v", err)))
+	}
+	return m.verifier().ClearEvents(traits)
+}
+
+func (m *Module) verifier() common.EventVerifier {
+	v := m.host.EventVerifier()
+	if v == nil {
+		panic(m.rt.ToValue("event verification not available"))
+	}
+	return v
+}
+
+func (m *Module) decode(v goja.Value, target any) error {
+	if v == nil || goja.IsUndefined(v) || goja.IsNull(v) {
+		return nil
+	}
+	b, err := json.Marshal(v.Export())
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, target)
+}
@@ -0,0 +1,107 @@
+package verify_test
+
+import (
+	"mokapi/config/dynamic"
+	"mokapi/config/dynamic/dynamictest"
+	"mokapi/engine/enginetest"
+	"mokapi/js"
+	"mokapi/js/eventloop"
+	"mokapi/js/require"
+	"mokapi/js/verify"
+	"mokapi/providers/mail"
+	"mokapi/providers/openapi"
+	"mokapi/runtime/events"
+	"testing"
+
+	"github.com/dop251/goja"
+	r "github.com/stretchr/testify/require"
+)
+
+type index struct{}
+
+func (i *index) Add(string, any) {}
+func (i *index) Delete(string)   {}
+
+func TestVerify(t *testing.T) {
+	testcases := []struct {
+		name string
+		test func(t *testing.T, vm *goja.Runtime, sm *events.StoreManager)
+	}{
+		{
+			name: "count http requests",
+			test: func(t *testing.T, vm *goja.Runtime, sm *events.StoreManager) {
+				for _, body := range []string{`{"id":1}`, `{"id":2}`, `{"id":1}`} {
+					err := sm.Push(&openapi.HttpLog{
+						Request:  &openapi.HttpRequestLog{Method: "POST", Url: "http://localhost/orders", Body: body},
+						Response: &openapi.HttpResponseLog{StatusCode: 201},
+					}, events.NewTraits().WithNamespace("http").WithName("shop"))
+					r.NoError(t, err)
+				}
+
+				v, err := vm.RunString(`
+					const { verify } = require("mokapi/verify")
+					const r = verify({ http: { method: 'POST', path: '/orders', body: { '$.id': 1 } } });
+					[r.count, r.events[0].data.request.body]
+				`)
+				r.NoError(t, err)
+				r.Equal(t, []any{int64(2), `{"id":1}`}, v.Export())
+			},
+		},
+		{
+			name: "mail recipient",
+			test: func(t *testing.T, vm *goja.Runtime, sm *events.StoreManager) {
+				err := sm.Push(&mail.Log{From: "alice@example.com", To: []string{"bob@example.com"}, Subject: "Hello"}, events.NewTraits().WithNamespace("mail"))
+				r.NoError(t, err)
+
+				v, err := vm.RunString(`
+					const { verify } = require("mokapi/verify")
+					verify({ mail: { to: 'bob@example.com' } }).count
+				`)
+				r.NoError(t, err)
+				r.Equal(t, int64(1), v.Export())
+			},
+		},
+		{
+			name: "invalid matcher",
+			test: func(t *testing.T, vm *goja.Runtime, sm *events.StoreManager) {
+				_, err := vm.RunString(`
+					const { verify } = require("mokapi/verify")
+					verify({ http: { body: { id: 1 } } })
+				`)
+				r.EqualError(t, err, "invalid JSONPath 'id': must start with $ at mokapi/js/verify.(*Module).Verify-fm (native)")
+			},
+		},
+		{
+			name: "reset",
+			test: func(t *testing.T, vm *goja.Runtime, sm *events.StoreManager) {
+				r.NoError(t, sm.Push(&mail.Log{}, events.NewTraits().WithNamespace("mail")))
+				r.NoError(t, sm.Push(&openapi.HttpLog{}, events.NewTraits().WithNamespace("http")))
+
+				v, err := vm.RunString(`
+					const { reset } = require("mokapi/verify")
+					reset({ namespace: 'http' })
+				`)
+				r.NoError(t, err)
+				r.Equal(t, int64(1), v.Export())
+				r.Len(t, sm.GetEvents(events.NewTraits()), 1)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			sm := events.NewStoreManager(&index{})
+			sm.SetStore(10, events.NewTraits())
+
+			vm := goja.New()
+			host := &enginetest.Host{EventVerifierTest: sm}
+			js.EnableInternal(vm, host, &eventloop.EventLoop{}, &dynamic.Config{Info: dynamictest.NewConfigInfo()})
+			req, err := require.NewRegistry()
+			r.NoError(t, err)
+			req.Enable(vm)
+			req.RegisterNativeModule("mokapi/verify", verify.Require)
+
+			tc.test(t, vm, sm)
+		})
+	}
+}
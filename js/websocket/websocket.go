@@ -0,0 +1,102 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"mokapi/engine/common"
+
+	"github.com/dop251/goja"
+)
+
+type Module struct {
+	host common.Host
+	rt   *goja.Runtime
+}
+
+type sendOptions struct {
+	api    string
+	binary bool
+}
+
+func Require(vm *goja.Runtime, module *goja.Object) {
+	o := vm.Get("mokapi/internal").(*goja.Object)
+	host := o.Get("host").Export().(common.Host)
+	m := &Module{
+		rt:   vm,
+		host: host,
+	}
+	obj := module.Get("exports").(*goja.Object)
+	_ = obj.Set("broadcast", m.Broadcast)
+	_ = obj.Set("send", m.Send)
+}
+
+// Broadcast sends the data to all clients connected to the channel and
+// returns the number of clients the data was sent to.
+func (m *Module) Broadcast(channel string, data goja.Value, opts goja.Value) int {
+	return m.send(&common.WebSocketSendArgs{Channel: channel}, data, opts)
+}
+
+// Send sends the data to the client of the given session.
+func (m *Module) Send(sessionId string, data goja.Value, opts goja.Value) bool {
+	return m.send(&common.WebSocketSendArgs{SessionId: sessionId}, data, opts) > 0
+}
+
+func (m *Module) send(args *common.WebSocketSendArgs, data goja.Value, opts goja.Value) int {
+	o := m.parseOptions(opts)
+	args.Api = o.api
+	args.Binary = o.binary
+
+	var err error
+	args.Data, err = toBytes(data)
+	if err != nil {
+		panic(m.rt.ToValue(err.Error()))
+	}
+
+	client := m.host.WebSocketClient()
+	if client == nil {
+		panic(m.rt.ToValue("websocket client not available"))
+	}
+	n, err := client.Send(args)
+	if err != nil {
+		panic(m.rt.ToValue(err.Error()))
+	}
+	return n
+}
+
+func (m *Module) parseOptions(v goja.Value) sendOptions {
+	var o sendOptions
+	if v == nil || goja.IsUndefined(v) || goja.IsNull(v) {
+		return o
+	}
+	obj := v.ToObject(m.rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "api":
+			o.api = obj.Get(k).String()
+		case "binary":
+			o.binary = obj.Get(k).ToBoolean()
+		}
+	}
+	return o
+}
+
+// toBytes writes strings as they are and encodes any other value as JSON
+func toBytes(v goja.Value) ([]byte, error) {
+	if v == nil || goja.IsUndefined(v) || goja.IsNull(v) {
+		return []byte{}, nil
+	}
+	switch val := v.Export().(type) {
+	case string:
+		return []byte(val), nil
+	case []byte:
+		return val, nil
+	case goja.ArrayBuffer:
+		return val.Bytes(), nil
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return nil, fmt.Errorf("encode websocket data failed: %w", err)
+		}
+		return b, nil
+	}
+}
@@ -0,0 +1,90 @@
+package websocket_test
+
+import (
+	"mokapi/config/dynamic"
+	"mokapi/config/dynamic/dynamictest"
+	"mokapi/engine/common"
+	"mokapi/engine/enginetest"
+	"mokapi/js"
+	"mokapi/js/eventloop"
+	"mokapi/js/require"
+	"mokapi/js/websocket"
+	"testing"
+
+	"github.com/dop251/goja"
+	r "github.com/stretchr/testify/require"
+)
+
+func TestWebSocket(t *testing.T) {
+	testcases := []struct {
+		name string
+		test func(t *testing.T, vm *goja.Runtime, host *enginetest.Host)
+	}{
+		{
+			name: "broadcast string",
+			test: func(t *testing.T, vm *goja.Runtime, host *enginetest.Host) {
+				host.WebSocketTest = &enginetest.WebSocketClient{SendFunc: func(args *common.WebSocketSendArgs) (int, error) {
+					r.Equal(t, "chat", args.Channel)
+					r.Equal(t, "", args.SessionId)
+					r.Equal(t, "hello", string(args.Data))
+					r.False(t, args.Binary)
+					return 2, nil
+				}}
+
+				v, err := vm.RunString(`
+					const ws = require("mokapi/websocket")
+					ws.broadcast('chat', 'hello')
+				`)
+				r.NoError(t, err)
+				r.Equal(t, int64(2), v.Export())
+			},
+		},
+		{
+			name: "broadcast object as JSON",
+			test: func(t *testing.T, vm *goja.Runtime, host *enginetest.Host) {
+				host.WebSocketTest = &enginetest.WebSocketClient{SendFunc: func(args *common.WebSocketSendArgs) (int, error) {
+					r.Equal(t, "Chat API", args.Api)
+					r.Equal(t, `{"text":"hello"}`, string(args.Data))
+					return 1, nil
+				}}
+
+				_, err := vm.RunString(`
+					const ws = require("mokapi/websocket")
+					ws.broadcast('chat', { text: 'hello' }, { api: 'Chat API' })
+				`)
+				r.NoError(t, err)
+			},
+		},
+		{
+			name: "send to session",
+			test: func(t *testing.T, vm *goja.Runtime, host *enginetest.Host) {
+				host.WebSocketTest = &enginetest.WebSocketClient{SendFunc: func(args *common.WebSocketSendArgs) (int, error) {
+					r.Equal(t, "123", args.SessionId)
+					r.True(t, args.Binary)
+					return 0, nil
+				}}
+
+				v, err := vm.RunString(`
+					const ws = require("mokapi/websocket")
+					ws.send('123', 'hello', { binary: true })
+				`)
+				r.NoError(t, err)
+				r.Equal(t, false, v.Export())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			vm := goja.New()
+			host := &enginetest.Host{}
+			js.EnableInternal(vm, host, &eventloop.EventLoop{}, &dynamic.Config{Info: dynamictest.NewConfigInfo()})
+			req, err := require.NewRegistry()
+			r.NoError(t, err)
+			req.Enable(vm)
+			req.RegisterNativeModule("mokapi/websocket", websocket.Require)
+
+			tc.test(t, vm, host)
+		})
+	}
+}
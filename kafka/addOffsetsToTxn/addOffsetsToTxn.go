@@ -0,0 +1,31 @@
+package addOffsetsToTxn
+
+import "mokapi/kafka"
+
+func init() {
+	kafka.Register(
+		kafka.ApiReg{
+			ApiKey:     kafka.AddOffsetsToTxn,
+			MinVersion: 0,
+			MaxVersion: 3,
+		},
+		&Request{},
+		&Response{},
+		3,
+		3,
+	)
+}
+
+type Request struct {
+	TransactionalId string           `kafka:"compact=3"`
+	ProducerId      int64            `kafka:""`
+	ProducerEpoch   int16            `kafka:""`
+	GroupId         string           `kafka:"compact=3"`
+	TagFields       map[int64]string `kafka:"type=TAG_BUFFER,min=3"`
+}
+
+type Response struct {
+	ThrottleTimeMs int32            `kafka:""`
+	ErrorCode      kafka.ErrorCode  `kafka:""`
+	TagFields      map[int64]string `kafka:"type=TAG_BUFFER,min=3"`
+}
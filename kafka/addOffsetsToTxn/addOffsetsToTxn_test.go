@@ -0,0 +1,44 @@
+package addOffsetsToTxn_test
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/addOffsetsToTxn"
+	"mokapi/kafka/kafkatest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	reg := kafka.ApiTypes[kafka.AddOffsetsToTxn]
+	require.Equal(t, int16(0), reg.MinVersion)
+	require.Equal(t, int16(3), reg.MaxVersion)
+}
+
+func TestRequest(t *testing.T) {
+	kafkatest.TestRequest(t, 2, &addOffsetsToTxn.Request{
+		TransactionalId: "trx",
+		ProducerId:      123,
+		ProducerEpoch:   1,
+		GroupId:         "foo",
+	})
+
+	kafkatest.TestRequest(t, 3, &addOffsetsToTxn.Request{
+		TransactionalId: "trx",
+		ProducerId:      123,
+		ProducerEpoch:   1,
+		GroupId:         "foo",
+	})
+}
+
+func TestResponse(t *testing.T) {
+	kafkatest.TestResponse(t, 2, &addOffsetsToTxn.Response{
+		ThrottleTimeMs: 100,
+		ErrorCode:      kafka.InvalidTxnState,
+	})
+
+	kafkatest.TestResponse(t, 3, &addOffsetsToTxn.Response{
+		ThrottleTimeMs: 100,
+		ErrorCode:      kafka.None,
+	})
+}
@@ -0,0 +1,49 @@
+package addPartitionsToTxn
+
+import "mokapi/kafka"
+
+func init() {
+	kafka.Register(
+		kafka.ApiReg{
+			ApiKey:     kafka.AddPartitionsToTxn,
+			MinVersion: 0,
+			MaxVersion: 3,
+		},
+		&Request{},
+		&Response{},
+		3,
+		3,
+	)
+}
+
+type Request struct {
+	TransactionalId string           `kafka:"compact=3"`
+	ProducerId      int64            `kafka:""`
+	ProducerEpoch   int16            `kafka:""`
+	Topics          []Topic          `kafka:"compact=3"`
+	TagFields       map[int64]string `kafka:"type=TAG_BUFFER,min=3"`
+}
+
+type Topic struct {
+	Name       string           `kafka:"compact=3"`
+	Partitions []int32          `kafka:"compact=3"`
+	TagFields  map[int64]string `kafka:"type=TAG_BUFFER,min=3"`
+}
+
+type Response struct {
+	ThrottleTimeMs int32            `kafka:""`
+	Results        []ResponseTopic  `kafka:"compact=3"`
+	TagFields      map[int64]string `kafka:"type=TAG_BUFFER,min=3"`
+}
+
+type ResponseTopic struct {
+	Name      string              `kafka:"compact=3"`
+	Results   []ResponsePartition `kafka:"compact=3"`
+	TagFields map[int64]string    `kafka:"type=TAG_BUFFER,min=3"`
+}
+
+type ResponsePartition struct {
+	PartitionIndex int32            `kafka:""`
+	ErrorCode      kafka.ErrorCode  `kafka:""`
+	TagFields      map[int64]string `kafka:"type=TAG_BUFFER,min=3"`
+}
@@ -0,0 +1,90 @@
+package addPartitionsToTxn_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"mokapi/kafka"
+	"mokapi/kafka/addPartitionsToTxn"
+	"mokapi/kafka/kafkatest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	reg := kafka.ApiTypes[kafka.AddPartitionsToTxn]
+	require.Equal(t, int16(0), reg.MinVersion)
+	require.Equal(t, int16(3), reg.MaxVersion)
+}
+
+func TestRequest(t *testing.T) {
+	kafkatest.TestRequest(t, 2, &addPartitionsToTxn.Request{
+		TransactionalId: "trx",
+		ProducerId:      123,
+		ProducerEpoch:   1,
+		Topics: []addPartitionsToTxn.Topic{
+			{Name: "foo", Partitions: []int32{0, 1}},
+		},
+	})
+
+	kafkatest.TestRequest(t, 3, &addPartitionsToTxn.Request{
+		TransactionalId: "trx",
+		ProducerId:      123,
+		ProducerEpoch:   1,
+		Topics: []addPartitionsToTxn.Topic{
+			{Name: "foo", Partitions: []int32{0, 1}},
+		},
+	})
+
+	b := kafkatest.WriteRequest(t, 3, 123, "me", &addPartitionsToTxn.Request{
+		TransactionalId: "trx",
+		ProducerId:      123,
+		ProducerEpoch:   1,
+		Topics: []addPartitionsToTxn.Topic{
+			{Name: "foo", Partitions: []int32{0}},
+		},
+	})
+	expected := new(bytes.Buffer)
+	// header
+	_ = binary.Write(expected, binary.BigEndian, int32(39))                       // length
+	_ = binary.Write(expected, binary.BigEndian, int16(kafka.AddPartitionsToTxn)) // ApiKey
+	_ = binary.Write(expected, binary.BigEndian, int16(3))                        // ApiVersion
+	_ = binary.Write(expected, binary.BigEndian, int32(123))                      // correlationId
+	_ = binary.Write(expected, binary.BigEndian, int16(2))                        // ClientId length
+	_ = binary.Write(expected, binary.BigEndian, []byte("me"))                    // ClientId
+	_ = binary.Write(expected, binary.BigEndian, int8(0))                         // tag buffer
+	// message
+	_ = binary.Write(expected, binary.BigEndian, int8(4))       // TransactionalId length
+	_ = binary.Write(expected, binary.BigEndian, []byte("trx")) // TransactionalId
+	_ = binary.Write(expected, binary.BigEndian, int64(123))    // ProducerId
+	_ = binary.Write(expected, binary.BigEndian, int16(1))      // ProducerEpoch
+	_ = binary.Write(expected, binary.BigEndian, int8(2))       // Topics length
+	_ = binary.Write(expected, binary.BigEndian, int8(4))       // Name length
+	_ = binary.Write(expected, binary.BigEndian, []byte("foo")) // Name
+	_ = binary.Write(expected, binary.BigEndian, int8(2))       // Partitions length
+	_ = binary.Write(expected, binary.BigEndian, int32(0))      // Partition
+	_ = binary.Write(expected, binary.BigEndian, int8(0))       // tag buffer
+	_ = binary.Write(expected, binary.BigEndian, int8(0))       // tag buffer
+	require.Equal(t, expected.Bytes(), b)
+}
+
+func TestResponse(t *testing.T) {
+	kafkatest.TestResponse(t, 2, &addPartitionsToTxn.Response{
+		ThrottleTimeMs: 100,
+		Results: []addPartitionsToTxn.ResponseTopic{
+			{Name: "foo", Results: []addPartitionsToTxn.ResponsePartition{
+				{PartitionIndex: 0, ErrorCode: kafka.None},
+				{PartitionIndex: 1, ErrorCode: kafka.UnknownTopicOrPartition},
+			}},
+		},
+	})
+
+	kafkatest.TestResponse(t, 3, &addPartitionsToTxn.Response{
+		ThrottleTimeMs: 100,
+		Results: []addPartitionsToTxn.ResponseTopic{
+			{Name: "foo", Results: []addPartitionsToTxn.ResponsePartition{
+				{PartitionIndex: 0, ErrorCode: kafka.None},
+			}},
+		},
+	})
+}
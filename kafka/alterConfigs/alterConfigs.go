@@ -0,0 +1,55 @@
+package alterConfigs
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/describeConfigs"
+)
+
+func init() {
+	kafka.Register(
+		kafka.ApiReg{
+			ApiKey:     kafka.AlterConfigs,
+			MinVersion: 0,
+			MaxVersion: 2,
+		},
+		&Request{},
+		&Response{},
+		2,
+		2,
+	)
+}
+
+type Request struct {
+	Resources    []Resource       `kafka:"compact=2"`
+	ValidateOnly bool             `kafka:""`
+	TagFields    map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type Resource struct {
+	ResourceType describeConfigs.ResourceType `kafka:""`
+	ResourceName string                       `kafka:"compact=2"`
+	// Configs replaces all dynamic configs of the resource. Configs which are
+	// not listed are reset to their default value.
+	Configs   []Config         `kafka:"compact=2"`
+	TagFields map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type Config struct {
+	Name      string           `kafka:"compact=2"`
+	Value     string           `kafka:"compact=2,nullable"`
+	TagFields map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type Response struct {
+	ThrottleTimeMs int32            `kafka:""`
+	Responses      []Result         `kafka:"compact=2"`
+	TagFields      map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type Result struct {
+	ErrorCode    kafka.ErrorCode              `kafka:""`
+	ErrorMessage string                       `kafka:"compact=2,nullable"`
+	ResourceType describeConfigs.ResourceType `kafka:""`
+	ResourceName string                       `kafka:"compact=2"`
+	TagFields    map[int64]string             `kafka:"type=TAG_BUFFER,min=2"`
+}
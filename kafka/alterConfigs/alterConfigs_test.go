@@ -0,0 +1,56 @@
+package alterConfigs_test
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/alterConfigs"
+	"mokapi/kafka/describeConfigs"
+	"mokapi/kafka/kafkatest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	reg := kafka.ApiTypes[kafka.AlterConfigs]
+	require.Equal(t, int16(0), reg.MinVersion)
+	require.Equal(t, int16(2), reg.MaxVersion)
+}
+
+func TestRequest(t *testing.T) {
+	kafkatest.TestRequest(t, 1, &alterConfigs.Request{
+		Resources: []alterConfigs.Resource{
+			{
+				ResourceType: describeConfigs.ResourceTopic,
+				ResourceName: "foo",
+				Configs:      []alterConfigs.Config{{Name: "retention.ms", Value: "1000"}},
+			},
+		},
+		ValidateOnly: true,
+	})
+
+	kafkatest.TestRequest(t, 2, &alterConfigs.Request{
+		Resources: []alterConfigs.Resource{
+			{
+				ResourceType: describeConfigs.ResourceTopic,
+				ResourceName: "foo",
+				Configs:      []alterConfigs.Config{{Name: "retention.ms", Value: "1000"}},
+			},
+		},
+	})
+}
+
+func TestResponse(t *testing.T) {
+	kafkatest.TestResponse(t, 1, &alterConfigs.Response{
+		ThrottleTimeMs: 100,
+		Responses: []alterConfigs.Result{
+			{ResourceType: describeConfigs.ResourceTopic, ResourceName: "foo", ErrorCode: kafka.InvalidConfig, ErrorMessage: "invalid"},
+		},
+	})
+
+	kafkatest.TestResponse(t, 2, &alterConfigs.Response{
+		ThrottleTimeMs: 100,
+		Responses: []alterConfigs.Result{
+			{ResourceType: describeConfigs.ResourceTopic, ResourceName: "foo"},
+		},
+	})
+}
@@ -2,6 +2,7 @@ package kafka
 
 import (
 	"context"
+	"mokapi/sasl"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,6 +30,21 @@ type ClientContext struct {
 	Close                  func()
 	AllowAutoTopicCreation bool
 	ServerAddress          string
+
+	// SaslMechanism is the mechanism selected by SaslHandshake
+	SaslMechanism string
+	SaslServer    sasl.Server
+	// Principal is the user authenticated via SASL
+	Principal string
+
+	disconnect func()
+}
+
+// Disconnect closes the client connection after the current request has been handled.
+func (c *ClientContext) Disconnect() {
+	if c.disconnect != nil {
+		c.disconnect()
+	}
 }
 
 func (c *ClientContext) AddGroup(groupName, memberId string) {
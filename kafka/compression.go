@@ -0,0 +1,169 @@
+package kafka
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+type Compression int8
+
+const (
+	NoCompression Compression = 0
+	Gzip          Compression = 1
+	Snappy        Compression = 2
+	Lz4           Compression = 3
+	Zstd          Compression = 4
+)
+
+// xerial framing used by the Java client for snappy compressed batches
+var xerialHeader = []byte{130, 'S', 'N', 'A', 'P', 'P', 'Y', 0}
+
+var (
+	zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+		return zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	})
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	})
+)
+
+func (c Compression) String() string {
+	switch c {
+	case NoCompression:
+		return "none"
+	case Gzip:
+		return "gzip"
+	case Snappy:
+		return "snappy"
+	case Lz4:
+		return "lz4"
+	case Zstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("unknown(%d)", int8(c))
+	}
+}
+
+// ParseCompression parses a codec name as used by the Kafka configuration
+// compression.type. Both "none" and "uncompressed" disable compression.
+func ParseCompression(s string) (Compression, error) {
+	switch strings.ToLower(s) {
+	case "none", "uncompressed":
+		return NoCompression, nil
+	case "gzip":
+		return Gzip, nil
+	case "snappy":
+		return Snappy, nil
+	case "lz4":
+		return Lz4, nil
+	case "zstd":
+		return Zstd, nil
+	default:
+		return NoCompression, fmt.Errorf("unknown compression type '%v'", s)
+	}
+}
+
+func (c Compression) compress(data []byte) ([]byte, error) {
+	switch c {
+	case NoCompression:
+		return data, nil
+	case Gzip:
+		var b bytes.Buffer
+		w := gzip.NewWriter(&b)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	case Snappy:
+		return snappy.Encode(nil, data), nil
+	case Lz4:
+		var b bytes.Buffer
+		w := lz4.NewWriter(&b)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	case Zstd:
+		enc, err := zstdEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.EncodeAll(data, nil), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %v", c)
+	}
+}
+
+func (c Compression) decompress(data []byte) ([]byte, error) {
+	switch c {
+	case NoCompression:
+		return data, nil
+	case Gzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	case Snappy:
+		if bytes.HasPrefix(data, xerialHeader) {
+			return decodeXerial(data)
+		}
+		return snappy.Decode(nil, data)
+	case Lz4:
+		return io.ReadAll(lz4.NewReader(bytes.NewReader(data)))
+	case Zstd:
+		dec, err := zstdDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.DecodeAll(data, nil)
+	default:
+		return nil, fmt.Errorf("unsupported compression %v", c)
+	}
+}
+
+// decodeXerial decodes snappy data framed by the xerial snappy-java library:
+// a 16 byte header (magic, version, compatible version) followed by
+// blocks, each prefixed with its length as a 4 byte big-endian integer.
+func decodeXerial(data []byte) ([]byte, error) {
+	const headerSize = 16
+	if len(data) < headerSize {
+		return nil, fmt.Errorf("invalid xerial snappy header")
+	}
+	data = data[headerSize:]
+
+	var result []byte
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("invalid xerial snappy block size")
+		}
+		size := int(binary.BigEndian.Uint32(data[:4]))
+		data = data[4:]
+		if size > len(data) {
+			return nil, fmt.Errorf("xerial snappy block exceeds data")
+		}
+		block, err := snappy.Decode(nil, data[:size])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, block...)
+		data = data[size:]
+	}
+	return result, nil
+}
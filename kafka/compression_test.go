@@ -0,0 +1,160 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"mokapi/buffer"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordBatch_Compression(t *testing.T) {
+	codecs := []Compression{Gzip, Snappy, Lz4, Zstd}
+
+	for _, codec := range codecs {
+		t.Run(codec.String(), func(t *testing.T) {
+			value := strings.Repeat("bar", 100)
+			batch := RecordBatch{
+				Compression: codec,
+				Records: []*Record{
+					{
+						Offset:  5,
+						Time:    ToTime(Timestamp(time.Now())),
+						Key:     NewBytes([]byte("foo")),
+						Value:   NewBytes([]byte(value)),
+						Headers: []RecordHeader{{Key: "foo", Value: []byte("bar")}},
+					},
+					{
+						Offset: 6,
+						Time:   ToTime(Timestamp(time.Now())),
+						Value:  NewBytes([]byte("baz")),
+					},
+				},
+			}
+
+			pb := buffer.NewPageBuffer()
+			batch.WriteTo(NewEncoder(pb), 0, kafkaTag{})
+			var buf bytes.Buffer
+			_, err := pb.WriteTo(&buf)
+			require.NoError(t, err)
+
+			// attributes of the batch: length(4) + offset(8) + batch length(4) + epoch(4) + magic(1) + crc(4)
+			attributes := binary.BigEndian.Uint16(buf.Bytes()[25:27])
+			require.Equal(t, uint16(codec), attributes)
+			require.NotContains(t, buf.String(), value, "value should be compressed")
+
+			d := NewDecoder(bytes.NewReader(buf.Bytes()), buf.Len())
+			result := RecordBatch{}
+			err = result.ReadFrom(d, 0, kafkaTag{})
+			require.NoError(t, err)
+
+			require.Equal(t, codec, result.Compression)
+			require.Len(t, result.Records, 2)
+			require.Equal(t, int64(5), result.Records[0].Offset)
+			require.Equal(t, "foo", readBytes(result.Records[0].Key))
+			require.Equal(t, value, readBytes(result.Records[0].Value))
+			require.Equal(t, []RecordHeader{{Key: "foo", Value: []byte("bar")}}, result.Records[0].Headers)
+			require.Equal(t, int64(6), result.Records[1].Offset)
+			require.Nil(t, result.Records[1].Key)
+			require.Equal(t, "baz", readBytes(result.Records[1].Value))
+		})
+	}
+}
+
+func TestRecordBatch_CompressedV1(t *testing.T) {
+	inner := []byte{
+		0, 0, 0, 0, 0, 0, 0, 0, // relative offset
+		0, 0, 0, 26, // message size
+		0, 0, 0, 0, // crc
+		1,                              // magic
+		0,                              // attributes
+		0, 0, 1, 125, 158, 189, 76, 76, // timestamp
+		0, 0, 0, 3, 'f', 'o', 'o', // key
+		0, 0, 0, 3, 'b', 'a', 'r', // value
+	}
+	compressed, err := Gzip.compress(inner)
+	require.NoError(t, err)
+
+	var data []byte
+	data = binary.BigEndian.AppendUint64(data, 12) // offset of last inner message
+	data = binary.BigEndian.AppendUint32(data, uint32(22+len(compressed)))
+	data = append(data, 0, 0, 0, 0)                        // crc
+	data = append(data, 1)                                 // magic
+	data = append(data, byte(Gzip))                        // attributes
+	data = append(data, 0, 0, 1, 125, 158, 189, 76, 76)    // timestamp
+	data = binary.BigEndian.AppendUint32(data, 0xffffffff) // null key
+	data = binary.BigEndian.AppendUint32(data, uint32(len(compressed)))
+	data = append(data, compressed...)
+
+	var b []byte
+	b = binary.BigEndian.AppendUint32(b, uint32(len(data)))
+	b = append(b, data...)
+
+	d := NewDecoder(bytes.NewReader(b), len(b))
+	batch := RecordBatch{}
+	err = batch.ReadFrom(d, 0, kafkaTag{})
+	require.NoError(t, err)
+	require.Equal(t, Gzip, batch.Compression)
+	require.Len(t, batch.Records, 1)
+	require.Equal(t, int64(12), batch.Records[0].Offset)
+	require.Equal(t, "foo", readBytes(batch.Records[0].Key))
+	require.Equal(t, "bar", readBytes(batch.Records[0].Value))
+}
+
+func TestCompression_Xerial(t *testing.T) {
+	block1 := snappy.Encode(nil, []byte("hello "))
+	block2 := snappy.Encode(nil, []byte("world"))
+
+	data := append([]byte{}, xerialHeader...)
+	data = binary.BigEndian.AppendUint32(data, 1) // version
+	data = binary.BigEndian.AppendUint32(data, 1) // compatible version
+	data = binary.BigEndian.AppendUint32(data, uint32(len(block1)))
+	data = append(data, block1...)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(block2)))
+	data = append(data, block2...)
+
+	b, err := Snappy.decompress(data)
+	require.NoError(t, err)
+	require.Equal(t, "hello world", string(b))
+
+	_, err = Snappy.decompress(data[:len(data)-2])
+	require.EqualError(t, err, "xerial snappy block exceeds data")
+}
+
+func TestParseCompression(t *testing.T) {
+	testcases := []struct {
+		value string
+		want  Compression
+		err   string
+	}{
+		{value: "none", want: NoCompression},
+		{value: "uncompressed", want: NoCompression},
+		{value: "gzip", want: Gzip},
+		{value: "SNAPPY", want: Snappy},
+		{value: "lz4", want: Lz4},
+		{value: "zstd", want: Zstd},
+		{value: "brotli", err: "unknown compression type 'brotli'"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.value, func(t *testing.T) {
+			c, err := ParseCompression(tc.value)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.want, c)
+			}
+		})
+	}
+}
+
+func readBytes(b Bytes) string {
+	data, _ := io.ReadAll(b)
+	return string(data)
+}
@@ -0,0 +1,50 @@
+package createPartitions
+
+import "mokapi/kafka"
+
+func init() {
+	kafka.Register(
+		kafka.ApiReg{
+			ApiKey:     kafka.CreatePartitions,
+			MinVersion: 0,
+			MaxVersion: 3,
+		},
+		&Request{},
+		&Response{},
+		2,
+		2,
+	)
+}
+
+type Request struct {
+	Topics       []Topic          `kafka:"compact=2"`
+	TimeoutMs    int32            `kafka:""`
+	ValidateOnly bool             `kafka:""`
+	TagFields    map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type Topic struct {
+	Name string `kafka:"compact=2"`
+	// Count is the new total number of partitions
+	Count       int32            `kafka:""`
+	Assignments []Assignment     `kafka:"compact=2"`
+	TagFields   map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type Assignment struct {
+	BrokerIds []int32          `kafka:"compact=2"`
+	TagFields map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type Response struct {
+	ThrottleTimeMs int32            `kafka:""`
+	Results        []Result         `kafka:"compact=2"`
+	TagFields      map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type Result struct {
+	Name         string           `kafka:"compact=2"`
+	ErrorCode    kafka.ErrorCode  `kafka:""`
+	ErrorMessage string           `kafka:"compact=2,nullable"`
+	TagFields    map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
@@ -0,0 +1,54 @@
+package createPartitions_test
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/createPartitions"
+	"mokapi/kafka/kafkatest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	reg := kafka.ApiTypes[kafka.CreatePartitions]
+	require.Equal(t, int16(0), reg.MinVersion)
+	require.Equal(t, int16(3), reg.MaxVersion)
+}
+
+func TestRequest(t *testing.T) {
+	kafkatest.TestRequest(t, 1, &createPartitions.Request{
+		Topics: []createPartitions.Topic{
+			{
+				Name:        "foo",
+				Count:       3,
+				Assignments: []createPartitions.Assignment{{BrokerIds: []int32{0}}},
+			},
+		},
+		TimeoutMs:    30000,
+		ValidateOnly: true,
+	})
+
+	kafkatest.TestRequest(t, 3, &createPartitions.Request{
+		Topics: []createPartitions.Topic{
+			{Name: "foo", Count: 3, Assignments: []createPartitions.Assignment{}},
+		},
+		TimeoutMs: 30000,
+	})
+}
+
+func TestResponse(t *testing.T) {
+	kafkatest.TestResponse(t, 1, &createPartitions.Response{
+		ThrottleTimeMs: 100,
+		Results: []createPartitions.Result{
+			{Name: "foo", ErrorCode: kafka.None},
+			{Name: "bar", ErrorCode: kafka.InvalidPartitions, ErrorMessage: "invalid"},
+		},
+	})
+
+	kafkatest.TestResponse(t, 3, &createPartitions.Response{
+		ThrottleTimeMs: 100,
+		Results: []createPartitions.Result{
+			{Name: "foo", ErrorCode: kafka.None},
+		},
+	})
+}
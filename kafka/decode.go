@@ -79,7 +79,7 @@ func newBytesDecodeFunc(version int16, tag kafkaTag) decodeFunc {
 
 func newArayDecodeFunc(t reflect.Type, version int16, tag kafkaTag) decodeFunc {
 	elemType := t.Elem()
-	elemFunc := newDecodeFunc(elemType, version, kafkaTag{})
+	elemFunc := newDecodeFunc(elemType, version, tag)
 
 	if version >= tag.compact {
 		return func(d *Decoder, v reflect.Value) { d.decodeCompactArray(v, elemFunc) }
@@ -0,0 +1,35 @@
+package deleteGroups
+
+import (
+	"mokapi/kafka"
+)
+
+func init() {
+	kafka.Register(
+		kafka.ApiReg{
+			ApiKey:     kafka.DeleteGroups,
+			MinVersion: 0,
+			MaxVersion: 2},
+		&Request{},
+		&Response{},
+		2,
+		2,
+	)
+}
+
+type Request struct {
+	GroupNames []string         `kafka:"compact=2"`
+	TagFields  map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type Response struct {
+	ThrottleTimeMs int32            `kafka:""`
+	Results        []Result         `kafka:"compact=2"`
+	TagFields      map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type Result struct {
+	GroupId   string           `kafka:"compact=2"`
+	ErrorCode kafka.ErrorCode  `kafka:""`
+	TagFields map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
@@ -0,0 +1,42 @@
+package deleteGroups_test
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/deleteGroups"
+	"mokapi/kafka/kafkatest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	reg := kafka.ApiTypes[kafka.DeleteGroups]
+	require.Equal(t, int16(0), reg.MinVersion)
+	require.Equal(t, int16(2), reg.MaxVersion)
+}
+
+func TestRequest(t *testing.T) {
+	kafkatest.TestRequest(t, 0, &deleteGroups.Request{
+		GroupNames: []string{"foo", "bar"},
+	})
+
+	kafkatest.TestRequest(t, 2, &deleteGroups.Request{
+		GroupNames: []string{"foo"},
+	})
+}
+
+func TestResponse(t *testing.T) {
+	kafkatest.TestResponse(t, 0, &deleteGroups.Response{
+		ThrottleTimeMs: 123,
+		Results: []deleteGroups.Result{
+			{GroupId: "foo"},
+			{GroupId: "bar", ErrorCode: kafka.NonEmptyGroup},
+		},
+	})
+
+	kafkatest.TestResponse(t, 2, &deleteGroups.Response{
+		Results: []deleteGroups.Result{
+			{GroupId: "foo", ErrorCode: kafka.GroupIdNotFound},
+		},
+	})
+}
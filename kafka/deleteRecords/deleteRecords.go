@@ -0,0 +1,56 @@
+package deleteRecords
+
+import "mokapi/kafka"
+
+func init() {
+	kafka.Register(
+		kafka.ApiReg{
+			ApiKey:     kafka.DeleteRecords,
+			MinVersion: 0,
+			MaxVersion: 2,
+		},
+		&Request{},
+		&Response{},
+		2,
+		2,
+	)
+}
+
+type Request struct {
+	Topics    []Topic          `kafka:"compact=2"`
+	TimeoutMs int32            `kafka:""`
+	TagFields map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type Topic struct {
+	Name       string           `kafka:"compact=2"`
+	Partitions []Partition      `kafka:"compact=2"`
+	TagFields  map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type Partition struct {
+	PartitionIndex int32 `kafka:""`
+	// Offset is the offset before which records are deleted. The value -1
+	// deletes all records up to the high watermark.
+	Offset    int64            `kafka:""`
+	TagFields map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type Response struct {
+	ThrottleTimeMs int32            `kafka:""`
+	Topics         []ResponseTopic  `kafka:"compact=2"`
+	TagFields      map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type ResponseTopic struct {
+	Name       string              `kafka:"compact=2"`
+	Partitions []ResponsePartition `kafka:"compact=2"`
+	TagFields  map[int64]string    `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type ResponsePartition struct {
+	PartitionIndex int32            `kafka:""`
+	LowWatermark   int64            `kafka:""`
+	ErrorCode      kafka.ErrorCode  `kafka:""`
+	TagFields      map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
@@ -0,0 +1,58 @@
+package deleteRecords_test
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/deleteRecords"
+	"mokapi/kafka/kafkatest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	reg := kafka.ApiTypes[kafka.DeleteRecords]
+	require.Equal(t, int16(0), reg.MinVersion)
+	require.Equal(t, int16(2), reg.MaxVersion)
+}
+
+func TestRequest(t *testing.T) {
+	kafkatest.TestRequest(t, 1, &deleteRecords.Request{
+		Topics: []deleteRecords.Topic{
+			{Name: "foo", Partitions: []deleteRecords.Partition{
+				{PartitionIndex: 0, Offset: 10},
+				{PartitionIndex: 1, Offset: -1},
+			}},
+		},
+		TimeoutMs: 30000,
+	})
+
+	kafkatest.TestRequest(t, 2, &deleteRecords.Request{
+		Topics: []deleteRecords.Topic{
+			{Name: "foo", Partitions: []deleteRecords.Partition{
+				{PartitionIndex: 0, Offset: 10},
+			}},
+		},
+		TimeoutMs: 30000,
+	})
+}
+
+func TestResponse(t *testing.T) {
+	kafkatest.TestResponse(t, 1, &deleteRecords.Response{
+		ThrottleTimeMs: 100,
+		Topics: []deleteRecords.ResponseTopic{
+			{Name: "foo", Partitions: []deleteRecords.ResponsePartition{
+				{PartitionIndex: 0, LowWatermark: 10, ErrorCode: kafka.None},
+				{PartitionIndex: 1, LowWatermark: -1, ErrorCode: kafka.OffsetOutOfRange},
+			}},
+		},
+	})
+
+	kafkatest.TestResponse(t, 2, &deleteRecords.Response{
+		ThrottleTimeMs: 100,
+		Topics: []deleteRecords.ResponseTopic{
+			{Name: "foo", Partitions: []deleteRecords.ResponsePartition{
+				{PartitionIndex: 0, LowWatermark: 10, ErrorCode: kafka.None},
+			}},
+		},
+	})
+}
@@ -0,0 +1,37 @@
+package deleteTopics
+
+import "mokapi/kafka"
+
+func init() {
+	kafka.Register(
+		kafka.ApiReg{
+			ApiKey:     kafka.DeleteTopics,
+			MinVersion: 0,
+			// version 6 identifies topics by their topic id
+			MaxVersion: 5,
+		},
+		&Request{},
+		&Response{},
+		4,
+		4,
+	)
+}
+
+type Request struct {
+	TopicNames []string         `kafka:"compact=4"`
+	TimeoutMs  int32            `kafka:""`
+	TagFields  map[int64]string `kafka:"type=TAG_BUFFER,min=4"`
+}
+
+type Response struct {
+	ThrottleTimeMs int32            `kafka:"min=1"`
+	Responses      []ResponseTopic  `kafka:"compact=4"`
+	TagFields      map[int64]string `kafka:"type=TAG_BUFFER,min=4"`
+}
+
+type ResponseTopic struct {
+	Name         string           `kafka:"compact=4"`
+	ErrorCode    kafka.ErrorCode  `kafka:""`
+	ErrorMessage string           `kafka:"min=5,compact=4,nullable"`
+	TagFields    map[int64]string `kafka:"type=TAG_BUFFER,min=4"`
+}
@@ -0,0 +1,68 @@
+package deleteTopics_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"mokapi/kafka"
+	"mokapi/kafka/deleteTopics"
+	"mokapi/kafka/kafkatest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	reg := kafka.ApiTypes[kafka.DeleteTopics]
+	require.Equal(t, int16(0), reg.MinVersion)
+	require.Equal(t, int16(5), reg.MaxVersion)
+}
+
+func TestRequest(t *testing.T) {
+	kafkatest.TestRequest(t, 3, &deleteTopics.Request{
+		TopicNames: []string{"foo", "bar"},
+		TimeoutMs:  30000,
+	})
+
+	kafkatest.TestRequest(t, 5, &deleteTopics.Request{
+		TopicNames: []string{"foo"},
+		TimeoutMs:  30000,
+	})
+
+	b := kafkatest.WriteRequest(t, 4, 123, "me", &deleteTopics.Request{
+		TopicNames: []string{"foo"},
+		TimeoutMs:  30000,
+	})
+	expected := new(bytes.Buffer)
+	// header
+	_ = binary.Write(expected, binary.BigEndian, int32(23))                 // length
+	_ = binary.Write(expected, binary.BigEndian, int16(kafka.DeleteTopics)) // ApiKey
+	_ = binary.Write(expected, binary.BigEndian, int16(4))                  // ApiVersion
+	_ = binary.Write(expected, binary.BigEndian, int32(123))                // correlationId
+	_ = binary.Write(expected, binary.BigEndian, int16(2))                  // ClientId length
+	_ = binary.Write(expected, binary.BigEndian, []byte("me"))              // ClientId
+	_ = binary.Write(expected, binary.BigEndian, int8(0))                   // tag buffer
+	// message
+	_ = binary.Write(expected, binary.BigEndian, int8(2))       // TopicNames length
+	_ = binary.Write(expected, binary.BigEndian, int8(4))       // Name length
+	_ = binary.Write(expected, binary.BigEndian, []byte("foo")) // Name
+	_ = binary.Write(expected, binary.BigEndian, int32(30000))  // TimeoutMs
+	_ = binary.Write(expected, binary.BigEndian, int8(0))       // tag buffer
+	require.Equal(t, expected.Bytes(), b)
+}
+
+func TestResponse(t *testing.T) {
+	kafkatest.TestResponse(t, 3, &deleteTopics.Response{
+		ThrottleTimeMs: 100,
+		Responses: []deleteTopics.ResponseTopic{
+			{Name: "foo", ErrorCode: kafka.None},
+			{Name: "bar", ErrorCode: kafka.UnknownTopicOrPartition},
+		},
+	})
+
+	kafkatest.TestResponse(t, 5, &deleteTopics.Response{
+		ThrottleTimeMs: 100,
+		Responses: []deleteTopics.ResponseTopic{
+			{Name: "bar", ErrorCode: kafka.UnknownTopicOrPartition, ErrorMessage: "unknown topic"},
+		},
+	})
+}
@@ -0,0 +1,95 @@
+package describeConfigs
+
+import "mokapi/kafka"
+
+func init() {
+	kafka.Register(
+		kafka.ApiReg{
+			ApiKey:     kafka.DescribeConfigs,
+			MinVersion: 0,
+			MaxVersion: 4,
+		},
+		&Request{},
+		&Response{},
+		4,
+		4,
+	)
+}
+
+type ResourceType int8
+
+const (
+	ResourceTopic  ResourceType = 2
+	ResourceBroker ResourceType = 4
+)
+
+type ConfigSource int8
+
+const (
+	SourceUnknown            ConfigSource = 0
+	SourceDynamicTopicConfig ConfigSource = 1
+	SourceStaticBrokerConfig ConfigSource = 4
+	SourceDefaultConfig      ConfigSource = 5
+)
+
+type ConfigType int8
+
+const (
+	TypeUnknown ConfigType = 0
+	TypeBoolean ConfigType = 1
+	TypeString  ConfigType = 2
+	TypeInt     ConfigType = 3
+	TypeLong    ConfigType = 5
+	TypeList    ConfigType = 7
+)
+
+type Request struct {
+	Resources            []Resource       `kafka:"compact=4"`
+	IncludeSynonyms      bool             `kafka:"min=1"`
+	IncludeDocumentation bool             `kafka:"min=3"`
+	TagFields            map[int64]string `kafka:"type=TAG_BUFFER,min=4"`
+}
+
+type Resource struct {
+	ResourceType ResourceType `kafka:""`
+	ResourceName string       `kafka:"compact=4"`
+	// ConfigurationKeys lists the configs to describe. All configs are
+	// described if the list is empty.
+	ConfigurationKeys []string         `kafka:"compact=4"`
+	TagFields         map[int64]string `kafka:"type=TAG_BUFFER,min=4"`
+}
+
+type Response struct {
+	ThrottleTimeMs int32            `kafka:""`
+	Results        []Result         `kafka:"compact=4"`
+	TagFields      map[int64]string `kafka:"type=TAG_BUFFER,min=4"`
+}
+
+type Result struct {
+	ErrorCode    kafka.ErrorCode  `kafka:""`
+	ErrorMessage string           `kafka:"compact=4,nullable"`
+	ResourceType ResourceType     `kafka:""`
+	ResourceName string           `kafka:"compact=4"`
+	Configs      []Config         `kafka:"compact=4"`
+	TagFields    map[int64]string `kafka:"type=TAG_BUFFER,min=4"`
+}
+
+type Config struct {
+	Name          string           `kafka:"compact=4"`
+	Value         string           `kafka:"compact=4,nullable"`
+	ReadOnly      bool             `kafka:""`
+	IsDefault     bool             `kafka:"max=0"`
+	ConfigSource  ConfigSource     `kafka:"min=1"`
+	IsSensitive   bool             `kafka:""`
+	Synonyms      []Synonym        `kafka:"min=1,compact=4"`
+	ConfigType    ConfigType       `kafka:"min=3"`
+	Documentation string           `kafka:"min=3,compact=4,nullable"`
+	TagFields     map[int64]string `kafka:"type=TAG_BUFFER,min=4"`
+}
+
+type Synonym struct {
+	Name      string           `kafka:"compact=4"`
+	Value     string           `kafka:"compact=4,nullable"`
+	Source    ConfigSource     `kafka:""`
+	TagFields map[int64]string `kafka:"type=TAG_BUFFER,min=4"`
+}
@@ -0,0 +1,77 @@
+package describeConfigs_test
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/describeConfigs"
+	"mokapi/kafka/kafkatest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	reg := kafka.ApiTypes[kafka.DescribeConfigs]
+	require.Equal(t, int16(0), reg.MinVersion)
+	require.Equal(t, int16(4), reg.MaxVersion)
+}
+
+func TestRequest(t *testing.T) {
+	kafkatest.TestRequest(t, 0, &describeConfigs.Request{
+		Resources: []describeConfigs.Resource{
+			{
+				ResourceType:      describeConfigs.ResourceTopic,
+				ResourceName:      "foo",
+				ConfigurationKeys: []string{"retention.ms"},
+			},
+		},
+	})
+
+	kafkatest.TestRequest(t, 4, &describeConfigs.Request{
+		Resources: []describeConfigs.Resource{
+			{
+				ResourceType:      describeConfigs.ResourceTopic,
+				ResourceName:      "foo",
+				ConfigurationKeys: []string{"retention.ms"},
+			},
+		},
+		IncludeSynonyms:      true,
+		IncludeDocumentation: true,
+	})
+}
+
+func TestResponse(t *testing.T) {
+	kafkatest.TestResponse(t, 0, &describeConfigs.Response{
+		ThrottleTimeMs: 100,
+		Results: []describeConfigs.Result{
+			{
+				ResourceType: describeConfigs.ResourceTopic,
+				ResourceName: "foo",
+				Configs: []describeConfigs.Config{
+					{Name: "retention.ms", Value: "1000", IsDefault: true},
+				},
+			},
+		},
+	})
+
+	kafkatest.TestResponse(t, 4, &describeConfigs.Response{
+		ThrottleTimeMs: 100,
+		Results: []describeConfigs.Result{
+			{
+				ResourceType: describeConfigs.ResourceTopic,
+				ResourceName: "foo",
+				Configs: []describeConfigs.Config{
+					{
+						Name:         "retention.ms",
+						Value:        "1000",
+						ConfigSource: describeConfigs.SourceDynamicTopicConfig,
+						Synonyms: []describeConfigs.Synonym{
+							{Name: "retention.ms", Value: "1000", Source: describeConfigs.SourceDynamicTopicConfig},
+						},
+						ConfigType:    describeConfigs.TypeLong,
+						Documentation: "foo",
+					},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,51 @@
+package describeGroups
+
+import (
+	"mokapi/kafka"
+)
+
+func init() {
+	kafka.Register(
+		kafka.ApiReg{
+			ApiKey:     kafka.DescribeGroups,
+			MinVersion: 0,
+			MaxVersion: 5},
+		&Request{},
+		&Response{},
+		5,
+		5,
+	)
+}
+
+type Request struct {
+	Groups                      []string         `kafka:"compact=5"`
+	IncludeAuthorizedOperations bool             `kafka:"min=3"`
+	TagFields                   map[int64]string `kafka:"type=TAG_BUFFER,min=5"`
+}
+
+type Response struct {
+	ThrottleTimeMs int32            `kafka:"min=1"`
+	Groups         []Group          `kafka:"compact=5"`
+	TagFields      map[int64]string `kafka:"type=TAG_BUFFER,min=5"`
+}
+
+type Group struct {
+	ErrorCode            kafka.ErrorCode  `kafka:""`
+	GroupId              string           `kafka:"compact=5"`
+	GroupState           string           `kafka:"compact=5"`
+	ProtocolType         string           `kafka:"compact=5"`
+	ProtocolData         string           `kafka:"compact=5"`
+	Members              []Member         `kafka:"compact=5"`
+	AuthorizedOperations int32            `kafka:"min=3"`
+	TagFields            map[int64]string `kafka:"type=TAG_BUFFER,min=5"`
+}
+
+type Member struct {
+	MemberId         string           `kafka:"compact=5"`
+	GroupInstanceId  string           `kafka:"min=4,compact=5,nullable"`
+	ClientId         string           `kafka:"compact=5"`
+	ClientHost       string           `kafka:"compact=5"`
+	MemberMetadata   []byte           `kafka:"compact=5"`
+	MemberAssignment []byte           `kafka:"compact=5"`
+	TagFields        map[int64]string `kafka:"type=TAG_BUFFER,min=5"`
+}
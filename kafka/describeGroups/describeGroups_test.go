@@ -0,0 +1,75 @@
+package describeGroups_test
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/describeGroups"
+	"mokapi/kafka/kafkatest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	reg := kafka.ApiTypes[kafka.DescribeGroups]
+	require.Equal(t, int16(0), reg.MinVersion)
+	require.Equal(t, int16(5), reg.MaxVersion)
+}
+
+func TestRequest(t *testing.T) {
+	kafkatest.TestRequest(t, 0, &describeGroups.Request{
+		Groups: []string{"foo", "bar"},
+	})
+
+	kafkatest.TestRequest(t, 3, &describeGroups.Request{
+		Groups:                      []string{"foo"},
+		IncludeAuthorizedOperations: true,
+	})
+
+	kafkatest.TestRequest(t, 5, &describeGroups.Request{
+		Groups: []string{"foo"},
+	})
+}
+
+func TestResponse(t *testing.T) {
+	kafkatest.TestResponse(t, 0, &describeGroups.Response{
+		Groups: []describeGroups.Group{
+			{
+				GroupId:      "foo",
+				GroupState:   "Stable",
+				ProtocolType: "consumer",
+				ProtocolData: "range",
+				Members: []describeGroups.Member{
+					{
+						MemberId:         "m1",
+						ClientId:         "client",
+						ClientHost:       "127.0.0.1",
+						MemberMetadata:   []byte{1, 2},
+						MemberAssignment: []byte{3, 4},
+					},
+				},
+			},
+		},
+	})
+
+	kafkatest.TestResponse(t, 4, &describeGroups.Response{
+		ThrottleTimeMs: 123,
+		Groups: []describeGroups.Group{
+			{
+				GroupId:              "foo",
+				GroupState:           "Stable",
+				Members:              []describeGroups.Member{{MemberId: "m1", GroupInstanceId: "g1"}},
+				AuthorizedOperations: -2147483648,
+			},
+		},
+	})
+
+	kafkatest.TestResponse(t, 5, &describeGroups.Response{
+		Groups: []describeGroups.Group{
+			{
+				ErrorCode:  kafka.GroupIdNotFound,
+				GroupId:    "foo",
+				GroupState: "Dead",
+			},
+		},
+	})
+}
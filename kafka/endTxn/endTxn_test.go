@@ -0,0 +1,61 @@
+package endTxn_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"mokapi/kafka"
+	"mokapi/kafka/endTxn"
+	"mokapi/kafka/kafkatest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	reg := kafka.ApiTypes[kafka.EndTxn]
+	require.Equal(t, int16(0), reg.MinVersion)
+	require.Equal(t, int16(3), reg.MaxVersion)
+}
+
+func TestRequest(t *testing.T) {
+	kafkatest.TestRequest(t, 2, &endTxn.Request{
+		TransactionalId: "trx",
+		ProducerId:      123,
+		ProducerEpoch:   1,
+		Committed:       true,
+	})
+
+	kafkatest.TestRequest(t, 3, &endTxn.Request{
+		TransactionalId: "trx",
+		ProducerId:      123,
+		ProducerEpoch:   1,
+		Committed:       false,
+	})
+}
+
+func TestResponse(t *testing.T) {
+	kafkatest.TestResponse(t, 2, &endTxn.Response{
+		ThrottleTimeMs: 100,
+		ErrorCode:      kafka.None,
+	})
+
+	kafkatest.TestResponse(t, 3, &endTxn.Response{
+		ThrottleTimeMs: 100,
+		ErrorCode:      kafka.ProducerFenced,
+	})
+
+	b := kafkatest.WriteResponse(t, 3, 123, &endTxn.Response{
+		ThrottleTimeMs: 100,
+		ErrorCode:      kafka.ProducerFenced,
+	})
+	expected := new(bytes.Buffer)
+	// header
+	_ = binary.Write(expected, binary.BigEndian, int32(12))  // length
+	_ = binary.Write(expected, binary.BigEndian, int32(123)) // correlationId
+	_ = binary.Write(expected, binary.BigEndian, int8(0))    // tag buffer
+	// message
+	_ = binary.Write(expected, binary.BigEndian, int32(100)) // ThrottleTimeMs
+	_ = binary.Write(expected, binary.BigEndian, int16(90))  // ErrorCode
+	_ = binary.Write(expected, binary.BigEndian, int8(0))    // tag buffer
+	require.Equal(t, expected.Bytes(), b)
+}
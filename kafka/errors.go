@@ -23,14 +23,25 @@ const (
 	InvalidGroupId              ErrorCode = 24
 	UnknownMemberId             ErrorCode = 25
 	RebalanceInProgress         ErrorCode = 27
+	UnsupportedSaslMechanism    ErrorCode = 33
+	IllegalSaslState            ErrorCode = 34
 	UnsupportedVersion          ErrorCode = 35
 	TopicAlreadyExists          ErrorCode = 36
+	InvalidPartitions           ErrorCode = 37
+	InvalidConfig               ErrorCode = 40
+	InvalidRequest              ErrorCode = 42
 	UnsupportedForMessageFormat ErrorCode = 43
 	OutOfOrderSequenceNumber    ErrorCode = 45
 	DuplicateSequenceNumber     ErrorCode = 46
 	InvalidProducerEpoch        ErrorCode = 47
+	InvalidTxnState             ErrorCode = 48
 	InvalidProducerIdMapping    ErrorCode = 49
+	InvalidTransactionTimeout   ErrorCode = 50
+	ConcurrentTransactions      ErrorCode = 51
+	OperationNotAttempted       ErrorCode = 55
+	SaslAuthenticationFailed    ErrorCode = 58
 	UnknownProducerId           ErrorCode = 59
+	NonEmptyGroup               ErrorCode = 68
 	GroupIdNotFound             ErrorCode = 69
 	MemberIdRequired            ErrorCode = 79
 	InvalidRecord               ErrorCode = 87
@@ -39,22 +50,35 @@ const (
 
 var (
 	errorCodeText = map[ErrorCode]string{
-		UnknownServerError:      "UNKNOWN_SERVER_ERROR",
-		None:                    "NONE",
-		OffsetOutOfRange:        "OFFSET_OUT_OF_RANGE",
-		CorruptMessage:          "CORRUPT_MESSAGE",
-		UnknownTopicOrPartition: "UNKNOWN_TOPIC_OR_PARTITION",
-		CoordinatorNotAvailable: "COORDINATOR_NOT_AVAILABLE",
-		NotCoordinator:          "NOT_COORDINATOR",
-		InvalidTopic:            "INVALID_TOPIC_EXCEPTION",
-		IllegalGeneration:       "ILLEGAL_GENERATION",
-		InvalidGroupId:          "INVALID_GROUP_ID",
-		UnknownMemberId:         "UNKNOWN_MEMBER_ID",
-		RebalanceInProgress:     "REBALANCE_IN_PROGRESS",
-		UnsupportedVersion:      "UNSUPPORTED_VERSION",
-		TopicAlreadyExists:      "TOPIC_ALREADY_EXISTS",
-		GroupIdNotFound:         "GROUP_ID_NOT_FOUND",
-		MemberIdRequired:        "MEMBER_ID_REQUIRED",
+		UnknownServerError:        "UNKNOWN_SERVER_ERROR",
+		None:                      "NONE",
+		OffsetOutOfRange:          "OFFSET_OUT_OF_RANGE",
+		CorruptMessage:            "CORRUPT_MESSAGE",
+		UnknownTopicOrPartition:   "UNKNOWN_TOPIC_OR_PARTITION",
+		CoordinatorNotAvailable:   "COORDINATOR_NOT_AVAILABLE",
+		NotCoordinator:            "NOT_COORDINATOR",
+		InvalidTopic:              "INVALID_TOPIC_EXCEPTION",
+		IllegalGeneration:         "ILLEGAL_GENERATION",
+		InvalidGroupId:            "INVALID_GROUP_ID",
+		UnknownMemberId:           "UNKNOWN_MEMBER_ID",
+		RebalanceInProgress:       "REBALANCE_IN_PROGRESS",
+		UnsupportedSaslMechanism:  "UNSUPPORTED_SASL_MECHANISM",
+		IllegalSaslState:          "ILLEGAL_SASL_STATE",
+		UnsupportedVersion:        "UNSUPPORTED_VERSION",
+		TopicAlreadyExists:        "TOPIC_ALREADY_EXISTS",
+		InvalidPartitions:         "INVALID_PARTITIONS",
+		InvalidConfig:             "INVALID_CONFIG",
+		InvalidRequest:            "INVALID_REQUEST",
+		InvalidProducerEpoch:      "INVALID_PRODUCER_EPOCH",
+		InvalidTxnState:           "INVALID_TXN_STATE",
+		InvalidProducerIdMapping:  "INVALID_PRODUCER_ID_MAPPING",
+		InvalidTransactionTimeout: "INVALID_TRANSACTION_TIMEOUT",
+		ConcurrentTransactions:    "CONCURRENT_TRANSACTIONS",
+		NonEmptyGroup:             "NON_EMPTY_GROUP",
+		SaslAuthenticationFailed:  "SASL_AUTHENTICATION_FAILED",
+		GroupIdNotFound:           "GROUP_ID_NOT_FOUND",
+		MemberIdRequired:          "MEMBER_ID_REQUIRED",
+		ProducerFenced:            "PRODUCER_FENCED",
 	}
 )
 
@@ -17,7 +17,10 @@ func init() {
 	)
 }
 
-const KeyTypeGroup = 0
+const (
+	KeyTypeGroup       = 0
+	KeyTypeTransaction = 1
+)
 
 type Request struct {
 	Key       string           `kafka:"compact=3"`
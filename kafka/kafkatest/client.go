@@ -1,19 +1,25 @@
 package kafkatest
 
 import (
+	"crypto/tls"
 	"fmt"
 	"mokapi/kafka"
 	"mokapi/kafka/apiVersion"
+	"mokapi/kafka/deleteGroups"
+	"mokapi/kafka/describeGroups"
 	"mokapi/kafka/fetch"
 	"mokapi/kafka/findCoordinator"
 	"mokapi/kafka/heartbeat"
 	"mokapi/kafka/joinGroup"
+	"mokapi/kafka/leaveGroup"
 	"mokapi/kafka/listgroup"
 	"mokapi/kafka/metaData"
 	"mokapi/kafka/offset"
 	"mokapi/kafka/offsetCommit"
 	"mokapi/kafka/offsetFetch"
 	"mokapi/kafka/produce"
+	"mokapi/kafka/saslAuthenticate"
+	"mokapi/kafka/saslHandshake"
 	"mokapi/kafka/syncGroup"
 	"net"
 	"reflect"
@@ -24,6 +30,8 @@ import (
 type Client struct {
 	Addr    string
 	Timeout time.Duration
+	// TLSConfig enables TLS when dialing the server
+	TLSConfig *tls.Config
 
 	conn          net.Conn
 	clientId      string
@@ -50,7 +58,11 @@ func (c *Client) Send(r *kafka.Request) (*kafka.Response, error) {
 	if c.conn == nil {
 		for i := 0; i < 10; i++ {
 			d := net.Dialer{Timeout: c.Timeout}
-			c.conn, err = d.Dial("tcp", c.Addr)
+			if c.TLSConfig != nil {
+				c.conn, err = tls.DialWithDialer(&d, "tcp", c.Addr, c.TLSConfig)
+			} else {
+				c.conn, err = d.Dial("tcp", c.Addr)
+			}
 			if err != nil {
 				time.Sleep(backoff)
 				continue
@@ -206,6 +218,61 @@ func (c *Client) Listgroup(version int16, r *listgroup.Request) (*listgroup.Resp
 	return nil, fmt.Errorf("unexpected response message: %T", res.Message)
 }
 
+func (c *Client) LeaveGroup(version int16, r *leaveGroup.Request) (*leaveGroup.Response, error) {
+	res, err := c.Send(NewRequest(c.clientId, version, r))
+	if err != nil {
+		return nil, err
+	}
+	if msg, ok := res.Message.(*leaveGroup.Response); ok {
+		return msg, nil
+	}
+	return nil, fmt.Errorf("unexpected response message: %T", res.Message)
+}
+
+func (c *Client) DescribeGroups(version int16, r *describeGroups.Request) (*describeGroups.Response, error) {
+	res, err := c.Send(NewRequest(c.clientId, version, r))
+	if err != nil {
+		return nil, err
+	}
+	if msg, ok := res.Message.(*describeGroups.Response); ok {
+		return msg, nil
+	}
+	return nil, fmt.Errorf("unexpected response message: %T", res.Message)
+}
+
+func (c *Client) DeleteGroups(version int16, r *deleteGroups.Request) (*deleteGroups.Response, error) {
+	res, err := c.Send(NewRequest(c.clientId, version, r))
+	if err != nil {
+		return nil, err
+	}
+	if msg, ok := res.Message.(*deleteGroups.Response); ok {
+		return msg, nil
+	}
+	return nil, fmt.Errorf("unexpected response message: %T", res.Message)
+}
+
+func (c *Client) SaslHandshake(version int16, r *saslHandshake.Request) (*saslHandshake.Response, error) {
+	res, err := c.Send(NewRequest(c.clientId, version, r))
+	if err != nil {
+		return nil, err
+	}
+	if msg, ok := res.Message.(*saslHandshake.Response); ok {
+		return msg, nil
+	}
+	return nil, fmt.Errorf("unexpected response message: %T", res.Message)
+}
+
+func (c *Client) SaslAuthenticate(version int16, r *saslAuthenticate.Request) (*saslAuthenticate.Response, error) {
+	res, err := c.Send(NewRequest(c.clientId, version, r))
+	if err != nil {
+		return nil, err
+	}
+	if msg, ok := res.Message.(*saslAuthenticate.Response); ok {
+		return msg, nil
+	}
+	return nil, fmt.Errorf("unexpected response message: %T", res.Message)
+}
+
 func (c *Client) JoinSyncGroup(member, group string, joinVersion, syncVersion int16) error {
 	join, err := c.JoinGroup(joinVersion, &joinGroup.Request{
 		GroupId:      group,
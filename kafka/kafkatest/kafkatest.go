@@ -5,20 +5,34 @@ import (
 	"fmt"
 	"io"
 	"mokapi/kafka"
+	"mokapi/kafka/addOffsetsToTxn"
+	"mokapi/kafka/addPartitionsToTxn"
+	"mokapi/kafka/alterConfigs"
 	"mokapi/kafka/apiVersion"
+	"mokapi/kafka/createPartitions"
 	"mokapi/kafka/createTopics"
+	"mokapi/kafka/deleteGroups"
+	"mokapi/kafka/deleteRecords"
+	"mokapi/kafka/deleteTopics"
+	"mokapi/kafka/describeConfigs"
+	"mokapi/kafka/describeGroups"
+	"mokapi/kafka/endTxn"
 	"mokapi/kafka/fetch"
 	"mokapi/kafka/findCoordinator"
 	"mokapi/kafka/heartbeat"
 	"mokapi/kafka/initProducerId"
 	"mokapi/kafka/joinGroup"
+	"mokapi/kafka/leaveGroup"
 	"mokapi/kafka/listgroup"
 	"mokapi/kafka/metaData"
 	"mokapi/kafka/offset"
 	"mokapi/kafka/offsetCommit"
 	"mokapi/kafka/offsetFetch"
 	"mokapi/kafka/produce"
+	"mokapi/kafka/saslAuthenticate"
+	"mokapi/kafka/saslHandshake"
 	"mokapi/kafka/syncGroup"
+	"mokapi/kafka/txnOffsetCommit"
 )
 
 func NewRequest(clientId string, version int16, msg kafka.Message) *kafka.Request {
@@ -74,6 +88,34 @@ func getApiKey(msg kafka.Message) kafka.ApiKey {
 		return kafka.CreateTopics
 	case *initProducerId.Request, *initProducerId.Response:
 		return kafka.InitProducerId
+	case *leaveGroup.Request, *leaveGroup.Response:
+		return kafka.LeaveGroup
+	case *describeGroups.Request, *describeGroups.Response:
+		return kafka.DescribeGroups
+	case *deleteGroups.Request, *deleteGroups.Response:
+		return kafka.DeleteGroups
+	case *saslHandshake.Request, *saslHandshake.Response:
+		return kafka.SaslHandshake
+	case *saslAuthenticate.Request, *saslAuthenticate.Response:
+		return kafka.SaslAuthenticate
+	case *addPartitionsToTxn.Request, *addPartitionsToTxn.Response:
+		return kafka.AddPartitionsToTxn
+	case *addOffsetsToTxn.Request, *addOffsetsToTxn.Response:
+		return kafka.AddOffsetsToTxn
+	case *endTxn.Request, *endTxn.Response:
+		return kafka.EndTxn
+	case *txnOffsetCommit.Request, *txnOffsetCommit.Response:
+		return kafka.TxnOffsetCommit
+	case *deleteTopics.Request, *deleteTopics.Response:
+		return kafka.DeleteTopics
+	case *deleteRecords.Request, *deleteRecords.Response:
+		return kafka.DeleteRecords
+	case *describeConfigs.Request, *describeConfigs.Response:
+		return kafka.DescribeConfigs
+	case *alterConfigs.Request, *alterConfigs.Response:
+		return kafka.AlterConfigs
+	case *createPartitions.Request, *createPartitions.Response:
+		return kafka.CreatePartitions
 	default:
 		panic(fmt.Sprintf("unknown type: %v", t))
 	}
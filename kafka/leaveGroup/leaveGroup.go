@@ -0,0 +1,46 @@
+package leaveGroup
+
+import (
+	"mokapi/kafka"
+)
+
+func init() {
+	kafka.Register(
+		kafka.ApiReg{
+			ApiKey:     kafka.LeaveGroup,
+			MinVersion: 0,
+			MaxVersion: 5},
+		&Request{},
+		&Response{},
+		4,
+		4,
+	)
+}
+
+type Request struct {
+	GroupId   string           `kafka:"compact=4"`
+	MemberId  string           `kafka:"max=2"`
+	Members   []Member         `kafka:"min=3,compact=4"`
+	TagFields map[int64]string `kafka:"type=TAG_BUFFER,min=4"`
+}
+
+type Member struct {
+	MemberId        string           `kafka:"compact=4"`
+	GroupInstanceId string           `kafka:"compact=4,nullable"`
+	Reason          string           `kafka:"min=5,compact=5,nullable"`
+	TagFields       map[int64]string `kafka:"type=TAG_BUFFER,min=4"`
+}
+
+type Response struct {
+	ThrottleTimeMs int32            `kafka:"min=1"`
+	ErrorCode      kafka.ErrorCode  `kafka:""`
+	Members        []MemberResponse `kafka:"min=3,compact=4"`
+	TagFields      map[int64]string `kafka:"type=TAG_BUFFER,min=4"`
+}
+
+type MemberResponse struct {
+	MemberId        string           `kafka:"compact=4"`
+	GroupInstanceId string           `kafka:"compact=4,nullable"`
+	ErrorCode       kafka.ErrorCode  `kafka:""`
+	TagFields       map[int64]string `kafka:"type=TAG_BUFFER,min=4"`
+}
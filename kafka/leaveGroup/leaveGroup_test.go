@@ -0,0 +1,79 @@
+package leaveGroup_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"mokapi/kafka"
+	"mokapi/kafka/kafkatest"
+	"mokapi/kafka/leaveGroup"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	reg := kafka.ApiTypes[kafka.LeaveGroup]
+	require.Equal(t, int16(0), reg.MinVersion)
+	require.Equal(t, int16(5), reg.MaxVersion)
+}
+
+func TestRequest(t *testing.T) {
+	kafkatest.TestRequest(t, 0, &leaveGroup.Request{
+		GroupId:  "foo",
+		MemberId: "m1",
+	})
+
+	kafkatest.TestRequest(t, 3, &leaveGroup.Request{
+		GroupId: "foo",
+		Members: []leaveGroup.Member{{MemberId: "m1", GroupInstanceId: "g1"}},
+	})
+
+	kafkatest.TestRequest(t, 5, &leaveGroup.Request{
+		GroupId: "foo",
+		Members: []leaveGroup.Member{{MemberId: "m1", Reason: "shutdown"}},
+	})
+
+	b := kafkatest.WriteRequest(t, 4, 123, "me", &leaveGroup.Request{
+		GroupId: "foo",
+		Members: []leaveGroup.Member{{MemberId: "m1"}},
+	})
+	expected := new(bytes.Buffer)
+	// header
+	_ = binary.Write(expected, binary.BigEndian, int32(24))               // length
+	_ = binary.Write(expected, binary.BigEndian, int16(kafka.LeaveGroup)) // ApiKey
+	_ = binary.Write(expected, binary.BigEndian, int16(4))                // ApiVersion
+	_ = binary.Write(expected, binary.BigEndian, int32(123))              // correlationId
+	_ = binary.Write(expected, binary.BigEndian, int16(2))                // ClientId length
+	_ = binary.Write(expected, binary.BigEndian, []byte("me"))            // ClientId
+	_ = binary.Write(expected, binary.BigEndian, int8(0))                 // tag buffer
+	// message
+	_ = binary.Write(expected, binary.BigEndian, int8(4))       // GroupId length
+	_ = binary.Write(expected, binary.BigEndian, []byte("foo")) // GroupId
+	_ = binary.Write(expected, binary.BigEndian, int8(2))       // Members length
+	_ = binary.Write(expected, binary.BigEndian, int8(3))       // MemberId length
+	_ = binary.Write(expected, binary.BigEndian, []byte("m1"))  // MemberId
+	_ = binary.Write(expected, binary.BigEndian, int8(0))       // GroupInstanceId null
+	_ = binary.Write(expected, binary.BigEndian, int8(0))       // Member tag buffer
+	_ = binary.Write(expected, binary.BigEndian, int8(0))       // tag buffer
+	require.Equal(t, expected.Bytes(), b)
+}
+
+func TestResponse(t *testing.T) {
+	kafkatest.TestResponse(t, 0, &leaveGroup.Response{
+		ErrorCode: kafka.UnknownMemberId,
+	})
+
+	kafkatest.TestResponse(t, 3, &leaveGroup.Response{
+		ThrottleTimeMs: 123,
+		Members: []leaveGroup.MemberResponse{
+			{MemberId: "m1", GroupInstanceId: "g1", ErrorCode: kafka.UnknownMemberId},
+		},
+	})
+
+	kafkatest.TestResponse(t, 5, &leaveGroup.Response{
+		ThrottleTimeMs: 123,
+		Members: []leaveGroup.MemberResponse{
+			{MemberId: "m1"},
+		},
+	})
+}
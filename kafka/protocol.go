@@ -11,36 +11,65 @@ import (
 type ApiKey int16
 
 const (
-	Produce         ApiKey = 0
-	Fetch           ApiKey = 1
-	ListOffsets     ApiKey = 2
-	Metadata        ApiKey = 3
-	OffsetCommit    ApiKey = 8
-	OffsetFetch     ApiKey = 9
-	FindCoordinator ApiKey = 10
-	JoinGroup       ApiKey = 11
-	Heartbeat       ApiKey = 12
-	SyncGroup       ApiKey = 14
-	ListGroup       ApiKey = 16
-	ApiVersions     ApiKey = 18
-	CreateTopics    ApiKey = 19
-	InitProducerId  ApiKey = 22
+	Produce            ApiKey = 0
+	Fetch              ApiKey = 1
+	ListOffsets        ApiKey = 2
+	Metadata           ApiKey = 3
+	OffsetCommit       ApiKey = 8
+	OffsetFetch        ApiKey = 9
+	FindCoordinator    ApiKey = 10
+	JoinGroup          ApiKey = 11
+	Heartbeat          ApiKey = 12
+	LeaveGroup         ApiKey = 13
+	SyncGroup          ApiKey = 14
+	DescribeGroups     ApiKey = 15
+	ListGroup          ApiKey = 16
+	SaslHandshake      ApiKey = 17
+	ApiVersions        ApiKey = 18
+	CreateTopics       ApiKey = 19
+	DeleteTopics       ApiKey = 20
+	DeleteRecords      ApiKey = 21
+	InitProducerId     ApiKey = 22
+	AddPartitionsToTxn ApiKey = 24
+	AddOffsetsToTxn    ApiKey = 25
+	EndTxn             ApiKey = 26
+	TxnOffsetCommit    ApiKey = 28
+	DescribeConfigs    ApiKey = 32
+	AlterConfigs       ApiKey = 33
+	SaslAuthenticate   ApiKey = 36
+	CreatePartitions   ApiKey = 37
+	DeleteGroups       ApiKey = 42
 )
 
 var apitext = map[ApiKey]string{
-	Produce:         "Produce",
-	Fetch:           "Fetch",
-	ListOffsets:     "ListOffsets",
-	Metadata:        "Metadata",
-	OffsetCommit:    "OffsetCommit",
-	OffsetFetch:     "OffsetFetch",
-	FindCoordinator: "FindCoordinator",
-	JoinGroup:       "JoinGroup",
-	Heartbeat:       "Heartbeat",
-	SyncGroup:       "SyncGroup",
-	ApiVersions:     "ApiVersions",
-	CreateTopics:    "CreateTopics",
-	InitProducerId:  "InitProducerId",
+	Produce:            "Produce",
+	Fetch:              "Fetch",
+	ListOffsets:        "ListOffsets",
+	Metadata:           "Metadata",
+	OffsetCommit:       "OffsetCommit",
+	OffsetFetch:        "OffsetFetch",
+	FindCoordinator:    "FindCoordinator",
+	JoinGroup:          "JoinGroup",
+	Heartbeat:          "Heartbeat",
+	LeaveGroup:         "LeaveGroup",
+	SyncGroup:          "SyncGroup",
+	DescribeGroups:     "DescribeGroups",
+	ListGroup:          "ListGroups",
+	SaslHandshake:      "SaslHandshake",
+	ApiVersions:        "ApiVersions",
+	CreateTopics:       "CreateTopics",
+	DeleteTopics:       "DeleteTopics",
+	DeleteRecords:      "DeleteRecords",
+	InitProducerId:     "InitProducerId",
+	AddPartitionsToTxn: "AddPartitionsToTxn",
+	AddOffsetsToTxn:    "AddOffsetsToTxn",
+	EndTxn:             "EndTxn",
+	TxnOffsetCommit:    "TxnOffsetCommit",
+	DescribeConfigs:    "DescribeConfigs",
+	AlterConfigs:       "AlterConfigs",
+	SaslAuthenticate:   "SaslAuthenticate",
+	CreatePartitions:   "CreatePartitions",
+	DeleteGroups:       "DeleteGroups",
 }
 
 var ApiTypes = map[ApiKey]ApiType{}
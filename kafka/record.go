@@ -9,14 +9,30 @@ import (
 	"math/bits"
 	"mokapi/buffer"
 	"time"
+
+	log "github.com/sirupsen/logrus"
 )
 
 const magicOffset = 16
 
+const (
+	transactionalFlag Attributes = 0x10
+	controlFlag       Attributes = 0x20
+)
+
 type Attributes int16
 
 type RecordBatch struct {
 	Records []*Record
+	// Compression is the codec used by the producer. When written, the
+	// records are compressed with this codec.
+	Compression Compression
+	// Transactional is true when the records were produced within a
+	// transaction. The producer of the first record is written to the
+	// batch header.
+	Transactional bool
+	// Control is true when the batch contains a transaction marker
+	Control bool
 }
 
 func NewRecordBatch() RecordBatch {
@@ -155,13 +171,20 @@ func (rb *RecordBatch) writeTo(e *Encoder) {
 	e.writeInt32(0)                      // leader epoch
 	e.writeInt8(2)                       // magic
 	e.writeInt32(0)                      // checksum: 17
-	e.writeInt16(int16(0))               // 21
+	e.writeInt16(int16(rb.attributes())) // 21
 	e.writeInt32(0)                      // last offset delta: 23
 	e.writeInt64(0)                      // first timestamp: 27
 	e.writeInt64(0)                      // max timestamp: 35
-	e.writeInt64(-1)                     // Producer Id
-	e.writeInt16(-1)                     // producer epoch
-	e.writeInt32(-1)                     // base sequence
+	// idempotent and transactional batches
+	if rb.Transactional || rb.Records[0].ProducerId > 0 {
+		e.writeInt64(rb.Records[0].ProducerId)
+		e.writeInt16(rb.Records[0].ProducerEpoch)
+		e.writeInt32(rb.Records[0].SequenceNumber)
+	} else {
+		e.writeInt64(-1) // Producer Id
+		e.writeInt16(-1) // producer epoch
+		e.writeInt32(-1) // base sequence
+	}
 	e.writeInt32(int32(len(rb.Records))) // num records
 
 	firstTime := rb.Records[0].Time
@@ -173,8 +196,17 @@ func (rb *RecordBatch) writeTo(e *Encoder) {
 	lastOffSetDetla := uint32(0)
 	baseOffset := rb.Records[0].Offset
 
-	// records must be sorted by time
-	for i, r := range rb.Records {
+	// records are written to a separate buffer when the batch is compressed
+	re := e
+	if rb.Compression != NoCompression {
+		tmp := buffer.NewPageBuffer()
+		defer tmp.Unref()
+		re = NewEncoder(tmp)
+	}
+
+	// records must be sorted by time. Offsets may have gaps, for example
+	// after log compaction.
+	for _, r := range rb.Records {
 		if r.Time.IsZero() {
 			r.Time = firstTime
 		}
@@ -184,22 +216,39 @@ func (rb *RecordBatch) writeTo(e *Encoder) {
 		}
 
 		deltaTimestamp := t - firstTimestamp
-		deltaOffset := int64(i)
-		lastOffSetDetla = uint32(i)
+		deltaOffset := r.Offset - baseOffset
+		lastOffSetDetla = uint32(deltaOffset)
 
-		e.writeVarInt(int64(r.Size(baseOffset, firstTime)))
-		e.writeInt8(0) // attributes
-		e.writeVarInt(deltaTimestamp)
-		e.writeVarInt(deltaOffset)
+		re.writeVarInt(int64(r.Size(baseOffset, firstTime)))
+		re.writeInt8(0) // attributes
+		re.writeVarInt(deltaTimestamp)
+		re.writeVarInt(deltaOffset)
 
-		e.writeVarNullBytesFrom(r.Key)
-		e.writeVarNullBytesFrom(r.Value)
+		re.writeVarNullBytesFrom(r.Key)
+		re.writeVarNullBytesFrom(r.Value)
 
-		e.writeVarInt(int64(len(r.Headers)))
+		re.writeVarInt(int64(len(r.Headers)))
 		for _, h := range r.Headers {
-			e.writeVarString(h.Key)
-			e.writeVarNullBytes(h.Value)
+			re.writeVarString(h.Key)
+			re.writeVarNullBytes(h.Value)
+		}
+	}
+
+	if re != e {
+		raw := make([]byte, 0, re.writer.Size())
+		re.writer.Scan(0, re.writer.Size(), func(chunk []byte) bool {
+			raw = append(raw, chunk...)
+			return true
+		})
+		compressed, err := rb.Compression.compress(raw)
+		if err != nil {
+			log.Errorf("kafka: compress record batch using %v failed, sending uncompressed: %v", rb.Compression, err)
+			compressed = raw
+		} else {
+			binary.BigEndian.PutUint16(b[:2], uint16(rb.attributes()|Attributes(rb.Compression)))
+			e.writer.WriteAt(b[:2], offset+21)
 		}
+		_, _ = e.Write(compressed)
 	}
 
 	binary.BigEndian.PutUint32(b[:4], lastOffSetDetla)
@@ -228,6 +277,19 @@ func (rb *RecordBatch) writeTo(e *Encoder) {
 	e.writer.WriteAt(b[:4], offset+17)
 }
 
+// attributes returns the batch attributes without compression which is
+// only set when the records were compressed successfully.
+func (rb *RecordBatch) attributes() Attributes {
+	var a Attributes
+	if rb.Transactional {
+		a |= transactionalFlag
+	}
+	if rb.Control {
+		a |= controlFlag
+	}
+	return a
+}
+
 func sizeVarInt(x int64) int {
 	// Kafka-compatible ZigZag
 	i := uint64((x << 1) ^ (x >> 63))
@@ -237,3 +299,25 @@ func sizeVarInt(x int64) int {
 func (r *Record) String() string {
 	return ""
 }
+
+// NewTransactionMarker returns a control record which commits or aborts the
+// transaction of the given producer.
+func NewTransactionMarker(producerId int64, producerEpoch int16, commit bool) *Record {
+	markerType := int16(0) // abort
+	if commit {
+		markerType = 1
+	}
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint16(key[0:2], 0) // version
+	binary.BigEndian.PutUint16(key[2:4], uint16(markerType))
+	// version and coordinator epoch
+	value := make([]byte, 6)
+
+	return &Record{
+		Key:            NewBytes(key),
+		Value:          NewBytes(value),
+		ProducerId:     producerId,
+		ProducerEpoch:  producerEpoch,
+		SequenceNumber: -1,
+	}
+}
@@ -0,0 +1,73 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"mokapi/buffer"
+)
+
+// logHeaderSize is the size of the base offset and the batch length
+// which precede each record batch in a log segment
+const logHeaderSize = 12
+
+var ErrCorruptLog = errors.New("corrupt record batch")
+
+// WriteLog writes the batch in the format of a Kafka log segment, in which
+// record batches are stored one after another without a size prefix.
+func (rb *RecordBatch) WriteLog(w io.Writer) (int, error) {
+	if len(rb.Records) == 0 {
+		return 0, nil
+	}
+
+	b := buffer.NewPageBuffer()
+	defer b.Unref()
+
+	rb.writeTo(NewEncoder(b))
+	return b.WriteTo(w)
+}
+
+// ReadLog reads the next record batch of a log segment written by WriteLog.
+// It returns io.EOF at the end of the log and io.ErrUnexpectedEOF if the
+// last batch was not written completely.
+func ReadLog(r io.Reader) (RecordBatch, int, error) {
+	rb := NewRecordBatch()
+
+	header := make([]byte, logHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return rb, 0, err
+	}
+	length := int(int32(binary.BigEndian.Uint32(header[8:])))
+	if length < batchHeaderSize {
+		return rb, 0, fmt.Errorf("%w: invalid length %v", ErrCorruptLog, length)
+	}
+
+	b := make([]byte, logHeaderSize+length)
+	copy(b, header)
+	if _, err := io.ReadFull(r, b[logHeaderSize:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return rb, 0, err
+	}
+
+	if magic := b[magicOffset]; magic != 2 {
+		return rb, 0, fmt.Errorf("%w: unsupported record version %v", ErrCorruptLog, magic)
+	}
+	checksum := binary.BigEndian.Uint32(b[17:21])
+	if crc32.Checksum(b[21:], crc32.MakeTable(crc32.Castagnoli)) != checksum {
+		return rb, 0, fmt.Errorf("%w: checksum mismatch", ErrCorruptLog)
+	}
+
+	d := NewDecoder(bytes.NewReader(b), len(b))
+	if err := rb.readFromV2(d); err != nil {
+		return rb, 0, err
+	}
+	if d.err != nil {
+		return rb, 0, fmt.Errorf("%w: %v", ErrCorruptLog, d.err)
+	}
+	return rb, len(b), nil
+}
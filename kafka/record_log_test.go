@@ -0,0 +1,103 @@
+package kafka
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordBatch_WriteLog(t *testing.T) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	var log bytes.Buffer
+
+	batches := []RecordBatch{
+		{
+			Records: []*Record{
+				{Offset: 0, Time: now, Key: NewBytes([]byte("foo")), Value: NewBytes([]byte("bar"))},
+				{Offset: 2, Time: now, Key: NewBytes([]byte("foo")), Headers: []RecordHeader{{Key: "h", Value: []byte("v")}}},
+			},
+		},
+		{
+			Records:     []*Record{{Offset: 3, Time: now, Value: NewBytes([]byte("compressed"))}},
+			Compression: Gzip,
+		},
+		{
+			Records:       []*Record{{Offset: 4, Time: now, ProducerId: 7, ProducerEpoch: 1, SequenceNumber: 5}},
+			Transactional: true,
+		},
+		{
+			Records:       []*Record{NewTransactionMarker(7, 1, true)},
+			Transactional: true,
+			Control:       true,
+		},
+		{
+			Records: []*Record{{Offset: 6, Time: now, ProducerId: 8, SequenceNumber: 2}},
+		},
+	}
+	batches[3].Records[0].Offset = 5
+	batches[3].Records[0].Time = now
+
+	for _, rb := range batches {
+		_, err := rb.WriteLog(&log)
+		require.NoError(t, err)
+	}
+
+	r := bytes.NewReader(log.Bytes())
+
+	rb, _, err := ReadLog(r)
+	require.NoError(t, err)
+	require.Len(t, rb.Records, 2)
+	require.Equal(t, int64(0), rb.Records[0].Offset)
+	require.Equal(t, now, rb.Records[0].Time)
+	require.Equal(t, "foo", string(Read(rb.Records[0].Key)))
+	require.Equal(t, "bar", string(Read(rb.Records[0].Value)))
+	require.Equal(t, int64(2), rb.Records[1].Offset)
+	require.Nil(t, rb.Records[1].Value)
+	require.Equal(t, []RecordHeader{{Key: "h", Value: []byte("v")}}, rb.Records[1].Headers)
+
+	rb, _, err = ReadLog(r)
+	require.NoError(t, err)
+	require.Equal(t, Gzip, rb.Compression)
+	require.Equal(t, "compressed", string(Read(rb.Records[0].Value)))
+
+	rb, _, err = ReadLog(r)
+	require.NoError(t, err)
+	require.True(t, rb.Transactional)
+	require.False(t, rb.Control)
+	require.Equal(t, int64(7), rb.Records[0].ProducerId)
+	require.Equal(t, int16(1), rb.Records[0].ProducerEpoch)
+	require.Equal(t, int32(5), rb.Records[0].SequenceNumber)
+
+	rb, _, err = ReadLog(r)
+	require.NoError(t, err)
+	require.True(t, rb.Control)
+	require.Equal(t, int64(5), rb.Records[0].Offset)
+
+	rb, _, err = ReadLog(r)
+	require.NoError(t, err)
+	require.False(t, rb.Transactional)
+	require.Equal(t, int64(8), rb.Records[0].ProducerId)
+	require.Equal(t, int32(2), rb.Records[0].SequenceNumber)
+
+	_, _, err = ReadLog(r)
+	require.Equal(t, io.EOF, err)
+}
+
+func TestReadLog_Corrupt(t *testing.T) {
+	rb := RecordBatch{Records: []*Record{{Offset: 0, Time: time.Now(), Value: NewBytes([]byte("foo"))}}}
+	var log bytes.Buffer
+	n, err := rb.WriteLog(&log)
+	require.NoError(t, err)
+
+	b := log.Bytes()
+
+	_, _, err = ReadLog(bytes.NewReader(b[:n-2]))
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+
+	b[n-1] ^= 0xff
+	_, _, err = ReadLog(bytes.NewReader(b))
+	require.ErrorIs(t, err, ErrCorruptLog)
+}
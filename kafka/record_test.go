@@ -3,6 +3,7 @@ package kafka
 import (
 	"bufio"
 	"bytes"
+	"io"
 	"mokapi/buffer"
 	"strings"
 	"testing"
@@ -399,3 +400,96 @@ func TestSize_LongValue(t *testing.T) {
 
 	require.Equal(t, 150, records.Size())
 }
+
+func TestRecordBatch_Transactional(t *testing.T) {
+	testcases := []struct {
+		name  string
+		batch RecordBatch
+		test  func(t *testing.T, header []byte, result RecordBatch)
+	}{
+		{
+			name: "transactional",
+			batch: RecordBatch{
+				Transactional: true,
+				Records: []*Record{
+					{
+						Offset:         3,
+						Time:           time.Now(),
+						Value:          NewBytes([]byte("foo")),
+						ProducerId:     12,
+						ProducerEpoch:  2,
+						SequenceNumber: 5,
+					},
+				},
+			},
+			test: func(t *testing.T, header []byte, result RecordBatch) {
+				require.Equal(t, []byte{0, 0x10}, header[21:23], "attributes")
+				require.True(t, result.Transactional)
+				require.False(t, result.Control)
+				require.Equal(t, int64(12), result.Records[0].ProducerId)
+				require.Equal(t, int16(2), result.Records[0].ProducerEpoch)
+				require.Equal(t, int32(5), result.Records[0].SequenceNumber)
+			},
+		},
+		{
+			name: "commit marker",
+			batch: RecordBatch{
+				Transactional: true,
+				Control:       true,
+				Records:       []*Record{NewTransactionMarker(12, 2, true)},
+			},
+			test: func(t *testing.T, header []byte, result RecordBatch) {
+				require.Equal(t, []byte{0, 0x30}, header[21:23], "attributes")
+				require.True(t, result.Transactional)
+				require.True(t, result.Control)
+				require.Equal(t, int64(12), result.Records[0].ProducerId)
+				key, _ := io.ReadAll(result.Records[0].Key)
+				require.Equal(t, []byte{0, 0, 0, 1}, key)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			pb := buffer.NewPageBuffer()
+			tc.batch.WriteTo(NewEncoder(pb), 0, kafkaTag{})
+			var buf bytes.Buffer
+			_, err := pb.WriteTo(&buf)
+			require.NoError(t, err)
+
+			result := RecordBatch{}
+			err = result.ReadFrom(NewDecoder(bytes.NewReader(buf.Bytes()), buf.Len()), 0, kafkaTag{})
+			require.NoError(t, err)
+			require.Len(t, result.Records, 1)
+
+			// skip the length of the record set
+			tc.test(t, buf.Bytes()[4:], result)
+		})
+	}
+}
+
+func TestRecordBatch_OffsetGap(t *testing.T) {
+	now := time.Now()
+	batch := RecordBatch{
+		Records: []*Record{
+			{Offset: 5, Time: now, Key: NewBytes([]byte("a")), Value: NewBytes([]byte("foo"))},
+			{Offset: 8, Time: now, Key: NewBytes([]byte("b")), Value: NewBytes([]byte("bar"))},
+		},
+	}
+
+	pb := buffer.NewPageBuffer()
+	batch.WriteTo(NewEncoder(pb), 0, kafkaTag{})
+	var buf bytes.Buffer
+	_, err := pb.WriteTo(&buf)
+	require.NoError(t, err)
+
+	// skip the length of the record set; last offset delta is at byte 23
+	require.Equal(t, []byte{0, 0, 0, 3}, buf.Bytes()[4+23:4+27], "last offset delta")
+
+	result := RecordBatch{}
+	err = result.ReadFrom(NewDecoder(bytes.NewReader(buf.Bytes()), buf.Len()), 0, kafkaTag{})
+	require.NoError(t, err)
+	require.Len(t, result.Records, 2)
+	require.Equal(t, int64(5), result.Records[0].Offset)
+	require.Equal(t, int64(8), result.Records[1].Offset)
+}
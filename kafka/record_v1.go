@@ -1,6 +1,7 @@
 package kafka
 
 import (
+	"bytes"
 	"fmt"
 	"mokapi/buffer"
 )
@@ -16,15 +17,24 @@ func (rb *RecordBatch) readFromV1(d *Decoder) error {
 		crc := d.ReadInt32()
 		magic := d.ReadInt8()
 		attributes := Attributes(d.ReadInt8())
-		timestamp := d.ReadInt64()
-		r.Time = ToTime(timestamp)
+		if magic > 0 {
+			r.Time = ToTime(d.ReadInt64())
+		}
 
 		_ = size
 		_ = crc
-		_ = magic
 
-		if attributes.Compression() != 0 {
-			return fmt.Errorf("compression currently not supported")
+		if c := attributes.Compression(); c != NoCompression {
+			// the value of a wrapper message contains the compressed inner messages
+			d.ReadBytes() // key
+			value := d.ReadBytes()
+			if d.err != nil {
+				return d.err
+			}
+			if err := rb.readCompressedV1(c, value, magic, r.Offset); err != nil {
+				return err
+			}
+			continue
 		}
 
 		keyOffset := pb.Size()
@@ -46,3 +56,28 @@ func (rb *RecordBatch) readFromV1(d *Decoder) error {
 
 	return nil
 }
+
+func (rb *RecordBatch) readCompressedV1(c Compression, value []byte, magic int8, wrapperOffset int64) error {
+	data, err := c.decompress(value)
+	if err != nil {
+		return fmt.Errorf("decompress message set using %v failed: %w", c, err)
+	}
+
+	inner := &RecordBatch{}
+	if err = inner.readFromV1(NewDecoder(bytes.NewReader(data), len(data))); err != nil {
+		return err
+	}
+
+	// since magic v1, inner offsets are relative and the wrapper
+	// offset is the offset of the last inner message
+	if magic > 0 && len(inner.Records) > 0 {
+		base := wrapperOffset - inner.Records[len(inner.Records)-1].Offset
+		for _, r := range inner.Records {
+			r.Offset += base
+		}
+	}
+
+	rb.Compression = c
+	rb.Records = append(rb.Records, inner.Records...)
+	return nil
+}
@@ -1,17 +1,21 @@
 package kafka
 
 import (
+	"bytes"
 	"fmt"
 	"mokapi/buffer"
 )
 
+// size of the batch header following the batch length field
+const batchHeaderSize = 49
+
 func (rb *RecordBatch) readFromV2(d *Decoder) error {
 	// partition base offset of following records
 	baseOffset := d.ReadInt64()
-	d.ReadInt32() // message size
-	d.ReadInt32() // leader epoch
-	d.ReadInt8()  // magic byte version
-	d.ReadInt32() // checksum
+	batchLength := d.ReadInt32() // message size
+	d.ReadInt32()                // leader epoch
+	d.ReadInt8()                 // magic byte version
+	d.ReadInt32()                // checksum
 	attributes := Attributes(d.ReadInt16())
 	d.ReadInt32() // lastOffsetDelta
 	firstTimestamp := d.ReadInt64()
@@ -21,8 +25,23 @@ func (rb *RecordBatch) readFromV2(d *Decoder) error {
 	sequence := d.ReadInt32()      // baseSequence
 	numRecords := d.ReadInt32()
 
-	if attributes.Compression() != 0 {
-		return fmt.Errorf("compression currently not supported")
+	rb.Compression = attributes.Compression()
+	rb.Transactional = attributes.IsTransactional()
+	rb.Control = attributes.IsControl()
+	if rb.Compression != NoCompression {
+		n := int(batchLength) - batchHeaderSize
+		if n < 0 {
+			return fmt.Errorf("invalid record batch length %v", batchLength)
+		}
+		compressed := make([]byte, n)
+		if !d.ReadFull(compressed) {
+			return d.err
+		}
+		data, err := rb.Compression.decompress(compressed)
+		if err != nil {
+			return fmt.Errorf("decompress record batch using %v failed: %w", rb.Compression, err)
+		}
+		d = NewDecoder(bytes.NewReader(data), len(data))
 	}
 
 	pb := buffer.NewPageBuffer()
@@ -0,0 +1,31 @@
+package saslAuthenticate
+
+import (
+	"mokapi/kafka"
+)
+
+func init() {
+	kafka.Register(
+		kafka.ApiReg{
+			ApiKey:     kafka.SaslAuthenticate,
+			MinVersion: 0,
+			MaxVersion: 2},
+		&Request{},
+		&Response{},
+		2,
+		2,
+	)
+}
+
+type Request struct {
+	AuthBytes []byte           `kafka:"compact=2"`
+	TagFields map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
+
+type Response struct {
+	ErrorCode         kafka.ErrorCode  `kafka:""`
+	ErrorMessage      string           `kafka:"compact=2,nullable"`
+	AuthBytes         []byte           `kafka:"compact=2"`
+	SessionLifetimeMs int64            `kafka:"min=1"`
+	TagFields         map[int64]string `kafka:"type=TAG_BUFFER,min=2"`
+}
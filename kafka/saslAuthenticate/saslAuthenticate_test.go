@@ -0,0 +1,43 @@
+package saslAuthenticate_test
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/kafkatest"
+	"mokapi/kafka/saslAuthenticate"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	reg := kafka.ApiTypes[kafka.SaslAuthenticate]
+	require.Equal(t, int16(0), reg.MinVersion)
+	require.Equal(t, int16(2), reg.MaxVersion)
+}
+
+func TestRequest(t *testing.T) {
+	kafkatest.TestRequest(t, 0, &saslAuthenticate.Request{
+		AuthBytes: []byte("\x00foo\x00bar"),
+	})
+
+	kafkatest.TestRequest(t, 2, &saslAuthenticate.Request{
+		AuthBytes: []byte("n,,n=foo,r=abc"),
+	})
+}
+
+func TestResponse(t *testing.T) {
+	kafkatest.TestResponse(t, 0, &saslAuthenticate.Response{
+		AuthBytes: []byte{},
+	})
+
+	kafkatest.TestResponse(t, 1, &saslAuthenticate.Response{
+		ErrorCode:         kafka.SaslAuthenticationFailed,
+		ErrorMessage:      "invalid username or password",
+		AuthBytes:         []byte{},
+		SessionLifetimeMs: 1000,
+	})
+
+	kafkatest.TestResponse(t, 2, &saslAuthenticate.Response{
+		AuthBytes: []byte("r=abc,s=c2FsdA==,i=4096"),
+	})
+}
@@ -0,0 +1,29 @@
+package saslHandshake
+
+import (
+	"mokapi/kafka"
+)
+
+func init() {
+	kafka.Register(
+		kafka.ApiReg{
+			ApiKey: kafka.SaslHandshake,
+			// v0 sends raw SASL tokens without SaslAuthenticate framing,
+			// which the broker does not support
+			MinVersion: 1,
+			MaxVersion: 1},
+		&Request{},
+		&Response{},
+		2,
+		2,
+	)
+}
+
+type Request struct {
+	Mechanism string `kafka:""`
+}
+
+type Response struct {
+	ErrorCode  kafka.ErrorCode `kafka:""`
+	Mechanisms []string        `kafka:""`
+}
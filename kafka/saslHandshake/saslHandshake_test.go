@@ -0,0 +1,33 @@
+package saslHandshake_test
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/kafkatest"
+	"mokapi/kafka/saslHandshake"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	reg := kafka.ApiTypes[kafka.SaslHandshake]
+	require.Equal(t, int16(1), reg.MinVersion)
+	require.Equal(t, int16(1), reg.MaxVersion)
+}
+
+func TestRequest(t *testing.T) {
+	kafkatest.TestRequest(t, 1, &saslHandshake.Request{
+		Mechanism: "SCRAM-SHA-256",
+	})
+}
+
+func TestResponse(t *testing.T) {
+	kafkatest.TestResponse(t, 1, &saslHandshake.Response{
+		Mechanisms: []string{"PLAIN"},
+	})
+
+	kafkatest.TestResponse(t, 1, &saslHandshake.Response{
+		ErrorCode:  kafka.UnsupportedSaslMechanism,
+		Mechanisms: []string{"SCRAM-SHA-256", "SCRAM-SHA-512"},
+	})
+}
@@ -2,6 +2,7 @@ package kafka
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"io"
 	"mokapi/safe"
@@ -38,8 +39,9 @@ type response struct {
 }
 
 type Server struct {
-	Addr    string
-	Handler Handler
+	Addr      string
+	Handler   Handler
+	TLSConfig *tls.Config
 
 	mu         sync.Mutex
 	closeChan  chan bool
@@ -77,6 +79,10 @@ func (s *Server) Serve(l net.Listener) error {
 			}
 		}
 
+		if s.TLSConfig != nil {
+			conn = tls.Server(conn, s.TLSConfig)
+		}
+
 		ctx := s.trackConn(conn)
 		go s.serve(conn, ctx)
 	}
@@ -108,6 +114,7 @@ func (s *Server) Close() {
 
 func (s *Server) serve(conn net.Conn, ctx context.Context) {
 	ctx, cancel := context.WithCancel(ctx)
+	ClientFromContext(ctx).disconnect = cancel
 	defer func() {
 		r := recover()
 		if r != nil {
@@ -146,6 +153,10 @@ func (s *Server) serve(conn net.Conn, ctx context.Context) {
 			}()
 			s.handleMessage(&response{writer: conn, header: r.Header, ctx: ctx}, r)
 		}()
+
+		if ctx.Err() != nil {
+			return
+		}
 	}
 }
 
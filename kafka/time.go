@@ -16,8 +16,19 @@ func Timestamp(t time.Time) int64 {
 	return t.UnixNano() / int64(time.Millisecond)
 }
 
-func (a Attributes) Compression() int8 {
-	return int8(a & 7)
+func (a Attributes) Compression() Compression {
+	return Compression(a & 7)
+}
+
+// IsTransactional reports whether the batch is part of a transaction
+func (a Attributes) IsTransactional() bool {
+	return a&transactionalFlag != 0
+}
+
+// IsControl reports whether the batch contains a control record like a
+// transaction marker
+func (a Attributes) IsControl() bool {
+	return a&controlFlag != 0
 }
 
 func ToTime(i int64) time.Time {
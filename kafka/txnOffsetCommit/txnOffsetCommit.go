@@ -0,0 +1,61 @@
+package txnOffsetCommit
+
+import "mokapi/kafka"
+
+func init() {
+	kafka.Register(
+		kafka.ApiReg{
+			ApiKey:     kafka.TxnOffsetCommit,
+			MinVersion: 0,
+			MaxVersion: 3,
+		},
+		&Request{},
+		&Response{},
+		3,
+		3,
+	)
+}
+
+type Request struct {
+	TransactionalId string           `kafka:"compact=3"`
+	GroupId         string           `kafka:"compact=3"`
+	ProducerId      int64            `kafka:""`
+	ProducerEpoch   int16            `kafka:""`
+	GenerationId    int32            `kafka:"min=3"`
+	MemberId        string           `kafka:"min=3,compact=3"`
+	GroupInstanceId string           `kafka:"min=3,compact=3,nullable"`
+	Topics          []Topic          `kafka:"compact=3"`
+	TagFields       map[int64]string `kafka:"type=TAG_BUFFER,min=3"`
+}
+
+type Topic struct {
+	Name       string           `kafka:"compact=3"`
+	Partitions []Partition      `kafka:"compact=3"`
+	TagFields  map[int64]string `kafka:"type=TAG_BUFFER,min=3"`
+}
+
+type Partition struct {
+	Index                int32            `kafka:""`
+	CommittedOffset      int64            `kafka:""`
+	CommittedLeaderEpoch int32            `kafka:"min=2"`
+	CommittedMetadata    string           `kafka:"compact=3,nullable"`
+	TagFields            map[int64]string `kafka:"type=TAG_BUFFER,min=3"`
+}
+
+type Response struct {
+	ThrottleTimeMs int32            `kafka:""`
+	Topics         []ResponseTopic  `kafka:"compact=3"`
+	TagFields      map[int64]string `kafka:"type=TAG_BUFFER,min=3"`
+}
+
+type ResponseTopic struct {
+	Name       string              `kafka:"compact=3"`
+	Partitions []ResponsePartition `kafka:"compact=3"`
+	TagFields  map[int64]string    `kafka:"type=TAG_BUFFER,min=3"`
+}
+
+type ResponsePartition struct {
+	Index     int32            `kafka:""`
+	ErrorCode kafka.ErrorCode  `kafka:""`
+	TagFields map[int64]string `kafka:"type=TAG_BUFFER,min=3"`
+}
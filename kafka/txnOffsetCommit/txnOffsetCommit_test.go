@@ -0,0 +1,65 @@
+package txnOffsetCommit_test
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/kafkatest"
+	"mokapi/kafka/txnOffsetCommit"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	reg := kafka.ApiTypes[kafka.TxnOffsetCommit]
+	require.Equal(t, int16(0), reg.MinVersion)
+	require.Equal(t, int16(3), reg.MaxVersion)
+}
+
+func TestRequest(t *testing.T) {
+	kafkatest.TestRequest(t, 2, &txnOffsetCommit.Request{
+		TransactionalId: "trx",
+		GroupId:         "foo",
+		ProducerId:      123,
+		ProducerEpoch:   1,
+		Topics: []txnOffsetCommit.Topic{
+			{Name: "bar", Partitions: []txnOffsetCommit.Partition{
+				{Index: 0, CommittedOffset: 10, CommittedLeaderEpoch: -1, CommittedMetadata: "meta"},
+			}},
+		},
+	})
+
+	kafkatest.TestRequest(t, 3, &txnOffsetCommit.Request{
+		TransactionalId: "trx",
+		GroupId:         "foo",
+		ProducerId:      123,
+		ProducerEpoch:   1,
+		GenerationId:    2,
+		MemberId:        "m1",
+		GroupInstanceId: "g1",
+		Topics: []txnOffsetCommit.Topic{
+			{Name: "bar", Partitions: []txnOffsetCommit.Partition{
+				{Index: 0, CommittedOffset: 10, CommittedLeaderEpoch: -1, CommittedMetadata: "meta"},
+			}},
+		},
+	})
+}
+
+func TestResponse(t *testing.T) {
+	kafkatest.TestResponse(t, 2, &txnOffsetCommit.Response{
+		ThrottleTimeMs: 100,
+		Topics: []txnOffsetCommit.ResponseTopic{
+			{Name: "bar", Partitions: []txnOffsetCommit.ResponsePartition{
+				{Index: 0, ErrorCode: kafka.None},
+			}},
+		},
+	})
+
+	kafkatest.TestResponse(t, 3, &txnOffsetCommit.Response{
+		ThrottleTimeMs: 100,
+		Topics: []txnOffsetCommit.ResponseTopic{
+			{Name: "bar", Partitions: []txnOffsetCommit.ResponsePartition{
+				{Index: 0, ErrorCode: kafka.InvalidTxnState},
+			}},
+		},
+	})
+}
@@ -1,7 +1,9 @@
 package ldap
 
 import (
+	"context"
 	"fmt"
+
 	ber "gopkg.in/go-asn1-ber/asn1-ber.v1"
 )
 
@@ -62,3 +64,23 @@ func (r *BindResponse) toPacket() *ber.Packet {
 	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, r.Message, "errorMessage: "))
 	return p
 }
+
+const authContextKey = "AuthContext"
+
+// AuthContext holds the identity the connection is bound to.
+// An empty Dn is an anonymous bind.
+type AuthContext struct {
+	Dn string
+}
+
+func AuthFromContext(ctx context.Context) *AuthContext {
+	if ctx == nil {
+		return nil
+	}
+	a, _ := ctx.Value(authContextKey).(*AuthContext)
+	return a
+}
+
+func NewAuthContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, authContextKey, &AuthContext{})
+}
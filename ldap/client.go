@@ -1,6 +1,7 @@
 package ldap
 
 import (
+	"crypto/tls"
 	"fmt"
 	ber "gopkg.in/go-asn1-ber/asn1-ber.v1"
 	"net"
@@ -138,6 +139,8 @@ func (c *Client) newRequest(msg Message) (*ber.Packet, error) {
 		b.encode(p)
 	case *CompareRequest:
 		b.encode(p)
+	case *ExtendedRequest:
+		b.encode(p)
 	default:
 		return nil, fmt.Errorf("unsupported request type %t", msg)
 	}
@@ -165,6 +168,19 @@ func (c *Client) Dial() error {
 	return err
 }
 
+func (c *Client) DialTls(cfg *tls.Config) error {
+	err := c.Dial()
+	if err != nil {
+		return err
+	}
+	tlsConn := tls.Client(c.conn, cfg)
+	if err = tlsConn.Handshake(); err != nil {
+		return err
+	}
+	c.conn = tlsConn
+	return nil
+}
+
 func (c *Client) Modify(request *ModifyRequest) (*ModifyResponse, error) {
 	r, err := c.newRequest(request)
 	if err != nil {
@@ -259,3 +275,44 @@ func (c *Client) Compare(request *CompareRequest) (*CompareResponse, error) {
 	body := p.Children[1]
 	return decodeCompareResponse(body)
 }
+
+func (c *Client) Extended(request *ExtendedRequest) (*ExtendedResponse, error) {
+	r, err := c.newRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.conn.Write(r.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := ber.ReadPacket(c.conn)
+	if err != nil {
+		return nil, err
+	}
+
+	body := p.Children[1]
+	return decodeExtendedResponse(body)
+}
+
+func (c *Client) StartTLS(cfg *tls.Config) (*ExtendedResponse, error) {
+	res, err := c.Extended(&ExtendedRequest{Name: StartTLSOID})
+	if err != nil || res.ResultCode != Success {
+		return res, err
+	}
+
+	tlsConn := tls.Client(c.conn, cfg)
+	if err = tlsConn.Handshake(); err != nil {
+		return nil, err
+	}
+	c.conn = tlsConn
+	return res, nil
+}
+
+func (c *Client) WhoAmI() (*ExtendedResponse, error) {
+	return c.Extended(&ExtendedRequest{Name: WhoAmIOID})
+}
+
+func (c *Client) PasswordModify(request *PasswordModifyRequest) (*ExtendedResponse, error) {
+	return c.Extended(&ExtendedRequest{Name: PasswordModifyOID, Value: request.Encode()})
+}
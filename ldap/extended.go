@@ -0,0 +1,99 @@
+package ldap
+
+import (
+	"fmt"
+
+	ber "gopkg.in/go-asn1-ber/asn1-ber.v1"
+)
+
+const (
+	// StartTLSOID is the name of the StartTLS extended operation (RFC 4511, section 4.14)
+	StartTLSOID = "1.3.6.1.4.1.1466.20037"
+	// WhoAmIOID is the name of the "Who am I?" extended operation (RFC 4532)
+	WhoAmIOID = "1.3.6.1.4.1.4203.1.11.3"
+	// PasswordModifyOID is the name of the Password Modify extended operation (RFC 3062)
+	PasswordModifyOID = "1.3.6.1.4.1.4203.1.11.1"
+)
+
+var ExtendedOperationText = map[string]string{
+	StartTLSOID:       "StartTLS",
+	WhoAmIOID:         "WhoAmI",
+	PasswordModifyOID: "PasswordModify",
+}
+
+type ExtendedRequest struct {
+	Name  string `json:"name"`
+	Value []byte `json:"value"`
+}
+
+type ExtendedResponse struct {
+	ResultCode uint8  `json:"resultCode"`
+	MatchedDn  string `json:"matchedDn"`
+	Message    string `json:"message"`
+	Name       string `json:"name"`
+	Value      []byte `json:"value"`
+}
+
+func decodeExtendedRequest(body *ber.Packet) (*ExtendedRequest, error) {
+	if len(body.Children) == 0 {
+		return nil, fmt.Errorf("invalid extended request: request name is missing")
+	}
+
+	r := &ExtendedRequest{}
+	for _, child := range body.Children {
+		if child.ClassType != ber.ClassContext {
+			return nil, fmt.Errorf("invalid extended request: unexpected class type %v", child.ClassType)
+		}
+		switch child.Tag {
+		case 0:
+			r.Name = child.Data.String()
+		case 1:
+			r.Value = child.Data.Bytes()
+		}
+	}
+	return r, nil
+}
+
+func (r *ExtendedRequest) encode(envelope *ber.Packet) {
+	body := ber.Encode(ber.ClassApplication, ber.TypeConstructed, extendedRequest, nil, "Extended Request")
+	body.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 0, r.Name, "RequestName"))
+	if r.Value != nil {
+		body.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 1, string(r.Value), "RequestValue"))
+	}
+	envelope.AppendChild(body)
+}
+
+func decodeExtendedResponse(p *ber.Packet) (*ExtendedResponse, error) {
+	if len(p.Children) < 3 {
+		return nil, fmt.Errorf("invalid extended response: expected at least 3 children, got %d", len(p.Children))
+	}
+
+	r := &ExtendedResponse{
+		ResultCode: uint8(p.Children[0].Value.(int64)),
+		MatchedDn:  p.Children[1].Value.(string),
+		Message:    p.Children[2].Value.(string),
+	}
+	for _, child := range p.Children[3:] {
+		switch child.Tag {
+		case 10:
+			r.Name = child.Data.String()
+		case 11:
+			r.Value = child.Data.Bytes()
+		}
+	}
+	return r, nil
+}
+
+func (r *ExtendedResponse) encode(envelope *ber.Packet) {
+	body := ber.Encode(ber.ClassApplication, ber.TypeConstructed, extendedResponse, nil, "Extended Response")
+	body.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, uint64(r.ResultCode), "ResultCode"))
+	body.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, r.MatchedDn, "MatchedDN"))
+	body.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, r.Message, "DiagnosticMessage"))
+	if r.Name != "" {
+		body.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 10, r.Name, "ResponseName"))
+	}
+	if r.Value != nil {
+		body.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 11, string(r.Value), "ResponseValue"))
+	}
+	envelope.AppendChild(body)
+}
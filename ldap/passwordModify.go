@@ -0,0 +1,87 @@
+package ldap
+
+import (
+	"fmt"
+
+	ber "gopkg.in/go-asn1-ber/asn1-ber.v1"
+)
+
+// PasswordModifyRequest is the value of a Password Modify extended request (RFC 3062).
+// All fields are optional.
+type PasswordModifyRequest struct {
+	UserIdentity string `json:"userIdentity"`
+	OldPassword  string `json:"oldPassword"`
+	NewPassword  string `json:"newPassword"`
+}
+
+// PasswordModifyResponse is the value of a Password Modify extended response.
+// GenPassword is only set if the server generated the new password.
+type PasswordModifyResponse struct {
+	GenPassword string `json:"genPassword"`
+}
+
+func DecodePasswordModifyRequest(value []byte) (*PasswordModifyRequest, error) {
+	r := &PasswordModifyRequest{}
+	if len(value) == 0 {
+		return r, nil
+	}
+
+	p, err := ber.DecodePacketErr(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid password modify request: %w", err)
+	}
+	for _, child := range p.Children {
+		if child.ClassType != ber.ClassContext {
+			return nil, fmt.Errorf("invalid password modify request: unexpected class type %v", child.ClassType)
+		}
+		switch child.Tag {
+		case 0:
+			r.UserIdentity = child.Data.String()
+		case 1:
+			r.OldPassword = child.Data.String()
+		case 2:
+			r.NewPassword = child.Data.String()
+		}
+	}
+	return r, nil
+}
+
+func (r *PasswordModifyRequest) Encode() []byte {
+	p := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "PasswdModifyRequestValue")
+	if r.UserIdentity != "" {
+		p.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 0, r.UserIdentity, "userIdentity"))
+	}
+	if r.OldPassword != "" {
+		p.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 1, r.OldPassword, "oldPasswd"))
+	}
+	if r.NewPassword != "" {
+		p.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 2, r.NewPassword, "newPasswd"))
+	}
+	return p.Bytes()
+}
+
+func DecodePasswordModifyResponse(value []byte) (*PasswordModifyResponse, error) {
+	r := &PasswordModifyResponse{}
+	if len(value) == 0 {
+		return r, nil
+	}
+
+	p, err := ber.DecodePacketErr(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid password modify response: %w", err)
+	}
+	for _, child := range p.Children {
+		if child.ClassType == ber.ClassContext && child.Tag == 0 {
+			r.GenPassword = child.Data.String()
+		}
+	}
+	return r, nil
+}
+
+func (r *PasswordModifyResponse) Encode() []byte {
+	p := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "PasswdModifyResponseValue")
+	if r.GenPassword != "" {
+		p.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 0, r.GenPassword, "genPasswd"))
+	}
+	return p.Bytes()
+}
@@ -22,6 +22,8 @@ const (
 	compareRequest   = 14
 	compareResponse  = 15
 	abandonRequest   = 16
+	extendedRequest  = 23
+	extendedResponse = 24
 
 	FilterAnd             = 0
 	FilterOr              = 1
@@ -45,18 +47,21 @@ const (
 	// ScopeWholeSubtree examines the subtree below the base DN and includes the base DN level
 	ScopeWholeSubtree = 2
 
-	Success                uint8 = 0
-	OperationsError        uint8 = 1
-	ProtocolError          uint8 = 2
-	SizeLimitExceeded      uint8 = 4
-	CompareFalse           uint8 = 5
-	CompareTrue            uint8 = 6
-	AuthMethodNotSupported uint8 = 7
-	ConstraintViolation    uint8 = 19
-	NoSuchObject           uint8 = 32
-	InvalidCredentials     uint8 = 49
-	EntryAlreadyExists     uint8 = 68
-	CannotCancel           uint8 = 121
+	Success                      uint8 = 0
+	OperationsError              uint8 = 1
+	ProtocolError                uint8 = 2
+	SizeLimitExceeded            uint8 = 4
+	CompareFalse                 uint8 = 5
+	CompareTrue                  uint8 = 6
+	AuthMethodNotSupported       uint8 = 7
+	UnavailableCriticalExtension uint8 = 12
+	ConstraintViolation          uint8 = 19
+	NoSuchObject                 uint8 = 32
+	InvalidCredentials           uint8 = 49
+	InsufficientAccessRights     uint8 = 50
+	UnwillingToPerform           uint8 = 53
+	EntryAlreadyExists           uint8 = 68
+	CannotCancel                 uint8 = 121
 )
 
 var OperatorText = map[int]string{
@@ -74,18 +79,21 @@ var SubstringText = map[uint8]string{
 }
 
 var StatusText = map[uint8]string{
-	Success:                "Success",
-	OperationsError:        "OperationsError",
-	ProtocolError:          "ProtocolError",
-	SizeLimitExceeded:      "SizeLimitExceeded",
-	CompareFalse:           "CompareFalse",
-	CompareTrue:            "CompareTrue",
-	AuthMethodNotSupported: "AuthMethodNotSupported",
-	ConstraintViolation:    "ConstraintViolation",
-	NoSuchObject:           "NoSuchObject",
-	InvalidCredentials:     "InvalidCredentials",
-	EntryAlreadyExists:     "EntryAlreadyExists",
-	CannotCancel:           "CannotCancel",
+	Success:                      "Success",
+	OperationsError:              "OperationsError",
+	ProtocolError:                "ProtocolError",
+	SizeLimitExceeded:            "SizeLimitExceeded",
+	CompareFalse:                 "CompareFalse",
+	CompareTrue:                  "CompareTrue",
+	AuthMethodNotSupported:       "AuthMethodNotSupported",
+	UnavailableCriticalExtension: "UnavailableCriticalExtension",
+	ConstraintViolation:          "ConstraintViolation",
+	NoSuchObject:                 "NoSuchObject",
+	InvalidCredentials:           "InvalidCredentials",
+	InsufficientAccessRights:     "InsufficientAccessRights",
+	UnwillingToPerform:           "UnwillingToPerform",
+	EntryAlreadyExists:           "EntryAlreadyExists",
+	CannotCancel:                 "CannotCancel",
 }
 
 var ScopeText = map[uint8]string{
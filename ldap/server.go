@@ -2,6 +2,7 @@ package ldap
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
@@ -18,6 +19,14 @@ import (
 
 var ErrServerClosed = errors.New("ldap: Server closed")
 
+const (
+	TlsNone TlsMode = iota
+	TlsStartTls
+	TlsImplicit
+)
+
+type TlsMode int
+
 type atomicBool int32
 
 func (a *atomicBool) isSet() bool { return atomic.LoadInt32((*int32)(a)) != 0 }
@@ -27,6 +36,8 @@ func (a *atomicBool) setTrue()    { atomic.StoreInt32((*int32)(a), 1) }
 type Handler interface {
 	ServeLDAP(ResponseWriter, *Request)
 	Unbind(ctx context.Context)
+	// StartTLS is called after the server answered a StartTLS request
+	StartTLS(ctx context.Context, res *ExtendedResponse)
 }
 
 type HandlerFunc func(rw ResponseWriter, req *Request)
@@ -37,6 +48,8 @@ func (f HandlerFunc) ServeLDAP(rw ResponseWriter, req *Request) {
 
 func (f HandlerFunc) Unbind(ctx context.Context) {}
 
+func (f HandlerFunc) StartTLS(ctx context.Context, res *ExtendedResponse) {}
+
 type Message interface {
 }
 
@@ -56,8 +69,10 @@ type response struct {
 }
 
 type Server struct {
-	Addr    string
-	Handler Handler
+	Addr      string
+	Handler   Handler
+	TLSConfig *tls.Config
+	TlsMode   TlsMode
 
 	mu         sync.Mutex
 	closeChan  chan bool
@@ -95,6 +110,10 @@ func (s *Server) Serve(l net.Listener) error {
 			}
 		}
 
+		if s.TlsMode == TlsImplicit {
+			conn = tls.Server(conn, s.TLSConfig)
+		}
+
 		ctx := s.trackConn(conn)
 		go s.serve(conn, ctx)
 	}
@@ -127,6 +146,7 @@ func (s *Server) Close() {
 func (s *Server) serve(conn net.Conn, ctx context.Context) {
 	ctx, cancel := context.WithCancel(ctx)
 	ctx = NewPagingFromContext(ctx)
+	ctx = NewAuthContext(ctx)
 	defer func() {
 		r := recover()
 		if r != nil {
@@ -191,6 +211,20 @@ func (s *Server) serve(conn net.Conn, ctx context.Context) {
 			msg, err = decodeModifyDNRequest(body)
 		case compareRequest:
 			msg, err = decodeCompareRequest(body)
+		case extendedRequest:
+			var ext *ExtendedRequest
+			ext, err = decodeExtendedRequest(body)
+			if err == nil && ext.Name == StartTLSOID {
+				var res *ExtendedResponse
+				conn, res, err = s.serveStartTls(conn, ctx, messageId)
+				s.Handler.StartTLS(ctx, res)
+				if err != nil {
+					log.Errorf("ldap: StartTLS failed: %v", err)
+					return
+				}
+				continue
+			}
+			msg = ext
 		default:
 			log.Errorf("ldap: server does not support %v", body.Tag)
 		}
@@ -210,6 +244,45 @@ func (s *Server) serve(conn net.Conn, ctx context.Context) {
 	}
 }
 
+// serveStartTls answers the StartTLS request and upgrades the connection.
+// The returned connection replaces the raw TCP connection.
+func (s *Server) serveStartTls(conn net.Conn, ctx context.Context, messageId int64) (net.Conn, *ExtendedResponse, error) {
+	rw := &response{messageId: messageId, conn: conn}
+	if s.TLSConfig == nil || s.TlsMode != TlsStartTls {
+		res := &ExtendedResponse{
+			ResultCode: ProtocolError,
+			Message:    "StartTLS is not supported",
+			Name:       StartTLSOID,
+		}
+		return conn, res, rw.Write(res)
+	}
+	if _, ok := conn.(*tls.Conn); ok {
+		res := &ExtendedResponse{
+			ResultCode: OperationsError,
+			Message:    "TLS already established",
+			Name:       StartTLSOID,
+		}
+		return conn, res, rw.Write(res)
+	}
+
+	res := &ExtendedResponse{ResultCode: Success, Name: StartTLSOID}
+	err := rw.Write(res)
+	if err != nil {
+		return conn, res, err
+	}
+
+	tlsConn := tls.Server(conn, s.TLSConfig)
+	if err = tlsConn.Handshake(); err != nil {
+		return conn, res, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.activeConn, conn)
+	s.activeConn[tlsConn] = ctx
+	return tlsConn, res, nil
+}
+
 func (s *Server) closeConn(conn net.Conn) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -271,6 +344,8 @@ func (r *response) Write(msg Message) error {
 		res.encode(envelope)
 	case *CompareResponse:
 		res.encode(envelope)
+	case *ExtendedResponse:
+		res.encode(envelope)
 	default:
 		return fmt.Errorf("unsupported message: %t", msg)
 	}
@@ -1,9 +1,13 @@
 package ldap
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
+	"mokapi/config/static"
+	"mokapi/server/cert"
 	"mokapi/try"
 	"testing"
 )
@@ -89,3 +93,95 @@ func newTestServer(h Handler) (*Server, Client) {
 
 	return s, c
 }
+
+func TestServer_Tls(t *testing.T) {
+	store, err := cert.NewStore(&static.Config{})
+	require.NoError(t, err)
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(cert.DefaultRootCert())
+
+	testcases := []struct {
+		name string
+		mode TlsMode
+		test func(t *testing.T, c *Client)
+	}{
+		{
+			name: "implicit",
+			mode: TlsImplicit,
+			test: func(t *testing.T, c *Client) {
+				err := c.DialTls(&tls.Config{RootCAs: rootCAs, ServerName: "localhost"})
+				require.NoError(t, err)
+				r, err := c.Bind("foo", "bar")
+				require.NoError(t, err)
+				require.Equal(t, Success, r.Result)
+			},
+		},
+		{
+			name: "StartTLS",
+			mode: TlsStartTls,
+			test: func(t *testing.T, c *Client) {
+				require.NoError(t, c.Dial())
+				res, err := c.StartTLS(&tls.Config{RootCAs: rootCAs, ServerName: "localhost"})
+				require.NoError(t, err)
+				require.Equal(t, Success, res.ResultCode)
+				require.Equal(t, StartTLSOID, res.Name)
+				_, ok := c.conn.(*tls.Conn)
+				require.True(t, ok)
+
+				r, err := c.Bind("foo", "bar")
+				require.NoError(t, err)
+				require.Equal(t, Success, r.Result)
+			},
+		},
+		{
+			name: "StartTLS twice",
+			mode: TlsStartTls,
+			test: func(t *testing.T, c *Client) {
+				require.NoError(t, c.Dial())
+				_, err := c.StartTLS(&tls.Config{RootCAs: rootCAs, ServerName: "localhost"})
+				require.NoError(t, err)
+
+				res, err := c.StartTLS(&tls.Config{RootCAs: rootCAs, ServerName: "localhost"})
+				require.NoError(t, err)
+				require.Equal(t, OperationsError, res.ResultCode)
+				require.Equal(t, "TLS already established", res.Message)
+			},
+		},
+		{
+			name: "StartTLS not supported",
+			mode: TlsNone,
+			test: func(t *testing.T, c *Client) {
+				require.NoError(t, c.Dial())
+				res, err := c.StartTLS(&tls.Config{RootCAs: rootCAs, ServerName: "localhost"})
+				require.NoError(t, err)
+				require.Equal(t, ProtocolError, res.ResultCode)
+				require.Equal(t, "StartTLS is not supported", res.Message)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			addr := fmt.Sprintf("127.0.0.1:%v", try.GetFreePort())
+			s := &Server{
+				Addr: addr,
+				Handler: HandlerFunc(func(rw ResponseWriter, req *Request) {
+					err := rw.Write(&BindResponse{Result: Success})
+					require.NoError(t, err)
+				}),
+				TLSConfig: &tls.Config{GetCertificate: store.GetCertificate},
+				TlsMode:   tc.mode,
+			}
+			defer s.Close()
+			go func() {
+				err := s.ListenAndServe()
+				if err != nil && !errors.Is(err, ErrServerClosed) {
+					panic(err)
+				}
+			}()
+
+			tc.test(t, &Client{Addr: addr})
+		})
+	}
+}
@@ -130,3 +130,7 @@ func (th *testHost) Produce(_ *common.KafkaProduceArgs) (*common.KafkaProduceRes
 func (th *testHost) KafkaClient() common.KafkaClient {
 	return th
 }
+
+func (th *testHost) WebSocketClient() common.WebSocketClient {
+	return nil
+}
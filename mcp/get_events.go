@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"mokapi/runtime/events"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type GetEventsInput struct {
+	Type   string            `json:"type"`
+	Traits map[string]string `json:"traits"`
+	Limit  int               `json:"limit"`
+}
+
+type GetEventsOutput struct {
+	Events []any `json:"events"`
+}
+
+type GetEventInput struct {
+	Id string `json:"id"`
+}
+
+type GetEventOutput struct {
+	Id     string        `json:"id"`
+	Type   string        `json:"type"`
+	Time   time.Time     `json:"time"`
+	Traits events.Traits `json:"traits"`
+	Data   any           `json:"data"`
+}
+
+func (s *Service) registerGetEvents(server *mcp.Server) {
+	registerTool(server, &mcp.Tool{
+		Name: "mokapi_get_events",
+		Description: `Returns the latest events recorded by Mokapi, newest first, e.g. received HTTP requests
+and their responses, or Kafka records written to a topic.
+
+Events are filtered by protocol type and traits. Common traits are:
+- http: name (API name), path, method
+- kafka: name (cluster name), topic
+- mail: name, to
+
+Each event is a summary. Use 'mokapi_get_event' with the id of an event to get the full details
+like headers and bodies.`,
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"type": map[string]any{
+					"type":        "string",
+					"description": "The protocol of the events. If omitted, events of all types are returned.",
+					"enum":        []string{"http", "kafka", "mqtt", "ldap", "mail", "websocket", "job", "logs"},
+				},
+				"traits": map[string]any{
+					"type":                 "object",
+					"description":          "Traits an event must have, e.g. { \"name\": \"Petstore\", \"path\": \"/pets\" }",
+					"additionalProperties": map[string]any{"type": "string"},
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"description": "The maximum number of events to return. Default is 10.",
+					"minimum":     1,
+				},
+			},
+		},
+	}, s.GetEvents)
+}
+
+func (s *Service) registerGetEvent(server *mcp.Server) {
+	registerTool(server, &mcp.Tool{
+		Name: "mokapi_get_event",
+		Description: `Returns a single event with all details, e.g. the full HTTP request and response
+including headers and bodies, or a Kafka record including key, value, headers, partition and offset.
+
+Use 'mokapi_get_events' to find the id of an event.`,
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id": map[string]any{
+					"type":        "string",
+					"description": "The id of the event.",
+				},
+			},
+			"required": []string{"id"},
+		},
+	}, s.GetEvent)
+}
+
+func (s *Service) GetEvents(_ context.Context, in GetEventsInput) (GetEventsOutput, error) {
+	traits := events.NewTraits()
+	if in.Type != "" {
+		traits.WithNamespace(in.Type)
+	}
+	for k, v := range in.Traits {
+		switch k {
+		case "name":
+			traits.WithName(v)
+		case "method":
+			traits.With(k, strings.ToUpper(v))
+		default:
+			traits.With(k, v)
+		}
+	}
+
+	limit := in.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	result := GetEventsOutput{Events: []any{}}
+	for _, evt := range s.app.Events.GetEvents(traits) {
+		if len(result.Events) >= limit {
+			break
+		}
+		result.Events = append(result.Events, convertEvent(evt))
+	}
+	return result, nil
+}
+
+func (s *Service) GetEvent(_ context.Context, in GetEventInput) (GetEventOutput, error) {
+	if in.Id == "" {
+		return GetEventOutput{}, fmt.Errorf("expected id parameter")
+	}
+	e := s.app.Events.GetEvent(in.Id)
+	if e.Id == "" {
+		return GetEventOutput{}, fmt.Errorf("event %s not found. Use 'mokapi_get_events' to find existing events", in.Id)
+	}
+	return GetEventOutput{
+		Id:     e.Id,
+		Type:   e.Traits.GetNamespace(),
+		Time:   e.Time,
+		Traits: e.Traits,
+		Data:   e.Data,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package mcp_test
+
+import (
+	"context"
+	"mokapi/mcp"
+	"mokapi/providers/asyncapi3/kafka/store"
+	"mokapi/providers/openapi"
+	"mokapi/runtime"
+	"mokapi/runtime/events"
+	"mokapi/runtime/runtimetest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_GetEvents(t *testing.T) {
+	testcases := []struct {
+		name string
+		app  *runtime.App
+		in   mcp.GetEventsInput
+		test func(t *testing.T, out mcp.GetEventsOutput, err error)
+	}{
+		{
+			name: "no events",
+			app:  runtimetest.NewApp(),
+			test: func(t *testing.T, out mcp.GetEventsOutput, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []any{}, out.Events)
+			},
+		},
+		{
+			name: "filter by type and traits",
+			app: runtimetest.NewApp(
+				runtimetest.WithEvent(events.NewTraits().WithNamespace("http").WithName("foo").With("method", "GET"), &openapi.HttpLog{Api: "foo", Path: "/a"}),
+				runtimetest.WithEvent(events.NewTraits().WithNamespace("http").WithName("foo").With("method", "POST"), &openapi.HttpLog{Api: "foo", Path: "/b"}),
+				runtimetest.WithEvent(events.NewTraits().WithNamespace("kafka").WithName("foo"), &store.KafkaMessageLog{Api: "foo"}),
+			),
+			in: mcp.GetEventsInput{Type: "http", Traits: map[string]string{"name": "foo", "method": "post"}},
+			test: func(t *testing.T, out mcp.GetEventsOutput, err error) {
+				require.NoError(t, err)
+				require.Len(t, out.Events, 1)
+				require.Equal(t, "/b", out.Events[0].(*mcp.HttpEvent).Path)
+			},
+		},
+		{
+			name: "limit",
+			app: runtimetest.NewApp(
+				runtimetest.WithEvent(events.NewTraits().WithNamespace("http"), &openapi.HttpLog{}),
+				runtimetest.WithEvent(events.NewTraits().WithNamespace("http"), &openapi.HttpLog{}),
+				runtimetest.WithEvent(events.NewTraits().WithNamespace("http"), &openapi.HttpLog{}),
+			),
+			in: mcp.GetEventsInput{Limit: 2},
+			test: func(t *testing.T, out mcp.GetEventsOutput, err error) {
+				require.NoError(t, err)
+				require.Len(t, out.Events, 2)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := mcp.NewService(tc.app)
+			out, err := s.GetEvents(context.Background(), tc.in)
+			tc.test(t, out, err)
+		})
+	}
+}
+
+func TestService_GetEvent(t *testing.T) {
+	app := runtimetest.NewApp(
+		runtimetest.WithEvent(events.NewTraits().WithNamespace("kafka").With("topic", "orders"), &store.KafkaMessageLog{
+			Offset:  3,
+			Key:     store.LogValue{Value: "key"},
+			Message: store.LogValue{Value: `{"id":1}`},
+			Headers: map[string]store.LogValue{"source": {Value: "test"}},
+		}),
+	)
+	s := mcp.NewService(app)
+
+	list, err := s.GetEvents(context.Background(), mcp.GetEventsInput{})
+	require.NoError(t, err)
+	id := list.Events[0].(*mcp.KafkaEvent).Id
+
+	out, err := s.GetEvent(context.Background(), mcp.GetEventInput{Id: id})
+	require.NoError(t, err)
+	require.Equal(t, id, out.Id)
+	require.Equal(t, "kafka", out.Type)
+	require.Equal(t, "orders", out.Traits.Get("topic"))
+	log := out.Data.(*store.KafkaMessageLog)
+	require.Equal(t, int64(3), log.Offset)
+	require.Equal(t, "test", log.Headers["source"].Value)
+
+	_, err = s.GetEvent(context.Background(), mcp.GetEventInput{Id: "foo"})
+	require.EqualError(t, err, "event foo not found. Use 'mokapi_get_events' to find existing events")
+}
@@ -0,0 +1,197 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"mokapi/providers/asyncapi3/kafka/store"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type GetKafkaStateInput struct {
+	Cluster string `json:"cluster"`
+	Topic   string `json:"topic"`
+}
+
+type GetKafkaStateOutput struct {
+	Cluster string            `json:"cluster"`
+	Topics  []KafkaTopicState `json:"topics"`
+	Groups  []KafkaGroupState `json:"groups"`
+}
+
+type KafkaTopicState struct {
+	Name       string                `json:"name"`
+	Partitions []KafkaPartitionState `json:"partitions"`
+}
+
+type KafkaPartitionState struct {
+	Index       int   `json:"index"`
+	StartOffset int64 `json:"startOffset"`
+	Offset      int64 `json:"offset"`
+}
+
+type KafkaGroupState struct {
+	Name       string              `json:"name"`
+	State      string              `json:"state"`
+	Generation int                 `json:"generation"`
+	Protocol   string              `json:"protocol,omitempty"`
+	Leader     string              `json:"leader,omitempty"`
+	Members    []KafkaGroupMember  `json:"members"`
+	Offsets    []KafkaCommitOffset `json:"offsets"`
+}
+
+type KafkaGroupMember struct {
+	Id         string           `json:"id"`
+	ClientId   string           `json:"clientId"`
+	Address    string           `json:"address"`
+	Partitions map[string][]int `json:"partitions"`
+}
+
+type KafkaCommitOffset struct {
+	Topic     string `json:"topic"`
+	Partition int    `json:"partition"`
+	Offset    int64  `json:"offset"`
+	// Lag is the number of records the group has not yet committed
+	Lag int64 `json:"lag"`
+}
+
+func (s *Service) registerGetKafkaState(server *mcp.Server) {
+	registerTool(server, &mcp.Tool{
+		Name: "mokapi_get_kafka_state",
+		Description: `Returns the live state of a Kafka cluster mocked by Mokapi: the start and end offset of each
+topic partition and the consumer groups with their state, members, partition assignments,
+committed offsets and lag.
+
+Use this tool to check whether a record was written to a topic or why a consumer does not
+receive records.`,
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"cluster": map[string]any{
+					"type":        "string",
+					"description": "The name of the Kafka cluster (title of the AsyncAPI specification).",
+				},
+				"topic": map[string]any{
+					"type":        "string",
+					"description": "If set, only this topic and the groups consuming it are returned.",
+				},
+			},
+			"required": []string{"cluster"},
+		},
+	}, s.GetKafkaState)
+}
+
+func (s *Service) GetKafkaState(_ context.Context, in GetKafkaStateInput) (GetKafkaStateOutput, error) {
+	k := s.app.Kafka.Get(in.Cluster)
+	if k == nil {
+		return GetKafkaStateOutput{}, fmt.Errorf("kafka cluster '%s' not found. Use 'mokapi_list_apis' to list all clusters", in.Cluster)
+	}
+
+	result := GetKafkaStateOutput{
+		Cluster: in.Cluster,
+		Topics:  []KafkaTopicState{},
+		Groups:  []KafkaGroupState{},
+	}
+
+	offsets := map[string]map[int]int64{}
+	for _, t := range k.Topics() {
+		if in.Topic != "" && t.Name != in.Topic {
+			continue
+		}
+		ts := KafkaTopicState{Name: t.Name, Partitions: []KafkaPartitionState{}}
+		offsets[t.Name] = map[int]int64{}
+		for _, p := range t.Partitions {
+			ts.Partitions = append(ts.Partitions, KafkaPartitionState{
+				Index:       p.Index,
+				StartOffset: p.StartOffset(),
+				Offset:      p.Offset(),
+			})
+			offsets[t.Name][p.Index] = p.Offset()
+		}
+		result.Topics = append(result.Topics, ts)
+	}
+	if in.Topic != "" && len(result.Topics) == 0 {
+		return GetKafkaStateOutput{}, fmt.Errorf("topic '%s' not found in cluster '%s'", in.Topic, in.Cluster)
+	}
+	slices.SortFunc(result.Topics, func(a, b KafkaTopicState) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for _, g := range k.Groups() {
+		snapshot, ok := g.Snapshot()
+		if !ok {
+			// group has been deleted in the meantime
+			continue
+		}
+		commits := g.CommittedOffsets()
+		if in.Topic != "" && !groupConsumesTopic(snapshot, commits, in.Topic) {
+			continue
+		}
+		result.Groups = append(result.Groups, getKafkaGroupState(g.Name, snapshot, commits, offsets))
+	}
+	slices.SortFunc(result.Groups, func(a, b KafkaGroupState) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return result, nil
+}
+
+func getKafkaGroupState(name string, snapshot store.GroupSnapshot, commits map[string]map[int]int64, offsets map[string]map[int]int64) KafkaGroupState {
+	gs := KafkaGroupState{
+		Name:       name,
+		State:      snapshot.State.String(),
+		Generation: snapshot.Generation,
+		Protocol:   snapshot.Protocol,
+		Leader:     snapshot.LeaderId,
+		Members:    []KafkaGroupMember{},
+		Offsets:    []KafkaCommitOffset{},
+	}
+	for _, m := range snapshot.Members {
+		gs.Members = append(gs.Members, KafkaGroupMember{
+			Id:         m.MemberId,
+			ClientId:   m.ClientId,
+			Address:    m.ClientHost,
+			Partitions: m.Partitions,
+		})
+	}
+	slices.SortFunc(gs.Members, func(a, b KafkaGroupMember) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	for topic, partitions := range commits {
+		partitionOffsets, ok := offsets[topic]
+		if !ok {
+			continue
+		}
+		for partition, offset := range partitions {
+			gs.Offsets = append(gs.Offsets, KafkaCommitOffset{
+				Topic:     topic,
+				Partition: partition,
+				Offset:    offset,
+				Lag:       max(partitionOffsets[partition]-offset, 0),
+			})
+		}
+	}
+	slices.SortFunc(gs.Offsets, func(a, b KafkaCommitOffset) int {
+		if r := strings.Compare(a.Topic, b.Topic); r != 0 {
+			return r
+		}
+		return a.Partition - b.Partition
+	})
+
+	return gs
+}
+
+func groupConsumesTopic(snapshot store.GroupSnapshot, commits map[string]map[int]int64, topic string) bool {
+	if _, ok := commits[topic]; ok {
+		return true
+	}
+	for _, m := range snapshot.Members {
+		if _, ok := m.Partitions[topic]; ok {
+			return true
+		}
+	}
+	return false
+}
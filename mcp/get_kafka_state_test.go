@@ -0,0 +1,106 @@
+package mcp_test
+
+import (
+	"context"
+	"mokapi/kafka"
+	"mokapi/mcp"
+	"mokapi/providers/asyncapi3"
+	"mokapi/providers/asyncapi3/asyncapi3test"
+	"mokapi/runtime"
+	"mokapi/runtime/runtimetest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_GetKafkaState(t *testing.T) {
+	newApp := func(t *testing.T) *runtime.App {
+		app := runtimetest.NewKafkaApp(
+			asyncapi3test.NewConfig(
+				asyncapi3test.WithInfo("foo", "", ""),
+				asyncapi3test.WithChannel("orders",
+					asyncapi3test.WithKafkaChannelBinding(asyncapi3.TopicBindings{Partitions: 2}),
+				),
+				asyncapi3test.WithChannel("users"),
+			),
+		)
+		k := app.Kafka.Get("foo")
+		_, err := k.Topic("orders").Partition(1).Write(kafka.RecordBatch{Records: []*kafka.Record{
+			{Value: kafka.NewBytes([]byte("a"))},
+			{Value: kafka.NewBytes([]byte("b"))},
+			{Value: kafka.NewBytes([]byte("c"))},
+		}})
+		require.NoError(t, err)
+
+		g := k.GetOrCreateGroup("billing", k.Brokers()[0])
+		g.Commit("orders", 1, 1)
+		k.GetOrCreateGroup("other", k.Brokers()[0]).Commit("users", 0, 0)
+		return app
+	}
+
+	testcases := []struct {
+		name string
+		in   mcp.GetKafkaStateInput
+		test func(t *testing.T, out mcp.GetKafkaStateOutput, err error)
+	}{
+		{
+			name: "cluster",
+			in:   mcp.GetKafkaStateInput{Cluster: "foo"},
+			test: func(t *testing.T, out mcp.GetKafkaStateOutput, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "foo", out.Cluster)
+				require.Len(t, out.Topics, 2)
+				require.Equal(t, mcp.KafkaTopicState{
+					Name: "orders",
+					Partitions: []mcp.KafkaPartitionState{
+						{Index: 0, StartOffset: 0, Offset: 0},
+						{Index: 1, StartOffset: 0, Offset: 3},
+					},
+				}, out.Topics[0])
+				require.Len(t, out.Groups, 2)
+				require.Equal(t, mcp.KafkaGroupState{
+					Name:       "billing",
+					State:      "Empty",
+					Generation: -1,
+					Members:    []mcp.KafkaGroupMember{},
+					Offsets: []mcp.KafkaCommitOffset{
+						{Topic: "orders", Partition: 1, Offset: 1, Lag: 2},
+					},
+				}, out.Groups[0])
+			},
+		},
+		{
+			name: "filter by topic",
+			in:   mcp.GetKafkaStateInput{Cluster: "foo", Topic: "users"},
+			test: func(t *testing.T, out mcp.GetKafkaStateOutput, err error) {
+				require.NoError(t, err)
+				require.Len(t, out.Topics, 1)
+				require.Equal(t, "users", out.Topics[0].Name)
+				require.Len(t, out.Groups, 1)
+				require.Equal(t, "other", out.Groups[0].Name)
+			},
+		},
+		{
+			name: "cluster not found",
+			in:   mcp.GetKafkaStateInput{Cluster: "bar"},
+			test: func(t *testing.T, out mcp.GetKafkaStateOutput, err error) {
+				require.EqualError(t, err, "kafka cluster 'bar' not found. Use 'mokapi_list_apis' to list all clusters")
+			},
+		},
+		{
+			name: "topic not found",
+			in:   mcp.GetKafkaStateInput{Cluster: "foo", Topic: "bar"},
+			test: func(t *testing.T, out mcp.GetKafkaStateOutput, err error) {
+				require.EqualError(t, err, "topic 'bar' not found in cluster 'foo'")
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := mcp.NewService(newApp(t))
+			out, err := s.GetKafkaState(context.Background(), tc.in)
+			tc.test(t, out, err)
+		})
+	}
+}
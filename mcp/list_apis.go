@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"context"
+	"mokapi/config/dynamic"
+	"mokapi/runtime"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ListApisInput struct{}
+
+type ListApisOutput struct {
+	Apis    []ApiInfo    `json:"apis"`
+	Configs []ConfigInfo `json:"configs"`
+}
+
+type ApiInfo struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Configs []string `json:"configs"`
+}
+
+type ConfigInfo struct {
+	Url      string    `json:"url"`
+	Provider string    `json:"provider,omitempty"`
+	Time     time.Time `json:"time"`
+	Error    string    `json:"error,omitempty"`
+}
+
+func (s *Service) registerListApis(server *mcp.Server) {
+	registerTool(server, &mcp.Tool{
+		Name: "mokapi_list_apis",
+		Description: `Lists all APIs mocked by Mokapi and all loaded config files.
+
+Config files that could not be parsed are included with their error. Use this tool first
+when a mock does not behave as expected, e.g. an endpoint or topic is missing.`,
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+	}, s.ListApis)
+}
+
+func (s *Service) ListApis(_ context.Context, _ ListApisInput) (ListApisOutput, error) {
+	result := ListApisOutput{Apis: []ApiInfo{}, Configs: []ConfigInfo{}}
+
+	for _, api := range s.app.Http.List() {
+		result.Apis = append(result.Apis, newApiInfo(api.Info.Name, "http", api.Configs()))
+	}
+	for _, api := range s.app.Kafka.List() {
+		result.Apis = append(result.Apis, newApiInfo(api.Info.Name, "kafka", api.Configs()))
+	}
+	for _, api := range s.app.Mqtt.List() {
+		result.Apis = append(result.Apis, newApiInfo(api.Info.Name, "mqtt", api.Configs()))
+	}
+	for _, api := range s.app.Mail.List() {
+		result.Apis = append(result.Apis, newApiInfo(api.Info.Name, "mail", api.Configs()))
+	}
+	for _, api := range s.app.Ldap.List() {
+		result.Apis = append(result.Apis, newApiInfo(api.Info.Name, "ldap", api.Configs()))
+	}
+	slices.SortStableFunc(result.Apis, func(a, b ApiInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	errs := map[string]runtime.ConfigError{}
+	for _, e := range s.app.ConfigErrors() {
+		errs[e.Url] = e
+	}
+	for _, c := range s.app.Configs {
+		info := ConfigInfo{
+			Url:      filepath.ToSlash(c.Info.Path()),
+			Provider: c.Info.Provider,
+			Time:     c.Info.Time,
+		}
+		// a config that failed to reload is still served with its previous version
+		if e, ok := errs[info.Url]; ok {
+			info.Error = e.Error
+			delete(errs, info.Url)
+		}
+		result.Configs = append(result.Configs, info)
+	}
+	for _, e := range errs {
+		result.Configs = append(result.Configs, ConfigInfo{
+			Url:   e.Url,
+			Time:  e.Time,
+			Error: e.Error,
+		})
+	}
+	slices.SortStableFunc(result.Configs, func(a, b ConfigInfo) int {
+		return strings.Compare(a.Url, b.Url)
+	})
+
+	return result, nil
+}
+
+func newApiInfo(name, apiType string, configs []*dynamic.Config) ApiInfo {
+	info := ApiInfo{Name: name, Type: apiType, Configs: []string{}}
+	for _, c := range configs {
+		info.Configs = append(info.Configs, filepath.ToSlash(c.Info.Path()))
+	}
+	return info
+}
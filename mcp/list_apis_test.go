@@ -0,0 +1,54 @@
+package mcp_test
+
+import (
+	"context"
+	"errors"
+	"mokapi/config/dynamic"
+	"mokapi/config/dynamic/dynamictest"
+	"mokapi/mcp"
+	"mokapi/providers/asyncapi3/asyncapi3test"
+	"mokapi/providers/openapi/openapitest"
+	"mokapi/runtime/runtimetest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_ListApis(t *testing.T) {
+	app := runtimetest.NewApp(
+		runtimetest.WithHttp(openapitest.NewConfig("3.0", openapitest.WithInfo("petstore", "", ""))),
+		runtimetest.WithKafka(asyncapi3test.NewConfig(asyncapi3test.WithInfo("orders", "", ""))),
+	)
+	loaded := &dynamic.Config{Info: dynamictest.NewConfigInfo(dynamictest.WithUrl("file:///petstore.yaml"))}
+	app.UpdateConfig(dynamic.ConfigEvent{Config: loaded, Event: dynamic.Create})
+	app.SetConfigError(
+		&dynamic.Config{Info: dynamictest.NewConfigInfo(dynamictest.WithUrl("file:///broken.yaml"))},
+		errors.New("unexpected end of file"),
+	)
+
+	s := mcp.NewService(app)
+	out, err := s.ListApis(context.Background(), mcp.ListApisInput{})
+	require.NoError(t, err)
+
+	require.Len(t, out.Apis, 2)
+	require.Equal(t, "orders", out.Apis[0].Name)
+	require.Equal(t, "kafka", out.Apis[0].Type)
+	require.Equal(t, "petstore", out.Apis[1].Name)
+	require.Equal(t, "http", out.Apis[1].Type)
+
+	require.Len(t, out.Configs, 2)
+	require.Equal(t, "file:///broken.yaml", out.Configs[0].Url)
+	require.Equal(t, "unexpected end of file", out.Configs[0].Error)
+	require.Equal(t, "file:///petstore.yaml", out.Configs[1].Url)
+	require.Empty(t, out.Configs[1].Error)
+
+	// error is removed after successful update
+	app.UpdateConfig(dynamic.ConfigEvent{
+		Config: &dynamic.Config{Info: dynamictest.NewConfigInfo(dynamictest.WithUrl("file:///broken.yaml"))},
+		Event:  dynamic.Update,
+	})
+	out, err = s.ListApis(context.Background(), mcp.ListApisInput{})
+	require.NoError(t, err)
+	require.Len(t, out.Configs, 2)
+	require.Empty(t, out.Configs[0].Error)
+}
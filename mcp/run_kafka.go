@@ -260,6 +260,11 @@ func (t *Topic) Consume(partition int, startOffset int64, limit int) ([]KafkaRec
 
 		for seg.Contains(offset) {
 			r := seg.Record(offset)
+			if r == nil || seg.IsControl(offset) {
+				// removed by log compaction or DeleteRecords, or a transaction marker
+				offset++
+				continue
+			}
 
 			result := KafkaRecord{
 				Offset: offset,
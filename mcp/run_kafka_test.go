@@ -350,6 +350,45 @@ lastMessage
 				require.Equal(t, `{"foo":"bar"}`, records[0].Value)
 			},
 		},
+		{
+			name: "consume skips records removed by compaction",
+			app: func() *runtime.App {
+				app := runtimetest.NewKafkaApp(
+					asyncapi3test.NewConfig(
+						asyncapi3test.WithInfo("foo", "", ""),
+						asyncapi3test.WithChannel("channel-1"),
+					),
+				)
+
+				p := app.Kafka.Get("foo").Store.Topic("channel-1").Partition(0)
+				_, err := p.Write(
+					kafka.RecordBatch{
+						Records: []*kafka.Record{
+							{Key: kafka.NewBytes([]byte("foo")), Value: kafka.NewBytes([]byte("a"))},
+							{Key: kafka.NewBytes([]byte("foo")), Value: kafka.NewBytes([]byte("b"))},
+						},
+					},
+				)
+				require.NoError(t, err)
+				// a compacted record leaves a gap in the log
+				p.GetSegment(0).Log[0] = nil
+
+				return app
+			}(),
+			test: func(t *testing.T, s *mcp.Service) {
+				r, err := s.GetRunResponse(
+					context.Background(),
+					mcp.RunInput{
+						Code: `mokapi.getApi('foo').getTopic('channel-1').consume(0, 0, 10)`,
+					},
+				)
+				require.NoError(t, err)
+				records := r.Result.([]mcp.KafkaRecord)
+				require.Len(t, records, 1)
+				require.Equal(t, int64(1), records[0].Offset)
+				require.Equal(t, "b", records[0].Value)
+			},
+		},
 		{
 			name: "produce into topic",
 			app: func() *runtime.App {
@@ -29,6 +29,11 @@ func NewServer(app *runtime.App) http.Handler {
 	svc.registerRunTool(server)
 	svc.registerGetAutomationDefinitions(server)
 	svc.registerGetMockReference(server)
+	svc.registerListApis(server)
+	svc.registerGetEvents(server)
+	svc.registerGetEvent(server)
+	svc.registerGetKafkaState(server)
+	svc.registerValidatePayload(server)
 
 	addResources(server)
 
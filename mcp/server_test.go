@@ -38,11 +38,16 @@ func TestServer(t *testing.T) {
 			test: func(t *testing.T) {
 				list, err := session.ListTools(ctx, &gomcp.ListToolsParams{})
 				require.NoError(t, err)
-				require.Len(t, list.Tools, 3)
+				require.Len(t, list.Tools, 8)
 				// alphabetical order
 				require.Equal(t, "mokapi_execute_code", list.Tools[0].Name)
 				require.Equal(t, "mokapi_get_automation_definitions", list.Tools[1].Name)
-				require.Equal(t, "mokapi_get_mock_reference", list.Tools[2].Name)
+				require.Equal(t, "mokapi_get_event", list.Tools[2].Name)
+				require.Equal(t, "mokapi_get_events", list.Tools[3].Name)
+				require.Equal(t, "mokapi_get_kafka_state", list.Tools[4].Name)
+				require.Equal(t, "mokapi_get_mock_reference", list.Tools[5].Name)
+				require.Equal(t, "mokapi_list_apis", list.Tools[6].Name)
+				require.Equal(t, "mokapi_validate_payload", list.Tools[7].Name)
 				/*require.Equal(t, "mokapi_generate_http_mock_response", list.Tools[1].Name)
 				require.Equal(t, "mokapi_get_api_spec", list.Tools[2].Name)
 				require.Equal(t, "mokapi_get_events", list.Tools[3].Name)
@@ -79,6 +84,45 @@ func TestServer(t *testing.T) {
 				require.Contains(t, list.Tools[0].Description, "mokapi_get_automation_definitions")
 			},
 		},
+		{
+			name: "list apis",
+			test: func(t *testing.T) {
+				r, err := session.CallTool(ctx, &gomcp.CallToolParams{
+					Name:      "mokapi_list_apis",
+					Arguments: map[string]any{},
+				})
+				require.NoError(t, err)
+				require.False(t, r.IsError)
+				tc := r.Content[0].(*gomcp.TextContent)
+				require.Equal(t, `{"apis":[],"configs":[]}`, tc.Text)
+			},
+		},
+		{
+			name: "get events",
+			test: func(t *testing.T) {
+				r, err := session.CallTool(ctx, &gomcp.CallToolParams{
+					Name:      "mokapi_get_events",
+					Arguments: map[string]any{"type": "http", "limit": 5},
+				})
+				require.NoError(t, err)
+				require.False(t, r.IsError)
+				tc := r.Content[0].(*gomcp.TextContent)
+				require.Equal(t, `{"events":[]}`, tc.Text)
+			},
+		},
+		{
+			name: "get unknown event",
+			test: func(t *testing.T) {
+				r, err := session.CallTool(ctx, &gomcp.CallToolParams{
+					Name:      "mokapi_get_event",
+					Arguments: map[string]any{"id": "foo"},
+				})
+				require.NoError(t, err)
+				require.True(t, r.IsError)
+				tc := r.Content[0].(*gomcp.TextContent)
+				require.Equal(t, "event foo not found. Use 'mokapi_get_events' to find existing events", tc.Text)
+			},
+		},
 		{
 			name: "get resource execute-types",
 			test: func(t *testing.T) {
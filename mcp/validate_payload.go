@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mokapi/media"
+	"mokapi/providers/asyncapi3"
+	"mokapi/providers/openapi/schema"
+	"mokapi/schema/encoding"
+	"mokapi/schema/json/parser"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ValidatePayloadInput struct {
+	Api         string `json:"api"`
+	Schema      string `json:"schema"`
+	Data        any    `json:"data"`
+	Raw         string `json:"raw"`
+	Base64      bool   `json:"base64"`
+	ContentType string `json:"contentType"`
+}
+
+type ValidatePayloadOutput struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+func (s *Service) registerValidatePayload(server *mcp.Server) {
+	registerTool(server, &mcp.Tool{
+		Name: "mokapi_validate_payload",
+		Description: `Validates a payload against a named schema of a loaded API specification. The schema is looked up
+in 'components.schemas' of the OpenAPI or AsyncAPI specification.
+
+Pass the payload either as JSON value in 'data', or encoded in 'raw' together with its 'contentType'.
+Binary payloads like Avro or Protobuf must be base64 encoded in 'raw' with 'base64' set to true.
+
+Use this tool to find out why Mokapi rejects a request body or a Kafka record.`,
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"api": map[string]any{
+					"type":        "string",
+					"description": "The name of the API (title of the specification).",
+				},
+				"schema": map[string]any{
+					"type":        "string",
+					"description": "The name of the schema in components.schemas.",
+				},
+				"data": map[string]any{
+					"description": "The payload as JSON value.",
+				},
+				"raw": map[string]any{
+					"type":        "string",
+					"description": "The encoded payload. Used instead of 'data'.",
+				},
+				"base64": map[string]any{
+					"type":        "boolean",
+					"description": "Whether 'raw' is base64 encoded.",
+				},
+				"contentType": map[string]any{
+					"type":        "string",
+					"description": "The content type of 'raw'. Default is application/json.",
+				},
+			},
+			"required": []string{"api", "schema"},
+		},
+	}, s.ValidatePayload)
+}
+
+func (s *Service) ValidatePayload(_ context.Context, in ValidatePayloadInput) (ValidatePayloadOutput, error) {
+	ct := media.ParseContentType("application/json")
+	if in.ContentType != "" {
+		ct = media.ParseContentType(in.ContentType)
+	}
+
+	p, err := s.getSchemaParser(in.Api, in.Schema, ct)
+	if err != nil {
+		return ValidatePayloadOutput{}, err
+	}
+
+	var v any
+	if in.Raw != "" {
+		b := []byte(in.Raw)
+		if in.Base64 {
+			b, err = base64.StdEncoding.DecodeString(in.Raw)
+			if err != nil {
+				return ValidatePayloadOutput{}, fmt.Errorf("decode base64 failed: %w", err)
+			}
+		}
+		v, err = encoding.Decode(b, encoding.WithContentType(ct), encoding.WithParser(p))
+	} else {
+		v, err = p.Parse(in.Data)
+	}
+
+	if err != nil {
+		return ValidatePayloadOutput{Valid: false, Error: err.Error()}, nil
+	}
+	return ValidatePayloadOutput{Valid: true, Value: v}, nil
+}
+
+func (s *Service) getSchemaParser(api, name string, ct media.ContentType) (encoding.Parser, error) {
+	if h := s.app.Http.Get(api); h != nil {
+		if h.Components.Schemas == nil || h.Components.Schemas.Get(name) == nil {
+			return nil, fmt.Errorf("schema '%s' not found in API '%s'", name, api)
+		}
+		return &parser.Parser{
+			Schema:                       schema.ConvertToJsonSchema(h.Components.Schemas.Get(name)),
+			ValidateAdditionalProperties: true,
+		}, nil
+	}
+
+	var cfg *asyncapi3.Config
+	if k := s.app.Kafka.Get(api); k != nil {
+		cfg = k.Config
+	} else if m := s.app.Mqtt.Get(api); m != nil {
+		cfg = m.Config
+	} else {
+		return nil, fmt.Errorf("API '%s' not found. Use 'mokapi_list_apis' to list all APIs", api)
+	}
+
+	if cfg.Components == nil || cfg.Components.Schemas[name] == nil {
+		return nil, fmt.Errorf("schema '%s' not found in API '%s'", name, api)
+	}
+	return cfg.Components.Schemas[name].GetParser(ct.String())
+}
@@ -0,0 +1,107 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/base64"
+	"mokapi/mcp"
+	"mokapi/providers/asyncapi3"
+	"mokapi/providers/asyncapi3/asyncapi3test"
+	"mokapi/providers/openapi/openapitest"
+	opSchematest "mokapi/providers/openapi/schema/schematest"
+	"mokapi/runtime/runtimetest"
+	"mokapi/schema/avro/schema"
+	"mokapi/schema/json/schema/schematest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_ValidatePayload(t *testing.T) {
+	app := runtimetest.NewApp(
+		runtimetest.WithHttp(openapitest.NewConfig("3.0",
+			openapitest.WithInfo("petstore", "", ""),
+			openapitest.WithComponentSchema("Pet", opSchematest.New("object",
+				opSchematest.WithProperty("name", opSchematest.New("string")),
+				opSchematest.WithRequired("name"),
+			)),
+		)),
+		runtimetest.WithKafka(asyncapi3test.NewConfig(
+			asyncapi3test.WithInfo("orders", "", ""),
+			asyncapi3test.WithComponentSchema("Order", schematest.New("object",
+				schematest.WithProperty("id", schematest.New("integer")),
+			)),
+			asyncapi3test.WithComponentSchema("Avro", &asyncapi3.AvroRef{Schema: &schema.Schema{Type: []any{"string"}}}),
+		)),
+	)
+
+	testcases := []struct {
+		name string
+		in   mcp.ValidatePayloadInput
+		test func(t *testing.T, out mcp.ValidatePayloadOutput, err error)
+	}{
+		{
+			name: "valid OpenAPI data",
+			in:   mcp.ValidatePayloadInput{Api: "petstore", Schema: "Pet", Data: map[string]any{"name": "Bob"}},
+			test: func(t *testing.T, out mcp.ValidatePayloadOutput, err error) {
+				require.NoError(t, err)
+				require.True(t, out.Valid)
+				require.Equal(t, map[string]any{"name": "Bob"}, out.Value)
+			},
+		},
+		{
+			name: "invalid OpenAPI data",
+			in:   mcp.ValidatePayloadInput{Api: "petstore", Schema: "Pet", Data: map[string]any{}},
+			test: func(t *testing.T, out mcp.ValidatePayloadOutput, err error) {
+				require.NoError(t, err)
+				require.False(t, out.Valid)
+				require.Equal(t, "Validation error count 1:\n\t- #/required: required properties are missing: name", out.Error)
+			},
+		},
+		{
+			name: "raw JSON for AsyncAPI schema",
+			in:   mcp.ValidatePayloadInput{Api: "orders", Schema: "Order", Raw: `{"id":"foo"}`},
+			test: func(t *testing.T, out mcp.ValidatePayloadOutput, err error) {
+				require.NoError(t, err)
+				require.False(t, out.Valid)
+				require.Equal(t, "Validation error count 1:\n\t- #/id/type: invalid type, expected integer but got string", out.Error)
+			},
+		},
+		{
+			name: "base64 encoded Avro",
+			in: mcp.ValidatePayloadInput{
+				Api:         "orders",
+				Schema:      "Avro",
+				Raw:         base64.StdEncoding.EncodeToString([]byte{0x06, 'f', 'o', 'o'}),
+				Base64:      true,
+				ContentType: "avro/binary",
+			},
+			test: func(t *testing.T, out mcp.ValidatePayloadOutput, err error) {
+				require.NoError(t, err)
+				require.True(t, out.Valid)
+				require.Equal(t, "foo", out.Value)
+			},
+		},
+		{
+			name: "schema not found",
+			in:   mcp.ValidatePayloadInput{Api: "petstore", Schema: "Foo"},
+			test: func(t *testing.T, out mcp.ValidatePayloadOutput, err error) {
+				require.EqualError(t, err, "schema 'Foo' not found in API 'petstore'")
+			},
+		},
+		{
+			name: "API not found",
+			in:   mcp.ValidatePayloadInput{Api: "foo", Schema: "Foo"},
+			test: func(t *testing.T, out mcp.ValidatePayloadOutput, err error) {
+				require.EqualError(t, err, "API 'foo' not found. Use 'mokapi_list_apis' to list all APIs")
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := mcp.NewService(app)
+			out, err := s.ValidatePayload(context.Background(), tc.in)
+			tc.test(t, out, err)
+		})
+	}
+}
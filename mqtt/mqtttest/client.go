@@ -1,6 +1,7 @@
 package mqtttest
 
 import (
+	"crypto/tls"
 	"mokapi/mqtt"
 	"net"
 	"time"
@@ -9,6 +10,8 @@ import (
 type Client struct {
 	Addr    string
 	Timeout time.Duration
+	// TLSConfig enables TLS when dialing the server
+	TLSConfig *tls.Config
 
 	conn net.Conn
 }
@@ -68,7 +71,11 @@ func (c *Client) ensureConnection() error {
 	if c.conn == nil {
 		for i := 0; i < 10; i++ {
 			d := net.Dialer{Timeout: c.Timeout}
-			c.conn, err = d.Dial("tcp", c.Addr)
+			if c.TLSConfig != nil {
+				c.conn, err = tls.DialWithDialer(&d, "tcp", c.Addr, c.TLSConfig)
+			} else {
+				c.conn, err = d.Dial("tcp", c.Addr)
+			}
 			if err != nil {
 				time.Sleep(backoff)
 				continue
@@ -46,13 +46,20 @@ func (p Properties) Write(e *Encoder) {
 	var b bytes.Buffer
 	propBuffer := NewEncoder(&b, e.protocolVersion)
 	for id, val := range p {
-		propBuffer.writeByte(id)
-
 		switch v := val.(type) {
 		case string:
+			propBuffer.writeByte(id)
 			propBuffer.writeString(v)
 		case int32:
+			propBuffer.writeByte(id)
 			propBuffer.writeInt32(v)
+		case map[string]string:
+			// user property is a string pair and may appear multiple times
+			for key, s := range v {
+				propBuffer.writeByte(id)
+				propBuffer.writeString(key)
+				propBuffer.writeString(s)
+			}
 		}
 	}
 
@@ -81,3 +88,14 @@ func (p Properties) ReasonString() string {
 	}
 	return v.(string)
 }
+
+func (p Properties) UserProperties() map[string]string {
+	if p == nil {
+		return nil
+	}
+	v, ok := p[UserProperty]
+	if !ok {
+		return nil
+	}
+	return v.(map[string]string)
+}
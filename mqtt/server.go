@@ -2,6 +2,7 @@ package mqtt
 
 import (
 	"context"
+	"crypto/tls"
 	"io"
 	"mokapi/safe"
 	"net"
@@ -30,8 +31,9 @@ type MessageWriter interface {
 }
 
 type Server struct {
-	Addr    string
-	Handler Handler
+	Addr      string
+	Handler   Handler
+	TLSConfig *tls.Config
 
 	mu         sync.Mutex
 	closeChan  chan bool
@@ -69,6 +71,10 @@ func (s *Server) Serve(l net.Listener) error {
 			}
 		}
 
+		if s.TLSConfig != nil {
+			conn = tls.Server(conn, s.TLSConfig)
+		}
+
 		ctx := s.trackConn(conn)
 		go s.serve(conn, ctx)
 	}
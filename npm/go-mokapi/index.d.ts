@@ -4,4 +4,6 @@
 /// <reference path="types/kafka.d.ts" />
 /// <reference path="types/index.d.ts" />
 /// <reference path="types/mustache.d.ts" />
-/// <reference path="types/yaml.d.ts" />
\ No newline at end of file
+/// <reference path="types/yaml.d.ts" />
+/// <reference path="types/websocket.d.ts" />
+/// <reference path="types/verify.d.ts" />
\ No newline at end of file
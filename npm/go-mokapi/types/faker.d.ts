@@ -2,15 +2,29 @@
  * Creates a fake based on the given schema
  * https://mokapi.io/docs/javascript-api/mokapi-faker/fake
  * @param schema schema - OpenAPI Schema object contains definition of a type
+ * @param options options - Options like the locale of the generated data
  * @example
  * export default function() {
  *   console.log(fake({type: 'string'}))
  *   console.log(fake({type: 'number'}))
  *   console.log(fake({type: 'string', format: 'date-time'}))
  *   console.log(fake({type: 'string', pattern: '^\d{3}-\d{2}-\d{4}$'})) // 123-45-6789
+ *   console.log(fake({type: 'object', properties: {name: {}, city: {}}}, {locale: 'de-DE'}))
  * }
  */
-export function fake(schema: Schema | JSONSchema): any;
+export function fake(schema: Schema | JSONSchema, options?: FakeOptions): any;
+
+/**
+ * Options for generating fake data.
+ */
+export interface FakeOptions {
+    /**
+     * The locale of generated names, addresses, phone numbers etc.,
+     * e.g. de-DE. Supported locales are en, de, fr and ja.
+     * Defaults to the locale of the data generator configuration.
+     */
+    locale?: string;
+}
 
 /**
  * Retrieves a node from the faker tree by its name or path.
@@ -161,6 +175,11 @@ export interface Request {
      * address using a previously generated first and last name.
      */
     context: Context;
+
+    /**
+     * The requested locale, e.g. de-DE, if any.
+     */
+    locale?: string;
 }
 
 /**
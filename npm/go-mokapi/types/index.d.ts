@@ -25,7 +25,7 @@ import "./file";
  * Multiple handlers can be registered for the same event.
  *
  * https://mokapi.io/docs/javascript-api/mokapi/on
- * @param event Event type such as `http`, `kafka`, `ldap`, or `smtp`
+ * @param event Event type such as `http`, `kafka`, `mqtt`, `ldap`, or `smtp`
  * @param handler Function executed when the event is triggered
  * @param args Optional event configuration such as priority, tracking, or tags
  * @example
@@ -115,8 +115,10 @@ export type Interval = string;
 export interface EventHandler {
     http: HttpEventHandler;
     kafka: KafkaEventHandler;
+    mqtt: MqttEventHandler;
     ldap: LdapEventHandler;
     smtp: SmtpEventHandler;
+    websocket: WebSocketEventHandler;
 }
 
 /**
@@ -179,6 +181,12 @@ export interface HttpRequest {
     /** OperationId defined in OpenAPI */
     readonly operationId: string;
 
+    /** Names of the security schemes of the satisfied OpenAPI security requirement */
+    readonly security: string[];
+
+    /** Authenticated user, such as the basic auth username or the subject of a JWT bearer token */
+    readonly principal: string;
+
     /** Returns a string representing this HttpRequest object.  */
     toString(): string;
 }
@@ -224,6 +232,26 @@ export interface HttpResponse {
      * })
      */
     rebuild: (statusCode?: number, contentType?: string) => void;
+
+    /**
+     * Writes the response as a stream of events, for example for
+     * text/event-stream or application/x-ndjson. Events of multiple
+     * calls are appended.
+     *
+     * @param events An event or an array of events. For Server-Sent Events,
+     *               an object with the fields id, event, data and retry is
+     *               written as such an event.
+     * @param opts.interval Time in milliseconds between two events.
+     *
+     * @example
+     * on('http', (request, response) => {
+     *   response.stream([
+     *     { event: 'token', data: 'Hello' },
+     *     { event: 'token', data: 'World' }
+     *   ], { interval: 200 })
+     * })
+     */
+    stream: (events: any | any[], opts?: { interval?: number }) => void;
 }
 
 /**
@@ -260,7 +288,7 @@ export interface Url {
  *   })
  * }
  */
-export type KafkaEventHandler = (message: KafkaEventMessage) => void | Promise<void>;
+export type KafkaEventHandler = (message: KafkaEventMessage, context: KafkaEventContext) => void | Promise<void>;
 
 /**
  * KafkaEventMessage is an object used by KafkaEventHandler that contains Kafka-specific message data.
@@ -280,6 +308,118 @@ export interface KafkaEventMessage {
     headers: { [name: string]: string } | null;
 }
 
+/**
+ * KafkaEventContext contains information about the producer that triggered the event.
+ */
+export interface KafkaEventContext {
+    /** Name of the Kafka topic */
+    readonly topic: string;
+
+    /** Index of the partition the message is written to */
+    readonly partition: number;
+
+    /** Client identifier of the producer */
+    readonly clientId: string;
+
+    /** User authenticated via SASL, empty if the broker does not require authentication */
+    readonly principal: string;
+}
+
+/**
+ * MqttEventHandler is a function that is executed when a client publishes an MQTT message.
+ * https://mokapi.io/docs/javascript-api/mokapi/eventhandler/MqttEventHandler
+ * @example
+ * export default function() {
+ *   on('mqtt', function(message, context) {
+ *     // drop all messages of a client
+ *     if (context.clientId === 'noisy-sensor') {
+ *       message.drop = true
+ *     }
+ *   })
+ * }
+ */
+export type MqttEventHandler = (message: MqttEventMessage, context: MqttEventContext) => void | Promise<void>;
+
+/**
+ * MqttEventMessage is an object used by MqttEventHandler that contains MQTT-specific message data.
+ * https://mokapi.io/docs/javascript-api/mokapi/eventhandler/MqttEventMessage
+ */
+export interface MqttEventMessage {
+    /** Topic name the message is published to */
+    topic: string;
+
+    /** Message payload */
+    payload: string;
+
+    /** Quality of service level */
+    qos: 0 | 1 | 2;
+
+    /** Whether the broker retains the message */
+    retain: boolean;
+
+    /** MQTT 5 user properties */
+    userProperties: { [name: string]: string };
+
+    /** If true, the message is acknowledged but neither retained nor delivered to subscribers */
+    drop: boolean;
+}
+
+/**
+ * MqttEventContext contains information about the client that triggered the event.
+ */
+export interface MqttEventContext {
+    /** Name of the MQTT API */
+    readonly api: string;
+
+    /** Client identifier of the publishing client */
+    readonly clientId: string;
+
+    /** Type of the MQTT packet */
+    readonly type: 'publish';
+}
+
+/**
+ * WebSocketEventHandler is a function that is executed when a client sends a WebSocket message.
+ * https://mokapi.io/docs/javascript-api/mokapi/eventhandler/WebSocketEventHandler
+ * @example
+ * import { send } from 'mokapi/websocket'
+ *
+ * export default function() {
+ *   on('websocket', function(message, context) {
+ *     send(context.sessionId, message.data)
+ *   })
+ * }
+ */
+export type WebSocketEventHandler = (message: WebSocketEventMessage, context: WebSocketEventContext) => void | Promise<void>;
+
+/**
+ * WebSocketEventMessage is an object used by WebSocketEventHandler that contains the received message.
+ */
+export interface WebSocketEventMessage {
+    /** Name of the channel the client is connected to */
+    readonly channel: string;
+
+    /** Message data as received from the client */
+    readonly data: string;
+}
+
+/**
+ * WebSocketEventContext contains information about the session that received the message.
+ */
+export interface WebSocketEventContext {
+    /** Name of the AsyncAPI specification */
+    readonly api: string;
+
+    /** Id of the WebSocket session, can be used with send of mokapi/websocket */
+    readonly sessionId: string;
+
+    /** Request path the client connected to */
+    readonly path: string;
+
+    /** IP address of the client */
+    readonly clientIP: string;
+}
+
 /**
  * LdapEventHandler is a function that is executed when a LDAP search query is triggered.
  * @example
@@ -517,6 +657,10 @@ export interface TypedEventArgs {
      * Arguments for Kafka event handlers.
      */
     kafka: KafkaEventArgs;
+    /**
+     * Arguments for MQTT event handlers.
+     */
+    mqtt: MqttEventArgs;
     /**
      * Arguments for LDAP event handlers.
      */
@@ -525,6 +669,10 @@ export interface TypedEventArgs {
      * Arguments for SMTP event handlers.
      */
     smtp: SmtpEventArgs;
+    /**
+     * Arguments for WebSocket event handlers.
+     */
+    websocket: WebSocketEventArgs;
 }
 
 /**
@@ -563,6 +711,24 @@ export interface KafkaEventArgs extends EventArgs {
     track?: boolean | ((message: KafkaEventMessage) => boolean);
 }
 
+/**
+ * Configuration options for MQTT event handlers.
+ *
+ * These arguments control execution behavior such as
+ * priority, tagging, and dashboard tracking.
+ */
+export interface MqttEventArgs extends EventArgs {
+    /**
+     * Controls whether this event handler is tracked in the dashboard.
+     *
+     * - true: always track this handler
+     * - false: never track this handler
+     * - undefined: Mokapi determines tracking automatically based on
+     *   whether the message was modified by the handler
+     */
+    track?: boolean | ((message: MqttEventMessage, context: MqttEventContext) => boolean);
+}
+
 /**
  * Configuration options for LDAP event handlers.
  *
@@ -599,6 +765,23 @@ export interface SmtpEventArgs extends EventArgs {
     track?: boolean | ((record: SmtpEventMessage) => boolean);
 }
 
+/**
+ * Configuration options for WebSocket event handlers.
+ *
+ * These arguments control execution behavior such as
+ * priority, tagging, and dashboard tracking.
+ */
+export interface WebSocketEventArgs extends EventArgs {
+    /**
+     * Controls whether this event handler is tracked in the dashboard.
+     *
+     * - true: always track this handler
+     * - false: never track this handler
+     * - undefined: Mokapi determines tracking automatically
+     */
+    track?: boolean | ((message: WebSocketEventMessage, context: WebSocketEventContext) => boolean);
+}
+
 /**
  * ScheduledEventHandler is an object used by every and cron function.
  * https://mokapi.io/docs/javascript-api/mokapi/eventhandler/scheduledeventargs
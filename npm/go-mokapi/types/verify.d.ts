@@ -0,0 +1,79 @@
+/**
+ * Returns the number of recorded events and the events matching the matcher, newest first.
+ * https://mokapi.io/docs/javascript-api/mokapi-verify/verify
+ * @param matcher - Selects the events. At most one protocol matcher can be specified.
+ * @example
+ * import { verify } from 'mokapi/verify'
+ *
+ * export default function() {
+ *   const result = verify({ http: { method: 'POST', path: '/orders' } })
+ *   console.log(result.count)
+ * }
+ */
+export function verify(matcher: Matcher): VerifyResult;
+
+/**
+ * Removes all recorded events matching the traits and returns the number of removed events.
+ * https://mokapi.io/docs/javascript-api/mokapi-verify/reset
+ * @param traits - Traits of the events to remove. Without traits, all events are removed.
+ * @example
+ * import { reset } from 'mokapi/verify'
+ *
+ * export default function() {
+ *   reset({ namespace: 'http', name: 'Petstore' })
+ * }
+ */
+export function reset(traits?: { [name: string]: string }): number;
+
+export interface Matcher {
+    /** Limits events by their traits, e.g. namespace and name */
+    traits?: { [name: string]: string };
+    http?: HttpMatcher;
+    kafka?: KafkaMatcher;
+    mail?: MailMatcher;
+    ldap?: LdapMatcher;
+}
+
+export interface HttpMatcher {
+    api?: string;
+    method?: string;
+    /** Request path or path of the specification. Wildcards like /pets/* are supported. */
+    path?: string;
+    headers?: { [name: string]: string };
+    statusCode?: number;
+    /** JSONPath expressions with their expected values, e.g. { '$.status': 'open' } */
+    body?: { [jsonPath: string]: any };
+}
+
+export interface KafkaMatcher {
+    cluster?: string;
+    topic?: string;
+    key?: string;
+    headers?: { [name: string]: string };
+}
+
+export interface MailMatcher {
+    server?: string;
+    from?: string;
+    /** Matches if any recipient equals the address */
+    to?: string;
+    subject?: string;
+}
+
+export interface LdapMatcher {
+    server?: string;
+    operation?: string;
+    dn?: string;
+}
+
+export interface VerifyResult {
+    count: number;
+    events: VerifiedEvent[];
+}
+
+export interface VerifiedEvent {
+    id: string;
+    traits: { [name: string]: string };
+    data: any;
+    time: string;
+}
@@ -0,0 +1,49 @@
+import { JSONValue } from ".";
+
+/**
+ * Sends data to all clients connected to a WebSocket channel.
+ * https://mokapi.io/docs/javascript-api/mokapi-websocket/broadcast
+ * @param channel - Channel name, address or request path the clients are connected to.
+ * @param data - Data to send. Strings are sent as they are, other values are encoded as JSON.
+ * @param options - Optional send options.
+ * @returns The number of clients the data was sent to.
+ * @example
+ * import { broadcast } from 'mokapi/websocket'
+ *
+ * export default function() {
+ *   every('10s', function() {
+ *     broadcast('rooms/{roomId}', { text: 'ping from mokapi' })
+ *   })
+ * }
+ */
+export function broadcast(channel: string, data: JSONValue | ArrayBuffer, options?: SendOptions): number;
+
+/**
+ * Sends data to the client of a single WebSocket session.
+ * https://mokapi.io/docs/javascript-api/mokapi-websocket/send
+ * @param sessionId - Id of the session, available in the context of the websocket event.
+ * @param data - Data to send. Strings are sent as they are, other values are encoded as JSON.
+ * @param options - Optional send options.
+ * @returns true if the session was found and the data was sent.
+ * @example
+ * import { on } from 'mokapi'
+ * import { send } from 'mokapi/websocket'
+ *
+ * export default function() {
+ *   on('websocket', function(message, context) {
+ *     send(context.sessionId, { echo: message.data })
+ *   })
+ * }
+ */
+export function send(sessionId: string, data: JSONValue | ArrayBuffer, options?: SendOptions): boolean;
+
+/**
+ * SendOptions controls how data is sent to WebSocket clients.
+ */
+export interface SendOptions {
+    /** Name of the AsyncAPI specification. If omitted, all specifications are searched. */
+    api?: string;
+
+    /** If true, the data is sent as binary frame instead of a text frame. */
+    binary?: boolean;
+}
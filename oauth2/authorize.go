@@ -0,0 +1,183 @@
+package oauth2
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+var loginPage = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Mokapi Login</title></head>
+<body>
+<h1>Sign in to {{.ClientId}}</h1>
+{{if .Error}}<p style="color:red">{{.Error}}</p>{{end}}
+<form method="post">
+{{range $k, $v := .Params}}<input type="hidden" name="{{$k}}" value="{{$v}}">
+{{end}}<label>Username <input name="username" value="{{.Username}}" autofocus></label><br>
+<label>Password <input name="password" type="password"></label><br>
+<button type="submit">Sign in</button>
+</form>
+</body>
+</html>`))
+
+type loginData struct {
+	ClientId string
+	Username string
+	Error    string
+	Params   map[string]string
+}
+
+// authorizeParams are the parameters that are passed through the login form
+var authorizeParams = []string{
+	"response_type", "client_id", "redirect_uri", "scope", "state",
+	"nonce", "code_challenge", "code_challenge_method", "login_hint",
+}
+
+// serveAuthorize handles the authorization endpoint. If users are
+// configured, the resource owner must sign in using a login form,
+// otherwise the request is approved immediately.
+func (s *Server) serveAuthorize(rw http.ResponseWriter, r *http.Request, issuer string) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		rw.Header().Set("Allow", "GET, POST")
+		writeError(rw, newError(http.StatusMethodNotAllowed, "invalid_request", "authorization endpoint requires GET or POST"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(rw, newError(http.StatusBadRequest, "invalid_request", "%v", err))
+		return
+	}
+
+	clientId := r.Form.Get("client_id")
+	client, ok := s.findClient(clientId)
+	if clientId == "" || !ok {
+		// must not redirect to an unverified redirect URI (RFC 6749, section 4.1.2.1)
+		writeError(rw, newError(http.StatusBadRequest, "invalid_client", "unknown client '%s'", clientId))
+		return
+	}
+	redirectUri := r.Form.Get("redirect_uri")
+	if !isRedirectUriAllowed(client, redirectUri) {
+		writeError(rw, newError(http.StatusBadRequest, "invalid_request", "redirect_uri '%s' is not registered", redirectUri))
+		return
+	}
+
+	state := r.Form.Get("state")
+	if rt := r.Form.Get("response_type"); rt != "code" {
+		redirect(rw, r, redirectUri, url.Values{
+			"error":             {"unsupported_response_type"},
+			"error_description": {"response type '" + rt + "' is not supported"},
+		}, state)
+		return
+	}
+	if method := r.Form.Get("code_challenge_method"); method != "" && method != "S256" && method != "plain" {
+		redirect(rw, r, redirectUri, url.Values{
+			"error":             {"invalid_request"},
+			"error_description": {"code challenge method '" + method + "' is not supported"},
+		}, state)
+		return
+	}
+
+	subject := r.Form.Get("login_hint")
+	if len(s.Users) > 0 {
+		if r.Method == http.MethodGet {
+			s.renderLogin(rw, r, client, "", http.StatusOK)
+			return
+		}
+		username := r.PostForm.Get("username")
+		u := s.findUser(username)
+		if u == nil || u.Password != r.PostForm.Get("password") {
+			s.renderLogin(rw, r, client, "invalid username or password", http.StatusUnauthorized)
+			return
+		}
+		subject = u.Username
+	}
+	if subject == "" {
+		subject = defaultSubject
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":          issuer,
+		"sub":          subject,
+		"aud":          client.ClientId,
+		"iat":          now.Unix(),
+		"exp":          now.Add(codeLifetime).Unix(),
+		"redirect_uri": redirectUri,
+		"typ":          tokenTypeCode,
+		"jti":          newCodeId(),
+	}
+	for _, name := range []string{"scope", "nonce", "code_challenge", "code_challenge_method"} {
+		if v := r.Form.Get(name); v != "" {
+			claims[name] = v
+		}
+	}
+	code, err := s.sign(claims)
+	if err != nil {
+		writeError(rw, newError(http.StatusInternalServerError, "server_error", "%v", err))
+		return
+	}
+	s.codeStore().Add(code, now.Add(codeLifetime))
+
+	log.Infof("oauth2: issued authorization code for '%v' to client '%v'", subject, client.ClientId)
+	redirect(rw, r, redirectUri, url.Values{"code": {code}}, state)
+}
+
+func (s *Server) renderLogin(rw http.ResponseWriter, r *http.Request, client *Client, msg string, status int) {
+	data := loginData{
+		ClientId: client.ClientId,
+		Username: r.Form.Get("username"),
+		Error:    msg,
+		Params:   map[string]string{},
+	}
+	if data.Username == "" {
+		data.Username = r.Form.Get("login_hint")
+	}
+	for _, name := range authorizeParams {
+		if v := r.Form.Get(name); v != "" {
+			data.Params[name] = v
+		}
+	}
+
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	rw.WriteHeader(status)
+	if err := loginPage.Execute(rw, data); err != nil {
+		log.Errorf("oauth2: render login page failed: %v", err)
+	}
+}
+
+func isRedirectUriAllowed(client *Client, redirectUri string) bool {
+	if redirectUri == "" {
+		return false
+	}
+	if len(client.RedirectUris) == 0 {
+		u, err := url.Parse(redirectUri)
+		return err == nil && u.IsAbs()
+	}
+	return slices.Contains(client.RedirectUris, redirectUri)
+}
+
+func redirect(rw http.ResponseWriter, r *http.Request, redirectUri string, params url.Values, state string) {
+	if state != "" {
+		params.Set("state", state)
+	}
+	sep := "?"
+	if strings.Contains(redirectUri, "?") {
+		sep = "&"
+	}
+	http.Redirect(rw, r, redirectUri+sep+params.Encode(), http.StatusFound)
+}
+
+// newCodeId returns a random ID, so that each authorization code is unique
+func newCodeId() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
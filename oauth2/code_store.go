@@ -0,0 +1,63 @@
+package oauth2
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrCodeUnknown = errors.New("authorization code is unknown")
+	ErrCodeUsed    = errors.New("authorization code has already been used")
+	ErrCodeExpired = errors.New("authorization code has expired")
+)
+
+// CodeStore remembers issued authorization codes, so that each code can be
+// exchanged only once (RFC 6749, section 4.1.2). A store can be shared by
+// servers that issue codes for the same API.
+type CodeStore struct {
+	m     sync.Mutex
+	codes map[string]*issuedCode
+}
+
+type issuedCode struct {
+	expires time.Time
+	used    bool
+}
+
+func NewCodeStore() *CodeStore {
+	return &CodeStore{codes: map[string]*issuedCode{}}
+}
+
+func (s *CodeStore) Add(code string, expires time.Time) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	now := time.Now()
+	for c, ic := range s.codes {
+		if now.After(ic.expires) {
+			delete(s.codes, c)
+		}
+	}
+	s.codes[code] = &issuedCode{expires: expires}
+}
+
+// Redeem marks the code as used. Used codes are kept until they expire
+// to tell a second exchange apart from an unknown code.
+func (s *CodeStore) Redeem(code string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	ic, ok := s.codes[code]
+	switch {
+	case !ok:
+		return ErrCodeUnknown
+	case ic.used:
+		return ErrCodeUsed
+	case time.Now().After(ic.expires):
+		delete(s.codes, code)
+		return ErrCodeExpired
+	}
+	ic.used = true
+	return nil
+}
@@ -0,0 +1,272 @@
+package oauth2
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DiscoveryPath = "/.well-known/openid-configuration"
+	JwksPath      = "/.well-known/jwks.json"
+	UserInfoPath  = "/userinfo"
+
+	defaultSubject = "mokapi"
+)
+
+type Client struct {
+	ClientId     string
+	ClientSecret string
+	RedirectUris []string
+}
+
+type User struct {
+	Username string
+	Password string
+	Claims   map[string]any
+}
+
+// Endpoints contains the URLs of the authorization and token endpoint.
+// A relative URL is resolved against the issuer.
+type Endpoints struct {
+	Authorization []string
+	Token         []string
+}
+
+// Server is a mock OAuth2 authorization server and OpenID Connect provider.
+// Authorization codes and refresh tokens are signed JWTs. Issued codes are
+// also remembered in Codes, so that each code can be exchanged only once.
+type Server struct {
+	Key       *rsa.PrivateKey
+	Endpoints Endpoints
+	Clients   []Client
+	Users     []User
+	Scopes    []string
+	// TokenLifetime of issued access and ID tokens. Default is one hour
+	TokenLifetime time.Duration
+	// Codes stores the issued authorization codes. If nil, the server
+	// uses its own store.
+	Codes *CodeStore
+
+	m sync.Mutex
+}
+
+type Error struct {
+	StatusCode  int    `json:"-"`
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+func newError(status int, code, format string, args ...any) *Error {
+	return &Error{StatusCode: status, Code: code, Description: fmt.Sprintf(format, args...)}
+}
+
+// Match reports whether the path is one of the server's endpoints.
+// requestPath is the path relative to the issuer and fullPath the
+// path of the request URL.
+func (s *Server) Match(requestPath, fullPath string) bool {
+	return s.route(requestPath, fullPath) != nil
+}
+
+// ServeHTTP serves the endpoint matching the request path. The issuer
+// is the URL of the API the server belongs to.
+func (s *Server) ServeHTTP(rw http.ResponseWriter, r *http.Request, issuer, requestPath string) {
+	h := s.route(requestPath, r.URL.Path)
+	if h == nil {
+		http.NotFound(rw, r)
+		return
+	}
+	h(rw, r, strings.TrimRight(issuer, "/"))
+}
+
+type endpointHandler func(rw http.ResponseWriter, r *http.Request, issuer string)
+
+func (s *Server) route(requestPath, fullPath string) endpointHandler {
+	switch requestPath {
+	case DiscoveryPath:
+		return s.serveDiscovery
+	case JwksPath:
+		return s.serveJwks
+	case UserInfoPath:
+		return s.serveUserInfo
+	}
+	if matchEndpoint(s.Endpoints.Authorization, requestPath, fullPath) {
+		return s.serveAuthorize
+	}
+	if matchEndpoint(s.Endpoints.Token, requestPath, fullPath) {
+		return s.serveToken
+	}
+	return nil
+}
+
+func (s *Server) serveDiscovery(rw http.ResponseWriter, _ *http.Request, issuer string) {
+	doc := map[string]any{
+		"issuer":                                issuer,
+		"jwks_uri":                              issuer + JwksPath,
+		"userinfo_endpoint":                     issuer + UserInfoPath,
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{"authorization_code", "client_credentials", "refresh_token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+		"code_challenge_methods_supported":      []string{"S256", "plain"},
+	}
+	if len(s.Endpoints.Authorization) > 0 {
+		doc["authorization_endpoint"] = resolve(issuer, s.Endpoints.Authorization[0])
+	}
+	if len(s.Endpoints.Token) > 0 {
+		doc["token_endpoint"] = resolve(issuer, s.Endpoints.Token[0])
+	}
+	if len(s.Scopes) > 0 {
+		doc["scopes_supported"] = s.Scopes
+	}
+	writeJson(rw, http.StatusOK, doc)
+}
+
+func (s *Server) serveJwks(rw http.ResponseWriter, _ *http.Request, _ string) {
+	pub := s.Key.PublicKey
+	writeJson(rw, http.StatusOK, map[string]any{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"use": "sig",
+				"alg": "RS256",
+				"kid": s.keyId(),
+				"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			},
+		},
+	})
+}
+
+func (s *Server) serveUserInfo(rw http.ResponseWriter, r *http.Request, issuer string) {
+	scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !strings.EqualFold(scheme, "bearer") || token == "" {
+		rw.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		writeError(rw, newError(http.StatusUnauthorized, "invalid_token", "missing bearer token"))
+		return
+	}
+	claims, err := s.parse(token, tokenTypeAccess, issuer)
+	if err != nil {
+		rw.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		writeError(rw, newError(http.StatusUnauthorized, "invalid_token", "%v", err))
+		return
+	}
+
+	sub, _ := claims["sub"].(string)
+	info := map[string]any{"sub": sub}
+	if u := s.findUser(sub); u != nil {
+		for k, v := range u.Claims {
+			info[k] = v
+		}
+	}
+	writeJson(rw, http.StatusOK, info)
+}
+
+func (s *Server) findUser(username string) *User {
+	for i := range s.Users {
+		if s.Users[i].Username == username {
+			return &s.Users[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) findClient(clientId string) (*Client, bool) {
+	if len(s.Clients) == 0 {
+		// any client is accepted
+		return &Client{ClientId: clientId}, true
+	}
+	for i := range s.Clients {
+		if s.Clients[i].ClientId == clientId {
+			return &s.Clients[i], true
+		}
+	}
+	return nil, false
+}
+
+// keyId returns the JWK thumbprint (RFC 7638) of the signing key
+func (s *Server) keyId() string {
+	pub := s.Key.PublicKey
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	n := base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+	h := sha256.Sum256([]byte(fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, e, n)))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+func (s *Server) codeStore() *CodeStore {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.Codes == nil {
+		s.Codes = NewCodeStore()
+	}
+	return s.Codes
+}
+
+func (s *Server) tokenLifetime() time.Duration {
+	if s.TokenLifetime > 0 {
+		return s.TokenLifetime
+	}
+	return time.Hour
+}
+
+func matchEndpoint(endpoints []string, requestPath, fullPath string) bool {
+	for _, e := range endpoints {
+		p, absolute := endpointPath(e)
+		if (absolute && p == fullPath) || (!absolute && p == requestPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// endpointPath returns the path of the endpoint URL and whether the URL
+// is absolute and therefore independent of the issuer.
+func endpointPath(endpoint string) (string, bool) {
+	if i := strings.Index(endpoint, "://"); i > 0 {
+		rest := endpoint[i+3:]
+		if j := strings.IndexAny(rest, "/?"); j >= 0 && rest[j] == '/' {
+			p, _, _ := strings.Cut(rest[j:], "?")
+			return p, true
+		}
+		return "/", true
+	}
+	p, _, _ := strings.Cut(endpoint, "?")
+	return "/" + strings.TrimPrefix(p, "/"), false
+}
+
+func resolve(issuer, endpoint string) string {
+	if strings.Contains(endpoint, "://") {
+		return endpoint
+	}
+	p, _ := endpointPath(endpoint)
+	return issuer + p
+}
+
+func writeJson(rw http.ResponseWriter, status int, v any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.WriteHeader(status)
+	if err := json.NewEncoder(rw).Encode(v); err != nil {
+		log.Errorf("oauth2: write response failed: %v", err)
+	}
+}
+
+func writeError(rw http.ResponseWriter, err *Error) {
+	log.Infof("oauth2: %v", err)
+	writeJson(rw, err.StatusCode, err)
+}
@@ -0,0 +1,544 @@
+package oauth2_test
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"mokapi/config/static"
+	"mokapi/oauth2"
+	"mokapi/server/cert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+)
+
+const issuer = "http://localhost/api"
+
+func newServer(t *testing.T) *oauth2.Server {
+	store, err := cert.NewStore(&static.Config{})
+	require.NoError(t, err)
+	key, err := store.SigningKey()
+	require.NoError(t, err)
+	return &oauth2.Server{
+		Key: key,
+		Endpoints: oauth2.Endpoints{
+			Authorization: []string{"/oauth/authorize"},
+			Token:         []string{"https://auth.example.com/oauth/token"},
+		},
+		Clients: []oauth2.Client{
+			{ClientId: "spa", RedirectUris: []string{"http://localhost:3000/callback"}},
+			{ClientId: "backend", ClientSecret: "secret"},
+		},
+		Scopes: []string{"openid", "read"},
+	}
+}
+
+func serve(s *oauth2.Server, r *http.Request, path string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	s.ServeHTTP(rr, r, issuer, path)
+	return rr
+}
+
+func postToken(s *oauth2.Server, form url.Values, setup func(r *http.Request)) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "https://auth.example.com/oauth/token", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if setup != nil {
+		setup(r)
+	}
+	return serve(s, r, "/oauth/token")
+}
+
+func authorize(t *testing.T, s *oauth2.Server, query url.Values) *url.URL {
+	r := httptest.NewRequest(http.MethodGet, "http://localhost/api/oauth/authorize?"+query.Encode(), nil)
+	rr := serve(s, r, "/oauth/authorize")
+	require.Equal(t, http.StatusFound, rr.Code, rr.Body.String())
+	u, err := url.Parse(rr.Header().Get("Location"))
+	require.NoError(t, err)
+	return u
+}
+
+func decode(t *testing.T, rr *httptest.ResponseRecorder) map[string]any {
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &m), rr.Body.String())
+	return m
+}
+
+func verify(t *testing.T, s *oauth2.Server, token string) jwt.MapClaims {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(_ *jwt.Token) (any, error) {
+		return &s.Key.PublicKey, nil
+	})
+	require.NoError(t, err)
+	return claims
+}
+
+func TestServer_Match(t *testing.T) {
+	s := newServer(t)
+
+	require.True(t, s.Match(oauth2.DiscoveryPath, "/api"+oauth2.DiscoveryPath))
+	require.True(t, s.Match(oauth2.JwksPath, "/api"+oauth2.JwksPath))
+	require.True(t, s.Match(oauth2.UserInfoPath, "/api"+oauth2.UserInfoPath))
+	require.True(t, s.Match("/oauth/authorize", "/api/oauth/authorize"))
+	// absolute token URL is matched against the request URL
+	require.True(t, s.Match("/token", "/oauth/token"))
+	require.False(t, s.Match("/oauth/token", "/api/oauth/token"))
+	require.False(t, s.Match("/pets", "/api/pets"))
+}
+
+func TestServer_Discovery(t *testing.T) {
+	s := newServer(t)
+
+	rr := serve(s, httptest.NewRequest(http.MethodGet, issuer+oauth2.DiscoveryPath, nil), oauth2.DiscoveryPath)
+	require.Equal(t, http.StatusOK, rr.Code)
+	doc := decode(t, rr)
+	require.Equal(t, issuer, doc["issuer"])
+	require.Equal(t, "http://localhost/api/oauth/authorize", doc["authorization_endpoint"])
+	require.Equal(t, "https://auth.example.com/oauth/token", doc["token_endpoint"])
+	require.Equal(t, "http://localhost/api/.well-known/jwks.json", doc["jwks_uri"])
+	require.Equal(t, "http://localhost/api/userinfo", doc["userinfo_endpoint"])
+	require.Equal(t, []any{"openid", "read"}, doc["scopes_supported"])
+}
+
+func TestServer_Jwks(t *testing.T) {
+	s := newServer(t)
+
+	rr := serve(s, httptest.NewRequest(http.MethodGet, issuer+oauth2.JwksPath, nil), oauth2.JwksPath)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &jwks))
+	require.Len(t, jwks.Keys, 1)
+
+	n, err := base64.RawURLEncoding.DecodeString(jwks.Keys[0].N)
+	require.NoError(t, err)
+	e, err := base64.RawURLEncoding.DecodeString(jwks.Keys[0].E)
+	require.NoError(t, err)
+	pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	require.True(t, pub.Equal(&s.Key.PublicKey))
+
+	// issued tokens reference the key
+	res := decode(t, postToken(s, url.Values{"grant_type": {"client_credentials"}}, func(r *http.Request) {
+		r.SetBasicAuth("backend", "secret")
+	}))
+	token, _, err := jwt.NewParser().ParseUnverified(res["access_token"].(string), jwt.MapClaims{})
+	require.NoError(t, err)
+	require.Equal(t, jwks.Keys[0].Kid, token.Header["kid"])
+}
+
+func TestServer_AuthorizationCode(t *testing.T) {
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	h := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(h[:])
+
+	testcases := []struct {
+		name string
+		test func(t *testing.T, s *oauth2.Server)
+	}{
+		{
+			name: "authorization code with PKCE",
+			test: func(t *testing.T, s *oauth2.Server) {
+				u := authorize(t, s, url.Values{
+					"response_type":         {"code"},
+					"client_id":             {"spa"},
+					"redirect_uri":          {"http://localhost:3000/callback"},
+					"scope":                 {"openid read"},
+					"state":                 {"xyz"},
+					"nonce":                 {"n-0S6"},
+					"code_challenge":        {challenge},
+					"code_challenge_method": {"S256"},
+					"login_hint":            {"alice"},
+				})
+				require.Equal(t, "localhost:3000", u.Host)
+				require.Equal(t, "xyz", u.Query().Get("state"))
+
+				rr := postToken(s, url.Values{
+					"grant_type":    {"authorization_code"},
+					"client_id":     {"spa"},
+					"code":          {u.Query().Get("code")},
+					"redirect_uri":  {"http://localhost:3000/callback"},
+					"code_verifier": {verifier},
+				}, nil)
+				require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+				res := decode(t, rr)
+				require.Equal(t, "Bearer", res["token_type"])
+				require.Equal(t, float64(3600), res["expires_in"])
+				require.Equal(t, "openid read", res["scope"])
+				require.NotEmpty(t, res["refresh_token"])
+
+				access := verify(t, s, res["access_token"].(string))
+				require.Equal(t, "alice", access["sub"])
+				require.Equal(t, issuer, access["iss"])
+				require.Equal(t, "openid read", access["scope"])
+
+				id := verify(t, s, res["id_token"].(string))
+				require.Equal(t, "alice", id["sub"])
+				require.Equal(t, "spa", id["aud"])
+				require.Equal(t, "n-0S6", id["nonce"])
+			},
+		},
+		{
+			name: "code can be used only once",
+			test: func(t *testing.T, s *oauth2.Server) {
+				u := authorize(t, s, url.Values{
+					"response_type": {"code"},
+					"client_id":     {"spa"},
+					"redirect_uri":  {"http://localhost:3000/callback"},
+				})
+				form := url.Values{
+					"grant_type":   {"authorization_code"},
+					"client_id":    {"spa"},
+					"code":         {u.Query().Get("code")},
+					"redirect_uri": {"http://localhost:3000/callback"},
+				}
+				rr := postToken(s, form, nil)
+				require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+				rr = postToken(s, form, nil)
+				require.Equal(t, http.StatusBadRequest, rr.Code)
+				res := decode(t, rr)
+				require.Equal(t, "invalid_grant", res["error"])
+				require.Equal(t, "authorization code has already been used", res["error_description"])
+			},
+		},
+		{
+			name: "code of another server",
+			test: func(t *testing.T, s *oauth2.Server) {
+				u := authorize(t, s, url.Values{
+					"response_type": {"code"},
+					"client_id":     {"spa"},
+					"redirect_uri":  {"http://localhost:3000/callback"},
+				})
+				other := &oauth2.Server{Key: s.Key, Endpoints: s.Endpoints, Clients: s.Clients}
+				rr := postToken(other, url.Values{
+					"grant_type":   {"authorization_code"},
+					"client_id":    {"spa"},
+					"code":         {u.Query().Get("code")},
+					"redirect_uri": {"http://localhost:3000/callback"},
+				}, nil)
+				require.Equal(t, http.StatusBadRequest, rr.Code)
+				res := decode(t, rr)
+				require.Equal(t, "invalid_grant", res["error"])
+				require.Equal(t, "authorization code is unknown", res["error_description"])
+			},
+		},
+		{
+			name: "code of another server sharing the code store",
+			test: func(t *testing.T, s *oauth2.Server) {
+				s.Codes = oauth2.NewCodeStore()
+				u := authorize(t, s, url.Values{
+					"response_type": {"code"},
+					"client_id":     {"spa"},
+					"redirect_uri":  {"http://localhost:3000/callback"},
+				})
+				other := &oauth2.Server{Key: s.Key, Endpoints: s.Endpoints, Clients: s.Clients, Codes: s.Codes}
+				rr := postToken(other, url.Values{
+					"grant_type":   {"authorization_code"},
+					"client_id":    {"spa"},
+					"code":         {u.Query().Get("code")},
+					"redirect_uri": {"http://localhost:3000/callback"},
+				}, nil)
+				require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+			},
+		},
+		{
+			name: "invalid code verifier",
+			test: func(t *testing.T, s *oauth2.Server) {
+				u := authorize(t, s, url.Values{
+					"response_type":         {"code"},
+					"client_id":             {"spa"},
+					"redirect_uri":          {"http://localhost:3000/callback"},
+					"code_challenge":        {challenge},
+					"code_challenge_method": {"S256"},
+				})
+				rr := postToken(s, url.Values{
+					"grant_type":    {"authorization_code"},
+					"client_id":     {"spa"},
+					"code":          {u.Query().Get("code")},
+					"redirect_uri":  {"http://localhost:3000/callback"},
+					"code_verifier": {"foo"},
+				}, nil)
+				require.Equal(t, http.StatusBadRequest, rr.Code)
+				require.Equal(t, "invalid_grant", decode(t, rr)["error"])
+			},
+		},
+		{
+			name: "code issued to another client",
+			test: func(t *testing.T, s *oauth2.Server) {
+				u := authorize(t, s, url.Values{
+					"response_type": {"code"},
+					"client_id":     {"spa"},
+					"redirect_uri":  {"http://localhost:3000/callback"},
+				})
+				rr := postToken(s, url.Values{
+					"grant_type":   {"authorization_code"},
+					"code":         {u.Query().Get("code")},
+					"redirect_uri": {"http://localhost:3000/callback"},
+				}, func(r *http.Request) {
+					r.SetBasicAuth("backend", "secret")
+				})
+				require.Equal(t, http.StatusBadRequest, rr.Code)
+				require.Equal(t, "invalid_grant", decode(t, rr)["error"])
+			},
+		},
+		{
+			name: "unregistered redirect uri",
+			test: func(t *testing.T, s *oauth2.Server) {
+				q := url.Values{
+					"response_type": {"code"},
+					"client_id":     {"spa"},
+					"redirect_uri":  {"http://evil.example.com"},
+				}
+				r := httptest.NewRequest(http.MethodGet, issuer+"/oauth/authorize?"+q.Encode(), nil)
+				rr := serve(s, r, "/oauth/authorize")
+				require.Equal(t, http.StatusBadRequest, rr.Code)
+				require.Empty(t, rr.Header().Get("Location"))
+			},
+		},
+		{
+			name: "unsupported response type",
+			test: func(t *testing.T, s *oauth2.Server) {
+				u := authorize(t, s, url.Values{
+					"response_type": {"token"},
+					"client_id":     {"spa"},
+					"redirect_uri":  {"http://localhost:3000/callback"},
+					"state":         {"xyz"},
+				})
+				require.Equal(t, "unsupported_response_type", u.Query().Get("error"))
+				require.Equal(t, "xyz", u.Query().Get("state"))
+			},
+		},
+		{
+			name: "login form",
+			test: func(t *testing.T, s *oauth2.Server) {
+				s.Users = []oauth2.User{{Username: "bob", Password: "pw", Claims: map[string]any{"email": "bob@example.com"}}}
+				q := url.Values{
+					"response_type": {"code"},
+					"client_id":     {"spa"},
+					"redirect_uri":  {"http://localhost:3000/callback"},
+					"scope":         {"openid"},
+				}
+				r := httptest.NewRequest(http.MethodGet, issuer+"/oauth/authorize?"+q.Encode(), nil)
+				rr := serve(s, r, "/oauth/authorize")
+				require.Equal(t, http.StatusOK, rr.Code)
+				require.Contains(t, rr.Body.String(), `<input type="hidden" name="client_id" value="spa">`)
+
+				q.Set("username", "bob")
+				q.Set("password", "wrong")
+				r = httptest.NewRequest(http.MethodPost, issuer+"/oauth/authorize", strings.NewReader(q.Encode()))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				rr = serve(s, r, "/oauth/authorize")
+				require.Equal(t, http.StatusUnauthorized, rr.Code)
+				require.Contains(t, rr.Body.String(), "invalid username or password")
+
+				q.Set("password", "pw")
+				r = httptest.NewRequest(http.MethodPost, issuer+"/oauth/authorize", strings.NewReader(q.Encode()))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				rr = serve(s, r, "/oauth/authorize")
+				require.Equal(t, http.StatusFound, rr.Code)
+				u, _ := url.Parse(rr.Header().Get("Location"))
+
+				rr = postToken(s, url.Values{
+					"grant_type":   {"authorization_code"},
+					"client_id":    {"spa"},
+					"code":         {u.Query().Get("code")},
+					"redirect_uri": {"http://localhost:3000/callback"},
+				}, nil)
+				require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+				id := verify(t, s, decode(t, rr)["id_token"].(string))
+				require.Equal(t, "bob", id["sub"])
+				require.Equal(t, "bob@example.com", id["email"])
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, newServer(t))
+		})
+	}
+}
+
+func TestServer_ClientCredentials(t *testing.T) {
+	testcases := []struct {
+		name   string
+		form   url.Values
+		setup  func(r *http.Request)
+		status int
+		test   func(t *testing.T, s *oauth2.Server, res map[string]any)
+	}{
+		{
+			name:   "basic authentication",
+			form:   url.Values{"grant_type": {"client_credentials"}, "scope": {"read"}},
+			setup:  func(r *http.Request) { r.SetBasicAuth("backend", "secret") },
+			status: http.StatusOK,
+			test: func(t *testing.T, s *oauth2.Server, res map[string]any) {
+				require.Nil(t, res["refresh_token"])
+				claims := verify(t, s, res["access_token"].(string))
+				require.Equal(t, "backend", claims["sub"])
+				require.Equal(t, "read", claims["scope"])
+			},
+		},
+		{
+			name: "form parameters",
+			form: url.Values{
+				"grant_type":    {"client_credentials"},
+				"client_id":     {"backend"},
+				"client_secret": {"secret"},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "invalid secret",
+			form:   url.Values{"grant_type": {"client_credentials"}},
+			setup:  func(r *http.Request) { r.SetBasicAuth("backend", "foo") },
+			status: http.StatusUnauthorized,
+			test: func(t *testing.T, _ *oauth2.Server, res map[string]any) {
+				require.Equal(t, "invalid_client", res["error"])
+			},
+		},
+		{
+			name:   "public client",
+			form:   url.Values{"grant_type": {"client_credentials"}, "client_id": {"spa"}},
+			status: http.StatusUnauthorized,
+			test: func(t *testing.T, _ *oauth2.Server, res map[string]any) {
+				require.Equal(t, "invalid_client", res["error"])
+				require.Equal(t, "client authentication required", res["error_description"])
+			},
+		},
+		{
+			name: "public client with made-up secret",
+			form: url.Values{
+				"grant_type":    {"client_credentials"},
+				"client_id":     {"spa"},
+				"client_secret": {"foo"},
+			},
+			status: http.StatusUnauthorized,
+			test: func(t *testing.T, _ *oauth2.Server, res map[string]any) {
+				require.Equal(t, "invalid_client", res["error"])
+				require.Equal(t, "client 'spa' has no secret", res["error_description"])
+			},
+		},
+		{
+			name:   "public client with made-up secret using basic authentication",
+			form:   url.Values{"grant_type": {"client_credentials"}},
+			setup:  func(r *http.Request) { r.SetBasicAuth("spa", "foo") },
+			status: http.StatusUnauthorized,
+			test: func(t *testing.T, _ *oauth2.Server, res map[string]any) {
+				require.Equal(t, "invalid_client", res["error"])
+			},
+		},
+		{
+			name:   "unsupported grant type",
+			form:   url.Values{"grant_type": {"password"}},
+			setup:  func(r *http.Request) { r.SetBasicAuth("backend", "secret") },
+			status: http.StatusBadRequest,
+			test: func(t *testing.T, _ *oauth2.Server, res map[string]any) {
+				require.Equal(t, "unsupported_grant_type", res["error"])
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newServer(t)
+			rr := postToken(s, tc.form, tc.setup)
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+			if tc.test != nil {
+				tc.test(t, s, decode(t, rr))
+			}
+		})
+	}
+}
+
+func TestServer_RefreshToken(t *testing.T) {
+	s := newServer(t)
+	u := authorize(t, s, url.Values{
+		"response_type": {"code"},
+		"client_id":     {"spa"},
+		"redirect_uri":  {"http://localhost:3000/callback"},
+		"scope":         {"read write"},
+	})
+	res := decode(t, postToken(s, url.Values{
+		"grant_type":   {"authorization_code"},
+		"client_id":    {"spa"},
+		"code":         {u.Query().Get("code")},
+		"redirect_uri": {"http://localhost:3000/callback"},
+	}, nil))
+
+	rr := postToken(s, url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {"spa"},
+		"refresh_token": {res["refresh_token"].(string)},
+		"scope":         {"read"},
+	}, nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	claims := verify(t, s, decode(t, rr)["access_token"].(string))
+	require.Equal(t, "mokapi", claims["sub"])
+	require.Equal(t, "read", claims["scope"])
+
+	rr = postToken(s, url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {"spa"},
+		"refresh_token": {res["refresh_token"].(string)},
+		"scope":         {"admin"},
+	}, nil)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Equal(t, "invalid_scope", decode(t, rr)["error"])
+
+	// an access token is not a refresh token
+	rr = postToken(s, url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {"spa"},
+		"refresh_token": {res["access_token"].(string)},
+	}, nil)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestServer_UserInfo(t *testing.T) {
+	s := newServer(t)
+	s.Users = []oauth2.User{{Username: "alice", Claims: map[string]any{"name": "Alice"}}}
+	s.Clients = nil
+
+	rr := serve(s, httptest.NewRequest(http.MethodGet, issuer+oauth2.UserInfoPath, nil), oauth2.UserInfoPath)
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	require.Equal(t, `Bearer error="invalid_token"`, rr.Header().Get("WWW-Authenticate"))
+
+	q := url.Values{
+		"response_type": {"code"},
+		"client_id":     {"any"},
+		"redirect_uri":  {"http://localhost:3000/callback"},
+		"scope":         {"openid"},
+		"username":      {"alice"},
+	}
+	r := httptest.NewRequest(http.MethodPost, issuer+"/oauth/authorize", strings.NewReader(q.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr = serve(s, r, "/oauth/authorize")
+	require.Equal(t, http.StatusFound, rr.Code, rr.Body.String())
+	u, _ := url.Parse(rr.Header().Get("Location"))
+
+	res := decode(t, postToken(s, url.Values{
+		"grant_type":   {"authorization_code"},
+		"client_id":    {"any"},
+		"code":         {u.Query().Get("code")},
+		"redirect_uri": {"http://localhost:3000/callback"},
+	}, nil))
+
+	r = httptest.NewRequest(http.MethodGet, issuer+oauth2.UserInfoPath, nil)
+	r.Header.Set("Authorization", "Bearer "+res["access_token"].(string))
+	rr = serve(s, r, oauth2.UserInfoPath)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, map[string]any{"sub": "alice", "name": "Alice"}, decode(t, rr))
+}
@@ -0,0 +1,290 @@
+package oauth2
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	tokenTypeAccess  = "access"
+	tokenTypeCode    = "code"
+	tokenTypeRefresh = "refresh"
+
+	codeLifetime    = 5 * time.Minute
+	refreshLifetime = 24 * time.Hour
+)
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IdToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// grant is the result of a successful authorization
+type grant struct {
+	client  *Client
+	subject string
+	scope   string
+	nonce   string
+}
+
+func (s *Server) serveToken(rw http.ResponseWriter, r *http.Request, issuer string) {
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		writeError(rw, newError(http.StatusMethodNotAllowed, "invalid_request", "token endpoint requires POST"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(rw, newError(http.StatusBadRequest, "invalid_request", "%v", err))
+		return
+	}
+
+	client, authenticated, oErr := s.authenticateClient(r)
+	if oErr != nil {
+		writeError(rw, oErr)
+		return
+	}
+
+	var g *grant
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		g, oErr = s.grantAuthorizationCode(r, client, issuer)
+	case "client_credentials":
+		if !authenticated {
+			oErr = newError(http.StatusUnauthorized, "invalid_client", "client authentication required")
+		} else {
+			g = &grant{client: client, subject: client.ClientId, scope: r.PostForm.Get("scope")}
+		}
+	case "refresh_token":
+		g, oErr = s.grantRefreshToken(r, client, issuer)
+	case "":
+		oErr = newError(http.StatusBadRequest, "invalid_request", "missing grant_type")
+	default:
+		oErr = newError(http.StatusBadRequest, "unsupported_grant_type", "grant type '%s' is not supported", r.PostForm.Get("grant_type"))
+	}
+	if oErr != nil {
+		writeError(rw, oErr)
+		return
+	}
+
+	res, err := s.issue(g, issuer)
+	if err != nil {
+		writeError(rw, newError(http.StatusInternalServerError, "server_error", "%v", err))
+		return
+	}
+	writeJson(rw, http.StatusOK, res)
+}
+
+// authenticateClient identifies the client by HTTP basic authentication or
+// by the form parameters client_id and client_secret. Public clients
+// without a configured secret only need to provide their client_id and are
+// never authenticated, unless no clients are configured.
+func (s *Server) authenticateClient(r *http.Request) (*Client, bool, *Error) {
+	clientId, secret, hasBasic := r.BasicAuth()
+	if !hasBasic {
+		clientId = r.PostForm.Get("client_id")
+		secret = r.PostForm.Get("client_secret")
+	}
+	if clientId == "" {
+		return nil, false, newError(http.StatusUnauthorized, "invalid_client", "missing client_id")
+	}
+
+	client, ok := s.findClient(clientId)
+	if !ok {
+		return nil, false, newError(http.StatusUnauthorized, "invalid_client", "unknown client '%s'", clientId)
+	}
+	if len(s.Clients) == 0 {
+		// without configured clients any client is accepted
+		return client, true, nil
+	}
+	if client.ClientSecret == "" {
+		if secret != "" {
+			return nil, false, newError(http.StatusUnauthorized, "invalid_client", "client '%s' has no secret", clientId)
+		}
+		return client, false, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(client.ClientSecret), []byte(secret)) != 1 {
+		return nil, false, newError(http.StatusUnauthorized, "invalid_client", "invalid client credentials")
+	}
+	return client, true, nil
+}
+
+func (s *Server) grantAuthorizationCode(r *http.Request, client *Client, issuer string) (*grant, *Error) {
+	claims, err := s.parse(r.PostForm.Get("code"), tokenTypeCode, issuer)
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "invalid_grant", "invalid authorization code: %v", err)
+	}
+	if claims["aud"] != client.ClientId {
+		return nil, newError(http.StatusBadRequest, "invalid_grant", "authorization code was issued to another client")
+	}
+	if err := s.codeStore().Redeem(r.PostForm.Get("code")); err != nil {
+		return nil, newError(http.StatusBadRequest, "invalid_grant", "%v", err)
+	}
+	if redirectUri, _ := claims["redirect_uri"].(string); redirectUri != r.PostForm.Get("redirect_uri") {
+		return nil, newError(http.StatusBadRequest, "invalid_grant", "redirect_uri does not match")
+	}
+
+	if challenge, _ := claims["code_challenge"].(string); challenge != "" {
+		verifier := r.PostForm.Get("code_verifier")
+		if verifier == "" {
+			return nil, newError(http.StatusBadRequest, "invalid_grant", "missing code_verifier")
+		}
+		method, _ := claims["code_challenge_method"].(string)
+		if !verifyCodeChallenge(challenge, method, verifier) {
+			return nil, newError(http.StatusBadRequest, "invalid_grant", "invalid code_verifier")
+		}
+	}
+
+	g := &grant{client: client}
+	g.subject, _ = claims["sub"].(string)
+	g.scope, _ = claims["scope"].(string)
+	g.nonce, _ = claims["nonce"].(string)
+	return g, nil
+}
+
+func (s *Server) grantRefreshToken(r *http.Request, client *Client, issuer string) (*grant, *Error) {
+	claims, err := s.parse(r.PostForm.Get("refresh_token"), tokenTypeRefresh, issuer)
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "invalid_grant", "invalid refresh token: %v", err)
+	}
+	if claims["aud"] != client.ClientId {
+		return nil, newError(http.StatusBadRequest, "invalid_grant", "refresh token was issued to another client")
+	}
+
+	g := &grant{client: client}
+	g.subject, _ = claims["sub"].(string)
+	g.scope, _ = claims["scope"].(string)
+
+	// the client may request a subset of the original scopes
+	if requested := r.PostForm.Get("scope"); requested != "" {
+		granted := strings.Fields(g.scope)
+		for _, scope := range strings.Fields(requested) {
+			if !slices.Contains(granted, scope) {
+				return nil, newError(http.StatusBadRequest, "invalid_scope", "scope '%s' was not granted", scope)
+			}
+		}
+		g.scope = requested
+	}
+	return g, nil
+}
+
+func (s *Server) issue(g *grant, issuer string) (*tokenResponse, error) {
+	now := time.Now()
+	lifetime := s.tokenLifetime()
+
+	access := jwt.MapClaims{
+		"iss":       issuer,
+		"sub":       g.subject,
+		"aud":       g.client.ClientId,
+		"client_id": g.client.ClientId,
+		"iat":       now.Unix(),
+		"exp":       now.Add(lifetime).Unix(),
+		"typ":       tokenTypeAccess,
+	}
+	if g.scope != "" {
+		access["scope"] = g.scope
+	}
+	accessToken, err := s.sign(access)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &tokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(lifetime.Seconds()),
+		Scope:       g.scope,
+	}
+
+	// client credentials are not issued a refresh token (RFC 6749, section 4.4.3)
+	if g.subject != g.client.ClientId {
+		res.RefreshToken, err = s.sign(jwt.MapClaims{
+			"iss":   issuer,
+			"sub":   g.subject,
+			"aud":   g.client.ClientId,
+			"iat":   now.Unix(),
+			"exp":   now.Add(refreshLifetime).Unix(),
+			"scope": g.scope,
+			"typ":   tokenTypeRefresh,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if slices.Contains(strings.Fields(g.scope), "openid") {
+		id := jwt.MapClaims{}
+		if u := s.findUser(g.subject); u != nil {
+			for k, v := range u.Claims {
+				id[k] = v
+			}
+		}
+		id["iss"] = issuer
+		id["sub"] = g.subject
+		id["aud"] = g.client.ClientId
+		id["iat"] = now.Unix()
+		id["exp"] = now.Add(lifetime).Unix()
+		if g.nonce != "" {
+			id["nonce"] = g.nonce
+		}
+		res.IdToken, err = s.sign(id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func (s *Server) sign(claims jwt.MapClaims) (string, error) {
+	t := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	t.Header["kid"] = s.keyId()
+	return t.SignedString(s.Key)
+}
+
+// parse verifies the token signature, expiry and issuer and returns its claims
+func (s *Server) parse(token, typ, issuer string) (jwt.MapClaims, error) {
+	if token == "" {
+		return nil, fmt.Errorf("token is empty")
+	}
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		return &s.Key.PublicKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if claims["typ"] != typ {
+		return nil, fmt.Errorf("unexpected token type")
+	}
+	if !claims.VerifyIssuer(issuer, true) {
+		return nil, fmt.Errorf("token was issued by another issuer")
+	}
+	return claims, nil
+}
+
+func verifyCodeChallenge(challenge, method, verifier string) bool {
+	switch method {
+	case "S256":
+		h := sha256.Sum256([]byte(verifier))
+		return base64.RawURLEncoding.EncodeToString(h[:]) == challenge
+	case "", "plain":
+		return verifier == challenge
+	default:
+		return false
+	}
+}
@@ -10,6 +10,7 @@ func RegisterApiFlags(cmd *cli.Command) {
 	cmd.Flags().Bool("api-search-enabled", true, apiSearch)
 	cmd.Flags().String("api-search-index-path", "", apiSearchIndexPath)
 	cmd.Flags().Bool("api-search-in-memory", false, apiSearchInMemory)
+	cmd.Flags().Bool("api-config-write", false, apiConfigWrite)
 }
 
 var apiPort = cli.FlagDoc{
@@ -146,3 +147,20 @@ This option is recommended only for small projects or development environments.`
 		},
 	},
 }
+
+var apiConfigWrite = cli.FlagDoc{
+	Short: "Allow uploading, replacing and deleting configs through the API",
+	Long: `Enables the endpoints POST, PUT and DELETE /api/configs to change mock configurations at runtime.
+
+Uploaded configs are kept in memory and are lost when Mokapi restarts.
+Only enable this option in trusted environments, because uploaded JavaScript files are executed by Mokapi.`,
+	Examples: []cli.Example{
+		{
+			Codes: []cli.Code{
+				{Title: "CLI", Source: "--api-config-write"},
+				{Title: "Env", Source: "MOKAPI_API_CONFIG_WRITE=true"},
+				{Title: "File", Source: "api:\n  configWrite: true"},
+			},
+		},
+	},
+}
@@ -6,6 +6,10 @@ func RegisterEventStoreFlags(cmd *cli.Command) {
 	cmd.Flags().Int("event-store-default-size", 100, eventStoreDefaultSize)
 	cmd.Flags().String("event-store", "", eventStore)
 	cmd.Flags().DynamicInt("event-store-<name>-size", eventStoreName)
+	cmd.Flags().String("event-storage-type", "memory", eventStorageType)
+	cmd.Flags().String("event-storage-path", "", eventStoragePath)
+	cmd.Flags().String("event-storage-max-age", "", eventStorageMaxAge)
+	cmd.Flags().Int("event-storage-max-size", 0, eventStorageMaxSize)
 }
 
 var eventStoreDefaultSize = cli.FlagDoc{
@@ -52,3 +56,60 @@ The event store keeps track of events produced by mocked APIs, allowing them to
 		},
 	},
 }
+
+var eventStorageType = cli.FlagDoc{
+	Short: "Where events are stored: memory or file",
+	Long: `Selects the storage backend of the event store.
+With memory (default), events are lost when Mokapi restarts. With file, events are appended to a log file in the directory given by --event-storage-path and restored on startup, including the search index.`,
+	Examples: []cli.Example{
+		{
+			Codes: []cli.Code{
+				{Title: "CLI", Source: "--event-storage-type file"},
+				{Title: "Env", Source: "MOKAPI_EVENT_STORAGE_TYPE=file"},
+				{Title: "File", Source: "event:\n  storage:\n    type: file", Language: "yaml"},
+			},
+		},
+	},
+}
+
+var eventStoragePath = cli.FlagDoc{
+	Short: "Directory of the persistent event log",
+	Long:  `Specifies the directory where events are written when the event storage type is file. The directory is created if it does not exist.`,
+	Examples: []cli.Example{
+		{
+			Codes: []cli.Code{
+				{Title: "CLI", Source: "--event-storage-path ./data/events"},
+				{Title: "Env", Source: "MOKAPI_EVENT_STORAGE_PATH=./data/events"},
+				{Title: "File", Source: "event:\n  storage:\n    path: ./data/events", Language: "yaml"},
+			},
+		},
+	},
+}
+
+var eventStorageMaxAge = cli.FlagDoc{
+	Short: "Discard stored events older than this duration",
+	Long:  `Events older than the given duration (e.g. 72h) are discarded from memory and disk. By default, events are kept until the store size limit is reached.`,
+	Examples: []cli.Example{
+		{
+			Codes: []cli.Code{
+				{Title: "CLI", Source: "--event-storage-max-age 72h"},
+				{Title: "Env", Source: "MOKAPI_EVENT_STORAGE_MAX_AGE=72h"},
+				{Title: "File", Source: "event:\n  storage:\n    maxAge: 72h", Language: "yaml"},
+			},
+		},
+	},
+}
+
+var eventStorageMaxSize = cli.FlagDoc{
+	Short: "Maximum size of the event log in bytes",
+	Long:  `Limits the size of the event log on disk. When the limit is reached, the oldest events are discarded. 0 means no limit.`,
+	Examples: []cli.Example{
+		{
+			Codes: []cli.Code{
+				{Title: "CLI", Source: "--event-storage-max-size 104857600"},
+				{Title: "Env", Source: "MOKAPI_EVENT_STORAGE_MAX_SIZE=104857600"},
+				{Title: "File", Source: "event:\n  storage:\n    maxSize: 104857600", Language: "yaml"},
+			},
+		},
+	},
+}
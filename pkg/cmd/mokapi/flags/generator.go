@@ -4,6 +4,8 @@ import "mokapi/pkg/cli"
 
 func RegisterDataGeneratorFlags(cmd *cli.Command) {
 	cmd.Flags().String("data-gen-optional-properties", "0.85", generatorOptionalProperties)
+	cmd.Flags().Bool("data-gen-deterministic", false, generatorDeterministic)
+	cmd.Flags().String("data-gen-locale", "en", generatorLocale)
 }
 
 var generatorOptionalProperties = cli.FlagDoc{
@@ -28,3 +30,39 @@ Higher values result in more optional properties being present in generated data
 		},
 	},
 }
+
+var generatorDeterministic = cli.FlagDoc{
+	Short: "Generate the same data for the same request",
+	Long: `Seeds the data generator from the API name, the endpoint path and the path parameters.
+Repeated requests like GET /pets/12 return the same generated response, while GET /pets/13
+returns different data. This applies to HTTP responses generated from an OpenAPI specification.
+
+Individual operations can override this setting with the extension x-deterministic.`,
+	Examples: []cli.Example{
+		{
+			Codes: []cli.Code{
+				{Title: "CLI", Source: "--data-gen-deterministic"},
+				{Title: "Env", Source: "MOKAPI_DATA_GEN_DETERMINISTIC=true"},
+				{Title: "File", Source: "data-gen:\n  deterministic: true", Language: "yaml"},
+			},
+		},
+	},
+}
+
+var generatorLocale = cli.FlagDoc{
+	Short: "Locale of generated data",
+	Long: `Sets the language and region of generated names, addresses, postal codes, phone numbers,
+company names and IBANs. Supported locales are en, de, fr and ja. Regions like de-AT or fr-CH
+use the data of their language.
+
+For HTTP responses, a supported locale in the request's Accept-Language header takes precedence.`,
+	Examples: []cli.Example{
+		{
+			Codes: []cli.Code{
+				{Title: "CLI", Source: "--data-gen-locale de-DE"},
+				{Title: "Env", Source: "MOKAPI_DATA_GEN_LOCALE=de-DE"},
+				{Title: "File", Source: "data-gen:\n  locale: de-DE", Language: "yaml"},
+			},
+		},
+	},
+}
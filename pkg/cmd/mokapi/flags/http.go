@@ -0,0 +1,25 @@
+package flags
+
+import "mokapi/pkg/cli"
+
+func RegisterHttpFlags(cmd *cli.Command) {
+	cmd.Flags().Bool("http-strict-security", false, httpStrictSecurity)
+}
+
+var httpStrictSecurity = cli.FlagDoc{
+	Short: "Reject HTTP requests that do not satisfy the OpenAPI security requirements",
+	Long: `By default, Mokapi only logs requests that do not satisfy the security requirements of an operation.
+When enabled, such requests are rejected with 401 Unauthorized and a WWW-Authenticate header, or with
+403 Forbidden if the bearer token does not grant the required scopes.
+Credentials are validated against the values defined with the extensions x-users (HTTP basic) and x-keys (API key).
+Strict mode can also be enabled for a single API with the OpenAPI extension x-strictSecurity.`,
+	Examples: []cli.Example{
+		{
+			Codes: []cli.Code{
+				{Title: "CLI", Source: "--http-strict-security"},
+				{Title: "Env", Source: "MOKAPI_HTTP_STRICT_SECURITY=true"},
+				{Title: "File", Source: "http:\n  strictSecurity: true", Language: "yaml"},
+			},
+		},
+	},
+}
@@ -0,0 +1,24 @@
+package flags
+
+import "mokapi/pkg/cli"
+
+func RegisterKafkaFlags(cmd *cli.Command) {
+	cmd.Flags().String("kafka-data-dir", "", kafkaDataDir)
+}
+
+var kafkaDataDir = cli.FlagDoc{
+	Short: "Directory where Kafka topics, consumer group offsets and producer states are stored",
+	Long: `By default, Kafka records are kept in memory and lost when Mokapi restarts or a config reload recreates a topic.
+When set, the segments of each partition are written as files to this directory together with the committed offsets
+of consumer groups and the state of idempotent and transactional producers. The data is restored when the cluster
+is started again. Segments removed by the log retention are deleted from disk as well.`,
+	Examples: []cli.Example{
+		{
+			Codes: []cli.Code{
+				{Title: "CLI", Source: "--kafka-data-dir ./data/kafka"},
+				{Title: "Env", Source: "MOKAPI_KAFKA_DATA_DIR=./data/kafka"},
+				{Title: "File", Source: "kafka:\n  dataDir: ./data/kafka", Language: "yaml"},
+			},
+		},
+	},
+}
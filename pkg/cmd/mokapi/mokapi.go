@@ -68,6 +68,8 @@ func NewCmdMokapi() *cli.Command {
 	flags.RegisterTlsFlags(cmd)
 	flags.RegisterEventStoreFlags(cmd)
 	flags.RegisterDataGeneratorFlags(cmd)
+	flags.RegisterHttpFlags(cmd)
+	flags.RegisterKafkaFlags(cmd)
 
 	cmd.Flags().StringSlice("config", []string{}, true, cli.FlagDoc{Short: "Provide inline configuration data"})
 	cmd.Flags().StringSlice("configs", []string{}, false, cli.FlagDoc{Short: "Provide inline configuration data"})
@@ -142,6 +144,7 @@ func createServer(cfg *static.Config) (*server.Server, error) {
 	http := server.NewHttpManager(scriptEngine, certStore, app)
 
 	apiHandler := api.New(app, cfg.Api)
+	apiHandler.RegisterConfigProvider(watcher.Memory())
 	if urls, err := api.BuildUrl(cfg.Api); err == nil {
 		for _, u := range urls {
 			err = http.AddInternalService("api", u, apiHandler)
@@ -180,8 +183,8 @@ func createServer(cfg *static.Config) (*server.Server, error) {
 		}
 	}
 
-	kafka := server.NewKafkaManager(scriptEngine, app)
-	mqtt := server.NewMqttManager(scriptEngine, app)
+	kafka := server.NewKafkaManager(scriptEngine, certStore, app)
+	mqtt := server.NewMqttManager(scriptEngine, certStore, app)
 	mailManager := server.NewMailManager(app, scriptEngine, certStore)
 	ldap := server.NewLdapDirectoryManager(scriptEngine, certStore, app)
 
@@ -196,6 +199,7 @@ func createServer(cfg *static.Config) (*server.Server, error) {
 		}
 		app.UpdateConfig(e)
 	})
+	watcher.AddErrorListener(app.SetConfigError)
 
 	return server.NewServer(pool, app, watcher, kafka, http, mailManager, ldap, scriptEngine), nil
 }
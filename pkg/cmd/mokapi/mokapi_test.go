@@ -81,6 +81,20 @@ func TestMain_Flags(t *testing.T) {
 				require.Equal(t, int64(250), cfg.Event.Store["foo"].Size)
 			},
 		},
+		{
+			name: "--event-storage",
+			args: []string{"--event-storage-type", "file", "--event-storage-path", "/tmp/events", "--event-storage-max-age", "72h", "--event-storage-max-size", "1048576"},
+			test: func(t *testing.T, cfg *static.Config) {
+				require.Equal(t, static.EventStorage{Type: "file", Path: "/tmp/events", MaxAge: "72h", MaxSize: 1048576}, cfg.Event.Storage)
+			},
+		},
+		{
+			name: "--kafka-data-dir",
+			args: []string{"--kafka-data-dir", "/tmp/kafka"},
+			test: func(t *testing.T, cfg *static.Config) {
+				require.Equal(t, "/tmp/kafka", cfg.Kafka.DataDir)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -386,6 +400,15 @@ func TestStaticConfig(t *testing.T) {
 				require.Equal(t, 0.3, cfg.DataGen.OptionalPropertiesProbability())
 			},
 		},
+		{
+			name: "http strict security",
+			args: []string{
+				"--http-strict-security",
+			},
+			test: func(t *testing.T, cfg *static.Config) {
+				require.True(t, cfg.Http.StrictSecurity)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
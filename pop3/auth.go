@@ -0,0 +1,58 @@
+package pop3
+
+import (
+	"crypto/subtle"
+	"strings"
+)
+
+func (c *conn) handleUser(args []string) error {
+	if len(args) != 1 {
+		return c.err("syntax: USER name")
+	}
+	c.username = args[0]
+	return c.ok("send PASS")
+}
+
+func (c *conn) handlePass(password string) error {
+	if c.username == "" {
+		return c.err("send USER first")
+	}
+	username := c.username
+	c.username = ""
+	return c.login(username, func(stored string) bool {
+		return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+	})
+}
+
+func (c *conn) handleApop(args []string) error {
+	if len(args) != 2 {
+		return c.err("syntax: APOP name digest")
+	}
+	digest := strings.ToLower(args[1])
+	return c.login(args[0], func(stored string) bool {
+		return subtle.ConstantTimeCompare([]byte(apopDigest(c.timestamp, stored)), []byte(digest)) == 1
+	})
+}
+
+func (c *conn) login(username string, verify func(password string) bool) error {
+	if c.handler == nil {
+		return c.err("[AUTH] invalid credentials")
+	}
+	err := c.handler.Authorize(username, verify, c.ctx)
+	if err != nil {
+		return c.err("[AUTH] %v", err)
+	}
+
+	messages, err := c.handler.Maildrop(c.ctx)
+	if err != nil {
+		return c.err("[SYS/TEMP] unable to open maildrop: %v", err)
+	}
+
+	ctx := ClientFromContext(c.ctx)
+	ctx.Username = username
+	c.messages = messages
+	c.deleted = map[int]bool{}
+	c.state = TransactionState
+
+	return c.ok("maildrop has %d messages (%d octets)", len(c.messages), c.maildropSize())
+}
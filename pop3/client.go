@@ -0,0 +1,120 @@
+package pop3
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+type Client struct {
+	Addr    string
+	Timeout time.Duration
+
+	tpc      *textproto.Conn
+	conn     net.Conn
+	greeting string
+}
+
+func NewClient(addr string) *Client {
+	return &Client{Addr: addr, Timeout: time.Second * 10}
+}
+
+func (c *Client) Dial() (string, error) {
+	return c.dial(func(d *net.Dialer) (net.Conn, error) {
+		return d.Dial("tcp", c.Addr)
+	})
+}
+
+func (c *Client) DialTls(cfg *tls.Config) (string, error) {
+	return c.dial(func(d *net.Dialer) (net.Conn, error) {
+		return tls.DialWithDialer(d, "tcp", c.Addr, cfg)
+	})
+}
+
+func (c *Client) dial(dial func(d *net.Dialer) (net.Conn, error)) (string, error) {
+	var err error
+	backoff := 50 * time.Millisecond
+	if c.conn == nil {
+		for i := 0; i < 10; i++ {
+			c.conn, err = dial(&net.Dialer{Timeout: c.Timeout})
+			if err != nil {
+				time.Sleep(backoff)
+				continue
+			}
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = c.conn.SetDeadline(time.Now().Add(time.Second * 5))
+	if err != nil {
+		return "", fmt.Errorf("unable to set deadline: %v", err)
+	}
+	c.tpc = textproto.NewConn(c.conn)
+	c.greeting, err = c.tpc.ReadLine()
+	return c.greeting, err
+}
+
+// Send sends a command and returns the single-line response.
+func (c *Client) Send(line string) (string, error) {
+	err := c.tpc.PrintfLine("%s", line)
+	if err != nil {
+		return "", err
+	}
+	return c.tpc.ReadLine()
+}
+
+// SendMulti sends a command and returns the status line and the lines of a
+// multi-line response. The lines are only read if the status is positive.
+func (c *Client) SendMulti(line string) (string, []string, error) {
+	status, err := c.Send(line)
+	if err != nil || !strings.HasPrefix(status, "+OK") {
+		return status, nil, err
+	}
+	lines, err := c.tpc.ReadDotLines()
+	return status, lines, err
+}
+
+func (c *Client) Login(username, password string) (string, error) {
+	res, err := c.Send(fmt.Sprintf("USER %s", username))
+	if err != nil || !strings.HasPrefix(res, "+OK") {
+		return res, err
+	}
+	return c.Send(fmt.Sprintf("PASS %s", password))
+}
+
+// Apop authenticates with the timestamp of the server greeting.
+func (c *Client) Apop(username, password string) (string, error) {
+	start := strings.Index(c.greeting, "<")
+	end := strings.LastIndex(c.greeting, ">")
+	if start < 0 || end < start {
+		return "", fmt.Errorf("server greeting does not contain a timestamp")
+	}
+	digest := apopDigest(c.greeting[start:end+1], password)
+	return c.Send(fmt.Sprintf("APOP %s %s", username, digest))
+}
+
+func (c *Client) StartTLS(cfg *tls.Config) (string, error) {
+	res, err := c.Send("STLS")
+	if err != nil || !strings.HasPrefix(res, "+OK") {
+		return res, err
+	}
+	tlsConn := tls.Client(c.conn, cfg)
+	if err = tlsConn.Handshake(); err != nil {
+		return res, err
+	}
+	c.conn = tlsConn
+	c.tpc = textproto.NewConn(tlsConn)
+	return res, nil
+}
+
+func (c *Client) Close() {
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+}
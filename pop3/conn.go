@@ -0,0 +1,368 @@
+package pop3
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+type conn struct {
+	conn      net.Conn
+	ctx       context.Context
+	tpc       *textproto.Conn
+	state     ConnState
+	tlsConfig *tls.Config
+	handler   Handler
+
+	// timestamp sent in the greeting and used to verify APOP digests
+	timestamp string
+	username  string
+	messages  []Message
+	deleted   map[int]bool
+}
+
+func (c *conn) serve() {
+	_, cancel := context.WithCancel(c.ctx)
+	defer func() {
+		r := recover()
+		if r != nil {
+			log.Debugf("pop3 panic: %v", string(debug.Stack()))
+			log.Errorf("pop3 panic: %v", r)
+		}
+		if c.state != AuthorizationState && c.handler != nil {
+			c.handler.CloseSession(c.ctx)
+		}
+		cancel()
+	}()
+
+	if c.tpc == nil {
+		c.tpc = textproto.NewConn(c.conn)
+	}
+	c.timestamp = fmt.Sprintf("<%d.%d@mokapi>", os.Getpid(), time.Now().UnixNano())
+	err := c.ok("Mokapi POP3 server ready %s", c.timestamp)
+	if err != nil {
+		log.Errorf("failed to send greetings: %v", err)
+		return
+	}
+
+	for {
+		var quit bool
+		quit, err = c.readCmd()
+		if err != nil {
+			switch {
+			case clientDisconnected(err):
+			default:
+				log.Errorf("pop3: %v", err)
+			}
+			return
+		}
+		if quit {
+			return
+		}
+	}
+}
+
+func (c *conn) readCmd() (bool, error) {
+	line, err := c.tpc.ReadLine()
+	if err != nil {
+		return false, err
+	}
+
+	cmd, param := parseLine(line)
+	args := strings.Fields(param)
+
+	switch c.state {
+	case AuthorizationState:
+		switch cmd {
+		case "USER":
+			err = c.handleUser(args)
+		case "PASS":
+			err = c.handlePass(param)
+		case "APOP":
+			err = c.handleApop(args)
+		case "CAPA":
+			err = c.handleCapa()
+		case "STLS":
+			err = c.handleStartTLS()
+		case "QUIT":
+			return true, c.ok("Mokapi POP3 server signing off")
+		default:
+			err = c.unknownCommand(cmd, line)
+		}
+	case TransactionState:
+		switch cmd {
+		case "STAT":
+			err = c.handleStat()
+		case "LIST":
+			err = c.handleList(args)
+		case "UIDL":
+			err = c.handleUidl(args)
+		case "RETR":
+			err = c.handleRetr(args)
+		case "TOP":
+			err = c.handleTop(args)
+		case "DELE":
+			err = c.handleDele(args)
+		case "RSET":
+			c.deleted = map[int]bool{}
+			ctx := ClientFromContext(c.ctx)
+			ctx.Deleted = nil
+			err = c.ok("maildrop has %d messages (%d octets)", len(c.messages), c.maildropSize())
+		case "NOOP":
+			err = c.ok("")
+		case "CAPA":
+			err = c.handleCapa()
+		case "QUIT":
+			return true, c.handleQuit()
+		default:
+			err = c.unknownCommand(cmd, line)
+		}
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("handle command failed '%v': %w", cmd, err)
+	}
+	return false, nil
+}
+
+func (c *conn) handleQuit() error {
+	c.state = UpdateState
+	var deleted []string
+	for i, m := range c.messages {
+		if c.deleted[i] {
+			deleted = append(deleted, m.Uid)
+		}
+	}
+	if len(deleted) > 0 {
+		if err := c.handler.UpdateMaildrop(deleted, c.ctx); err != nil {
+			return c.err("some deleted messages not removed: %v", err)
+		}
+	}
+	remaining := len(c.messages) - len(deleted)
+	if remaining == 0 {
+		return c.ok("Mokapi POP3 server signing off (maildrop empty)")
+	}
+	return c.ok("Mokapi POP3 server signing off (%d messages left)", remaining)
+}
+
+func (c *conn) handleCapa() error {
+	if err := c.ok("Capability list follows"); err != nil {
+		return err
+	}
+	caps := []string{"USER", "TOP", "UIDL", "RESP-CODES"}
+	if c.canStartTLS() {
+		caps = append(caps, "STLS")
+	}
+	if c.state == TransactionState {
+		caps = append(caps, "EXPIRE NEVER")
+	}
+	caps = append(caps, "IMPLEMENTATION Mokapi")
+	return c.writeLines(strings.Join(caps, "\r\n"))
+}
+
+func (c *conn) handleStat() error {
+	n := 0
+	for i := range c.messages {
+		if !c.deleted[i] {
+			n++
+		}
+	}
+	return c.ok("%d %d", n, c.maildropSize())
+}
+
+func (c *conn) handleList(args []string) error {
+	if len(args) > 0 {
+		i, err := c.message(args[0])
+		if err != nil {
+			return c.err("%v", err)
+		}
+		return c.ok("%d %d", i+1, size(c.messages[i].Data))
+	}
+
+	var sb strings.Builder
+	n := 0
+	for i, m := range c.messages {
+		if c.deleted[i] {
+			continue
+		}
+		n++
+		sb.WriteString(fmt.Sprintf("%d %d\n", i+1, size(m.Data)))
+	}
+	if err := c.ok("%d messages (%d octets)", n, c.maildropSize()); err != nil {
+		return err
+	}
+	return c.writeLines(strings.TrimSuffix(sb.String(), "\n"))
+}
+
+func (c *conn) handleUidl(args []string) error {
+	if len(args) > 0 {
+		i, err := c.message(args[0])
+		if err != nil {
+			return c.err("%v", err)
+		}
+		return c.ok("%d %s", i+1, c.messages[i].Uid)
+	}
+
+	var sb strings.Builder
+	for i, m := range c.messages {
+		if c.deleted[i] {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%d %s\n", i+1, m.Uid))
+	}
+	if err := c.ok(""); err != nil {
+		return err
+	}
+	return c.writeLines(strings.TrimSuffix(sb.String(), "\n"))
+}
+
+func (c *conn) handleRetr(args []string) error {
+	if len(args) != 1 {
+		return c.err("syntax: RETR msg")
+	}
+	i, err := c.message(args[0])
+	if err != nil {
+		return c.err("%v", err)
+	}
+	m := c.messages[i]
+	ctx := ClientFromContext(c.ctx)
+	ctx.Retrieved = append(ctx.Retrieved, m.Uid)
+
+	if err = c.ok("%d octets", size(m.Data)); err != nil {
+		return err
+	}
+	return c.writeLines(string(m.Data))
+}
+
+func (c *conn) handleTop(args []string) error {
+	if len(args) != 2 {
+		return c.err("syntax: TOP msg n")
+	}
+	i, err := c.message(args[0])
+	if err != nil {
+		return c.err("%v", err)
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 0 {
+		return c.err("invalid number of lines: %v", args[1])
+	}
+
+	data := strings.ReplaceAll(string(c.messages[i].Data), "\r\n", "\n")
+	header, body, _ := strings.Cut(data, "\n\n")
+	lines := strings.Split(body, "\n")
+	if n < len(lines) {
+		lines = lines[:n]
+	}
+
+	if err = c.ok(""); err != nil {
+		return err
+	}
+	return c.writeLines(header + "\n\n" + strings.Join(lines, "\n"))
+}
+
+func (c *conn) handleDele(args []string) error {
+	if len(args) != 1 {
+		return c.err("syntax: DELE msg")
+	}
+	i, err := c.message(args[0])
+	if err != nil {
+		return c.err("%v", err)
+	}
+	c.deleted[i] = true
+	ctx := ClientFromContext(c.ctx)
+	ctx.Deleted = append(ctx.Deleted, c.messages[i].Uid)
+	return c.ok("message %d deleted", i+1)
+}
+
+// message returns the index of the message with the given message-number.
+func (c *conn) message(arg string) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("invalid message-number: %v", arg)
+	}
+	i := n - 1
+	if i < 0 || i >= len(c.messages) {
+		return 0, fmt.Errorf("no such message")
+	}
+	if c.deleted[i] {
+		return 0, fmt.Errorf("message %d already deleted", n)
+	}
+	return i, nil
+}
+
+func (c *conn) maildropSize() int {
+	n := 0
+	for i, m := range c.messages {
+		if !c.deleted[i] {
+			n += size(m.Data)
+		}
+	}
+	return n
+}
+
+func (c *conn) unknownCommand(cmd, line string) error {
+	log.Errorf("pop3: unknown command: %v", line)
+	return c.err("Unknown command %v", cmd)
+}
+
+func (c *conn) ok(format string, args ...any) error {
+	if format == "" {
+		return c.tpc.PrintfLine("+OK")
+	}
+	return c.tpc.PrintfLine("+OK "+format, args...)
+}
+
+func (c *conn) err(format string, args ...any) error {
+	return c.tpc.PrintfLine("-ERR "+format, args...)
+}
+
+// writeLines writes a multi-line response. Lines are dot-stuffed and the
+// response is terminated by a line containing a single dot.
+func (c *conn) writeLines(s string) error {
+	w := c.tpc.DotWriter()
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	if len(s) > 0 && !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+	_, err := io.WriteString(w, s)
+	if err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// size returns the size of the message in octets as transmitted, where
+// every line ends with CRLF.
+func size(data []byte) int {
+	s := strings.ReplaceAll(string(data), "\r\n", "\n")
+	return len(s) + strings.Count(s, "\n")
+}
+
+func apopDigest(timestamp, password string) string {
+	sum := md5.Sum([]byte(timestamp + password))
+	return hex.EncodeToString(sum[:])
+}
+
+func parseLine(line string) (cmd, param string) {
+	cmd, param, _ = strings.Cut(strings.TrimLeft(line, " "), " ")
+	return strings.ToUpper(cmd), param
+}
+
+func clientDisconnected(err error) bool {
+	return err == io.EOF || errors.Is(err, net.ErrClosed) || errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,25 @@
+package pop3
+
+import "context"
+
+const clientKey = "pop3-client"
+
+type ClientContext struct {
+	Addr      string
+	Username  string
+	Retrieved []string
+	Deleted   []string
+	Session   map[string]interface{}
+}
+
+func NewClientContext(ctx context.Context, addr string) context.Context {
+	return context.WithValue(ctx, clientKey, &ClientContext{
+		Addr:    addr,
+		Session: map[string]interface{}{},
+	})
+}
+
+func ClientFromContext(ctx context.Context) *ClientContext {
+	c := ctx.Value(clientKey).(*ClientContext)
+	return c
+}
@@ -0,0 +1,33 @@
+package pop3
+
+import "context"
+
+type ConnState uint8
+
+const (
+	AuthorizationState ConnState = iota
+	TransactionState
+	UpdateState
+)
+
+// Message is a single message of a maildrop. Data contains the complete
+// message in internet message format (RFC 5322).
+type Message struct {
+	Uid  string
+	Data []byte
+}
+
+type Handler interface {
+	// Authorize looks up the maildrop of the given user. The verify function
+	// reports whether the client proved knowledge of the stored password,
+	// either by USER/PASS or by an APOP digest.
+	Authorize(username string, verify func(password string) bool, ctx context.Context) error
+	// Maildrop returns the messages available to the authorized client. It is
+	// called once when the session enters the TRANSACTION state.
+	Maildrop(ctx context.Context) ([]Message, error)
+	// UpdateMaildrop removes the messages marked as deleted. It is called only
+	// when the client issues QUIT in the TRANSACTION state.
+	UpdateMaildrop(deleted []string, ctx context.Context) error
+	// CloseSession is called when the connection of an authorized client ends.
+	CloseSession(ctx context.Context)
+}
@@ -0,0 +1,121 @@
+package pop3
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/pkg/errors"
+	"mokapi/safe"
+	"net"
+	"sync"
+)
+
+var ErrServerClosed = errors.New("pop3: Server closed")
+
+const (
+	None     TlsMode = iota
+	StartTls TlsMode = iota
+	Implicit TlsMode = iota
+)
+
+type TlsMode int
+
+type Server struct {
+	Addr      string
+	TLSConfig *tls.Config
+	TlsMode   TlsMode
+	Handler   Handler
+
+	mu         sync.Mutex
+	activeConn map[net.Conn]context.Context
+	listener   net.Listener
+	inShutdown safe.AtomicBool
+}
+
+func (s *Server) ListenAndServe() error {
+	if s.inShutdown.IsSet() {
+		return ErrServerClosed
+	}
+
+	var err error
+	s.mu.Lock()
+	s.listener, err = net.Listen("tcp", s.Addr)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return s.Serve(s.listener)
+}
+
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			if s.inShutdown.IsSet() {
+				return ErrServerClosed
+			}
+			return fmt.Errorf("pop3: %v", err)
+		}
+
+		if s.TlsMode == Implicit {
+			c = tls.Server(c, s.TLSConfig)
+		}
+
+		ic := conn{
+			conn:      c,
+			ctx:       s.trackConn(c),
+			tlsConfig: s.TLSConfig,
+			handler:   s.Handler,
+		}
+		go func() {
+			ic.serve()
+			s.closeConn(c)
+		}()
+	}
+}
+
+func (s *Server) Close() {
+	if s.inShutdown.IsSet() {
+		return
+	}
+	s.inShutdown.SetTrue()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener != nil {
+		s.listener.Close()
+	}
+
+	for c, ctx := range s.activeConn {
+		ctx.Done()
+		c.Close()
+		delete(s.activeConn, c)
+	}
+	return
+}
+
+func (s *Server) trackConn(conn net.Conn) context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.activeConn == nil {
+		s.activeConn = make(map[net.Conn]context.Context)
+	}
+	ctx := NewClientContext(context.Background(), conn.RemoteAddr().String())
+	s.activeConn[conn] = ctx
+	return ctx
+}
+
+func (s *Server) closeConn(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx, ok := s.activeConn[conn]
+	if !ok {
+		return
+	}
+	ctx.Done()
+	conn.Close()
+	delete(s.activeConn, conn)
+}
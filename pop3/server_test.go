@@ -0,0 +1,359 @@
+package pop3_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"mokapi/config/static"
+	"mokapi/pop3"
+	"mokapi/server/cert"
+	"mokapi/try"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type handler struct {
+	password string
+	messages []pop3.Message
+	deleted  []string
+	closed   *pop3.ClientContext
+}
+
+func (h *handler) Authorize(username string, verify func(password string) bool, _ context.Context) error {
+	if username != "bob" || !verify(h.password) {
+		return fmt.Errorf("invalid credentials")
+	}
+	return nil
+}
+
+func (h *handler) Maildrop(_ context.Context) ([]pop3.Message, error) {
+	return h.messages, nil
+}
+
+func (h *handler) UpdateMaildrop(deleted []string, _ context.Context) error {
+	h.deleted = deleted
+	return nil
+}
+
+func (h *handler) CloseSession(ctx context.Context) {
+	h.closed = pop3.ClientFromContext(ctx)
+}
+
+func newHandler() *handler {
+	return &handler{
+		password: "secret",
+		messages: []pop3.Message{
+			{Uid: "1.1", Data: []byte("Subject: Hello\r\n\r\nline 1\r\nline 2\r\n.dot\r\n")},
+			{Uid: "1.2", Data: []byte("Subject: World\n\nfoo\n")},
+		},
+	}
+}
+
+func TestServer(t *testing.T) {
+	testcases := []struct {
+		name string
+		test func(t *testing.T, c *pop3.Client, h *handler)
+	}{
+		{
+			name: "greeting contains timestamp",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				res, err := c.Dial()
+				require.NoError(t, err)
+				require.Regexp(t, `^\+OK Mokapi POP3 server ready <\d+\.\d+@mokapi>$`, res)
+			},
+		},
+		{
+			name: "capabilities",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustDial(t, c)
+				_, lines, err := c.SendMulti("CAPA")
+				require.NoError(t, err)
+				require.Equal(t, []string{"USER", "TOP", "UIDL", "RESP-CODES", "IMPLEMENTATION Mokapi"}, lines)
+			},
+		},
+		{
+			name: "unknown command",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustDial(t, c)
+				res, err := c.Send("FOO")
+				require.NoError(t, err)
+				require.Equal(t, "-ERR Unknown command FOO", res)
+			},
+		},
+		{
+			name: "STAT not allowed before login",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustDial(t, c)
+				res, err := c.Send("STAT")
+				require.NoError(t, err)
+				require.Equal(t, "-ERR Unknown command STAT", res)
+			},
+		},
+		{
+			name: "PASS without USER",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustDial(t, c)
+				res, err := c.Send("PASS secret")
+				require.NoError(t, err)
+				require.Equal(t, "-ERR send USER first", res)
+			},
+		},
+		{
+			name: "login with invalid password",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustDial(t, c)
+				res, err := c.Login("bob", "foo")
+				require.NoError(t, err)
+				require.Equal(t, "-ERR [AUTH] invalid credentials", res)
+			},
+		},
+		{
+			name: "login",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustDial(t, c)
+				res, err := c.Login("bob", "secret")
+				require.NoError(t, err)
+				require.Equal(t, "+OK maildrop has 2 messages (63 octets)", res)
+			},
+		},
+		{
+			name: "APOP",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustDial(t, c)
+				res, err := c.Apop("bob", "secret")
+				require.NoError(t, err)
+				require.Equal(t, "+OK maildrop has 2 messages (63 octets)", res)
+			},
+		},
+		{
+			name: "APOP with invalid digest",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustDial(t, c)
+				res, err := c.Send("APOP bob c4c9334bac560ecc979e58001b3e22fb")
+				require.NoError(t, err)
+				require.Equal(t, "-ERR [AUTH] invalid credentials", res)
+			},
+		},
+		{
+			name: "STAT",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustLogin(t, c)
+				res, err := c.Send("STAT")
+				require.NoError(t, err)
+				require.Equal(t, "+OK 2 63", res)
+			},
+		},
+		{
+			name: "LIST",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustLogin(t, c)
+				res, lines, err := c.SendMulti("LIST")
+				require.NoError(t, err)
+				require.Equal(t, "+OK 2 messages (63 octets)", res)
+				require.Equal(t, []string{"1 40", "2 23"}, lines)
+
+				res, err = c.Send("LIST 2")
+				require.NoError(t, err)
+				require.Equal(t, "+OK 2 23", res)
+
+				res, err = c.Send("LIST 3")
+				require.NoError(t, err)
+				require.Equal(t, "-ERR no such message", res)
+			},
+		},
+		{
+			name: "UIDL",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustLogin(t, c)
+				_, lines, err := c.SendMulti("UIDL")
+				require.NoError(t, err)
+				require.Equal(t, []string{"1 1.1", "2 1.2"}, lines)
+
+				res, err := c.Send("UIDL 1")
+				require.NoError(t, err)
+				require.Equal(t, "+OK 1 1.1", res)
+			},
+		},
+		{
+			name: "RETR",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustLogin(t, c)
+				res, lines, err := c.SendMulti("RETR 1")
+				require.NoError(t, err)
+				require.Equal(t, "+OK 40 octets", res)
+				require.Equal(t, []string{"Subject: Hello", "", "line 1", "line 2", ".dot"}, lines)
+			},
+		},
+		{
+			name: "TOP",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustLogin(t, c)
+				_, lines, err := c.SendMulti("TOP 1 1")
+				require.NoError(t, err)
+				require.Equal(t, []string{"Subject: Hello", "", "line 1"}, lines)
+
+				_, lines, err = c.SendMulti("TOP 1 0")
+				require.NoError(t, err)
+				require.Equal(t, []string{"Subject: Hello", ""}, lines)
+			},
+		},
+		{
+			name: "DELE and QUIT",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustLogin(t, c)
+				res, err := c.Send("DELE 1")
+				require.NoError(t, err)
+				require.Equal(t, "+OK message 1 deleted", res)
+
+				res, err = c.Send("RETR 1")
+				require.NoError(t, err)
+				require.Equal(t, "-ERR message 1 already deleted", res)
+
+				res, err = c.Send("STAT")
+				require.NoError(t, err)
+				require.Equal(t, "+OK 1 23", res)
+
+				res, err = c.Send("QUIT")
+				require.NoError(t, err)
+				require.Equal(t, "+OK Mokapi POP3 server signing off (1 messages left)", res)
+				require.Equal(t, []string{"1.1"}, h.deleted)
+			},
+		},
+		{
+			name: "RSET unmarks deleted messages",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustLogin(t, c)
+				_, err := c.Send("DELE 1")
+				require.NoError(t, err)
+				res, err := c.Send("RSET")
+				require.NoError(t, err)
+				require.Equal(t, "+OK maildrop has 2 messages (63 octets)", res)
+
+				_, err = c.Send("QUIT")
+				require.NoError(t, err)
+				require.Nil(t, h.deleted)
+			},
+		},
+		{
+			name: "session is closed",
+			test: func(t *testing.T, c *pop3.Client, h *handler) {
+				mustLogin(t, c)
+				_, _, err := c.SendMulti("RETR 2")
+				require.NoError(t, err)
+				_, err = c.Send("QUIT")
+				require.NoError(t, err)
+
+				require.Eventually(t, func() bool { return h.closed != nil }, time.Second, 10*time.Millisecond)
+				require.Equal(t, "bob", h.closed.Username)
+				require.Equal(t, []string{"1.2"}, h.closed.Retrieved)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			p := try.GetFreePort()
+			h := newHandler()
+			s := &pop3.Server{Addr: fmt.Sprintf(":%v", p), Handler: h}
+			defer s.Close()
+			go func() {
+				err := s.ListenAndServe()
+				require.ErrorIs(t, err, pop3.ErrServerClosed)
+			}()
+
+			c := pop3.NewClient(fmt.Sprintf("localhost:%v", p))
+			defer c.Close()
+
+			tc.test(t, c, h)
+		})
+	}
+}
+
+func TestServer_Tls(t *testing.T) {
+	store, err := cert.NewStore(&static.Config{})
+	require.NoError(t, err)
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(cert.DefaultRootCert())
+	cfg := &tls.Config{RootCAs: rootCAs, ServerName: "localhost"}
+
+	testcases := []struct {
+		name    string
+		tlsMode pop3.TlsMode
+		test    func(t *testing.T, c *pop3.Client)
+	}{
+		{
+			name:    "implicit",
+			tlsMode: pop3.Implicit,
+			test: func(t *testing.T, c *pop3.Client) {
+				_, err := c.DialTls(cfg)
+				require.NoError(t, err)
+				_, lines, err := c.SendMulti("CAPA")
+				require.NoError(t, err)
+				require.NotContains(t, lines, "STLS")
+				res, err := c.Login("bob", "secret")
+				require.NoError(t, err)
+				require.Equal(t, "+OK maildrop has 2 messages (63 octets)", res)
+			},
+		},
+		{
+			name:    "STLS",
+			tlsMode: pop3.StartTls,
+			test: func(t *testing.T, c *pop3.Client) {
+				mustDial(t, c)
+				_, lines, err := c.SendMulti("CAPA")
+				require.NoError(t, err)
+				require.Contains(t, lines, "STLS")
+
+				res, err := c.StartTLS(cfg)
+				require.NoError(t, err)
+				require.Equal(t, "+OK Begin TLS negotiation now", res)
+
+				_, lines, err = c.SendMulti("CAPA")
+				require.NoError(t, err)
+				require.NotContains(t, lines, "STLS")
+				res, err = c.Login("bob", "secret")
+				require.NoError(t, err)
+				require.Equal(t, "+OK maildrop has 2 messages (63 octets)", res)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			p := try.GetFreePort()
+			s := &pop3.Server{
+				Addr:      fmt.Sprintf(":%v", p),
+				TlsMode:   tc.tlsMode,
+				TLSConfig: &tls.Config{GetCertificate: store.GetCertificate},
+				Handler:   newHandler(),
+			}
+			defer s.Close()
+			go func() {
+				err := s.ListenAndServe()
+				require.ErrorIs(t, err, pop3.ErrServerClosed)
+			}()
+
+			c := pop3.NewClient(fmt.Sprintf("localhost:%v", p))
+			defer c.Close()
+
+			tc.test(t, c)
+		})
+	}
+}
+
+func mustDial(t *testing.T, c *pop3.Client) {
+	_, err := c.Dial()
+	require.NoError(t, err)
+}
+
+func mustLogin(t *testing.T, c *pop3.Client) {
+	mustDial(t, c)
+	res, err := c.Login("bob", "secret")
+	require.NoError(t, err)
+	require.Contains(t, res, "+OK")
+}
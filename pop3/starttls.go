@@ -0,0 +1,29 @@
+package pop3
+
+import (
+	"crypto/tls"
+	"net/textproto"
+)
+
+func (c *conn) canStartTLS() bool {
+	_, isTLS := c.conn.(*tls.Conn)
+	return !isTLS && c.tlsConfig != nil && c.state == AuthorizationState
+}
+
+func (c *conn) handleStartTLS() error {
+	if !c.canStartTLS() {
+		return c.err("STLS not available")
+	}
+	err := c.ok("Begin TLS negotiation now")
+	if err != nil {
+		return err
+	}
+
+	tlsConn := tls.Server(c.conn, c.tlsConfig)
+	if err := tlsConn.Handshake(); err != nil {
+		return err
+	}
+	c.conn = tlsConn
+	c.tpc = textproto.NewConn(tlsConn)
+	return nil
+}
@@ -23,3 +23,11 @@ func WithKafkaServerBinding(k asyncapi3.BrokerBindings) ServerOptions {
 		s.Bindings.Kafka = k
 	}
 }
+
+func WithServerSecurity(schemes ...*asyncapi3.SecurityScheme) ServerOptions {
+	return func(s *asyncapi3.Server) {
+		for _, scheme := range schemes {
+			s.Security = append(s.Security, &asyncapi3.SecuritySchemeRef{Value: scheme})
+		}
+	}
+}
@@ -3,6 +3,8 @@ package asyncapi3
 import (
 	"fmt"
 	"mokapi/schema/json/schema"
+	"slices"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -75,6 +77,20 @@ type TopicBindings struct {
 	// the segment file isn’t full to ensure that retention can delete or compact old data.
 	SegmentMs int64
 
+	// CompressionType Specify the final compression type for a given topic: uncompressed, gzip, snappy, lz4, zstd or
+	// producer. The value producer means retaining the original compression codec set by the producer.
+	CompressionType string
+
+	// CleanupPolicy A list of the retention policies to apply on old log segments: delete, compact or both.
+	// The delete policy discards old segments when their retention time or size limit has been reached. The
+	// compact policy retains at least the last known value for each message key. Default is delete.
+	CleanupPolicy []string
+
+	// DeleteRetentionMs The amount of time to retain tombstone markers for log compacted topics. A consumer must
+	// complete a read from offset 0 within this time to be sure it sees the final stage of a deleted key.
+	// Default is 86400000 (1 day).
+	DeleteRetentionMs int64
+
 	ValueSchemaValidation bool
 	KeySchemaValidation   bool
 }
@@ -167,6 +183,18 @@ func (t *TopicBindings) UnmarshalYAML(value *yaml.Node) error {
 	if err != nil {
 		return fmt.Errorf("invalid segment.ms: %w", err)
 	}
+	t.CompressionType, err = getCompressionType(m)
+	if err != nil {
+		return err
+	}
+	t.CleanupPolicy, err = getCleanupPolicy(m)
+	if err != nil {
+		return err
+	}
+	t.DeleteRetentionMs, err = getInt64(m, "delete.retention.ms")
+	if err != nil {
+		return fmt.Errorf("invalid delete.retention.ms: %w", err)
+	}
 	t.ValueSchemaValidation, err = getBool(m, "confluent.value.schema.validation")
 	if err != nil {
 		return fmt.Errorf("invalid confluent.value.schema.validation: %w", err)
@@ -179,6 +207,15 @@ func (t *TopicBindings) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// HasCleanupPolicy reports whether the given cleanup policy applies to the topic.
+// Topics without a cleanup policy use delete.
+func (t *TopicBindings) HasCleanupPolicy(policy string) bool {
+	if len(t.CleanupPolicy) == 0 {
+		return policy == "delete"
+	}
+	return slices.Contains(t.CleanupPolicy, policy)
+}
+
 func getMs(m map[string]interface{}, baseKey string) (int64, error) {
 	key := baseKey + ".ms"
 	if i, err := getInt64(m, key); err != nil {
@@ -245,6 +282,61 @@ func getBool(m map[string]interface{}, keys ...string) (bool, error) {
 	return true, nil
 }
 
+func getCompressionType(m map[string]interface{}) (string, error) {
+	i := getValue(m, "compression.type")
+	if i == nil {
+		return "", nil
+	}
+	s, ok := i.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid compression.type: cannot unmarshal %T to string: %v", i, i)
+	}
+	switch s {
+	case "producer", "uncompressed", "gzip", "snappy", "lz4", "zstd":
+		return s, nil
+	default:
+		return "", fmt.Errorf("invalid compression.type: unsupported value '%v'", s)
+	}
+}
+
+// getCleanupPolicy accepts a list of policies as defined by the AsyncAPI Kafka
+// bindings as well as a comma-separated string as used by the Kafka topic config.
+func getCleanupPolicy(m map[string]interface{}) ([]string, error) {
+	i := getValue(m, "cleanup.policy")
+	if i == nil {
+		return nil, nil
+	}
+	var values []string
+	switch v := i.(type) {
+	case string:
+		values = strings.Split(v, ",")
+	case []interface{}:
+		for _, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid cleanup.policy: cannot unmarshal %T to string: %v", e, e)
+			}
+			values = append(values, s)
+		}
+	default:
+		return nil, fmt.Errorf("invalid cleanup.policy: cannot unmarshal %T to []string: %v", i, i)
+	}
+
+	var policy []string
+	for _, p := range values {
+		p = strings.TrimSpace(p)
+		switch p {
+		case "delete", "compact":
+			if !slices.Contains(policy, p) {
+				policy = append(policy, p)
+			}
+		default:
+			return nil, fmt.Errorf("invalid cleanup.policy: unsupported value '%v'", p)
+		}
+	}
+	return policy, nil
+}
+
 func getValue(m map[string]interface{}, keys ...string) interface{} {
 	for _, key := range keys {
 		if i, ok := m[key]; ok {
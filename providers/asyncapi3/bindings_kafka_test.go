@@ -454,6 +454,82 @@ channels:
 				require.EqualError(t, err, "invalid retention.bytes: cannot unmarshal string to int64: foo")
 			},
 		},
+		{
+			name: "compression.type",
+			config: `
+channels:
+  test:
+    bindings:
+      kafka:
+        topicConfiguration:
+          compression.type: gzip
+`,
+			test: func(t *testing.T, config *asyncapi3.Config, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "gzip", config.Channels["test"].Value.Bindings.Kafka.CompressionType)
+			},
+		},
+		{
+			name: "compression.type error",
+			config: `
+channels:
+  test:
+    bindings:
+      kafka:
+        compression.type: brotli
+`,
+			test: func(t *testing.T, config *asyncapi3.Config, err error) {
+				require.EqualError(t, err, "invalid compression.type: unsupported value 'brotli'")
+			},
+		},
+		{
+			name: "cleanup.policy",
+			config: `
+channels:
+  test:
+    bindings:
+      kafka:
+        topicConfiguration:
+          cleanup.policy: [compact, delete]
+          delete.retention.ms: 1000
+`,
+			test: func(t *testing.T, config *asyncapi3.Config, err error) {
+				require.NoError(t, err)
+				b := config.Channels["test"].Value.Bindings.Kafka
+				require.Equal(t, []string{"compact", "delete"}, b.CleanupPolicy)
+				require.Equal(t, int64(1000), b.DeleteRetentionMs)
+				require.True(t, b.HasCleanupPolicy("compact"))
+			},
+		},
+		{
+			name: "cleanup.policy as string",
+			config: `
+channels:
+  test:
+    bindings:
+      kafka:
+        cleanup.policy: compact
+`,
+			test: func(t *testing.T, config *asyncapi3.Config, err error) {
+				require.NoError(t, err)
+				b := config.Channels["test"].Value.Bindings.Kafka
+				require.Equal(t, []string{"compact"}, b.CleanupPolicy)
+				require.False(t, b.HasCleanupPolicy("delete"))
+			},
+		},
+		{
+			name: "cleanup.policy error",
+			config: `
+channels:
+  test:
+    bindings:
+      kafka:
+        cleanup.policy: compact,remove
+`,
+			test: func(t *testing.T, config *asyncapi3.Config, err error) {
+				require.EqualError(t, err, "invalid cleanup.policy: unsupported value 'remove'")
+			},
+		},
 		{
 			name: "retention.ms",
 			config: `
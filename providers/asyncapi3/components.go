@@ -14,6 +14,7 @@ type Components struct {
 	ExternalDocs    map[string]*ExternalDocRef    `yaml:"externalDocs" json:"externalDocs"`
 	OperationTraits map[string]*OperationTraitRef `yaml:"operationTraits" json:"operationTraits"`
 	MessageTraits   map[string]*MessageTraitRef   `yaml:"messageTraits" json:"messageTraits"`
+	SecuritySchemes map[string]*SecuritySchemeRef `yaml:"securitySchemes" json:"securitySchemes"`
 }
 
 func (c *Components) parse(config *dynamic.Config, reader dynamic.Reader) error {
@@ -27,5 +28,11 @@ func (c *Components) parse(config *dynamic.Config, reader dynamic.Reader) error
 		}
 	}
 
+	for _, s := range c.SecuritySchemes {
+		if err := s.parse(config, reader); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
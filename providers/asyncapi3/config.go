@@ -110,7 +110,7 @@ func (c *Config) HasKafkaServer() bool {
 	}
 	for it := c.Servers.Iter(); it.Next(); {
 		server := it.Value()
-		if server.Value.Protocol == "kafka" {
+		if server.Value.IsKafka() {
 			return true
 		}
 	}
@@ -123,7 +123,7 @@ func (c *Config) HasMqttServer() bool {
 	}
 	for it := c.Servers.Iter(); it.Next(); {
 		server := it.Value()
-		if server.Value.Protocol == "mqtt" {
+		if server.Value.IsMqtt() {
 			return true
 		}
 	}
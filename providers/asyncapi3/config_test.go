@@ -52,6 +52,35 @@ components:
 	require.Equal(t, "application/json", cfg.DefaultContentType)
 }
 
+func TestConfig3_SecuritySchemeUsers(t *testing.T) {
+	b := []byte(`asyncapi: 3.0.0
+servers:
+  broker:
+    host: localhost:9092
+    protocol: kafka
+    security:
+      - $ref: '#/components/securitySchemes/plain'
+components:
+  securitySchemes:
+    plain:
+      type: plain
+      x-users:
+        - username: alice
+          password: secret
+`)
+	var cfg *asyncapi3.Config
+	err := yaml.Unmarshal(b, &cfg)
+	require.NoError(t, err)
+	err = cfg.Parse(&dynamic.Config{Data: cfg}, &dynamictest.Reader{})
+	require.NoError(t, err)
+
+	server := cfg.Servers.Lookup("broker")
+	require.Len(t, server.Value.Security, 1)
+	scheme := server.Value.Security[0].Value
+	require.Equal(t, "plain", scheme.Type)
+	require.Equal(t, []asyncapi3.SecurityUser{{Username: "alice", Password: "secret"}}, scheme.Users)
+}
+
 func TestStreetlightKafka(t *testing.T) {
 	b, err := os.ReadFile("./test/streetlight-kafka-3.0.yaml")
 	require.NoError(t, err)
@@ -76,11 +105,15 @@ func TestStreetlightKafka(t *testing.T) {
 	require.Equal(t, "test.mykafkacluster.org:18092", server.Value.Host)
 	require.Equal(t, "kafka-secure", server.Value.Protocol)
 	require.Equal(t, "Test broker secured with scramSha256", server.Value.Description)
+	require.Len(t, server.Value.Security, 1)
+	require.Equal(t, "scramSha256", server.Value.Security[0].Value.Type)
+	require.Equal(t, "Provide your username and password for SASL/SCRAM authentication", server.Value.Security[0].Value.Description)
 
 	server = cfg.Servers.Lookup("mtls-connections")
 	require.Equal(t, "test.mykafkacluster.org:28092", server.Value.Host)
 	require.Equal(t, "kafka-secure", server.Value.Protocol)
 	require.Equal(t, "Test broker secured with X509", server.Value.Description)
+	require.Equal(t, "X509", server.Value.Security[0].Value.Type)
 
 	// Channel
 	require.Len(t, cfg.Channels, 4)
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/addOffsetsToTxn"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (s *Store) addOffsetsToTxn(rw kafka.ResponseWriter, req *kafka.Request) error {
+	r := req.Message.(*addOffsetsToTxn.Request)
+	res := &addOffsetsToTxn.Response{}
+
+	s.txm.Lock()
+	defer s.txm.Unlock()
+
+	txn, errCode := s.transaction(r.TransactionalId, r.ProducerId, r.ProducerEpoch)
+	if errCode != kafka.None {
+		log.Errorf("kafka AddOffsetsToTxn: transaction '%v': %v", r.TransactionalId, errCode)
+		res.ErrorCode = errCode
+	} else {
+		if !slices.Contains(txn.groups, r.GroupId) {
+			txn.groups = append(txn.groups, r.GroupId)
+		}
+		s.begin(txn)
+	}
+
+	return rw.Write(res)
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/addPartitionsToTxn"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (s *Store) addPartitionsToTxn(rw kafka.ResponseWriter, req *kafka.Request) error {
+	r := req.Message.(*addPartitionsToTxn.Request)
+	res := &addPartitionsToTxn.Response{}
+
+	s.txm.Lock()
+	defer s.txm.Unlock()
+
+	txn, errCode := s.transaction(r.TransactionalId, r.ProducerId, r.ProducerEpoch)
+	if errCode != kafka.None {
+		log.Errorf("kafka AddPartitionsToTxn: transaction '%v': %v", r.TransactionalId, errCode)
+	}
+
+	var partitions []*Partition
+	failed := false
+	for _, rt := range r.Topics {
+		resTopic := addPartitionsToTxn.ResponseTopic{Name: rt.Name}
+		topic := s.Topic(rt.Name)
+		for _, index := range rt.Partitions {
+			resPartition := addPartitionsToTxn.ResponsePartition{PartitionIndex: index, ErrorCode: errCode}
+			if errCode == kafka.None {
+				var p *Partition
+				if topic != nil {
+					p = topic.Partition(int(index))
+				}
+				if p == nil {
+					log.Errorf("kafka AddPartitionsToTxn: unknown topic %v or partition %v", rt.Name, index)
+					resPartition.ErrorCode = kafka.UnknownTopicOrPartition
+					failed = true
+				} else {
+					partitions = append(partitions, p)
+				}
+			}
+			resTopic.Results = append(resTopic.Results, resPartition)
+		}
+		res.Results = append(res.Results, resTopic)
+	}
+
+	if failed {
+		// the partitions are added all together or not at all
+		for i := range res.Results {
+			for j := range res.Results[i].Results {
+				if res.Results[i].Results[j].ErrorCode == kafka.None {
+					res.Results[i].Results[j].ErrorCode = kafka.OperationNotAttempted
+				}
+			}
+		}
+	} else if errCode == kafka.None {
+		for _, p := range partitions {
+			if !slices.Contains(txn.partitions, p) {
+				txn.partitions = append(txn.partitions, p)
+			}
+		}
+		s.begin(txn)
+	}
+
+	return rw.Write(res)
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"fmt"
+	"mokapi/kafka"
+	"mokapi/kafka/alterConfigs"
+	"mokapi/kafka/describeConfigs"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (s *Store) alterConfigs(rw kafka.ResponseWriter, req *kafka.Request) error {
+	r := req.Message.(*alterConfigs.Request)
+
+	reqLog := &KafkaAlterConfigsRequest{ValidateOnly: r.ValidateOnly}
+	resLog := &KafkaConfigsResponse{}
+
+	res := &alterConfigs.Response{}
+	for _, resource := range r.Resources {
+		resourceLog := KafkaConfigResource{
+			Type:    resourceTypeString(resource.ResourceType),
+			Name:    resource.ResourceName,
+			Configs: map[string]string{},
+		}
+		for _, c := range resource.Configs {
+			resourceLog.Configs[c.Name] = c.Value
+		}
+		reqLog.Resources = append(reqLog.Resources, resourceLog)
+
+		result := alterConfigs.Result{
+			ResourceType: resource.ResourceType,
+			ResourceName: resource.ResourceName,
+		}
+		if resource.ResourceType != describeConfigs.ResourceTopic {
+			result.ErrorCode = kafka.InvalidRequest
+			result.ErrorMessage = fmt.Sprintf("configs of resource type %v cannot be altered", resourceTypeString(resource.ResourceType))
+		} else {
+			result.ErrorCode, result.ErrorMessage = s.alterTopicConfigs(resource, r.ValidateOnly)
+		}
+		res.Responses = append(res.Responses, result)
+
+		resultLog := KafkaConfigResourceResult{
+			KafkaConfigResource: KafkaConfigResource{Type: resourceLog.Type, Name: resourceLog.Name},
+		}
+		if result.ErrorCode != kafka.None {
+			resultLog.ErrorCode = result.ErrorCode.String()
+			resultLog.ErrorMessage = result.ErrorMessage
+		}
+		resLog.Resources = append(resLog.Resources, resultLog)
+	}
+
+	go s.logRequest(req.Header, reqLog)(resLog)
+
+	return rw.Write(res)
+}
+
+// alterTopicConfigs replaces the configs of the topic. Configs which are not
+// part of the request are reset to the broker config or Kafka default.
+func (s *Store) alterTopicConfigs(resource alterConfigs.Resource, validateOnly bool) (kafka.ErrorCode, string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	t, ok := s.topics[resource.ResourceName]
+	if !ok {
+		return kafka.UnknownTopicOrPartition, fmt.Sprintf("topic '%v' does not exist", resource.ResourceName)
+	}
+
+	bindings := t.bindings
+	resetTopicConfigs(&bindings)
+	for _, c := range resource.Configs {
+		tc, ok := getTopicConfig(c.Name)
+		if !ok {
+			return kafka.InvalidConfig, fmt.Sprintf("unknown topic config name: %v", c.Name)
+		}
+		if c.Value == "" {
+			continue
+		}
+		if err := tc.set(&bindings, c.Value); err != nil {
+			return kafka.InvalidConfig, fmt.Sprintf("invalid value for config %v: %v", c.Name, err)
+		}
+	}
+
+	if !validateOnly {
+		// keep the configs separate from the loaded config, so a reload
+		// does not reset them
+		t.configs = &bindings
+		t.update(t.Config, s)
+		log.Infof("kafka: updated configs of topic %v", t.Name)
+	}
+	return kafka.None, ""
+}
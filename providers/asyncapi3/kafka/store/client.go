@@ -15,6 +15,7 @@ import (
 	avro "mokapi/schema/avro/schema"
 	"mokapi/schema/encoding"
 	"mokapi/schema/json/schema"
+	protobuf "mokapi/schema/protobuf/schema"
 	"slices"
 	"time"
 
@@ -158,10 +159,22 @@ func (c *Client) Read(topic string, partition int, offset int64, ct *media.Conte
 		offset = p.Head
 	}
 
-	// read max 6MB
-	b, errCode := p.Read(offset, 6e+6)
-	if errCode != kafka.None {
-		return nil, fmt.Errorf("read records failed: %v", errCode.String())
+	// read max 6MB, batches are split by codec and transaction
+	var read []*kafka.Record
+	maxBytes := int(6e+6)
+	for offset < p.Offset() && maxBytes > 0 {
+		b, errCode := p.Read(offset, maxBytes)
+		if errCode != kafka.None {
+			return nil, fmt.Errorf("read records failed: %v", errCode.String())
+		}
+		if len(b.Records) == 0 {
+			break
+		}
+		offset = b.Records[len(b.Records)-1].Offset + 1
+		maxBytes -= b.Size()
+		if !b.Control {
+			read = append(read, b.Records...)
+		}
 	}
 
 	records := make([]Record, 0)
@@ -186,7 +199,7 @@ func (c *Client) Read(topic string, partition int, offset int64, ct *media.Conte
 		}
 	}
 
-	for _, r := range b.Records {
+	for _, r := range read {
 		key := string(kafka.Read(r.Key))
 		val, err := getValue(kafka.Read(r.Value))
 		if err != nil {
@@ -281,14 +294,15 @@ func (c *Client) Offset(topic string, partition int, offset int64, ct *media.Con
 }
 
 func (c *Client) getPartition(t *Topic, id int) (*Partition, error) {
+	partitions := t.partitionList()
 	if id < 0 {
 		r := rand.New(rand.NewSource(time.Now().Unix()))
-		id = r.Intn(len(t.Partitions))
-	} else if id >= len(t.Partitions) {
+		id = r.Intn(len(partitions))
+	} else if id >= len(partitions) {
 		return nil, PartitionNotFound
 	}
 
-	return t.Partition(id), nil
+	return partitions[id], nil
 }
 
 func (c *Client) parse(v any, ct media.ContentType, topic *asyncapi3.Channel) ([]byte, error) {
@@ -475,6 +489,9 @@ func valueMatchMessagePayload(value any, msg *asyncapi3.Message) error {
 		jsSchema := avro.ConvertToJsonSchema(v)
 		_, err := encoding.NewEncoder(jsSchema).Write(value, ct)
 		return err
+	case *protobuf.Message:
+		_, err := v.Marshal(value)
+		return err
 	default:
 		return nil
 	}
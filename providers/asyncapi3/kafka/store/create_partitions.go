@@ -0,0 +1,53 @@
+package store
+
+import (
+	"fmt"
+	"mokapi/kafka"
+	"mokapi/kafka/createPartitions"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (s *Store) createPartitions(rw kafka.ResponseWriter, req *kafka.Request) error {
+	r := req.Message.(*createPartitions.Request)
+
+	reqLog := &KafkaCreatePartitionsRequest{Topics: map[string]int{}, ValidateOnly: r.ValidateOnly}
+	resLog := &KafkaCreatePartitionsResponse{Topics: map[string]KafkaResponseError{}}
+
+	res := &createPartitions.Response{}
+	for _, t := range r.Topics {
+		reqLog.Topics[t.Name] = int(t.Count)
+		result := createPartitions.Result{Name: t.Name}
+
+		result.ErrorCode, result.ErrorMessage = s.increasePartitions(t.Name, int(t.Count), r.ValidateOnly)
+
+		res.Results = append(res.Results, result)
+		resLog.Topics[t.Name] = KafkaResponseError{ErrorCode: result.ErrorCode.String(), ErrorMessage: result.ErrorMessage}
+	}
+
+	go s.logRequest(req.Header, reqLog)(resLog)
+
+	return rw.Write(res)
+}
+
+// increasePartitions adds partitions to the topic. The partition count is kept
+// when the config is reloaded with fewer partitions.
+func (s *Store) increasePartitions(name string, count int, validateOnly bool) (kafka.ErrorCode, string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	topic, ok := s.topics[name]
+	switch {
+	case !ok:
+		return kafka.UnknownTopicOrPartition, fmt.Sprintf("topic '%v' does not exist", name)
+	case count < len(topic.Partitions):
+		return kafka.InvalidPartitions, fmt.Sprintf("topic currently has %v partitions, which is higher than the requested %v", len(topic.Partitions), count)
+	case count == len(topic.Partitions):
+		return kafka.InvalidPartitions, fmt.Sprintf("topic already has %v partitions", count)
+	case !validateOnly:
+		topic.partitions = count
+		topic.update(topic.Config, s)
+		log.Infof("kafka: increased partitions of topic %v to %v", name, count)
+	}
+	return kafka.None, ""
+}
@@ -0,0 +1,150 @@
+package store_test
+
+import (
+	"mokapi/engine/enginetest"
+	"mokapi/kafka"
+	"mokapi/kafka/createPartitions"
+	"mokapi/kafka/kafkatest"
+	"mokapi/providers/asyncapi3"
+	"mokapi/providers/asyncapi3/asyncapi3test"
+	"mokapi/providers/asyncapi3/kafka/store"
+	"mokapi/runtime/events"
+	"mokapi/runtime/events/eventstest"
+	"mokapi/runtime/monitor"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePartitions(t *testing.T) {
+	testcases := []struct {
+		name string
+		req  *createPartitions.Request
+		test func(t *testing.T, s *store.Store, res *createPartitions.Response)
+	}{
+		{
+			name: "increase partitions",
+			req: &createPartitions.Request{
+				Topics: []createPartitions.Topic{{Name: "foo", Count: 3}},
+			},
+			test: func(t *testing.T, s *store.Store, res *createPartitions.Response) {
+				require.Equal(t, kafka.None, res.Results[0].ErrorCode)
+				require.Len(t, s.Topic("foo").Partitions, 3)
+				require.Equal(t, 3, s.Topic("foo").Bindings().Partitions)
+			},
+		},
+		{
+			name: "validate only",
+			req: &createPartitions.Request{
+				Topics:       []createPartitions.Topic{{Name: "foo", Count: 3}},
+				ValidateOnly: true,
+			},
+			test: func(t *testing.T, s *store.Store, res *createPartitions.Response) {
+				require.Equal(t, kafka.None, res.Results[0].ErrorCode)
+				require.Len(t, s.Topic("foo").Partitions, 1)
+			},
+		},
+		{
+			name: "same number of partitions",
+			req: &createPartitions.Request{
+				Topics: []createPartitions.Topic{{Name: "foo", Count: 1}},
+			},
+			test: func(t *testing.T, s *store.Store, res *createPartitions.Response) {
+				require.Equal(t, kafka.InvalidPartitions, res.Results[0].ErrorCode)
+				require.Equal(t, "topic already has 1 partitions", res.Results[0].ErrorMessage)
+			},
+		},
+		{
+			name: "decrease partitions",
+			req: &createPartitions.Request{
+				Topics: []createPartitions.Topic{{Name: "foo", Count: 0}},
+			},
+			test: func(t *testing.T, s *store.Store, res *createPartitions.Response) {
+				require.Equal(t, kafka.InvalidPartitions, res.Results[0].ErrorCode)
+				require.Len(t, s.Topic("foo").Partitions, 1)
+			},
+		},
+		{
+			name: "unknown topic",
+			req: &createPartitions.Request{
+				Topics: []createPartitions.Topic{{Name: "bar", Count: 2}},
+			},
+			test: func(t *testing.T, s *store.Store, res *createPartitions.Response) {
+				require.Equal(t, "bar", res.Results[0].Name)
+				require.Equal(t, kafka.UnknownTopicOrPartition, res.Results[0].ErrorCode)
+			},
+		},
+	}
+
+	t.Parallel()
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := asyncapi3test.NewConfig(
+				asyncapi3test.WithChannel("foo", asyncapi3test.WithKafkaChannelBinding(asyncapi3.TopicBindings{Partitions: 1})),
+			)
+			s := store.New(cfg, enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+			defer s.Close()
+
+			rr := kafkatest.NewRecorder()
+			s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 2, tc.req))
+
+			res, ok := rr.Message.(*createPartitions.Response)
+			require.True(t, ok)
+			tc.test(t, s, res)
+		})
+	}
+}
+
+func TestCreatePartitions_Reload(t *testing.T) {
+	dir := t.TempDir()
+	newConfig := func() *asyncapi3.Config {
+		return asyncapi3test.NewConfig(
+			asyncapi3test.WithChannel("foo", asyncapi3test.WithKafkaChannelBinding(asyncapi3.TopicBindings{Partitions: 1})),
+		)
+	}
+	cfg := newConfig()
+	sm := events.NewStoreManager(nil)
+	sm.SetStore(10, events.NewTraits().WithNamespace("kafka"))
+	s := store.NewEmpty(enginetest.NewEngine(), sm, monitor.NewKafka())
+	require.NoError(t, s.SetDataDir(dir))
+	s.Update(cfg)
+	defer s.Close()
+
+	rr := kafkatest.NewRecorder()
+	s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 2, &createPartitions.Request{
+		Topics: []createPartitions.Topic{{Name: "foo", Count: 3}},
+	}))
+	res, ok := rr.Message.(*createPartitions.Response)
+	require.True(t, ok)
+	require.Equal(t, kafka.None, res.Results[0].ErrorCode)
+	// the request is logged asynchronously
+	require.Eventually(t, func() bool {
+		return len(sm.GetEvents(events.NewTraits().WithNamespace("kafka").With("type", "request"))) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	rErr, err := s.Topic("foo").WritePartition(2, &kafka.Record{
+		Key:   kafka.NewBytes([]byte("key-1")),
+		Value: kafka.NewBytes([]byte("a")),
+	})
+	require.NoError(t, err)
+	require.Nil(t, rErr)
+
+	// the loaded config is not changed by clients
+	require.Equal(t, 1, cfg.Channels["foo"].Value.Bindings.Kafka.Partitions)
+
+	// reload of the file
+	s.Update(newConfig())
+
+	topic := s.Topic("foo")
+	require.Len(t, topic.Partitions, 3)
+	require.Equal(t, 3, topic.Bindings().Partitions)
+	b, errCode := topic.Partition(2).Read(0, 1000)
+	require.Equal(t, kafka.None, errCode)
+	require.Len(t, b.Records, 1)
+	require.Equal(t, "a", kafka.BytesToString(b.Records[0].Value))
+	require.DirExists(t, filepath.Join(dir, "foo-2"))
+}
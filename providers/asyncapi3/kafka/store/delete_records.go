@@ -0,0 +1,56 @@
+package store
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/deleteRecords"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (s *Store) deleteRecords(rw kafka.ResponseWriter, req *kafka.Request) error {
+	r := req.Message.(*deleteRecords.Request)
+
+	reqLog := &KafkaDeleteRecordsRequest{Topics: map[string]map[int]int64{}}
+	resLog := &KafkaDeleteRecordsResponse{Topics: map[string]map[int]KafkaDeleteRecordsPartition{}}
+
+	res := &deleteRecords.Response{}
+	for _, t := range r.Topics {
+		reqLog.Topics[t.Name] = map[int]int64{}
+		resLog.Topics[t.Name] = map[int]KafkaDeleteRecordsPartition{}
+		resTopic := deleteRecords.ResponseTopic{Name: t.Name}
+
+		topic := s.Topic(t.Name)
+		for _, rp := range t.Partitions {
+			reqLog.Topics[t.Name][int(rp.PartitionIndex)] = rp.Offset
+			resPartition := deleteRecords.ResponsePartition{PartitionIndex: rp.PartitionIndex, LowWatermark: -1}
+
+			var p *Partition
+			if topic != nil {
+				p = topic.Partition(int(rp.PartitionIndex))
+			}
+			if p == nil {
+				resPartition.ErrorCode = kafka.UnknownTopicOrPartition
+			} else {
+				resPartition.LowWatermark, resPartition.ErrorCode = p.deleteRecords(rp.Offset)
+			}
+
+			if resPartition.ErrorCode != kafka.None {
+				log.Errorf("kafka DeleteRecords: topic %v partition %v: %v", t.Name, rp.PartitionIndex, resPartition.ErrorCode)
+			} else {
+				log.Infof("kafka: deleted records before offset %v from partition %v topic '%s'", resPartition.LowWatermark, rp.PartitionIndex, t.Name)
+			}
+
+			resTopic.Partitions = append(resTopic.Partitions, resPartition)
+			partitionLog := KafkaDeleteRecordsPartition{LowWatermark: resPartition.LowWatermark}
+			if resPartition.ErrorCode != kafka.None {
+				partitionLog.ErrorCode = resPartition.ErrorCode.String()
+			}
+			resLog.Topics[t.Name][int(rp.PartitionIndex)] = partitionLog
+		}
+		res.Topics = append(res.Topics, resTopic)
+	}
+
+	go s.logRequest(req.Header, reqLog)(resLog)
+
+	return rw.Write(res)
+}
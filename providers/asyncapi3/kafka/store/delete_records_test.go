@@ -0,0 +1,115 @@
+package store_test
+
+import (
+	"mokapi/engine/enginetest"
+	"mokapi/kafka"
+	"mokapi/kafka/deleteRecords"
+	"mokapi/kafka/kafkatest"
+	"mokapi/providers/asyncapi3"
+	"mokapi/providers/asyncapi3/asyncapi3test"
+	"mokapi/providers/asyncapi3/kafka/store"
+	"mokapi/runtime/events/eventstest"
+	"mokapi/runtime/monitor"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteRecords(t *testing.T) {
+	testcases := []struct {
+		name   string
+		offset int64
+		test   func(t *testing.T, p *store.Partition, res deleteRecords.ResponsePartition)
+	}{
+		{
+			name:   "delete records before offset",
+			offset: 2,
+			test: func(t *testing.T, p *store.Partition, res deleteRecords.ResponsePartition) {
+				require.Equal(t, kafka.None, res.ErrorCode)
+				require.Equal(t, int64(2), res.LowWatermark)
+				require.Equal(t, int64(2), p.Head)
+				require.Equal(t, int64(3), p.Tail)
+
+				_, errCode := p.Read(0, 1000)
+				require.Equal(t, kafka.OffsetOutOfRange, errCode)
+
+				b, errCode := p.Read(2, 1000)
+				require.Equal(t, kafka.None, errCode)
+				require.Len(t, b.Records, 1)
+				require.Equal(t, int64(2), b.Records[0].Offset)
+			},
+		},
+		{
+			name:   "delete all records",
+			offset: -1,
+			test: func(t *testing.T, p *store.Partition, res deleteRecords.ResponsePartition) {
+				require.Equal(t, kafka.None, res.ErrorCode)
+				require.Equal(t, int64(3), res.LowWatermark)
+				require.Equal(t, int64(3), p.Head)
+			},
+		},
+		{
+			name:   "offset out of range",
+			offset: 4,
+			test: func(t *testing.T, p *store.Partition, res deleteRecords.ResponsePartition) {
+				require.Equal(t, kafka.OffsetOutOfRange, res.ErrorCode)
+				require.Equal(t, int64(-1), res.LowWatermark)
+				require.Equal(t, int64(0), p.Head)
+			},
+		},
+	}
+
+	t.Parallel()
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := asyncapi3test.NewConfig(
+				asyncapi3test.WithChannel("foo", asyncapi3test.WithKafkaChannelBinding(asyncapi3.TopicBindings{Partitions: 1})),
+			)
+			s := store.New(cfg, enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+			defer s.Close()
+
+			topic := s.Topic("foo")
+			for _, v := range []string{"a", "b", "c"} {
+				writeKeyValue(t, topic, v, v)
+			}
+
+			rr := kafkatest.NewRecorder()
+			s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 2, &deleteRecords.Request{
+				Topics: []deleteRecords.Topic{
+					{
+						Name:       "foo",
+						Partitions: []deleteRecords.Partition{{PartitionIndex: 0, Offset: tc.offset}},
+					},
+				},
+			}))
+
+			res, ok := rr.Message.(*deleteRecords.Response)
+			require.True(t, ok)
+			require.Equal(t, "foo", res.Topics[0].Name)
+			tc.test(t, topic.Partition(0), res.Topics[0].Partitions[0])
+		})
+	}
+}
+
+func TestDeleteRecords_UnknownPartition(t *testing.T) {
+	cfg := asyncapi3test.NewConfig(
+		asyncapi3test.WithChannel("foo", asyncapi3test.WithKafkaChannelBinding(asyncapi3.TopicBindings{Partitions: 1})),
+	)
+	s := store.New(cfg, enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+	defer s.Close()
+
+	rr := kafkatest.NewRecorder()
+	s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 2, &deleteRecords.Request{
+		Topics: []deleteRecords.Topic{
+			{Name: "foo", Partitions: []deleteRecords.Partition{{PartitionIndex: 1, Offset: 0}}},
+			{Name: "bar", Partitions: []deleteRecords.Partition{{PartitionIndex: 0, Offset: 0}}},
+		},
+	}))
+
+	res, ok := rr.Message.(*deleteRecords.Response)
+	require.True(t, ok)
+	require.Equal(t, kafka.UnknownTopicOrPartition, res.Topics[0].Partitions[0].ErrorCode)
+	require.Equal(t, kafka.UnknownTopicOrPartition, res.Topics[1].Partitions[0].ErrorCode)
+}
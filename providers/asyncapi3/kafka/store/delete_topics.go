@@ -0,0 +1,34 @@
+package store
+
+import (
+	"fmt"
+	"mokapi/kafka"
+	"mokapi/kafka/deleteTopics"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (s *Store) deletetopics(rw kafka.ResponseWriter, req *kafka.Request) error {
+	r := req.Message.(*deleteTopics.Request)
+
+	reqLog := &KafkaDeleteTopicsRequest{Topics: r.TopicNames}
+	resLog := &KafkaDeleteTopicsResponse{Topics: map[string]string{}}
+
+	res := &deleteTopics.Response{}
+	for _, name := range r.TopicNames {
+		result := deleteTopics.ResponseTopic{Name: name}
+		if s.Topic(name) == nil {
+			result.ErrorCode = kafka.UnknownTopicOrPartition
+			result.ErrorMessage = fmt.Sprintf("topic '%v' does not exist", name)
+		} else {
+			s.deleteTopic(name)
+			log.Infof("kafka: topic %v deleted", name)
+		}
+		res.Responses = append(res.Responses, result)
+		resLog.Topics[name] = result.ErrorCode.String()
+	}
+
+	go s.logRequest(req.Header, reqLog)(resLog)
+
+	return rw.Write(res)
+}
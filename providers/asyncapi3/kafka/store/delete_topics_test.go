@@ -0,0 +1,36 @@
+package store_test
+
+import (
+	"mokapi/engine/enginetest"
+	"mokapi/kafka"
+	"mokapi/kafka/deleteTopics"
+	"mokapi/kafka/kafkatest"
+	"mokapi/providers/asyncapi3"
+	"mokapi/providers/asyncapi3/asyncapi3test"
+	"mokapi/providers/asyncapi3/kafka/store"
+	"mokapi/runtime/events/eventstest"
+	"mokapi/runtime/monitor"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteTopics(t *testing.T) {
+	s := store.New(asyncapi3test.NewConfig(asyncapi3test.AddChannel("foo", &asyncapi3.Channel{})), enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+	defer s.Close()
+
+	rr := kafkatest.NewRecorder()
+	s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 4, &deleteTopics.Request{
+		TopicNames: []string{"foo", "bar"},
+	}))
+
+	res, ok := rr.Message.(*deleteTopics.Response)
+	require.True(t, ok)
+	require.Len(t, res.Responses, 2)
+	require.Equal(t, "foo", res.Responses[0].Name)
+	require.Equal(t, kafka.None, res.Responses[0].ErrorCode)
+	require.Equal(t, "bar", res.Responses[1].Name)
+	require.Equal(t, kafka.UnknownTopicOrPartition, res.Responses[1].ErrorCode)
+
+	require.Nil(t, s.Topic("foo"))
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/deleteGroups"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (s *Store) deletegroups(rw kafka.ResponseWriter, req *kafka.Request) error {
+	r := req.Message.(*deleteGroups.Request)
+
+	reqLog := &KafkaDeleteGroupsRequest{Groups: r.GroupNames}
+	resLog := &KafkaDeleteGroupsResponse{Groups: map[string]string{}}
+
+	res := &deleteGroups.Response{}
+	for _, name := range r.GroupNames {
+		errCode := s.deleteGroup(name)
+		res.Results = append(res.Results, deleteGroups.Result{
+			GroupId:   name,
+			ErrorCode: errCode,
+		})
+		resLog.Groups[name] = errCode.String()
+	}
+	s.saveGroups()
+
+	go s.logRequest(req.Header, reqLog)(resLog)
+
+	return rw.Write(res)
+}
+
+func (s *Store) deleteGroup(name string) kafka.ErrorCode {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	g, ok := s.groups[name]
+	if !ok {
+		return kafka.GroupIdNotFound
+	}
+	if snapshot, ok := g.balancer.Snapshot(); ok && len(snapshot.Members) > 0 {
+		return kafka.NonEmptyGroup
+	}
+
+	g.balancer.Stop()
+	delete(s.groups, name)
+	log.Infof("kafka: group %v deleted", name)
+
+	return kafka.None
+}
@@ -0,0 +1,85 @@
+package store_test
+
+import (
+	"mokapi/engine/enginetest"
+	"mokapi/kafka"
+	"mokapi/kafka/deleteGroups"
+	"mokapi/kafka/kafkatest"
+	"mokapi/providers/asyncapi3/asyncapi3test"
+	"mokapi/providers/asyncapi3/kafka/store"
+	"mokapi/runtime/events/eventstest"
+	"mokapi/runtime/monitor"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteGroups(t *testing.T) {
+	testcases := []struct {
+		name string
+		fn   func(t *testing.T, s *store.Store)
+	}{
+		{
+			name: "unknown group",
+			fn: func(t *testing.T, s *store.Store) {
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 2, &deleteGroups.Request{
+					GroupNames: []string{"foo"},
+				}))
+				res, ok := rr.Message.(*deleteGroups.Response)
+				require.True(t, ok)
+				require.Len(t, res.Results, 1)
+				require.Equal(t, "foo", res.Results[0].GroupId)
+				require.Equal(t, kafka.GroupIdNotFound, res.Results[0].ErrorCode)
+			},
+		},
+		{
+			name: "delete empty group",
+			fn: func(t *testing.T, s *store.Store) {
+				g := s.GetOrCreateGroup("foo", &store.Broker{})
+				g.Commit("bar", 0, 10)
+
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 2, &deleteGroups.Request{
+					GroupNames: []string{"foo"},
+				}))
+				res, ok := rr.Message.(*deleteGroups.Response)
+				require.True(t, ok)
+				require.Equal(t, kafka.None, res.Results[0].ErrorCode)
+
+				_, ok = s.Group("foo")
+				require.False(t, ok)
+			},
+		},
+		{
+			name: "group with active members",
+			fn: func(t *testing.T, s *store.Store) {
+				g := s.GetOrCreateGroup("foo", &store.Broker{})
+				g.NewGeneration().Members["m1"] = &store.Member{}
+
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 0, &deleteGroups.Request{
+					GroupNames: []string{"foo"},
+				}))
+				res, ok := rr.Message.(*deleteGroups.Response)
+				require.True(t, ok)
+				require.Equal(t, kafka.NonEmptyGroup, res.Results[0].ErrorCode)
+
+				_, ok = s.Group("foo")
+				require.True(t, ok)
+			},
+		},
+	}
+
+	t.Parallel()
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := store.New(asyncapi3test.NewConfig(), enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+			defer s.Close()
+			tc.fn(t, s)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"fmt"
+	"mokapi/kafka"
+	"mokapi/kafka/describeConfigs"
+	"mokapi/providers/asyncapi3"
+	"slices"
+	"strconv"
+)
+
+func (s *Store) describeConfigs(rw kafka.ResponseWriter, req *kafka.Request) error {
+	r := req.Message.(*describeConfigs.Request)
+
+	reqLog := &KafkaDescribeConfigsRequest{}
+	resLog := &KafkaConfigsResponse{}
+
+	res := &describeConfigs.Response{}
+	for _, resource := range r.Resources {
+		reqLog.Resources = append(reqLog.Resources, KafkaConfigResource{
+			Type: resourceTypeString(resource.ResourceType),
+			Name: resource.ResourceName,
+		})
+
+		result := describeConfigs.Result{
+			ResourceType: resource.ResourceType,
+			ResourceName: resource.ResourceName,
+		}
+
+		switch resource.ResourceType {
+		case describeConfigs.ResourceTopic:
+			result.Configs, result.ErrorCode, result.ErrorMessage = s.describeTopicConfigs(resource, s.getBrokerByPort(req.Host))
+		case describeConfigs.ResourceBroker:
+			result.Configs, result.ErrorCode, result.ErrorMessage = s.describeBrokerConfigs(resource, s.getBrokerByPort(req.Host))
+		default:
+			result.ErrorCode = kafka.InvalidRequest
+			result.ErrorMessage = fmt.Sprintf("unsupported resource type %v", resource.ResourceType)
+		}
+
+		if !r.IncludeSynonyms {
+			for i := range result.Configs {
+				result.Configs[i].Synonyms = nil
+			}
+		}
+		res.Results = append(res.Results, result)
+
+		resourceLog := KafkaConfigResourceResult{
+			KafkaConfigResource: KafkaConfigResource{
+				Type:    resourceTypeString(resource.ResourceType),
+				Name:    resource.ResourceName,
+				Configs: map[string]string{},
+			},
+		}
+		if result.ErrorCode != kafka.None {
+			resourceLog.ErrorCode = result.ErrorCode.String()
+			resourceLog.ErrorMessage = result.ErrorMessage
+		}
+		for _, c := range result.Configs {
+			resourceLog.Configs[c.Name] = c.Value
+		}
+		resLog.Resources = append(resLog.Resources, resourceLog)
+	}
+
+	go s.logRequest(req.Header, reqLog)(resLog)
+
+	return rw.Write(res)
+}
+
+func (s *Store) describeTopicConfigs(resource describeConfigs.Resource, b *Broker) ([]describeConfigs.Config, kafka.ErrorCode, string) {
+	t := s.Topic(resource.ResourceName)
+	if t == nil {
+		return nil, kafka.UnknownTopicOrPartition, fmt.Sprintf("topic '%v' does not exist", resource.ResourceName)
+	}
+
+	var brokerBindings asyncapi3.BrokerBindings
+	if b != nil {
+		brokerBindings = b.kafkaConfig
+	}
+
+	bindings := t.Bindings()
+	var configs []describeConfigs.Config
+	for _, c := range topicConfigs {
+		if len(resource.ConfigurationKeys) > 0 && !slices.Contains(resource.ConfigurationKeys, c.name) {
+			continue
+		}
+		configs = append(configs, c.describe(&bindings, brokerBindings))
+	}
+	return configs, kafka.None, ""
+}
+
+// describeBrokerConfigs returns the configs of the broker given by its id. An
+// empty resource name refers to the broker which received the request.
+func (s *Store) describeBrokerConfigs(resource describeConfigs.Resource, b *Broker) ([]describeConfigs.Config, kafka.ErrorCode, string) {
+	if resource.ResourceName != "" {
+		b = nil
+		id, err := strconv.Atoi(resource.ResourceName)
+		if err == nil {
+			for _, broker := range s.Brokers() {
+				if broker.Id == id {
+					b = broker
+				}
+			}
+		}
+	}
+	if b == nil {
+		return nil, kafka.InvalidRequest, fmt.Sprintf("unknown broker '%v'", resource.ResourceName)
+	}
+
+	var configs []describeConfigs.Config
+	for _, c := range brokerConfigs {
+		if len(resource.ConfigurationKeys) > 0 && !slices.Contains(resource.ConfigurationKeys, c.name) {
+			continue
+		}
+		cfg := describeConfigs.Config{
+			Name:         c.name,
+			Value:        c.defaultValue,
+			IsDefault:    true,
+			ConfigSource: describeConfigs.SourceDefaultConfig,
+			ConfigType:   describeConfigs.TypeLong,
+			ReadOnly:     true,
+		}
+		if v := c.get(b.kafkaConfig); v != 0 {
+			cfg.Value = strconv.FormatInt(v, 10)
+			cfg.IsDefault = false
+			cfg.ConfigSource = describeConfigs.SourceStaticBrokerConfig
+		}
+		cfg.Synonyms = []describeConfigs.Synonym{{Name: cfg.Name, Value: cfg.Value, Source: cfg.ConfigSource}}
+		configs = append(configs, cfg)
+	}
+	return configs, kafka.None, ""
+}
@@ -0,0 +1,264 @@
+package store_test
+
+import (
+	"mokapi/engine/enginetest"
+	"mokapi/kafka"
+	"mokapi/kafka/alterConfigs"
+	"mokapi/kafka/describeConfigs"
+	"mokapi/kafka/kafkatest"
+	"mokapi/providers/asyncapi3"
+	"mokapi/providers/asyncapi3/asyncapi3test"
+	"mokapi/providers/asyncapi3/kafka/store"
+	"mokapi/runtime/events/eventstest"
+	"mokapi/runtime/monitor"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newConfigsTestConfig() *asyncapi3.Config {
+	return asyncapi3test.NewConfig(
+		asyncapi3test.WithServer("foo", "kafka", "127.0.0.1:9092",
+			asyncapi3test.WithKafkaServerBinding(asyncapi3.BrokerBindings{
+				LogSegmentBytes: 1024,
+			}),
+		),
+		asyncapi3test.WithChannel("foo", asyncapi3test.WithKafkaChannelBinding(asyncapi3.TopicBindings{
+			Partitions:  1,
+			RetentionMs: 60000,
+		})),
+	)
+}
+
+func TestDescribeConfigs(t *testing.T) {
+	testcases := []struct {
+		name string
+		req  *describeConfigs.Request
+		test func(t *testing.T, res *describeConfigs.Response)
+	}{
+		{
+			name: "topic config",
+			req: &describeConfigs.Request{
+				Resources: []describeConfigs.Resource{
+					{
+						ResourceType:      describeConfigs.ResourceTopic,
+						ResourceName:      "foo",
+						ConfigurationKeys: []string{"retention.ms", "segment.bytes", "cleanup.policy"},
+					},
+				},
+				IncludeSynonyms: true,
+			},
+			test: func(t *testing.T, res *describeConfigs.Response) {
+				r := res.Results[0]
+				require.Equal(t, kafka.None, r.ErrorCode)
+				require.Len(t, r.Configs, 3)
+
+				require.Equal(t, "cleanup.policy", r.Configs[0].Name)
+				require.Equal(t, "delete", r.Configs[0].Value)
+				require.True(t, r.Configs[0].IsDefault)
+				require.Equal(t, describeConfigs.SourceDefaultConfig, r.Configs[0].ConfigSource)
+
+				require.Equal(t, "retention.ms", r.Configs[1].Name)
+				require.Equal(t, "60000", r.Configs[1].Value)
+				require.Equal(t, describeConfigs.SourceDynamicTopicConfig, r.Configs[1].ConfigSource)
+				require.Len(t, r.Configs[1].Synonyms, 2)
+
+				require.Equal(t, "segment.bytes", r.Configs[2].Name)
+				require.Equal(t, "1024", r.Configs[2].Value)
+				require.Equal(t, describeConfigs.SourceStaticBrokerConfig, r.Configs[2].ConfigSource)
+				require.Equal(t, []describeConfigs.Synonym{
+					{Name: "log.segment.bytes", Value: "1024", Source: describeConfigs.SourceStaticBrokerConfig},
+					{Name: "segment.bytes", Value: "1073741824", Source: describeConfigs.SourceDefaultConfig},
+				}, r.Configs[2].Synonyms)
+			},
+		},
+		{
+			name: "all topic configs without synonyms",
+			req: &describeConfigs.Request{
+				Resources: []describeConfigs.Resource{
+					{ResourceType: describeConfigs.ResourceTopic, ResourceName: "foo"},
+				},
+			},
+			test: func(t *testing.T, res *describeConfigs.Response) {
+				r := res.Results[0]
+				require.Equal(t, kafka.None, r.ErrorCode)
+				require.Len(t, r.Configs, 7)
+				for _, c := range r.Configs {
+					require.Nil(t, c.Synonyms)
+				}
+			},
+		},
+		{
+			name: "unknown topic",
+			req: &describeConfigs.Request{
+				Resources: []describeConfigs.Resource{
+					{ResourceType: describeConfigs.ResourceTopic, ResourceName: "bar"},
+				},
+			},
+			test: func(t *testing.T, res *describeConfigs.Response) {
+				require.Equal(t, kafka.UnknownTopicOrPartition, res.Results[0].ErrorCode)
+			},
+		},
+		{
+			name: "broker config",
+			req: &describeConfigs.Request{
+				Resources: []describeConfigs.Resource{
+					{
+						ResourceType:      describeConfigs.ResourceBroker,
+						ResourceName:      "",
+						ConfigurationKeys: []string{"log.segment.bytes", "log.retention.ms"},
+					},
+				},
+			},
+			test: func(t *testing.T, res *describeConfigs.Response) {
+				r := res.Results[0]
+				require.Equal(t, kafka.None, r.ErrorCode)
+				require.Len(t, r.Configs, 2)
+				require.Equal(t, "log.retention.ms", r.Configs[0].Name)
+				require.Equal(t, "604800000", r.Configs[0].Value)
+				require.True(t, r.Configs[0].IsDefault)
+				require.Equal(t, "log.segment.bytes", r.Configs[1].Name)
+				require.Equal(t, "1024", r.Configs[1].Value)
+				require.Equal(t, describeConfigs.SourceStaticBrokerConfig, r.Configs[1].ConfigSource)
+			},
+		},
+		{
+			name: "unknown broker",
+			req: &describeConfigs.Request{
+				Resources: []describeConfigs.Resource{
+					{ResourceType: describeConfigs.ResourceBroker, ResourceName: "99"},
+				},
+			},
+			test: func(t *testing.T, res *describeConfigs.Response) {
+				require.Equal(t, kafka.InvalidRequest, res.Results[0].ErrorCode)
+			},
+		},
+	}
+
+	t.Parallel()
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := store.New(newConfigsTestConfig(), enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+			defer s.Close()
+
+			rr := kafkatest.NewRecorder()
+			s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 3, tc.req))
+
+			res, ok := rr.Message.(*describeConfigs.Response)
+			require.True(t, ok)
+			tc.test(t, res)
+		})
+	}
+}
+
+func TestAlterConfigs(t *testing.T) {
+	testcases := []struct {
+		name string
+		req  *alterConfigs.Request
+		test func(t *testing.T, s *store.Store, res *alterConfigs.Response)
+	}{
+		{
+			name: "alter topic configs",
+			req: &alterConfigs.Request{
+				Resources: []alterConfigs.Resource{
+					{
+						ResourceType: describeConfigs.ResourceTopic,
+						ResourceName: "foo",
+						Configs: []alterConfigs.Config{
+							{Name: "retention.bytes", Value: "2048"},
+							{Name: "cleanup.policy", Value: "compact,delete"},
+						},
+					},
+				},
+			},
+			test: func(t *testing.T, s *store.Store, res *alterConfigs.Response) {
+				require.Equal(t, kafka.None, res.Responses[0].ErrorCode)
+				b := s.Topic("foo").Bindings()
+				require.Equal(t, int64(2048), b.RetentionBytes)
+				require.Equal(t, []string{"compact", "delete"}, b.CleanupPolicy)
+				// configs not part of the request are reset
+				require.Equal(t, int64(0), b.RetentionMs)
+				require.Equal(t, 1, b.Partitions)
+			},
+		},
+		{
+			name: "validate only",
+			req: &alterConfigs.Request{
+				Resources: []alterConfigs.Resource{
+					{
+						ResourceType: describeConfigs.ResourceTopic,
+						ResourceName: "foo",
+						Configs:      []alterConfigs.Config{{Name: "retention.ms", Value: "1000"}},
+					},
+				},
+				ValidateOnly: true,
+			},
+			test: func(t *testing.T, s *store.Store, res *alterConfigs.Response) {
+				require.Equal(t, kafka.None, res.Responses[0].ErrorCode)
+				require.Equal(t, int64(60000), s.Topic("foo").Bindings().RetentionMs)
+			},
+		},
+		{
+			name: "invalid value",
+			req: &alterConfigs.Request{
+				Resources: []alterConfigs.Resource{
+					{
+						ResourceType: describeConfigs.ResourceTopic,
+						ResourceName: "foo",
+						Configs:      []alterConfigs.Config{{Name: "retention.ms", Value: "foo"}},
+					},
+				},
+			},
+			test: func(t *testing.T, s *store.Store, res *alterConfigs.Response) {
+				require.Equal(t, kafka.InvalidConfig, res.Responses[0].ErrorCode)
+				require.Equal(t, "invalid value for config retention.ms: value 'foo' is not a number", res.Responses[0].ErrorMessage)
+				require.Equal(t, int64(60000), s.Topic("foo").Bindings().RetentionMs)
+			},
+		},
+		{
+			name: "unknown config",
+			req: &alterConfigs.Request{
+				Resources: []alterConfigs.Resource{
+					{
+						ResourceType: describeConfigs.ResourceTopic,
+						ResourceName: "foo",
+						Configs:      []alterConfigs.Config{{Name: "foo.bar", Value: "1"}},
+					},
+				},
+			},
+			test: func(t *testing.T, s *store.Store, res *alterConfigs.Response) {
+				require.Equal(t, kafka.InvalidConfig, res.Responses[0].ErrorCode)
+			},
+		},
+		{
+			name: "broker resource",
+			req: &alterConfigs.Request{
+				Resources: []alterConfigs.Resource{
+					{ResourceType: describeConfigs.ResourceBroker, ResourceName: "0"},
+				},
+			},
+			test: func(t *testing.T, s *store.Store, res *alterConfigs.Response) {
+				require.Equal(t, kafka.InvalidRequest, res.Responses[0].ErrorCode)
+			},
+		},
+	}
+
+	t.Parallel()
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := store.New(newConfigsTestConfig(), enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+			defer s.Close()
+
+			rr := kafkatest.NewRecorder()
+			s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 1, tc.req))
+
+			res, ok := rr.Message.(*alterConfigs.Response)
+			require.True(t, ok)
+			tc.test(t, s, res)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/describeGroups"
+)
+
+func (s *Store) describegroups(rw kafka.ResponseWriter, req *kafka.Request) error {
+	r := req.Message.(*describeGroups.Request)
+
+	res := &describeGroups.Response{}
+	for _, name := range r.Groups {
+		g, ok := s.Group(name)
+		if !ok {
+			// Kafka does not respond with an error for unknown groups
+			res.Groups = append(res.Groups, describeGroups.Group{
+				GroupId:    name,
+				GroupState: "Dead",
+			})
+			continue
+		}
+
+		snapshot, ok := g.balancer.Snapshot()
+		if !ok {
+			// group has been deleted in the meantime
+			res.Groups = append(res.Groups, describeGroups.Group{
+				GroupId:    name,
+				GroupState: "Dead",
+			})
+			continue
+		}
+
+		group := describeGroups.Group{
+			GroupId:      g.Name,
+			GroupState:   snapshot.State.String(),
+			ProtocolType: snapshot.ProtocolType,
+		}
+		if snapshot.State == Stable {
+			group.ProtocolData = snapshot.Protocol
+		}
+		for _, m := range snapshot.Members {
+			group.Members = append(group.Members, describeGroups.Member{
+				MemberId:         m.MemberId,
+				ClientId:         m.ClientId,
+				ClientHost:       m.ClientHost,
+				MemberMetadata:   m.Metadata,
+				MemberAssignment: m.Assignment,
+			})
+		}
+
+		res.Groups = append(res.Groups, group)
+	}
+
+	return rw.Write(res)
+}
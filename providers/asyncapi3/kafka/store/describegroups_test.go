@@ -0,0 +1,89 @@
+package store_test
+
+import (
+	"mokapi/engine/enginetest"
+	"mokapi/kafka"
+	"mokapi/kafka/describeGroups"
+	"mokapi/kafka/kafkatest"
+	"mokapi/providers/asyncapi3/asyncapi3test"
+	"mokapi/providers/asyncapi3/kafka/store"
+	"mokapi/runtime/events/eventstest"
+	"mokapi/runtime/monitor"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeGroups(t *testing.T) {
+	testcases := []struct {
+		name string
+		fn   func(t *testing.T, s *store.Store)
+	}{
+		{
+			name: "unknown group",
+			fn: func(t *testing.T, s *store.Store) {
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 5, &describeGroups.Request{
+					Groups: []string{"foo"},
+				}))
+				res, ok := rr.Message.(*describeGroups.Response)
+				require.True(t, ok)
+				require.Len(t, res.Groups, 1)
+				require.Equal(t, kafka.None, res.Groups[0].ErrorCode)
+				require.Equal(t, "foo", res.Groups[0].GroupId)
+				require.Equal(t, "Dead", res.Groups[0].GroupState)
+			},
+		},
+		{
+			name: "empty group",
+			fn: func(t *testing.T, s *store.Store) {
+				s.GetOrCreateGroup("foo", &store.Broker{})
+
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 5, &describeGroups.Request{
+					Groups: []string{"foo"},
+				}))
+				res, ok := rr.Message.(*describeGroups.Response)
+				require.True(t, ok)
+				require.Equal(t, "Empty", res.Groups[0].GroupState)
+				require.Len(t, res.Groups[0].Members, 0)
+			},
+		},
+		{
+			name: "stable group",
+			fn: func(t *testing.T, s *store.Store) {
+				b := kafkatest.NewBroker(kafkatest.WithHandler(s))
+				defer b.Close()
+				s.Update(asyncapi3test.NewConfig(asyncapi3test.WithServer("", "kafka", b.Addr)))
+				err := b.Client().JoinSyncGroup("foo", "TestGroup", 3, 3)
+				require.NoError(t, err)
+
+				res, err := b.Client().DescribeGroups(5, &describeGroups.Request{
+					Groups: []string{"TestGroup"},
+				})
+				require.NoError(t, err)
+				require.Len(t, res.Groups, 1)
+				g := res.Groups[0]
+				require.Equal(t, "Stable", g.GroupState)
+				require.Equal(t, "consumer", g.ProtocolType)
+				require.Equal(t, "range", g.ProtocolData)
+				require.Len(t, g.Members, 1)
+				require.Equal(t, "foo", g.Members[0].MemberId)
+				require.Equal(t, "kafkatest", g.Members[0].ClientId)
+				require.NotEmpty(t, g.Members[0].ClientHost)
+			},
+		},
+	}
+
+	t.Parallel()
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := store.New(asyncapi3test.NewConfig(), enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+			defer s.Close()
+			tc.fn(t, s)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package store
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/endTxn"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (s *Store) endTxn(rw kafka.ResponseWriter, req *kafka.Request) error {
+	r := req.Message.(*endTxn.Request)
+	res := &endTxn.Response{}
+
+	s.txm.Lock()
+	defer s.txm.Unlock()
+
+	txn, errCode := s.transaction(r.TransactionalId, r.ProducerId, r.ProducerEpoch)
+	switch {
+	case errCode != kafka.None:
+		res.ErrorCode = errCode
+	case txn.State == TransactionOngoing:
+		s.completeTransaction(txn, r.Committed)
+	case txn.State == TransactionCompleteCommit && r.Committed,
+		txn.State == TransactionCompleteAbort && !r.Committed:
+		// retry of an already completed request
+	case txn.State == TransactionEmpty && !r.Committed:
+		// aborting a transaction without any records or offsets
+		txn.State = TransactionCompleteAbort
+	default:
+		res.ErrorCode = kafka.InvalidTxnState
+	}
+
+	if res.ErrorCode != kafka.None {
+		log.Errorf("kafka EndTxn: transaction '%v': %v", r.TransactionalId, res.ErrorCode)
+	}
+
+	return rw.Write(res)
+}
@@ -4,7 +4,7 @@ import (
 	"mokapi/kafka"
 )
 
-type Trigger func(record *kafka.Record, schemaId int) bool
+type Trigger func(record *kafka.Record, schemaId int, ctx *EventContext) bool
 
 type EventRecord struct {
 	Offset   int64
@@ -13,3 +13,11 @@ type EventRecord struct {
 	SchemaId int
 	Headers  map[string]string
 }
+
+type EventContext struct {
+	Topic     string
+	Partition int
+	ClientId  string
+	// Principal is the user authenticated via SASL, empty otherwise
+	Principal string
+}
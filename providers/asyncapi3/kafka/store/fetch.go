@@ -4,18 +4,24 @@ import (
 	"math"
 	"mokapi/kafka"
 	"mokapi/kafka/fetch"
+	"slices"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// isolation level of consumers which only read committed transactional records
+const readCommitted = 1
+
 type fetchData struct {
-	fetchOffset int64
-	batch       kafka.RecordBatch
-	maxBytes    int
-	error       kafka.ErrorCode
-	offset      int64
-	startOffset int64
+	fetchOffset      int64
+	batch            kafka.RecordBatch
+	maxBytes         int
+	error            kafka.ErrorCode
+	offset           int64
+	startOffset      int64
+	lastStableOffset int64
+	aborted          []fetch.AbortedTransaction
 }
 
 func (s *Store) fetch(rw kafka.ResponseWriter, req *kafka.Request) error {
@@ -61,9 +67,25 @@ func (s *Store) fetch(rw kafka.ResponseWriter, req *kafka.Request) error {
 
 				data.offset = p.Offset()
 				data.startOffset = p.StartOffset()
+				data.lastStableOffset = p.LastStableOffset()
 
 				var batch kafka.RecordBatch
-				batch, data.error = p.Read(data.fetchOffset, data.maxBytes)
+				if f.IsolationLevel == readCommitted {
+					batch, data.error = p.ReadCommitted(data.fetchOffset, data.maxBytes)
+				} else {
+					batch, data.error = p.Read(data.fetchOffset, data.maxBytes)
+				}
+				if len(batch.Records) == 0 {
+					continue
+				}
+				if len(data.batch.Records) == 0 {
+					data.batch.Compression = batch.Compression
+					data.batch.Transactional = batch.Transactional
+					data.batch.Control = batch.Control
+				} else if !sameBatch(data.batch, batch) {
+					// records of a different batch are returned by the next fetch
+					continue
+				}
 				batchSize := batch.Size()
 				size += int32(batchSize)
 				if size > maxSize {
@@ -72,7 +94,21 @@ func (s *Store) fetch(rw kafka.ResponseWriter, req *kafka.Request) error {
 					break
 				}
 				data.maxBytes -= batchSize
-				data.fetchOffset += int64(len(batch.Records))
+				// offsets may have gaps after log compaction
+				nextOffset := batch.Records[len(batch.Records)-1].Offset + 1
+				if f.IsolationLevel == readCommitted && batch.Transactional && !batch.Control {
+					for _, a := range p.AbortedTransactions(data.fetchOffset, nextOffset) {
+						if !slices.ContainsFunc(data.aborted, func(t fetch.AbortedTransaction) bool {
+							return t.ProducerId == a.ProducerId && t.FirstOffset == a.FirstOffset
+						}) {
+							data.aborted = append(data.aborted, fetch.AbortedTransaction{
+								ProducerId:  a.ProducerId,
+								FirstOffset: a.FirstOffset,
+							})
+						}
+					}
+				}
+				data.fetchOffset = nextOffset
 				data.batch.Records = append(data.batch.Records, batch.Records...)
 			}
 		}
@@ -93,7 +129,8 @@ func (s *Store) fetch(rw kafka.ResponseWriter, req *kafka.Request) error {
 			resPar := fetch.ResponsePartition{
 				Index:                index,
 				HighWatermark:        data.offset,
-				LastStableOffset:     data.offset,
+				LastStableOffset:     data.lastStableOffset,
+				AbortedTransactions:  data.aborted,
 				LogStartOffset:       data.startOffset,
 				PreferredReadReplica: -1,
 				RecordSet:            data.batch,
@@ -109,3 +146,11 @@ func (s *Store) fetch(rw kafka.ResponseWriter, req *kafka.Request) error {
 
 	return rw.Write(res)
 }
+
+// sameBatch reports whether the records of b can be sent in the record batch a
+func sameBatch(a, b kafka.RecordBatch) bool {
+	if a.Compression != b.Compression || a.Transactional != b.Transactional || a.Control != b.Control {
+		return false
+	}
+	return !a.Transactional || a.Records[0].ProducerId == b.Records[0].ProducerId
+}
@@ -19,7 +19,7 @@ func (s *Store) findCoordinator(rw kafka.ResponseWriter, req *kafka.Request) err
 	resLog := &KafkaFindCoordinatorResponse{}
 
 	switch r.KeyType {
-	case findCoordinator.KeyTypeGroup:
+	case findCoordinator.KeyTypeGroup, findCoordinator.KeyTypeTransaction:
 		host, port := parseHostAndPort(req.Host)
 		b := s.getBrokerByPort(req.Host)
 		if b != nil && b.Host != "" {
@@ -1,8 +1,10 @@
 package store
 
 import (
+	"maps"
 	"mokapi/kafka"
 	"mokapi/runtime/monitor"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -33,6 +35,8 @@ type Group struct {
 	Commits map[string]map[int]int64
 
 	balancer *groupBalancer
+	// m guards Commits
+	m sync.RWMutex
 }
 
 func (s *Store) newGroup(name string, coordinator *Broker) *Group {
@@ -45,10 +49,11 @@ func (s *Store) newGroup(name string, coordinator *Broker) *Group {
 }
 
 type Generation struct {
-	Id       int
-	Protocol string
-	LeaderId string
-	Members  map[string]*Member
+	Id           int
+	ProtocolType string
+	Protocol     string
+	LeaderId     string
+	Members      map[string]*Member
 
 	RebalanceTimeoutMs int
 }
@@ -57,6 +62,10 @@ type Member struct {
 	Partitions     map[string][]int
 	Client         *kafka.ClientContext
 	SessionTimeout int
+	// Metadata is the protocol metadata sent by the member when joining
+	Metadata []byte
+	// Assignment is the raw assignment received from the group leader
+	Assignment []byte
 }
 
 func (g *Group) NewGeneration() *Generation {
@@ -74,6 +83,9 @@ func (g *Group) NewGeneration() *Generation {
 }
 
 func (g *Group) Commit(topic string, partition int, offset int64) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
 	if g.Commits == nil {
 		g.Commits = make(map[string]map[int]int64)
 	}
@@ -87,6 +99,9 @@ func (g *Group) Commit(topic string, partition int, offset int64) {
 }
 
 func (g *Group) Offset(topic string, partition int) int64 {
+	g.m.RLock()
+	defer g.m.RUnlock()
+
 	if t, ok := g.Commits[topic]; ok {
 		if offset, ok := t[partition]; ok {
 			return offset
@@ -98,6 +113,29 @@ func (g *Group) Offset(topic string, partition int) int64 {
 	return -1
 }
 
+// CommittedOffsets returns a copy of the committed offsets by topic and partition
+func (g *Group) CommittedOffsets() map[string]map[int]int64 {
+	g.m.RLock()
+	defer g.m.RUnlock()
+
+	commits := make(map[string]map[int]int64, len(g.Commits))
+	for topic, partitions := range g.Commits {
+		commits[topic] = maps.Clone(partitions)
+	}
+	return commits
+}
+
+// Snapshot returns a copy of the group state taken by the balancer. The
+// second value is false if the group has been deleted.
+func (g *Group) Snapshot() (GroupSnapshot, bool) {
+	return g.balancer.Snapshot()
+}
+
+// IsEmpty reports whether the group has no active members
+func (g *Group) IsEmpty() bool {
+	return g.Generation == nil || len(g.Generation.Members) == 0
+}
+
 func (g GroupState) String() string {
 	switch g {
 	case Empty:
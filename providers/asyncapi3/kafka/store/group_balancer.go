@@ -1,6 +1,7 @@
 package store
 
 import (
+	"maps"
 	"mokapi/kafka"
 	"mokapi/kafka/joinGroup"
 	"mokapi/kafka/syncGroup"
@@ -11,10 +12,14 @@ import (
 )
 
 type groupBalancer struct {
-	group *Group
-	join  chan joindata
-	sync  chan syncdata
-	stop  chan bool
+	group    *Group
+	join     chan joindata
+	sync     chan syncdata
+	leave    chan leavedata
+	describe chan chan GroupSnapshot
+	stop     chan bool
+	// done is closed when the balancer has stopped
+	done chan struct{}
 
 	joins   []joindata
 	config  asyncapi3.BrokerBindings
@@ -41,6 +46,32 @@ type syncdata struct {
 	log          func(res any)
 }
 
+type leavedata struct {
+	memberId string
+	result   chan kafka.ErrorCode
+}
+
+// GroupSnapshot is a copy of the group state taken by the balancer, so it
+// can be read without racing with join and sync requests
+type GroupSnapshot struct {
+	State GroupState
+	// Generation is -1 if no member has joined the group yet
+	Generation   int
+	ProtocolType string
+	Protocol     string
+	LeaderId     string
+	Members      []MemberSnapshot
+}
+
+type MemberSnapshot struct {
+	MemberId   string
+	ClientId   string
+	ClientHost string
+	Metadata   []byte
+	Assignment []byte
+	Partitions map[string][]int
+}
+
 type protocoldata struct {
 	counter  int
 	metadata map[string][]byte
@@ -55,20 +86,51 @@ type groupAssignment struct {
 
 func newGroupBalancer(group *Group, config asyncapi3.BrokerBindings, monitor *groupMonitor) *groupBalancer {
 	return &groupBalancer{
-		group:   group,
-		join:    make(chan joindata),
-		sync:    make(chan syncdata),
-		stop:    make(chan bool, 1),
-		config:  config,
-		monitor: monitor,
+		group:    group,
+		join:     make(chan joindata),
+		sync:     make(chan syncdata),
+		leave:    make(chan leavedata),
+		describe: make(chan chan GroupSnapshot),
+		stop:     make(chan bool, 1),
+		done:     make(chan struct{}),
+		config:   config,
+		monitor:  monitor,
 	}
 }
 
 func (b *groupBalancer) Stop() {
-	b.stop <- true
+	select {
+	case b.stop <- true:
+	case <-b.done:
+	}
+}
+
+// Leave removes the member from the group. A stopped balancer, for example
+// of a deleted group, reports the member as unknown.
+func (b *groupBalancer) Leave(memberId string) kafka.ErrorCode {
+	result := make(chan kafka.ErrorCode, 1)
+	select {
+	case b.leave <- leavedata{memberId: memberId, result: result}:
+		return <-result
+	case <-b.done:
+		return kafka.UnknownMemberId
+	}
+}
+
+// Snapshot returns a copy of the group state. The second value is false if
+// the balancer has stopped.
+func (b *groupBalancer) Snapshot() (GroupSnapshot, bool) {
+	result := make(chan GroupSnapshot, 1)
+	select {
+	case b.describe <- result:
+		return <-result, true
+	case <-b.done:
+		return GroupSnapshot{}, false
+	}
 }
 
 func (b *groupBalancer) run() {
+	defer close(b.done)
 	stop := make(chan bool, 1)
 	var syncs []syncdata
 	var assigns map[string]*groupAssignment
@@ -139,8 +201,13 @@ func (b *groupBalancer) run() {
 				}
 
 				for memberName, assign := range assigns {
+					member, ok := b.group.Generation.Members[memberName]
+					if !ok {
+						continue
+					}
+					member.Assignment = assign.raw
 					for topicName, partitions := range assign.topics {
-						b.group.Generation.Members[memberName].Partitions[topicName] = partitions
+						member.Partitions[topicName] = partitions
 					}
 				}
 
@@ -159,8 +226,63 @@ func (b *groupBalancer) run() {
 				}
 				b.respond(s.writer, res)
 			}
+		case r := <-b.describe:
+			r <- b.snapshot()
+		case l := <-b.leave:
+			if b.group.Generation == nil {
+				l.result <- kafka.UnknownMemberId
+				continue
+			}
+			m, ok := b.group.Generation.Members[l.memberId]
+			if !ok {
+				l.result <- kafka.UnknownMemberId
+				continue
+			}
+			delete(b.group.Generation.Members, l.memberId)
+			delete(m.Client.Member, b.group.Name)
+			log.Infof("kafka: consumer '%v' left the group '%v'", m.Client.ClientId, b.group.Name)
+
+			if len(b.group.Generation.Members) == 0 {
+				log.Infof("kafka: group %v state changed from %v to %v", b.group.Name, states[b.group.State], states[Empty])
+				b.group.State = Empty
+			} else if b.group.State != PreparingRebalance {
+				// remaining members are informed by heartbeat and rejoin the group
+				prepareRebalance()
+			}
+			l.result <- kafka.None
+		}
+	}
+}
+
+func (b *groupBalancer) snapshot() GroupSnapshot {
+	g := b.group
+	if g.Generation == nil {
+		return GroupSnapshot{State: Empty, Generation: -1}
+	}
+	if len(g.Generation.Members) == 0 {
+		return GroupSnapshot{State: Empty, Generation: g.Generation.Id}
+	}
+	snapshot := GroupSnapshot{
+		State:        g.State,
+		Generation:   g.Generation.Id,
+		ProtocolType: g.Generation.ProtocolType,
+		Protocol:     g.Generation.Protocol,
+		LeaderId:     g.Generation.LeaderId,
+	}
+	for memberId, m := range g.Generation.Members {
+		ms := MemberSnapshot{
+			MemberId:   memberId,
+			Metadata:   m.Metadata,
+			Assignment: m.Assignment,
+			Partitions: maps.Clone(m.Partitions),
 		}
+		if m.Client != nil {
+			ms.ClientId = m.Client.ClientId
+			ms.ClientHost = m.Client.Addr
+		}
+		snapshot.Members = append(snapshot.Members, ms)
 	}
+	return snapshot
 }
 
 func (b *groupBalancer) finishJoin(stop chan bool) {
@@ -217,8 +339,12 @@ StopWaitingForConsumers:
 	}
 
 	generation.Protocol = protocol
+	for memberId, m := range generation.Members {
+		m.Metadata = counter[protocol].metadata[memberId]
+	}
 
 	leader := b.joins[0]
+	generation.ProtocolType = leader.protocolType
 	generation.LeaderId = leader.client.Member[b.group.Name]
 	generation.RebalanceTimeoutMs = leader.rebalanceTimeout
 	members := make([]joinGroup.Member, 0, len(b.joins))
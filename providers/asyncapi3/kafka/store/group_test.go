@@ -4,6 +4,8 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 	"io"
+	"mokapi/kafka"
+	"mokapi/providers/asyncapi3"
 	"testing"
 )
 
@@ -33,3 +35,76 @@ func TestGroup_Commit(t *testing.T) {
 	require.Equal(t, int64(1), g.Offset("foo", 0))
 	require.Equal(t, int64(10), g.Offset("foo", 1))
 }
+
+func TestGroup_CommittedOffsets(t *testing.T) {
+	logrus.SetOutput(io.Discard)
+
+	g := &Group{}
+	g.Commit("foo", 0, 1)
+
+	commits := g.CommittedOffsets()
+	g.Commit("foo", 0, 2)
+
+	require.Equal(t, map[string]map[int]int64{"foo": {0: 1}}, commits)
+	require.Equal(t, int64(2), g.Offset("foo", 0))
+}
+
+func TestGroupBalancer_Snapshot(t *testing.T) {
+	logrus.SetOutput(io.Discard)
+
+	g := &Group{Name: "foo"}
+	g.balancer = newGroupBalancer(g, asyncapi3.BrokerBindings{}, nil)
+	go g.balancer.run()
+
+	snapshot, ok := g.Snapshot()
+	require.True(t, ok)
+	require.Equal(t, GroupSnapshot{State: Empty, Generation: -1}, snapshot)
+
+	g.balancer.Stop()
+	<-g.balancer.done
+
+	generation := g.NewGeneration()
+	generation.LeaderId = "m1"
+	generation.Protocol = "range"
+	generation.Members["m1"] = &Member{
+		Partitions: map[string][]int{"bar": {0}},
+		Client:     &kafka.ClientContext{ClientId: "client", Addr: "127.0.0.1"},
+	}
+	g.State = Stable
+	g.balancer = newGroupBalancer(g, asyncapi3.BrokerBindings{}, nil)
+	go g.balancer.run()
+	defer g.balancer.Stop()
+
+	snapshot, ok = g.Snapshot()
+	require.True(t, ok)
+	require.Equal(t, GroupSnapshot{
+		State:      Stable,
+		Generation: 0,
+		Protocol:   "range",
+		LeaderId:   "m1",
+		Members: []MemberSnapshot{
+			{MemberId: "m1", ClientId: "client", ClientHost: "127.0.0.1", Partitions: map[string][]int{"bar": {0}}},
+		},
+	}, snapshot)
+}
+
+func TestGroupBalancer_Stopped(t *testing.T) {
+	logrus.SetOutput(io.Discard)
+
+	g := &Group{Name: "foo"}
+	g.balancer = newGroupBalancer(g, asyncapi3.BrokerBindings{}, nil)
+	go g.balancer.run()
+
+	snapshot, ok := g.balancer.Snapshot()
+	require.True(t, ok)
+	require.Equal(t, Empty, snapshot.State)
+
+	g.balancer.Stop()
+	<-g.balancer.done
+
+	// must not block after the group has been deleted
+	require.Equal(t, kafka.UnknownMemberId, g.balancer.Leave("bar"))
+	_, ok = g.balancer.Snapshot()
+	require.False(t, ok)
+	g.balancer.Stop()
+}
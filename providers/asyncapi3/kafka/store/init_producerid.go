@@ -32,8 +32,16 @@ func (s *Store) initProducerID(rw kafka.ResponseWriter, req *kafka.Request) erro
 			s.producers[res.ProducerId] = ps
 		}
 	} else {
-		res.ErrorCode = kafka.UnsupportedForMessageFormat
-		log.Errorf("kafka: mokapi does not support transactional producer: %s", r.TransactionalId)
+		s.initTransaction(r, res)
+		if res.ErrorCode != kafka.None {
+			log.Errorf("kafka InitProducerId: transactional id '%s': %v", r.TransactionalId, res.ErrorCode)
+		}
+	}
+
+	if res.ErrorCode == kafka.None {
+		s.txm.Lock()
+		s.saveProducers()
+		s.txm.Unlock()
 	}
 
 	go func() {
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/leaveGroup"
+)
+
+func (s *Store) leavegroup(rw kafka.ResponseWriter, req *kafka.Request) error {
+	r := req.Message.(*leaveGroup.Request)
+
+	members := r.Members
+	if req.Header.ApiVersion < 3 {
+		members = []leaveGroup.Member{{MemberId: r.MemberId}}
+	}
+
+	reqLog := &KafkaLeaveGroupRequest{GroupName: r.GroupId}
+	for _, m := range members {
+		reqLog.Members = append(reqLog.Members, m.MemberId)
+	}
+
+	res := &leaveGroup.Response{}
+	resLog := &KafkaLeaveGroupResponse{}
+	g, ok := s.Group(r.GroupId)
+	for _, m := range members {
+		errCode := kafka.UnknownMemberId
+		if ok {
+			errCode = g.balancer.Leave(m.MemberId)
+		}
+
+		res.Members = append(res.Members, leaveGroup.MemberResponse{
+			MemberId:        m.MemberId,
+			GroupInstanceId: m.GroupInstanceId,
+			ErrorCode:       errCode,
+		})
+		memberLog := KafkaLeaveGroupMember{MemberId: m.MemberId}
+		if errCode != kafka.None {
+			memberLog.ErrorCode = errCode.String()
+		}
+		resLog.Members = append(resLog.Members, memberLog)
+
+		if req.Header.ApiVersion < 3 {
+			// older versions have no member level error
+			res.ErrorCode = errCode
+		}
+	}
+
+	go s.logRequest(req.Header, reqLog)(resLog)
+
+	return rw.Write(res)
+}
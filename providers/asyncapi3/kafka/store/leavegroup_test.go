@@ -0,0 +1,104 @@
+package store_test
+
+import (
+	"mokapi/engine/enginetest"
+	"mokapi/kafka"
+	"mokapi/kafka/heartbeat"
+	"mokapi/kafka/kafkatest"
+	"mokapi/kafka/leaveGroup"
+	"mokapi/providers/asyncapi3/asyncapi3test"
+	"mokapi/providers/asyncapi3/kafka/store"
+	"mokapi/runtime/events"
+	"mokapi/runtime/events/eventstest"
+	"mokapi/runtime/monitor"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaveGroup(t *testing.T) {
+	testcases := []struct {
+		name string
+		fn   func(t *testing.T, s *store.Store, eh *eventstest.Handler)
+	}{
+		{
+			name: "group does not exist",
+			fn: func(t *testing.T, s *store.Store, eh *eventstest.Handler) {
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 0, &leaveGroup.Request{
+					GroupId:  "foo",
+					MemberId: "bar",
+				}))
+				res, ok := rr.Message.(*leaveGroup.Response)
+				require.True(t, ok)
+				require.Equal(t, kafka.UnknownMemberId, res.ErrorCode)
+			},
+		},
+		{
+			name: "unknown member v3",
+			fn: func(t *testing.T, s *store.Store, eh *eventstest.Handler) {
+				s.GetOrCreateGroup("foo", &store.Broker{})
+
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 3, &leaveGroup.Request{
+					GroupId: "foo",
+					Members: []leaveGroup.Member{{MemberId: "bar"}},
+				}))
+				res, ok := rr.Message.(*leaveGroup.Response)
+				require.True(t, ok)
+				require.Equal(t, kafka.None, res.ErrorCode)
+				require.Len(t, res.Members, 1)
+				require.Equal(t, "bar", res.Members[0].MemberId)
+				require.Equal(t, kafka.UnknownMemberId, res.Members[0].ErrorCode)
+			},
+		},
+		{
+			name: "last member leaves group",
+			fn: func(t *testing.T, s *store.Store, eh *eventstest.Handler) {
+				b := kafkatest.NewBroker(kafkatest.WithHandler(s))
+				defer b.Close()
+				s.Update(asyncapi3test.NewConfig(asyncapi3test.WithServer("", "kafka", b.Addr)))
+				err := b.Client().JoinSyncGroup("foo", "TestGroup", 3, 3)
+				require.NoError(t, err)
+
+				res, err := b.Client().LeaveGroup(4, &leaveGroup.Request{
+					GroupId: "TestGroup",
+					Members: []leaveGroup.Member{{MemberId: "foo"}},
+				})
+				require.NoError(t, err)
+				require.Equal(t, kafka.None, res.ErrorCode)
+				require.Equal(t, kafka.None, res.Members[0].ErrorCode)
+
+				g, ok := s.Group("TestGroup")
+				require.True(t, ok)
+				require.Equal(t, store.Empty, g.State)
+				require.True(t, g.IsEmpty())
+
+				// member is no longer known
+				hb, err := b.Client().Heartbeat(3, &heartbeat.Request{GroupId: "TestGroup", MemberId: "foo"})
+				require.NoError(t, err)
+				require.Equal(t, kafka.UnknownMemberId, hb.ErrorCode)
+
+				logs := eh.GetEvents(events.NewTraits().WithNamespace("kafka").With("type", "request"))
+				var titles []string
+				for _, l := range logs {
+					titles = append(titles, l.Data.Title())
+				}
+				require.Contains(t, titles, "LeaveGroup TestGroup")
+			},
+		},
+	}
+
+	t.Parallel()
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			eh := &eventstest.Handler{}
+			s := store.New(asyncapi3test.NewConfig(), enginetest.NewEngine(), eh, monitor.NewKafka())
+			defer s.Close()
+			tc.fn(t, s, eh)
+		})
+	}
+}
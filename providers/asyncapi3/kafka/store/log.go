@@ -3,7 +3,9 @@ package store
 import (
 	"fmt"
 	"mokapi/kafka"
+	"mokapi/kafka/describeConfigs"
 	"mokapi/runtime/events"
+	"strconv"
 	"strings"
 )
 
@@ -24,6 +26,7 @@ type KafkaMessageLog struct {
 	Api            string              `json:"api"`
 	ClientId       string              `json:"clientId"`
 	ScriptFile     string              `json:"script"`
+	Compression    string              `json:"compression,omitempty"`
 }
 
 type LogValue struct {
@@ -186,6 +189,38 @@ type KafkaSyncGroupResponse struct {
 	Assignment   KafkaSyncGroupAssignment `json:"assignment"`
 }
 
+type KafkaLeaveGroupRequest struct {
+	GroupName string   `json:"groupName"`
+	Members   []string `json:"members"`
+}
+
+func (r *KafkaLeaveGroupRequest) Title() string {
+	return fmt.Sprintf("LeaveGroup %s", r.GroupName)
+}
+
+type KafkaLeaveGroupResponse struct {
+	KafkaResponseError
+	Members []KafkaLeaveGroupMember `json:"members"`
+}
+
+type KafkaLeaveGroupMember struct {
+	MemberId  string `json:"memberId"`
+	ErrorCode string `json:"errorCode,omitempty"`
+}
+
+type KafkaDeleteGroupsRequest struct {
+	Groups []string `json:"groups"`
+}
+
+func (r *KafkaDeleteGroupsRequest) Title() string {
+	return fmt.Sprintf("DeleteGroups %s", strings.Join(r.Groups, ", "))
+}
+
+type KafkaDeleteGroupsResponse struct {
+	// Groups maps the group name to its error code
+	Groups map[string]string `json:"groups"`
+}
+
 type KafkaListOffsetsRequest struct {
 	Topics map[string][]KafkaListOffsetsRequestPartition `json:"topics"`
 }
@@ -249,3 +284,106 @@ type KafkaInitProducerIdResponse struct {
 	OngoingTxnProducerId    int64 `json:"ongoingTxnProducerId"`
 	OngoingTxnProducerEpoch int16 `json:"ongoingTxnProducerEpoch"`
 }
+
+type KafkaCompactionLog struct {
+	Api         string `json:"api"`
+	Topic       string `json:"topic"`
+	Partition   int    `json:"partition"`
+	StartOffset int64  `json:"startOffset"`
+	EndOffset   int64  `json:"endOffset"`
+	Records     int    `json:"records"`
+	Tombstones  int    `json:"tombstones"`
+	Duration    int64  `json:"duration"`
+}
+
+func (l *KafkaCompactionLog) Title() string {
+	return fmt.Sprintf("Compaction of %v partition %v", l.Topic, l.Partition)
+}
+
+type KafkaDeleteTopicsRequest struct {
+	Topics []string `json:"topics"`
+}
+
+func (r *KafkaDeleteTopicsRequest) Title() string {
+	return fmt.Sprintf("DeleteTopics %s", strings.Join(r.Topics, ", "))
+}
+
+type KafkaDeleteTopicsResponse struct {
+	// Topics maps the topic name to its error code
+	Topics map[string]string `json:"topics"`
+}
+
+type KafkaCreatePartitionsRequest struct {
+	// Topics maps the topic name to the requested number of partitions
+	Topics       map[string]int `json:"topics"`
+	ValidateOnly bool           `json:"validateOnly"`
+}
+
+func (r *KafkaCreatePartitionsRequest) Title() string {
+	return "CreatePartitions"
+}
+
+type KafkaCreatePartitionsResponse struct {
+	Topics map[string]KafkaResponseError `json:"topics"`
+}
+
+type KafkaDeleteRecordsRequest struct {
+	// Topics maps the topic name and partition to the offset before which records are deleted
+	Topics map[string]map[int]int64 `json:"topics"`
+}
+
+func (r *KafkaDeleteRecordsRequest) Title() string {
+	return "DeleteRecords"
+}
+
+type KafkaDeleteRecordsResponse struct {
+	Topics map[string]map[int]KafkaDeleteRecordsPartition `json:"topics"`
+}
+
+type KafkaDeleteRecordsPartition struct {
+	LowWatermark int64  `json:"lowWatermark"`
+	ErrorCode    string `json:"errorCode,omitempty"`
+}
+
+type KafkaConfigResource struct {
+	Type    string            `json:"type"`
+	Name    string            `json:"name"`
+	Configs map[string]string `json:"configs,omitempty"`
+}
+
+type KafkaDescribeConfigsRequest struct {
+	Resources []KafkaConfigResource `json:"resources"`
+}
+
+func (r *KafkaDescribeConfigsRequest) Title() string {
+	return "DescribeConfigs"
+}
+
+type KafkaAlterConfigsRequest struct {
+	Resources    []KafkaConfigResource `json:"resources"`
+	ValidateOnly bool                  `json:"validateOnly"`
+}
+
+func (r *KafkaAlterConfigsRequest) Title() string {
+	return "AlterConfigs"
+}
+
+type KafkaConfigsResponse struct {
+	Resources []KafkaConfigResourceResult `json:"resources"`
+}
+
+type KafkaConfigResourceResult struct {
+	KafkaConfigResource
+	KafkaResponseError
+}
+
+func resourceTypeString(t describeConfigs.ResourceType) string {
+	switch t {
+	case describeConfigs.ResourceTopic:
+		return "topic"
+	case describeConfigs.ResourceBroker:
+		return "broker"
+	default:
+		return strconv.Itoa(int(t))
+	}
+}
@@ -26,18 +26,24 @@ func (s *Store) cleanLog(b *Broker) {
 		retentionTime := time.Duration(brokerRetentionMs) * time.Millisecond
 		retentionBytes := brokerRetentionBytes
 		rollingTime := time.Duration(brokerRollingMs) * time.Millisecond
+		deleteRetention := 24 * time.Hour
 
-		if topic.Config.Bindings.Kafka.RetentionMs > 0 {
-			retentionTime = time.Duration(topic.Config.Bindings.Kafka.RetentionMs) * time.Millisecond
+		if topic.bindings.RetentionMs > 0 {
+			retentionTime = time.Duration(topic.bindings.RetentionMs) * time.Millisecond
 		}
-		if topic.Config.Bindings.Kafka.RetentionBytes > 0 {
-			retentionBytes = topic.Config.Bindings.Kafka.RetentionBytes
+		if topic.bindings.RetentionBytes > 0 {
+			retentionBytes = topic.bindings.RetentionBytes
 		}
-		if topic.Config.Bindings.Kafka.SegmentMs > 0 {
-			rollingTime = time.Duration(topic.Config.Bindings.Kafka.SegmentMs) * time.Millisecond
+		if topic.bindings.SegmentMs > 0 {
+			rollingTime = time.Duration(topic.bindings.SegmentMs) * time.Millisecond
 		}
+		if topic.bindings.DeleteRetentionMs > 0 {
+			deleteRetention = time.Duration(topic.bindings.DeleteRetentionMs) * time.Millisecond
+		}
+		deleteEnabled := topic.bindings.HasCleanupPolicy("delete")
+		compactEnabled := topic.bindings.HasCleanupPolicy("compact")
 
-		for _, p := range topic.Partitions {
+		for _, p := range topic.partitionList() {
 			if p.leader.Id != b.Id {
 				continue
 			}
@@ -54,7 +60,7 @@ func (s *Store) cleanLog(b *Broker) {
 				}
 
 				// check retention
-				if segment.Size > 0 && !segment.Closed.IsZero() && now.After(segment.Closed.Add(retentionTime)) {
+				if deleteEnabled && segment.Size > 0 && !segment.Closed.IsZero() && now.After(segment.Closed.Add(retentionTime)) {
 					log.Infof(
 						"kafka: deleting segment with offset [%v:%v] from partition %v topic '%s'",
 						segment.Head, segment.Tail, p.Index, topic.Name)
@@ -62,9 +68,22 @@ func (s *Store) cleanLog(b *Broker) {
 				}
 			}
 
-			if retentionBytes > 0 && partitionSize >= retentionBytes {
+			if deleteEnabled && retentionBytes > 0 && partitionSize >= retentionBytes {
 				deleteSegmentsUntilLogIsWithinLimit(p)
 			}
+
+			if compactEnabled {
+				start := time.Now()
+				result := p.compact(deleteRetention, now)
+				if result.StartOffset >= 0 {
+					s.logCompaction(p, result, time.Since(start))
+				}
+			}
+
+			// keep segment times of rolled segments
+			p.m.RLock()
+			p.saveState()
+			p.m.RUnlock()
 		}
 	}
 }
@@ -6,7 +6,9 @@ import (
 	"mokapi/providers/asyncapi3"
 	"mokapi/providers/asyncapi3/asyncapi3test"
 	"mokapi/providers/asyncapi3/kafka/store"
+	"mokapi/runtime/events"
 	"mokapi/runtime/events/eventstest"
+	"mokapi/runtime/metrics"
 	"mokapi/runtime/monitor"
 	"testing"
 	"time"
@@ -92,6 +94,146 @@ func TestCleaner(t *testing.T) {
 				require.Equal(t, "kafka: maximum partition size reached. deleting segment [0:1] from partition 0 of topic 'foo'", hook.LastEntry().Message)
 			},
 		},
+		{
+			name: "compaction keeps latest record per key",
+			test: func(t *testing.T) {
+				hook := test.NewGlobal()
+
+				eh := &eventstest.Handler{}
+				m := monitor.NewKafka()
+				s := store.New(newCompactedConfig(asyncapi3.TopicBindings{}), enginetest.NewEngine(), eh, m)
+
+				topic := s.Topic("foo")
+				require.NotNil(t, topic)
+				writeKeyValue(t, topic, "key-1", "a")
+				writeKeyValue(t, topic, "key-2", "b")
+				writeKeyValue(t, topic, "key-1", "c")
+
+				time.Sleep(800 * time.Millisecond)
+				p := topic.Partitions[0]
+				require.Len(t, p.Segments, 1)
+				require.Equal(t, int64(0), p.StartOffset())
+				require.Equal(t, int64(3), p.Offset())
+
+				b, errCode := p.Read(0, 1000)
+				require.Equal(t, kafka.None, errCode)
+				require.Len(t, b.Records, 2)
+				require.Equal(t, int64(1), b.Records[0].Offset)
+				require.Equal(t, "b", kafka.BytesToString(b.Records[0].Value))
+				require.Equal(t, int64(2), b.Records[1].Offset)
+				require.Equal(t, "c", kafka.BytesToString(b.Records[1].Value))
+
+				require.Equal(t, "kafka: compacted offset [0:3] of partition 0 topic 'foo': removed 1 records and 0 tombstones", hook.LastEntry().Message)
+				events := eh.GetEvents(events.NewTraits().WithNamespace("kafka").With("type", "compaction"))
+				require.Len(t, events, 1)
+				require.Equal(t, &store.KafkaCompactionLog{
+					Api:         "test",
+					Topic:       "foo",
+					Partition:   0,
+					StartOffset: 0,
+					EndOffset:   3,
+					Records:     1,
+					Duration:    events[0].Data.(*store.KafkaCompactionLog).Duration,
+				}, events[0].Data)
+				require.Equal(t, float64(1), m.CompactedRecords.Sum(metrics.NewQuery()))
+				require.Greater(t, m.Compactions.Sum(metrics.NewQuery()), float64(0))
+			},
+		},
+		{
+			name: "tombstone is kept until delete retention",
+			test: func(t *testing.T) {
+				s := store.New(newCompactedConfig(asyncapi3.TopicBindings{}), enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+
+				topic := s.Topic("foo")
+				writeKeyValue(t, topic, "key-1", "a")
+				rErr, err := topic.WritePartition(0, &kafka.Record{Key: kafka.NewBytes([]byte("key-1"))})
+				require.NoError(t, err)
+				require.Nil(t, rErr)
+
+				time.Sleep(800 * time.Millisecond)
+				b, errCode := topic.Partitions[0].Read(0, 1000)
+				require.Equal(t, kafka.None, errCode)
+				require.Len(t, b.Records, 1)
+				require.Equal(t, int64(1), b.Records[0].Offset)
+				require.Nil(t, b.Records[0].Value)
+			},
+		},
+		{
+			name: "tombstone is removed after delete retention",
+			test: func(t *testing.T) {
+				hook := test.NewGlobal()
+
+				s := store.New(newCompactedConfig(asyncapi3.TopicBindings{DeleteRetentionMs: 1}), enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+
+				topic := s.Topic("foo")
+				writeKeyValue(t, topic, "key-1", "a")
+				rErr, err := topic.WritePartition(0, &kafka.Record{Key: kafka.NewBytes([]byte("key-1"))})
+				require.NoError(t, err)
+				require.Nil(t, rErr)
+
+				time.Sleep(800 * time.Millisecond)
+				b, errCode := topic.Partitions[0].Read(0, 1000)
+				require.Equal(t, kafka.None, errCode)
+				require.Len(t, b.Records, 0)
+				require.Equal(t, int64(2), topic.Partitions[0].Offset())
+
+				require.Equal(t, "kafka: compacted offset [0:2] of partition 0 topic 'foo': removed 0 records and 1 tombstones", hook.LastEntry().Message)
+			},
+		},
+		{
+			name: "compaction removes records of aborted transactions",
+			test: func(t *testing.T) {
+				s := store.New(newCompactedConfig(asyncapi3.TopicBindings{}), enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+
+				topic := s.Topic("foo")
+				writeKeyValue(t, topic, "key-1", "a")
+
+				p := initTransactional(t, s, "trx")
+				require.Equal(t, kafka.None, addPartition(t, s, "trx", p))
+				res := produceTransactionalKeyValue(t, s, p, 0, "key-1", "b")
+				require.Equal(t, kafka.None, res.Topics[0].Partitions[0].ErrorCode)
+				require.Equal(t, kafka.None, endTransaction(t, s, "trx", p, false))
+
+				time.Sleep(800 * time.Millisecond)
+				b, errCode := topic.Partitions[0].Read(0, 1000)
+				require.Equal(t, kafka.None, errCode)
+				var values []string
+				for _, r := range b.Records {
+					if r.Value != nil && r.ProducerId != p.ProducerId {
+						values = append(values, kafka.BytesToString(r.Value))
+					}
+					require.NotEqual(t, "b", kafka.BytesToString(r.Value))
+				}
+				require.Equal(t, []string{"a"}, values)
+
+				fp := fetchRecords(t, s, 1, 0)
+				require.Equal(t, "a", kafka.BytesToString(fp.RecordSet.Records[0].Value))
+			},
+		},
+		{
+			name: "compacted topic requires key",
+			test: func(t *testing.T) {
+				s := store.New(newCompactedConfig(asyncapi3.TopicBindings{}), enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+
+				topic := s.Topic("foo")
+				rErr, err := topic.WritePartition(0, &kafka.Record{Value: kafka.NewBytes([]byte("a"))})
+				require.NoError(t, err)
+				require.NotNil(t, rErr)
+				require.Equal(t, "compacted topic cannot accept message without key", rErr.BatchIndexErrorMessage)
+			},
+		},
+		{
+			name: "compaction without delete policy keeps segments",
+			test: func(t *testing.T) {
+				s := store.New(newCompactedConfig(asyncapi3.TopicBindings{RetentionMs: 1}), enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+
+				topic := s.Topic("foo")
+				writeKeyValue(t, topic, "key-1", "a")
+
+				time.Sleep(800 * time.Millisecond)
+				require.Len(t, topic.Partitions[0].Segments, 1)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -100,3 +242,27 @@ func TestCleaner(t *testing.T) {
 		})
 	}
 }
+
+func newCompactedConfig(bindings asyncapi3.TopicBindings) *asyncapi3.Config {
+	bindings.Partitions = 1
+	bindings.CleanupPolicy = []string{"compact"}
+	return asyncapi3test.NewConfig(
+		asyncapi3test.WithInfo("test", "", ""),
+		asyncapi3test.WithServer("foo", "kafka", "",
+			asyncapi3test.WithKafkaServerBinding(asyncapi3.BrokerBindings{
+				LogRetentionCheckIntervalMs: 200,
+				LogRollMs:                   10,
+			}),
+		),
+		asyncapi3test.WithChannel("foo", asyncapi3test.WithKafkaChannelBinding(bindings)),
+	)
+}
+
+func writeKeyValue(t *testing.T, topic *store.Topic, key, value string) {
+	rErr, err := topic.WritePartition(0, &kafka.Record{
+		Key:   kafka.NewBytes([]byte(key)),
+		Value: kafka.NewBytes([]byte(value)),
+	})
+	require.NoError(t, err)
+	require.Nil(t, rErr)
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"mokapi/kafka"
+	"mokapi/runtime/events"
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type compactionResult struct {
+	StartOffset int64
+	EndOffset   int64
+	Records     int
+	Tombstones  int
+}
+
+// compact retains only the latest record for each key within the closed
+// segments of the partition. The active segment and records of ongoing
+// transactions are not compacted. Records of aborted transactions are
+// removed and never replace a committed value. A tombstone, a record with a
+// null value, is removed once its segment has been closed for longer than
+// deleteRetention. Removed records leave a gap in the offsets of their segment.
+func (p *Partition) compact(deleteRetention time.Duration, now time.Time) compactionResult {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	var segments []*Segment
+	for _, seg := range p.Segments {
+		if !seg.Closed.IsZero() {
+			segments = append(segments, seg)
+		}
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].Head < segments[j].Head
+	})
+
+	result := compactionResult{StartOffset: -1, EndOffset: -1}
+	if len(segments) == 0 {
+		return result
+	}
+	lso := p.lastStableOffset()
+	result.StartOffset = segments[0].Head
+	result.EndOffset = min(segments[len(segments)-1].Tail, lso)
+
+	latest := map[string]int64{}
+	for _, seg := range segments {
+		for _, r := range seg.Log {
+			if isCompactable(r, lso) && !p.isAborted(r) {
+				latest[string(kafka.Read(r.Data.Key))] = r.Data.Offset
+			}
+		}
+	}
+
+	for _, seg := range segments {
+		removed := result.Records + result.Tombstones
+		tombstoneExpired := now.After(seg.Closed.Add(deleteRetention))
+		for i, r := range seg.Log {
+			if r != nil && r.Data.Offset < lso && p.isAborted(r) {
+				result.Records++
+				seg.removeRecord(i)
+				continue
+			}
+			if !isCompactable(r, lso) {
+				continue
+			}
+			if latest[string(kafka.Read(r.Data.Key))] == r.Data.Offset {
+				if r.Data.Value != nil || !tombstoneExpired {
+					continue
+				}
+				result.Tombstones++
+			} else {
+				result.Records++
+			}
+
+			seg.removeRecord(i)
+		}
+		if result.Records+result.Tombstones > removed {
+			p.rewrite(seg)
+		}
+	}
+
+	return result
+}
+
+// isCompactable reports whether the record takes part in the log compaction.
+// Transaction markers are kept because read_committed consumers rely on them.
+func isCompactable(r *record, lastStableOffset int64) bool {
+	return r != nil && !r.Control && r.Data.Key != nil && r.Data.Offset < lastStableOffset
+}
+
+// isAborted reports whether the record belongs to an aborted transaction.
+// The abort marker itself is not part of the aborted records.
+func (p *Partition) isAborted(r *record) bool {
+	if !r.Transactional || r.Control {
+		return false
+	}
+	for _, a := range p.aborted {
+		if a.ProducerId == r.Data.ProducerId && r.Data.Offset >= a.FirstOffset && r.Data.Offset < a.LastOffset {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) logCompaction(p *Partition, result compactionResult, d time.Duration) {
+	if s.monitor != nil {
+		s.monitor.Compactions.WithLabel(s.cluster, p.Topic.Name).Add(1)
+		s.monitor.CompactedRecords.WithLabel(s.cluster, p.Topic.Name).Add(float64(result.Records + result.Tombstones))
+	}
+
+	if result.Records == 0 && result.Tombstones == 0 {
+		return
+	}
+
+	log.Infof("kafka: compacted offset [%v:%v] of partition %v topic '%s': removed %v records and %v tombstones",
+		result.StartOffset, result.EndOffset, p.Index, p.Topic.Name, result.Records, result.Tombstones)
+
+	e := &KafkaCompactionLog{
+		Api:         s.cluster,
+		Topic:       p.Topic.Name,
+		Partition:   p.Index,
+		StartOffset: result.StartOffset,
+		EndOffset:   result.EndOffset,
+		Records:     result.Records,
+		Tombstones:  result.Tombstones,
+		Duration:    d.Milliseconds(),
+	}
+	t := events.NewTraits().
+		WithNamespace("kafka").
+		WithName(s.cluster).
+		With("type", "compaction").
+		With("topic", p.Topic.Name).
+		With("partition", strconv.Itoa(p.Index))
+	_ = s.eh.Push(e, t)
+}
@@ -72,7 +72,7 @@ func (s *Store) metadata(rw kafka.ResponseWriter, req *kafka.Request) error {
 			Name: t.Name,
 		}
 
-		for i := range t.Partitions {
+		for i := range t.partitionList() {
 			resTopic.Partitions = append(resTopic.Partitions, metaData.ResponsePartition{
 				PartitionIndex: int32(i),
 				LeaderId:       0,
@@ -72,6 +72,8 @@ func (s *Store) offsetCommit(rw kafka.ResponseWriter, req *kafka.Request) error
 		res.Topics = append(res.Topics, resTopic)
 	}
 
+	s.saveGroups()
+
 	return rw.Write(res)
 }
 
@@ -81,7 +83,8 @@ func (s *Store) processMetricsOffsetCommit(ctx context.Context, g *Group, topic
 		return
 	}
 
-	lag := float64(partition.Offset() - g.Commits[topic][partition.Index])
+	offset := g.Offset(topic, partition.Index)
+	lag := float64(partition.Offset() - offset)
 	m.Lags.WithLabel(s.cluster, g.Name, topic, strconv.Itoa(partition.Index)).Set(lag)
-	m.Commits.WithLabel(s.cluster, g.Name, topic, strconv.Itoa(partition.Index)).Set(float64(g.Commits[topic][partition.Index]))
+	m.Commits.WithLabel(s.cluster, g.Name, topic, strconv.Itoa(partition.Index)).Set(float64(offset))
 }
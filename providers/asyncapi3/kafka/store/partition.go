@@ -30,6 +30,12 @@ type Partition struct {
 	trigger   Trigger
 
 	producers map[int64]*PartitionProducerState
+	// first offset of the ongoing transaction by producer id
+	ongoing map[int64]int64
+	aborted []AbortedTransaction
+
+	// segment files if a data directory is configured
+	files *partitionLog
 
 	m sync.RWMutex
 }
@@ -47,11 +53,24 @@ type Segment struct {
 type record struct {
 	Data *kafka.Record
 	Log  *KafkaMessageLog
+	// compression codec of the produced batch
+	Compression   kafka.Compression
+	Transactional bool
+	// Control is true for transaction markers
+	Control bool
+}
+
+type AbortedTransaction struct {
+	ProducerId  int64
+	FirstOffset int64
+	// LastOffset is the offset of the abort marker
+	LastOffset int64
 }
 
 type WriteOptions struct {
 	SkipValidation bool
 	ClientId       string
+	Principal      string
 	ScriptFile     string
 }
 
@@ -90,24 +109,53 @@ func newPartition(index int, brokers Brokers, logger LogRecord, trigger Trigger,
 		trigger:   trigger,
 		Topic:     topic,
 		producers: make(map[int64]*PartitionProducerState),
+		ongoing:   make(map[int64]int64),
 	}
 	if len(brokerList) > 0 {
 		p.leader = brokerList[0]
 	}
+	if topic.s != nil && topic.s.dataDir != "" {
+		var err error
+		p.files, err = openPartitionLog(topic.s.partitionDir(topic.Name, index))
+		if err == nil {
+			err = p.restore()
+		}
+		if err != nil {
+			log.Errorf("kafka: unable to restore partition %v topic '%s': %v", index, topic.Name, err)
+		}
+	}
 	return p
 }
 
+// Read returns the records starting at the given offset including records of
+// ongoing transactions. The records keep the compression of their produced batch
+// unless the topic binding compression.type defines a codec. A batch only contains
+// records of the same codec and, for transactional records, of the same producer.
 func (p *Partition) Read(offset int64, maxBytes int) (kafka.RecordBatch, kafka.ErrorCode) {
+	return p.read(offset, maxBytes, p.Tail)
+}
+
+// ReadCommitted returns the records up to the last stable offset as required by
+// consumers with isolation level read_committed.
+func (p *Partition) ReadCommitted(offset int64, maxBytes int) (kafka.RecordBatch, kafka.ErrorCode) {
+	return p.read(offset, maxBytes, p.LastStableOffset())
+}
+
+func (p *Partition) read(offset int64, maxBytes int, limit int64) (kafka.RecordBatch, kafka.ErrorCode) {
 	batch := kafka.NewRecordBatch()
 	if offset < p.StartOffset() {
 		return batch, kafka.OffsetOutOfRange
 	}
 
+	topicCompression, keepCompression := p.compressionType()
+	batch.Compression = topicCompression
+
 	size := 0
 	var baseOffset int64
 	var baseTime time.Time
+	var first *record
 	for {
-		if offset >= p.Tail || size > maxBytes {
+		if offset >= limit || size > maxBytes {
 			return batch, kafka.None
 		}
 		seg := p.GetSegment(offset)
@@ -115,8 +163,25 @@ func (p *Partition) Read(offset int64, maxBytes int) (kafka.RecordBatch, kafka.E
 			return batch, kafka.None
 		}
 
-		for seg.Contains(offset) {
-			r := seg.Record(offset)
+		for seg.Contains(offset) && offset < limit {
+			index := int(offset - seg.Head)
+			if index >= len(seg.Log) || seg.Log[index] == nil {
+				// removed by log compaction
+				offset++
+				continue
+			}
+			rec := seg.Log[index]
+			if first == nil {
+				first = rec
+				batch.Transactional = rec.Transactional
+				batch.Control = rec.Control
+				if keepCompression || rec.Control {
+					batch.Compression = rec.Compression
+				}
+			} else if !first.sameBatch(rec, keepCompression) {
+				return batch, kafka.None
+			}
+			r := rec.Data
 
 			if baseOffset == 0 {
 				baseOffset = r.Offset
@@ -160,6 +225,13 @@ func (p *Partition) write(batch kafka.RecordBatch, opts WriteOptions) (WriteResu
 	var producer *ProducerState
 	sequenceNumber := int32(-1)
 
+	eventCtx := &EventContext{
+		Topic:     p.Topic.Name,
+		Partition: p.Index,
+		ClientId:  opts.ClientId,
+		Principal: opts.Principal,
+	}
+
 	now := time.Now()
 	result.BaseOffset = p.Tail
 	var baseTime time.Time
@@ -169,19 +241,19 @@ func (p *Partition) write(batch kafka.RecordBatch, opts WriteOptions) (WriteResu
 			if producer == nil {
 				producer = p.Topic.s.producers[r.ProducerId]
 			}
+			// sequence numbers start again with a new epoch
 			state, ok := p.producers[r.ProducerId]
-			if ok {
+			if ok && state.Epoch == r.ProducerEpoch {
 				sequenceNumber = state.LastSequence
 			}
 
 			if producer == nil {
 				result.fail(i, kafka.InvalidProducerIdMapping, "unknown producer id")
 				return result, nil
-			} else if producer.ProducerEpoch != r.ProducerEpoch {
-				// this is without transactional produce not possible
-				// due to producer will always get a new producer id
-				// should return PRODUCER_FENCED when record epoch is lower as current known
-				// this should be adjusted when transactional is implemented
+			} else if r.ProducerEpoch < producer.ProducerEpoch {
+				result.fail(i, kafka.ProducerFenced, "producer has been fenced by a newer epoch")
+				return result, nil
+			} else if r.ProducerEpoch > producer.ProducerEpoch {
 				result.fail(i, kafka.InvalidProducerEpoch, "producer epoch does not match")
 				return result, nil
 			} else if r.SequenceNumber != sequenceNumber+1 {
@@ -204,7 +276,7 @@ func (p *Partition) write(batch kafka.RecordBatch, opts WriteOptions) (WriteResu
 			result.fail(i, kafka.InvalidRecord, err.Error())
 			return result, nil
 		}
-		if p.trigger(r, kLog.SchemaId) && !opts.SkipValidation {
+		if p.trigger(r, kLog.SchemaId, eventCtx) && !opts.SkipValidation {
 			// validate again
 			kLog, err = p.validator.Validate(r)
 			if err != nil {
@@ -213,6 +285,11 @@ func (p *Partition) write(batch kafka.RecordBatch, opts WriteOptions) (WriteResu
 			}
 		}
 
+		if r.Key == nil && p.Topic.bindings.HasCleanupPolicy("compact") {
+			result.fail(i, kafka.InvalidRecord, "compacted topic cannot accept message without key")
+			return result, nil
+		}
+
 		if r.Time.IsZero() {
 			r.Time = now
 		}
@@ -222,24 +299,31 @@ func (p *Partition) write(batch kafka.RecordBatch, opts WriteOptions) (WriteResu
 
 		kLog.ClientId = opts.ClientId
 		kLog.ScriptFile = opts.ScriptFile
+		if batch.Compression != kafka.NoCompression {
+			kLog.Compression = batch.Compression.String()
+		}
 
 		writeFuncs = append(writeFuncs, func() {
 			r.Offset = p.Tail
 
-			if len(p.Segments) == 0 {
-				p.Segments[p.ActiveSegment] = newSegment(p.Tail)
+			segment := p.activeSegment()
+			rec := &record{
+				Data:          r,
+				Log:           kLog,
+				Compression:   batch.Compression,
+				Transactional: batch.Transactional,
 			}
-
-			segment, ok := p.Segments[p.ActiveSegment]
-			if !ok {
-				segment = p.addSegment()
+			segment.Log = append(segment.Log, rec)
+			if batch.Transactional {
+				if _, ok := p.ongoing[r.ProducerId]; !ok {
+					p.ongoing[r.ProducerId] = r.Offset
+				}
 			}
-
-			segment.Log = append(segment.Log, &record{Data: r, Log: kLog})
 			segment.Tail++
 			segment.LastWritten = now
 			segment.Size += r.Size(result.BaseOffset, baseTime)
 			p.Tail++
+			p.persist(segment, rec)
 
 			kLog.Partition = p.Index
 			kLog.Offset = r.Offset
@@ -247,7 +331,7 @@ func (p *Partition) write(batch kafka.RecordBatch, opts WriteOptions) (WriteResu
 		})
 	}
 
-	if len(result.Records) > 0 && p.Topic.Config.Bindings.Kafka.ValueSchemaValidation {
+	if len(result.Records) > 0 && p.Topic.bindings.ValueSchemaValidation {
 		return result, nil
 	}
 
@@ -258,9 +342,10 @@ func (p *Partition) write(batch kafka.RecordBatch, opts WriteOptions) (WriteResu
 	if sequenceNumber >= 0 && producer != nil {
 		state, ok := p.producers[producer.ProducerId]
 		if !ok {
-			state = &PartitionProducerState{LastSequence: sequenceNumber}
+			state = &PartitionProducerState{ProducerId: producer.ProducerId, Epoch: producer.ProducerEpoch, LastSequence: sequenceNumber}
 			p.producers[producer.ProducerId] = state
 		} else {
+			state.Epoch = producer.ProducerEpoch
 			state.LastSequence = sequenceNumber
 		}
 	}
@@ -268,6 +353,144 @@ func (p *Partition) write(batch kafka.RecordBatch, opts WriteOptions) (WriteResu
 	return result, nil
 }
 
+// writeMarker appends a control record which completes the transaction of the
+// given producer. Records of an aborted transaction stay in the log and are
+// filtered by read_committed consumers using the aborted transactions.
+func (p *Partition) writeMarker(producerId int64, epoch int16, commit bool) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	now := time.Now()
+	r := kafka.NewTransactionMarker(producerId, epoch, commit)
+	r.Offset = p.Tail
+	r.Time = now
+
+	segment := p.activeSegment()
+	rec := &record{Data: r, Transactional: true, Control: true}
+	segment.Log = append(segment.Log, rec)
+	segment.Tail++
+	segment.LastWritten = now
+	segment.Size += r.Size(r.Offset, now)
+	p.Tail++
+	p.persist(segment, rec)
+
+	if first, ok := p.ongoing[producerId]; ok {
+		if !commit {
+			p.aborted = append(p.aborted, AbortedTransaction{
+				ProducerId:  producerId,
+				FirstOffset: first,
+				LastOffset:  r.Offset,
+			})
+		}
+		delete(p.ongoing, producerId)
+	}
+	p.saveState()
+}
+
+// LastStableOffset returns the offset of the first record which belongs to an
+// ongoing transaction or the high watermark if no transaction is ongoing.
+func (p *Partition) LastStableOffset() int64 {
+	p.m.RLock()
+	defer p.m.RUnlock()
+
+	return p.lastStableOffset()
+}
+
+func (p *Partition) lastStableOffset() int64 {
+	lso := p.Tail
+	for _, first := range p.ongoing {
+		if first < lso {
+			lso = first
+		}
+	}
+	return lso
+}
+
+// AbortedTransactions returns the aborted transactions which overlap the
+// offset range [from, to).
+func (p *Partition) AbortedTransactions(from, to int64) []AbortedTransaction {
+	p.m.RLock()
+	defer p.m.RUnlock()
+
+	var result []AbortedTransaction
+	for _, a := range p.aborted {
+		if a.LastOffset >= from && a.FirstOffset < to {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// deleteRecords deletes all records before the given offset and moves the start
+// offset of the partition. The offset -1 deletes all records up to the high
+// watermark. It returns the new start offset (low watermark).
+func (p *Partition) deleteRecords(offset int64) (int64, kafka.ErrorCode) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if offset == -1 {
+		offset = p.Tail
+	}
+	if offset < 0 || offset > p.Tail {
+		return -1, kafka.OffsetOutOfRange
+	}
+	if offset <= p.Head {
+		return p.Head, kafka.None
+	}
+
+	for key, seg := range p.Segments {
+		if seg.Tail <= offset && key != p.ActiveSegment {
+			seg.delete()
+			delete(p.Segments, key)
+			if p.files != nil {
+				p.files.removeSegment(key)
+			}
+			continue
+		}
+		if seg.Head >= offset {
+			continue
+		}
+		for i := 0; i < len(seg.Log) && seg.Head+int64(i) < offset; i++ {
+			seg.removeRecord(i)
+		}
+		p.rewrite(seg)
+	}
+	p.Head = offset
+	p.saveState()
+
+	return p.Head, kafka.None
+}
+
+// sameBatch reports whether both records can be sent in the same record batch
+func (r *record) sameBatch(other *record, keepCompression bool) bool {
+	if keepCompression && r.Compression != other.Compression {
+		return false
+	}
+	if r.Transactional != other.Transactional || r.Control != other.Control {
+		return false
+	}
+	return !r.Transactional || r.Data.ProducerId == other.Data.ProducerId
+}
+
+// compressionType returns the codec defined by the topic binding compression.type
+// and whether the codec of the producer should be kept instead.
+func (p *Partition) compressionType() (kafka.Compression, bool) {
+	if p.Topic == nil {
+		return kafka.NoCompression, true
+	}
+	switch t := p.Topic.bindings.CompressionType; t {
+	case "", "producer":
+		return kafka.NoCompression, true
+	default:
+		c, err := kafka.ParseCompression(t)
+		if err != nil {
+			log.Errorf("kafka: topic %v: %v", p.Topic.Name, err)
+			return kafka.NoCompression, true
+		}
+		return c, false
+	}
+}
+
 func (p *Partition) Offset() int64 {
 	return p.Tail
 }
@@ -303,7 +526,11 @@ func (p *Partition) GetSegment(offset int64) *Segment {
 
 func (p *Partition) delete() {
 	for _, s := range p.Segments {
-		p.removeSegment(s)
+		s.delete()
+	}
+	p.Segments = make(map[int64]*Segment)
+	if p.files != nil {
+		p.files.delete()
 	}
 }
 
@@ -328,12 +555,31 @@ func (p *Partition) removeSegment(s *Segment) {
 
 	s.delete()
 	delete(p.Segments, s.Head)
+	if p.files != nil {
+		p.files.removeSegment(s.Head)
+	}
+	p.saveState()
 }
 
-func (p *Partition) addSegment() *Segment {
-	p.m.RLock()
-	defer p.m.RUnlock()
+// activeSegment returns the segment to which records are appended. A new
+// segment is added if the active one has been closed by the log cleaner.
+// The caller must hold the partition lock.
+func (p *Partition) activeSegment() *Segment {
+	if len(p.Segments) == 0 {
+		p.ActiveSegment = p.Tail
+		p.Segments[p.ActiveSegment] = newSegment(p.Tail)
+		p.saveState()
+	}
+	segment, ok := p.Segments[p.ActiveSegment]
+	if !ok || !segment.Closed.IsZero() {
+		segment = p.addSegment()
+	}
+	return segment
+}
 
+// addSegment closes the active segment and adds a new one starting at the
+// current offset. The caller must hold the partition lock.
+func (p *Partition) addSegment() *Segment {
 	now := time.Now()
 
 	if active, ok := p.Segments[p.ActiveSegment]; ok && active.Closed.IsZero() {
@@ -343,6 +589,7 @@ func (p *Partition) addSegment() *Segment {
 	s := newSegment(p.Offset())
 	p.Segments[p.ActiveSegment] = s
 	log.Infof("kafka: added new segment to partition %v, topic %v", p.Index, p.Topic.Name)
+	p.saveState()
 
 	return s
 }
@@ -362,21 +609,55 @@ func (s *Segment) Contains(offset int64) bool {
 
 func (s *Segment) Record(offset int64) *kafka.Record {
 	index := int(offset - s.Head)
-	if index < 0 || index >= len(s.Log) {
+	if index < 0 || index >= len(s.Log) || s.Log[index] == nil {
 		return nil
 	}
 	return s.Log[index].Data
 }
 
+// IsControl reports whether the record at the given offset is a
+// transaction marker
+func (s *Segment) IsControl(offset int64) bool {
+	index := int(offset - s.Head)
+	if index < 0 || index >= len(s.Log) || s.Log[index] == nil {
+		return false
+	}
+	return s.Log[index].Control
+}
+
 func (s *Segment) delete() {
 	for _, r := range s.Log {
-		log.Debugf("delete record: %v", r.Data.Offset)
-		if r.Data.Key != nil {
-			_ = r.Data.Key.Close()
-		}
-		if r.Data.Value != nil {
-			_ = r.Data.Value.Close()
+		if r == nil {
+			continue
 		}
+		log.Debugf("delete record: %v", r.Data.Offset)
+		r.delete()
+	}
+}
+
+// removeRecord deletes the record at the given index. The offset of the record
+// remains as a gap in the segment.
+func (s *Segment) removeRecord(index int) {
+	r := s.Log[index]
+	if r == nil {
+		return
+	}
+	s.Size -= r.Data.Size(r.Data.Offset, r.Data.Time)
+	if s.Size < 0 {
+		s.Size = 0
+	}
+	r.delete()
+	s.Log[index] = nil
+}
+
+func (r *record) delete() {
+	if r.Data.Key != nil {
+		_ = r.Data.Key.Close()
+	}
+	if r.Data.Value != nil {
+		_ = r.Data.Value.Close()
+	}
+	if r.Log != nil {
 		r.Log.Deleted = true
 	}
 }
@@ -17,7 +17,7 @@ func TestPartition(t *testing.T) {
 		0,
 		[]*Broker{{Id: 1}},
 		func(log *KafkaMessageLog, traits events.Traits) {},
-		func(record *kafka.Record, schemaId int) bool { return false },
+		func(record *kafka.Record, schemaId int, ctx *EventContext) bool { return false },
 		&Topic{},
 	)
 
@@ -34,7 +34,7 @@ func TestPartition_Write(t *testing.T) {
 		func(log *KafkaMessageLog, traits events.Traits) {
 			logs = append(logs, log.Offset)
 		},
-		func(record *kafka.Record, schemaId int) bool { return false },
+		func(record *kafka.Record, schemaId int, ctx *EventContext) bool { return false },
 		&Topic{},
 	)
 
@@ -79,7 +79,7 @@ func TestPartition_Read_Empty(t *testing.T) {
 		0,
 		[]*Broker{{Id: 1}},
 		func(log *KafkaMessageLog, traits events.Traits) {},
-		func(record *kafka.Record, schemaId int) bool { return false },
+		func(record *kafka.Record, schemaId int, ctx *EventContext) bool { return false },
 		&Topic{},
 	)
 	b, errCode := p.Read(0, 1)
@@ -92,7 +92,7 @@ func TestPartition_Read(t *testing.T) {
 		0,
 		[]*Broker{{Id: 1}},
 		func(log *KafkaMessageLog, traits events.Traits) {},
-		func(record *kafka.Record, schemaId int) bool { return false },
+		func(record *kafka.Record, schemaId int, ctx *EventContext) bool { return false },
 		&Topic{},
 	)
 	wr, err := p.Write(kafka.RecordBatch{
@@ -119,7 +119,7 @@ func TestPartition_Read_OutOfOffset_Empty(t *testing.T) {
 		0,
 		[]*Broker{{Id: 1}},
 		func(log *KafkaMessageLog, traits events.Traits) {},
-		func(record *kafka.Record, schemaId int) bool { return false },
+		func(record *kafka.Record, schemaId int, ctx *EventContext) bool { return false },
 		&Topic{},
 	)
 	b, errCode := p.Read(10, 1)
@@ -132,7 +132,7 @@ func TestPartition_Read_OutOfOffset(t *testing.T) {
 		0,
 		[]*Broker{{Id: 1}},
 		func(log *KafkaMessageLog, traits events.Traits) {},
-		func(record *kafka.Record, schemaId int) bool { return false },
+		func(record *kafka.Record, schemaId int, ctx *EventContext) bool { return false },
 		&Topic{},
 	)
 	_, _ = p.Write(kafka.RecordBatch{
@@ -156,10 +156,8 @@ func TestPartition_Write_Value_Validator(t *testing.T) {
 		0,
 		[]*Broker{{Id: 1}},
 		func(log *KafkaMessageLog, _ events.Traits) {
-		}, func(record *kafka.Record, schemaId int) bool { return false },
-		&Topic{Config: &asyncapi3.Channel{Bindings: asyncapi3.ChannelBindings{
-			Kafka: asyncapi3.TopicBindings{ValueSchemaValidation: true},
-		}}},
+		}, func(record *kafka.Record, schemaId int, ctx *EventContext) bool { return false },
+		&Topic{bindings: asyncapi3.TopicBindings{ValueSchemaValidation: true}},
 	)
 	p.validator = &validator{
 		validators: []recordValidator{
@@ -220,7 +218,7 @@ func TestPartition_Write_Value_Validator(t *testing.T) {
 func TestPatition_Retention(t *testing.T) {
 	p := newPartition(0, []*Broker{{Id: 1}},
 		func(log *KafkaMessageLog, traits events.Traits) {},
-		func(record *kafka.Record, schemaId int) bool { return false },
+		func(record *kafka.Record, schemaId int, ctx *EventContext) bool { return false },
 		&Topic{},
 	)
 	require.Equal(t, int64(0), p.Head)
@@ -269,7 +267,7 @@ func TestPartition_Write_Producer_ClientId(t *testing.T) {
 		func(log *KafkaMessageLog, traits events.Traits) {
 			logs = append(logs, log)
 		},
-		func(record *kafka.Record, schemaId int) bool { return false },
+		func(record *kafka.Record, schemaId int, ctx *EventContext) bool { return false },
 		&Topic{},
 	)
 
@@ -288,3 +286,88 @@ func TestPartition_Write_Producer_ClientId(t *testing.T) {
 
 	require.Equal(t, "foo", logs[0].ClientId)
 }
+
+func TestPartition_Compression(t *testing.T) {
+	testcases := []struct {
+		name            string
+		compressionType string
+		test            func(t *testing.T, p *Partition, logs []*KafkaMessageLog)
+	}{
+		{
+			name: "keeps producer compression",
+			test: func(t *testing.T, p *Partition, logs []*KafkaMessageLog) {
+				b, errCode := p.Read(0, 1000)
+				require.Equal(t, kafka.None, errCode)
+				require.Equal(t, kafka.Gzip, b.Compression)
+				require.Len(t, b.Records, 1)
+
+				b, errCode = p.Read(1, 1000)
+				require.Equal(t, kafka.None, errCode)
+				require.Equal(t, kafka.NoCompression, b.Compression)
+				require.Len(t, b.Records, 1)
+
+				require.Equal(t, "gzip", logs[0].Compression)
+				require.Equal(t, "", logs[1].Compression)
+			},
+		},
+		{
+			name:            "producer",
+			compressionType: "producer",
+			test: func(t *testing.T, p *Partition, logs []*KafkaMessageLog) {
+				b, _ := p.Read(0, 1000)
+				require.Equal(t, kafka.Gzip, b.Compression)
+				require.Len(t, b.Records, 1)
+			},
+		},
+		{
+			name:            "topic compression",
+			compressionType: "zstd",
+			test: func(t *testing.T, p *Partition, logs []*KafkaMessageLog) {
+				b, errCode := p.Read(0, 1000)
+				require.Equal(t, kafka.None, errCode)
+				require.Equal(t, kafka.Zstd, b.Compression)
+				require.Len(t, b.Records, 2)
+			},
+		},
+		{
+			name:            "uncompressed",
+			compressionType: "uncompressed",
+			test: func(t *testing.T, p *Partition, logs []*KafkaMessageLog) {
+				b, _ := p.Read(0, 1000)
+				require.Equal(t, kafka.NoCompression, b.Compression)
+				require.Len(t, b.Records, 2)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var logs []*KafkaMessageLog
+			p := newPartition(
+				0,
+				[]*Broker{{Id: 1}},
+				func(log *KafkaMessageLog, _ events.Traits) {
+					logs = append(logs, log)
+				},
+				func(record *kafka.Record, schemaId int, ctx *EventContext) bool { return false },
+				&Topic{bindings: asyncapi3.TopicBindings{CompressionType: tc.compressionType}},
+			)
+
+			_, err := p.Write(kafka.RecordBatch{
+				Compression: kafka.Gzip,
+				Records: []*kafka.Record{
+					{Time: time.Now(), Value: kafka.NewBytes([]byte(`"foo"`))},
+				},
+			})
+			require.NoError(t, err)
+			_, err = p.Write(kafka.RecordBatch{
+				Records: []*kafka.Record{
+					{Time: time.Now(), Value: kafka.NewBytes([]byte(`"bar"`))},
+				},
+			})
+			require.NoError(t, err)
+
+			tc.test(t, p, logs)
+		})
+	}
+}
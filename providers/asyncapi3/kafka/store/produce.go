@@ -17,7 +17,8 @@ func (s *Store) produce(rw kafka.ResponseWriter, req *kafka.Request) error {
 
 	m, withMonitor := monitor.KafkaFromContext(req.Context)
 	opts := WriteOptions{
-		ClientId: ctx.ClientId,
+		ClientId:  ctx.ClientId,
+		Principal: ctx.Principal,
 	}
 
 	for _, rt := range r.Topics {
@@ -46,6 +47,10 @@ func (s *Store) produce(rw kafka.ResponseWriter, req *kafka.Request) error {
 					resPartition.ErrorCode = kafka.UnknownTopicOrPartition
 					resPartition.ErrorMessage = fmt.Sprintf("unknown partition %v", rp.Index)
 					log.Errorf("kafka: failed to write to topic '%s': %s", topic.Name, resPartition.ErrorMessage)
+				} else if errCode := s.validateTransactional(rp.Record, p); errCode != kafka.None {
+					resPartition.ErrorCode = errCode
+					resPartition.ErrorMessage = fmt.Sprintf("transactional records rejected: %v", errCode)
+					log.Errorf("kafka: failed to write to topic '%s' partition %d: %s", topic.Name, rp.Index, resPartition.ErrorMessage)
 				} else {
 					wr, err := p.write(rp.Record, opts)
 					if err != nil {
@@ -75,6 +80,14 @@ func (s *Store) produce(rw kafka.ResponseWriter, req *kafka.Request) error {
 	return rw.Write(res)
 }
 
+func (s *Store) validateTransactional(batch kafka.RecordBatch, p *Partition) kafka.ErrorCode {
+	if !batch.Transactional || len(batch.Records) == 0 {
+		return kafka.None
+	}
+	r := batch.Records[0]
+	return s.isInTransaction(r.ProducerId, r.ProducerEpoch, p)
+}
+
 func validateProducer(t *Topic, ctx *kafka.ClientContext) error {
 	for _, op := range t.operations {
 		if op.Action != "send" {
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"fmt"
+	"mokapi/kafka"
+	"mokapi/kafka/apiVersion"
+	"mokapi/kafka/saslAuthenticate"
+	"mokapi/kafka/saslHandshake"
+	"mokapi/providers/asyncapi3"
+	"mokapi/sasl"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// saslMechanisms returns the SASL mechanisms defined in the broker's
+// server security section mapped to their security scheme.
+func (b *Broker) saslMechanisms() map[string]*asyncapi3.SecurityScheme {
+	if b == nil || b.config == nil {
+		return nil
+	}
+
+	m := map[string]*asyncapi3.SecurityScheme{}
+	for _, ref := range b.config.Security {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		switch ref.Value.Type {
+		case "plain", "userPassword":
+			m[sasl.Plain] = ref.Value
+		case "scramSha256":
+			m[sasl.ScramSha256] = ref.Value
+		case "scramSha512":
+			m[sasl.ScramSha512] = ref.Value
+		}
+	}
+	return m
+}
+
+func (s *Store) saslhandshake(rw kafka.ResponseWriter, req *kafka.Request) error {
+	r := req.Message.(*saslHandshake.Request)
+	ctx := kafka.ClientFromContext(req.Context)
+	res := &saslHandshake.Response{}
+
+	mechanisms := s.getBrokerByPort(req.Host).saslMechanisms()
+	for name := range mechanisms {
+		res.Mechanisms = append(res.Mechanisms, name)
+	}
+	slices.Sort(res.Mechanisms)
+
+	if _, ok := mechanisms[r.Mechanism]; !ok {
+		log.Errorf("kafka: unsupported SASL mechanism '%v' requested by client '%v'", r.Mechanism, ctx.ClientId)
+		res.ErrorCode = kafka.UnsupportedSaslMechanism
+	} else {
+		ctx.SaslMechanism = r.Mechanism
+		ctx.SaslServer = nil
+	}
+
+	return rw.Write(res)
+}
+
+func (s *Store) saslauthenticate(rw kafka.ResponseWriter, req *kafka.Request) error {
+	r := req.Message.(*saslAuthenticate.Request)
+	ctx := kafka.ClientFromContext(req.Context)
+	res := &saslAuthenticate.Response{AuthBytes: []byte{}}
+
+	scheme, ok := s.getBrokerByPort(req.Host).saslMechanisms()[ctx.SaslMechanism]
+	if !ok {
+		res.ErrorCode = kafka.IllegalSaslState
+		res.ErrorMessage = "SaslAuthenticate request received before successful SaslHandshake"
+		return rw.Write(res)
+	}
+
+	if ctx.SaslServer == nil {
+		ctx.SaslServer = newSaslServer(ctx.SaslMechanism, scheme)
+	}
+
+	challenge, err := ctx.SaslServer.Next(r.AuthBytes)
+	if err != nil {
+		log.Errorf("kafka: SASL authentication failed for client '%v': %v", ctx.ClientId, err)
+		res.ErrorCode = kafka.SaslAuthenticationFailed
+		res.ErrorMessage = fmt.Sprintf("authentication failed: %v", err)
+		ctx.SaslServer = nil
+		ctx.SaslMechanism = ""
+		return rw.Write(res)
+	}
+	if challenge != nil {
+		res.AuthBytes = challenge
+	}
+	if !ctx.SaslServer.HasNext() {
+		if srv, ok := ctx.SaslServer.(*saslServer); ok {
+			ctx.Principal = srv.username
+		}
+		ctx.SaslServer = nil
+	}
+
+	return rw.Write(res)
+}
+
+// saslServer keeps track of the user across the SASL exchange
+type saslServer struct {
+	sasl.Server
+	username string
+}
+
+func newSaslServer(mechanism string, scheme *asyncapi3.SecurityScheme) *saslServer {
+	srv := &saslServer{}
+	credentials := func(name string) (string, error) {
+		for _, u := range scheme.Users {
+			if u.Username == name {
+				srv.username = name
+				return u.Password, nil
+			}
+		}
+		return "", fmt.Errorf("unknown user '%v'", name)
+	}
+
+	switch mechanism {
+	case sasl.ScramSha256:
+		srv.Server = sasl.NewScramSha256Server(credentials)
+	case sasl.ScramSha512:
+		srv.Server = sasl.NewScramSha512Server(credentials)
+	default:
+		srv.Server = sasl.NewPlainServer(func(_, name, password string) error {
+			p, err := credentials(name)
+			if err != nil || p != password {
+				return fmt.Errorf("invalid username or password")
+			}
+			return nil
+		})
+	}
+	return srv
+}
+
+// isAuthenticated reports whether the client may send the request. If the
+// broker requires SASL, only ApiVersions and SASL requests are allowed before
+// authentication.
+func (s *Store) isAuthenticated(req *kafka.Request) bool {
+	switch req.Message.(type) {
+	case *apiVersion.Request, *saslHandshake.Request, *saslAuthenticate.Request:
+		return true
+	}
+
+	if len(s.getBrokerByPort(req.Host).saslMechanisms()) == 0 {
+		return true
+	}
+	return kafka.ClientFromContext(req.Context).Principal != ""
+}
@@ -0,0 +1,215 @@
+package store_test
+
+import (
+	"mokapi/engine/common"
+	"mokapi/engine/enginetest"
+	"mokapi/kafka"
+	"mokapi/kafka/kafkatest"
+	"mokapi/kafka/metaData"
+	"mokapi/kafka/produce"
+	"mokapi/kafka/saslAuthenticate"
+	"mokapi/kafka/saslHandshake"
+	"mokapi/providers/asyncapi3"
+	"mokapi/providers/asyncapi3/asyncapi3test"
+	"mokapi/providers/asyncapi3/kafka/store"
+	"mokapi/runtime/events/eventstest"
+	"mokapi/runtime/monitor"
+	"mokapi/sasl"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSasl(t *testing.T) {
+	users := []asyncapi3.SecurityUser{{Username: "alice", Password: "secret"}}
+	security := asyncapi3test.WithServerSecurity(
+		&asyncapi3.SecurityScheme{Type: "plain", Users: users},
+		&asyncapi3.SecurityScheme{Type: "scramSha512", Users: users},
+	)
+
+	testcases := []struct {
+		name string
+		fn   func(t *testing.T, s *store.Store)
+	}{
+		{
+			name: "handshake unsupported mechanism",
+			fn: func(t *testing.T, s *store.Store) {
+				s.Update(asyncapi3test.NewConfig(asyncapi3test.WithServer("", "kafka", "127.0.0.1:9092", security)))
+
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 1, &saslHandshake.Request{Mechanism: "GSSAPI"}))
+				res, ok := rr.Message.(*saslHandshake.Response)
+				require.True(t, ok)
+				require.Equal(t, kafka.UnsupportedSaslMechanism, res.ErrorCode)
+				require.Equal(t, []string{"PLAIN", "SCRAM-SHA-512"}, res.Mechanisms)
+			},
+		},
+		{
+			name: "authenticate before handshake",
+			fn: func(t *testing.T, s *store.Store) {
+				s.Update(asyncapi3test.NewConfig(asyncapi3test.WithServer("", "kafka", "127.0.0.1:9092", security)))
+
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 2, &saslAuthenticate.Request{AuthBytes: []byte("\x00alice\x00secret")}))
+				res, ok := rr.Message.(*saslAuthenticate.Response)
+				require.True(t, ok)
+				require.Equal(t, kafka.IllegalSaslState, res.ErrorCode)
+			},
+		},
+		{
+			name: "request before authentication is rejected",
+			fn: func(t *testing.T, s *store.Store) {
+				s.Update(asyncapi3test.NewConfig(asyncapi3test.WithServer("", "kafka", "127.0.0.1:9092", security)))
+
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 4, &metaData.Request{}))
+				require.Nil(t, rr.Message)
+			},
+		},
+		{
+			name: "no security configured",
+			fn: func(t *testing.T, s *store.Store) {
+				s.Update(asyncapi3test.NewConfig(asyncapi3test.WithServer("", "kafka", "127.0.0.1:9092")))
+
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 1, &saslHandshake.Request{Mechanism: "PLAIN"}))
+				res, ok := rr.Message.(*saslHandshake.Response)
+				require.True(t, ok)
+				require.Equal(t, kafka.UnsupportedSaslMechanism, res.ErrorCode)
+				require.Len(t, res.Mechanisms, 0)
+
+				rr = kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 4, &metaData.Request{}))
+				require.IsType(t, &metaData.Response{}, rr.Message)
+			},
+		},
+		{
+			name: "PLAIN wrong password",
+			fn: func(t *testing.T, s *store.Store) {
+				b := kafkatest.NewBroker(kafkatest.WithHandler(s))
+				defer b.Close()
+				s.Update(asyncapi3test.NewConfig(asyncapi3test.WithServer("", "kafka", b.Addr, security)))
+
+				hs, err := b.Client().SaslHandshake(1, &saslHandshake.Request{Mechanism: "PLAIN"})
+				require.NoError(t, err)
+				require.Equal(t, kafka.None, hs.ErrorCode)
+
+				res, err := b.Client().SaslAuthenticate(2, &saslAuthenticate.Request{AuthBytes: []byte("\x00alice\x00foo")})
+				require.NoError(t, err)
+				require.Equal(t, kafka.SaslAuthenticationFailed, res.ErrorCode)
+				require.Equal(t, "authentication failed: invalid username or password", res.ErrorMessage)
+			},
+		},
+		{
+			name: "PLAIN",
+			fn: func(t *testing.T, s *store.Store) {
+				b := kafkatest.NewBroker(kafkatest.WithHandler(s))
+				defer b.Close()
+				s.Update(asyncapi3test.NewConfig(asyncapi3test.WithServer("", "kafka", b.Addr, security)))
+
+				hs, err := b.Client().SaslHandshake(1, &saslHandshake.Request{Mechanism: "PLAIN"})
+				require.NoError(t, err)
+				require.Equal(t, kafka.None, hs.ErrorCode)
+
+				res, err := b.Client().SaslAuthenticate(2, &saslAuthenticate.Request{AuthBytes: []byte("\x00alice\x00secret")})
+				require.NoError(t, err)
+				require.Equal(t, kafka.None, res.ErrorCode)
+
+				md, err := b.Client().Metadata(4, &metaData.Request{})
+				require.NoError(t, err)
+				require.Len(t, md.Brokers, 1)
+			},
+		},
+		{
+			name: "SCRAM-SHA-512",
+			fn: func(t *testing.T, s *store.Store) {
+				b := kafkatest.NewBroker(kafkatest.WithHandler(s))
+				defer b.Close()
+				s.Update(asyncapi3test.NewConfig(asyncapi3test.WithServer("", "kafka", b.Addr, security)))
+
+				hs, err := b.Client().SaslHandshake(1, &saslHandshake.Request{Mechanism: "SCRAM-SHA-512"})
+				require.NoError(t, err)
+				require.Equal(t, kafka.None, hs.ErrorCode)
+
+				c := sasl.NewScramSha512Client("alice", "secret")
+				var challenge []byte
+				for c.HasNext() {
+					msg, err := c.Next(challenge)
+					require.NoError(t, err)
+					if msg == nil {
+						break
+					}
+					res, err := b.Client().SaslAuthenticate(1, &saslAuthenticate.Request{AuthBytes: msg})
+					require.NoError(t, err)
+					require.Equal(t, kafka.None, res.ErrorCode, res.ErrorMessage)
+					challenge = res.AuthBytes
+				}
+
+				md, err := b.Client().Metadata(4, &metaData.Request{})
+				require.NoError(t, err)
+				require.Len(t, md.Brokers, 1)
+			},
+		},
+	}
+
+	t.Parallel()
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := store.New(asyncapi3test.NewConfig(), enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+			defer s.Close()
+			tc.fn(t, s)
+		})
+	}
+}
+
+func TestSasl_PrincipalInEventContext(t *testing.T) {
+	var ctx *store.EventContext
+	s := store.New(asyncapi3test.NewConfig(), enginetest.NewEngineWithHandler(func(event string, args ...interface{}) []*common.Action {
+		ctx = args[1].(*store.EventContext)
+		return nil
+	}), &eventstest.Handler{}, monitor.NewKafka())
+	defer s.Close()
+
+	b := kafkatest.NewBroker(kafkatest.WithHandler(s))
+	defer b.Close()
+	s.Update(asyncapi3test.NewConfig(
+		asyncapi3test.WithServer("", "kafka", b.Addr, asyncapi3test.WithServerSecurity(
+			&asyncapi3.SecurityScheme{Type: "plain", Users: []asyncapi3.SecurityUser{{Username: "alice", Password: "secret"}}},
+		)),
+		asyncapi3test.WithChannel("foo"),
+	))
+
+	_, err := b.Client().SaslHandshake(1, &saslHandshake.Request{Mechanism: "PLAIN"})
+	require.NoError(t, err)
+	_, err = b.Client().SaslAuthenticate(2, &saslAuthenticate.Request{AuthBytes: []byte("\x00alice\x00secret")})
+	require.NoError(t, err)
+
+	res, err := b.Client().Produce(3, &produce.Request{
+		Topics: []produce.RequestTopic{
+			{Name: "foo", Partitions: []produce.RequestPartition{
+				{
+					Record: kafka.RecordBatch{
+						Records: []*kafka.Record{
+							{
+								Time:  time.Now(),
+								Key:   kafka.NewBytes([]byte("foo")),
+								Value: kafka.NewBytes([]byte("bar")),
+							},
+						},
+					},
+				},
+			}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, kafka.None, res.Topics[0].Partitions[0].ErrorCode)
+
+	require.NotNil(t, ctx)
+	require.Equal(t, "alice", ctx.Principal)
+	require.Equal(t, "foo", ctx.Topic)
+	require.Equal(t, "kafkatest", ctx.ClientId)
+}
@@ -0,0 +1,567 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mokapi/kafka"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Layout of the data directory of a cluster:
+//
+//	groups.json                   committed offsets of consumer groups
+//	producers.json                producer ids, epochs and transactional ids
+//	<topic>-<partition>/          one directory per partition containing
+//	  <base offset>.log           the record batches of a segment
+//	  partition.json              offsets, segment times and producer states
+const (
+	groupsFile       = "groups.json"
+	producersFile    = "producers.json"
+	partitionFile    = "partition.json"
+	segmentExtension = ".log"
+)
+
+type partitionState struct {
+	StartOffset int64                    `json:"startOffset"`
+	Offset      int64                    `json:"offset"`
+	Segments    []segmentState           `json:"segments"`
+	Producers   []PartitionProducerState `json:"producers,omitempty"`
+	Aborted     []AbortedTransaction     `json:"aborted,omitempty"`
+	// first offset of the ongoing transaction by producer id
+	Ongoing map[int64]int64 `json:"ongoing,omitempty"`
+}
+
+type segmentState struct {
+	Head        int64     `json:"head"`
+	Tail        int64     `json:"tail"`
+	Opened      time.Time `json:"opened"`
+	Closed      time.Time `json:"closed,omitempty"`
+	LastWritten time.Time `json:"lastWritten,omitempty"`
+}
+
+type producersState struct {
+	NextProducerId int64              `json:"nextProducerId"`
+	Producers      []ProducerState    `json:"producers,omitempty"`
+	Transactions   []transactionState `json:"transactions,omitempty"`
+}
+
+type transactionState struct {
+	TransactionalId string `json:"transactionalId"`
+	ProducerId      int64  `json:"producerId"`
+	ProducerEpoch   int16  `json:"producerEpoch"`
+	TimeoutMs       int32  `json:"timeoutMs"`
+}
+
+type groupState struct {
+	Name    string                   `json:"name"`
+	Commits map[string]map[int]int64 `json:"commits"`
+}
+
+// partitionLog writes the segments of a partition to files. Only the file of
+// the active segment is kept open.
+type partitionLog struct {
+	dir        string
+	active     *os.File
+	activeHead int64
+}
+
+// SetDataDir enables storing partitions, consumer group offsets and producer
+// states in the given directory. Data of a previous run is restored when
+// topics and brokers are added by Update, so it must be called before.
+func (s *Store) SetDataDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create kafka data directory failed: %w", err)
+	}
+
+	var producers producersState
+	if err := readState(filepath.Join(dir, producersFile), &producers); err != nil {
+		return err
+	}
+	var groups []groupState
+	if err := readState(filepath.Join(dir, groupsFile), &groups); err != nil {
+		return err
+	}
+
+	s.dataDir = dir
+	s.nextPID = producers.NextProducerId
+	for _, ps := range producers.Producers {
+		s.producers[ps.ProducerId] = &ProducerState{ProducerId: ps.ProducerId, ProducerEpoch: ps.ProducerEpoch}
+	}
+	for _, txn := range producers.Transactions {
+		s.transactions[txn.TransactionalId] = &Transaction{
+			TransactionalId: txn.TransactionalId,
+			ProducerId:      txn.ProducerId,
+			ProducerEpoch:   txn.ProducerEpoch,
+			TimeoutMs:       txn.TimeoutMs,
+		}
+	}
+	s.restoredGroups = make(map[string]map[string]map[int]int64)
+	for _, g := range groups {
+		s.restoredGroups[g.Name] = g.Commits
+	}
+
+	log.Infof("kafka: using data directory %v", dir)
+	return nil
+}
+
+// restoreGroups adds the consumer groups of a previous run. Groups need a
+// coordinator, therefore they are restored once the brokers are known.
+func (s *Store) restoreGroups() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if len(s.restoredGroups) == 0 || len(s.brokers) == 0 {
+		return
+	}
+	for name, commits := range s.restoredGroups {
+		if _, ok := s.groups[name]; ok {
+			continue
+		}
+		g := s.newGroup(name, s.brokers[0])
+		g.Commits = commits
+		s.groups[name] = g
+	}
+	log.Infof("kafka: restored %v consumer groups of cluster '%v'", len(s.restoredGroups), s.cluster)
+	s.restoredGroups = nil
+}
+
+func (s *Store) saveGroups() {
+	if s.dataDir == "" {
+		return
+	}
+
+	s.m.RLock()
+	groups := make([]groupState, 0, len(s.groups))
+	for _, g := range s.groups {
+		groups = append(groups, groupState{Name: g.Name, Commits: g.CommittedOffsets()})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+	err := writeState(filepath.Join(s.dataDir, groupsFile), groups)
+	s.m.RUnlock()
+
+	if err != nil {
+		log.Errorf("kafka: unable to save consumer groups: %v", err)
+	}
+}
+
+// saveProducers writes the producer ids and transactional ids. The caller must
+// hold the transaction lock.
+func (s *Store) saveProducers() {
+	if s.dataDir == "" {
+		return
+	}
+
+	state := producersState{NextProducerId: s.nextPID}
+	for _, ps := range s.producers {
+		state.Producers = append(state.Producers, *ps)
+	}
+	sort.Slice(state.Producers, func(i, j int) bool {
+		return state.Producers[i].ProducerId < state.Producers[j].ProducerId
+	})
+	for _, txn := range s.transactions {
+		state.Transactions = append(state.Transactions, transactionState{
+			TransactionalId: txn.TransactionalId,
+			ProducerId:      txn.ProducerId,
+			ProducerEpoch:   txn.ProducerEpoch,
+			TimeoutMs:       txn.TimeoutMs,
+		})
+	}
+	sort.Slice(state.Transactions, func(i, j int) bool {
+		return state.Transactions[i].TransactionalId < state.Transactions[j].TransactionalId
+	})
+
+	if err := writeState(filepath.Join(s.dataDir, producersFile), state); err != nil {
+		log.Errorf("kafka: unable to save producer states: %v", err)
+	}
+}
+
+func (s *Store) partitionDir(topic string, index int) string {
+	return filepath.Join(s.dataDir, fmt.Sprintf("%v-%v", url.PathEscape(topic), index))
+}
+
+func openPartitionLog(dir string) (*partitionLog, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create partition directory failed: %w", err)
+	}
+	return &partitionLog{dir: dir}, nil
+}
+
+// restore loads the segments and state of the partition written by a previous
+// run. Transactions which were ongoing when Mokapi stopped are aborted.
+func (p *Partition) restore() error {
+	var state partitionState
+	if err := readState(filepath.Join(p.files.dir, partitionFile), &state); err != nil {
+		return err
+	}
+	times := map[int64]segmentState{}
+	for _, seg := range state.Segments {
+		times[seg.Head] = seg
+	}
+
+	heads, err := p.files.segments()
+	if err != nil {
+		return err
+	}
+
+	p.Head = state.StartOffset
+	p.Tail = state.Offset
+	for i, head := range heads {
+		seg, modTime, err := p.files.read(head)
+		if err != nil {
+			return err
+		}
+		if t, ok := times[head]; ok {
+			seg.Tail = max(seg.Tail, t.Tail)
+			seg.Opened = t.Opened
+			seg.Closed = t.Closed
+			seg.LastWritten = t.LastWritten
+		} else {
+			seg.Opened = modTime
+			seg.LastWritten = modTime
+		}
+		if i < len(heads)-1 && seg.Closed.IsZero() {
+			seg.Closed = modTime
+		}
+		for int64(len(seg.Log)) < seg.Tail-seg.Head {
+			// records at the end removed by log compaction
+			seg.Log = append(seg.Log, nil)
+		}
+
+		p.Segments[head] = seg
+		p.ActiveSegment = head
+		p.Tail = max(p.Tail, seg.Tail)
+		if i == 0 {
+			p.Head = max(p.Head, seg.Head)
+		}
+	}
+	if len(heads) == 0 {
+		p.Head = p.Tail
+		p.ActiveSegment = p.Tail
+	}
+
+	for _, ps := range state.Producers {
+		p.producers[ps.ProducerId] = &ps
+	}
+	p.aborted = state.Aborted
+	for producerId, first := range state.Ongoing {
+		p.ongoing[producerId] = first
+	}
+	p.restoreProducers(heads, state.Offset)
+
+	if len(heads) > 0 {
+		log.Infof("kafka: restored offset [%v:%v] of partition %v topic '%s'", p.Head, p.Tail, p.Index, p.Topic.Name)
+	}
+
+	for producerId := range p.ongoing {
+		var epoch int16
+		if ps, ok := p.producers[producerId]; ok {
+			epoch = ps.Epoch
+		}
+		log.Infof("kafka: aborting transaction of producer %v interrupted by restart on partition %v topic '%s'", producerId, p.Index, p.Topic.Name)
+		p.writeMarker(producerId, epoch, false)
+	}
+
+	return nil
+}
+
+// restoreProducers rebuilds the producer states from the records written
+// after the state of the partition was saved. Producer states are not saved
+// on every write, so sequence numbers and ongoing transactions of the tail
+// are read from the segment files.
+func (p *Partition) restoreProducers(heads []int64, from int64) {
+	for _, head := range heads {
+		for _, r := range p.Segments[head].Log {
+			if r == nil || r.Data.Offset < from || r.Data.ProducerId <= 0 {
+				continue
+			}
+			producerId := r.Data.ProducerId
+			if r.Control {
+				delete(p.ongoing, producerId)
+				continue
+			}
+			state, ok := p.producers[producerId]
+			if !ok {
+				state = &PartitionProducerState{ProducerId: producerId}
+				p.producers[producerId] = state
+			}
+			state.Epoch = r.Data.ProducerEpoch
+			state.LastSequence = r.Data.SequenceNumber
+			if _, ok := p.ongoing[producerId]; r.Transactional && !ok {
+				p.ongoing[producerId] = r.Data.Offset
+			}
+		}
+	}
+}
+
+// saveState writes the offsets, segment times and producer states of the
+// partition. It is called when segments change, for transaction markers and
+// on close, not for every produced record. The caller must hold the
+// partition lock.
+func (p *Partition) saveState() {
+	if p.files == nil {
+		return
+	}
+
+	state := partitionState{
+		StartOffset: p.Head,
+		Offset:      p.Tail,
+		Aborted:     p.aborted,
+	}
+	for _, seg := range p.Segments {
+		state.Segments = append(state.Segments, segmentState{
+			Head:        seg.Head,
+			Tail:        seg.Tail,
+			Opened:      seg.Opened,
+			Closed:      seg.Closed,
+			LastWritten: seg.LastWritten,
+		})
+	}
+	sort.Slice(state.Segments, func(i, j int) bool {
+		return state.Segments[i].Head < state.Segments[j].Head
+	})
+	for _, ps := range p.producers {
+		state.Producers = append(state.Producers, *ps)
+	}
+	sort.Slice(state.Producers, func(i, j int) bool {
+		return state.Producers[i].ProducerId < state.Producers[j].ProducerId
+	})
+	if len(p.ongoing) > 0 {
+		state.Ongoing = p.ongoing
+	}
+
+	if err := writeState(filepath.Join(p.files.dir, partitionFile), state); err != nil {
+		log.Errorf("kafka: unable to save state of partition %v topic '%s': %v", p.Index, p.Topic.Name, err)
+	}
+}
+
+// persist appends the record to the file of its segment
+func (p *Partition) persist(seg *Segment, r *record) {
+	if p.files == nil {
+		return
+	}
+	if err := p.files.append(seg.Head, r); err != nil {
+		log.Errorf("kafka: unable to write offset %v of partition %v topic '%s': %v", r.Data.Offset, p.Index, p.Topic.Name, err)
+	}
+}
+
+// rewrite replaces the file of the segment after records have been removed
+func (p *Partition) rewrite(seg *Segment) {
+	if p.files == nil {
+		return
+	}
+	if err := p.files.rewrite(seg); err != nil {
+		log.Errorf("kafka: unable to rewrite segment [%v:%v] of partition %v topic '%s': %v", seg.Head, seg.Tail, p.Index, p.Topic.Name, err)
+	}
+}
+
+func (l *partitionLog) segmentPath(head int64) string {
+	return filepath.Join(l.dir, fmt.Sprintf("%020d%s", head, segmentExtension))
+}
+
+// segments returns the base offsets of the segment files in ascending order
+func (l *partitionLog) segments() ([]int64, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, err
+	}
+	var heads []int64
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, segmentExtension) {
+			continue
+		}
+		head, err := strconv.ParseInt(strings.TrimSuffix(name, segmentExtension), 10, 64)
+		if err != nil {
+			continue
+		}
+		heads = append(heads, head)
+	}
+	sort.Slice(heads, func(i, j int) bool { return heads[i] < heads[j] })
+	return heads, nil
+}
+
+// read loads the records of a segment file. An incomplete or corrupt batch at
+// the end of the file, for example after a crash, is truncated.
+func (l *partitionLog) read(head int64) (*Segment, time.Time, error) {
+	path := l.segmentPath(head)
+	f, err := os.OpenFile(path, os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer func() { _ = f.Close() }()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	seg := newSegment(head)
+	valid := int64(0)
+	for {
+		rb, n, err := kafka.ReadLog(f)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Warnf("kafka: truncating segment file %v at position %v: %v", path, valid, err)
+			if err = f.Truncate(valid); err != nil {
+				return nil, time.Time{}, err
+			}
+			break
+		}
+		valid += int64(n)
+
+		for _, r := range rb.Records {
+			index := int(r.Offset - head)
+			if index < len(seg.Log) {
+				continue
+			}
+			for len(seg.Log) < index {
+				// removed by log compaction or deletion
+				seg.Log = append(seg.Log, nil)
+			}
+			// copy record data to release the page buffer of the decoded batch
+			r.Key = copyBytes(r.Key)
+			r.Value = copyBytes(r.Value)
+			seg.Log = append(seg.Log, &record{
+				Data:          r,
+				Compression:   rb.Compression,
+				Transactional: rb.Transactional,
+				Control:       rb.Control,
+			})
+			seg.Size += r.Size(r.Offset, r.Time)
+		}
+	}
+	seg.Tail = head + int64(len(seg.Log))
+
+	return seg, fi.ModTime(), nil
+}
+
+func (l *partitionLog) append(head int64, r *record) error {
+	if l.active == nil || l.activeHead != head {
+		l.close()
+		f, err := os.OpenFile(l.segmentPath(head), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		l.active = f
+		l.activeHead = head
+	}
+	_, err := r.batch().WriteLog(l.active)
+	return err
+}
+
+func (l *partitionLog) rewrite(seg *Segment) error {
+	path := l.segmentPath(seg.Head)
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	for _, r := range seg.Log {
+		if r == nil {
+			continue
+		}
+		if _, err = r.batch().WriteLog(f); err != nil {
+			_ = f.Close()
+			_ = os.Remove(tmp)
+			return err
+		}
+	}
+	if err = f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if l.active != nil && l.activeHead == seg.Head {
+		l.close()
+	}
+	return os.Rename(tmp, path)
+}
+
+func (l *partitionLog) removeSegment(head int64) {
+	if l.active != nil && l.activeHead == head {
+		l.close()
+	}
+	if err := os.Remove(l.segmentPath(head)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Errorf("kafka: unable to delete segment file: %v", err)
+	}
+}
+
+func (l *partitionLog) delete() {
+	l.close()
+	if err := os.RemoveAll(l.dir); err != nil {
+		log.Errorf("kafka: unable to delete partition directory: %v", err)
+	}
+}
+
+func (l *partitionLog) close() {
+	if l.active == nil {
+		return
+	}
+	if err := l.active.Close(); err != nil {
+		log.Errorf("kafka: unable to close segment file: %v", err)
+	}
+	l.active = nil
+}
+
+// batch returns the record as a batch of its own to keep its compression
+// codec and transactional attributes in the segment file
+func (r *record) batch() *kafka.RecordBatch {
+	return &kafka.RecordBatch{
+		Records:       []*kafka.Record{r.Data},
+		Compression:   r.Compression,
+		Transactional: r.Transactional,
+		Control:       r.Control,
+	}
+}
+
+func copyBytes(b kafka.Bytes) kafka.Bytes {
+	if b == nil {
+		return nil
+	}
+	c := kafka.NewBytes(kafka.Read(b))
+	_ = b.Close()
+	return c
+}
+
+func readState(path string, v any) error {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("read %v failed: %w", path, err)
+	}
+	return nil
+}
+
+// writeState replaces the file atomically so that a crash does not leave a
+// partially written state behind
+func writeState(path string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
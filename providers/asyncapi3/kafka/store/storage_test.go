@@ -0,0 +1,347 @@
+package store_test
+
+import (
+	"mokapi/engine/enginetest"
+	"mokapi/kafka"
+	"mokapi/kafka/deleteRecords"
+	"mokapi/kafka/deleteTopics"
+	"mokapi/kafka/initProducerId"
+	"mokapi/kafka/kafkatest"
+	"mokapi/kafka/offsetCommit"
+	"mokapi/providers/asyncapi3"
+	"mokapi/providers/asyncapi3/asyncapi3test"
+	"mokapi/providers/asyncapi3/kafka/store"
+	"mokapi/runtime/events/eventstest"
+	"mokapi/runtime/monitor"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_DataDir(t *testing.T) {
+	testcases := []struct {
+		name string
+		test func(t *testing.T, dir string, cfg *asyncapi3.Config)
+	}{
+		{
+			name: "records are restored",
+			test: func(t *testing.T, dir string, cfg *asyncapi3.Config) {
+				s := newDurableStore(t, dir, cfg)
+				writeKeyValue(t, s.Topic("foo"), "key-1", "a")
+				writeKeyValue(t, s.Topic("foo"), "key-2", "b")
+				s.Close()
+
+				require.FileExists(t, filepath.Join(dir, "foo-0", "00000000000000000000.log"))
+
+				s = newDurableStore(t, dir, cfg)
+				defer s.Close()
+				p := s.Topic("foo").Partition(0)
+				require.Equal(t, int64(0), p.StartOffset())
+				require.Equal(t, int64(2), p.Offset())
+
+				b, errCode := p.Read(0, 1000)
+				require.Equal(t, kafka.None, errCode)
+				require.Len(t, b.Records, 2)
+				require.Equal(t, "key-2", kafka.BytesToString(b.Records[1].Key))
+				require.Equal(t, "b", kafka.BytesToString(b.Records[1].Value))
+
+				writeKeyValue(t, s.Topic("foo"), "key-3", "c")
+				require.Equal(t, int64(3), p.Offset())
+			},
+		},
+		{
+			name: "deleted records are not restored",
+			test: func(t *testing.T, dir string, cfg *asyncapi3.Config) {
+				s := newDurableStore(t, dir, cfg)
+				for _, v := range []string{"a", "b", "c"} {
+					writeKeyValue(t, s.Topic("foo"), v, v)
+				}
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 2, &deleteRecords.Request{
+					Topics: []deleteRecords.Topic{
+						{Name: "foo", Partitions: []deleteRecords.Partition{{PartitionIndex: 0, Offset: 2}}},
+					},
+				}))
+				s.Close()
+
+				s = newDurableStore(t, dir, cfg)
+				defer s.Close()
+				p := s.Topic("foo").Partition(0)
+				require.Equal(t, int64(2), p.StartOffset())
+				require.Equal(t, int64(3), p.Offset())
+
+				b, errCode := p.Read(2, 1000)
+				require.Equal(t, kafka.None, errCode)
+				require.Len(t, b.Records, 1)
+				require.Equal(t, "c", kafka.BytesToString(b.Records[0].Value))
+			},
+		},
+		{
+			name: "compacted records at the end of a segment are restored as gaps",
+			test: func(t *testing.T, dir string, _ *asyncapi3.Config) {
+				cfg := newCompactedConfig(asyncapi3.TopicBindings{DeleteRetentionMs: 1})
+				s := newDurableStore(t, dir, cfg)
+				writeKeyValue(t, s.Topic("foo"), "key-1", "a")
+				rErr, err := s.Topic("foo").WritePartition(0, &kafka.Record{Key: kafka.NewBytes([]byte("key-1"))})
+				require.NoError(t, err)
+				require.Nil(t, rErr)
+
+				time.Sleep(800 * time.Millisecond)
+				b, errCode := s.Topic("foo").Partition(0).Read(0, 1000)
+				require.Equal(t, kafka.None, errCode)
+				require.Len(t, b.Records, 0)
+				s.Close()
+
+				s = newDurableStore(t, dir, cfg)
+				defer s.Close()
+				p := s.Topic("foo").Partition(0)
+				require.Equal(t, int64(2), p.Offset())
+
+				b, errCode = p.Read(0, 1000)
+				require.Equal(t, kafka.None, errCode)
+				require.Len(t, b.Records, 0)
+
+				writeKeyValue(t, s.Topic("foo"), "key-2", "b")
+				b, errCode = p.Read(0, 1000)
+				require.Equal(t, kafka.None, errCode)
+				require.Len(t, b.Records, 1)
+				require.Equal(t, int64(2), b.Records[0].Offset)
+				require.Equal(t, "b", kafka.BytesToString(b.Records[0].Value))
+			},
+		},
+		{
+			name: "incomplete batch is truncated",
+			test: func(t *testing.T, dir string, cfg *asyncapi3.Config) {
+				s := newDurableStore(t, dir, cfg)
+				writeKeyValue(t, s.Topic("foo"), "key-1", "a")
+				s.Close()
+
+				file := filepath.Join(dir, "foo-0", "00000000000000000000.log")
+				fi, err := os.Stat(file)
+				require.NoError(t, err)
+				f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0o644)
+				require.NoError(t, err)
+				_, err = f.Write([]byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 80, 1})
+				require.NoError(t, err)
+				require.NoError(t, f.Close())
+
+				s = newDurableStore(t, dir, cfg)
+				defer s.Close()
+				require.Equal(t, int64(1), s.Topic("foo").Partition(0).Offset())
+
+				truncated, err := os.Stat(file)
+				require.NoError(t, err)
+				require.Equal(t, fi.Size(), truncated.Size())
+			},
+		},
+		{
+			name: "deleted topic removes files",
+			test: func(t *testing.T, dir string, cfg *asyncapi3.Config) {
+				s := newDurableStore(t, dir, cfg)
+				defer s.Close()
+				writeKeyValue(t, s.Topic("foo"), "key-1", "a")
+				require.DirExists(t, filepath.Join(dir, "foo-0"))
+
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 4, &deleteTopics.Request{TopicNames: []string{"foo"}}))
+
+				require.NoDirExists(t, filepath.Join(dir, "foo-0"))
+			},
+		},
+		{
+			name: "producer ids are restored",
+			test: func(t *testing.T, dir string, cfg *asyncapi3.Config) {
+				s := newDurableStore(t, dir, cfg)
+				res := initProducer(t, s, &initProducerId.Request{TransactionalId: "txn", TransactionTimeoutMs: 1000})
+				require.Equal(t, int64(1), res.ProducerId)
+				require.Equal(t, int16(0), res.ProducerEpoch)
+				s.Close()
+
+				s = newDurableStore(t, dir, cfg)
+				defer s.Close()
+				// new instance of the transactional producer
+				res = initProducer(t, s, &initProducerId.Request{TransactionalId: "txn", TransactionTimeoutMs: 1000})
+				require.Equal(t, int64(1), res.ProducerId)
+				require.Equal(t, int16(1), res.ProducerEpoch)
+
+				res = initProducer(t, s, &initProducerId.Request{})
+				require.Equal(t, int64(2), res.ProducerId)
+			},
+		},
+		{
+			name: "producer sequences are restored from segment files",
+			test: func(t *testing.T, dir string, cfg *asyncapi3.Config) {
+				s := newDurableStore(t, dir, cfg)
+				pid := initProducer(t, s, &initProducerId.Request{})
+				p := s.Topic("foo").Partition(0)
+				for _, seq := range []int32{0, 1} {
+					res, err := p.Write(idempotentBatch(pid, seq, "a"))
+					require.NoError(t, err)
+					require.Equal(t, kafka.None, res.ErrorCode)
+				}
+				b, err := os.ReadFile(filepath.Join(dir, "foo-0", "partition.json"))
+				require.NoError(t, err)
+				require.NotContains(t, string(b), "lastSequence")
+				// no close to simulate a crash
+
+				s = newDurableStore(t, dir, cfg)
+				defer s.Close()
+				p = s.Topic("foo").Partition(0)
+				res, err := p.Write(idempotentBatch(pid, 1, "b"))
+				require.NoError(t, err)
+				require.Equal(t, kafka.DuplicateSequenceNumber, res.ErrorCode, res.ErrorMessage)
+
+				res, err = p.Write(idempotentBatch(pid, 2, "c"))
+				require.NoError(t, err)
+				require.Equal(t, kafka.None, res.ErrorCode)
+				require.Equal(t, int64(3), p.Offset())
+			},
+		},
+		{
+			name: "producer states are saved on close",
+			test: func(t *testing.T, dir string, cfg *asyncapi3.Config) {
+				s := newDurableStore(t, dir, cfg)
+				pid := initProducer(t, s, &initProducerId.Request{})
+				res, err := s.Topic("foo").Partition(0).Write(idempotentBatch(pid, 0, "a"))
+				require.NoError(t, err)
+				require.Equal(t, kafka.None, res.ErrorCode)
+				s.Close()
+
+				b, err := os.ReadFile(filepath.Join(dir, "foo-0", "partition.json"))
+				require.NoError(t, err)
+				require.Contains(t, string(b), `"LastSequence":0`)
+			},
+		},
+		{
+			name: "transaction interrupted before a state was saved is aborted",
+			test: func(t *testing.T, dir string, cfg *asyncapi3.Config) {
+				s := newDurableStore(t, dir, cfg)
+				pid := initProducer(t, s, &initProducerId.Request{TransactionalId: "txn", TransactionTimeoutMs: 1000})
+				batch := idempotentBatch(pid, 0, "a")
+				batch.Transactional = true
+				res, err := s.Topic("foo").Partition(0).Write(batch)
+				require.NoError(t, err)
+				require.Equal(t, kafka.None, res.ErrorCode)
+				// no close to simulate a crash
+
+				s = newDurableStore(t, dir, cfg)
+				defer s.Close()
+				p := s.Topic("foo").Partition(0)
+				require.Equal(t, int64(2), p.Offset())
+				require.Equal(t, int64(2), p.LastStableOffset())
+				require.Len(t, p.AbortedTransactions(0, 2), 1)
+			},
+		},
+		{
+			name: "committed offsets are restored",
+			test: func(t *testing.T, dir string, _ *asyncapi3.Config) {
+				s := store.NewEmpty(enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+				require.NoError(t, s.SetDataDir(dir))
+				b := kafkatest.NewBroker(kafkatest.WithHandler(s))
+				defer b.Close()
+				cfg := asyncapi3test.NewConfig(
+					asyncapi3test.WithServer("", "kafka", b.Addr),
+					asyncapi3test.WithChannel("foo"),
+				)
+				s.Update(cfg)
+				writeKeyValue(t, s.Topic("foo"), "key-1", "a")
+
+				err := b.Client().JoinSyncGroup("foo", "bar", 3, 3)
+				require.NoError(t, err)
+				r, err := b.Client().OffsetCommit(2, &offsetCommit.Request{
+					GroupId:  "bar",
+					MemberId: "foo",
+					Topics: []offsetCommit.Topic{
+						{Name: "foo", Partitions: []offsetCommit.Partition{{Index: 0, Offset: 1}}},
+					},
+				})
+				require.NoError(t, err)
+				require.Equal(t, kafka.None, r.Topics[0].Partitions[0].ErrorCode)
+				s.Close()
+
+				s = newDurableStore(t, dir, cfg)
+				defer s.Close()
+				g, ok := s.Group("bar")
+				require.True(t, ok)
+				require.Equal(t, int64(1), g.Offset("foo", 0))
+			},
+		},
+		{
+			name: "retention deletes segment files",
+			test: func(t *testing.T, dir string, _ *asyncapi3.Config) {
+				cfg := asyncapi3test.NewConfig(
+					asyncapi3test.WithServer("foo", "kafka", "",
+						asyncapi3test.WithKafkaServerBinding(asyncapi3.BrokerBindings{
+							LogRetentionCheckIntervalMs: 200,
+							LogRollMs:                   10,
+							LogRetentionMs:              200,
+						}),
+					),
+					asyncapi3test.WithChannel("foo"),
+				)
+				s := newDurableStore(t, dir, cfg)
+				writeKeyValue(t, s.Topic("foo"), "key-1", "a")
+				require.FileExists(t, filepath.Join(dir, "foo-0", "00000000000000000000.log"))
+
+				// first run closes the segment, a later run deletes it
+				time.Sleep(800 * time.Millisecond)
+				require.NoFileExists(t, filepath.Join(dir, "foo-0", "00000000000000000000.log"))
+
+				writeKeyValue(t, s.Topic("foo"), "key-2", "b")
+				require.FileExists(t, filepath.Join(dir, "foo-0", "00000000000000000001.log"))
+				s.Close()
+
+				s = newDurableStore(t, dir, cfg)
+				defer s.Close()
+				p := s.Topic("foo").Partition(0)
+				require.Equal(t, int64(1), p.StartOffset())
+				require.Equal(t, int64(2), p.Offset())
+			},
+		},
+	}
+
+	t.Parallel()
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := asyncapi3test.NewConfig(
+				asyncapi3test.WithChannel("foo", asyncapi3test.WithKafkaChannelBinding(asyncapi3.TopicBindings{Partitions: 1})),
+			)
+			tc.test(t, t.TempDir(), cfg)
+		})
+	}
+}
+
+func newDurableStore(t *testing.T, dir string, cfg *asyncapi3.Config) *store.Store {
+	s := store.NewEmpty(enginetest.NewEngine(), &eventstest.Handler{}, monitor.NewKafka())
+	require.NoError(t, s.SetDataDir(dir))
+	s.Update(cfg)
+	return s
+}
+
+func initProducer(t *testing.T, s *store.Store, r *initProducerId.Request) *initProducerId.Response {
+	rr := kafkatest.NewRecorder()
+	s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 3, r))
+	res, ok := rr.Message.(*initProducerId.Response)
+	require.True(t, ok)
+	require.Equal(t, kafka.None, res.ErrorCode)
+	return res
+}
+
+func idempotentBatch(p *initProducerId.Response, sequence int32, value string) kafka.RecordBatch {
+	return kafka.RecordBatch{
+		Records: []*kafka.Record{
+			{
+				Key:            kafka.NewBytes([]byte("key")),
+				Value:          kafka.NewBytes([]byte(value)),
+				ProducerId:     p.ProducerId,
+				ProducerEpoch:  p.ProducerEpoch,
+				SequenceNumber: sequence,
+			},
+		},
+	}
+}
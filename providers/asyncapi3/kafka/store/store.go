@@ -4,20 +4,34 @@ import (
 	"fmt"
 	"mokapi/engine/common"
 	"mokapi/kafka"
+	"mokapi/kafka/addOffsetsToTxn"
+	"mokapi/kafka/addPartitionsToTxn"
+	"mokapi/kafka/alterConfigs"
 	"mokapi/kafka/apiVersion"
+	"mokapi/kafka/createPartitions"
 	"mokapi/kafka/createTopics"
+	"mokapi/kafka/deleteGroups"
+	"mokapi/kafka/deleteRecords"
+	"mokapi/kafka/deleteTopics"
+	"mokapi/kafka/describeConfigs"
+	"mokapi/kafka/describeGroups"
+	"mokapi/kafka/endTxn"
 	"mokapi/kafka/fetch"
 	"mokapi/kafka/findCoordinator"
 	"mokapi/kafka/heartbeat"
 	"mokapi/kafka/initProducerId"
 	"mokapi/kafka/joinGroup"
+	"mokapi/kafka/leaveGroup"
 	"mokapi/kafka/listgroup"
 	"mokapi/kafka/metaData"
 	"mokapi/kafka/offset"
 	"mokapi/kafka/offsetCommit"
 	"mokapi/kafka/offsetFetch"
 	"mokapi/kafka/produce"
+	"mokapi/kafka/saslAuthenticate"
+	"mokapi/kafka/saslHandshake"
 	"mokapi/kafka/syncGroup"
+	"mokapi/kafka/txnOffsetCommit"
 	"mokapi/providers/asyncapi3"
 	"mokapi/runtime/events"
 	"mokapi/runtime/monitor"
@@ -42,9 +56,16 @@ type Store struct {
 	producers    map[int64]*ProducerState
 	monitor      *monitor.Kafka
 	clients      map[string]*kafka.ClientContext
+	transactions map[string]*Transaction
 
 	nextPID int64
 	m       sync.RWMutex
+	txm     sync.Mutex
+
+	// directory of partitions, group offsets and producer states; empty if
+	// the data is kept in memory only
+	dataDir        string
+	restoredGroups map[string]map[string]map[int]int64
 }
 
 type ProducerState struct {
@@ -62,6 +83,7 @@ func NewEmpty(eventEmitter common.EventEmitter, eh events.Handler, monitor *moni
 		monitor:      monitor,
 		producers:    make(map[int64]*ProducerState),
 		clients:      make(map[string]*kafka.ClientContext),
+		transactions: make(map[string]*Transaction),
 	}
 }
 
@@ -75,6 +97,22 @@ func (s *Store) Close() {
 	for _, g := range s.groups {
 		g.balancer.Stop()
 	}
+	for _, b := range s.brokers {
+		b.stopCleaner()
+	}
+	for _, t := range s.topics {
+		for _, p := range t.Partitions {
+			p.m.Lock()
+			if p.files != nil {
+				// a running log cleaner must not write to the data directory
+				// which may already be used by a new store
+				p.saveState()
+				p.files.close()
+				p.files = nil
+			}
+			p.m.Unlock()
+		}
+	}
 }
 
 func (s *Store) Topic(name string) *Topic {
@@ -143,7 +181,7 @@ func (s *Store) Update(c *asyncapi3.Config) {
 		for it := c.Servers.Iter(); it.Next(); {
 			name := it.Key()
 			server := it.Value()
-			if server.Value.Protocol != "" && server.Value.Protocol != "kafka" {
+			if server.Value.Protocol != "" && !server.Value.IsKafka() {
 				continue
 			}
 			if b := s.getBroker(name); b != nil {
@@ -154,6 +192,7 @@ func (s *Store) Update(c *asyncapi3.Config) {
 				}
 				b.Host = host
 				b.Port = port
+				b.config = server.Value
 			} else {
 				s.addBroker(name, server.Value)
 			}
@@ -180,8 +219,8 @@ func (s *Store) Update(c *asyncapi3.Config) {
 			n = ch.Value.Address
 		}
 
-		if t, ok := s.topics[n]; ok {
-			t.update(ch.Value, s)
+		if t := s.Topic(n); t != nil {
+			s.updateTopic(t, ch.Value)
 		} else {
 			if _, err := s.addTopic(n, ch.Value, getOperations(ch.Value, c)); err != nil {
 				log.Errorf("unable to add topic '%v' to broker '%v': %v", n, s.cluster, err)
@@ -202,6 +241,7 @@ func (s *Store) Update(c *asyncapi3.Config) {
 			s.deleteTopic(name)
 		}
 	}
+	s.restoreGroups()
 }
 
 func (s *Store) ServeMessage(rw kafka.ResponseWriter, req *kafka.Request) {
@@ -222,11 +262,25 @@ func (s *Store) ServeMessage(rw kafka.ResponseWriter, req *kafka.Request) {
 		s.m.Unlock()
 	}
 
+	if !s.isAuthenticated(req) {
+		log.Errorf("kafka: unexpected request %v from client '%v' before SASL authentication", req.Header.ApiKey, req.Header.ClientId)
+		client.Disconnect()
+		return
+	}
+
 	switch req.Message.(type) {
 	case *produce.Request:
+		start := time.Now()
 		err = s.produce(rw, req)
+		if m, ok := monitor.KafkaFromContext(req.Context); ok {
+			m.ProduceDuration.WithLabel(s.cluster).Observe(time.Since(start).Seconds())
+		}
 	case *fetch.Request:
+		start := time.Now()
 		err = s.fetch(rw, req)
+		if m, ok := monitor.KafkaFromContext(req.Context); ok {
+			m.FetchDuration.WithLabel(s.cluster).Observe(time.Since(start).Seconds())
+		}
 	case *offset.Request:
 		err = s.offset(rw, req)
 	case *metaData.Request:
@@ -241,16 +295,44 @@ func (s *Store) ServeMessage(rw kafka.ResponseWriter, req *kafka.Request) {
 		err = s.joingroup(rw, req)
 	case *heartbeat.Request:
 		err = s.heartbeat(rw, req)
+	case *leaveGroup.Request:
+		err = s.leavegroup(rw, req)
 	case *syncGroup.Request:
 		err = s.syncgroup(rw, req)
+	case *describeGroups.Request:
+		err = s.describegroups(rw, req)
 	case *listgroup.Request:
 		err = s.listgroup(rw, req)
+	case *deleteGroups.Request:
+		err = s.deletegroups(rw, req)
 	case *apiVersion.Request:
 		err = s.apiversion(rw, req)
 	case *createTopics.Request:
 		err = s.createtopics(rw, req)
+	case *deleteTopics.Request:
+		err = s.deletetopics(rw, req)
+	case *createPartitions.Request:
+		err = s.createPartitions(rw, req)
+	case *deleteRecords.Request:
+		err = s.deleteRecords(rw, req)
+	case *describeConfigs.Request:
+		err = s.describeConfigs(rw, req)
+	case *alterConfigs.Request:
+		err = s.alterConfigs(rw, req)
 	case *initProducerId.Request:
 		err = s.initProducerID(rw, req)
+	case *addPartitionsToTxn.Request:
+		err = s.addPartitionsToTxn(rw, req)
+	case *addOffsetsToTxn.Request:
+		err = s.addOffsetsToTxn(rw, req)
+	case *endTxn.Request:
+		err = s.endTxn(rw, req)
+	case *txnOffsetCommit.Request:
+		err = s.txnOffsetCommit(rw, req)
+	case *saslHandshake.Request:
+		err = s.saslhandshake(rw, req)
+	case *saslAuthenticate.Request:
+		err = s.saslauthenticate(rw, req)
 	default:
 		err = fmt.Errorf("kafka: unsupported api key: %v", req.Header.ApiKey)
 	}
@@ -276,6 +358,13 @@ func (s *Store) addTopic(name string, channel *asyncapi3.Channel, ops []*asyncap
 	return t, nil
 }
 
+func (s *Store) updateTopic(t *Topic, channel *asyncapi3.Channel) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	t.update(channel, s)
+}
+
 func (s *Store) deleteTopic(name string) {
 	s.m.Lock()
 	defer s.m.Unlock()
@@ -339,7 +428,7 @@ func (s *Store) log(log *KafkaMessageLog, traits events.Traits) {
 	_ = s.eh.Push(log, t)
 }
 
-func (s *Store) trigger(record *kafka.Record, schemaId int) bool {
+func (s *Store) trigger(record *kafka.Record, schemaId int, ctx *EventContext) bool {
 	h := map[string]string{}
 	for _, v := range record.Headers {
 		h[v.Key] = string(v.Value)
@@ -358,7 +447,7 @@ func (s *Store) trigger(record *kafka.Record, schemaId int) bool {
 		r.Value = kafka.BytesToString(record.Value)
 	}
 
-	actions := s.eventEmitter.Emit("kafka", r)
+	actions := s.eventEmitter.Emit("kafka", r, ctx)
 	if len(actions) == 0 {
 		return false
 	}
@@ -442,12 +531,8 @@ func (s *Store) UpdateMetrics(m *monitor.Kafka, topic *Topic, partition *Partiti
 	m.LastMessage.WithLabel(s.cluster, topic.Name).Set(float64(time.Now().Unix()))
 
 	for name, g := range s.groups {
-		gt, ok := g.Commits[topic.Name]
-		if !ok {
-			continue
-		}
-		commit, ok := gt[partition.Index]
-		if !ok {
+		commit := g.Offset(topic.Name, partition.Index)
+		if commit < 0 {
 			continue
 		}
 		lag := float64(partition.Offset() - commit)
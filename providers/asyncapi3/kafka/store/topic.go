@@ -19,6 +19,13 @@ type Topic struct {
 	s          *Store
 	Config     *asyncapi3.Channel
 	operations []*asyncapi3.Operation
+
+	// bindings are the bindings of Config including the changes of clients
+	bindings asyncapi3.TopicBindings
+	// changes of clients by CreatePartitions and AlterConfigs which are kept
+	// when the config is reloaded
+	partitions int
+	configs    *asyncapi3.TopicBindings
 }
 
 type Operation struct {
@@ -27,12 +34,33 @@ type Operation struct {
 }
 
 func (t *Topic) Partition(index int) *Partition {
+	if t.s != nil {
+		t.s.m.RLock()
+		defer t.s.m.RUnlock()
+	}
+
 	if index >= len(t.Partitions) {
 		return nil
 	}
 	return t.Partitions[index]
 }
 
+// partitionList returns the current partitions. Partitions added later
+// are not part of the returned slice.
+func (t *Topic) partitionList() []*Partition {
+	if t.s != nil {
+		t.s.m.RLock()
+		defer t.s.m.RUnlock()
+	}
+	return t.Partitions
+}
+
+// Bindings returns the Kafka bindings of the topic including the changes
+// of clients, e.g. by CreatePartitions or AlterConfigs
+func (t *Topic) Bindings() asyncapi3.TopicBindings {
+	return t.bindings
+}
+
 func (t *Topic) delete() {
 	for _, p := range t.Partitions {
 		p.delete()
@@ -40,7 +68,7 @@ func (t *Topic) delete() {
 }
 
 func newTopic(name string, channel *asyncapi3.Channel, ops []*asyncapi3.Operation, s *Store) *Topic {
-	t := &Topic{Name: name, logger: s.log, s: s, Config: channel, operations: ops}
+	t := &Topic{Name: name, logger: s.log, s: s, Config: channel, operations: ops, bindings: channel.Bindings.Kafka}
 
 	numPartitions := channel.Bindings.Kafka.Partitions
 	for i := 0; i < numPartitions; i++ {
@@ -52,9 +80,17 @@ func newTopic(name string, channel *asyncapi3.Channel, ops []*asyncapi3.Operatio
 	return t
 }
 
+// update applies the config and the changes of clients. The caller must
+// hold the store lock.
 func (t *Topic) update(config *asyncapi3.Channel, s *Store) {
 	t.Config = config
-	numPartitions := config.Bindings.Kafka.Partitions
+	t.bindings = config.Bindings.Kafka
+	if t.configs != nil {
+		copyTopicConfigs(&t.bindings, *t.configs)
+	}
+	// partitions added by clients are never removed by a reload
+	t.bindings.Partitions = max(config.Bindings.Kafka.Partitions, t.partitions)
+	numPartitions := t.bindings.Partitions
 
 	for i, p := range t.Partitions {
 		if i >= numPartitions {
@@ -82,9 +118,10 @@ func (t *Topic) Store() *Store {
 }
 
 func (t *Topic) Write(record *kafka.Record) (partition int, recordError *produce.RecordError) {
+	partitions := t.partitionList()
 	r := rand.New(rand.NewSource(time.Now().Unix()))
-	index := r.Intn(len(t.Partitions))
-	wr, _ := t.Partitions[index].Write(kafka.RecordBatch{Records: []*kafka.Record{record}})
+	index := r.Intn(len(partitions))
+	wr, _ := partitions[index].Write(kafka.RecordBatch{Records: []*kafka.Record{record}})
 	if wr.Records == nil {
 		return index, nil
 	}
@@ -92,10 +129,11 @@ func (t *Topic) Write(record *kafka.Record) (partition int, recordError *produce
 }
 
 func (t *Topic) WritePartition(partition int, record *kafka.Record) (recordError *produce.RecordError, err error) {
-	if partition >= len(t.Partitions) {
+	p := t.Partition(partition)
+	if p == nil {
 		return nil, fmt.Errorf("partition out of range")
 	}
-	wr, _ := t.Partitions[partition].Write(kafka.RecordBatch{Records: []*kafka.Record{record}})
+	wr, _ := p.Write(kafka.RecordBatch{Records: []*kafka.Record{record}})
 	if wr.Records == nil {
 		return nil, nil
 	}
@@ -0,0 +1,208 @@
+package store
+
+import (
+	"fmt"
+	"mokapi/kafka"
+	"mokapi/kafka/describeConfigs"
+	"mokapi/providers/asyncapi3"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// topicConfig maps a Kafka topic config to its field of the topic bindings.
+// A zero value of the binding means the topic uses the broker config or the
+// Kafka default.
+type topicConfig struct {
+	name         string
+	configType   describeConfigs.ConfigType
+	defaultValue string
+	// name of the broker config used if the topic does not define a value
+	brokerName string
+	get        func(b *asyncapi3.TopicBindings) string
+	set        func(b *asyncapi3.TopicBindings, value string) error
+	broker     func(b asyncapi3.BrokerBindings) string
+}
+
+var topicConfigs = []topicConfig{
+	{
+		name:         "cleanup.policy",
+		configType:   describeConfigs.TypeList,
+		defaultValue: "delete",
+		get: func(b *asyncapi3.TopicBindings) string {
+			return strings.Join(b.CleanupPolicy, ",")
+		},
+		set: func(b *asyncapi3.TopicBindings, value string) error {
+			var policy []string
+			for _, p := range strings.Split(value, ",") {
+				p = strings.TrimSpace(p)
+				if p != "delete" && p != "compact" {
+					return fmt.Errorf("unsupported value '%v'", p)
+				}
+				if !slices.Contains(policy, p) {
+					policy = append(policy, p)
+				}
+			}
+			b.CleanupPolicy = policy
+			return nil
+		},
+	},
+	{
+		name:         "compression.type",
+		configType:   describeConfigs.TypeString,
+		defaultValue: "producer",
+		get: func(b *asyncapi3.TopicBindings) string {
+			return b.CompressionType
+		},
+		set: func(b *asyncapi3.TopicBindings, value string) error {
+			if value != "producer" {
+				if _, err := kafka.ParseCompression(value); err != nil {
+					return fmt.Errorf("unsupported value '%v'", value)
+				}
+			}
+			b.CompressionType = value
+			return nil
+		},
+	},
+	{
+		name:         "delete.retention.ms",
+		configType:   describeConfigs.TypeLong,
+		defaultValue: "86400000",
+		get:          int64Getter(func(b *asyncapi3.TopicBindings) int64 { return b.DeleteRetentionMs }),
+		set:          int64Setter(func(b *asyncapi3.TopicBindings, v int64) { b.DeleteRetentionMs = v }),
+	},
+	{
+		name:         "retention.bytes",
+		configType:   describeConfigs.TypeLong,
+		defaultValue: "-1",
+		brokerName:   "log.retention.bytes",
+		get:          int64Getter(func(b *asyncapi3.TopicBindings) int64 { return b.RetentionBytes }),
+		set:          int64Setter(func(b *asyncapi3.TopicBindings, v int64) { b.RetentionBytes = v }),
+		broker:       brokerGetter(func(b asyncapi3.BrokerBindings) int64 { return b.LogRetentionBytes }),
+	},
+	{
+		name:         "retention.ms",
+		configType:   describeConfigs.TypeLong,
+		defaultValue: "604800000",
+		brokerName:   "log.retention.ms",
+		get:          int64Getter(func(b *asyncapi3.TopicBindings) int64 { return b.RetentionMs }),
+		set:          int64Setter(func(b *asyncapi3.TopicBindings, v int64) { b.RetentionMs = v }),
+		broker:       brokerGetter(func(b asyncapi3.BrokerBindings) int64 { return b.LogRetentionMs }),
+	},
+	{
+		name:         "segment.bytes",
+		configType:   describeConfigs.TypeInt,
+		defaultValue: "1073741824",
+		brokerName:   "log.segment.bytes",
+		get:          int64Getter(func(b *asyncapi3.TopicBindings) int64 { return b.SegmentBytes }),
+		set:          int64Setter(func(b *asyncapi3.TopicBindings, v int64) { b.SegmentBytes = v }),
+		broker:       brokerGetter(func(b asyncapi3.BrokerBindings) int64 { return b.LogSegmentBytes }),
+	},
+	{
+		name:         "segment.ms",
+		configType:   describeConfigs.TypeLong,
+		defaultValue: "604800000",
+		brokerName:   "log.roll.ms",
+		get:          int64Getter(func(b *asyncapi3.TopicBindings) int64 { return b.SegmentMs }),
+		set:          int64Setter(func(b *asyncapi3.TopicBindings, v int64) { b.SegmentMs = v }),
+		broker:       brokerGetter(func(b asyncapi3.BrokerBindings) int64 { return b.LogRollMs }),
+	},
+}
+
+// brokerConfigs are the configs returned for a broker resource
+var brokerConfigs = []struct {
+	name         string
+	defaultValue string
+	get          func(b asyncapi3.BrokerBindings) int64
+}{
+	{"group.initial.rebalance.delay.ms", "3000", func(b asyncapi3.BrokerBindings) int64 { return b.GroupInitialRebalanceDelayMs }},
+	{"log.retention.bytes", "-1", func(b asyncapi3.BrokerBindings) int64 { return b.LogRetentionBytes }},
+	{"log.retention.check.interval.ms", "300000", func(b asyncapi3.BrokerBindings) int64 { return b.LogRetentionCheckIntervalMs }},
+	{"log.retention.ms", "604800000", func(b asyncapi3.BrokerBindings) int64 { return b.LogRetentionMs }},
+	{"log.roll.ms", "604800000", func(b asyncapi3.BrokerBindings) int64 { return b.LogRollMs }},
+	{"log.segment.bytes", "1073741824", func(b asyncapi3.BrokerBindings) int64 { return b.LogSegmentBytes }},
+}
+
+func resetTopicConfigs(b *asyncapi3.TopicBindings) {
+	b.CleanupPolicy = nil
+	b.CompressionType = ""
+	b.DeleteRetentionMs = 0
+	b.RetentionBytes = 0
+	b.RetentionMs = 0
+	b.SegmentBytes = 0
+	b.SegmentMs = 0
+}
+
+// copyTopicConfigs sets the configs of src, which clients can change by
+// AlterConfigs, to dst
+func copyTopicConfigs(dst *asyncapi3.TopicBindings, src asyncapi3.TopicBindings) {
+	dst.CleanupPolicy = src.CleanupPolicy
+	dst.CompressionType = src.CompressionType
+	dst.DeleteRetentionMs = src.DeleteRetentionMs
+	dst.RetentionBytes = src.RetentionBytes
+	dst.RetentionMs = src.RetentionMs
+	dst.SegmentBytes = src.SegmentBytes
+	dst.SegmentMs = src.SegmentMs
+}
+
+func getTopicConfig(name string) (topicConfig, bool) {
+	for _, c := range topicConfigs {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return topicConfig{}, false
+}
+
+// describe returns the effective value of the config for the given topic
+// bindings together with its synonyms ordered by precedence.
+func (c topicConfig) describe(b *asyncapi3.TopicBindings, broker asyncapi3.BrokerBindings) describeConfigs.Config {
+	var synonyms []describeConfigs.Synonym
+	if v := c.get(b); v != "" {
+		synonyms = append(synonyms, describeConfigs.Synonym{Name: c.name, Value: v, Source: describeConfigs.SourceDynamicTopicConfig})
+	}
+	if c.broker != nil {
+		if v := c.broker(broker); v != "" {
+			synonyms = append(synonyms, describeConfigs.Synonym{Name: c.brokerName, Value: v, Source: describeConfigs.SourceStaticBrokerConfig})
+		}
+	}
+	synonyms = append(synonyms, describeConfigs.Synonym{Name: c.name, Value: c.defaultValue, Source: describeConfigs.SourceDefaultConfig})
+
+	return describeConfigs.Config{
+		Name:         c.name,
+		Value:        synonyms[0].Value,
+		IsDefault:    synonyms[0].Source == describeConfigs.SourceDefaultConfig,
+		ConfigSource: synonyms[0].Source,
+		Synonyms:     synonyms,
+		ConfigType:   c.configType,
+	}
+}
+
+func int64Getter(get func(b *asyncapi3.TopicBindings) int64) func(b *asyncapi3.TopicBindings) string {
+	return func(b *asyncapi3.TopicBindings) string {
+		if v := get(b); v != 0 {
+			return strconv.FormatInt(v, 10)
+		}
+		return ""
+	}
+}
+
+func int64Setter(set func(b *asyncapi3.TopicBindings, v int64)) func(b *asyncapi3.TopicBindings, value string) error {
+	return func(b *asyncapi3.TopicBindings, value string) error {
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("value '%v' is not a number", value)
+		}
+		set(b, v)
+		return nil
+	}
+}
+
+func brokerGetter(get func(b asyncapi3.BrokerBindings) int64) func(b asyncapi3.BrokerBindings) string {
+	return func(b asyncapi3.BrokerBindings) string {
+		if v := get(b); v != 0 {
+			return strconv.FormatInt(v, 10)
+		}
+		return ""
+	}
+}
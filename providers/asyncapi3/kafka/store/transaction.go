@@ -0,0 +1,214 @@
+package store
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/initProducerId"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxTransactionTimeoutMs is the default of the broker setting transaction.max.timeout.ms
+const maxTransactionTimeoutMs = 900000
+
+type TransactionState int
+
+const (
+	TransactionEmpty TransactionState = iota
+	TransactionOngoing
+	TransactionCompleteCommit
+	TransactionCompleteAbort
+)
+
+var transactionStateText = map[TransactionState]string{
+	TransactionEmpty:          "Empty",
+	TransactionOngoing:        "Ongoing",
+	TransactionCompleteCommit: "CompleteCommit",
+	TransactionCompleteAbort:  "CompleteAbort",
+}
+
+func (s TransactionState) String() string {
+	return transactionStateText[s]
+}
+
+type Transaction struct {
+	TransactionalId string
+	ProducerId      int64
+	ProducerEpoch   int16
+	TimeoutMs       int32
+	State           TransactionState
+
+	partitions []*Partition
+	groups     []string
+	// pending offsets by group, topic and partition committed with the transaction
+	offsets map[string]map[string]map[int]int64
+	timer   *time.Timer
+}
+
+// initTransaction assigns a producer id to a transactional producer. An existing
+// transactional id keeps its producer id but gets a new epoch which fences older
+// producer instances. An ongoing transaction of the previous instance is aborted.
+func (s *Store) initTransaction(r *initProducerId.Request, res *initProducerId.Response) {
+	if r.TransactionTimeoutMs <= 0 || r.TransactionTimeoutMs > maxTransactionTimeoutMs {
+		res.ErrorCode = kafka.InvalidTransactionTimeout
+		return
+	}
+
+	s.txm.Lock()
+	defer s.txm.Unlock()
+
+	txn, ok := s.transactions[r.TransactionalId]
+	if !ok {
+		txn = &Transaction{
+			TransactionalId: r.TransactionalId,
+			ProducerId:      atomic.AddInt64(&s.nextPID, 1),
+		}
+		s.transactions[r.TransactionalId] = txn
+	} else {
+		if r.ProducerId > 0 && (r.ProducerId != txn.ProducerId || r.ProducerEpoch != txn.ProducerEpoch) {
+			res.ErrorCode = kafka.ProducerFenced
+			return
+		}
+		if txn.State == TransactionOngoing {
+			log.Infof("kafka: aborting ongoing transaction '%v' of previous producer instance", txn.TransactionalId)
+			s.completeTransaction(txn, false)
+		}
+		txn.ProducerEpoch++
+	}
+
+	txn.TimeoutMs = r.TransactionTimeoutMs
+	txn.State = TransactionEmpty
+	s.producers[txn.ProducerId] = &ProducerState{ProducerId: txn.ProducerId, ProducerEpoch: txn.ProducerEpoch}
+
+	res.ProducerId = txn.ProducerId
+	res.ProducerEpoch = txn.ProducerEpoch
+}
+
+func (s *Store) Transaction(transactionalId string) (*Transaction, bool) {
+	s.txm.Lock()
+	defer s.txm.Unlock()
+
+	txn, ok := s.transactions[transactionalId]
+	return txn, ok
+}
+
+// transaction returns the transaction if the given producer is its current
+// instance. The caller must hold the transaction lock.
+func (s *Store) transaction(transactionalId string, producerId int64, epoch int16) (*Transaction, kafka.ErrorCode) {
+	txn, ok := s.transactions[transactionalId]
+	if !ok || txn.ProducerId != producerId {
+		return nil, kafka.InvalidProducerIdMapping
+	}
+	if epoch < txn.ProducerEpoch {
+		return nil, kafka.ProducerFenced
+	}
+	if epoch > txn.ProducerEpoch {
+		return nil, kafka.InvalidProducerEpoch
+	}
+	return txn, kafka.None
+}
+
+// isInTransaction reports whether the producer may write transactional records
+// to the given partition.
+func (s *Store) isInTransaction(producerId int64, epoch int16, p *Partition) kafka.ErrorCode {
+	s.txm.Lock()
+	defer s.txm.Unlock()
+
+	for _, txn := range s.transactions {
+		if txn.ProducerId != producerId {
+			continue
+		}
+		if epoch < txn.ProducerEpoch {
+			return kafka.ProducerFenced
+		}
+		if txn.State != TransactionOngoing || !slices.Contains(txn.partitions, p) {
+			return kafka.InvalidTxnState
+		}
+		return kafka.None
+	}
+	return kafka.InvalidProducerIdMapping
+}
+
+// begin marks the transaction as ongoing and starts its timeout. The caller
+// must hold the transaction lock.
+func (s *Store) begin(txn *Transaction) {
+	if txn.State == TransactionOngoing {
+		return
+	}
+	txn.State = TransactionOngoing
+	epoch := txn.ProducerEpoch
+	txn.timer = time.AfterFunc(time.Duration(txn.TimeoutMs)*time.Millisecond, func() {
+		s.txm.Lock()
+		defer s.txm.Unlock()
+
+		if txn.State != TransactionOngoing || txn.ProducerEpoch != epoch {
+			return
+		}
+		log.Infof("kafka: transaction '%v' timed out after %vms and is aborted", txn.TransactionalId, txn.TimeoutMs)
+		s.completeTransaction(txn, false)
+		// fence the producer so that it cannot continue the aborted transaction
+		txn.ProducerEpoch++
+		if ps, ok := s.producers[txn.ProducerId]; ok {
+			ps.ProducerEpoch = txn.ProducerEpoch
+		}
+		s.saveProducers()
+	})
+}
+
+// completeTransaction writes the transaction markers to all partitions of the
+// transaction and applies the pending offsets on commit. The caller must hold
+// the transaction lock.
+func (s *Store) completeTransaction(txn *Transaction, commit bool) {
+	if txn.timer != nil {
+		txn.timer.Stop()
+		txn.timer = nil
+	}
+
+	for _, p := range txn.partitions {
+		p.writeMarker(txn.ProducerId, txn.ProducerEpoch, commit)
+	}
+
+	if commit {
+		for groupId, topics := range txn.offsets {
+			g, ok := s.Group(groupId)
+			if !ok {
+				log.Errorf("kafka: unable to commit offsets of transaction '%v': unknown group %v", txn.TransactionalId, groupId)
+				continue
+			}
+			for topic, partitions := range topics {
+				for partition, offset := range partitions {
+					g.Commit(topic, partition, offset)
+				}
+			}
+		}
+		if len(txn.offsets) > 0 {
+			s.saveGroups()
+		}
+		txn.State = TransactionCompleteCommit
+	} else {
+		txn.State = TransactionCompleteAbort
+	}
+
+	txn.partitions = nil
+	txn.groups = nil
+	txn.offsets = nil
+}
+
+func (txn *Transaction) addOffset(group, topic string, partition int, offset int64) {
+	if txn.offsets == nil {
+		txn.offsets = make(map[string]map[string]map[int]int64)
+	}
+	topics, ok := txn.offsets[group]
+	if !ok {
+		topics = make(map[string]map[int]int64)
+		txn.offsets[group] = topics
+	}
+	partitions, ok := topics[topic]
+	if !ok {
+		partitions = make(map[int]int64)
+		topics[topic] = partitions
+	}
+	partitions[partition] = offset
+}
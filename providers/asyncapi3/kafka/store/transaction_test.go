@@ -0,0 +1,371 @@
+package store_test
+
+import (
+	"mokapi/engine/enginetest"
+	"mokapi/kafka"
+	"mokapi/kafka/addOffsetsToTxn"
+	"mokapi/kafka/addPartitionsToTxn"
+	"mokapi/kafka/endTxn"
+	"mokapi/kafka/fetch"
+	"mokapi/kafka/findCoordinator"
+	"mokapi/kafka/initProducerId"
+	"mokapi/kafka/kafkatest"
+	"mokapi/kafka/produce"
+	"mokapi/kafka/txnOffsetCommit"
+	"mokapi/media"
+	"mokapi/providers/asyncapi3/asyncapi3test"
+	"mokapi/providers/asyncapi3/kafka/store"
+	"mokapi/runtime/events"
+	"mokapi/runtime/monitor"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction(t *testing.T) {
+	testcases := []struct {
+		name string
+		test func(t *testing.T, s *store.Store)
+	}{
+		{
+			name: "init transactional producer",
+			test: func(t *testing.T, s *store.Store) {
+				res := initTransactional(t, s, "trx")
+				require.Equal(t, kafka.None, res.ErrorCode)
+				require.Greater(t, res.ProducerId, int64(0))
+				require.Equal(t, int16(0), res.ProducerEpoch)
+
+				// a new instance keeps the producer id and gets a new epoch
+				res2 := initTransactional(t, s, "trx")
+				require.Equal(t, kafka.None, res2.ErrorCode)
+				require.Equal(t, res.ProducerId, res2.ProducerId)
+				require.Equal(t, int16(1), res2.ProducerEpoch)
+			},
+		},
+		{
+			name: "invalid transaction timeout",
+			test: func(t *testing.T, s *store.Store) {
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 4, &initProducerId.Request{
+					TransactionalId:      "trx",
+					TransactionTimeoutMs: 0,
+				}))
+				res := rr.Message.(*initProducerId.Response)
+				require.Equal(t, kafka.InvalidTransactionTimeout, res.ErrorCode)
+			},
+		},
+		{
+			name: "find transaction coordinator",
+			test: func(t *testing.T, s *store.Store) {
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 3, &findCoordinator.Request{
+					Key:     "trx",
+					KeyType: findCoordinator.KeyTypeTransaction,
+				}))
+				res := rr.Message.(*findCoordinator.Response)
+				require.Equal(t, kafka.None, res.ErrorCode)
+				require.Equal(t, "127.0.0.1", res.Host)
+				require.Equal(t, int32(9092), res.Port)
+			},
+		},
+		{
+			name: "produce without adding partition",
+			test: func(t *testing.T, s *store.Store) {
+				p := initTransactional(t, s, "trx")
+				res := produceTransactional(t, s, p, 0, "foo")
+				require.Equal(t, kafka.InvalidTxnState, res.Topics[0].Partitions[0].ErrorCode)
+			},
+		},
+		{
+			name: "add unknown partition",
+			test: func(t *testing.T, s *store.Store) {
+				p := initTransactional(t, s, "trx")
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 3, &addPartitionsToTxn.Request{
+					TransactionalId: "trx",
+					ProducerId:      p.ProducerId,
+					ProducerEpoch:   p.ProducerEpoch,
+					Topics: []addPartitionsToTxn.Topic{
+						{Name: "foo", Partitions: []int32{0, 5}},
+					},
+				}))
+				res := rr.Message.(*addPartitionsToTxn.Response)
+				require.Equal(t, kafka.OperationNotAttempted, res.Results[0].Results[0].ErrorCode)
+				require.Equal(t, kafka.UnknownTopicOrPartition, res.Results[0].Results[1].ErrorCode)
+
+				txn, ok := s.Transaction("trx")
+				require.True(t, ok)
+				require.Equal(t, store.TransactionEmpty, txn.State)
+			},
+		},
+		{
+			name: "commit transaction",
+			test: func(t *testing.T, s *store.Store) {
+				p := initTransactional(t, s, "trx")
+				require.Equal(t, kafka.None, addPartition(t, s, "trx", p))
+				res := produceTransactional(t, s, p, 0, "foo")
+				require.Equal(t, kafka.None, res.Topics[0].Partitions[0].ErrorCode)
+
+				// records of an ongoing transaction are not visible to read_committed consumers
+				fp := fetchRecords(t, s, 1, 0)
+				require.Equal(t, int64(1), fp.HighWatermark)
+				require.Equal(t, int64(0), fp.LastStableOffset)
+				require.Len(t, fp.RecordSet.Records, 0)
+
+				fp = fetchRecords(t, s, 0, 0)
+				require.Len(t, fp.RecordSet.Records, 1)
+				require.True(t, fp.RecordSet.Transactional)
+
+				require.Equal(t, kafka.None, endTransaction(t, s, "trx", p, true))
+
+				fp = fetchRecords(t, s, 1, 0)
+				require.Equal(t, int64(2), fp.HighWatermark)
+				require.Equal(t, int64(2), fp.LastStableOffset)
+				require.Len(t, fp.AbortedTransactions, 0)
+				require.Len(t, fp.RecordSet.Records, 1)
+				require.True(t, fp.RecordSet.Transactional)
+				require.False(t, fp.RecordSet.Control)
+				require.Equal(t, p.ProducerId, fp.RecordSet.Records[0].ProducerId)
+
+				// commit marker
+				fp = fetchRecords(t, s, 1, 1)
+				require.Len(t, fp.RecordSet.Records, 1)
+				require.True(t, fp.RecordSet.Control)
+
+				// retry of end transaction
+				require.Equal(t, kafka.None, endTransaction(t, s, "trx", p, true))
+				require.Equal(t, kafka.InvalidTxnState, endTransaction(t, s, "trx", p, false))
+			},
+		},
+		{
+			name: "abort transaction",
+			test: func(t *testing.T, s *store.Store) {
+				p := initTransactional(t, s, "trx")
+				require.Equal(t, kafka.None, addPartition(t, s, "trx", p))
+				res := produceTransactional(t, s, p, 0, "foo")
+				require.Equal(t, kafka.None, res.Topics[0].Partitions[0].ErrorCode)
+
+				require.Equal(t, kafka.None, endTransaction(t, s, "trx", p, false))
+
+				fp := fetchRecords(t, s, 1, 0)
+				require.Equal(t, int64(2), fp.LastStableOffset)
+				require.Len(t, fp.RecordSet.Records, 1)
+				require.Equal(t, []fetch.AbortedTransaction{{ProducerId: p.ProducerId, FirstOffset: 0}}, fp.AbortedTransactions)
+
+				// abort marker is not returned by the client API
+				ct := media.ParseContentType("text/plain")
+				records, err := store.NewClient(s, monitor.NewKafka()).Read("foo", 0, 0, &ct)
+				require.NoError(t, err)
+				require.Len(t, records, 1)
+			},
+		},
+		{
+			name: "new instance fences producer and aborts ongoing transaction",
+			test: func(t *testing.T, s *store.Store) {
+				p := initTransactional(t, s, "trx")
+				require.Equal(t, kafka.None, addPartition(t, s, "trx", p))
+				res := produceTransactional(t, s, p, 0, "foo")
+				require.Equal(t, kafka.None, res.Topics[0].Partitions[0].ErrorCode)
+
+				_ = initTransactional(t, s, "trx")
+
+				res = produceTransactional(t, s, p, 1, "bar")
+				require.Equal(t, kafka.ProducerFenced, res.Topics[0].Partitions[0].ErrorCode)
+				require.Equal(t, kafka.ProducerFenced, endTransaction(t, s, "trx", p, true))
+
+				fp := fetchRecords(t, s, 1, 0)
+				require.Equal(t, int64(2), fp.LastStableOffset)
+				require.Len(t, fp.AbortedTransactions, 1)
+			},
+		},
+		{
+			name: "transaction timeout",
+			test: func(t *testing.T, s *store.Store) {
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 4, &initProducerId.Request{
+					TransactionalId:      "trx",
+					TransactionTimeoutMs: 50,
+					ProducerId:           -1,
+					ProducerEpoch:        -1,
+				}))
+				p := rr.Message.(*initProducerId.Response)
+				require.Equal(t, kafka.None, addPartition(t, s, "trx", p))
+				res := produceTransactional(t, s, p, 0, "foo")
+				require.Equal(t, kafka.None, res.Topics[0].Partitions[0].ErrorCode)
+
+				require.Eventually(t, func() bool {
+					return fetchRecords(t, s, 1, 0).LastStableOffset == 2
+				}, time.Second, 10*time.Millisecond)
+				require.Equal(t, kafka.ProducerFenced, endTransaction(t, s, "trx", p, true))
+			},
+		},
+		{
+			name: "offsets are committed with transaction",
+			test: func(t *testing.T, s *store.Store) {
+				g := s.GetOrCreateGroup("g1", &store.Broker{})
+				p := initTransactional(t, s, "trx")
+
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 3, &addOffsetsToTxn.Request{
+					TransactionalId: "trx",
+					ProducerId:      p.ProducerId,
+					ProducerEpoch:   p.ProducerEpoch,
+					GroupId:         "g1",
+				}))
+				require.Equal(t, kafka.None, rr.Message.(*addOffsetsToTxn.Response).ErrorCode)
+
+				require.Equal(t, kafka.None, addPartition(t, s, "trx", p))
+				res := produceTransactional(t, s, p, 0, "foo")
+				require.Equal(t, kafka.None, res.Topics[0].Partitions[0].ErrorCode)
+
+				rr = kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 3, &txnOffsetCommit.Request{
+					TransactionalId: "trx",
+					GroupId:         "g1",
+					ProducerId:      p.ProducerId,
+					ProducerEpoch:   p.ProducerEpoch,
+					Topics: []txnOffsetCommit.Topic{
+						{Name: "foo", Partitions: []txnOffsetCommit.Partition{{Index: 0, CommittedOffset: 1}}},
+					},
+				}))
+				commitRes := rr.Message.(*txnOffsetCommit.Response)
+				require.Equal(t, kafka.None, commitRes.Topics[0].Partitions[0].ErrorCode)
+				require.Equal(t, int64(-1), g.Offset("foo", 0))
+
+				require.Equal(t, kafka.None, endTransaction(t, s, "trx", p, true))
+				require.Equal(t, int64(1), g.Offset("foo", 0))
+			},
+		},
+		{
+			name: "txn offset commit requires group in transaction",
+			test: func(t *testing.T, s *store.Store) {
+				s.GetOrCreateGroup("g1", &store.Broker{})
+				p := initTransactional(t, s, "trx")
+
+				rr := kafkatest.NewRecorder()
+				s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 3, &txnOffsetCommit.Request{
+					TransactionalId: "trx",
+					GroupId:         "g1",
+					ProducerId:      p.ProducerId,
+					ProducerEpoch:   p.ProducerEpoch,
+					Topics: []txnOffsetCommit.Topic{
+						{Name: "foo", Partitions: []txnOffsetCommit.Partition{{Index: 0, CommittedOffset: 0}}},
+					},
+				}))
+				res := rr.Message.(*txnOffsetCommit.Response)
+				require.Equal(t, kafka.InvalidTxnState, res.Topics[0].Partitions[0].ErrorCode)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := store.New(asyncapi3test.NewConfig(
+				asyncapi3test.WithServer("foo", "kafka", "127.0.0.1"),
+				asyncapi3test.WithChannel("foo"),
+			), enginetest.NewEngine(), &events.StoreManager{}, monitor.NewKafka())
+			defer s.Close()
+
+			tc.test(t, s)
+		})
+	}
+}
+
+func initTransactional(t *testing.T, s *store.Store, transactionalId string) *initProducerId.Response {
+	rr := kafkatest.NewRecorder()
+	s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 4, &initProducerId.Request{
+		TransactionalId:      transactionalId,
+		TransactionTimeoutMs: 60000,
+		ProducerId:           -1,
+		ProducerEpoch:        -1,
+	}))
+	res, ok := rr.Message.(*initProducerId.Response)
+	require.True(t, ok)
+	return res
+}
+
+func addPartition(t *testing.T, s *store.Store, transactionalId string, p *initProducerId.Response) kafka.ErrorCode {
+	rr := kafkatest.NewRecorder()
+	s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 3, &addPartitionsToTxn.Request{
+		TransactionalId: transactionalId,
+		ProducerId:      p.ProducerId,
+		ProducerEpoch:   p.ProducerEpoch,
+		Topics: []addPartitionsToTxn.Topic{
+			{Name: "foo", Partitions: []int32{0}},
+		},
+	}))
+	res, ok := rr.Message.(*addPartitionsToTxn.Response)
+	require.True(t, ok)
+	return res.Results[0].Results[0].ErrorCode
+}
+
+func produceTransactional(t *testing.T, s *store.Store, p *initProducerId.Response, sequence int32, value string) *produce.Response {
+	return produceTransactionalKeyValue(t, s, p, sequence, "", value)
+}
+
+func produceTransactionalKeyValue(t *testing.T, s *store.Store, p *initProducerId.Response, sequence int32, key, value string) *produce.Response {
+	var k kafka.Bytes
+	if key != "" {
+		k = kafka.NewBytes([]byte(key))
+	}
+	rr := kafkatest.NewRecorder()
+	s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 3, &produce.Request{
+		TransactionalId: "trx",
+		Topics: []produce.RequestTopic{
+			{Name: "foo", Partitions: []produce.RequestPartition{
+				{
+					Record: kafka.RecordBatch{
+						Transactional: true,
+						Records: []*kafka.Record{
+							{
+								Time:           time.Now(),
+								Key:            k,
+								Value:          kafka.NewBytes([]byte(value)),
+								ProducerId:     p.ProducerId,
+								ProducerEpoch:  p.ProducerEpoch,
+								SequenceNumber: sequence,
+							},
+						},
+					},
+				},
+			}},
+		},
+	}))
+	res, ok := rr.Message.(*produce.Response)
+	require.True(t, ok)
+	return res
+}
+
+func endTransaction(t *testing.T, s *store.Store, transactionalId string, p *initProducerId.Response, commit bool) kafka.ErrorCode {
+	rr := kafkatest.NewRecorder()
+	s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 3, &endTxn.Request{
+		TransactionalId: transactionalId,
+		ProducerId:      p.ProducerId,
+		ProducerEpoch:   p.ProducerEpoch,
+		Committed:       commit,
+	}))
+	res, ok := rr.Message.(*endTxn.Response)
+	require.True(t, ok)
+	return res.ErrorCode
+}
+
+func fetchRecords(t *testing.T, s *store.Store, isolationLevel int8, offset int64) fetch.ResponsePartition {
+	rr := kafkatest.NewRecorder()
+	s.ServeMessage(rr, kafkatest.NewRequest("kafkatest", 11, &fetch.Request{
+		MaxBytes:       1000,
+		IsolationLevel: isolationLevel,
+		Topics: []fetch.Topic{
+			{
+				Name: "foo",
+				Partitions: []fetch.RequestPartition{{
+					FetchOffset: offset,
+					MaxBytes:    1000,
+				}},
+			},
+		},
+	}))
+	res, ok := rr.Message.(*fetch.Response)
+	require.True(t, ok)
+	return res.Topics[0].Partitions[0]
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"mokapi/kafka"
+	"mokapi/kafka/txnOffsetCommit"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// txnOffsetCommit stores the offsets as pending until the transaction is committed
+func (s *Store) txnOffsetCommit(rw kafka.ResponseWriter, req *kafka.Request) error {
+	r := req.Message.(*txnOffsetCommit.Request)
+	res := &txnOffsetCommit.Response{
+		Topics: make([]txnOffsetCommit.ResponseTopic, 0, len(r.Topics)),
+	}
+
+	s.txm.Lock()
+	defer s.txm.Unlock()
+
+	txn, errCode := s.transaction(r.TransactionalId, r.ProducerId, r.ProducerEpoch)
+	if errCode == kafka.None && (txn.State != TransactionOngoing || !slices.Contains(txn.groups, r.GroupId)) {
+		errCode = kafka.InvalidTxnState
+	}
+	if errCode == kafka.None {
+		if _, ok := s.Group(r.GroupId); !ok {
+			errCode = kafka.GroupIdNotFound
+		}
+	}
+	if errCode != kafka.None {
+		log.Errorf("kafka TxnOffsetCommit: transaction '%v', group %v: %v", r.TransactionalId, r.GroupId, errCode)
+	}
+
+	for _, rt := range r.Topics {
+		resTopic := txnOffsetCommit.ResponseTopic{
+			Name:       rt.Name,
+			Partitions: make([]txnOffsetCommit.ResponsePartition, 0, len(rt.Partitions)),
+		}
+		topic := s.Topic(rt.Name)
+		for _, rp := range rt.Partitions {
+			resPartition := txnOffsetCommit.ResponsePartition{Index: rp.Index, ErrorCode: errCode}
+			if errCode == kafka.None {
+				var p *Partition
+				if topic != nil {
+					p = topic.Partition(int(rp.Index))
+				}
+				if p == nil {
+					log.Errorf("kafka TxnOffsetCommit: unknown topic %v or partition %v", rt.Name, rp.Index)
+					resPartition.ErrorCode = kafka.UnknownTopicOrPartition
+				} else if rp.CommittedOffset > p.Offset() {
+					resPartition.ErrorCode = kafka.OffsetOutOfRange
+				} else {
+					txn.addOffset(r.GroupId, topic.Name, p.Index, rp.CommittedOffset)
+				}
+			}
+			resTopic.Partitions = append(resTopic.Partitions, resPartition)
+		}
+		res.Topics = append(res.Topics, resTopic)
+	}
+
+	return rw.Write(res)
+}
@@ -12,6 +12,7 @@ import (
 	"mokapi/schema/encoding"
 	"mokapi/schema/json/parser"
 	"mokapi/schema/json/schema"
+	protobuf "mokapi/schema/protobuf/schema"
 	"slices"
 	"strconv"
 	"unicode/utf8"
@@ -73,9 +74,11 @@ func newMessageValidator(messageId string, msg *asyncapi3.Message, channel *asyn
 			log.Errorf("unsupported payload type: %T", msg.Payload.Value)
 		}
 		if msgParser != nil {
+			_, isProtobuf := msgParser.(*protobuf.Parser)
 			v.payload = &schemaValidator{
 				parser:      msgParser,
 				contentType: msg.ContentType,
+				logAsJson:   isProtobuf,
 			}
 		}
 	}
@@ -87,6 +90,8 @@ func newMessageValidator(messageId string, msg *asyncapi3.Message, channel *asyn
 			keyParser = &parser.Parser{Schema: s}
 		case *asyncapi3.AvroRef:
 			keyParser = &avro.Parser{Schema: s.Schema}
+		case *protobuf.Message:
+			keyParser = &protobuf.Parser{Message: s}
 		default:
 			log.Errorf("unsupported key type: %T", msg.Bindings.Kafka.Key.Value)
 		}
@@ -141,12 +146,17 @@ func (mv *messageValidator) Validate(record *kafka.Record) (*KafkaMessageLog, er
 	}
 
 	if mv.payload != nil {
-		if _, err := mv.payload.Validate(record.Value); err != nil {
+		if v, err := mv.payload.Validate(record.Value); err != nil {
 			return r, err
 		} else {
 			b := kafka.Read(record.Value)
 			r.Message.Value = string(b)
 			r.Message.Binary = b
+			if mv.payload.logAsJson {
+				// show decoded binary data like protobuf as JSON in the dashboard
+				j, _ := json.Marshal(v)
+				r.Message.Value = string(j)
+			}
 		}
 	} else {
 		r.Message.Binary = kafka.Read(record.Value)
@@ -181,6 +191,7 @@ func (mv *messageValidator) Validate(record *kafka.Record) (*KafkaMessageLog, er
 type schemaValidator struct {
 	parser      encoding.Parser
 	contentType string
+	logAsJson   bool
 }
 
 func (v *schemaValidator) Validate(data io.Reader) (interface{}, error) {
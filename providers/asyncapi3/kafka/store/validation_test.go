@@ -1,6 +1,7 @@
 package store_test
 
 import (
+	"mokapi/config/dynamic"
 	"mokapi/engine/enginetest"
 	"mokapi/kafka"
 	"mokapi/providers/asyncapi3"
@@ -11,6 +12,7 @@ import (
 	"mokapi/runtime/events"
 	"mokapi/runtime/monitor"
 	"mokapi/schema/json/schema/schematest"
+	protobuf "mokapi/schema/protobuf/schema"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -288,6 +290,56 @@ func TestValidation(t *testing.T) {
 				require.Equal(t, "Validation error count 1:\n\t- #/required: required properties are missing: bar", wr.Records[0].BatchIndexErrorMessage)
 			},
 		},
+		{
+			name: "protobuf value is logged as JSON",
+			cfg: asyncapi3test.NewConfig(
+				asyncapi3test.WithChannel("foo",
+					asyncapi3test.WithMessage("foo",
+						asyncapi3test.WithPayloadMulti("application/vnd.google.protobuf", newProtoMessage(t, "Foo")),
+						asyncapi3test.WithContentType("application/x-protobuf"),
+					),
+				),
+			),
+			test: func(t *testing.T, s *store.Store, sm *events.StoreManager) {
+				p := s.Topic("foo").Partition(0)
+				wr, err := p.Write(kafka.RecordBatch{
+					Records: []*kafka.Record{
+						{
+							Value: kafka.NewBytes([]byte{0x0a, 0x03, 0x62, 0x61, 0x72}),
+						},
+					},
+				})
+				require.NoError(t, err)
+				require.Len(t, wr.Records, 0)
+				e := sm.GetEvents(events.NewTraits())
+				require.Len(t, e, 1)
+				require.Equal(t, store.LogValue{Value: `{"name":"bar"}`, Binary: []byte{0x0a, 0x03, 0x62, 0x61, 0x72}}, e[0].Data.(*store.KafkaMessageLog).Message)
+			},
+		},
+		{
+			name: "protobuf value invalid",
+			cfg: asyncapi3test.NewConfig(
+				asyncapi3test.WithChannel("foo",
+					asyncapi3test.WithMessage("foo",
+						asyncapi3test.WithPayloadMulti("application/vnd.google.protobuf", newProtoMessage(t, "Foo")),
+						asyncapi3test.WithContentType("application/x-protobuf"),
+					),
+				),
+			),
+			test: func(t *testing.T, s *store.Store, sm *events.StoreManager) {
+				p := s.Topic("foo").Partition(0)
+				wr, err := p.Write(kafka.RecordBatch{
+					Records: []*kafka.Record{
+						{
+							Value: kafka.NewBytes([]byte{0x12, 0x05, 0x01}),
+						},
+					},
+				})
+				require.NoError(t, err)
+				require.Len(t, wr.Records, 1)
+				require.Equal(t, "message 'Foo': field number 2: unexpected end of data", wr.Records[0].BatchIndexErrorMessage)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -424,3 +476,10 @@ func TestValidation_Header(t *testing.T) {
 		})
 	}
 }
+
+func newProtoMessage(t *testing.T, name string) *protobuf.Message {
+	f, err := protobuf.Parse([]byte(`syntax = "proto3"; message Foo { string name = 1; }`))
+	require.NoError(t, err)
+	require.NoError(t, f.Parse(&dynamic.Config{}, nil))
+	return f.Message(name)
+}
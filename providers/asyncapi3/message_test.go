@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"mokapi/config/dynamic"
 	"mokapi/config/dynamic/dynamictest"
+	"mokapi/media"
 	"mokapi/providers/asyncapi3"
 	jsonSchema "mokapi/schema/json/schema"
+	protobuf "mokapi/schema/protobuf/schema"
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -157,3 +160,104 @@ func TestMessage_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestMessage_Protobuf(t *testing.T) {
+	testcases := []struct {
+		name   string
+		config string
+		test   func(t *testing.T, msg *asyncapi3.Message, err error)
+	}{
+		{
+			name: "reference to message",
+			config: `
+asyncapi: 3.0.0
+channels:
+  orders:
+    messages:
+      order:
+        contentType: application/x-protobuf
+        payload:
+          schemaFormat: application/vnd.google.protobuf
+          schema:
+            $ref: ./order.proto#/Order
+`,
+			test: func(t *testing.T, msg *asyncapi3.Message, err error) {
+				require.NoError(t, err)
+				m := msg.Payload.Value.(*asyncapi3.MultiSchemaFormat).Schema.Value.(*protobuf.Message)
+				require.Equal(t, "shop.Order", m.FullName)
+
+				b, err := msg.Payload.Marshal(map[string]any{"id": "a"}, media.ParseContentType("application/x-protobuf"))
+				require.NoError(t, err)
+				require.Equal(t, []byte{0x0a, 0x01, 0x61}, b)
+			},
+		},
+		{
+			name: "payload reference without schema format",
+			config: `
+asyncapi: 3.0.0
+channels:
+  orders:
+    messages:
+      order:
+        contentType: application/x-protobuf
+        payload:
+          $ref: ./order.proto#/Order
+`,
+			test: func(t *testing.T, msg *asyncapi3.Message, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "shop.Order", msg.Payload.Value.(*protobuf.Message).FullName)
+			},
+		},
+		{
+			name: "message not found",
+			config: `
+asyncapi: 3.0.0
+channels:
+  orders:
+    messages:
+      order:
+        payload:
+          schemaFormat: application/vnd.google.protobuf
+          schema:
+            $ref: ./order.proto#/Item
+`,
+			test: func(t *testing.T, msg *asyncapi3.Message, err error) {
+				require.ErrorContains(t, err, "message 'Item' not found")
+			},
+		},
+		{
+			name: "inline schema",
+			config: `
+asyncapi: 3.0.0
+channels:
+  orders:
+    messages:
+      order:
+        payload:
+          schemaFormat: application/vnd.google.protobuf
+          schema:
+            type: object
+`,
+			test: func(t *testing.T, msg *asyncapi3.Message, err error) {
+				require.ErrorContains(t, err, "protobuf schema must reference a message in a .proto file")
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &asyncapi3.Config{}
+			require.NoError(t, yaml.Unmarshal([]byte(tc.config), cfg))
+
+			reader := &dynamictest.Reader{Data: map[string]*dynamic.Config{
+				"file:///order.proto": {
+					Info: dynamictest.NewConfigInfo(dynamictest.WithUrl("file:///order.proto")),
+					Raw:  []byte(`syntax = "proto3"; package shop; message Order { string id = 1; }`),
+				},
+			}}
+			u, _ := url.Parse("file:///asyncapi.yaml")
+			err := cfg.Parse(&dynamic.Config{Info: dynamic.ConfigInfo{Url: u}, Data: cfg}, reader)
+			tc.test(t, cfg.Channels["orders"].Value.Messages["order"].Value, err)
+		})
+	}
+}
@@ -57,17 +57,22 @@ func (c *Client) publish(msg *Message) {
 				c.appendInflight(id, msg)
 			}
 
+			pub := &mqtt.PublishRequest{
+				MessageId: id,
+				Topic:     msg.Topic,
+				Data:      msg.Data,
+			}
+			if len(msg.UserProperties) > 0 {
+				pub.Properties = mqtt.Properties{mqtt.UserProperty: msg.UserProperties}
+			}
+
 			err := c.ctx.Send(&mqtt.Message{
 				Header: &mqtt.Header{
 					Type:   mqtt.PUBLISH,
 					QoS:    effectiveQoS,
 					Retain: false,
 				},
-				Payload: &mqtt.PublishRequest{
-					MessageId: id,
-					Topic:     msg.Topic,
-					Data:      msg.Data,
-				},
+				Payload: pub,
 			})
 			if err != nil {
 				log.Errorf("mqtt: failed to publish msg %d: %v", id, err)
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"maps"
+	"mokapi/engine/common"
+)
+
+type EventMessage struct {
+	Topic          string            `json:"topic"`
+	Payload        string            `json:"payload"`
+	QoS            byte              `json:"qos"`
+	Retain         bool              `json:"retain"`
+	UserProperties map[string]string `json:"userProperties"`
+	// Drop discards the message: it is acknowledged but neither
+	// retained nor delivered to subscribers.
+	Drop bool `json:"drop"`
+}
+
+type EventContext struct {
+	Api      string `json:"api"`
+	ClientId string `json:"clientId"`
+	Type     string `json:"type"`
+}
+
+func (s *Store) trigger(msg *Message, clientId string) (actions []*common.Action, drop bool) {
+	if s.eventEmitter == nil {
+		return nil, false
+	}
+
+	m := &EventMessage{
+		Topic:          msg.Topic,
+		Payload:        string(msg.Data),
+		QoS:            msg.QoS,
+		Retain:         msg.Retain,
+		UserProperties: map[string]string{},
+	}
+	maps.Copy(m.UserProperties, msg.UserProperties)
+
+	ctx := &EventContext{
+		Api:      s.cfg.Info.Name,
+		ClientId: clientId,
+		Type:     "publish",
+	}
+
+	actions = s.eventEmitter.Emit("mqtt", m, ctx)
+	if len(actions) == 0 {
+		return
+	}
+
+	msg.Topic = m.Topic
+	msg.Data = []byte(m.Payload)
+	if m.QoS <= 2 {
+		msg.QoS = m.QoS
+	}
+	msg.Retain = m.Retain
+	if len(m.UserProperties) == 0 {
+		msg.UserProperties = nil
+	} else {
+		msg.UserProperties = m.UserProperties
+	}
+
+	return actions, m.Drop
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"mokapi/engine/common"
 	"mokapi/mqtt"
 	"mokapi/runtime/events"
 	"time"
@@ -16,12 +17,15 @@ func (s *Store) publish(rw mqtt.MessageWriter, publish *mqtt.PublishRequest, qos
 	client.Alive()
 
 	msg := &Message{
-		Topic:  publish.Topic,
-		Data:   publish.Data,
-		QoS:    qos,
-		Retain: retain,
+		Topic:          publish.Topic,
+		Data:           publish.Data,
+		QoS:            qos,
+		Retain:         retain,
+		UserProperties: publish.Properties.UserProperties(),
 	}
 
+	actions, drop := s.trigger(msg, ctx.ClientId)
+
 	topic, ok := s.getTopic(msg.Topic)
 	if !ok {
 		log.Infof("mqtt: topic not specified %s", msg.Topic)
@@ -44,23 +48,28 @@ func (s *Store) publish(rw mqtt.MessageWriter, publish *mqtt.PublishRequest, qos
 		return
 	}
 
-	if retain {
-		topic.Retained = msg
-	}
-
 	if qos == 1 {
 		puback(rw, &mqtt.PublishResponse{
 			MessageId: publish.MessageId,
 		})
 	}
 
+	s.logMessage(messageId, topic, msg, actions, ctx)
+
+	if drop {
+		log.Infof("mqtt: message to topic '%s' dropped by event handler", msg.Topic)
+		return
+	}
+
+	if msg.Retain {
+		topic.Retained = msg
+	}
+
 	go func() {
 		for _, client := range s.clients {
 			client.publish(msg)
 		}
 	}()
-
-	s.logMessage(messageId, topic, publish, ctx)
 }
 
 func puback(rw mqtt.MessageWriter, payload *mqtt.PublishResponse) error {
@@ -105,7 +114,7 @@ func (s *Store) getTopic(topic string) (*Topic, bool) {
 	return nil, false
 }
 
-func (s *Store) logMessage(messageId string, topic *Topic, publish *mqtt.PublishRequest, ctx *mqtt.ClientContext) {
+func (s *Store) logMessage(messageId string, topic *Topic, msg *Message, actions []*common.Action, ctx *mqtt.ClientContext) {
 	topicName := topic.cfg.ResolveAddress()
 	labels := []string{s.cfg.Info.Name, topicName}
 
@@ -134,11 +143,15 @@ func (s *Store) logMessage(messageId string, topic *Topic, publish *mqtt.Publish
 		Topic:     topic.Name,
 		MessageId: messageId,
 		Message: LogValue{
-			Value:  string(publish.Data),
-			Binary: publish.Data,
+			Value:  string(msg.Data),
+			Binary: msg.Data,
 		},
-		Api:      s.cfg.Info.Name,
-		ClientId: client.Id,
+		QoS:            msg.QoS,
+		Retain:         msg.Retain,
+		UserProperties: msg.UserProperties,
+		Api:            s.cfg.Info.Name,
+		ClientId:       client.Id,
+		Actions:        actions,
 	}, traits)
 	if err != nil {
 		log.Errorf("mqtt: failed to log message: %s", err)
@@ -2,6 +2,7 @@ package store_test
 
 import (
 	"context"
+	"mokapi/engine/common"
 	"mokapi/engine/enginetest"
 	"mokapi/mqtt"
 	"mokapi/mqtt/mqtttest"
@@ -423,6 +424,136 @@ func TestPublish(t *testing.T) {
 	}
 }
 
+func TestPublish_EventHandler(t *testing.T) {
+	testcases := []struct {
+		name    string
+		handler func(event string, args ...interface{}) []*common.Action
+		test    func(t *testing.T, s *store.Store, eh *eventstest.Handler)
+	}{
+		{
+			name: "event handler receives message and context",
+			handler: func(event string, args ...interface{}) []*common.Action {
+				require.Equal(t, "mqtt", event)
+				msg := args[0].(*store.EventMessage)
+				require.Equal(t, "/foo/bar", msg.Topic)
+				require.Equal(t, "hello world", msg.Payload)
+				require.Equal(t, byte(1), msg.QoS)
+				require.Equal(t, map[string]string{"foo": "bar"}, msg.UserProperties)
+				ctx := args[1].(*store.EventContext)
+				require.Equal(t, "test-server", ctx.Api)
+				require.Equal(t, "publisher", ctx.ClientId)
+				require.Equal(t, "publish", ctx.Type)
+				return nil
+			},
+			test: func(t *testing.T, s *store.Store, eh *eventstest.Handler) {
+				publisher := newClient("publisher", s)
+				defer publisher.close()
+				publisher.connect()
+
+				rr := publisher.send(&mqtt.Message{
+					Header: &mqtt.Header{QoS: 1},
+					Payload: &mqtt.PublishRequest{
+						MessageId:  uint16(123),
+						Topic:      "/foo/bar",
+						Data:       []byte("hello world"),
+						Properties: mqtt.Properties{mqtt.UserProperty: map[string]string{"foo": "bar"}},
+					},
+					Context: publisher.ctx,
+				})
+				res := rr.Message.Payload.(*mqtt.PublishResponse)
+				require.Equal(t, mqtt.PublishSuccess, res.ReasonCode)
+
+				evts := eh.GetEvents(events.NewTraits().WithNamespace("mqtt").With("type", "message"))
+				require.Len(t, evts, 1)
+				require.Nil(t, evts[0].Data.(*store.LogMessage).Actions)
+			},
+		},
+		{
+			name: "event handler changes message",
+			handler: func(event string, args ...interface{}) []*common.Action {
+				msg := args[0].(*store.EventMessage)
+				msg.Payload = "modified"
+				msg.Retain = true
+				return []*common.Action{{Tags: map[string]string{"name": "test"}}}
+			},
+			test: func(t *testing.T, s *store.Store, eh *eventstest.Handler) {
+				publisher := newClient("publisher", s)
+				defer publisher.close()
+				publisher.connect()
+
+				publisher.send(&mqtt.Message{
+					Header: &mqtt.Header{QoS: 1},
+					Payload: &mqtt.PublishRequest{
+						MessageId: uint16(123),
+						Topic:     "/foo/bar",
+						Data:      []byte("hello world"),
+					},
+					Context: publisher.ctx,
+				})
+
+				evts := eh.GetEvents(events.NewTraits().WithNamespace("mqtt").With("type", "message"))
+				require.Len(t, evts, 1)
+				l := evts[0].Data.(*store.LogMessage)
+				require.Equal(t, "modified", l.Message.Value)
+				require.True(t, l.Retain)
+				require.Len(t, l.Actions, 1)
+				require.Equal(t, "modified", string(s.Topics["/foo/bar"].Retained.Data))
+			},
+		},
+		{
+			name: "event handler drops message",
+			handler: func(event string, args ...interface{}) []*common.Action {
+				msg := args[0].(*store.EventMessage)
+				msg.Drop = true
+				return []*common.Action{{}}
+			},
+			test: func(t *testing.T, s *store.Store, eh *eventstest.Handler) {
+				publisher := newClient("publisher", s)
+				defer publisher.close()
+				publisher.connect()
+
+				rr := publisher.send(&mqtt.Message{
+					Header: &mqtt.Header{QoS: 1, Retain: true},
+					Payload: &mqtt.PublishRequest{
+						MessageId: uint16(123),
+						Topic:     "/foo/bar",
+						Data:      []byte("hello world"),
+					},
+					Context: publisher.ctx,
+				})
+				res := rr.Message.Payload.(*mqtt.PublishResponse)
+				require.Equal(t, mqtt.PublishSuccess, res.ReasonCode)
+				require.Nil(t, s.Topics["/foo/bar"].Retained)
+
+				evts := eh.GetEvents(events.NewTraits().WithNamespace("mqtt").With("type", "message"))
+				require.Len(t, evts, 1)
+			},
+		},
+	}
+
+	t.Parallel()
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			eh := &eventstest.Handler{}
+			s := store.New(
+				asyncapi3test.NewConfig(
+					asyncapi3test.WithInfo("test-server", "", ""),
+					asyncapi3test.WithChannel("/foo/bar", asyncapi3test.WithMessage("msg")),
+				),
+				enginetest.NewEngineWithHandler(tc.handler),
+				eh,
+				monitor.NewMqtt(),
+			)
+			defer s.Close()
+
+			tc.test(t, s, eh)
+		})
+	}
+}
+
 type client struct {
 	ctx       context.Context
 	clientCtx *mqtt.ClientContext
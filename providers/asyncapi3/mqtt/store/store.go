@@ -14,14 +14,15 @@ import (
 type Store struct {
 	RetryInterval time.Duration
 
-	clients    map[string]*Client
-	Topics     map[string]*Topic
-	startedQoS bool
-	m          sync.RWMutex
-	close      chan bool
-	eh         events.Handler
-	cfg        *asyncapi3.Config
-	monitor    *monitor.Mqtt
+	clients      map[string]*Client
+	Topics       map[string]*Topic
+	startedQoS   bool
+	m            sync.RWMutex
+	close        chan bool
+	eh           events.Handler
+	eventEmitter engine.EventEmitter
+	cfg          *asyncapi3.Config
+	monitor      *monitor.Mqtt
 
 	stopClientCleaner chan bool
 }
@@ -33,6 +34,7 @@ func New(cfg *asyncapi3.Config, emitter engine.EventEmitter, eh events.Handler,
 		close:         make(chan bool, 1),
 		cfg:           cfg,
 		eh:            eh,
+		eventEmitter:  emitter,
 		monitor:       m,
 	}
 
@@ -8,10 +8,11 @@ import (
 )
 
 type Message struct {
-	Topic  string
-	Data   []byte
-	QoS    byte
-	Retain bool
+	Topic          string
+	Data           []byte
+	QoS            byte
+	Retain         bool
+	UserProperties map[string]string
 }
 
 type Topic struct {
@@ -94,6 +94,9 @@ func (s *Server) patch(patch *Server) {
 	if len(patch.ProtocolVersion) > 0 {
 		s.ProtocolVersion = patch.ProtocolVersion
 	}
+	if len(patch.Security) > 0 {
+		s.Security = patch.Security
+	}
 
 	s.Bindings.Kafka.Patch(patch.Bindings.Kafka)
 }
@@ -228,6 +231,14 @@ func (c *Config) patchComponents(patch *Config) {
 		}
 	}
 
+	if c.Components.SecuritySchemes == nil {
+		c.Components.SecuritySchemes = patch.Components.SecuritySchemes
+	} else {
+		for k, p := range patch.Components.SecuritySchemes {
+			c.Components.SecuritySchemes[k] = p
+		}
+	}
+
 	if c.Components.Schemas == nil {
 		c.Components.Schemas = patch.Components.Schemas
 	} else {
@@ -302,6 +313,15 @@ func (t *TopicBindings) Patch(patch TopicBindings) {
 	if patch.SegmentMs != 0 {
 		t.SegmentMs = patch.SegmentMs
 	}
+	if patch.CompressionType != "" {
+		t.CompressionType = patch.CompressionType
+	}
+	if len(patch.CleanupPolicy) > 0 {
+		t.CleanupPolicy = patch.CleanupPolicy
+	}
+	if patch.DeleteRetentionMs != 0 {
+		t.DeleteRetentionMs = patch.DeleteRetentionMs
+	}
 
 	t.ValueSchemaValidation = patch.ValueSchemaValidation
 }
@@ -11,7 +11,9 @@ import (
 	"mokapi/schema/encoding"
 	"mokapi/schema/json/parser"
 	jsonSchema "mokapi/schema/json/schema"
+	protobuf "mokapi/schema/protobuf/schema"
 	"reflect"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
@@ -108,6 +110,16 @@ func (r *SchemaRef) Marshal(v any, ct media.ContentType) ([]byte, error) {
 		return e.Write(v, ct)
 	case *avro.Schema:
 		return s.Marshal(v)
+	case *protobuf.Message:
+		if ct.Subtype == "json" || strings.HasSuffix(ct.Subtype, "+json") {
+			p := &protobuf.Parser{Message: s}
+			val, err := p.Parse(v)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(val)
+		}
+		return s.Marshal(v)
 	case *openapi.Schema:
 		return s.Marshal(v, ct)
 	case *SchemaRef:
@@ -125,7 +137,7 @@ func (r *SchemaRef) GetSchema() (Schema, error) {
 	}
 
 	switch s := r.Value.(type) {
-	case *jsonSchema.Schema, *avro.Schema, *openapi.Schema:
+	case *jsonSchema.Schema, *avro.Schema, *openapi.Schema, *protobuf.Message:
 		return s, nil
 	case *SchemaRef:
 		return s.GetSchema()
@@ -140,6 +152,9 @@ func (m *MultiSchemaFormat) Parse(config *dynamic.Config, reader dynamic.Reader)
 	if m == nil {
 		return nil
 	}
+	if isProtobuf(m.Format) && (m.Schema == nil || m.Schema.Ref == "") {
+		return errProtobufRef
+	}
 	if m.Schema != nil {
 		return m.Schema.Parse(config, reader)
 	}
@@ -156,6 +171,8 @@ func (m *MultiSchemaFormat) ConvertTo(i interface{}) (interface{}, error) {
 			return openapi.ConvertToJsonSchema(s), nil
 		case *avro.Schema:
 			return avro.ConvertToJsonSchema(s), nil
+		case *protobuf.Message:
+			return protobuf.ConvertToJsonSchema(s), nil
 		}
 	case *openapi.Schema:
 		if _, ok := m.Schema.Value.(*openapi.Schema); ok {
@@ -165,6 +182,10 @@ func (m *MultiSchemaFormat) ConvertTo(i interface{}) (interface{}, error) {
 		if _, ok := m.Schema.Value.(*avro.Schema); ok {
 			return m.Schema, nil
 		}
+	case *protobuf.Message:
+		if _, ok := m.Schema.Value.(*protobuf.Message); ok {
+			return m.Schema, nil
+		}
 	}
 	return nil, fmt.Errorf("unsupported schema convert %T: %T", m.Schema, i)
 }
@@ -275,6 +296,9 @@ func (m *MultiSchemaFormat) UnmarshalYAML(node *yaml.Node) error {
 			return err
 		}
 		m.Schema = &SchemaRef{Value: ref}
+	case isProtobuf(format):
+		// inline schemas are not supported and reported by Parse
+		return nil
 	default:
 		var s *jsonSchema.Schema
 		err = schemaNode.Decode(&s)
@@ -311,6 +335,8 @@ func (r *SchemaRef) Patch(patch *SchemaRef) {
 				}
 			case *avro.Schema:
 				log.Errorf("patch not supported for Avro schema")
+			case *protobuf.Message:
+				log.Errorf("patch not supported for Protobuf schema")
 			case *jsonSchema.Schema:
 				p, ok := patch.Value.(*jsonSchema.Schema)
 				if !ok {
@@ -352,6 +378,9 @@ func unmarshal(raw json.RawMessage, format string) (Schema, error) {
 			var a *avro.Schema
 			err := json.Unmarshal(raw, &a)
 			return a, err
+		case isProtobuf(format):
+			// inline schemas are not supported and reported by Parse
+			return nil, nil
 		default:
 			var r *jsonSchema.Schema
 			err := json.Unmarshal(raw, &r)
@@ -371,6 +400,21 @@ func isAvro(format string) bool {
 	}
 }
 
+// errProtobufRef is returned for inline Protobuf schemas. A Protobuf
+// schema references a message of a .proto file, e.g. ./order.proto#/Order
+var errProtobufRef = fmt.Errorf("protobuf schema must reference a message in a .proto file")
+
+func isProtobuf(format string) bool {
+	switch format {
+	case "application/vnd.google.protobuf",
+		"application/vnd.google.protobuf;version=2",
+		"application/vnd.google.protobuf;version=3":
+		return true
+	default:
+		return false
+	}
+}
+
 func isOpenApi(format string) bool {
 	switch format {
 	case "application/vnd.oai.openapi+json;version=3.0.0",
@@ -421,6 +465,8 @@ func (r *SchemaRef) GetParser(contentType string) (encoding.Parser, error) {
 		return &parser.Parser{Schema: openapi.ConvertToJsonSchema(s), ConvertToSortedMap: true}, nil
 	case *AvroRef:
 		return &avro.Parser{Schema: s.Schema}, nil
+	case *protobuf.Message:
+		return &protobuf.Parser{Message: s}, nil
 	case *MultiSchemaFormat:
 		return s.Schema.GetParser(contentType)
 	default:
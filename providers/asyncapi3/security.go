@@ -0,0 +1,46 @@
+package asyncapi3
+
+import (
+	"mokapi/config/dynamic"
+
+	"gopkg.in/yaml.v3"
+)
+
+type SecuritySchemeRef struct {
+	dynamic.Reference[*SecuritySchemeRef]
+	Value *SecurityScheme
+}
+
+type SecurityScheme struct {
+	// Type of the security scheme, e.g. plain, scramSha256 or scramSha512
+	Type        string `yaml:"type" json:"type"`
+	Description string `yaml:"description" json:"description"`
+
+	// Users is a Mokapi extension to define credentials the mock server accepts.
+	Users []SecurityUser `yaml:"x-users,omitempty" json:"x-users,omitempty"`
+}
+
+type SecurityUser struct {
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"password"`
+}
+
+func (r *SecuritySchemeRef) UnmarshalYAML(node *yaml.Node) error {
+	return r.Reference.UnmarshalYaml(node, &r.Value)
+}
+
+func (r *SecuritySchemeRef) UnmarshalJSON(b []byte) error {
+	return r.Reference.UnmarshalJson(b, &r.Value)
+}
+
+func (r *SecuritySchemeRef) parse(config *dynamic.Config, reader dynamic.Reader) error {
+	if len(r.Ref) > 0 {
+		resolved, err := r.Resolve(config, reader)
+		if err != nil {
+			return err
+		}
+		r.Value = resolved.Value
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package asyncapi3
 
 import (
 	"mokapi/config/dynamic"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -20,6 +21,7 @@ type Server struct {
 	Protocol        string                        `yaml:"protocol" json:"protocol"`
 	ProtocolVersion string                        `yaml:"protocolVersion" json:"protocolVersion"`
 	Variables       map[string]*ServerVariableRef `yaml:"variables" json:"variables"`
+	Security        []*SecuritySchemeRef          `yaml:"security" json:"security"`
 	Tags            []*TagRef                     `yaml:"tags" json:"tags"`
 	Bindings        ServerBindings                `yaml:"bindings" json:"bindings"`
 	ExternalDocs    []ExternalDocRef              `yaml:"externalDocs" json:"externalDocs"`
@@ -37,6 +39,42 @@ type ServerVariable struct {
 	Examples    []string `yaml:"examples" json:"examples"`
 }
 
+// IsKafka reports whether the server uses the Kafka protocol, with or without TLS
+func (s *Server) IsKafka() bool {
+	switch strings.ToLower(s.Protocol) {
+	case "kafka", "kafka-secure", "kafka+ssl":
+		return true
+	}
+	return false
+}
+
+// IsMqtt reports whether the server uses the MQTT protocol, with or without TLS
+func (s *Server) IsMqtt() bool {
+	switch strings.ToLower(s.Protocol) {
+	case "mqtt", "secure-mqtt", "mqtts":
+		return true
+	}
+	return false
+}
+
+// IsWebSocket reports whether the server uses the WebSocket protocol, with or without TLS
+func (s *Server) IsWebSocket() bool {
+	switch strings.ToLower(s.Protocol) {
+	case "ws", "wss":
+		return true
+	}
+	return false
+}
+
+// IsSecure reports whether the server protocol requires TLS
+func (s *Server) IsSecure() bool {
+	switch strings.ToLower(s.Protocol) {
+	case "kafka-secure", "kafka+ssl", "secure-mqtt", "mqtts", "wss":
+		return true
+	}
+	return false
+}
+
 func (r *ServerRef) Parse(config *dynamic.Config, reader dynamic.Reader) error {
 	if len(r.Ref) > 0 {
 		resolved, err := r.Resolve(config, reader)
@@ -57,6 +95,12 @@ func (r *ServerRef) Parse(config *dynamic.Config, reader dynamic.Reader) error {
 		}
 	}
 
+	for _, v := range r.Value.Security {
+		if err := v.parse(config, reader); err != nil {
+			return err
+		}
+	}
+
 	for _, v := range r.Value.Tags {
 		if err := v.parse(config, reader); err != nil {
 			return err
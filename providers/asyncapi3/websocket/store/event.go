@@ -0,0 +1,75 @@
+package store
+
+import (
+	"fmt"
+	"mokapi/engine/common"
+	"mokapi/media"
+	"mokapi/providers/asyncapi3"
+	openapi "mokapi/providers/openapi/schema"
+	avro "mokapi/schema/avro/schema"
+	"mokapi/schema/json/generator"
+	"mokapi/schema/json/schema"
+	protobuf "mokapi/schema/protobuf/schema"
+)
+
+type EventMessage struct {
+	Channel string `json:"channel"`
+	Data    string `json:"data"`
+}
+
+type EventContext struct {
+	Api       string `json:"api"`
+	SessionId string `json:"sessionId"`
+	Path      string `json:"path"`
+	ClientIP  string `json:"clientIP"`
+}
+
+func (s *Store) trigger(session *Session, data []byte) []*common.Action {
+	if s.eventEmitter == nil {
+		return nil
+	}
+
+	msg := &EventMessage{
+		Channel: session.Channel(),
+		Data:    string(data),
+	}
+	ctx := &EventContext{
+		Api:       s.cfg.Info.Name,
+		SessionId: session.Id,
+		Path:      session.Path,
+		ClientIP:  session.ClientIP,
+	}
+	return s.eventEmitter.Emit("websocket", msg, ctx)
+}
+
+// generate creates a message payload from the message's schema
+func (s *Store) generate(msg *asyncapi3.Message) ([]byte, error) {
+	ct := media.ParseContentType(s.contentType(msg))
+	if msg.Payload == nil || msg.Payload.Value == nil {
+		return []byte{}, nil
+	}
+
+	sch, err := msg.Payload.GetSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	var v any
+	switch t := sch.(type) {
+	case *schema.Schema:
+		v, err = generator.New(&generator.Request{Schema: t})
+	case *openapi.Schema:
+		v, err = openapi.CreateValue(t)
+	case *avro.Schema:
+		v, err = generator.New(&generator.Request{Schema: avro.ConvertToJsonSchema(t)})
+	case *protobuf.Message:
+		v, err = generator.New(&generator.Request{Schema: protobuf.ConvertToJsonSchema(t)})
+	default:
+		err = fmt.Errorf("schema format not supported: %T", sch)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.Payload.Marshal(v, ct)
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"mokapi/engine/common"
+	"mokapi/runtime/events"
+	"mokapi/websocket"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DirectionReceive = "receive"
+	DirectionSend    = "send"
+)
+
+// SessionLog is recorded when a client connects to or disconnects from a channel
+type SessionLog struct {
+	Api       string     `json:"api"`
+	Channel   string     `json:"channel"`
+	SessionId string     `json:"sessionId"`
+	Type      string     `json:"type"`
+	Url       string     `json:"url"`
+	ClientIP  string     `json:"clientIP"`
+	Duration  int64      `json:"duration"`
+	Close     *CloseInfo `json:"close,omitempty"`
+}
+
+type CloseInfo struct {
+	Code   int    `json:"code"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// LogMessage is recorded for each message received from or sent to a client
+type LogMessage struct {
+	Api       string           `json:"api"`
+	Channel   string           `json:"channel"`
+	SessionId string           `json:"sessionId"`
+	Direction string           `json:"direction"`
+	MessageId string           `json:"messageId"`
+	Data      LogValue         `json:"data"`
+	Error     string           `json:"error,omitempty"`
+	Actions   []*common.Action `json:"actions"`
+}
+
+type LogValue struct {
+	Value  string `json:"value"`
+	Binary []byte `json:"binary"`
+}
+
+func (l *SessionLog) Title() string {
+	if l.Type == "connect" {
+		return "Connect " + l.Channel
+	}
+	return "Close " + l.Channel
+}
+
+func (l *LogMessage) Title() string {
+	return l.Channel
+}
+
+func (s *Store) logSession(session *Session, typ string, closeErr *websocket.CloseError) {
+	l := &SessionLog{
+		Api:       s.cfg.Info.Name,
+		Channel:   session.Channel(),
+		SessionId: session.Id,
+		Type:      typ,
+		Url:       session.Url,
+		ClientIP:  session.ClientIP,
+	}
+	if typ == "close" {
+		l.Duration = time.Since(session.Opened).Milliseconds()
+		if closeErr != nil {
+			l.Close = &CloseInfo{Code: closeErr.Code, Reason: closeErr.Reason}
+		}
+	}
+	s.push(l, session, "session")
+}
+
+func (s *Store) logMessage(session *Session, msg *LogMessage, err error) {
+	msg.Api = s.cfg.Info.Name
+	msg.Channel = session.Channel()
+	msg.SessionId = session.Id
+	if err != nil {
+		msg.Error = err.Error()
+	}
+	s.push(msg, session, "message")
+}
+
+func (s *Store) push(data events.EventData, session *Session, typ string) {
+	if s.eh == nil {
+		return
+	}
+	traits := events.NewTraits().
+		WithNamespace("websocket").
+		WithName(s.cfg.Info.Name).
+		With("channel", session.Channel()).
+		With("type", typ).
+		With("sessionId", session.Id)
+	if err := s.eh.Push(data, traits); err != nil {
+		log.Errorf("websocket: failed to log event: %v", err)
+	}
+}
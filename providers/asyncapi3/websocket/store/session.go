@@ -0,0 +1,248 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"mokapi/lib"
+	"mokapi/media"
+	"mokapi/providers/asyncapi3"
+	"mokapi/schema/encoding"
+	"mokapi/websocket"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+type Session struct {
+	Id       string
+	Path     string
+	Url      string
+	ClientIP string
+	Opened   time.Time
+
+	store   *Store
+	conn    *websocket.Conn
+	channel *asyncapi3.Channel
+}
+
+func newSession(s *Store, conn *websocket.Conn, ch *asyncapi3.Channel, r *http.Request) *Session {
+	return &Session{
+		Id:       uuid.New().String(),
+		Path:     r.URL.Path,
+		Url:      lib.GetUrl(r),
+		ClientIP: lib.ClientIP(r),
+		Opened:   time.Now(),
+		store:    s,
+		conn:     conn,
+		channel:  ch,
+	}
+}
+
+func (s *Session) Channel() string {
+	return s.channel.GetName()
+}
+
+func (s *Session) serve() {
+	s.store.logSession(s, "connect", nil)
+	log.Infof("websocket: client %v connected to channel '%v'", s.ClientIP, s.Channel())
+
+	s.sendGenerated()
+
+	var closeErr *websocket.CloseError
+	for {
+		mt, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if !errors.As(err, &closeErr) {
+				closeErr = &websocket.CloseError{Code: websocket.CloseGoingAway, Reason: err.Error()}
+				_ = s.conn.Close(websocket.CloseGoingAway, "")
+			}
+			break
+		}
+		if closeErr = s.receive(mt, data); closeErr != nil {
+			_ = s.conn.Close(closeErr.Code, closeErr.Reason)
+			break
+		}
+	}
+
+	s.store.logSession(s, "close", closeErr)
+	log.Infof("websocket: client %v disconnected from channel '%v'", s.ClientIP, s.Channel())
+}
+
+// receive validates the message against the messages of the receive
+// operations and triggers the websocket event handlers. An invalid message
+// does not trigger the handlers and returns the close error for the
+// connection.
+func (s *Session) receive(mt websocket.MessageType, data []byte) *websocket.CloseError {
+	msg := &LogMessage{
+		Direction: DirectionReceive,
+		Data:      LogValue{Value: string(data), Binary: binaryOrNil(mt, data)},
+	}
+
+	var err error
+	msg.MessageId, err = s.validate(data)
+	if err != nil {
+		log.Errorf("websocket: invalid message on channel '%v': %v", s.Channel(), err)
+		s.store.logMessage(s, msg, err)
+		return &websocket.CloseError{Code: websocket.CloseInvalidPayload, Reason: "invalid message"}
+	}
+
+	msg.Actions = s.store.trigger(s, data)
+	s.store.logMessage(s, msg, nil)
+	return nil
+}
+
+func (s *Session) write(mt websocket.MessageType, data []byte, msg *LogMessage) error {
+	if err := s.conn.WriteMessage(mt, data); err != nil {
+		return err
+	}
+	if msg == nil {
+		msg = &LogMessage{}
+	}
+	msg.Direction = DirectionSend
+	msg.Data = LogValue{Value: string(data), Binary: binaryOrNil(mt, data)}
+	s.store.logMessage(s, msg, nil)
+	return nil
+}
+
+// sendGenerated sends a generated message for each send operation of the channel
+func (s *Session) sendGenerated() {
+	for _, op := range s.store.operations(s.channel, "send") {
+		msgId, msg := s.store.firstMessage(op, s.channel)
+		if msg == nil {
+			continue
+		}
+		data, err := s.store.generate(msg)
+		if err != nil {
+			log.Errorf("websocket: generate message for channel '%v' failed: %v", s.Channel(), err)
+			continue
+		}
+		err = s.write(messageType(s.store.contentType(msg)), data, &LogMessage{MessageId: msgId})
+		if err != nil {
+			log.Errorf("websocket: send message to channel '%v' failed: %v", s.Channel(), err)
+		}
+	}
+}
+
+func (s *Session) validate(data []byte) (messageId string, err error) {
+	ops := s.store.operations(s.channel, "receive")
+	var messages map[string]*asyncapi3.MessageRef
+	if len(ops) == 0 {
+		messages = s.channel.Messages
+	} else {
+		messages = map[string]*asyncapi3.MessageRef{}
+		for _, op := range ops {
+			for id, msg := range s.store.messages(op, s.channel) {
+				messages[id] = msg
+			}
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(messages)) {
+		msg := messages[id]
+		if msg == nil || msg.Value == nil {
+			continue
+		}
+		messageId = id
+		payload := msg.Value.Payload
+		if payload == nil || payload.Value == nil {
+			return id, nil
+		}
+		ct := s.store.contentType(msg.Value)
+		var p encoding.Parser
+		p, err = payload.GetParser(ct)
+		if err != nil {
+			return
+		}
+		_, err = encoding.Decode(data, encoding.WithContentType(media.ParseContentType(ct)), encoding.WithParser(p))
+		if err == nil {
+			return
+		}
+	}
+	return
+}
+
+// matches reports whether the session is connected to the channel given by
+// its name, its address or the request path of the session
+func (s *Session) matches(channel string) bool {
+	channel = strings.Trim(channel, "/")
+	path := strings.Trim(s.Path, "/")
+	return channel == strings.Trim(s.channel.GetName(), "/") ||
+		channel == strings.Trim(s.channel.Name, "/") ||
+		channel == path || strings.HasSuffix(path, "/"+channel)
+}
+
+func (s *Store) operations(ch *asyncapi3.Channel, action string) []*asyncapi3.Operation {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	var result []*asyncapi3.Operation
+	for _, name := range slices.Sorted(maps.Keys(s.cfg.Operations)) {
+		op := s.cfg.Operations[name]
+		if op == nil || op.Value == nil || op.Value.Channel.Value != ch {
+			continue
+		}
+		if op.Value.Action == action {
+			result = append(result, op.Value)
+		}
+	}
+	return result
+}
+
+// messages returns the messages of the operation or, if not specified,
+// the messages of the channel
+func (s *Store) messages(op *asyncapi3.Operation, ch *asyncapi3.Channel) map[string]*asyncapi3.MessageRef {
+	if len(op.Messages) == 0 {
+		return ch.Messages
+	}
+	m := map[string]*asyncapi3.MessageRef{}
+	for i, msg := range op.Messages {
+		id := fmt.Sprintf("%d", i)
+		if msg.Value != nil && msg.Value.Name != "" {
+			id = msg.Value.Name
+		}
+		m[id] = msg
+	}
+	return m
+}
+
+func (s *Store) firstMessage(op *asyncapi3.Operation, ch *asyncapi3.Channel) (string, *asyncapi3.Message) {
+	messages := s.messages(op, ch)
+	for _, id := range slices.Sorted(maps.Keys(messages)) {
+		if msg := messages[id]; msg != nil && msg.Value != nil {
+			return id, msg.Value
+		}
+	}
+	return "", nil
+}
+
+func (s *Store) contentType(msg *asyncapi3.Message) string {
+	if msg.ContentType != "" {
+		return msg.ContentType
+	}
+	if s.cfg.DefaultContentType != "" {
+		return s.cfg.DefaultContentType
+	}
+	return asyncapi3.DefaultContentType
+}
+
+// messageType returns whether a message with the given content type is
+// sent as text or binary frame
+func messageType(contentType string) websocket.MessageType {
+	ct := media.ParseContentType(contentType)
+	if ct.Type == "text" || ct.Subtype == "json" || strings.HasSuffix(ct.Subtype, "+json") || ct.IsXml() {
+		return websocket.TextMessage
+	}
+	return websocket.BinaryMessage
+}
+
+func binaryOrNil(mt websocket.MessageType, data []byte) []byte {
+	if mt == websocket.BinaryMessage {
+		return data
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"maps"
+	engine "mokapi/engine/common"
+	"mokapi/providers/asyncapi3"
+	"mokapi/runtime/events"
+	"mokapi/websocket"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type Store struct {
+	cfg          *asyncapi3.Config
+	sessions     map[string]*Session
+	eh           events.Handler
+	eventEmitter engine.EventEmitter
+	m            sync.RWMutex
+}
+
+func New(cfg *asyncapi3.Config, emitter engine.EventEmitter, eh events.Handler) *Store {
+	return &Store{
+		cfg:          cfg,
+		sessions:     map[string]*Session{},
+		eh:           eh,
+		eventEmitter: emitter,
+	}
+}
+
+func (s *Store) Update(cfg *asyncapi3.Config) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.cfg = cfg
+}
+
+// Match returns the channel whose address matches the request path.
+// The pathname of the WebSocket servers is a prefix of the channel address.
+func (s *Store) Match(path string) (*asyncapi3.Channel, bool) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	if s.cfg == nil {
+		return nil, false
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(s.cfg.Channels)) {
+		ch := s.cfg.Channels[name]
+		if ch == nil || ch.Value == nil {
+			continue
+		}
+		for _, pathname := range s.pathnames(ch.Value) {
+			rel, ok := strings.CutPrefix(path, pathname)
+			if !ok {
+				continue
+			}
+			if matchAddress(ch.Value.ResolveAddress(), rel) {
+				return ch.Value, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and serves
+// the session until the client disconnects.
+func (s *Store) ServeHTTP(rw http.ResponseWriter, r *http.Request, ch *asyncapi3.Channel) {
+	conn, err := websocket.Upgrade(rw, r, nil)
+	if err != nil {
+		log.Infof("websocket: upgrade failed for %v: %v", r.URL.String(), err)
+		return
+	}
+
+	session := newSession(s, conn, ch, r)
+	s.m.Lock()
+	s.sessions[session.Id] = session
+	s.m.Unlock()
+
+	defer func() {
+		s.m.Lock()
+		delete(s.sessions, session.Id)
+		s.m.Unlock()
+	}()
+
+	session.serve()
+}
+
+// Broadcast sends the data to all clients connected to the given channel.
+// If sessionId is not empty, the data is only sent to that session.
+func (s *Store) Broadcast(channel string, data []byte, messageType websocket.MessageType, sessionId string) int {
+	s.m.RLock()
+	var sessions []*Session
+	for _, session := range s.sessions {
+		if sessionId != "" && session.Id != sessionId {
+			continue
+		}
+		if channel != "" && !session.matches(channel) {
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	s.m.RUnlock()
+
+	n := 0
+	for _, session := range sessions {
+		if err := session.write(messageType, data, nil); err != nil {
+			log.Errorf("websocket: send to session %v failed: %v", session.Id, err)
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+// Sessions returns the currently connected sessions
+func (s *Store) Sessions() []*Session {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	return slices.Collect(maps.Values(s.sessions))
+}
+
+func (s *Store) Close() {
+	for _, session := range s.Sessions() {
+		_ = session.conn.Close(websocket.CloseGoingAway, "")
+	}
+}
+
+func (s *Store) pathnames(ch *asyncapi3.Channel) []string {
+	var servers []*asyncapi3.ServerRef
+	if len(ch.Servers) > 0 {
+		servers = ch.Servers
+	} else if s.cfg.Servers != nil {
+		for it := s.cfg.Servers.Iter(); it.Next(); {
+			servers = append(servers, it.Value())
+		}
+	}
+
+	var result []string
+	for _, server := range servers {
+		if server == nil || server.Value == nil || !server.Value.IsWebSocket() {
+			continue
+		}
+		result = append(result, strings.TrimSuffix(server.Value.Pathname, "/"))
+	}
+	return result
+}
+
+// matchAddress reports whether the path matches the channel address.
+// A parameter like {roomId} matches a single path segment.
+func matchAddress(address, path string) bool {
+	expected := strings.Split(strings.Trim(address, "/"), "/")
+	actual := strings.Split(strings.Trim(path, "/"), "/")
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i, segment := range expected {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if actual[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != actual[i] {
+			return false
+		}
+	}
+	return true
+}
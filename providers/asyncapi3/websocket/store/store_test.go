@@ -0,0 +1,236 @@
+package store_test
+
+import (
+	"mokapi/config/dynamic"
+	"mokapi/engine/common"
+	"mokapi/providers/asyncapi3"
+	"mokapi/providers/asyncapi3/websocket/store"
+	"mokapi/runtime/events"
+	"mokapi/runtime/events/eventstest"
+	"mokapi/websocket"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const config = `
+asyncapi: 3.0.0
+info:
+  title: Chat
+  version: 1.0.0
+servers:
+  ws:
+    host: localhost
+    protocol: ws
+    pathname: /ws
+channels:
+  room:
+    address: rooms/{roomId}
+    messages:
+      chat:
+        payload:
+          type: object
+          properties:
+            text:
+              type: string
+          required: [ text ]
+      welcome:
+        payload:
+          type: string
+          const: welcome
+operations:
+  sendWelcome:
+    action: send
+    channel:
+      $ref: '#/channels/room'
+    messages:
+      - $ref: '#/channels/room/messages/welcome'
+  receiveChat:
+    action: receive
+    channel:
+      $ref: '#/channels/room'
+    messages:
+      - $ref: '#/channels/room/messages/chat'
+`
+
+type emitter struct {
+	emit func(event string, args ...interface{}) []*common.Action
+}
+
+func (e *emitter) Emit(event string, args ...interface{}) []*common.Action {
+	if e.emit != nil {
+		return e.emit(event, args...)
+	}
+	return nil
+}
+
+func TestStore(t *testing.T) {
+	testcases := []struct {
+		name string
+		emit func(event string, args ...interface{}) []*common.Action
+		test func(t *testing.T, s *store.Store, url string, eh *lockedHandler)
+	}{
+		{
+			name: "match channel address with parameter",
+			test: func(t *testing.T, s *store.Store, _ string, _ *lockedHandler) {
+				ch, ok := s.Match("/ws/rooms/42")
+				require.True(t, ok)
+				require.Equal(t, "rooms/{roomId}", ch.GetName())
+
+				_, ok = s.Match("/rooms/42")
+				require.False(t, ok)
+				_, ok = s.Match("/ws/rooms/42/foo")
+				require.False(t, ok)
+			},
+		},
+		{
+			name: "send generated message on connect",
+			test: func(t *testing.T, s *store.Store, url string, eh *lockedHandler) {
+				conn := dial(t, url+"/ws/rooms/1")
+				mt, b, err := conn.ReadMessage()
+				require.NoError(t, err)
+				require.Equal(t, websocket.TextMessage, mt)
+				require.Equal(t, `"welcome"`, string(b))
+
+				e := eh.GetEvents(events.NewTraits().WithNamespace("websocket").With("type", "session"))
+				require.Len(t, e, 1)
+				require.Equal(t, "connect", e[0].Data.(*store.SessionLog).Type)
+			},
+		},
+		{
+			name: "receive valid message and close on invalid message",
+			emit: func(event string, args ...interface{}) []*common.Action {
+				return []*common.Action{{Tags: map[string]string{"event": event}}}
+			},
+			test: func(t *testing.T, s *store.Store, url string, eh *lockedHandler) {
+				conn := dial(t, url+"/ws/rooms/1")
+				_, _, err := conn.ReadMessage()
+				require.NoError(t, err)
+
+				require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"text":"hello"}`)))
+				require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"foo":"bar"}`)))
+
+				_, _, err = conn.ReadMessage()
+				var closeErr *websocket.CloseError
+				require.ErrorAs(t, err, &closeErr)
+				require.Equal(t, websocket.CloseInvalidPayload, closeErr.Code)
+
+				var received []*store.LogMessage
+				require.Eventually(t, func() bool {
+					received = nil
+					for _, e := range eh.GetEvents(events.NewTraits().WithNamespace("websocket").With("type", "message")) {
+						if l := e.Data.(*store.LogMessage); l.Direction == store.DirectionReceive {
+							received = append(received, l)
+						}
+					}
+					return len(received) == 2
+				}, time.Second, 10*time.Millisecond)
+
+				require.Equal(t, `{"text":"hello"}`, received[0].Data.Value)
+				require.Equal(t, "", received[0].Error)
+				require.Equal(t, "websocket", received[0].Actions[0].Tags["event"])
+				require.Contains(t, received[1].Error, "required properties are missing: text")
+				require.Len(t, received[1].Actions, 0)
+
+				require.Eventually(t, func() bool {
+					return len(s.Sessions()) == 0
+				}, time.Second, 10*time.Millisecond)
+				e := eh.GetEvents(events.NewTraits().WithNamespace("websocket").With("type", "session"))
+				require.Equal(t, &store.CloseInfo{Code: websocket.CloseInvalidPayload, Reason: "invalid message"}, e[1].Data.(*store.SessionLog).Close)
+			},
+		},
+		{
+			name: "broadcast to channel",
+			test: func(t *testing.T, s *store.Store, url string, eh *lockedHandler) {
+				c1 := dial(t, url+"/ws/rooms/1")
+				c2 := dial(t, url+"/ws/rooms/2")
+				for _, c := range []*websocket.Conn{c1, c2} {
+					_, _, err := c.ReadMessage()
+					require.NoError(t, err)
+				}
+
+				n := s.Broadcast("rooms/2", []byte("only room 2"), websocket.TextMessage, "")
+				require.Equal(t, 1, n)
+				_, b, err := c2.ReadMessage()
+				require.NoError(t, err)
+				require.Equal(t, "only room 2", string(b))
+
+				n = s.Broadcast("room", []byte("all rooms"), websocket.TextMessage, "")
+				require.Equal(t, 2, n)
+			},
+		},
+		{
+			name: "session closed",
+			test: func(t *testing.T, s *store.Store, url string, eh *lockedHandler) {
+				conn := dial(t, url+"/ws/rooms/1")
+				_, _, err := conn.ReadMessage()
+				require.NoError(t, err)
+				require.NoError(t, conn.Close(websocket.CloseNormal, "bye"))
+
+				require.Eventually(t, func() bool {
+					return len(s.Sessions()) == 0
+				}, time.Second, 10*time.Millisecond)
+
+				e := eh.GetEvents(events.NewTraits().WithNamespace("websocket").With("type", "session"))
+				require.Len(t, e, 2)
+				l := e[1].Data.(*store.SessionLog)
+				require.Equal(t, "close", l.Type)
+				require.Equal(t, &store.CloseInfo{Code: websocket.CloseNormal, Reason: "bye"}, l.Close)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &asyncapi3.Config{}
+			require.NoError(t, yaml.Unmarshal([]byte(config), cfg))
+			require.NoError(t, cfg.Parse(&dynamic.Config{Data: cfg}, nil))
+
+			eh := &lockedHandler{h: &eventstest.Handler{}}
+			s := store.New(cfg, &emitter{emit: tc.emit}, eh)
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				ch, ok := s.Match(r.URL.Path)
+				if !ok {
+					rw.WriteHeader(http.StatusNotFound)
+					return
+				}
+				s.ServeHTTP(rw, r, ch)
+			}))
+			defer server.Close()
+			defer s.Close()
+
+			tc.test(t, s, "ws"+strings.TrimPrefix(server.URL, "http"), eh)
+		})
+	}
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	conn, _, err := websocket.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close(websocket.CloseNormal, "") })
+	return conn
+}
+
+// lockedHandler makes the test handler safe for concurrent sessions
+type lockedHandler struct {
+	h *eventstest.Handler
+	m sync.Mutex
+}
+
+func (l *lockedHandler) Push(data events.EventData, traits events.Traits) error {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.h.Push(data, traits)
+}
+
+func (l *lockedHandler) GetEvents(traits events.Traits) []events.Event {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.h.GetEvents(traits)
+}
@@ -175,6 +175,30 @@ func (c *Config) Parse(config *dynamic.Config, reader dynamic.Reader) error {
 	return nil
 }
 
+// ListenAddress returns the address the server binds to and whether
+// the address uses the ldaps scheme which requires implicit TLS.
+// An address without scheme like ":389" is returned unchanged.
+func (c *Config) ListenAddress() (addr string, implicitTls bool) {
+	if !strings.Contains(c.Address, "://") {
+		return c.Address, false
+	}
+	u, err := url.Parse(c.Address)
+	if err != nil {
+		return c.Address, false
+	}
+
+	implicitTls = strings.ToLower(u.Scheme) == "ldaps"
+	addr = u.Host
+	if u.Port() == "" {
+		if implicitTls {
+			addr += ":636"
+		} else {
+			addr += ":389"
+		}
+	}
+	return addr, implicitTls
+}
+
 func (c *Config) getSizeLimit() int64 {
 	return c.SizeLimit
 }
@@ -22,6 +22,46 @@ func TestConfig(t *testing.T) {
 				require.Equal(t, ":389", cfg.Address)
 			},
 		},
+		{
+			name:  "address without scheme",
+			input: `{"ldap": "1.0", "host": ":389" }`,
+			test: func(t *testing.T, cfg *Config, err error) {
+				require.NoError(t, err)
+				addr, implicitTls := cfg.ListenAddress()
+				require.Equal(t, ":389", addr)
+				require.False(t, implicitTls)
+			},
+		},
+		{
+			name:  "ldaps address",
+			input: `{"ldap": "1.0", "host": "ldaps://:10636" }`,
+			test: func(t *testing.T, cfg *Config, err error) {
+				require.NoError(t, err)
+				addr, implicitTls := cfg.ListenAddress()
+				require.Equal(t, ":10636", addr)
+				require.True(t, implicitTls)
+			},
+		},
+		{
+			name:  "ldaps address with default port",
+			input: `{"ldap": "1.0", "host": "ldaps://localhost" }`,
+			test: func(t *testing.T, cfg *Config, err error) {
+				require.NoError(t, err)
+				addr, implicitTls := cfg.ListenAddress()
+				require.Equal(t, "localhost:636", addr)
+				require.True(t, implicitTls)
+			},
+		},
+		{
+			name:  "ldap address with scheme",
+			input: `{"ldap": "1.0", "host": "ldap://:10389" }`,
+			test: func(t *testing.T, cfg *Config, err error) {
+				require.NoError(t, err)
+				addr, implicitTls := cfg.ListenAddress()
+				require.Equal(t, ":10389", addr)
+				require.False(t, implicitTls)
+			},
+		},
 		{
 			name:  "set Root DSE by config",
 			input: `{"ldap": "1.0", "info": { "name": "foo", "description": "bar" } }`,
@@ -39,6 +39,8 @@ func (d *Directory) ServeLDAP(res ldap.ResponseWriter, r *ldap.Request) {
 		d.serveModifyDn(res, m, r.Context)
 	case *ldap.CompareRequest:
 		d.serveCompare(res, m, r.Context)
+	case *ldap.ExtendedRequest:
+		d.serveExtended(res, m, r.Context)
 	}
 }
 
@@ -93,6 +95,14 @@ func (d *Directory) serveBind(rw ldap.ResponseWriter, r *ldap.Request) {
 		}
 	}
 
+	// a failed bind leaves the connection in an anonymous state
+	if a := ldap.AuthFromContext(r.Context); a != nil {
+		a.Dn = ""
+		if res.Result == ldap.Success {
+			a.Dn = msg.Name
+		}
+	}
+
 	m, doMonitor := monitor.LdapFromContext(r.Context)
 	if doMonitor {
 		l := NewBindLogEvent(msg, res, d.eh, events.NewTraits().WithName(d.config.Info.Name))
@@ -102,6 +112,7 @@ func (d *Directory) serveBind(rw ldap.ResponseWriter, r *ldap.Request) {
 				t := i.(time.Time)
 				l.Duration = time.Now().Sub(t).Milliseconds()
 			}
+			events.Update(d.eh, l)
 		}()
 		m.RequestCounter.WithLabel(d.config.Info.Name, "modify").Add(1)
 		m.LastRequest.WithLabel(d.config.Info.Name).Set(float64(time.Now().Unix()))
@@ -173,6 +184,7 @@ func (d *Directory) serveModify(rw ldap.ResponseWriter, r *ldap.ModifyRequest, c
 				t := i.(time.Time)
 				l.Duration = time.Now().Sub(t).Milliseconds()
 			}
+			events.Update(d.eh, l)
 		}()
 		m.RequestCounter.WithLabel(d.config.Info.Name, "modify").Add(1)
 		m.LastRequest.WithLabel(d.config.Info.Name).Set(float64(time.Now().Unix()))
@@ -189,6 +201,7 @@ func (d *Directory) Unbind(ctx context.Context) {
 		if i != nil {
 			t := i.(time.Time)
 			l.Duration = time.Now().Sub(t).Milliseconds()
+			events.Update(d.eh, l)
 		}
 		m.RequestCounter.WithLabel(d.config.Info.Name, "unbind").Add(1)
 		m.LastRequest.WithLabel(d.config.Info.Name).Set(float64(time.Now().Unix()))
@@ -224,6 +237,7 @@ func (d *Directory) serveAdd(rw ldap.ResponseWriter, r *ldap.AddRequest, ctx con
 				t := i.(time.Time)
 				l.Duration = time.Now().Sub(t).Milliseconds()
 			}
+			events.Update(d.eh, l)
 		}()
 		m.RequestCounter.WithLabel(d.config.Info.Name, "add").Add(1)
 		m.LastRequest.WithLabel(d.config.Info.Name).Set(float64(time.Now().Unix()))
@@ -256,6 +270,7 @@ func (d *Directory) serveDelete(rw ldap.ResponseWriter, r *ldap.DeleteRequest, c
 				t := i.(time.Time)
 				l.Duration = time.Now().Sub(t).Milliseconds()
 			}
+			events.Update(d.eh, l)
 		}()
 		m.RequestCounter.WithLabel(d.config.Info.Name, "delete").Add(1)
 		m.LastRequest.WithLabel(d.config.Info.Name).Set(float64(time.Now().Unix()))
@@ -291,6 +306,7 @@ func (d *Directory) serveModifyDn(rw ldap.ResponseWriter, r *ldap.ModifyDNReques
 				t := i.(time.Time)
 				l.Duration = time.Now().Sub(t).Milliseconds()
 			}
+			events.Update(d.eh, l)
 		}()
 		m.RequestCounter.WithLabel(d.config.Info.Name, "modifyDN").Add(1)
 		m.LastRequest.WithLabel(d.config.Info.Name).Set(float64(time.Now().Unix()))
@@ -322,6 +338,7 @@ func (d *Directory) serveCompare(rw ldap.ResponseWriter, r *ldap.CompareRequest,
 				t := i.(time.Time)
 				l.Duration = time.Now().Sub(t).Milliseconds()
 			}
+			events.Update(d.eh, l)
 		}()
 		m.RequestCounter.WithLabel(d.config.Info.Name, "compare").Add(1)
 		m.LastRequest.WithLabel(d.config.Info.Name).Set(float64(time.Now().Unix()))
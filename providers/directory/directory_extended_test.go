@@ -0,0 +1,189 @@
+package directory_test
+
+import (
+	"context"
+	"mokapi/engine/enginetest"
+	"mokapi/ldap"
+	"mokapi/ldap/ldaptest"
+	"mokapi/providers/directory"
+	"mokapi/runtime/events"
+	"mokapi/runtime/events/eventstest"
+	"mokapi/runtime/monitor"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectory_ServeExtended(t *testing.T) {
+	testcases := []struct {
+		name string
+		fn   func(t *testing.T, h ldap.Handler, ctx context.Context, eh *eventstest.Handler, cfg *directory.Config)
+	}{
+		{
+			name: "who am i anonymous",
+			fn: func(t *testing.T, h ldap.Handler, ctx context.Context, eh *eventstest.Handler, _ *directory.Config) {
+				rr := ldaptest.NewRecorder()
+				h.ServeLDAP(rr, ldaptest.NewRequestWithContext(0, &ldap.ExtendedRequest{Name: ldap.WhoAmIOID}, ctx))
+				res := rr.Message.(*ldap.ExtendedResponse)
+				require.Equal(t, ldap.Success, res.ResultCode)
+				require.Equal(t, "", string(res.Value))
+
+				e := eh.GetEvents(events.NewTraits().With("operation", "whoami"))
+				require.Len(t, e, 1)
+				require.Equal(t, "WhoAmI", e[0].Data.(*directory.ExtendedLog).Request.Operation)
+			},
+		},
+		{
+			name: "who am i after bind",
+			fn: func(t *testing.T, h ldap.Handler, ctx context.Context, eh *eventstest.Handler, _ *directory.Config) {
+				rr := ldaptest.NewRecorder()
+				h.ServeLDAP(rr, ldaptest.NewRequestWithContext(0, &ldap.BindRequest{Version: 3, Name: "cn=alice", Password: "secret"}, ctx))
+				require.Equal(t, ldap.Success, rr.Message.(*ldap.BindResponse).Result)
+
+				h.ServeLDAP(rr, ldaptest.NewRequestWithContext(1, &ldap.ExtendedRequest{Name: ldap.WhoAmIOID}, ctx))
+				res := rr.Message.(*ldap.ExtendedResponse)
+				require.Equal(t, ldap.Success, res.ResultCode)
+				require.Equal(t, "dn:cn=alice", string(res.Value))
+			},
+		},
+		{
+			name: "modify password of bound user",
+			fn: func(t *testing.T, h ldap.Handler, ctx context.Context, eh *eventstest.Handler, cfg *directory.Config) {
+				rr := ldaptest.NewRecorder()
+				h.ServeLDAP(rr, ldaptest.NewRequestWithContext(0, &ldap.BindRequest{Version: 3, Name: "cn=alice", Password: "secret"}, ctx))
+
+				pm := &ldap.PasswordModifyRequest{OldPassword: "secret", NewPassword: "changed"}
+				h.ServeLDAP(rr, ldaptest.NewRequestWithContext(1, &ldap.ExtendedRequest{Name: ldap.PasswordModifyOID, Value: pm.Encode()}, ctx))
+				res := rr.Message.(*ldap.ExtendedResponse)
+				require.Equal(t, ldap.Success, res.ResultCode)
+				require.Nil(t, res.Value)
+
+				e, _ := cfg.Entries.Get("cn=alice")
+				require.Equal(t, []string{"changed"}, e.Attributes["userPassword"])
+
+				h.ServeLDAP(rr, ldaptest.NewRequestWithContext(2, &ldap.BindRequest{Version: 3, Name: "cn=alice", Password: "changed"}, ctx))
+				require.Equal(t, ldap.Success, rr.Message.(*ldap.BindResponse).Result)
+
+				evts := eh.GetEvents(events.NewTraits().With("operation", "passwordmodify"))
+				require.Len(t, evts, 1)
+				l := evts[0].Data.(*directory.ExtendedLog)
+				require.Equal(t, "changed", l.Request.NewPassword)
+				require.Equal(t, "Success", l.Response.Status)
+			},
+		},
+		{
+			name: "modify password generates new password",
+			fn: func(t *testing.T, h ldap.Handler, ctx context.Context, eh *eventstest.Handler, cfg *directory.Config) {
+				rr := ldaptest.NewRecorder()
+				h.ServeLDAP(rr, ldaptest.NewRequestWithContext(0, &ldap.BindRequest{Version: 3, Name: "cn=alice", Password: "secret"}, ctx))
+				require.Equal(t, ldap.Success, rr.Message.(*ldap.BindResponse).Result)
+
+				rr = ldaptest.NewRecorder()
+				pm := &ldap.PasswordModifyRequest{UserIdentity: "dn:cn=alice"}
+				h.ServeLDAP(rr, ldaptest.NewRequestWithContext(1, &ldap.ExtendedRequest{Name: ldap.PasswordModifyOID, Value: pm.Encode()}, ctx))
+				res := rr.Message.(*ldap.ExtendedResponse)
+				require.Equal(t, ldap.Success, res.ResultCode)
+
+				gen, err := ldap.DecodePasswordModifyResponse(res.Value)
+				require.NoError(t, err)
+				require.NotEmpty(t, gen.GenPassword)
+
+				e, _ := cfg.Entries.Get("cn=alice")
+				require.Equal(t, []string{gen.GenPassword}, e.Attributes["userPassword"])
+			},
+		},
+		{
+			name: "modify password with wrong old password",
+			fn: func(t *testing.T, h ldap.Handler, ctx context.Context, eh *eventstest.Handler, cfg *directory.Config) {
+				rr := ldaptest.NewRecorder()
+				pm := &ldap.PasswordModifyRequest{UserIdentity: "cn=alice", OldPassword: "wrong", NewPassword: "changed"}
+				h.ServeLDAP(rr, ldaptest.NewRequestWithContext(0, &ldap.ExtendedRequest{Name: ldap.PasswordModifyOID, Value: pm.Encode()}, ctx))
+				res := rr.Message.(*ldap.ExtendedResponse)
+				require.Equal(t, ldap.InvalidCredentials, res.ResultCode)
+
+				e, _ := cfg.Entries.Get("cn=alice")
+				require.Equal(t, []string{"secret"}, e.Attributes["userPassword"])
+			},
+		},
+		{
+			name: "modify password of other user without bind",
+			fn: func(t *testing.T, h ldap.Handler, ctx context.Context, eh *eventstest.Handler, cfg *directory.Config) {
+				rr := ldaptest.NewRecorder()
+				pm := &ldap.PasswordModifyRequest{UserIdentity: "dn:cn=alice", NewPassword: "changed"}
+				h.ServeLDAP(rr, ldaptest.NewRequestWithContext(0, &ldap.ExtendedRequest{Name: ldap.PasswordModifyOID, Value: pm.Encode()}, ctx))
+				res := rr.Message.(*ldap.ExtendedResponse)
+				require.Equal(t, ldap.InsufficientAccessRights, res.ResultCode)
+
+				e, _ := cfg.Entries.Get("cn=alice")
+				require.Equal(t, []string{"secret"}, e.Attributes["userPassword"])
+			},
+		},
+		{
+			name: "modify password of other user with old password",
+			fn: func(t *testing.T, h ldap.Handler, ctx context.Context, eh *eventstest.Handler, cfg *directory.Config) {
+				rr := ldaptest.NewRecorder()
+				pm := &ldap.PasswordModifyRequest{UserIdentity: "dn:cn=alice", OldPassword: "secret", NewPassword: "changed"}
+				h.ServeLDAP(rr, ldaptest.NewRequestWithContext(0, &ldap.ExtendedRequest{Name: ldap.PasswordModifyOID, Value: pm.Encode()}, ctx))
+				res := rr.Message.(*ldap.ExtendedResponse)
+				require.Equal(t, ldap.Success, res.ResultCode)
+
+				e, _ := cfg.Entries.Get("cn=alice")
+				require.Equal(t, []string{"changed"}, e.Attributes["userPassword"])
+			},
+		},
+		{
+			name: "modify password without identity",
+			fn: func(t *testing.T, h ldap.Handler, ctx context.Context, eh *eventstest.Handler, _ *directory.Config) {
+				rr := ldaptest.NewRecorder()
+				pm := &ldap.PasswordModifyRequest{NewPassword: "changed"}
+				h.ServeLDAP(rr, ldaptest.NewRequestWithContext(0, &ldap.ExtendedRequest{Name: ldap.PasswordModifyOID, Value: pm.Encode()}, ctx))
+				res := rr.Message.(*ldap.ExtendedResponse)
+				require.Equal(t, ldap.UnwillingToPerform, res.ResultCode)
+			},
+		},
+		{
+			name: "modify password of unknown entry",
+			fn: func(t *testing.T, h ldap.Handler, ctx context.Context, eh *eventstest.Handler, _ *directory.Config) {
+				rr := ldaptest.NewRecorder()
+				pm := &ldap.PasswordModifyRequest{UserIdentity: "cn=bob", NewPassword: "changed"}
+				h.ServeLDAP(rr, ldaptest.NewRequestWithContext(0, &ldap.ExtendedRequest{Name: ldap.PasswordModifyOID, Value: pm.Encode()}, ctx))
+				res := rr.Message.(*ldap.ExtendedResponse)
+				require.Equal(t, ldap.NoSuchObject, res.ResultCode)
+			},
+		},
+		{
+			name: "start tls is logged",
+			fn: func(t *testing.T, h ldap.Handler, ctx context.Context, eh *eventstest.Handler, _ *directory.Config) {
+				h.StartTLS(ctx, &ldap.ExtendedResponse{ResultCode: ldap.Success, Name: ldap.StartTLSOID})
+
+				e := eh.GetEvents(events.NewTraits().With("operation", "starttls"))
+				require.Len(t, e, 1)
+				require.Equal(t, "Success", e[0].Data.(*directory.ExtendedLog).Response.Status)
+			},
+		},
+		{
+			name: "unsupported extended operation",
+			fn: func(t *testing.T, h ldap.Handler, ctx context.Context, eh *eventstest.Handler, _ *directory.Config) {
+				rr := ldaptest.NewRecorder()
+				h.ServeLDAP(rr, ldaptest.NewRequestWithContext(0, &ldap.ExtendedRequest{Name: "1.2.3"}, ctx))
+				res := rr.Message.(*ldap.ExtendedResponse)
+				require.Equal(t, ldap.ProtocolError, res.ResultCode)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &directory.Config{
+				Info: directory.Info{Name: "foo"},
+				Entries: convert(map[string]directory.Entry{
+					"cn=alice": {Dn: "cn=alice", Attributes: map[string][]string{"userPassword": {"secret"}}},
+				}),
+			}
+			eh := &eventstest.Handler{}
+			ctx := ldap.NewAuthContext(monitor.NewLdapContext(context.Background(), monitor.NewLdap()))
+			h := directory.NewHandler(cfg, enginetest.NewEngine(), eh)
+			tc.fn(t, h, ctx, eh, cfg)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package directory
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"mokapi/ldap"
+	"mokapi/runtime/events"
+	"mokapi/runtime/monitor"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (d *Directory) serveExtended(rw ldap.ResponseWriter, r *ldap.ExtendedRequest, ctx context.Context) {
+	var req *ExtendedRequest
+	var res *ldap.ExtendedResponse
+	switch r.Name {
+	case ldap.WhoAmIOID:
+		req = &ExtendedRequest{Operation: "WhoAmI", Name: r.Name}
+		res = d.whoAmI(ctx)
+	case ldap.PasswordModifyOID:
+		pm, err := ldap.DecodePasswordModifyRequest(r.Value)
+		if err != nil {
+			req = &ExtendedRequest{Operation: "PasswordModify", Name: r.Name}
+			res = &ldap.ExtendedResponse{ResultCode: ldap.ProtocolError, Message: err.Error()}
+			break
+		}
+		req = &ExtendedRequest{
+			Operation:    "PasswordModify",
+			Name:         r.Name,
+			UserIdentity: pm.UserIdentity,
+			OldPassword:  pm.OldPassword,
+			NewPassword:  pm.NewPassword,
+		}
+		res = d.passwordModify(pm, ctx)
+	default:
+		log.Debugf("unsupported LDAP extended operation: %v", r.Name)
+		rw.Write(&ldap.ExtendedResponse{
+			ResultCode: ldap.ProtocolError,
+			Message:    fmt.Sprintf("unsupported extended operation %v", r.Name),
+		})
+		return
+	}
+
+	d.logExtended(req, res, ctx)
+	rw.Write(res)
+}
+
+func (d *Directory) StartTLS(ctx context.Context, res *ldap.ExtendedResponse) {
+	d.logExtended(&ExtendedRequest{Operation: "StartTLS", Name: ldap.StartTLSOID}, res, ctx)
+}
+
+// whoAmI returns the authorization identity of the connection as
+// "dn:<dn>" or an empty value for anonymous connections (RFC 4532)
+func (d *Directory) whoAmI(ctx context.Context) *ldap.ExtendedResponse {
+	res := &ldap.ExtendedResponse{ResultCode: ldap.Success, Value: []byte{}}
+	if a := ldap.AuthFromContext(ctx); a != nil && a.Dn != "" {
+		res.Value = []byte("dn:" + a.Dn)
+	}
+	return res
+}
+
+// passwordModify changes the password of the bound user or, if the old
+// password is given and matches, of the user given by the user identity
+// (RFC 3062)
+func (d *Directory) passwordModify(r *ldap.PasswordModifyRequest, ctx context.Context) *ldap.ExtendedResponse {
+	var bound string
+	if a := ldap.AuthFromContext(ctx); a != nil {
+		bound = a.Dn
+	}
+	dn := strings.TrimPrefix(r.UserIdentity, "dn:")
+	if dn == "" {
+		dn = bound
+	}
+	if dn == "" {
+		return &ldap.ExtendedResponse{
+			ResultCode: ldap.UnwillingToPerform,
+			Message:    "no user identity given and connection is not authenticated",
+		}
+	}
+
+	e := d.getEntry(dn)
+	if e == nil {
+		return &ldap.ExtendedResponse{
+			ResultCode: ldap.NoSuchObject,
+			MatchedDn:  dn,
+			Message:    fmt.Sprintf("entry '%v' not found", dn),
+		}
+	}
+	if r.OldPassword != "" {
+		if pw, ok := e.Attributes["userPassword"]; ok && len(pw) > 0 && pw[0] != r.OldPassword {
+			return &ldap.ExtendedResponse{
+				ResultCode: ldap.InvalidCredentials,
+				MatchedDn:  dn,
+				Message:    "old password does not match",
+			}
+		}
+	}
+	if !strings.EqualFold(dn, bound) && !hasPassword(e, r.OldPassword) {
+		return &ldap.ExtendedResponse{
+			ResultCode: ldap.InsufficientAccessRights,
+			MatchedDn:  dn,
+			Message:    fmt.Sprintf("not allowed to modify password of '%v'", dn),
+		}
+	}
+
+	newPassword := r.NewPassword
+	var value []byte
+	if newPassword == "" {
+		newPassword = rand.Text()
+		value = (&ldap.PasswordModifyResponse{GenPassword: newPassword}).Encode()
+	}
+
+	mod := &ModifyRecord{
+		Dn: dn,
+		Actions: []*ModifyAction{
+			{
+				Type:       "replace",
+				Name:       "userPassword",
+				Attributes: map[string][]string{"userPassword": {newPassword}},
+			},
+		},
+	}
+	if err := mod.Apply(d.config.Entries, d.config.Schema); err != nil {
+		code := ldap.OperationsError
+		var ee *EntryError
+		if errors.As(err, &ee) {
+			code = ee.Code
+		}
+		return &ldap.ExtendedResponse{ResultCode: code, MatchedDn: dn, Message: err.Error()}
+	}
+
+	return &ldap.ExtendedResponse{ResultCode: ldap.Success, MatchedDn: dn, Value: value}
+}
+
+func hasPassword(e *Entry, password string) bool {
+	pw, ok := e.Attributes["userPassword"]
+	return ok && len(pw) > 0 && password != "" && pw[0] == password
+}
+
+func (d *Directory) logExtended(req *ExtendedRequest, res *ldap.ExtendedResponse, ctx context.Context) {
+	m, doMonitor := monitor.LdapFromContext(ctx)
+	if !doMonitor {
+		return
+	}
+
+	l := NewExtendedLogEvent(req, res, d.eh, events.NewTraits().WithName(d.config.Info.Name))
+	if i := ctx.Value("time"); i != nil {
+		l.Duration = time.Now().Sub(i.(time.Time)).Milliseconds()
+		events.Update(d.eh, l)
+	}
+	operation := strings.ToLower(req.Operation)
+	m.RequestCounter.WithLabel(d.config.Info.Name, operation).Add(1)
+	m.LastRequest.WithLabel(d.config.Info.Name).Set(float64(time.Now().Unix()))
+}
@@ -5,6 +5,7 @@ import (
 	"mokapi/engine/common"
 	"mokapi/ldap"
 	"mokapi/runtime/events"
+	"strings"
 )
 
 type SearchLog struct {
@@ -317,6 +318,54 @@ func NewCompareLogEvent(req *ldap.CompareRequest, res *ldap.CompareResponse, eh
 
 }
 
+type ExtendedLog struct {
+	Request  *ExtendedRequest  `json:"request"`
+	Response *ExtendedResponse `json:"response"`
+	Duration int64             `json:"duration"`
+	Actions  []*common.Action  `json:"actions"`
+}
+
+type ExtendedRequest struct {
+	Operation    string `json:"operation"`
+	Name         string `json:"name"`
+	UserIdentity string `json:"userIdentity,omitempty"`
+	OldPassword  string `json:"oldPassword,omitempty"`
+	NewPassword  string `json:"newPassword,omitempty"`
+}
+
+type ExtendedResponse struct {
+	Status    string `json:"status"`
+	MatchedDn string `json:"matchedDn,omitempty"`
+	Message   string `json:"message"`
+	Value     string `json:"value,omitempty"`
+}
+
+func NewExtendedLogEvent(req *ExtendedRequest, res *ldap.ExtendedResponse, eh events.Handler, traits events.Traits) *ExtendedLog {
+	response := &ExtendedResponse{
+		Status:    ldap.StatusText[res.ResultCode],
+		MatchedDn: res.MatchedDn,
+		Message:   res.Message,
+	}
+	// the generated password is BER encoded, the dashboard shows it as plain text
+	if req.Name == ldap.PasswordModifyOID {
+		if pm, err := ldap.DecodePasswordModifyResponse(res.Value); err == nil {
+			response.Value = pm.GenPassword
+		}
+	} else {
+		response.Value = string(res.Value)
+	}
+
+	l := &ExtendedLog{
+		Request:  req,
+		Response: response,
+		Duration: 0,
+		Actions:  nil,
+	}
+	_ = eh.Push(l, traits.WithNamespace("ldap").With("operation", strings.ToLower(req.Operation)))
+
+	return l
+}
+
 func (l *BindLog) Title() string {
 	return fmt.Sprintf("%s %s %s", l.Request.Auth, l.Request.Name, l.Request.Password)
 }
@@ -355,6 +404,13 @@ func (l *DeleteLog) Title() string {
 	return fmt.Sprintf("%s %s", l.Request.Operation, l.Request.Dn)
 }
 
+func (l *ExtendedLog) Title() string {
+	if l.Request.UserIdentity != "" {
+		return fmt.Sprintf("%s %s", l.Request.Operation, l.Request.UserIdentity)
+	}
+	return l.Request.Operation
+}
+
 func (l *AddLog) Title() string {
 	return fmt.Sprintf("%s %s", l.Request.Operation, l.Request.Dn)
 }
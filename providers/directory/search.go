@@ -74,6 +74,7 @@ func (d *Directory) serveSearch(rw ldap.ResponseWriter, r *ldap.Request) {
 			t := i.(time.Time)
 			event.Duration = time.Now().Sub(t).Milliseconds()
 		}
+		events.Update(d.eh, event)
 	}()
 
 	log.Infof("ldap search request: messageId=%v, Scope=%v BaseDN=%v Filter=%v",
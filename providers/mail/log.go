@@ -5,6 +5,7 @@ import (
 	"mokapi/engine/common"
 	"mokapi/runtime/events"
 	"mokapi/smtp"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -17,6 +18,17 @@ type Log struct {
 	Duration  int64            `json:"duration"`
 	Error     string           `json:"error"`
 	Actions   []*common.Action `json:"actions"`
+	Session   *Session         `json:"session,omitempty"`
+}
+
+// Session describes a client session of a mailbox retrieval protocol like
+// POP3. Retrieved and Deleted contain message IDs.
+type Session struct {
+	Protocol  string   `json:"protocol"`
+	Client    string   `json:"client"`
+	Mailbox   string   `json:"mailbox"`
+	Retrieved []string `json:"retrieved,omitempty"`
+	Deleted   []string `json:"deleted,omitempty"`
 }
 
 func NewLogEvent(msg *smtp.Message, ctx *smtp.ClientContext, eh events.Handler, traits events.Traits) *Log {
@@ -42,7 +54,19 @@ func NewLogEvent(msg *smtp.Message, ctx *smtp.ClientContext, eh events.Handler,
 	return event
 }
 
+func NewSessionLogEvent(session *Session, eh events.Handler, traits events.Traits) *Log {
+	event := &Log{
+		To:      []string{session.Mailbox},
+		Session: session,
+	}
+	_ = eh.Push(event, traits.WithNamespace("mail"))
+	return event
+}
+
 func (l *Log) Title() string {
+	if l.Session != nil {
+		return fmt.Sprintf("%s session %s", strings.ToUpper(l.Session.Protocol), l.Session.Mailbox)
+	}
 	return fmt.Sprintf("%s", l.Subject)
 }
 
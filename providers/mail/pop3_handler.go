@@ -0,0 +1,220 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"mokapi/pop3"
+	"mokapi/runtime/events"
+	"net/textproto"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+func (h *Handler) Authorize(username string, verify func(password string) bool, ctx context.Context) error {
+	c := pop3.ClientFromContext(ctx)
+	for _, m := range h.store.Mailboxes {
+		if m.Username == username && verify(m.Password) {
+			c.Session["mailbox"] = m
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid credentials")
+}
+
+func (h *Handler) Maildrop(ctx context.Context) ([]pop3.Message, error) {
+	c := pop3.ClientFromContext(ctx)
+	mb := c.Session["mailbox"].(*Mailbox)
+	mb.EnsureInbox()
+
+	mb.m.Lock()
+	defer mb.m.Unlock()
+
+	inbox := mb.Folders["INBOX"]
+	mails := map[string]*Mail{}
+	messages := make([]pop3.Message, 0, len(inbox.Messages))
+	for _, m := range inbox.Messages {
+		uid := fmt.Sprintf("%d.%d", inbox.uidValidity, m.UId)
+		mails[uid] = m
+		messages = append(messages, pop3.Message{Uid: uid, Data: renderMail(m)})
+	}
+	c.Session["mails"] = mails
+
+	return messages, nil
+}
+
+func (h *Handler) UpdateMaildrop(deleted []string, ctx context.Context) error {
+	c := pop3.ClientFromContext(ctx)
+	mb := c.Session["mailbox"].(*Mailbox)
+	mails := c.Session["mails"].(map[string]*Mail)
+
+	mb.m.Lock()
+	defer mb.m.Unlock()
+
+	inbox := mb.Folders["INBOX"]
+	for _, uid := range deleted {
+		if m, ok := mails[uid]; ok {
+			inbox.Remove(m)
+		}
+	}
+	return nil
+}
+
+func (h *Handler) CloseSession(ctx context.Context) {
+	c := pop3.ClientFromContext(ctx)
+	mb, ok := c.Session["mailbox"].(*Mailbox)
+	if !ok {
+		return
+	}
+	mails, _ := c.Session["mails"].(map[string]*Mail)
+
+	session := &Session{
+		Protocol:  "pop3",
+		Client:    c.Addr,
+		Mailbox:   mb.Name,
+		Retrieved: messageIds(c.Retrieved, mails),
+		Deleted:   messageIds(c.Deleted, mails),
+	}
+	NewSessionLogEvent(session, h.eh, events.NewTraits().WithName(h.config.Info.Name))
+}
+
+func messageIds(uids []string, mails map[string]*Mail) []string {
+	var ids []string
+	for _, uid := range uids {
+		if m, ok := mails[uid]; ok {
+			ids = append(ids, m.MessageId)
+		}
+	}
+	return ids
+}
+
+// renderMail writes the message in internet message format. Transfer
+// encodings are not kept when a mail is received, so the body is encoded
+// again: quoted-printable for text and base64 for attachments.
+func renderMail(m *Mail) []byte {
+	var b bytes.Buffer
+
+	writeHeader := func(name, value string) {
+		if value != "" {
+			b.WriteString(fmt.Sprintf("%s: %s\r\n", name, value))
+		}
+	}
+
+	writeHeader("Date", m.Date.Format("Mon, 02 Jan 2006 15:04:05 -0700"))
+	writeHeader("Subject", m.Subject)
+	writeHeader("From", addressListToString(m.From))
+	if m.Sender != nil {
+		writeHeader("Sender", addressToString(*m.Sender))
+	}
+	writeHeader("To", addressListToString(m.To))
+	writeHeader("Cc", addressListToString(m.Cc))
+	writeHeader("Reply-To", addressListToString(m.ReplyTo))
+	writeHeader("Message-ID", m.MessageId)
+	writeHeader("In-Reply-To", m.InReplyTo)
+
+	var names []string
+	for name := range m.Headers {
+		if !isRenderedHeader(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeHeader(name, m.Headers[name])
+	}
+	writeHeader("MIME-Version", "1.0")
+
+	contentType := m.ContentType
+	if contentType == "" {
+		contentType = "text/plain; charset=UTF-8"
+	}
+
+	if len(m.Attachments) == 0 {
+		writeHeader("Content-Type", contentType)
+		writeBody(&b, m.Body)
+		return b.Bytes()
+	}
+
+	mw := multipart.NewWriter(&b)
+	writeHeader("Content-Type", fmt.Sprintf("multipart/mixed; boundary=%s", mw.Boundary()))
+	b.WriteString("\r\n")
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	part, _ := mw.CreatePart(header)
+	qp := quotedprintable.NewWriter(part)
+	_, _ = qp.Write([]byte(m.Body))
+	_ = qp.Close()
+
+	for _, att := range m.Attachments {
+		header = textproto.MIMEHeader{}
+		for k, v := range att.Headers() {
+			header.Set(k, v)
+		}
+		header.Set("Content-Transfer-Encoding", "base64")
+		if att.Disposition == "" {
+			header.Set("Content-Disposition", "attachment")
+		}
+		part, _ = mw.CreatePart(header)
+		writeBase64(part, att.Data)
+	}
+	_ = mw.Close()
+
+	return b.Bytes()
+}
+
+func writeBody(b *bytes.Buffer, body string) {
+	if isPlainText(body) {
+		b.WriteString("\r\n")
+		b.WriteString(body)
+		return
+	}
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	qp := quotedprintable.NewWriter(b)
+	_, _ = qp.Write([]byte(body))
+	_ = qp.Close()
+}
+
+func writeBase64(w io.Writer, data []byte) {
+	s := base64.StdEncoding.EncodeToString(data)
+	for len(s) > 76 {
+		_, _ = w.Write([]byte(s[:76] + "\r\n"))
+		s = s[76:]
+	}
+	_, _ = w.Write([]byte(s + "\r\n"))
+}
+
+// isPlainText reports whether the body can be sent without a transfer
+// encoding (7bit, RFC 2045).
+func isPlainText(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, line := range strings.Split(s, "\n") {
+		if len(line) > 998 {
+			return false
+		}
+		for _, r := range line {
+			if r > 127 || (r < 32 && r != '\t' && r != '\r') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isRenderedHeader(name string) bool {
+	switch strings.ToLower(name) {
+	case "date", "subject", "from", "sender", "to", "cc", "bcc", "reply-to", "message-id", "in-reply-to",
+		"mime-version", "content-type", "content-transfer-encoding":
+		return true
+	default:
+		return false
+	}
+}
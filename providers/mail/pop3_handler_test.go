@@ -0,0 +1,200 @@
+package mail_test
+
+import (
+	"context"
+	"mokapi/engine/enginetest"
+	"mokapi/pop3"
+	"mokapi/providers/mail"
+	"mokapi/runtime/events/eventstest"
+	"mokapi/smtp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPop3Handler(t *testing.T) {
+	cfg := func() *mail.Config {
+		return &mail.Config{
+			Info: mail.Info{Name: "test"},
+			Mailboxes: map[string]*mail.MailboxConfig{
+				"alice@mokapi.io": {
+					Username: "alice",
+					Password: "foo",
+				},
+			},
+		}
+	}
+	password := func(s string) func(string) bool {
+		return func(stored string) bool { return stored == s }
+	}
+	date := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		name string
+		cfg  *mail.Config
+		test func(t *testing.T, h *mail.Handler, s *mail.Store, eh *eventstest.Handler, ctx context.Context)
+	}{
+		{
+			name: "authorize successfully",
+			cfg:  cfg(),
+			test: func(t *testing.T, h *mail.Handler, s *mail.Store, eh *eventstest.Handler, ctx context.Context) {
+				err := h.Authorize("alice", password("foo"), ctx)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "authorize failed",
+			cfg:  cfg(),
+			test: func(t *testing.T, h *mail.Handler, s *mail.Store, eh *eventstest.Handler, ctx context.Context) {
+				err := h.Authorize("alice", password("bar"), ctx)
+				require.EqualError(t, err, "invalid credentials")
+			},
+		},
+		{
+			name: "empty maildrop",
+			cfg:  cfg(),
+			test: func(t *testing.T, h *mail.Handler, s *mail.Store, eh *eventstest.Handler, ctx context.Context) {
+				_ = h.Authorize("alice", password("foo"), ctx)
+				messages, err := h.Maildrop(ctx)
+				require.NoError(t, err)
+				require.Len(t, messages, 0)
+			},
+		},
+		{
+			name: "maildrop contains inbox messages",
+			cfg:  cfg(),
+			test: func(t *testing.T, h *mail.Handler, s *mail.Store, eh *eventstest.Handler, ctx context.Context) {
+				s.Mailboxes["alice@mokapi.io"].Append(&smtp.Message{
+					MessageId: "<1@mokapi.io>",
+					Date:      date,
+					From:      []smtp.Address{{Address: "bob@mokapi.io"}},
+					To:        []smtp.Address{{Name: "Alice", Address: "alice@mokapi.io"}},
+					Subject:   "Hello Alice",
+					Body:      "Hello",
+				})
+
+				_ = h.Authorize("alice", password("foo"), ctx)
+				messages, err := h.Maildrop(ctx)
+				require.NoError(t, err)
+				require.Len(t, messages, 1)
+				require.Equal(t, "Date: Sat, 01 Mar 2025 12:00:00 +0000\r\n"+
+					"Subject: Hello Alice\r\n"+
+					"From: bob@mokapi.io\r\n"+
+					"To: Alice <alice@mokapi.io>\r\n"+
+					"Message-ID: <1@mokapi.io>\r\n"+
+					"MIME-Version: 1.0\r\n"+
+					"Content-Type: text/plain; charset=UTF-8\r\n"+
+					"\r\n"+
+					"Hello", string(messages[0].Data))
+			},
+		},
+		{
+			name: "non ASCII body is quoted-printable encoded",
+			cfg:  cfg(),
+			test: func(t *testing.T, h *mail.Handler, s *mail.Store, eh *eventstest.Handler, ctx context.Context) {
+				s.Mailboxes["alice@mokapi.io"].Append(&smtp.Message{
+					MessageId:   "<1@mokapi.io>",
+					Date:        date,
+					ContentType: "text/plain; charset=UTF-8",
+					Body:        "Grüße",
+				})
+
+				_ = h.Authorize("alice", password("foo"), ctx)
+				messages, err := h.Maildrop(ctx)
+				require.NoError(t, err)
+				require.Contains(t, string(messages[0].Data), "Content-Transfer-Encoding: quoted-printable\r\n\r\nGr=C3=BC=C3=9Fe")
+			},
+		},
+		{
+			name: "attachments are sent as multipart",
+			cfg:  cfg(),
+			test: func(t *testing.T, h *mail.Handler, s *mail.Store, eh *eventstest.Handler, ctx context.Context) {
+				s.Mailboxes["alice@mokapi.io"].Append(&smtp.Message{
+					MessageId: "<1@mokapi.io>",
+					Date:      date,
+					Body:      "see attachment",
+					Attachments: []smtp.Attachment{
+						{Name: "foo.txt", ContentType: "text/plain", Disposition: "attachment; filename=foo.txt", Data: []byte("foo")},
+					},
+				})
+
+				_ = h.Authorize("alice", password("foo"), ctx)
+				messages, err := h.Maildrop(ctx)
+				require.NoError(t, err)
+				data := string(messages[0].Data)
+				require.Contains(t, data, "Content-Type: multipart/mixed; boundary=")
+				require.Contains(t, data, "Content-Disposition: attachment; filename=foo.txt\r\n")
+				require.Contains(t, data, "\r\n\r\nZm9v\r\n")
+			},
+		},
+		{
+			name: "update maildrop removes deleted messages",
+			cfg:  cfg(),
+			test: func(t *testing.T, h *mail.Handler, s *mail.Store, eh *eventstest.Handler, ctx context.Context) {
+				mb := s.Mailboxes["alice@mokapi.io"]
+				mb.Append(&smtp.Message{MessageId: "<1@mokapi.io>", Date: date})
+				mb.Append(&smtp.Message{MessageId: "<2@mokapi.io>", Date: date})
+
+				_ = h.Authorize("alice", password("foo"), ctx)
+				messages, err := h.Maildrop(ctx)
+				require.NoError(t, err)
+
+				err = h.UpdateMaildrop([]string{messages[0].Uid}, ctx)
+				require.NoError(t, err)
+				require.Len(t, mb.Folders["INBOX"].Messages, 1)
+				require.Equal(t, "<2@mokapi.io>", mb.Folders["INBOX"].Messages[0].MessageId)
+			},
+		},
+		{
+			name: "session is logged",
+			cfg:  cfg(),
+			test: func(t *testing.T, h *mail.Handler, s *mail.Store, eh *eventstest.Handler, ctx context.Context) {
+				mb := s.Mailboxes["alice@mokapi.io"]
+				mb.Append(&smtp.Message{MessageId: "<1@mokapi.io>", Date: date})
+
+				_ = h.Authorize("alice", password("foo"), ctx)
+				messages, _ := h.Maildrop(ctx)
+				c := pop3.ClientFromContext(ctx)
+				c.Retrieved = []string{messages[0].Uid}
+				c.Deleted = []string{messages[0].Uid}
+
+				h.CloseSession(ctx)
+
+				require.Len(t, eh.Events, 1)
+				require.Equal(t, "mail", eh.Events[0].Traits.GetNamespace())
+				require.Equal(t, "test", eh.Events[0].Traits.GetName())
+				l := eh.Events[0].Data.(*mail.Log)
+				require.Equal(t, "POP3 session alice@mokapi.io", l.Title())
+				require.Equal(t, &mail.Session{
+					Protocol:  "pop3",
+					Client:    "127.0.0.1:84793",
+					Mailbox:   "alice@mokapi.io",
+					Retrieved: []string{"<1@mokapi.io>"},
+					Deleted:   []string{"<1@mokapi.io>"},
+				}, l.Session)
+			},
+		},
+		{
+			name: "no session is logged without authorization",
+			cfg:  cfg(),
+			test: func(t *testing.T, h *mail.Handler, s *mail.Store, eh *eventstest.Handler, ctx context.Context) {
+				h.CloseSession(ctx)
+				require.Len(t, eh.Events, 0)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := pop3.NewClientContext(context.Background(), "127.0.0.1:84793")
+			s := mail.NewStore(tc.cfg)
+			eh := &eventstest.Handler{}
+			h := mail.NewHandler(tc.cfg, s, enginetest.NewEngine(), eh)
+			tc.test(t, h, s, eh, ctx)
+		})
+	}
+}
@@ -60,6 +60,7 @@ func (h *Handler) processMail(rw smtp.ResponseWriter, r *smtp.DataRequest) {
 			t := i.(time.Time)
 			event.Duration = time.Now().Sub(t).Milliseconds()
 		}
+		events.Update(h.eh, event)
 	}()
 
 	if res := h.config.Rules.runMail(r.Message); res != nil {
@@ -0,0 +1,86 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"mokapi/config/dynamic"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Callbacks map[string]*CallbackRef
+
+type CallbackRef struct {
+	dynamic.Reference[*CallbackRef]
+	Value Callback
+}
+
+// Callback is a map of runtime expressions to path items. The expression
+// is evaluated at runtime to identify the URL to use for the callback
+// request, e.g. {$request.body#/callbackUrl}
+type Callback map[string]*PathRef
+
+// WebhookTrigger is a Mokapi extension to send a webhook defined in the
+// top-level webhooks after the operation's response was written.
+type WebhookTrigger struct {
+	// Name of the webhook
+	Name string `yaml:"name" json:"name"`
+
+	// Url of the receiver. It may contain runtime expressions
+	// like {$request.body#/callbackUrl}
+	Url string `yaml:"url" json:"url"`
+}
+
+func (r *CallbackRef) UnmarshalJSON(b []byte) error {
+	return r.Reference.UnmarshalJson(b, &r.Value)
+}
+
+func (r *CallbackRef) MarshalJSON() ([]byte, error) {
+	if r.Value != nil {
+		return json.Marshal(r.Value)
+	}
+	return json.Marshal(r.Reference)
+}
+
+func (r *CallbackRef) UnmarshalYAML(node *yaml.Node) error {
+	return r.Reference.UnmarshalYaml(node, &r.Value)
+}
+
+func (c Callbacks) Parse(config *dynamic.Config, reader dynamic.Reader) error {
+	for name, cb := range c {
+		if err := cb.Parse(config, reader); err != nil {
+			return fmt.Errorf("parse callback '%v' failed: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (r *CallbackRef) Parse(config *dynamic.Config, reader dynamic.Reader) error {
+	if r == nil {
+		return nil
+	}
+
+	if len(r.Ref) > 0 {
+		resolved, err := r.Resolve(config, reader)
+		if err != nil {
+			return err
+		}
+		r.Value = resolved.Value
+		return nil
+	}
+
+	return PathItems(r.Value).Parse(config, reader)
+}
+
+func (c Callbacks) patch(patch Callbacks) {
+	for k, p := range patch {
+		if p == nil || p.Value == nil {
+			continue
+		}
+		if v, ok := c[k]; ok && v != nil && v.Value != nil {
+			PathItems(v.Value).patch(PathItems(p.Value))
+		} else {
+			c[k] = p
+		}
+	}
+}
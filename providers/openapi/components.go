@@ -15,6 +15,7 @@ type Components struct {
 	Headers         Headers             `yaml:"headers,omitempty" json:"headers,omitempty"`
 	PathItems       PathItems           `yaml:"pathItems,omitempty" json:"pathItems,omitempty"`
 	SecuritySchemes SecuritySchemes     `yaml:"securitySchemes,omitempty" json:"securitySchemes,omitempty"`
+	Callbacks       Callbacks           `yaml:"callbacks,omitempty" json:"callbacks,omitempty"`
 }
 
 type ComponentParameters map[string]*ParameterRef
@@ -64,6 +65,11 @@ func (c *Components) patch(patch Components) {
 	} else {
 		c.SecuritySchemes.patch(patch.SecuritySchemes)
 	}
+	if c.Callbacks == nil {
+		c.Callbacks = patch.Callbacks
+	} else {
+		c.Callbacks.patch(patch.Callbacks)
+	}
 }
 
 func (p ComponentParameters) patch(patch ComponentParameters) {
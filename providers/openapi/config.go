@@ -60,6 +60,11 @@ type Config struct {
 	// server objects url field in order to construct the full URL
 	Paths PathItems `yaml:"paths,omitempty" json:"paths,omitempty"`
 
+	// The incoming webhooks that MAY be received as part of this API.
+	// The key is a unique name and the path item describes the request
+	// that is sent to the receiver.
+	Webhooks PathItems `yaml:"webhooks,omitempty" json:"webhooks,omitempty"`
+
 	Security []SecurityRequirement `yaml:"security" json:"security"`
 
 	Components Components `yaml:"components,omitempty" json:"components,omitempty"`
@@ -67,6 +72,14 @@ type Config struct {
 	ExternalDocs *ExternalDocs `yaml:"externalDocs,omitempty" json:"externalDocs,omitempty"`
 
 	Tags []*Tag `yaml:"tags,omitempty" json:"tags,omitempty"`
+
+	// StrictSecurity is a Mokapi extension to reject requests which
+	// do not satisfy the security requirements
+	StrictSecurity bool `yaml:"x-strictSecurity,omitempty" json:"x-strictSecurity,omitempty"`
+
+	// Proxy is a Mokapi extension to forward requests to an upstream
+	// server and to record and replay the exchanges
+	Proxy *ProxyConfig `yaml:"x-proxy,omitempty" json:"x-proxy,omitempty"`
 }
 
 type Error struct {
@@ -115,6 +128,10 @@ func (c *Config) Validate() (err error) {
 		}
 	}
 
+	if errProxy := c.Proxy.validate(); errProxy != nil {
+		err = errors.Join(err, errProxy)
+	}
+
 	/*if feature.IsEnabled("openapi-validation") {
 		p := &parser.Parser{}
 		_, errParse := p.Parse(c, validation_schema)
@@ -133,7 +150,10 @@ func (c *Config) Parse(config *dynamic.Config, reader dynamic.Reader) error {
 
 	config.Scope.OpenIfNeeded(config.Info.Path())
 
-	return c.Paths.Parse(config, reader)
+	if err := c.Paths.Parse(config, reader); err != nil {
+		return err
+	}
+	return c.Webhooks.Parse(config, reader)
 }
 
 func (c *Config) Patch(patch *Config) {
@@ -144,6 +164,11 @@ func (c *Config) Patch(patch *Config) {
 	} else {
 		c.Paths.patch(patch.Paths)
 	}
+	if c.Webhooks == nil {
+		c.Webhooks = patch.Webhooks
+	} else {
+		c.Webhooks.patch(patch.Webhooks)
+	}
 	c.Components.patch(patch.Components)
 
 	if c.Security == nil {
@@ -154,6 +179,14 @@ func (c *Config) Patch(patch *Config) {
 		}
 	}
 
+	if patch.StrictSecurity {
+		c.StrictSecurity = true
+	}
+
+	if patch.Proxy != nil {
+		c.Proxy = patch.Proxy
+	}
+
 	for _, pt := range patch.Tags {
 		var st *Tag
 		for _, tag := range c.Tags {
@@ -3,13 +3,16 @@ package openapi
 import (
 	"context"
 	"fmt"
+	"maps"
 	"math/rand"
 	"mokapi/engine/common"
 	"mokapi/media"
 	"mokapi/providers/openapi/schema"
 	"mokapi/schema/json/generator"
+	protobuf "mokapi/schema/protobuf/schema"
 	"net/http"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -88,11 +91,17 @@ func NewEventRequest(r *http.Request, contentType media.ContentType, api string)
 	return req, context.WithValue(ctx, eventKey, req)
 }
 
-func setResponseData(r *common.HttpEventResponse, m *MediaType, request *common.HttpEventRequest) error {
+func setResponseData(r *common.HttpEventResponse, m *MediaType, request *common.HttpEventRequest, opts dataOptions) error {
 	if m != nil {
 		if len(m.Examples) > 0 {
-			keys := reflect.ValueOf(m.Examples).MapKeys()
-			key := keys[rand.Intn(len(keys))].String()
+			var key string
+			if opts.seed != 0 {
+				names := slices.Sorted(maps.Keys(m.Examples))
+				key = names[rand.New(rand.NewSource(opts.seed)).Intn(len(names))]
+			} else {
+				keys := reflect.ValueOf(m.Examples).MapKeys()
+				key = keys[rand.Intn(len(keys))].String()
+			}
 			v := m.Examples[key]
 			if v.Value != nil {
 				r.Data = v.Value.Value
@@ -110,11 +119,16 @@ func setResponseData(r *common.HttpEventResponse, m *MediaType, request *common.
 			}
 		}
 
+		js := schema.ConvertToJsonSchema(m.Schema)
+		if m.hasProtobuf() {
+			js = protobuf.ConvertToJsonSchema(m.Protobuf.Value)
+		}
 		req := generator.NewRequest(
 			names,
-			schema.ConvertToJsonSchema(m.Schema),
+			js,
 			getGeneratorContext(request),
 		)
+		opts.apply(req)
 		data, err := generator.New(req)
 		if err != nil {
 			return fmt.Errorf("generate response data failed: %v", err)
@@ -125,6 +139,45 @@ func setResponseData(r *common.HttpEventResponse, m *MediaType, request *common.
 	return nil
 }
 
+// dataOptions controls how response data is generated
+type dataOptions struct {
+	seed int64
+	// locale is the Accept-Language header of the request
+	locale string
+}
+
+func newDataOptions(op *Operation, request *common.HttpEventRequest, r *http.Request) dataOptions {
+	return dataOptions{
+		seed:   generatorSeed(op, request),
+		locale: r.Header.Get("Accept-Language"),
+	}
+}
+
+func (o dataOptions) apply(r *generator.Request) {
+	r.Seed = o.seed
+	r.Locale = o.locale
+}
+
+// generatorSeed returns the seed for generated response data or zero
+// if the data should be random. The seed is derived from the API, the
+// endpoint path and the path parameters but not from the method, so
+// GET and PUT /pets/12 return the same pet.
+func generatorSeed(op *Operation, request *common.HttpEventRequest) int64 {
+	deterministic := generator.Deterministic()
+	if op != nil && op.Deterministic != nil {
+		deterministic = *op.Deterministic
+	}
+	if !deterministic {
+		return 0
+	}
+
+	parts := []string{request.Api, request.Key}
+	for _, name := range slices.Sorted(maps.Keys(request.Path)) {
+		parts = append(parts, name, fmt.Sprintf("%v", request.Path[name]))
+	}
+	return generator.SeedFromKey(parts...)
+}
+
 func setResponseHeader(r *common.HttpEventResponse, headers Headers) error {
 	for k, v := range headers {
 		if v.Value == nil {
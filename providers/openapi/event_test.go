@@ -1,11 +1,13 @@
 package openapi_test
 
 import (
+	"mokapi/config/static"
 	"mokapi/engine/enginetest"
 	"mokapi/media"
 	"mokapi/providers/openapi"
 	"mokapi/providers/openapi/openapitest"
 	"mokapi/runtime/events"
+	"mokapi/schema/json/generator"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -97,3 +99,103 @@ func TestEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestEvent_Deterministic(t *testing.T) {
+	config := `
+openapi: 3.1.0
+info:
+  title: Test
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    get:
+      responses:
+        '200':
+          description: pet
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+                  age:
+                    type: integer
+    put:
+      responses:
+        '200':
+          description: pet
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+                  age:
+                    type: integer
+  /random:
+    get:
+      x-deterministic: false
+      responses:
+        '200':
+          description: number
+          content:
+            application/json:
+              schema:
+                type: number
+`
+
+	generator.SetConfig(static.DataGen{Deterministic: true})
+	defer generator.SetConfig(static.DataGen{})
+
+	h := openapi.NewHandler(parseConfig(t, config), enginetest.NewEngine(), &events.StoreManager{})
+	serve := func(method, path string) string {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(method, "http://localhost"+path, nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	require.Equal(t, serve(http.MethodGet, "/pets/12"), serve(http.MethodGet, "/pets/12"))
+	require.Equal(t, serve(http.MethodGet, "/pets/12"), serve(http.MethodPut, "/pets/12"))
+	require.NotEqual(t, serve(http.MethodGet, "/pets/12"), serve(http.MethodGet, "/pets/13"))
+	// operation overrides the global setting
+	require.NotEqual(t, serve(http.MethodGet, "/random"), serve(http.MethodGet, "/random"))
+}
+
+func TestEvent_AcceptLanguage(t *testing.T) {
+	config := `
+openapi: 3.1.0
+info:
+  title: Test
+paths:
+  /contact:
+    get:
+      responses:
+        '200':
+          description: contact
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  phone:
+                    type: string
+                required: [ phone ]
+`
+
+	h := openapi.NewHandler(parseConfig(t, config), enginetest.NewEngine(), &events.StoreManager{})
+	r := httptest.NewRequest(http.MethodGet, "http://localhost/contact", nil)
+	r.Header.Set("Accept-Language", "de-CH, de;q=0.9, en;q=0.8")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, r)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Regexp(t, `^\{"phone":"\+49[0-9]+"\}$`, rr.Body.String())
+}
@@ -2,6 +2,8 @@ package openapi
 
 import (
 	"context"
+	"crypto/rsa"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -9,8 +11,10 @@ import (
 	"mokapi/engine/common"
 	"mokapi/lib"
 	"mokapi/media"
+	"mokapi/oauth2"
 	"mokapi/runtime/events"
 	"mokapi/runtime/monitor"
+	"mokapi/schema/encoding"
 	"net/http"
 	"regexp"
 	"slices"
@@ -28,21 +32,58 @@ type operationHandler struct {
 	config *Config
 	next   http.Handler
 	eh     events.Handler
+	oauth2 *oauth2.Server
 }
 
 type responseHandler struct {
-	config       *Config
-	eventEmitter common.EventEmitter
+	config         *Config
+	eventEmitter   common.EventEmitter
+	eh             events.Handler
+	strictSecurity bool
+	signingKey     *rsa.PrivateKey
+	codes          *oauth2.CodeStore
 }
 
-func NewHandler(config *Config, eventEmitter common.EventEmitter, eh events.Handler) Handler {
+type HandlerOptions func(h *responseHandler)
+
+// WithStrictSecurity rejects requests that do not satisfy the security
+// requirements instead of only logging them.
+func WithStrictSecurity(strict bool) HandlerOptions {
+	return func(h *responseHandler) {
+		h.strictSecurity = strict
+	}
+}
+
+// WithSigningKey enables the built-in OAuth2 authorization server for
+// the OAuth2 security schemes. Issued tokens are signed with the key.
+func WithSigningKey(key *rsa.PrivateKey) HandlerOptions {
+	return func(h *responseHandler) {
+		h.signingKey = key
+	}
+}
+
+// WithCodeStore shares the issued authorization codes of the built-in
+// OAuth2 authorization server between handlers of the same API.
+func WithCodeStore(codes *oauth2.CodeStore) HandlerOptions {
+	return func(h *responseHandler) {
+		h.codes = codes
+	}
+}
+
+func NewHandler(config *Config, eventEmitter common.EventEmitter, eh events.Handler, opts ...HandlerOptions) Handler {
+	next := &responseHandler{
+		config:       config,
+		eventEmitter: eventEmitter,
+		eh:           eh,
+	}
+	for _, opt := range opts {
+		opt(next)
+	}
 	return &operationHandler{
 		config: config,
-		next: &responseHandler{
-			config:       config,
-			eventEmitter: eventEmitter,
-		},
-		eh: eh,
+		next:   next,
+		eh:     eh,
+		oauth2: newOAuth2Server(config, next.signingKey, next.codes),
 	}
 }
 
@@ -108,6 +149,16 @@ func (h *responseHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	request, ctx := NewEventRequest(r, contentType, h.config.Info.Name)
 	r = r.WithContext(ctx)
 
+	var rawBody []byte
+	useProxy := h.config.Proxy.handles(true)
+	if useProxy {
+		rawBody, err = readRequestBody(r)
+		if err != nil {
+			writeError(rw, r, newHttpErrorf(http.StatusBadRequest, "read request body failed: %v", err), h.config.Info.Name)
+			return
+		}
+	}
+
 	if op.RequestBody != nil {
 		body, err := BodyFromRequest(r, op)
 		if body != nil {
@@ -127,16 +178,29 @@ func (h *responseHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 		drainRequestBody(r)
 	}
 
-	if len(op.Security) > 0 {
-		h.serveSecurity(r, op.Security)
-	} else if len(h.config.Security) > 0 {
-		h.serveSecurity(r, h.config.Security)
+	security := op.Security
+	if len(security) == 0 {
+		security = h.config.Security
+	}
+	if err = h.serveSecurity(r, security); err != nil {
+		writeError(rw, r, err, h.config.Info.Name)
+		return
+	}
+
+	if useProxy {
+		if logHttp != nil && logHttp.Request.Body == "" {
+			logHttp.Request.Body = string(rawBody)
+		}
+		if serveProxy(rw, r, h.config, op, rawBody, logHttp) {
+			return
+		}
 	}
 
 	response := NewEventResponse(status, contentType)
-	setResponseRebuild(response, request, op)
+	dataOpts := newDataOptions(op, request, r)
+	setResponseRebuild(response, request, op, dataOpts)
 
-	err = setResponseData(response, mediaType, request)
+	err = setResponseData(response, mediaType, request, dataOpts)
 	if err != nil {
 		writeError(rw, r, err, h.config.Info.Name)
 		return
@@ -176,6 +240,16 @@ func (h *responseHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	streamType := contentType
+	if ct, err := getContentType(response.Headers); ct != "" && err == nil {
+		streamType = media.ParseContentType(ct)
+	}
+	if isStreaming(streamType) && response.Body == "" {
+		h.writeStream(rw, r, response, streamType, res.GetContent(streamType), request, dataOpts, logHttp)
+		h.sendCallbacks(r, op, request, response)
+		return
+	}
+
 	if len(res.Content) == 0 {
 		if response.Body == "" {
 			// no response content and no response body is defined which means body is empty
@@ -200,6 +274,7 @@ func (h *responseHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 				logHttp.Response.Size = len(response.Body)
 			}
 		}
+		h.sendCallbacks(r, op, request, response)
 		return
 	}
 
@@ -232,6 +307,13 @@ func (h *responseHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 			if contentType.Subtype == "*" {
 				contentType = media.ParseContentType(http.DetectContentType(b))
 			}
+		} else if mediaType.hasProtobuf() {
+			body, err = mediaType.Protobuf.marshal(response.Data, contentType)
+			if err != nil {
+				err = fmt.Errorf("HTTP body marshalling failed.\n\nBody: %v\n\n%w", lib.PrettyPrint(response.Data), err)
+				writeError(rw, r, err, h.config.Info.Name)
+				return
+			}
 		} else {
 			body, err = mediaType.Schema.Marshal(response.Data, contentType)
 			if err != nil {
@@ -256,23 +338,25 @@ func (h *responseHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	if logHttp != nil {
 		logHttp.Response.Body = string(body)
 		logHttp.Response.Size = len(body)
+		if mediaType.hasProtobuf() && encoding.IsProtobuf(contentType) && response.Data != nil {
+			// show binary protobuf body as JSON in the dashboard
+			if b, err := json.Marshal(response.Data); err == nil {
+				logHttp.Response.Body = string(b)
+			}
+		}
 	}
+
+	h.sendCallbacks(r, op, request, response)
 }
 
 func (h *operationHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) *HttpError {
-	requestPath := r.URL.Path
-	if len(requestPath) > 1 {
-		requestPath = strings.TrimRight(requestPath, "/")
-	}
+	requestPath, servicePath := getRequestPath(r)
 
-	servicePath, ok := r.Context().Value("servicePath").(string)
-	if ok && servicePath != "/" {
-		servicePath = strings.TrimRight(servicePath, "/")
-		requestPath = strings.Replace(requestPath, servicePath, "", 1)
-		if requestPath == "" {
-			requestPath = "/"
-		}
+	if h.oauth2 != nil && h.oauth2.Match(requestPath, r.URL.Path) {
+		h.oauth2.ServeHTTP(rw, r, issuerUrl(r, servicePath), requestPath)
+		return nil
 	}
+
 	op, errOpResolve := findOperation(r.Method, requestPath, h.config.Paths)
 	if op != nil {
 		var params Parameters
@@ -297,6 +381,10 @@ func (h *operationHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) *H
 			if m, ok := monitor.HttpFromContext(r.Context()); ok {
 				m.LastRequest.WithLabel(h.config.Info.Name, op.Path.Path, r.Method).Set(float64(time.Now().Unix()))
 				m.RequestCounter.WithLabel(h.config.Info.Name, op.Path.Path, r.Method).Add(1)
+				start := time.Now()
+				defer func() {
+					m.RequestDuration.WithLabel(h.config.Info.Name, op.Path.Path, r.Method).Observe(time.Since(start).Seconds())
+				}()
 			}
 
 			traits := events.NewTraits().WithNamespace("http").WithName(h.config.Info.Name).With("path", op.Path.Path).With("method", r.Method)
@@ -335,6 +423,9 @@ func (h *operationHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) *H
 	}
 
 	if errOpResolve == nil {
+		if h.config.Proxy.handles(false) && h.serveUnmatchedProxy(rw, r) {
+			return nil
+		}
 		return newHttpErrorf(http.StatusNotFound, "no matching endpoint found: %v %v", strings.ToUpper(r.Method), lib.GetUrl(r)).
 			WithTraits(events.NewTraits().WithNamespace("http").WithName(h.config.Info.Name)).
 			WithRecorder(record)
@@ -350,6 +441,39 @@ func (h *operationHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) *H
 	}
 }
 
+// getRequestPath returns the request path relative to the
+// path of the service and the service path itself.
+func getRequestPath(r *http.Request) (string, string) {
+	requestPath := r.URL.Path
+	if len(requestPath) > 1 {
+		requestPath = strings.TrimRight(requestPath, "/")
+	}
+
+	servicePath, ok := r.Context().Value("servicePath").(string)
+	if ok && servicePath != "/" {
+		servicePath = strings.TrimRight(servicePath, "/")
+		requestPath = strings.Replace(requestPath, servicePath, "", 1)
+		if requestPath == "" {
+			requestPath = "/"
+		}
+	}
+	return requestPath, servicePath
+}
+
+// issuerUrl returns the base URL of the API which is used as the
+// issuer of the built-in OAuth2 authorization server.
+func issuerUrl(r *http.Request, servicePath string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	host := r.Host
+	if host == "" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, host, strings.TrimRight(servicePath, "/"))
+}
+
 func writeError(rw http.ResponseWriter, r *http.Request, err error, serviceName string) {
 	message := err.Error()
 	status := http.StatusInternalServerError
@@ -394,31 +518,77 @@ func writeError(rw http.ResponseWriter, r *http.Request, err error, serviceName
 	}
 }
 
-func (h *responseHandler) serveSecurity(r *http.Request, requirements []SecurityRequirement) {
+func (h *responseHandler) serveSecurity(r *http.Request, requirements []SecurityRequirement) error {
+	if len(requirements) == 0 {
+		return nil
+	}
+
+	request := EventRequestFromContext(r.Context())
 	var errs error
+	var challenges []string
+	forbidden := false
 	for _, req := range requirements {
 		var reqError error
-		for name := range req {
+		authenticated := true
+		names := make([]string, 0, len(req))
+		for name, scopes := range req {
+			names = append(names, name)
 			scheme, ok := h.config.Components.SecuritySchemes[name]
-			if !ok {
+			if !ok || scheme == nil {
 				log.Warnf("%s: no security scheme found for %v", r.URL.String(), name)
+				reqError = errors.Join(reqError, fmt.Errorf("security scheme '%s' not defined", name))
+				authenticated = false
+				continue
+			}
+			if c := scheme.challenge(h.config.Info.Name); c != "" && !slices.Contains(challenges, c) {
+				challenges = append(challenges, c)
 			}
-			err := scheme.Serve(r)
+			err := h.serveScheme(r, scheme, scopes)
 			var notSupported *NotSupportedSecuritySchemeError
 			if errors.As(err, &notSupported) {
 				log.Warn(notSupported.Error())
 			} else if err != nil {
 				reqError = errors.Join(reqError, fmt.Errorf("security scheme name '%s' type '%s': %w", name, getSecuritySchemeType(scheme), err))
+				if !errors.Is(err, errInsufficientScope) {
+					authenticated = false
+				}
 			}
 		}
 		if reqError == nil {
-			return
+			slices.Sort(names)
+			request.Security = names
+			return nil
 		}
+		request.Principal = ""
+		forbidden = forbidden || authenticated
 		errs = errors.Join(errs, reqError)
 	}
-	if errs != nil {
+
+	if !h.strictSecurity && !h.config.StrictSecurity {
 		log.Infof("%s: security requirement skipped: %v", r.URL.String(), errs.Error())
+		return nil
+	}
+
+	status := http.StatusUnauthorized
+	if forbidden {
+		status = http.StatusForbidden
+	}
+	err := newHttpErrorf(status, "security requirement not satisfied: %v", errs.Error())
+	if len(challenges) > 0 {
+		err.Header = http.Header{"Www-Authenticate": challenges}
+	}
+	return err
+}
+
+// serveScheme verifies access tokens issued by the built-in authorization
+// server of this API with its signing key and issuer. Tokens of other
+// servers are not verified.
+func (h *responseHandler) serveScheme(r *http.Request, scheme SecurityScheme, scopes []string) error {
+	if o, ok := scheme.(*OAuth2SecurityScheme); ok && h.signingKey != nil && o.isServedBy(r.Host) {
+		_, servicePath := getRequestPath(r)
+		return o.serve(r, scopes, &h.signingKey.PublicKey, issuerUrl(r, servicePath))
 	}
+	return scheme.Serve(r, scopes)
 }
 
 func findOperation(method, requestPath string, paths PathItems) (*Operation, error) {
@@ -497,7 +667,7 @@ func drainRequestBody(r *http.Request) {
 	}
 }
 
-func setResponseRebuild(response *common.HttpEventResponse, request *common.HttpEventRequest, op *Operation) {
+func setResponseRebuild(response *common.HttpEventResponse, request *common.HttpEventRequest, op *Operation, opts dataOptions) {
 	response.Rebuild = func(statusCode int, contentType string) {
 		res := op.Responses.GetResponse(statusCode)
 		if res == nil {
@@ -527,7 +697,7 @@ func setResponseRebuild(response *common.HttpEventResponse, request *common.Http
 		response.StatusCode = statusCode
 		response.Headers = map[string]any{}
 
-		err := setResponseData(response, mediaType, request)
+		err := setResponseData(response, mediaType, request, opts)
 		if err != nil {
 			panic(err)
 		}
@@ -0,0 +1,270 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"mokapi/engine/common"
+	"mokapi/lib"
+	"mokapi/media"
+	"mokapi/runtime/events"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	callbackKindCallback = "callback"
+	callbackKindWebhook  = "webhook"
+)
+
+const (
+	callbackWorkers   = 8
+	callbackQueueSize = 1000
+)
+
+var callbackClient = &http.Client{Timeout: 30 * time.Second}
+
+// callbacks are sent by a fixed number of workers, so that a burst of
+// requests does not start an unbounded number of goroutines
+var callbacks = &callbackQueue{jobs: make(chan func(), callbackQueueSize)}
+
+type callbackQueue struct {
+	jobs chan func()
+	once sync.Once
+}
+
+// enqueue adds the job to the queue and reports false if the queue is full
+func (q *callbackQueue) enqueue(job func()) bool {
+	q.once.Do(func() {
+		for i := 0; i < callbackWorkers; i++ {
+			go func() {
+				for job := range q.jobs {
+					job()
+				}
+			}()
+		}
+	})
+
+	select {
+	case q.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+type callbackRequest struct {
+	kind    string
+	name    string
+	url     string
+	method  string
+	op      *Operation
+	trigger *HttpCallbackLog
+	request *common.HttpEventRequest
+}
+
+// sendCallbacks evaluates the callbacks and webhooks of the operation
+// and sends them in the background.
+func (h *responseHandler) sendCallbacks(r *http.Request, op *Operation, request *common.HttpEventRequest, response *common.HttpEventResponse) {
+	if len(op.Callbacks) == 0 && len(op.Webhooks) == 0 {
+		return
+	}
+
+	var eventId string
+	if l, ok := LogEventFromContext(r.Context()); ok {
+		eventId = l.EventId()
+	}
+
+	ctx := &expressionContext{r: r, request: request, response: response}
+	// the key is used for the data generation of the response, not needed here
+	data := *request
+	data.Key = ""
+
+	var requests []*callbackRequest
+	add := func(kind, name, url string, p *PathRef) {
+		if p == nil || p.Value == nil {
+			return
+		}
+		for _, method := range slices.Sorted(maps.Keys(p.Value.Operations())) {
+			requests = append(requests, &callbackRequest{
+				kind:   kind,
+				name:   name,
+				url:    url,
+				method: method,
+				op:     p.Value.Operations()[method],
+				trigger: &HttpCallbackLog{
+					EventId: eventId,
+					Type:    kind,
+					Name:    name,
+					Method:  r.Method,
+					Url:     lib.GetUrl(r),
+				},
+				request: &data,
+			})
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(op.Callbacks)) {
+		ref := op.Callbacks[name]
+		if ref == nil {
+			continue
+		}
+		for _, expr := range slices.Sorted(maps.Keys(ref.Value)) {
+			u, err := evalExpressionTemplate(expr, ctx)
+			if err != nil {
+				log.Errorf("resolve callback '%v' failed: %v", name, err)
+				continue
+			}
+			add(callbackKindCallback, name, u, ref.Value[expr])
+		}
+	}
+
+	for _, w := range op.Webhooks {
+		p, ok := h.config.Webhooks[w.Name]
+		if !ok {
+			log.Errorf("webhook '%v' not defined", w.Name)
+			continue
+		}
+		u, err := evalExpressionTemplate(w.Url, ctx)
+		if err != nil {
+			log.Errorf("resolve webhook '%v' failed: %v", w.Name, err)
+			continue
+		}
+		add(callbackKindWebhook, w.Name, u, p)
+	}
+
+	for _, cr := range requests {
+		h.scheduleCallback(cr)
+	}
+}
+
+// scheduleCallback queues the callback after its delay. A timer does not
+// occupy a worker while waiting.
+func (h *responseHandler) scheduleCallback(cr *callbackRequest) {
+	enqueue := func() {
+		if !callbacks.enqueue(func() { h.sendCallback(cr) }) {
+			log.Errorf("send %v '%v' skipped: too many pending callbacks", cr.kind, cr.name)
+		}
+	}
+
+	if cr.op.Delay != "" {
+		d, err := time.ParseDuration(cr.op.Delay)
+		if err != nil {
+			log.Warnf("invalid delay '%v' for %v '%v': %v", cr.op.Delay, cr.kind, cr.name, err)
+		} else {
+			time.AfterFunc(d, enqueue)
+			return
+		}
+	}
+	enqueue()
+}
+
+func (h *responseHandler) sendCallback(cr *callbackRequest) {
+	if cr.url == "" {
+		log.Errorf("send %v '%v' failed: URL is empty", cr.kind, cr.name)
+		return
+	}
+
+	body, contentType, err := callbackBody(cr.op, cr.request)
+	if err != nil {
+		log.Errorf("send %v '%v' failed: %v", cr.kind, cr.name, err)
+		return
+	}
+
+	req, err := http.NewRequest(cr.method, cr.url, bytes.NewReader(body))
+	if err != nil {
+		log.Errorf("send %v '%v' failed: %v", cr.kind, cr.name, err)
+		return
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	path := ""
+	if cr.op.Path != nil {
+		path = cr.op.Path.Path
+	}
+	l := &HttpLog{
+		Request: &HttpRequestLog{
+			Method:      cr.method,
+			Url:         cr.url,
+			ContentType: contentType,
+			Body:        string(body),
+		},
+		Response: &HttpResponseLog{Headers: make(map[string]string)},
+		Api:      h.config.Info.Name,
+		Path:     path,
+		Callback: cr.trigger,
+	}
+
+	start := time.Now()
+	res, err := callbackClient.Do(req)
+	l.Duration = time.Now().Sub(start).Milliseconds()
+	if err != nil {
+		log.Errorf("send %v '%v' to %v failed: %v", cr.kind, cr.name, cr.url, err)
+	} else {
+		b, _ := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		l.Response.StatusCode = res.StatusCode
+		l.Response.Body = string(b)
+		l.Response.Size = len(b)
+		for k, v := range res.Header {
+			l.Response.Headers[k] = strings.Join(v, ",")
+		}
+		log.Infof("sent %v '%v' %v %v: %v", cr.kind, cr.name, cr.method, cr.url, res.StatusCode)
+	}
+
+	if h.eh == nil {
+		return
+	}
+	traits := events.NewTraits().
+		WithNamespace("http").
+		WithName(h.config.Info.Name).
+		With("path", path).
+		With("method", cr.method)
+	if err = h.eh.Push(l, traits); err != nil {
+		log.Errorf("failed to log http event: %v", err)
+	}
+}
+
+// callbackBody returns the request body of the callback operation. JSON is
+// preferred if the request body defines multiple content types.
+func callbackBody(op *Operation, request *common.HttpEventRequest) ([]byte, string, error) {
+	if op.RequestBody == nil || op.RequestBody.Value == nil || len(op.RequestBody.Value.Content) == 0 {
+		return nil, "", nil
+	}
+
+	content := op.RequestBody.Value.Content
+	keys := slices.Sorted(maps.Keys(content))
+	key := keys[0]
+	for _, k := range keys {
+		if ct := media.ParseContentType(k); ct.Subtype == "json" || strings.HasSuffix(ct.Subtype, "+json") {
+			key = k
+			break
+		}
+	}
+	mt := content[key]
+	ct := media.ParseContentType(key)
+	if mt == nil {
+		return nil, ct.String(), nil
+	}
+
+	res := &common.HttpEventResponse{}
+	if err := setResponseData(res, mt, request, dataOptions{}); err != nil {
+		return nil, "", err
+	}
+	if b, ok := res.Data.([]byte); ok {
+		return b, ct.String(), nil
+	}
+	b, err := mt.Schema.Marshal(res.Data, ct)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshal request body failed: %w", err)
+	}
+	return b, ct.String(), nil
+}
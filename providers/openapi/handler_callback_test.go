@@ -0,0 +1,249 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mokapi/config/dynamic"
+	"mokapi/providers/openapi"
+	"mokapi/runtime/events"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type receivedRequest struct {
+	method      string
+	path        string
+	contentType string
+	body        string
+}
+
+func newReceiver(t *testing.T) (*httptest.Server, chan receivedRequest) {
+	ch := make(chan receivedRequest, 10)
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		ch <- receivedRequest{
+			method:      r.Method,
+			path:        r.URL.RequestURI(),
+			contentType: r.Header.Get("Content-Type"),
+			body:        string(b),
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(s.Close)
+	return s, ch
+}
+
+func receive(t *testing.T, ch chan receivedRequest) receivedRequest {
+	select {
+	case r := <-ch:
+		return r
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "callback not received")
+	}
+	return receivedRequest{}
+}
+
+func parseConfig(t *testing.T, s string) *openapi.Config {
+	c := &openapi.Config{}
+	require.NoError(t, yaml.Unmarshal([]byte(s), c))
+	require.NoError(t, c.Parse(&dynamic.Config{Data: c}, nil))
+	return c
+}
+
+func TestHandler_Callbacks(t *testing.T) {
+	testcases := []struct {
+		name   string
+		config string
+		test   func(t *testing.T, h openapi.Handler, receiver string, ch chan receivedRequest, sm *events.StoreManager)
+	}{
+		{
+			name: "callback url from request body",
+			config: `
+openapi: 3.1.0
+info:
+  title: Test
+paths:
+  /subscribe:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                callbackUrl:
+                  type: string
+      responses:
+        '201':
+          description: subscribed
+      callbacks:
+        onEvent:
+          '{$request.body#/callbackUrl}?event={$request.query.event}':
+            post:
+              requestBody:
+                content:
+                  application/json:
+                    schema:
+                      type: object
+                      properties:
+                        id:
+                          type: integer
+                      required: [ id ]
+                      additionalProperties: false
+              responses:
+                '200':
+                  description: ok
+`,
+			test: func(t *testing.T, h openapi.Handler, receiver string, ch chan receivedRequest, sm *events.StoreManager) {
+				body := fmt.Sprintf(`{"callbackUrl": "%s/events"}`, receiver)
+				r := httptest.NewRequest(http.MethodPost, "http://localhost/subscribe?event=created", strings.NewReader(body))
+				r.Header.Set("Content-Type", "application/json")
+				rr := httptest.NewRecorder()
+				h.ServeHTTP(rr, r)
+				require.Equal(t, http.StatusCreated, rr.Code)
+
+				cb := receive(t, ch)
+				require.Equal(t, http.MethodPost, cb.method)
+				require.Equal(t, "/events?event=created", cb.path)
+				require.Equal(t, "application/json", cb.contentType)
+				var data map[string]any
+				require.NoError(t, json.Unmarshal([]byte(cb.body), &data))
+				require.Contains(t, data, "id")
+
+				require.Eventually(t, func() bool {
+					return len(sm.GetEvents(events.NewTraits().WithNamespace("http").With("path", "{$request.body#/callbackUrl}?event={$request.query.event}"))) == 1
+				}, 5*time.Second, 10*time.Millisecond)
+				e := sm.GetEvents(events.NewTraits().WithNamespace("http").With("path", "{$request.body#/callbackUrl}?event={$request.query.event}"))[0]
+				l := e.Data.(*openapi.HttpLog)
+				require.Equal(t, http.StatusNoContent, l.Response.StatusCode)
+				parent := sm.GetEvents(events.NewTraits().WithNamespace("http").With("path", "/subscribe"))
+				require.Len(t, parent, 1)
+				require.Equal(t, &openapi.HttpCallbackLog{
+					EventId: parent[0].Id,
+					Type:    "callback",
+					Name:    "onEvent",
+					Method:  http.MethodPost,
+					Url:     "http://localhost/subscribe?event=created",
+				}, l.Callback)
+			},
+		},
+		{
+			name: "callback with delay from components",
+			config: `
+openapi: 3.1.0
+info:
+  title: Test
+paths:
+  /subscribe:
+    post:
+      parameters:
+        - name: X-Callback
+          in: header
+          schema:
+            type: string
+      responses:
+        '202':
+          description: accepted
+      callbacks:
+        onEvent:
+          $ref: '#/components/callbacks/Event'
+components:
+  callbacks:
+    Event:
+      '{$request.header.X-Callback}':
+        put:
+          x-delay: 200ms
+          requestBody:
+            content:
+              text/plain:
+                example: done
+          responses:
+            '200':
+              description: ok
+`,
+			test: func(t *testing.T, h openapi.Handler, receiver string, ch chan receivedRequest, _ *events.StoreManager) {
+				r := httptest.NewRequest(http.MethodPost, "http://localhost/subscribe", nil)
+				r.Header.Set("X-Callback", receiver+"/done")
+				rr := httptest.NewRecorder()
+				start := time.Now()
+				h.ServeHTTP(rr, r)
+				require.Equal(t, http.StatusAccepted, rr.Code)
+
+				cb := receive(t, ch)
+				require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+				require.Equal(t, http.MethodPut, cb.method)
+				require.Equal(t, "/done", cb.path)
+				require.Equal(t, "text/plain", cb.contentType)
+				require.Equal(t, "done", cb.body)
+			},
+		},
+		{
+			name: "webhook",
+			config: `
+openapi: 3.1.0
+info:
+  title: Test
+paths:
+  /pets:
+    post:
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: string
+                    const: '123'
+                required: [ id ]
+      x-webhooks:
+        - name: newPet
+          url: '{{RECEIVER}}/hooks/{$response.body#/id}'
+webhooks:
+  newPet:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: string
+              const: created
+      responses:
+        '200':
+          description: ok
+`,
+			test: func(t *testing.T, h openapi.Handler, receiver string, ch chan receivedRequest, _ *events.StoreManager) {
+				rr := httptest.NewRecorder()
+				h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "http://localhost/pets", nil))
+				require.Equal(t, http.StatusCreated, rr.Code)
+
+				cb := receive(t, ch)
+				require.Equal(t, http.MethodPost, cb.method)
+				require.Equal(t, "/hooks/123", cb.path)
+				require.Equal(t, `"created"`, cb.body)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			receiver, ch := newReceiver(t)
+			config := parseConfig(t, strings.ReplaceAll(tc.config, "{{RECEIVER}}", receiver.URL))
+
+			sm := events.NewStoreManager(&index{})
+			sm.SetStore(10, events.NewTraits().WithNamespace("http"))
+			h := openapi.NewHandler(config, &engine{}, sm)
+
+			tc.test(t, h, receiver.URL, ch, sm)
+		})
+	}
+}
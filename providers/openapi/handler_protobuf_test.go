@@ -0,0 +1,211 @@
+package openapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mokapi/config/dynamic"
+	"mokapi/config/dynamic/dynamictest"
+	"mokapi/engine/common"
+	"mokapi/providers/openapi"
+	"mokapi/runtime/events"
+	"mokapi/schema/json/generator"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const orderProto = `
+syntax = "proto3";
+package shop;
+message Order {
+  string id = 1;
+  int32 quantity = 2;
+}`
+
+func TestHandler_Protobuf(t *testing.T) {
+	testcases := []struct {
+		name        string
+		config      string
+		contentType string
+		body        []byte
+		emit        func(event string, args ...interface{}) []*common.Action
+		test        func(t *testing.T, rr *httptest.ResponseRecorder, l *openapi.HttpLog)
+	}{
+		{
+			name: "response data is encoded as protobuf",
+			config: `
+openapi: 3.1.0
+info:
+  title: Test
+paths:
+  /order:
+    get:
+      responses:
+        '200':
+          description: order
+          content:
+            application/x-protobuf:
+              x-protobuf:
+                $ref: ./order.proto#/Order
+`,
+			emit: func(event string, args ...interface{}) []*common.Action {
+				res := args[1].(*common.HttpEventResponse)
+				res.Data = map[string]any{"id": "a", "quantity": 150}
+				return nil
+			},
+			test: func(t *testing.T, rr *httptest.ResponseRecorder, l *openapi.HttpLog) {
+				require.Equal(t, http.StatusOK, rr.Code)
+				require.Equal(t, "application/x-protobuf", rr.Header().Get("Content-Type"))
+				require.Equal(t, []byte{0x0a, 0x01, 0x61, 0x10, 0x96, 0x01}, rr.Body.Bytes())
+				require.Equal(t, `{"id":"a","quantity":150}`, l.Response.Body)
+				require.Equal(t, 6, l.Response.Size)
+			},
+		},
+		{
+			name: "generated response data",
+			config: `
+openapi: 3.1.0
+info:
+  title: Test
+paths:
+  /order:
+    get:
+      responses:
+        '200':
+          description: order
+          content:
+            application/x-protobuf:
+              x-protobuf:
+                $ref: ./order.proto#/Order
+`,
+			test: func(t *testing.T, rr *httptest.ResponseRecorder, l *openapi.HttpLog) {
+				require.Equal(t, http.StatusOK, rr.Code)
+				require.Contains(t, l.Response.Body, `"id":`)
+			},
+		},
+		{
+			name: "response data is validated",
+			config: `
+openapi: 3.1.0
+info:
+  title: Test
+paths:
+  /order:
+    get:
+      responses:
+        '200':
+          description: order
+          content:
+            application/x-protobuf:
+              x-protobuf:
+                $ref: ./order.proto#/Order
+`,
+			emit: func(event string, args ...interface{}) []*common.Action {
+				res := args[1].(*common.HttpEventResponse)
+				res.Data = map[string]any{"id": 12}
+				return nil
+			},
+			test: func(t *testing.T, rr *httptest.ResponseRecorder, l *openapi.HttpLog) {
+				require.Equal(t, http.StatusInternalServerError, rr.Code)
+				require.Contains(t, rr.Body.String(), "message 'shop.Order': field 'id': expected string, got int")
+			},
+		},
+		{
+			name: "request body is decoded",
+			config: `
+openapi: 3.1.0
+info:
+  title: Test
+paths:
+  /order:
+    post:
+      requestBody:
+        content:
+          application/x-protobuf:
+            x-protobuf:
+              $ref: ./order.proto#/Order
+      responses:
+        '204':
+          description: created
+`,
+			contentType: "application/x-protobuf",
+			body:        []byte{0x0a, 0x01, 0x61, 0x10, 0x96, 0x01},
+			emit: func(event string, args ...interface{}) []*common.Action {
+				req := args[0].(*common.HttpEventRequest)
+				res := args[1].(*common.HttpEventResponse)
+				b, err := json.Marshal(req.Body)
+				if err != nil || string(b) != `{"id":"a","quantity":150}` {
+					res.StatusCode = http.StatusBadRequest
+				}
+				return nil
+			},
+			test: func(t *testing.T, rr *httptest.ResponseRecorder, l *openapi.HttpLog) {
+				require.Equal(t, http.StatusNoContent, rr.Code)
+			},
+		},
+		{
+			name: "invalid request body",
+			config: `
+openapi: 3.1.0
+info:
+  title: Test
+paths:
+  /order:
+    post:
+      requestBody:
+        content:
+          application/x-protobuf:
+            x-protobuf:
+              $ref: ./order.proto#/Order
+      responses:
+        '204':
+          description: created
+`,
+			contentType: "application/x-protobuf",
+			body:        []byte{0x1a, 0x05, 0x01},
+			test: func(t *testing.T, rr *httptest.ResponseRecorder, l *openapi.HttpLog) {
+				require.Equal(t, http.StatusInternalServerError, rr.Code)
+				require.Equal(t, "read request body 'application/x-protobuf' failed: message 'shop.Order': field number 3: unexpected end of data\n", rr.Body.String())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := &dynamictest.Reader{Data: map[string]*dynamic.Config{
+				"file:///order.proto": {
+					Info: dynamictest.NewConfigInfo(dynamictest.WithUrl("file:///order.proto")),
+					Raw:  []byte(orderProto),
+				},
+			}}
+			config := &openapi.Config{}
+			require.NoError(t, yaml.Unmarshal([]byte(tc.config), config))
+			u, _ := url.Parse("file:///openapi.yaml")
+			require.NoError(t, config.Parse(&dynamic.Config{Info: dynamic.ConfigInfo{Url: u}, Data: config}, reader))
+
+			generator.Seed(11)
+			sm := events.NewStoreManager(&index{})
+			sm.SetStore(10, events.NewTraits().WithNamespace("http"))
+			h := openapi.NewHandler(config, &engine{emit: tc.emit}, sm)
+
+			var method string
+			for m := range config.Paths["/order"].Value.Operations() {
+				method = m
+			}
+			r := httptest.NewRequest(method, "http://localhost/order", bytes.NewReader(tc.body))
+			if tc.contentType != "" {
+				r.Header.Set("Content-Type", tc.contentType)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+
+			e := sm.GetEvents(events.NewTraits().WithNamespace("http"))
+			require.Len(t, e, 1)
+			tc.test(t, rr, e[0].Data.(*openapi.HttpLog))
+		})
+	}
+}
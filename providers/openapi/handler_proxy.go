@@ -0,0 +1,310 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mokapi/lib"
+	"mokapi/media"
+	"mokapi/runtime/events"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ProxyModeForward = "forward"
+	ProxyModeRecord  = "record"
+	ProxyModeReplay  = "replay"
+
+	ProxyScopeAll       = "all"
+	ProxyScopeUnmatched = "unmatched"
+
+	defaultProxyTimeout = 30 * time.Second
+)
+
+var proxyClient = &http.Client{
+	// redirects are passed to the client unchanged
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// hop-by-hop headers are not forwarded, see RFC 9110 section 7.6.1
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// ProxyConfig is a Mokapi extension that forwards requests to an
+// upstream server instead of generating a response. Forwarded
+// exchanges can be recorded and replayed later without the upstream.
+type ProxyConfig struct {
+	// Url of the upstream server. The request path relative to the
+	// API's server URL is appended.
+	Url string `yaml:"url,omitempty" json:"url,omitempty"`
+
+	// Mode is one of forward (default), record or replay
+	Mode string `yaml:"mode,omitempty" json:"mode,omitempty"`
+
+	// Scope is one of all (default) or unmatched. With unmatched, only
+	// requests that do not match an operation are forwarded.
+	Scope string `yaml:"scope,omitempty" json:"scope,omitempty"`
+
+	// Recordings is the directory where exchanges are recorded to and
+	// replayed from
+	Recordings string `yaml:"recordings,omitempty" json:"recordings,omitempty"`
+
+	// Timeout of an upstream request, e.g. 10s. Default is 30s
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+type proxyResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+func (p *ProxyConfig) validate() error {
+	if p == nil {
+		return nil
+	}
+
+	var err error
+	switch p.mode() {
+	case ProxyModeForward, ProxyModeRecord:
+		if p.Url == "" {
+			err = errors.Join(err, fmt.Errorf("x-proxy: url is required in mode %v", p.mode()))
+		}
+	case ProxyModeReplay:
+	default:
+		err = errors.Join(err, fmt.Errorf("x-proxy: unsupported mode '%v'", p.Mode))
+	}
+	if (p.mode() == ProxyModeRecord || p.mode() == ProxyModeReplay) && p.Recordings == "" {
+		err = errors.Join(err, fmt.Errorf("x-proxy: recordings directory is required in mode %v", p.mode()))
+	}
+	switch p.scope() {
+	case ProxyScopeAll, ProxyScopeUnmatched:
+	default:
+		err = errors.Join(err, fmt.Errorf("x-proxy: unsupported scope '%v'", p.Scope))
+	}
+	if p.Timeout != "" {
+		if _, errParse := time.ParseDuration(p.Timeout); errParse != nil {
+			err = errors.Join(err, fmt.Errorf("x-proxy: invalid timeout '%v': %w", p.Timeout, errParse))
+		}
+	}
+	return err
+}
+
+func (p *ProxyConfig) mode() string {
+	if p.Mode == "" {
+		return ProxyModeForward
+	}
+	return strings.ToLower(p.Mode)
+}
+
+func (p *ProxyConfig) scope() string {
+	if p.Scope == "" {
+		return ProxyScopeAll
+	}
+	return strings.ToLower(p.Scope)
+}
+
+// handles reports whether a request is served by the proxy depending
+// on whether it matches an operation of the specification.
+func (p *ProxyConfig) handles(matched bool) bool {
+	if p == nil {
+		return false
+	}
+	return !matched || p.scope() == ProxyScopeAll
+}
+
+func (p *ProxyConfig) timeout() time.Duration {
+	if p.Timeout == "" {
+		return defaultProxyTimeout
+	}
+	d, err := time.ParseDuration(p.Timeout)
+	if err != nil {
+		return defaultProxyTimeout
+	}
+	return d
+}
+
+// serveProxy forwards the request to the upstream server or replays
+// a recorded response. The upstream response is validated against
+// the operation if the request matched one. It returns false if
+// no recording matches the request in replay mode.
+func serveProxy(rw http.ResponseWriter, r *http.Request, cfg *Config, op *Operation, body []byte, logHttp *HttpLog) bool {
+	p := cfg.Proxy
+	requestPath, _ := getRequestPath(r)
+	rec := newRecordedRequest(r.Method, requestPath, r.URL.RawQuery, body)
+
+	var res *proxyResponse
+	if p.mode() == ProxyModeReplay {
+		recording, err := loadRecording(p.Recordings, rec)
+		if err != nil {
+			log.Errorf("read HTTP recording for %v %v failed: %v", r.Method, lib.GetUrl(r), err)
+		}
+		if recording == nil {
+			return false
+		}
+		res = recording.Response.toResponse()
+	} else {
+		var err error
+		res, err = forward(r, p, requestPath, body)
+		if err != nil {
+			writeError(rw, r, newHttpErrorf(http.StatusBadGateway, "forward request to %v failed: %v", p.Url, err), cfg.Info.Name)
+			return true
+		}
+
+		if op != nil {
+			if err = validateProxyResponse(op, res); err != nil {
+				writeError(rw, r, newHttpErrorf(http.StatusBadGateway, "upstream response of %v does not match specification: %v", p.Url, err), cfg.Info.Name)
+				return true
+			}
+		}
+
+		if p.mode() == ProxyModeRecord {
+			if err = saveRecording(p.Recordings, rec, res); err != nil {
+				log.Errorf("record HTTP exchange for %v %v failed: %v", r.Method, lib.GetUrl(r), err)
+			}
+		}
+	}
+
+	for k, values := range res.header {
+		for _, v := range values {
+			rw.Header().Add(k, v)
+		}
+	}
+	rw.WriteHeader(res.statusCode)
+	if _, err := rw.Write(res.body); err != nil {
+		log.Errorf("write HTTP body failed for %v: %v", r.URL.String(), err)
+	}
+	if logHttp != nil {
+		logHttp.Response.StatusCode = res.statusCode
+		logHttp.Response.Body = string(res.body)
+		logHttp.Response.Size = len(res.body)
+	}
+	return true
+}
+
+// serveUnmatchedProxy serves a request that does not match any
+// operation and logs it as an event of the API.
+func (h *operationHandler) serveUnmatchedProxy(rw http.ResponseWriter, r *http.Request) bool {
+	start := time.Now()
+	body, err := readRequestBody(r)
+	if err != nil {
+		writeError(rw, r, newHttpErrorf(http.StatusBadRequest, "read request body failed: %v", err), h.config.Info.Name)
+		return true
+	}
+
+	traits := events.NewTraits().WithNamespace("http").WithName(h.config.Info.Name)
+	ctx, _ := NewLogEventContext(r, false, traits)
+	r = r.WithContext(ctx)
+	logHttp, _ := LogEventFromContext(ctx)
+	logHttp.Request.Body = string(body)
+
+	if !serveProxy(rw, r, h.config, nil, body, logHttp) {
+		return false
+	}
+
+	logHttp.Duration = time.Since(start).Milliseconds()
+	for k, v := range rw.Header() {
+		logHttp.Response.Headers[k] = strings.Join(v, ",")
+	}
+	if err = h.eh.Push(logHttp, traits); err != nil {
+		log.Errorf("failed to log http event: %v", err)
+	}
+	return true
+}
+
+func forward(r *http.Request, p *ProxyConfig, requestPath string, body []byte) (*proxyResponse, error) {
+	u := strings.TrimRight(p.Url, "/") + requestPath
+	if r.URL.RawQuery != "" {
+		u += "?" + r.URL.RawQuery
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), p.timeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, r.Method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = r.Header.Clone()
+	removeHopHeaders(req.Header)
+	// let the transport negotiate compression so the body can be validated
+	req.Header.Del("Accept-Encoding")
+	if ip := lib.ClientIP(r); ip != "" {
+		req.Header.Add("X-Forwarded-For", ip)
+	}
+
+	res, err := proxyClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body failed: %w", err)
+	}
+
+	header := res.Header.Clone()
+	removeHopHeaders(header)
+	header.Del("Content-Length")
+	if res.Uncompressed {
+		header.Del("Content-Encoding")
+	}
+
+	return &proxyResponse{statusCode: res.StatusCode, header: header, body: b}, nil
+}
+
+func validateProxyResponse(op *Operation, res *proxyResponse) error {
+	r := op.getResponse(res.statusCode)
+	if r == nil {
+		return fmt.Errorf("no configuration was found for HTTP status code %v", res.statusCode)
+	}
+	if len(r.Content) == 0 || len(res.body) == 0 {
+		return nil
+	}
+
+	ct := media.ParseContentType(res.header.Get("Content-Type"))
+	mt := r.GetContent(ct)
+	if mt == nil {
+		return fmt.Errorf("response has no definition for content type: %v", ct)
+	}
+	_, err := mt.ParseData(res.body, ct)
+	return err
+}
+
+// readRequestBody reads the request body and replaces it, so it can
+// be read again.
+func readRequestBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+func removeHopHeaders(h http.Header) {
+	for _, name := range hopHeaders {
+		h.Del(name)
+	}
+}
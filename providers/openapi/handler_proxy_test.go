@@ -0,0 +1,243 @@
+package openapi_test
+
+import (
+	"fmt"
+	"io"
+	"mokapi/providers/openapi"
+	"mokapi/runtime/events"
+	"mokapi/version"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const proxyConfig = `
+openapi: 3.1.0
+info:
+  title: Test
+x-proxy:
+  url: %v
+  mode: %v
+  scope: %v
+  recordings: %v
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+      responses:
+        '200':
+          description: pet
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+                required: [ name ]
+`
+
+func TestHandler_Proxy(t *testing.T) {
+	testcases := []struct {
+		name     string
+		upstream http.HandlerFunc
+		test     func(t *testing.T, upstream *httptest.Server, dir string)
+	}{
+		{
+			name: "forward request and validate response",
+			upstream: func(rw http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				rw.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(rw, `{"name":"%v %v %v"}`, r.URL.Path, r.URL.RawQuery, strings.ReplaceAll(string(b), `"`, `'`))
+			},
+			test: func(t *testing.T, upstream *httptest.Server, dir string) {
+				h, sm := newProxyHandler(t, upstream.URL, "forward", "all", dir)
+
+				rr := serve(h, http.MethodPost, "/pets?foo=bar", `{"id":1}`)
+				require.Equal(t, http.StatusOK, rr.Code)
+				require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+				require.Equal(t, `{"name":"/pets foo=bar {'id':1}"}`, rr.Body.String())
+
+				e := sm.GetEvents(events.NewTraits().WithNamespace("http"))
+				require.Len(t, e, 1)
+				l := e[0].Data.(*openapi.HttpLog)
+				require.Equal(t, `{"id":1}`, l.Request.Body)
+				require.Equal(t, http.StatusOK, l.Response.StatusCode)
+				require.Equal(t, rr.Body.String(), l.Response.Body)
+			},
+		},
+		{
+			name: "invalid upstream response",
+			upstream: func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set("Content-Type", "application/json")
+				_, _ = rw.Write([]byte(`{"id":1}`))
+			},
+			test: func(t *testing.T, upstream *httptest.Server, dir string) {
+				h, _ := newProxyHandler(t, upstream.URL, "forward", "all", dir)
+
+				rr := serve(h, http.MethodPost, "/pets", `{}`)
+				require.Equal(t, http.StatusBadGateway, rr.Code)
+				require.Contains(t, rr.Body.String(), "upstream response of "+upstream.URL+" does not match specification")
+			},
+		},
+		{
+			name: "upstream not available",
+			test: func(t *testing.T, upstream *httptest.Server, dir string) {
+				upstream.Close()
+				h, _ := newProxyHandler(t, upstream.URL, "forward", "all", dir)
+
+				rr := serve(h, http.MethodPost, "/pets", `{}`)
+				require.Equal(t, http.StatusBadGateway, rr.Code)
+				require.Contains(t, rr.Body.String(), "forward request to "+upstream.URL+" failed")
+			},
+		},
+		{
+			name: "record and replay",
+			upstream: func(rw http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				rw.Header().Set("Content-Type", "application/json")
+				rw.Header().Set("X-Upstream", "yes")
+				_, _ = fmt.Fprintf(rw, `{"name":"%v"}`, strings.ReplaceAll(string(b), `"`, `'`))
+			},
+			test: func(t *testing.T, upstream *httptest.Server, dir string) {
+				h, _ := newProxyHandler(t, upstream.URL, "record", "all", dir)
+				rr := serve(h, http.MethodPost, "/pets?a=1&b=2", `{"id":1,"tag":"x"}`)
+				require.Equal(t, http.StatusOK, rr.Code)
+				entries, err := os.ReadDir(dir)
+				require.NoError(t, err)
+				require.Len(t, entries, 1)
+				require.True(t, strings.HasPrefix(entries[0].Name(), "post_pets_"))
+
+				upstream.Close()
+
+				h, _ = newProxyHandler(t, upstream.URL, "replay", "all", dir)
+				// same query and body in different order and format
+				rr = serve(h, http.MethodPost, "/pets?b=2&a=1", `{ "tag": "x", "id": 1 }`)
+				require.Equal(t, http.StatusOK, rr.Code)
+				require.Equal(t, "yes", rr.Header().Get("X-Upstream"))
+				require.Equal(t, `{"name":"{'id':1,'tag':'x'}"}`, rr.Body.String())
+			},
+		},
+		{
+			name: "replay without recording falls back to mock",
+			test: func(t *testing.T, upstream *httptest.Server, dir string) {
+				h, _ := newProxyHandler(t, upstream.URL, "replay", "all", dir)
+
+				rr := serve(h, http.MethodPost, "/pets", `{}`)
+				require.Equal(t, http.StatusOK, rr.Code)
+				require.Contains(t, rr.Body.String(), `"name":`)
+			},
+		},
+		{
+			name: "scope unmatched",
+			upstream: func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(http.StatusTeapot)
+				_, _ = rw.Write([]byte("upstream " + r.URL.Path))
+			},
+			test: func(t *testing.T, upstream *httptest.Server, dir string) {
+				h, sm := newProxyHandler(t, upstream.URL, "forward", "unmatched", dir)
+
+				rr := serve(h, http.MethodPost, "/pets", `{}`)
+				require.Equal(t, http.StatusOK, rr.Code)
+				require.NotContains(t, rr.Body.String(), "upstream")
+
+				rr = serve(h, http.MethodGet, "/users", "")
+				require.Equal(t, http.StatusTeapot, rr.Code)
+				require.Equal(t, "upstream /users", rr.Body.String())
+
+				e := sm.GetEvents(events.NewTraits().WithNamespace("http"))
+				require.Len(t, e, 2)
+				require.Equal(t, http.StatusTeapot, e[0].Data.(*openapi.HttpLog).Response.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			upstream := httptest.NewServer(tc.upstream)
+			defer upstream.Close()
+
+			tc.test(t, upstream, t.TempDir())
+		})
+	}
+}
+
+func TestProxyConfig_Validate(t *testing.T) {
+	testcases := []struct {
+		name  string
+		proxy *openapi.ProxyConfig
+		err   string
+	}{
+		{
+			name:  "forward",
+			proxy: &openapi.ProxyConfig{Url: "http://localhost"},
+		},
+		{
+			name:  "url missing",
+			proxy: &openapi.ProxyConfig{},
+			err:   "x-proxy: url is required in mode forward",
+		},
+		{
+			name:  "recordings missing",
+			proxy: &openapi.ProxyConfig{Mode: "replay"},
+			err:   "x-proxy: recordings directory is required in mode replay",
+		},
+		{
+			name:  "invalid mode",
+			proxy: &openapi.ProxyConfig{Url: "http://localhost", Mode: "foo"},
+			err:   "x-proxy: unsupported mode 'foo'",
+		},
+		{
+			name:  "invalid scope",
+			proxy: &openapi.ProxyConfig{Url: "http://localhost", Scope: "foo"},
+			err:   "x-proxy: unsupported scope 'foo'",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &openapi.Config{
+				OpenApi: version.New("3.1.0"),
+				Info:    openapi.Info{Name: "Test"},
+				Proxy:   tc.proxy,
+			}
+			err := c.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func newProxyHandler(t *testing.T, url, mode, scope, dir string) (openapi.Handler, *events.StoreManager) {
+	config := parseConfig(t, fmt.Sprintf(proxyConfig, url, mode, scope, dir))
+	sm := events.NewStoreManager(&index{})
+	sm.SetStore(10, events.NewTraits().WithNamespace("http"))
+	return openapi.NewHandler(config, &engine{}, sm), sm
+}
+
+func serve(h openapi.Handler, method, url, body string) *httptest.ResponseRecorder {
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, "http://localhost"+url, r)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rr := httptest.NewRecorder()
+	if err := h.ServeHTTP(rr, req); err != nil {
+		rr.Code = err.StatusCode
+	}
+	return rr
+}
@@ -0,0 +1,233 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mokapi/engine/common"
+	"mokapi/media"
+	"mokapi/providers/openapi/schema"
+	"mokapi/schema/json/generator"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStreamCount    = 10
+	defaultStreamInterval = time.Second
+)
+
+// StreamConfig defines how many events Mokapi generates for a
+// streaming response and the interval between them.
+type StreamConfig struct {
+	// Count of generated events. Default is 10
+	Count int `yaml:"count,omitempty" json:"count,omitempty"`
+
+	// Interval between two events, e.g. 500ms. Default is 1s
+	Interval string `yaml:"interval,omitempty" json:"interval,omitempty"`
+}
+
+// isStreaming reports whether the content type is a sequential
+// media type whose items are written one after another.
+func isStreaming(ct media.ContentType) bool {
+	switch ct.Key() {
+	case "text/event-stream", "application/x-ndjson", "application/jsonl":
+		return true
+	default:
+		return false
+	}
+}
+
+// writeStream writes the events defined by a script or, if no script
+// defined a stream, events generated from the media type's schema.
+// The whole stream is written to the HTTP log.
+func (h *responseHandler) writeStream(rw http.ResponseWriter, r *http.Request, response *common.HttpEventResponse, ct media.ContentType, mt *MediaType, request *common.HttpEventRequest, opts dataOptions, logHttp *HttpLog) {
+	interval := defaultStreamInterval
+	count := defaultStreamCount
+	if mt != nil && mt.Stream != nil {
+		if mt.Stream.Count > 0 {
+			count = mt.Stream.Count
+		}
+		if mt.Stream.Interval != "" {
+			if d, err := time.ParseDuration(mt.Stream.Interval); err != nil {
+				log.Warnf("invalid stream interval '%v': %v", mt.Stream.Interval, err)
+			} else {
+				interval = d
+			}
+		}
+	}
+
+	var next func(i int) (any, bool, error)
+	// schema to encode generated data if no item schema is defined
+	var dataSchema *schema.Schema
+	if response.Stream != nil {
+		if response.Stream.Interval > 0 {
+			interval = time.Duration(response.Stream.Interval) * time.Millisecond
+		}
+		next = func(i int) (any, bool, error) {
+			if i >= len(response.Stream.Events) {
+				return nil, false, nil
+			}
+			return response.Stream.Events[i], true, nil
+		}
+	} else {
+		if mt != nil && mt.ItemSchema == nil {
+			dataSchema = mt.Schema
+		}
+		next = func(i int) (any, bool, error) {
+			if i >= count {
+				return nil, false, nil
+			}
+			itemOpts := opts
+			if opts.seed != 0 {
+				// each item differs but the stream is reproducible
+				itemOpts.seed += int64(i)
+			}
+			v, err := generateStreamItem(mt, request, itemOpts)
+			return v, err == nil, err
+		}
+	}
+
+	rw.Header().Set("Content-Type", ct.String())
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Del("Content-Length")
+	if response.StatusCode > 0 {
+		rw.WriteHeader(response.StatusCode)
+		if logHttp != nil {
+			logHttp.Response.StatusCode = response.StatusCode
+		}
+	}
+
+	rc := http.NewResponseController(rw)
+	var captured bytes.Buffer
+	defer func() {
+		if logHttp != nil {
+			logHttp.Response.Body = captured.String()
+			logHttp.Response.Size = captured.Len()
+		}
+	}()
+
+	for i := 0; ; i++ {
+		v, ok, err := next(i)
+		if err != nil {
+			log.Errorf("generate stream event failed for %v: %v", r.URL.String(), err)
+			return
+		}
+		if !ok {
+			return
+		}
+
+		if i > 0 && interval > 0 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+
+		b, err := encodeStreamItem(v, ct, dataSchema)
+		if err != nil {
+			log.Errorf("write stream event failed for %v: %v", r.URL.String(), err)
+			return
+		}
+		if _, err = rw.Write(b); err != nil {
+			log.Errorf("write stream event failed for %v: %v", r.URL.String(), err)
+			return
+		}
+		captured.Write(b)
+		if err = rc.Flush(); err != nil {
+			log.Debugf("flush stream failed for %v: %v", r.URL.String(), err)
+		}
+	}
+}
+
+func generateStreamItem(mt *MediaType, request *common.HttpEventRequest, opts dataOptions) (any, error) {
+	if mt == nil {
+		return nil, nil
+	}
+	s := mt.ItemSchema
+	if s == nil {
+		s = mt.Schema
+	}
+	req := generator.NewRequest(nil, schema.ConvertToJsonSchema(s), getGeneratorContext(request))
+	opts.apply(req)
+	return generator.New(req)
+}
+
+// encodeStreamItem encodes a single item. For text/event-stream an object
+// with any of the fields event, data, id and retry is written as such an
+// event. Any other value is written as the data of the event, encoded
+// with the given schema if not nil.
+func encodeStreamItem(v any, ct media.ContentType, dataSchema *schema.Schema) ([]byte, error) {
+	if ct.Key() != "text/event-stream" {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	}
+
+	var sb strings.Builder
+	if m, ok := toSseEvent(v); ok {
+		for _, field := range []string{"id", "event", "retry"} {
+			if f, ok := m[field]; ok && f != nil {
+				sb.WriteString(fmt.Sprintf("%s: %v\n", field, f))
+			}
+		}
+		v = m["data"]
+	} else if dataSchema != nil {
+		b, err := dataSchema.Marshal(v, media.ParseContentType("application/json"))
+		if err != nil {
+			return nil, err
+		}
+		v = string(b)
+	}
+
+	data, err := sseData(v)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(data, "\n") {
+		sb.WriteString("data: ")
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	return []byte(sb.String()), nil
+}
+
+// toSseEvent returns the value as map if it only contains fields of
+// a server-sent event.
+func toSseEvent(v any) (map[string]any, bool) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	var m map[string]any
+	if err = json.Unmarshal(b, &m); err != nil || len(m) == 0 {
+		return nil, false
+	}
+	for k := range m {
+		switch k {
+		case "id", "event", "data", "retry":
+		default:
+			return nil, false
+		}
+	}
+	return m, true
+}
+
+func sseData(v any) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	default:
+		b, err := json.Marshal(val)
+		return string(b), err
+	}
+}
@@ -0,0 +1,188 @@
+package openapi_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"mokapi/engine/common"
+	"mokapi/providers/openapi"
+	"mokapi/runtime/events"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Stream(t *testing.T) {
+	testcases := []struct {
+		name   string
+		config string
+		emit   func(event string, args ...interface{}) []*common.Action
+		test   func(t *testing.T, rr *httptest.ResponseRecorder, l *openapi.HttpLog)
+	}{
+		{
+			name: "server-sent events from item schema",
+			config: `
+openapi: 3.2.0
+info:
+  title: Test
+paths:
+  /events:
+    get:
+      responses:
+        '200':
+          description: stream
+          content:
+            text/event-stream:
+              itemSchema:
+                type: object
+                properties:
+                  event:
+                    const: message
+                  data:
+                    type: object
+                    properties:
+                      text:
+                        const: hello
+                    required: [ text ]
+                    additionalProperties: false
+                required: [ event, data ]
+                additionalProperties: false
+              x-stream:
+                count: 3
+                interval: 10ms
+`,
+			test: func(t *testing.T, rr *httptest.ResponseRecorder, l *openapi.HttpLog) {
+				require.Equal(t, http.StatusOK, rr.Code)
+				require.Equal(t, "text/event-stream", rr.Header().Get("Content-Type"))
+				require.Equal(t, strings.Repeat("event: message\ndata: {\"text\":\"hello\"}\n\n", 3), rr.Body.String())
+				require.Equal(t, rr.Body.String(), l.Response.Body)
+				require.Equal(t, rr.Body.Len(), l.Response.Size)
+			},
+		},
+		{
+			name: "server-sent events from schema",
+			config: `
+openapi: 3.1.0
+info:
+  title: Test
+paths:
+  /events:
+    get:
+      responses:
+        '200':
+          description: stream
+          content:
+            text/event-stream:
+              schema:
+                type: integer
+                minimum: 1
+                maximum: 1
+              x-stream:
+                count: 2
+                interval: 1ms
+`,
+			test: func(t *testing.T, rr *httptest.ResponseRecorder, _ *openapi.HttpLog) {
+				require.Equal(t, "data: 1\n\ndata: 1\n\n", rr.Body.String())
+			},
+		},
+		{
+			name: "ndjson",
+			config: `
+openapi: 3.1.0
+info:
+  title: Test
+paths:
+  /events:
+    get:
+      responses:
+        '200':
+          description: stream
+          content:
+            application/x-ndjson:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: integer
+                required: [ id ]
+                additionalProperties: false
+              x-stream:
+                count: 4
+                interval: 0s
+`,
+			test: func(t *testing.T, rr *httptest.ResponseRecorder, _ *openapi.HttpLog) {
+				require.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+				scanner := bufio.NewScanner(rr.Body)
+				n := 0
+				for scanner.Scan() {
+					var v map[string]any
+					require.NoError(t, json.Unmarshal(scanner.Bytes(), &v))
+					require.Contains(t, v, "id")
+					n++
+				}
+				require.Equal(t, 4, n)
+			},
+		},
+		{
+			name: "events from script",
+			config: `
+openapi: 3.1.0
+info:
+  title: Test
+paths:
+  /chat:
+    post:
+      responses:
+        '200':
+          description: stream
+          content:
+            text/event-stream:
+              schema:
+                type: string
+`,
+			emit: func(event string, args ...interface{}) []*common.Action {
+				res := args[1].(*common.HttpEventResponse)
+				res.Stream = &common.HttpStream{
+					Events: []any{
+						map[string]any{"event": "token", "data": "Hello"},
+						"world",
+						map[string]any{"id": 3, "data": map[string]any{"done": true}},
+					},
+					Interval: 1,
+				}
+				return nil
+			},
+			test: func(t *testing.T, rr *httptest.ResponseRecorder, l *openapi.HttpLog) {
+				expected := "event: token\ndata: Hello\n\ndata: world\n\nid: 3\ndata: {\"done\":true}\n\n"
+				require.Equal(t, expected, rr.Body.String())
+				require.Equal(t, expected, l.Response.Body)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := parseConfig(t, tc.config)
+			sm := events.NewStoreManager(&index{})
+			sm.SetStore(10, events.NewTraits().WithNamespace("http"))
+			h := openapi.NewHandler(config, &engine{emit: tc.emit}, sm)
+
+			var method, path string
+			for p, ref := range config.Paths {
+				path = p
+				for m := range ref.Value.Operations() {
+					method = m
+				}
+			}
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(method, "http://localhost"+path, nil))
+
+			e := sm.GetEvents(events.NewTraits().WithNamespace("http"))
+			require.Len(t, e, 1)
+			tc.test(t, rr, e[0].Data.(*openapi.HttpLog))
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"net/textproto"
 	"strings"
+
+	"github.com/google/uuid"
 )
 
 const logKey = "http_log"
@@ -22,6 +24,22 @@ type HttpLog struct {
 	Api        string           `json:"api"`
 	Path       string           `json:"path"`
 	ClientIP   string           `json:"clientIP"`
+	// Callback is set if the request was sent by Mokapi
+	// as a callback or webhook
+	Callback *HttpCallbackLog `json:"callback,omitempty"`
+
+	id string
+}
+
+// HttpCallbackLog links a callback or webhook to the request
+// that triggered it.
+type HttpCallbackLog struct {
+	// EventId is the ID of the HTTP event of the triggering request
+	EventId string `json:"eventId,omitempty"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Method  string `json:"method"`
+	Url     string `json:"url"`
 }
 
 type HttpRequestLog struct {
@@ -58,6 +76,7 @@ func NewLogEventContext(r *http.Request, deprecated bool, traits events.Traits)
 		Api:        traits.GetName(),
 		Path:       traits.Get("path"),
 		ClientIP:   lib.ClientIP(r),
+		id:         uuid.New().String(),
 	}
 
 	params, _ := FromContext(r.Context())
@@ -105,6 +124,12 @@ func LogEventFromContext(ctx context.Context) (*HttpLog, bool) {
 	return l, ok
 }
 
+// EventId returns the ID of the event, which is assigned before the
+// request is handled to link callbacks to it
+func (l *HttpLog) EventId() string {
+	return l.id
+}
+
 func (l *HttpLog) Title() string {
 	if l.Request == nil {
 		return ""
@@ -8,6 +8,7 @@ import (
 	"mokapi/providers/openapi/schema"
 	"mokapi/schema/encoding"
 	"mokapi/schema/json/parser"
+	protobuf "mokapi/schema/protobuf/schema"
 )
 
 type MediaType struct {
@@ -15,6 +16,18 @@ type MediaType struct {
 	Example  *ExampleValue  `yaml:"example,omitempty" json:"example,omitempty"`
 	Examples Examples       `yaml:"examples,omitempty" json:"examples,omitempty"`
 
+	// ItemSchema describes each item of a sequential media type like
+	// text/event-stream or application/x-ndjson (OpenAPI 3.2)
+	ItemSchema *schema.Schema `yaml:"itemSchema,omitempty" json:"itemSchema,omitempty"`
+
+	// Stream is a Mokapi extension to configure the generated
+	// events of a streaming response
+	Stream *StreamConfig `yaml:"x-stream,omitempty" json:"x-stream,omitempty"`
+
+	// Protobuf is a Mokapi extension to describe the body by a
+	// message of a .proto file instead of a schema
+	Protobuf *ProtobufRef `yaml:"x-protobuf,omitempty" json:"x-protobuf,omitempty"`
+
 	ContentType media.ContentType    `yaml:"-" json:"-"`
 	Encoding    map[string]*Encoding `yaml:"encoding,omitempty" json:"encoding,omitempty"`
 }
@@ -31,6 +44,12 @@ func (m *MediaType) Parse(config *dynamic.Config, reader dynamic.Reader) error {
 	if err := m.Schema.Parse(config, reader); err != nil {
 		return fmt.Errorf("parse schema failed: %s", err)
 	}
+	if err := m.ItemSchema.Parse(config, reader); err != nil {
+		return fmt.Errorf("parse item schema failed: %s", err)
+	}
+	if err := m.Protobuf.Parse(config, reader); err != nil {
+		return fmt.Errorf("parse protobuf message failed: %s", err)
+	}
 
 	if err := m.Examples.parse(config, reader); err != nil {
 		return err
@@ -45,6 +64,17 @@ func (m *MediaType) patch(patch *MediaType) {
 	} else {
 		m.Schema.Patch(patch.Schema)
 	}
+	if m.ItemSchema == nil {
+		m.ItemSchema = patch.ItemSchema
+	} else {
+		m.ItemSchema.Patch(patch.ItemSchema)
+	}
+	if patch.Stream != nil {
+		m.Stream = patch.Stream
+	}
+	if patch.Protobuf != nil {
+		m.Protobuf = patch.Protobuf
+	}
 
 	if patch.Example != nil && patch.Example.Value != nil {
 		m.Example = patch.Example
@@ -69,6 +99,13 @@ func (m *MediaType) ParseData(b []byte, contentType media.ContentType) (interfac
 		}
 	}
 
+	if m.hasProtobuf() {
+		return encoding.Decode(b,
+			encoding.WithContentType(contentType),
+			encoding.WithParser(&protobuf.Parser{Message: m.Protobuf.Value}),
+		)
+	}
+
 	if contentType.IsXml() {
 		return schema.UnmarshalXML(bytes.NewReader(b), m.Schema)
 	}
@@ -92,3 +129,7 @@ func (m *MediaType) ParseData(b []byte, contentType media.ContentType) (interfac
 
 	return encoding.Decode(b, opts...)
 }
+
+func (m *MediaType) hasProtobuf() bool {
+	return m != nil && m.Protobuf != nil && m.Protobuf.Value != nil
+}
@@ -0,0 +1,72 @@
+package openapi
+
+import (
+	"crypto/rsa"
+	"maps"
+	"mokapi/oauth2"
+	"slices"
+)
+
+// newOAuth2Server returns an authorization server for the OAuth2 security
+// schemes of the given config or nil if the config does not define any.
+func newOAuth2Server(config *Config, key *rsa.PrivateKey, codes *oauth2.CodeStore) *oauth2.Server {
+	if key == nil || config == nil || config.Components.SecuritySchemes == nil {
+		return nil
+	}
+
+	var s *oauth2.Server
+	scopes := map[string]struct{}{}
+	for _, name := range slices.Sorted(maps.Keys(config.Components.SecuritySchemes)) {
+		scheme, ok := config.Components.SecuritySchemes[name].(*OAuth2SecurityScheme)
+		if !ok {
+			continue
+		}
+		if s == nil {
+			s = &oauth2.Server{Key: key, Codes: codes}
+		}
+
+		for _, flowName := range slices.Sorted(maps.Keys(scheme.Flows)) {
+			flow := scheme.Flows[flowName]
+			if flow == nil {
+				continue
+			}
+			if flow.AuthorizationUrl != "" {
+				s.Endpoints.Authorization = appendUnique(s.Endpoints.Authorization, flow.AuthorizationUrl)
+			}
+			for _, u := range []string{flow.TokenUrl, flow.RefreshUrl} {
+				if u != "" {
+					s.Endpoints.Token = appendUnique(s.Endpoints.Token, u)
+				}
+			}
+			for scope := range flow.Scopes {
+				scopes[scope] = struct{}{}
+			}
+		}
+		for _, c := range scheme.Clients {
+			s.Clients = append(s.Clients, oauth2.Client{
+				ClientId:     c.ClientId,
+				ClientSecret: c.ClientSecret,
+				RedirectUris: c.RedirectUris,
+			})
+		}
+		for _, u := range scheme.Users {
+			s.Users = append(s.Users, oauth2.User{
+				Username: u.Username,
+				Password: u.Password,
+				Claims:   u.Claims,
+			})
+		}
+	}
+
+	if s != nil {
+		s.Scopes = slices.Sorted(maps.Keys(scopes))
+	}
+	return s
+}
+
+func appendUnique(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
@@ -47,6 +47,22 @@ type Operation struct {
 
 	Security []SecurityRequirement `yaml:"security" json:"security"`
 
+	// A map of possible out-of band callbacks related to the parent
+	// operation. Mokapi sends them after the response was written.
+	Callbacks Callbacks `yaml:"callbacks,omitempty" json:"callbacks,omitempty"`
+
+	// Webhooks is a Mokapi extension to send webhooks defined in the
+	// top-level webhooks after the response was written.
+	Webhooks []WebhookTrigger `yaml:"x-webhooks,omitempty" json:"x-webhooks,omitempty"`
+
+	// Delay is a Mokapi extension to delay a callback or webhook
+	// request, e.g. 2s
+	Delay string `yaml:"x-delay,omitempty" json:"x-delay,omitempty"`
+
+	// Deterministic is a Mokapi extension that overrides the global
+	// data-gen setting for generated responses of this operation
+	Deterministic *bool `yaml:"x-deterministic,omitempty" json:"x-deterministic,omitempty"`
+
 	Path   *Path   `yaml:"-" json:"-"`
 	Status Status  `yaml:"-" json:"-"`
 	Errors []Error `yaml:"-" json:"-"`
@@ -99,6 +115,10 @@ func (o *Operation) Parse(config *dynamic.Config, reader dynamic.Reader) error {
 		return fmt.Errorf("parse request body failed: %w", err)
 	}
 
+	if err := o.Callbacks.Parse(config, reader); err != nil {
+		return err
+	}
+
 	return o.Responses.parse(config, reader)
 }
 
@@ -117,6 +137,15 @@ func (o *Operation) patch(patch *Operation) {
 		o.OperationId = patch.OperationId
 	}
 	o.Deprecated = patch.Deprecated
+	if len(patch.Delay) > 0 {
+		o.Delay = patch.Delay
+	}
+	if len(patch.Webhooks) > 0 {
+		o.Webhooks = patch.Webhooks
+	}
+	if patch.Deterministic != nil {
+		o.Deterministic = patch.Deterministic
+	}
 
 	if o.RequestBody == nil {
 		o.RequestBody = patch.RequestBody
@@ -132,6 +161,12 @@ func (o *Operation) patch(patch *Operation) {
 
 	o.Parameters.Patch(patch.Parameters)
 
+	if o.Callbacks == nil {
+		o.Callbacks = patch.Callbacks
+	} else {
+		o.Callbacks.patch(patch.Callbacks)
+	}
+
 	if o.Security == nil {
 		o.Security = patch.Security
 	} else {
@@ -0,0 +1,45 @@
+package openapi
+
+import (
+	"encoding/json"
+	"mokapi/config/dynamic"
+	"mokapi/media"
+	"mokapi/schema/encoding"
+	protobuf "mokapi/schema/protobuf/schema"
+)
+
+// ProtobufRef is a Mokapi extension that references a message in a
+// .proto file describing the body of a media type, e.g.
+//
+//	x-protobuf:
+//	  $ref: ./order.proto#/Order
+type ProtobufRef struct {
+	Ref   string            `yaml:"$ref" json:"$ref"`
+	Value *protobuf.Message `yaml:"-" json:"-"`
+}
+
+func (r *ProtobufRef) Parse(config *dynamic.Config, reader dynamic.Reader) error {
+	if r == nil || r.Ref == "" {
+		return nil
+	}
+	ref := dynamic.Reference[*protobuf.Message]{Ref: r.Ref}
+	m, err := ref.Resolve(config, reader)
+	if err != nil {
+		return err
+	}
+	r.Value = m
+	return nil
+}
+
+func (r *ProtobufRef) marshal(v any, contentType media.ContentType) ([]byte, error) {
+	if encoding.IsProtobuf(contentType) {
+		return r.Value.Marshal(v)
+	}
+	// use JSON mapping of protobuf for any other content type
+	p := &protobuf.Parser{Message: r.Value}
+	val, err := p.Parse(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(val)
+}
@@ -0,0 +1,164 @@
+package openapi
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Recording is a request/response pair captured by the proxy. It is
+// stored as a JSON file named after the request, so a request
+// recorded again replaces the previous recording.
+type Recording struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Query    string `json:"query,omitempty"`
+	Body     string `json:"body,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	Encoding   string      `json:"encoding,omitempty"`
+}
+
+func newRecordedRequest(method, path, rawQuery string, body []byte) *RecordedRequest {
+	r := &RecordedRequest{
+		Method: strings.ToUpper(method),
+		Path:   path,
+	}
+	if q, err := url.ParseQuery(rawQuery); err == nil {
+		// Encode sorts by key so the order of query parameters does not matter
+		r.Query = q.Encode()
+	} else {
+		r.Query = rawQuery
+	}
+	r.Body, r.Encoding = encodeBody(body)
+	return r
+}
+
+// key identifies a request by method, path, query and body. JSON bodies
+// are compared by value, not by their formatting.
+func (r *RecordedRequest) key() string {
+	body := []byte(r.Body)
+	if r.Encoding == "" {
+		var v any
+		if err := json.Unmarshal(body, &v); err == nil {
+			body, _ = json.Marshal(v)
+		}
+	}
+
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.Path))
+	h.Write([]byte{0})
+	h.Write([]byte(r.Query))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func (r *RecordedRequest) fileName() string {
+	var sb strings.Builder
+	for _, c := range strings.Trim(r.Path, "/") {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+			sb.WriteRune(c)
+		default:
+			sb.WriteRune('-')
+		}
+		if sb.Len() >= 64 {
+			break
+		}
+	}
+	slug := sb.String()
+	if slug == "" {
+		slug = "root"
+	}
+	return fmt.Sprintf("%s_%s_%s.json", strings.ToLower(r.Method), slug, r.key())
+}
+
+func (r *RecordedResponse) toResponse() *proxyResponse {
+	header := r.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	body, err := decodeBody(r.Body, r.Encoding)
+	if err != nil {
+		body = []byte(r.Body)
+	}
+	return &proxyResponse{statusCode: r.StatusCode, header: header, body: body}
+}
+
+func saveRecording(dir string, req *RecordedRequest, res *proxyResponse) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	rec := Recording{
+		Request: *req,
+		Response: RecordedResponse{
+			StatusCode: res.statusCode,
+			Header:     res.header,
+		},
+	}
+	rec.Response.Body, rec.Response.Encoding = encodeBody(res.body)
+
+	b, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, req.fileName()), b, 0o644)
+}
+
+// loadRecording returns the recording of the given request or nil
+// if the request has not been recorded.
+func loadRecording(dir string, req *RecordedRequest) (*Recording, error) {
+	b, err := os.ReadFile(filepath.Join(dir, req.fileName()))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var rec *Recording
+	if err = json.Unmarshal(b, &rec); err != nil {
+		return nil, fmt.Errorf("parse %v failed: %w", req.fileName(), err)
+	}
+	return rec, nil
+}
+
+func encodeBody(b []byte) (string, string) {
+	if utf8.Valid(b) {
+		return string(b), ""
+	}
+	return base64.StdEncoding.EncodeToString(b), "base64"
+}
+
+func decodeBody(s, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return []byte(s), nil
+	case "base64":
+		return base64.StdEncoding.DecodeString(s)
+	default:
+		return nil, fmt.Errorf("unsupported body encoding '%v'", encoding)
+	}
+}
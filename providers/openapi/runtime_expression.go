@@ -0,0 +1,166 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"mokapi/engine/common"
+	"mokapi/lib"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// expressionContext contains the data a runtime expression is evaluated against
+// https://spec.openapis.org/oas/v3.1.0#runtime-expressions
+type expressionContext struct {
+	r        *http.Request
+	request  *common.HttpEventRequest
+	response *common.HttpEventResponse
+}
+
+// evalExpressionTemplate replaces all runtime expressions enclosed in
+// braces, e.g. http://localhost/{$request.path.id}/events. A value that
+// starts with $ is evaluated as a single expression.
+func evalExpressionTemplate(s string, ctx *expressionContext) (string, error) {
+	if strings.HasPrefix(s, "$") {
+		return evalExpression(s, ctx)
+	}
+
+	var sb strings.Builder
+	for {
+		start := strings.Index(s, "{$")
+		if start < 0 {
+			sb.WriteString(s)
+			return sb.String(), nil
+		}
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("missing closing brace in expression '%v'", s[start:])
+		}
+		v, err := evalExpression(s[start+1:start+end], ctx)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(s[:start])
+		sb.WriteString(v)
+		s = s[start+end+1:]
+	}
+}
+
+func evalExpression(expr string, ctx *expressionContext) (string, error) {
+	switch {
+	case expr == "$url":
+		return lib.GetUrl(ctx.r), nil
+	case expr == "$method":
+		return ctx.r.Method, nil
+	case expr == "$statusCode":
+		return strconv.Itoa(ctx.response.StatusCode), nil
+	case strings.HasPrefix(expr, "$request."):
+		return evalRequestExpression(strings.TrimPrefix(expr, "$request."), ctx)
+	case strings.HasPrefix(expr, "$response."):
+		return evalResponseExpression(strings.TrimPrefix(expr, "$response."), ctx)
+	default:
+		return "", fmt.Errorf("unsupported runtime expression '%v'", expr)
+	}
+}
+
+func evalRequestExpression(source string, ctx *expressionContext) (string, error) {
+	switch {
+	case strings.HasPrefix(source, "header."):
+		return ctx.r.Header.Get(strings.TrimPrefix(source, "header.")), nil
+	case strings.HasPrefix(source, "query."):
+		return ctx.r.URL.Query().Get(strings.TrimPrefix(source, "query.")), nil
+	case strings.HasPrefix(source, "path."):
+		v, ok := ctx.request.Path[strings.TrimPrefix(source, "path.")]
+		if !ok {
+			return "", nil
+		}
+		return toExpressionString(v)
+	case source == "body" || strings.HasPrefix(source, "body#"):
+		return evalBodyExpression(ctx.request.Body, strings.TrimPrefix(source, "body"))
+	default:
+		return "", fmt.Errorf("unsupported runtime expression '$request.%v'", source)
+	}
+}
+
+func evalResponseExpression(source string, ctx *expressionContext) (string, error) {
+	switch {
+	case strings.HasPrefix(source, "header."):
+		name := strings.TrimPrefix(source, "header.")
+		for k, v := range ctx.response.Headers {
+			if strings.EqualFold(k, name) {
+				return toExpressionString(v)
+			}
+		}
+		return "", nil
+	case source == "body" || strings.HasPrefix(source, "body#"):
+		var body any = ctx.response.Data
+		if len(ctx.response.Body) > 0 {
+			if err := json.Unmarshal([]byte(ctx.response.Body), &body); err != nil {
+				body = ctx.response.Body
+			}
+		}
+		return evalBodyExpression(body, strings.TrimPrefix(source, "body"))
+	default:
+		return "", fmt.Errorf("unsupported runtime expression '$response.%v'", source)
+	}
+}
+
+// evalBodyExpression resolves the JSON pointer of a body expression,
+// e.g. #/callbackUrl
+func evalBodyExpression(body any, fragment string) (string, error) {
+	// normalize body to plain JSON values
+	b, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	var v any
+	if err = json.Unmarshal(b, &v); err != nil {
+		return "", err
+	}
+
+	pointer := strings.TrimPrefix(fragment, "#")
+	if pointer == "" {
+		return toExpressionString(v)
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return "", fmt.Errorf("invalid JSON pointer '%v'", pointer)
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch val := v.(type) {
+		case map[string]any:
+			var ok bool
+			if v, ok = val[token]; !ok {
+				return "", fmt.Errorf("JSON pointer '%v' not found in body", pointer)
+			}
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(val) {
+				return "", fmt.Errorf("JSON pointer '%v' not found in body", pointer)
+			}
+			v = val[i]
+		default:
+			return "", fmt.Errorf("JSON pointer '%v' not found in body", pointer)
+		}
+	}
+	return toExpressionString(v)
+}
+
+func toExpressionString(v any) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case *string:
+		return *val, nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case map[string]any, []any:
+		b, err := json.Marshal(val)
+		return string(b), err
+	default:
+		return fmt.Sprintf("%v", val), nil
+	}
+}
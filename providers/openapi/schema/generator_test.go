@@ -50,6 +50,7 @@ func TestGenerator(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			gofakeit.Seed(11)
+			generator.Seed(11)
 
 			v, err := schema.CreateValue(tc.schema)
 			tc.test(t, v, err)
@@ -245,6 +246,7 @@ func TestGeneratorBool(t *testing.T) {
 	for _, data := range testdata {
 		t.Run(data.name, func(t *testing.T) {
 			gofakeit.Seed(11)
+			generator.Seed(11)
 
 			o, err := schema.CreateValue(data.schema)
 			require.NoError(t, err)
@@ -358,6 +360,7 @@ func TestGeneratorInt(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			gofakeit.Seed(11)
+			generator.Seed(11)
 
 			i, err := schema.CreateValue(tc.schema)
 			tc.test(t, i, err)
@@ -446,6 +449,7 @@ func TestGeneratorFloat(t *testing.T) {
 	for _, data := range testdata {
 		t.Run(data.name, func(t *testing.T) {
 			gofakeit.Seed(11)
+			generator.Seed(11)
 
 			o, err := schema.CreateValue(data.schema)
 			require.NoError(t, err)
@@ -694,6 +698,7 @@ func TestGenerator_AnyOf(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			gofakeit.Seed(11)
+			generator.Seed(11)
 			tc.f(t)
 		})
 	}
@@ -792,6 +797,7 @@ func TestGenerator_AllOf(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			gofakeit.Seed(11)
+			generator.Seed(11)
 
 			o, err := schema.CreateValue(tc.schema)
 
@@ -823,6 +829,7 @@ func TestGenerator_OneOf(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			gofakeit.Seed(11)
+			generator.Seed(11)
 
 			o, err := schema.CreateValue(tc.schema)
 
@@ -899,6 +906,7 @@ func TestGenerator_Recursions(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			gofakeit.Seed(11)
+			generator.Seed(11)
 			tc.f(t)
 		})
 	}
@@ -995,6 +1003,7 @@ func TestGeneratorNullable(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			gofakeit.Seed(tc.seed)
+			generator.Seed(tc.seed)
 
 			o, err := schema.CreateValue(tc.schema)
 			tc.test(t, o, err)
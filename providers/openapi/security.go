@@ -2,31 +2,63 @@ package openapi
 
 import (
 	"bytes"
+	"crypto/rsa"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
+	"mokapi/engine/common"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
 )
 
+// errInsufficientScope is returned if the request is authenticated but the
+// access token does not grant the scopes required by the operation.
+var errInsufficientScope = errors.New("insufficient scope")
+
 type SecuritySchemes map[string]SecurityScheme
 
 type SecurityScheme interface {
-	Serve(req *http.Request) error
+	Serve(req *http.Request, scopes []string) error
 	patch(scheme SecurityScheme)
+	// challenge returns the value for the WWW-Authenticate header
+	challenge(realm string) string
 }
 
 type SecurityRequirement map[string][]string
 
+// SecurityUser is a Mokapi extension to define the credentials
+// accepted by the HTTP basic authentication scheme and the users
+// that can sign in to the built-in OAuth2 authorization server.
+type SecurityUser struct {
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"password"`
+	// Claims are added to the ID token and the userinfo response
+	Claims map[string]any `yaml:"claims,omitempty" json:"claims,omitempty"`
+}
+
+// OAuth2Client is a Mokapi extension to register a client at the
+// built-in OAuth2 authorization server.
+type OAuth2Client struct {
+	ClientId     string   `yaml:"clientId" json:"clientId"`
+	ClientSecret string   `yaml:"clientSecret,omitempty" json:"clientSecret,omitempty"`
+	RedirectUris []string `yaml:"redirectUris,omitempty" json:"redirectUris,omitempty"`
+}
+
 type HttpSecurityScheme struct {
-	Type         string `yaml:"type" json:"type"`
-	Scheme       string `yaml:"scheme" json:"scheme"`
-	BearerFormat string `yaml:"bearerFormat" json:"bearerFormat"`
-	Description  string `yaml:"description,omitempty" json:"description,omitempty"`
+	Type         string         `yaml:"type" json:"type"`
+	Scheme       string         `yaml:"scheme" json:"scheme"`
+	BearerFormat string         `yaml:"bearerFormat" json:"bearerFormat"`
+	Description  string         `yaml:"description,omitempty" json:"description,omitempty"`
+	Users        []SecurityUser `yaml:"x-users,omitempty" json:"x-users,omitempty"`
 }
 
-func (s *HttpSecurityScheme) Serve(req *http.Request) error {
+func (s *HttpSecurityScheme) Serve(req *http.Request, scopes []string) error {
 	request := EventRequestFromContext(req.Context())
 	log, ok := LogEventFromContext(req.Context())
 
@@ -42,11 +74,14 @@ func (s *HttpSecurityScheme) Serve(req *http.Request) error {
 		return fmt.Errorf("no authorization header")
 	}
 
-	switch s.Scheme {
+	var err error
+	switch strings.ToLower(s.Scheme) {
 	case "bearer":
 		request.Header["Authorization"] = auth
+		err = serveBearer(request, auth, scopes, nil, "")
 	case "basic":
 		request.Header["Authorization"] = auth
+		err = s.serveBasic(req, request)
 	default:
 		return fmt.Errorf("security scheme not supported: %v", s.Scheme)
 	}
@@ -61,17 +96,46 @@ func (s *HttpSecurityScheme) Serve(req *http.Request) error {
 		}
 	}
 
+	return err
+}
+
+func (s *HttpSecurityScheme) serveBasic(req *http.Request, request *common.HttpEventRequest) error {
+	username, password, ok := req.BasicAuth()
+	if !ok {
+		if len(s.Users) > 0 {
+			return fmt.Errorf("invalid basic authorization header")
+		}
+		return nil
+	}
+	if len(s.Users) > 0 && !slices.ContainsFunc(s.Users, func(u SecurityUser) bool {
+		return u.Username == username && u.Password == password
+	}) {
+		return fmt.Errorf("invalid username or password")
+	}
+	request.Principal = username
 	return nil
 }
 
+func (s *HttpSecurityScheme) challenge(realm string) string {
+	switch strings.ToLower(s.Scheme) {
+	case "basic":
+		return fmt.Sprintf(`Basic realm="%s"`, realm)
+	case "bearer":
+		return fmt.Sprintf(`Bearer realm="%s"`, realm)
+	default:
+		return ""
+	}
+}
+
 type ApiKeySecurityScheme struct {
-	Type        string `yaml:"type" json:"type"`
-	In          string `yaml:"in" json:"in"`
-	Name        string `yaml:"name" json:"name"`
-	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Type        string   `yaml:"type" json:"type"`
+	In          string   `yaml:"in" json:"in"`
+	Name        string   `yaml:"name" json:"name"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Keys        []string `yaml:"x-keys,omitempty" json:"x-keys,omitempty"`
 }
 
-func (s *ApiKeySecurityScheme) Serve(req *http.Request) error {
+func (s *ApiKeySecurityScheme) Serve(req *http.Request, _ []string) error {
 	request := EventRequestFromContext(req.Context())
 	var val string
 	switch s.In {
@@ -130,13 +194,23 @@ func (s *ApiKeySecurityScheme) Serve(req *http.Request) error {
 		}
 	}
 
+	if len(s.Keys) > 0 && !slices.Contains(s.Keys, val) {
+		return fmt.Errorf("invalid API key: %v", s.Name)
+	}
+
 	return nil
 }
 
+func (s *ApiKeySecurityScheme) challenge(realm string) string {
+	return fmt.Sprintf(`ApiKey realm="%s", in="%s", name="%s"`, realm, s.In, s.Name)
+}
+
 type OAuth2SecurityScheme struct {
 	Type        string                 `yaml:"type" json:"type"`
 	Description string                 `yaml:"description,omitempty" json:"description,omitempty"`
 	Flows       map[string]*OAuth2Flow `yaml:"flows" json:"flows"`
+	Clients     []OAuth2Client         `yaml:"x-clients,omitempty" json:"x-clients,omitempty"`
+	Users       []SecurityUser         `yaml:"x-users,omitempty" json:"x-users,omitempty"`
 }
 
 type OAuth2Flow struct {
@@ -147,13 +221,20 @@ type OAuth2Flow struct {
 	Scopes map[string]string `yaml:"scopes" json:"scopes"`
 }
 
-func (s *OAuth2SecurityScheme) Serve(req *http.Request) error {
+func (s *OAuth2SecurityScheme) Serve(req *http.Request, scopes []string) error {
+	return s.serve(req, scopes, nil, "")
+}
+
+// serve checks the bearer token. If key is set, the token must be a JWT
+// signed with the key and issued by the given issuer.
+func (s *OAuth2SecurityScheme) serve(req *http.Request, scopes []string, key *rsa.PublicKey, issuer string) error {
 	request := EventRequestFromContext(req.Context())
 	auth := req.Header.Get("Authorization")
 	if auth == "" {
 		return fmt.Errorf("missing authorization header")
 	}
 	request.Header["Authorization"] = auth
+	err := serveBearer(request, auth, scopes, key, issuer)
 
 	if log, ok := LogEventFromContext(req.Context()); ok {
 		for i, p := range log.Request.Parameters {
@@ -165,9 +246,115 @@ func (s *OAuth2SecurityScheme) Serve(req *http.Request) error {
 		}
 	}
 
+	return err
+}
+
+func (s *OAuth2SecurityScheme) challenge(realm string) string {
+	return fmt.Sprintf(`Bearer realm="%s"`, realm)
+}
+
+// isServedBy reports whether the token endpoint of a flow is served by
+// the host, i.e. tokens are issued by the built-in authorization server.
+func (s *OAuth2SecurityScheme) isServedBy(host string) bool {
+	for _, flow := range s.Flows {
+		if flow == nil || flow.TokenUrl == "" {
+			continue
+		}
+		u, err := url.Parse(flow.TokenUrl)
+		if err != nil {
+			continue
+		}
+		if u.Host == "" || strings.EqualFold(u.Host, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// serveBearer checks the required scopes if the bearer token is a JWT.
+// Without a key, the signature and issuer of the token are not verified
+// and opaque tokens are accepted as they are.
+func serveBearer(request *common.HttpEventRequest, auth string, scopes []string, key *rsa.PublicKey, issuer string) error {
+	scheme, token, ok := strings.Cut(auth, " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") || token == "" {
+		return fmt.Errorf("invalid bearer authorization header")
+	}
+
+	var claims map[string]any
+	if key != nil {
+		var err error
+		claims, err = verifyJwt(token, key, issuer)
+		if err != nil {
+			return fmt.Errorf("invalid access token: %w", err)
+		}
+	} else if claims, ok = parseJwtClaims(token); !ok {
+		return nil
+	}
+	if sub, ok := claims["sub"].(string); ok {
+		request.Principal = sub
+	}
+
+	var granted []string
+	switch v := claims["scope"].(type) {
+	case string:
+		granted = strings.Fields(v)
+	case []any:
+		for _, scope := range v {
+			granted = append(granted, fmt.Sprintf("%v", scope))
+		}
+	}
+	if scp, ok := claims["scp"].([]any); ok {
+		for _, scope := range scp {
+			granted = append(granted, fmt.Sprintf("%v", scope))
+		}
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(granted, scope) {
+			return fmt.Errorf("%w: missing scope '%v'", errInsufficientScope, scope)
+		}
+	}
 	return nil
 }
 
+func verifyJwt(token string, key *rsa.PublicKey, issuer string) (map[string]any, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		return key, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// refresh and ID tokens are signed with the same key
+	if claims["typ"] != "access" {
+		return nil, fmt.Errorf("not an access token")
+	}
+	// all built-in authorization servers share the signing key
+	if !claims.VerifyIssuer(issuer, true) {
+		return nil, fmt.Errorf("token was issued by another issuer")
+	}
+	return claims, nil
+}
+
+func parseJwtClaims(token string) (map[string]any, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, false
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, false
+	}
+	var claims map[string]any
+	if err = json.Unmarshal(b, &claims); err != nil {
+		return nil, false
+	}
+	return claims, true
+}
+
 type NotSupportedSecuritySchemeError struct {
 	Scheme string
 }
@@ -176,10 +363,14 @@ type NotSupportedSecurityScheme struct {
 	Type string `yaml:"type" json:"type"`
 }
 
-func (s *NotSupportedSecurityScheme) Serve(req *http.Request) error {
+func (s *NotSupportedSecurityScheme) Serve(_ *http.Request, _ []string) error {
 	return &NotSupportedSecuritySchemeError{Scheme: s.Type}
 }
 
+func (s *NotSupportedSecurityScheme) challenge(_ string) string {
+	return ""
+}
+
 func (e *NotSupportedSecuritySchemeError) Error() string {
 	return fmt.Sprintf("security scheme %v not supported", e.Scheme)
 }
@@ -247,7 +438,9 @@ func (s *SecuritySchemes) UnmarshalYAML(node *yaml.Node) error {
 
 	for i := 0; i < len(node.Content); i += 2 {
 		key := node.Content[i].Value
-		var values map[string]string
+		var values struct {
+			Type string `yaml:"type"`
+		}
 		_ = node.Content[i+1].Decode(&values)
 
 		if *s == nil {
@@ -255,7 +448,7 @@ func (s *SecuritySchemes) UnmarshalYAML(node *yaml.Node) error {
 		}
 
 		var v SecurityScheme
-		switch values["type"] {
+		switch values.Type {
 		case "http":
 			v = &HttpSecurityScheme{}
 		case "apiKey":
@@ -314,6 +507,9 @@ func (s *HttpSecurityScheme) patch(patch SecurityScheme) {
 	if len(p.BearerFormat) > 0 {
 		s.BearerFormat = p.BearerFormat
 	}
+	if len(p.Users) > 0 {
+		s.Users = p.Users
+	}
 }
 
 func (s *ApiKeySecurityScheme) patch(patch SecurityScheme) {
@@ -330,6 +526,9 @@ func (s *ApiKeySecurityScheme) patch(patch SecurityScheme) {
 	if len(p.Name) > 0 {
 		s.Name = p.Name
 	}
+	if len(p.Keys) > 0 {
+		s.Keys = p.Keys
+	}
 }
 
 func (s *OAuth2SecurityScheme) patch(patch SecurityScheme) {
@@ -340,6 +539,15 @@ func (s *OAuth2SecurityScheme) patch(patch SecurityScheme) {
 	if len(p.Description) > 0 {
 		s.Description = p.Description
 	}
+	if len(p.Clients) > 0 {
+		s.Clients = p.Clients
+	}
+	if len(p.Users) > 0 {
+		s.Users = p.Users
+	}
+	if s.Flows == nil && len(p.Flows) > 0 {
+		s.Flows = map[string]*OAuth2Flow{}
+	}
 	for k, f := range p.Flows {
 		if v, ok := s.Flows[k]; ok && v != nil {
 			v.patch(f)
@@ -118,6 +118,62 @@ components:
 				require.Equal(t, map[string]string{"write_pets": "modify pets in your account", "read_pets": "read your pets"}, oauth2.Flows["implicit"].Scopes)
 			},
 		},
+		{
+			Name: "strict security with users and keys",
+			Content: `
+openapi: 3.0.0
+x-strictSecurity: true
+components:
+  securitySchemes:
+    basic:
+      type: http
+      scheme: basic
+      x-users:
+        - username: alice
+          password: secret
+    key:
+      type: apiKey
+      in: header
+      name: X-API-KEY
+      x-keys: [ foo, bar ]
+`,
+			f: func(t *testing.T, c *openapi.Config) {
+				require.True(t, c.StrictSecurity)
+				basic := c.Components.SecuritySchemes["basic"].(*openapi.HttpSecurityScheme)
+				require.Equal(t, []openapi.SecurityUser{{Username: "alice", Password: "secret"}}, basic.Users)
+				key := c.Components.SecuritySchemes["key"].(*openapi.ApiKeySecurityScheme)
+				require.Equal(t, []string{"foo", "bar"}, key.Keys)
+			},
+		},
+		{
+			Name: "oauth2 clients and users",
+			Content: `
+openapi: 3.0.0
+components:
+  securitySchemes:
+    oauth2:
+      type: oauth2
+      flows:
+        authorizationCode:
+          authorizationUrl: /oauth/authorize
+          tokenUrl: /oauth/token
+          scopes:
+            read: read access
+      x-clients:
+        - clientId: frontend
+          redirectUris: [ http://localhost:3000/callback ]
+      x-users:
+        - username: alice
+          password: secret
+          claims:
+            email: alice@example.com
+`,
+			f: func(t *testing.T, c *openapi.Config) {
+				oauth2 := c.Components.SecuritySchemes["oauth2"].(*openapi.OAuth2SecurityScheme)
+				require.Equal(t, []openapi.OAuth2Client{{ClientId: "frontend", RedirectUris: []string{"http://localhost:3000/callback"}}}, oauth2.Clients)
+				require.Equal(t, []openapi.SecurityUser{{Username: "alice", Password: "secret", Claims: map[string]any{"email": "alice@example.com"}}}, oauth2.Users)
+			},
+		},
 	}
 
 	for _, data := range testdata {
@@ -20,10 +20,25 @@ type EventData interface {
 	Title() string
 }
 
+// Updater is implemented by handlers that need to know when the data of
+// a pushed event has changed, e.g. to persist it again
+type Updater interface {
+	Update(data EventData)
+}
+
+// Update notifies the handler that the data of a pushed event has
+// changed, if the handler supports it
+func Update(h Handler, data EventData) {
+	if u, ok := h.(Updater); ok {
+		u.Update(data)
+	}
+}
+
 type StoreManager struct {
-	stores []*store
-	index  search.Index
-	m      sync.RWMutex
+	stores  []*store
+	index   search.Index
+	storage *FileStorage
+	m       sync.RWMutex
 }
 
 func NewStoreManager(index search.Index) *StoreManager {
@@ -37,17 +52,7 @@ func (m *StoreManager) Push(data EventData, traits Traits) error {
 	if len(traits) == 0 {
 		return fmt.Errorf("empty traits not allowed")
 	}
-	score := 0
-	var bestStore *store
-	for _, s := range m.stores {
-		if s.traits.Match(traits) {
-			if bestStore == nil || len(s.traits) > score {
-				bestStore = s
-				score = len(s.traits)
-			}
-		}
-	}
-
+	bestStore := m.findStore(traits)
 	if bestStore == nil {
 		return fmt.Errorf("no store found for %s", traits)
 	}
@@ -61,14 +66,41 @@ func (m *StoreManager) Push(data EventData, traits Traits) error {
 		m.index.Delete(removed.Id)
 	}
 
+	if m.storage != nil {
+		m.persist(bestStore, &evt)
+	}
+
 	return nil
 }
 
+func (m *StoreManager) findStore(traits Traits) *store {
+	score := 0
+	var bestStore *store
+	for _, s := range m.stores {
+		if s.traits.Match(traits) {
+			if bestStore == nil || len(s.traits) > score {
+				bestStore = s
+				score = len(s.traits)
+			}
+		}
+	}
+	return bestStore
+}
+
 func (m *StoreManager) SetStore(size int, traits Traits) {
-	m.stores = append(m.stores, &store{
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	s := &store{
 		size:   size,
 		traits: traits,
-	})
+	}
+	if m.storage != nil {
+		// events restored from disk may have been assigned to a less
+		// specific store because this store did not exist yet
+		m.adopt(s)
+	}
+	m.stores = append(m.stores, s)
 }
 
 func (m *StoreManager) GetStores(traits Traits) []StoreInfo {
@@ -160,9 +192,19 @@ func (e *Event) IsValid() bool {
 	return len(e.Id) > 0
 }
 
+// identified is implemented by event data that assigns its event ID before
+// the event is pushed, so that other events can reference it
+type identified interface {
+	EventId() string
+}
+
 func NewEvent(data EventData, traits Traits) Event {
+	id := uuid.New().String()
+	if i, ok := data.(identified); ok && i.EventId() != "" {
+		id = i.EventId()
+	}
 	return Event{
-		Id:     uuid.New().String(),
+		Id:     id,
 		Traits: traits,
 		Data:   data,
 		Time:   time.Now(),
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a subset of JSONPath (RFC 9535) supporting member names
+// ($.a.b, $['a']), array indices ($.a[0], $.a[-1]) and wildcards
+// ($.a[*], $.a.*).
+type jsonPath []string
+
+const wildcard = "*"
+
+func parseJsonPath(s string) (jsonPath, error) {
+	if !strings.HasPrefix(s, "$") {
+		return nil, fmt.Errorf("invalid JSONPath '%v': must start with $", s)
+	}
+
+	var p jsonPath
+	rest := s[1:]
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			i := strings.IndexAny(rest, ".[")
+			if i < 0 {
+				i = len(rest)
+			}
+			if i == 0 {
+				return nil, fmt.Errorf("invalid JSONPath '%v': expected member name", s)
+			}
+			p = append(p, rest[:i])
+			rest = rest[i:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSONPath '%v': missing ]", s)
+			}
+			seg := strings.TrimSpace(rest[1:end])
+			if len(seg) >= 2 && (seg[0] == '\'' || seg[0] == '"') && seg[len(seg)-1] == seg[0] {
+				seg = seg[1 : len(seg)-1]
+			} else if seg != wildcard {
+				if _, err := strconv.Atoi(seg); err != nil {
+					return nil, fmt.Errorf("invalid JSONPath '%v': invalid index '%v'", s, seg)
+				}
+				seg = "[" + seg
+			}
+			p = append(p, seg)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid JSONPath '%v': unexpected character '%c'", s, rest[0])
+		}
+	}
+	return p, nil
+}
+
+// eval returns all values selected by the path
+func (p jsonPath) eval(v any) []any {
+	values := []any{v}
+	for _, seg := range p {
+		var next []any
+		for _, v := range values {
+			next = append(next, selectSegment(v, seg)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func selectSegment(v any, seg string) []any {
+	switch t := v.(type) {
+	case map[string]any:
+		if seg == wildcard {
+			var result []any
+			for _, val := range t {
+				result = append(result, val)
+			}
+			return result
+		}
+		if val, ok := t[seg]; ok {
+			return []any{val}
+		}
+	case []any:
+		if seg == wildcard {
+			return t
+		}
+		if !strings.HasPrefix(seg, "[") {
+			return nil
+		}
+		i, _ := strconv.Atoi(seg[1:])
+		if i < 0 {
+			i += len(t)
+		}
+		if i >= 0 && i < len(t) {
+			return []any{t[i]}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package events
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SetStorage enables persisting events to the given storage.
+// Call Restore to load previously stored events.
+func (m *StoreManager) SetStorage(s *FileStorage) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	m.storage = s
+}
+
+// Restore loads the events of the storage into the stores and
+// adds them to the search index.
+func (m *StoreManager) Restore() error {
+	if m.storage == nil {
+		return nil
+	}
+
+	list, err := m.storage.load()
+	if err != nil {
+		return err
+	}
+
+	m.m.Lock()
+	restored := make(map[string]*Event, len(list))
+	for i := range list {
+		e := &list[i]
+		s := m.findStore(e.Traits)
+		if s == nil {
+			log.Debugf("no store found for restored event %v: %v", e.Id, e.Traits)
+			continue
+		}
+		restored[e.Id] = e
+		if removed := s.Push(*e); removed != nil {
+			delete(restored, removed.Id)
+		}
+	}
+	m.m.Unlock()
+
+	for _, e := range restored {
+		m.addToIndex(e)
+	}
+	log.Infof("restored %v events from %v", len(restored), m.storage.path)
+
+	m.m.RLock()
+	defer m.m.RUnlock()
+	return m.compact()
+}
+
+// Close closes the underlying storage, if any
+func (m *StoreManager) Close() error {
+	if m.storage == nil {
+		return nil
+	}
+	return m.storage.Close()
+}
+
+func (m *StoreManager) persist(s *store, e *Event) {
+	if m.storage.maxAge > 0 {
+		for _, expired := range s.expire(time.Now().Add(-m.storage.maxAge)) {
+			m.deleteFromIndex(expired.Id)
+		}
+	}
+	m.write(e)
+}
+
+// Update persists the data of a pushed event again. It must be called by
+// the goroutine that changed the data, once it is done changing it.
+func (m *StoreManager) Update(data EventData) {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
+	if m.storage == nil || data == nil || !reflect.TypeOf(data).Comparable() {
+		return
+	}
+	for _, s := range m.stores {
+		if e, ok := s.find(data); ok {
+			m.write(&e)
+			return
+		}
+	}
+}
+
+// write appends the event to the storage. The caller must hold the read
+// lock of the manager.
+func (m *StoreManager) write(e *Event) {
+	compact, err := m.storage.append(e)
+	if err != nil {
+		log.Errorf("failed to persist event %v: %v", e.Id, err)
+		return
+	}
+	if compact {
+		if err = m.compact(); err != nil {
+			log.Errorf("failed to compact event storage: %v", err)
+		}
+	}
+}
+
+// compact rewrites the storage and removes the events dropped by its
+// size limit from memory. The caller must hold the read lock of the
+// manager.
+func (m *StoreManager) compact() error {
+	dropped, err := m.storage.compact()
+	if err != nil {
+		return err
+	}
+	if len(dropped) == 0 {
+		return nil
+	}
+
+	ids := make(map[string]bool, len(dropped))
+	for _, id := range dropped {
+		ids[id] = true
+		m.deleteFromIndex(id)
+	}
+	for _, s := range m.stores {
+		s.remove(ids)
+	}
+	return nil
+}
+
+// adopt moves events from less specific stores into the given store
+// if they match its traits. The caller must hold the write lock.
+func (m *StoreManager) adopt(s *store) {
+	var adopted []Event
+	for _, parent := range m.stores {
+		if len(parent.traits) >= len(s.traits) || !parent.traits.Match(s.traits) {
+			continue
+		}
+		adopted = append(adopted, parent.take(s.traits)...)
+	}
+	if len(adopted) == 0 {
+		return
+	}
+
+	sort.SliceStable(adopted, func(i, j int) bool {
+		return adopted[i].Time.After(adopted[j].Time)
+	})
+	size := s.size
+	if size == 0 {
+		size = defaultSize
+	}
+	if len(adopted) > size {
+		for _, e := range adopted[size:] {
+			m.deleteFromIndex(e.Id)
+		}
+		adopted = adopted[:size]
+	}
+	s.events = adopted
+}
+
+func (m *StoreManager) deleteFromIndex(id string) {
+	if m.index == nil {
+		return
+	}
+	m.index.Delete(id)
+}
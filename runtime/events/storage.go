@@ -0,0 +1,342 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	storageFileName = "events.log"
+	// the log is not compacted before it reaches this size
+	minCompactSize = 4 * 1024 * 1024
+)
+
+// FileStorage persists events as JSON lines in an append-only log file.
+// An event changed after it was pushed is appended again. Compaction
+// rewrites the file with the latest line of each event, so events
+// evicted from the in-memory stores are kept until they expire or
+// exceed the size limit.
+type FileStorage struct {
+	path    string
+	maxAge  time.Duration
+	maxSize int64
+
+	f         *os.File
+	size      int64
+	compactAt int64
+	m         sync.Mutex
+}
+
+type record struct {
+	Id     string          `json:"id"`
+	Traits Traits          `json:"traits"`
+	Time   time.Time       `json:"time"`
+	Title  string          `json:"title,omitempty"`
+	Domain string          `json:"domain,omitempty"`
+	Fields map[string]any  `json:"fields,omitempty"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// StoredData is the payload of an event restored from disk. It keeps the
+// original JSON so the event is served unchanged by the API.
+type StoredData struct {
+	title  string
+	domain string
+	fields map[string]any
+	raw    json.RawMessage
+}
+
+// NewFileStorage opens the event log in the given directory. Events older
+// than maxAge are discarded and the log is kept below maxSize bytes. Zero
+// disables the corresponding limit.
+func NewFileStorage(dir string, maxAge time.Duration, maxSize int64) (*FileStorage, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("event storage path is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create event storage directory failed: %w", err)
+	}
+
+	s := &FileStorage{
+		path:    filepath.Join(dir, storageFileName),
+		maxAge:  maxAge,
+		maxSize: maxSize,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileStorage) Close() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
+
+func (s *FileStorage) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open event storage failed: %w", err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("open event storage failed: %w", err)
+	}
+	s.f = f
+	s.size = fi.Size()
+	s.updateCompactAt(s.size)
+	return nil
+}
+
+// logEntry is the latest line of an event in the log
+type logEntry struct {
+	rec  record
+	line []byte
+}
+
+// load reads all events from the log, oldest first. Expired events,
+// duplicates and lines that cannot be decoded are skipped.
+func (s *FileStorage) load() ([]Event, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Event, 0, len(entries))
+	for i := range entries {
+		result = append(result, entries[i].rec.event())
+	}
+	return result, nil
+}
+
+// read returns the latest line of each event in the log, oldest first.
+// The caller must hold the lock.
+func (s *FileStorage) read() ([]logEntry, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("read event storage failed: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var result []logEntry
+	seen := map[string]int{}
+	cutoff := s.cutoff()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var rec record
+			if errDecode := json.Unmarshal(line, &rec); errDecode != nil {
+				log.Warnf("skipping invalid event in %v: %v", s.path, errDecode)
+			} else if rec.Id != "" && (cutoff.IsZero() || !rec.Time.Before(cutoff)) {
+				if line[len(line)-1] != '\n' {
+					line = append(line, '\n')
+				}
+				e := logEntry{rec: rec, line: line}
+				if i, ok := seen[rec.Id]; ok {
+					// the event was updated after it was appended
+					result[i] = e
+				} else {
+					seen[rec.Id] = len(result)
+					result = append(result, e)
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("read event storage failed: %w", err)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].rec.Time.Before(result[j].rec.Time)
+	})
+
+	return result, nil
+}
+
+// append writes the event to the log and reports whether the log
+// has grown large enough to be compacted.
+func (s *FileStorage) append(e *Event) (bool, error) {
+	b, err := marshalRecord(e)
+	if err != nil {
+		return false, err
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.f == nil {
+		return false, fmt.Errorf("event storage is closed")
+	}
+	n, err := s.f.Write(b)
+	s.size += int64(n)
+	if err != nil {
+		return false, fmt.Errorf("write event failed: %w", err)
+	}
+	return s.size >= s.compactAt, nil
+}
+
+// compact rewrites the log with the latest line of each event that has
+// not expired, including events no longer held in memory. If the log has
+// a size limit, the IDs of the oldest events that no longer fit are
+// returned so they can be removed from memory as well.
+func (s *FileStorage) compact() ([]string, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	// keep the newest events that fit into the size limit
+	start := 0
+	if s.maxSize > 0 {
+		// leave room for new events before the next compaction
+		budget := s.maxSize * 3 / 4
+		size := int64(0)
+		for start = len(entries); start > 0; start-- {
+			n := int64(len(entries[start-1].line))
+			if size+n > budget {
+				break
+			}
+			size += n
+		}
+	}
+	var dropped []string
+	for _, e := range entries[:start] {
+		dropped = append(dropped, e.rec.Id)
+	}
+
+	tmp := s.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return nil, fmt.Errorf("compact event storage failed: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	for _, e := range entries[start:] {
+		_, _ = w.Write(e.line)
+	}
+	err = w.Flush()
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return nil, fmt.Errorf("compact event storage failed: %w", err)
+	}
+
+	if s.f != nil {
+		_ = s.f.Close()
+		s.f = nil
+	}
+	if err = os.Rename(tmp, s.path); err != nil {
+		_ = os.Remove(tmp)
+		return nil, fmt.Errorf("compact event storage failed: %w", err)
+	}
+	if err = s.open(); err != nil {
+		return nil, err
+	}
+
+	return dropped, nil
+}
+
+func (s *FileStorage) cutoff() time.Time {
+	if s.maxAge <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(-s.maxAge)
+}
+
+func (s *FileStorage) updateCompactAt(size int64) {
+	s.compactAt = max(2*size, minCompactSize)
+	if s.maxSize > 0 {
+		s.compactAt = min(s.compactAt, s.maxSize)
+	}
+}
+
+func marshalRecord(e *Event) ([]byte, error) {
+	rec := record{
+		Id:     e.Id,
+		Traits: e.Traits,
+		Time:   e.Time,
+	}
+	if e.Data != nil {
+		rec.Title = e.Data.Title()
+		rec.Domain = getDomainFromEvent(e)
+		if p, ok := e.Data.(IndexFieldsProvider); ok {
+			rec.Fields = p.IndexFields()
+		}
+	}
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return nil, fmt.Errorf("marshal event %v failed: %w", e.Id, err)
+	}
+	rec.Data = data
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return nil, fmt.Errorf("marshal event %v failed: %w", e.Id, err)
+	}
+	return append(b, '\n'), nil
+}
+
+func (r *record) event() Event {
+	e := Event{
+		Id:     r.Id,
+		Traits: r.Traits,
+		Time:   r.Time,
+	}
+	if e.Traits == nil {
+		e.Traits = NewTraits()
+	}
+	if len(r.Data) > 0 && !bytes.Equal(r.Data, []byte("null")) {
+		e.Data = &StoredData{
+			title:  r.Title,
+			domain: r.Domain,
+			fields: r.Fields,
+			raw:    r.Data,
+		}
+	}
+	return e
+}
+
+func (d *StoredData) Title() string {
+	return d.title
+}
+
+func (d *StoredData) Domain() string {
+	return d.domain
+}
+
+func (d *StoredData) IndexFields() map[string]any {
+	return d.fields
+}
+
+func (d *StoredData) MarshalJSON() ([]byte, error) {
+	return d.raw, nil
+}
@@ -0,0 +1,271 @@
+package events_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"mokapi/runtime/events"
+	"mokapi/runtime/events/eventstest"
+	"mokapi/runtime/search"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStorage(t *testing.T) {
+	testcases := []struct {
+		name string
+		test func(t *testing.T, dir string)
+	}{
+		{
+			name: "events survive restart",
+			test: func(t *testing.T, dir string) {
+				sm := newPersistentStoreManager(t, dir, 0, 0, &index{})
+				sm.SetStore(10, events.NewTraits().WithNamespace("foo"))
+				err := sm.Push(&eventstest.Event{Name: "foo", Api: "My API"}, events.NewTraits().WithNamespace("foo"))
+				require.NoError(t, err)
+				require.NoError(t, sm.Close())
+
+				idx := &recordIndex{}
+				sm = newPersistentStoreManager(t, dir, 0, 0, idx)
+				sm.SetStore(10, events.NewTraits().WithNamespace("foo"))
+				require.NoError(t, sm.Restore())
+
+				evts := sm.GetEvents(events.NewTraits().WithNamespace("foo"))
+				require.Len(t, evts, 1)
+				require.Equal(t, "foo", evts[0].Data.Title())
+				b, err := json.Marshal(evts[0].Data)
+				require.NoError(t, err)
+				require.Equal(t, `{"Name":"foo","api":"My API"}`, string(b))
+
+				require.Len(t, idx.added, 1)
+				doc := idx.added[evts[0].Id].(map[string]any)
+				require.Equal(t, "foo", doc["_title"])
+				require.Equal(t, "foo", doc["name"])
+				require.Equal(t, "My API", doc["domain"])
+			},
+		},
+		{
+			name: "restored event moves to more specific store",
+			test: func(t *testing.T, dir string) {
+				sm := newPersistentStoreManager(t, dir, 0, 0, &index{})
+				sm.SetStore(10, events.NewTraits().WithNamespace("http"))
+				sm.SetStore(10, events.NewTraits().WithNamespace("http").WithName("foo"))
+				err := sm.Push(&eventstest.Event{Name: "foo"}, events.NewTraits().WithNamespace("http").WithName("foo"))
+				require.NoError(t, err)
+				require.NoError(t, sm.Close())
+
+				sm = newPersistentStoreManager(t, dir, 0, 0, &index{})
+				sm.SetStore(10, events.NewTraits().WithNamespace("http"))
+				require.NoError(t, sm.Restore())
+				sm.SetStore(10, events.NewTraits().WithNamespace("http").WithName("foo"))
+
+				stores := sm.GetStores(events.NewTraits().WithNamespace("http").WithName("foo"))
+				require.Len(t, stores, 2)
+				require.Equal(t, 0, stores[0].NumEvents)
+				require.Equal(t, 1, stores[1].NumEvents)
+			},
+		},
+		{
+			name: "store size limits restored events",
+			test: func(t *testing.T, dir string) {
+				sm := newPersistentStoreManager(t, dir, 0, 0, &index{})
+				sm.SetStore(10, events.NewTraits().WithNamespace("foo"))
+				for i := 0; i < 5; i++ {
+					err := sm.Push(&eventstest.Event{Name: fmt.Sprintf("%v", i)}, events.NewTraits().WithNamespace("foo"))
+					require.NoError(t, err)
+				}
+				require.NoError(t, sm.Close())
+
+				sm = newPersistentStoreManager(t, dir, 0, 0, &index{})
+				sm.SetStore(2, events.NewTraits().WithNamespace("foo"))
+				require.NoError(t, sm.Restore())
+
+				evts := sm.GetEvents(events.NewTraits().WithNamespace("foo"))
+				require.Len(t, evts, 2)
+				require.Equal(t, "4", evts[0].Data.Title())
+				require.Equal(t, "3", evts[1].Data.Title())
+				// events exceeding the store size are kept on disk
+				require.Equal(t, 5, countLines(t, dir))
+				require.NoError(t, sm.Close())
+
+				sm = newPersistentStoreManager(t, dir, 0, 0, &index{})
+				sm.SetStore(10, events.NewTraits().WithNamespace("foo"))
+				require.NoError(t, sm.Restore())
+				require.Len(t, sm.GetEvents(events.NewTraits().WithNamespace("foo")), 5)
+			},
+		},
+		{
+			name: "updated event is restored with its changes",
+			test: func(t *testing.T, dir string) {
+				sm := newPersistentStoreManager(t, dir, 0, 0, &index{})
+				sm.SetStore(10, events.NewTraits().WithNamespace("foo"))
+				data := &eventstest.Event{Name: "foo"}
+				err := sm.Push(data, events.NewTraits().WithNamespace("foo"))
+				require.NoError(t, err)
+				data.Api = "My API"
+				events.Update(sm, data)
+				require.NoError(t, sm.Close())
+
+				sm = newPersistentStoreManager(t, dir, 0, 0, &index{})
+				sm.SetStore(10, events.NewTraits().WithNamespace("foo"))
+				require.NoError(t, sm.Restore())
+
+				evts := sm.GetEvents(events.NewTraits().WithNamespace("foo"))
+				require.Len(t, evts, 1)
+				b, err := json.Marshal(evts[0].Data)
+				require.NoError(t, err)
+				require.Equal(t, `{"Name":"foo","api":"My API"}`, string(b))
+				require.Equal(t, 1, countLines(t, dir))
+			},
+		},
+		{
+			name: "concurrent updates and compaction",
+			test: func(t *testing.T, dir string) {
+				sm := newPersistentStoreManager(t, dir, 0, 2048, &index{})
+				sm.SetStore(5, events.NewTraits().WithNamespace("foo"))
+
+				var wg sync.WaitGroup
+				for i := 0; i < 10; i++ {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						for j := 0; j < 20; j++ {
+							data := &eventstest.Event{Name: fmt.Sprintf("%v-%v", i, j)}
+							_ = sm.Push(data, events.NewTraits().WithNamespace("foo"))
+							data.Api = "done"
+							events.Update(sm, data)
+						}
+					}(i)
+				}
+				wg.Wait()
+				require.NoError(t, sm.Close())
+
+				sm = newPersistentStoreManager(t, dir, 0, 2048, &index{})
+				sm.SetStore(100, events.NewTraits().WithNamespace("foo"))
+				require.NoError(t, sm.Restore())
+
+				evts := sm.GetEvents(events.NewTraits().WithNamespace("foo"))
+				require.Greater(t, len(evts), 5)
+				for _, e := range evts {
+					b, err := json.Marshal(e.Data)
+					require.NoError(t, err)
+					require.Contains(t, string(b), `"api":"done"`)
+				}
+			},
+		},
+		{
+			name: "expired events are not restored",
+			test: func(t *testing.T, dir string) {
+				writeLog(t, dir,
+					`{"id":"1","traits":{"namespace":"foo"},"time":"%v","title":"old","data":{}}`, time.Now().Add(-2*time.Hour),
+					`{"id":"2","traits":{"namespace":"foo"},"time":"%v","title":"new","data":{}}`, time.Now(),
+				)
+
+				sm := newPersistentStoreManager(t, dir, time.Hour, 0, &index{})
+				sm.SetStore(10, events.NewTraits().WithNamespace("foo"))
+				require.NoError(t, sm.Restore())
+
+				evts := sm.GetEvents(events.NewTraits().WithNamespace("foo"))
+				require.Len(t, evts, 1)
+				require.Equal(t, "new", evts[0].Data.Title())
+				require.Equal(t, 1, countLines(t, dir))
+			},
+		},
+		{
+			name: "invalid lines are skipped",
+			test: func(t *testing.T, dir string) {
+				writeLog(t, dir,
+					`{"id":"1","traits":{"namespace":"foo"},"time":"%v","title":"foo","data":null}`, time.Now(),
+					`{"id":"2", %v`, "broken",
+				)
+
+				sm := newPersistentStoreManager(t, dir, 0, 0, &index{})
+				sm.SetStore(10, events.NewTraits().WithNamespace("foo"))
+				require.NoError(t, sm.Restore())
+
+				evts := sm.GetEvents(events.NewTraits().WithNamespace("foo"))
+				require.Len(t, evts, 1)
+				require.Equal(t, "1", evts[0].Id)
+				require.Nil(t, evts[0].Data)
+			},
+		},
+		{
+			name: "max size drops oldest events",
+			test: func(t *testing.T, dir string) {
+				idx := &recordIndex{}
+				sm := newPersistentStoreManager(t, dir, 0, 1024, idx)
+				sm.SetStore(100, events.NewTraits().WithNamespace("foo"))
+				for i := 0; i < 50; i++ {
+					err := sm.Push(&eventstest.Event{Name: fmt.Sprintf("%v", i)}, events.NewTraits().WithNamespace("foo"))
+					require.NoError(t, err)
+				}
+
+				evts := sm.GetEvents(events.NewTraits().WithNamespace("foo"))
+				require.Less(t, len(evts), 50)
+				require.Equal(t, "49", evts[0].Data.Title())
+				require.NotEmpty(t, idx.deleted)
+
+				fi, err := os.Stat(filepath.Join(dir, "events.log"))
+				require.NoError(t, err)
+				require.LessOrEqual(t, fi.Size(), int64(1024))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, t.TempDir())
+		})
+	}
+}
+
+func newPersistentStoreManager(t *testing.T, dir string, maxAge time.Duration, maxSize int64, idx search.Index) *events.StoreManager {
+	s, err := events.NewFileStorage(dir, maxAge, maxSize)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	sm := events.NewStoreManager(idx)
+	sm.SetStorage(s)
+	return sm
+}
+
+func writeLog(t *testing.T, dir string, lines ...any) {
+	var sb strings.Builder
+	for i := 0; i < len(lines); i += 2 {
+		v := lines[i+1]
+		if tm, ok := v.(time.Time); ok {
+			v = tm.Format(time.RFC3339Nano)
+		}
+		sb.WriteString(fmt.Sprintf(lines[i].(string), v))
+		sb.WriteString("\n")
+	}
+	err := os.WriteFile(filepath.Join(dir, "events.log"), []byte(sb.String()), 0o644)
+	require.NoError(t, err)
+}
+
+func countLines(t *testing.T, dir string) int {
+	b, err := os.ReadFile(filepath.Join(dir, "events.log"))
+	require.NoError(t, err)
+	return strings.Count(string(b), "\n")
+}
+
+type recordIndex struct {
+	added   map[string]any
+	deleted []string
+}
+
+func (i *recordIndex) Add(id string, data any) {
+	if i.added == nil {
+		i.added = map[string]any{}
+	}
+	i.added[id] = data
+}
+
+func (i *recordIndex) Delete(id string) {
+	i.deleted = append(i.deleted, id)
+}
@@ -1,6 +1,9 @@
 package events
 
-import "sync"
+import (
+	"sync"
+	"time"
+)
 
 const defaultSize = 20
 
@@ -44,6 +47,74 @@ func (s *store) Events(traits Traits) []Event {
 	return events
 }
 
+func (s *store) all() []Event {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	return append([]Event(nil), s.events...)
+}
+
+// expire removes all events older than the given time
+func (s *store) expire(before time.Time) []Event {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	i := len(s.events)
+	for i > 0 && s.events[i-1].Time.Before(before) {
+		i--
+	}
+	if i == len(s.events) {
+		return nil
+	}
+	removed := append([]Event(nil), s.events[i:]...)
+	s.events = s.events[:i]
+	return removed
+}
+
+// take removes and returns all events matching the given traits
+func (s *store) take(traits Traits) []Event {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var taken []Event
+	kept := s.events[:0]
+	for _, e := range s.events {
+		if traits.Match(e.Traits) {
+			taken = append(taken, e)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	s.events = kept
+	return taken
+}
+
+// find returns the event holding the given data
+func (s *store) find(data EventData) (Event, bool) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	for _, e := range s.events {
+		if e.Data == data {
+			return e, true
+		}
+	}
+	return Event{}, false
+}
+
+func (s *store) remove(ids map[string]bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	kept := s.events[:0]
+	for _, e := range s.events {
+		if !ids[e.Id] {
+			kept = append(kept, e)
+		}
+	}
+	s.events = kept
+}
+
 func (s *store) some(traits Traits) bool {
 	if len(traits) == 0 {
 		return true
@@ -0,0 +1,369 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Matcher selects events to verify received traffic. At most one
+// protocol matcher can be set. Empty fields match any value.
+type Matcher struct {
+	// Traits limits the events, e.g. namespace=http, name=Petstore
+	Traits Traits        `json:"traits,omitempty"`
+	Http   *HttpMatcher  `json:"http,omitempty"`
+	Kafka  *KafkaMatcher `json:"kafka,omitempty"`
+	Mail   *MailMatcher  `json:"mail,omitempty"`
+	Ldap   *LdapMatcher  `json:"ldap,omitempty"`
+}
+
+type HttpMatcher struct {
+	Api    string `json:"api,omitempty"`
+	Method string `json:"method,omitempty"`
+	// Path is matched against the request path and the path of the
+	// specification, e.g. /pets/12 or /pets/{petId}. Wildcards like
+	// /pets/* are supported.
+	Path       string            `json:"path,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	StatusCode int               `json:"statusCode,omitempty"`
+	// Body maps a JSONPath expression to the expected value,
+	// e.g. {"$.status": "open"}
+	Body map[string]any `json:"body,omitempty"`
+}
+
+type KafkaMatcher struct {
+	Cluster string            `json:"cluster,omitempty"`
+	Topic   string            `json:"topic,omitempty"`
+	Key     string            `json:"key,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+type MailMatcher struct {
+	Server  string `json:"server,omitempty"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	Subject string `json:"subject,omitempty"`
+}
+
+type LdapMatcher struct {
+	Server    string `json:"server,omitempty"`
+	Operation string `json:"operation,omitempty"`
+	Dn        string `json:"dn,omitempty"`
+}
+
+type VerifyResult struct {
+	Count  int     `json:"count"`
+	Events []Event `json:"events"`
+}
+
+type eventMatcher struct {
+	traits Traits
+	match  func(data map[string]any) bool
+}
+
+// Verify returns all events matching the given matcher, newest first
+func (m *StoreManager) Verify(matcher Matcher) (VerifyResult, error) {
+	em, err := matcher.compile()
+	if err != nil {
+		return VerifyResult{}, err
+	}
+
+	result := VerifyResult{Events: []Event{}}
+	for _, e := range m.GetEvents(em.traits) {
+		if em.match != nil {
+			data, ok := toMap(e.Data)
+			if !ok || !em.match(data) {
+				continue
+			}
+		}
+		result.Events = append(result.Events, e)
+	}
+	result.Count = len(result.Events)
+	return result, nil
+}
+
+// ClearEvents removes all events matching the given traits and returns
+// the number of removed events. Unlike ResetStores, the stores are kept.
+func (m *StoreManager) ClearEvents(traits Traits) int {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
+	n := 0
+	for _, s := range m.stores {
+		for _, e := range s.take(traits) {
+			m.deleteFromIndex(e.Id)
+			n++
+		}
+	}
+	if n > 0 && m.storage != nil {
+		if err := m.compact(); err != nil {
+			log.Errorf("failed to compact event storage: %v", err)
+		}
+	}
+	return n
+}
+
+func (m Matcher) compile() (*eventMatcher, error) {
+	traits := NewTraits()
+	for k, v := range m.Traits {
+		traits.With(k, v)
+	}
+
+	var protocols []string
+	em := &eventMatcher{traits: traits}
+	if m.Http != nil {
+		protocols = append(protocols, "http")
+		if err := m.Http.compile(em); err != nil {
+			return nil, err
+		}
+	}
+	if m.Kafka != nil {
+		protocols = append(protocols, "kafka")
+		m.Kafka.compile(em)
+	}
+	if m.Mail != nil {
+		protocols = append(protocols, "mail")
+		m.Mail.compile(em)
+	}
+	if m.Ldap != nil {
+		protocols = append(protocols, "ldap")
+		m.Ldap.compile(em)
+	}
+	if len(protocols) > 1 {
+		return nil, fmt.Errorf("only one protocol matcher allowed: %v", strings.Join(protocols, ", "))
+	}
+	return em, nil
+}
+
+func (h *HttpMatcher) compile(em *eventMatcher) error {
+	em.traits.WithNamespace("http")
+	if h.Api != "" {
+		em.traits.WithName(h.Api)
+	}
+
+	type bodyMatch struct {
+		path     jsonPath
+		expected any
+	}
+	var body []bodyMatch
+	for expr, expected := range h.Body {
+		p, err := parseJsonPath(expr)
+		if err != nil {
+			return err
+		}
+		body = append(body, bodyMatch{path: p, expected: normalize(expected)})
+	}
+
+	em.match = func(data map[string]any) bool {
+		req := getMap(data, "request")
+		if h.Method != "" && !strings.EqualFold(getString(req, "method"), h.Method) {
+			return false
+		}
+		if h.Path != "" {
+			requestPath := ""
+			if u, err := url.Parse(getString(req, "url")); err == nil {
+				requestPath = u.Path
+			}
+			if !matchPath(h.Path, requestPath) && !matchPath(h.Path, getString(data, "path")) {
+				return false
+			}
+		}
+		if h.StatusCode != 0 {
+			if status, _ := getMap(data, "response")["statusCode"].(float64); int(status) != h.StatusCode {
+				return false
+			}
+		}
+		for name, value := range h.Headers {
+			if !hasHttpHeader(req, name, value) {
+				return false
+			}
+		}
+		if len(body) > 0 {
+			var v any
+			if err := json.Unmarshal([]byte(getString(req, "body")), &v); err != nil {
+				return false
+			}
+			for _, b := range body {
+				if !containsValue(b.path.eval(v), b.expected) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return nil
+}
+
+func (k *KafkaMatcher) compile(em *eventMatcher) {
+	em.traits.WithNamespace("kafka").With("type", "message")
+	if k.Cluster != "" {
+		em.traits.WithName(k.Cluster)
+	}
+	if k.Topic != "" {
+		em.traits.With("topic", k.Topic)
+	}
+
+	em.match = func(data map[string]any) bool {
+		if k.Key != "" && getLogValue(getMap(data, "key")) != k.Key {
+			return false
+		}
+		headers := getMap(data, "headers")
+		for name, value := range k.Headers {
+			h, ok := headers[name].(map[string]any)
+			if !ok || getLogValue(h) != value {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func (m *MailMatcher) compile(em *eventMatcher) {
+	em.traits.WithNamespace("mail")
+	if m.Server != "" {
+		em.traits.WithName(m.Server)
+	}
+
+	em.match = func(data map[string]any) bool {
+		if m.From != "" && !strings.EqualFold(getString(data, "from"), m.From) {
+			return false
+		}
+		if m.To != "" {
+			found := false
+			to, _ := data["to"].([]any)
+			for _, r := range to {
+				if s, ok := r.(string); ok && strings.EqualFold(s, m.To) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		if m.Subject != "" && getString(data, "subject") != m.Subject {
+			return false
+		}
+		return true
+	}
+}
+
+func (l *LdapMatcher) compile(em *eventMatcher) {
+	em.traits.WithNamespace("ldap")
+	if l.Server != "" {
+		em.traits.WithName(l.Server)
+	}
+	if l.Operation != "" {
+		em.traits.With("operation", strings.ToLower(l.Operation))
+	}
+
+	em.match = func(data map[string]any) bool {
+		if l.Dn == "" {
+			return true
+		}
+		req := getMap(data, "request")
+		// the DN is named differently depending on the operation
+		for _, key := range []string{"dn", "baseDN", "name"} {
+			if strings.EqualFold(getString(req, key), l.Dn) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func toMap(data EventData) (map[string]any, bool) {
+	if data == nil {
+		return nil, false
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, false
+	}
+	var m map[string]any
+	if err = json.Unmarshal(b, &m); err != nil {
+		return nil, false
+	}
+	return m, true
+}
+
+// normalize converts v to the types produced by encoding/json
+func normalize(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var n any
+	if err = json.Unmarshal(b, &n); err != nil {
+		return v
+	}
+	return n
+}
+
+func containsValue(values []any, expected any) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPath(pattern, p string) bool {
+	if p == "" {
+		return false
+	}
+	if pattern == p {
+		return true
+	}
+	ok, _ := path.Match(pattern, p)
+	return ok
+}
+
+func hasHttpHeader(req map[string]any, name, value string) bool {
+	params, _ := req["parameters"].([]any)
+	for _, p := range params {
+		param, ok := p.(map[string]any)
+		if !ok || getString(param, "type") != "header" {
+			continue
+		}
+		if strings.EqualFold(getString(param, "name"), name) {
+			if raw, ok := param["raw"].(string); ok && raw == value {
+				return true
+			}
+			if getString(param, "value") == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getLogValue(v map[string]any) string {
+	if s := getString(v, "value"); s != "" {
+		return s
+	}
+	// []byte is encoded as base64 by encoding/json
+	if b, ok := v["binary"].(string); ok && b != "" {
+		var decoded []byte
+		if err := json.Unmarshal([]byte(`"`+b+`"`), &decoded); err == nil {
+			return string(decoded)
+		}
+	}
+	return ""
+}
+
+func getMap(m map[string]any, key string) map[string]any {
+	v, _ := m[key].(map[string]any)
+	return v
+}
+
+func getString(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
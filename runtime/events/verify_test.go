@@ -0,0 +1,187 @@
+package events_test
+
+import (
+	"mokapi/providers/asyncapi3/kafka/store"
+	"mokapi/providers/directory"
+	"mokapi/providers/mail"
+	"mokapi/providers/openapi"
+	"mokapi/runtime/events"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreManager_Verify(t *testing.T) {
+	contentType := "application/json"
+	push := func(sm *events.StoreManager) {
+		_ = sm.Push(&openapi.HttpLog{
+			Request: &openapi.HttpRequestLog{
+				Method: "POST",
+				Url:    "http://localhost/orders",
+				Parameters: []openapi.HttpParameter{
+					{Name: "Content-Type", Type: "header", Raw: &contentType},
+				},
+				Body: `{"status":"open","items":[{"sku":"A-1"},{"sku":"B-2"}]}`,
+			},
+			Response: &openapi.HttpResponseLog{StatusCode: 201},
+			Path:     "/orders",
+		}, events.NewTraits().WithNamespace("http").WithName("shop"))
+		_ = sm.Push(&openapi.HttpLog{
+			Request:  &openapi.HttpRequestLog{Method: "GET", Url: "http://localhost/orders/12"},
+			Response: &openapi.HttpResponseLog{StatusCode: 200},
+			Path:     "/orders/{id}",
+		}, events.NewTraits().WithNamespace("http").WithName("shop"))
+		_ = sm.Push(&store.KafkaMessageLog{
+			Key:     store.LogValue{Value: "order-1"},
+			Headers: map[string]store.LogValue{"source": {Binary: []byte("shop")}},
+		}, events.NewTraits().WithNamespace("kafka").WithName("cluster").With("type", "message").With("topic", "orders"))
+		_ = sm.Push(&mail.Log{
+			From:    "shop@example.com",
+			To:      []string{"alice@example.com", "bob@example.com"},
+			Subject: "Your order",
+		}, events.NewTraits().WithNamespace("mail").WithName("smtp"))
+		_ = sm.Push(&directory.SearchLog{
+			Request: &directory.SearchRequest{Operation: "Search", BaseDN: "dc=example,dc=com"},
+		}, events.NewTraits().WithNamespace("ldap").WithName("ldap").With("operation", "search"))
+	}
+
+	testcases := []struct {
+		name    string
+		matcher events.Matcher
+		count   int
+		err     string
+	}{
+		{
+			name:    "all events",
+			matcher: events.Matcher{},
+			count:   5,
+		},
+		{
+			name:    "traits",
+			matcher: events.Matcher{Traits: events.NewTraits().WithNamespace("http")},
+			count:   2,
+		},
+		{
+			name:    "http method and path",
+			matcher: events.Matcher{Http: &events.HttpMatcher{Method: "post", Path: "/orders"}},
+			count:   1,
+		},
+		{
+			name:    "http path of specification",
+			matcher: events.Matcher{Http: &events.HttpMatcher{Path: "/orders/{id}"}},
+			count:   1,
+		},
+		{
+			name:    "http path with wildcard",
+			matcher: events.Matcher{Http: &events.HttpMatcher{Path: "/orders/*"}},
+			count:   1,
+		},
+		{
+			name:    "http header",
+			matcher: events.Matcher{Http: &events.HttpMatcher{Headers: map[string]string{"content-type": "application/json"}}},
+			count:   1,
+		},
+		{
+			name:    "http status code",
+			matcher: events.Matcher{Http: &events.HttpMatcher{StatusCode: 200}},
+			count:   1,
+		},
+		{
+			name: "http body",
+			matcher: events.Matcher{Http: &events.HttpMatcher{Body: map[string]any{
+				"$.status":          "open",
+				"$.items[*].sku":    "B-2",
+				"$['items'][0].sku": "A-1",
+			}}},
+			count: 1,
+		},
+		{
+			name:    "http body does not match",
+			matcher: events.Matcher{Http: &events.HttpMatcher{Body: map[string]any{"$.status": "closed"}}},
+			count:   0,
+		},
+		{
+			name:    "http api does not match",
+			matcher: events.Matcher{Http: &events.HttpMatcher{Api: "foo"}},
+			count:   0,
+		},
+		{
+			name:    "invalid JSONPath",
+			matcher: events.Matcher{Http: &events.HttpMatcher{Body: map[string]any{"status": "open"}}},
+			err:     "invalid JSONPath 'status': must start with $",
+		},
+		{
+			name:    "kafka topic, key and header",
+			matcher: events.Matcher{Kafka: &events.KafkaMatcher{Topic: "orders", Key: "order-1", Headers: map[string]string{"source": "shop"}}},
+			count:   1,
+		},
+		{
+			name:    "kafka key does not match",
+			matcher: events.Matcher{Kafka: &events.KafkaMatcher{Key: "order-2"}},
+			count:   0,
+		},
+		{
+			name:    "mail recipient",
+			matcher: events.Matcher{Mail: &events.MailMatcher{From: "shop@example.com", To: "Bob@example.com", Subject: "Your order"}},
+			count:   1,
+		},
+		{
+			name:    "mail recipient does not match",
+			matcher: events.Matcher{Mail: &events.MailMatcher{To: "carol@example.com"}},
+			count:   0,
+		},
+		{
+			name:    "ldap operation and dn",
+			matcher: events.Matcher{Ldap: &events.LdapMatcher{Operation: "Search", Dn: "dc=example,dc=com"}},
+			count:   1,
+		},
+		{
+			name:    "ldap operation does not match",
+			matcher: events.Matcher{Ldap: &events.LdapMatcher{Operation: "bind"}},
+			count:   0,
+		},
+		{
+			name:    "more than one protocol",
+			matcher: events.Matcher{Http: &events.HttpMatcher{}, Kafka: &events.KafkaMatcher{}},
+			err:     "only one protocol matcher allowed: http, kafka",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			sm := events.NewStoreManager(&index{})
+			sm.SetStore(10, events.NewTraits())
+			push(sm)
+
+			r, err := sm.Verify(tc.matcher)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.count, r.Count)
+			require.Len(t, r.Events, tc.count)
+		})
+	}
+}
+
+func TestStoreManager_ClearEvents(t *testing.T) {
+	sm := events.NewStoreManager(&index{})
+	sm.SetStore(10, events.NewTraits())
+	sm.SetStore(10, events.NewTraits().WithNamespace("http"))
+	_ = sm.Push(&openapi.HttpLog{}, events.NewTraits().WithNamespace("http").WithName("foo"))
+	_ = sm.Push(&openapi.HttpLog{}, events.NewTraits().WithNamespace("http").WithName("bar"))
+	_ = sm.Push(&mail.Log{}, events.NewTraits().WithNamespace("mail"))
+
+	n := sm.ClearEvents(events.NewTraits().WithNamespace("http").WithName("foo"))
+	require.Equal(t, 1, n)
+	require.Len(t, sm.GetEvents(events.NewTraits()), 2)
+
+	n = sm.ClearEvents(events.NewTraits())
+	require.Equal(t, 2, n)
+	require.Len(t, sm.GetEvents(events.NewTraits()), 0)
+	// stores are kept
+	err := sm.Push(&openapi.HttpLog{}, events.NewTraits().WithNamespace("http").WithName("foo"))
+	require.NoError(t, err)
+	require.Len(t, sm.GetEvents(events.NewTraits().WithNamespace("http")), 1)
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TextContentType is the content type of the Prometheus text exposition format
+const TextContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WriteText writes metrics in the Prometheus text exposition format.
+// Metrics are grouped by their fully qualified name and sorted, so the
+// output is stable between scrapes.
+func WriteText(w io.Writer, list []Metric) error {
+	families := map[string][]Metric{}
+	for _, m := range list {
+		name := m.Info().FQName()
+		families[name] = append(families[name], m)
+	}
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		family := families[name]
+		sort.SliceStable(family, func(i, j int) bool {
+			return family[i].Info().String() < family[j].Info().String()
+		})
+
+		typeName := "untyped"
+		switch family[0].(type) {
+		case *Counter:
+			typeName = "counter"
+		case *Gauge:
+			typeName = "gauge"
+		case *Histogram:
+			typeName = "histogram"
+		}
+		bw.WriteString("# TYPE " + name + " " + typeName + "\n")
+
+		for _, m := range family {
+			switch v := m.(type) {
+			case *Counter:
+				writeSample(bw, name, v.info.labels, nil, v.Value())
+			case *Gauge:
+				writeSample(bw, name, v.info.labels, nil, v.Value())
+			case *Histogram:
+				for _, b := range v.Buckets() {
+					writeSample(bw, name+"_bucket", v.info.labels, &Label{Name: "le", Value: formatFloat(b.UpperBound)}, float64(b.Count))
+				}
+				count := v.Count()
+				writeSample(bw, name+"_bucket", v.info.labels, &Label{Name: "le", Value: "+Inf"}, float64(count))
+				writeSample(bw, name+"_sum", v.info.labels, nil, v.Sum())
+				writeSample(bw, name+"_count", v.info.labels, nil, float64(count))
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+func writeSample(w *bufio.Writer, name string, labels []*Label, extra *Label, value float64) {
+	w.WriteString(name)
+	if len(labels) > 0 || extra != nil {
+		w.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, l)
+		}
+		if extra != nil {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, extra)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func writeLabel(w *bufio.Writer, l *Label) {
+	w.WriteString(l.Name)
+	w.WriteString(`="`)
+	labelValueEscaper.WriteString(w, l.Value)
+	w.WriteByte('"')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
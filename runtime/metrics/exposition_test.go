@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteText(t *testing.T) {
+	testcases := []struct {
+		name    string
+		metrics func() []Metric
+		exp     string
+	}{
+		{
+			name: "counter and gauge",
+			metrics: func() []Metric {
+				c := NewCounter(WithFQName("app", "job_run_total"))
+				c.Add(3)
+				g := NewGauge(WithFQName("app", "start_timestamp"))
+				g.Set(1.5e9)
+				return []Metric{g, c}
+			},
+			exp: `# TYPE app_job_run_total counter
+app_job_run_total 3
+# TYPE app_start_timestamp gauge
+app_start_timestamp 1.5e+09
+`,
+		},
+		{
+			name: "counter map with labels",
+			metrics: func() []Metric {
+				m := NewCounterMap(WithFQName("http", "requests_total"), WithLabelNames("service", "endpoint"))
+				m.WithLabel("foo", "/pets").Add(2)
+				m.WithLabel("bar", `/"quoted"\`).Add(1)
+				return collect(m)
+			},
+			exp: `# TYPE http_requests_total counter
+http_requests_total{service="bar",endpoint="/\"quoted\"\\"} 1
+http_requests_total{service="foo",endpoint="/pets"} 2
+`,
+		},
+		{
+			name: "histogram",
+			metrics: func() []Metric {
+				m := NewHistogramMap(WithFQName("http", "request_duration_seconds"), WithLabelNames("service"), WithBuckets(0.1, 1))
+				m.WithLabel("foo").Observe(0.05)
+				m.WithLabel("foo").Observe(0.5)
+				m.WithLabel("foo").Observe(5)
+				return collect(m)
+			},
+			exp: `# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{service="foo",le="0.1"} 1
+http_request_duration_seconds_bucket{service="foo",le="1"} 2
+http_request_duration_seconds_bucket{service="foo",le="+Inf"} 3
+http_request_duration_seconds_sum{service="foo"} 5.55
+http_request_duration_seconds_count{service="foo"} 3
+`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := WriteText(&buf, tc.metrics())
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, buf.String())
+		})
+	}
+}
+
+func collect(m Metric) []Metric {
+	ch := make(chan Metric)
+	go func() {
+		m.Collect(ch)
+		close(ch)
+	}()
+	var result []Metric
+	for metric := range ch {
+		result = append(result, metric)
+	}
+	return result
+}
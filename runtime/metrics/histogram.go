@@ -0,0 +1,198 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefBuckets are the default histogram buckets in seconds, the same
+// as used by Prometheus client libraries.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type Histogram struct {
+	info    *Info
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+	m       sync.Mutex
+}
+
+type Bucket struct {
+	UpperBound float64 `json:"le"`
+	Count      uint64  `json:"count"`
+}
+
+func NewHistogram(opt ...Options) *Histogram {
+	o := &options{}
+	for _, opt := range opt {
+		opt(o)
+	}
+	return newHistogram(&Info{Namespace: o.namespace, Name: o.name, labels: o.labels}, o.buckets)
+}
+
+func newHistogram(info *Info, buckets []float64) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	b := make([]float64, len(buckets))
+	copy(b, buckets)
+	sort.Float64s(b)
+	return &Histogram{info: info, buckets: b, counts: make([]uint64, len(b))}
+}
+
+// Observe adds a single observation to the histogram
+func (h *Histogram) Observe(v float64) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	i := sort.SearchFloat64s(h.buckets, v)
+	if i < len(h.counts) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += v
+}
+
+// Buckets returns the cumulative count for each upper bound, without the
+// implicit +Inf bucket which always equals Count.
+func (h *Histogram) Buckets() []Bucket {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	result := make([]Bucket, 0, len(h.buckets))
+	var cumulative uint64
+	for i, ub := range h.buckets {
+		cumulative += h.counts[i]
+		result = append(result, Bucket{UpperBound: ub, Count: cumulative})
+	}
+	return result
+}
+
+func (h *Histogram) Count() uint64 {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.count
+}
+
+func (h *Histogram) Sum() float64 {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.sum
+}
+
+func (h *Histogram) Info() *Info {
+	return h.info
+}
+
+func (h *Histogram) Collect(ch chan<- Metric) {
+	ch <- h
+}
+
+func (h *Histogram) Reset() {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.counts = make([]uint64, len(h.buckets))
+	h.count = 0
+	h.sum = 0
+}
+
+func (h *Histogram) MarshalJSON() ([]byte, error) {
+	aux := &struct {
+		Name    string   `json:"name"`
+		Count   uint64   `json:"count"`
+		Sum     float64  `json:"sum"`
+		Buckets []Bucket `json:"buckets"`
+	}{
+		Name:    h.Info().String(),
+		Count:   h.Count(),
+		Sum:     h.Sum(),
+		Buckets: h.Buckets(),
+	}
+	return json.Marshal(aux)
+}
+
+type HistogramMap struct {
+	info         *Info
+	histograms   map[uint32]*Histogram
+	newHistogram func(values []string) *Histogram
+	m            sync.Mutex
+}
+
+func NewHistogramMap(opts ...Options) *HistogramMap {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	info := &Info{Namespace: o.namespace, Name: o.name}
+	return &HistogramMap{
+		info:       info,
+		histograms: make(map[uint32]*Histogram),
+		newHistogram: func(values []string) *Histogram {
+			if len(o.labelNames) != len(values) {
+				panic(fmt.Sprintf("invalid labels values for %v", info))
+			}
+			labels := make([]*Label, 0)
+			for i, v := range values {
+				labels = append(labels, &Label{
+					Name:  o.labelNames[i],
+					Value: v,
+				})
+			}
+			return newHistogram(&Info{
+				Namespace: info.Namespace,
+				Name:      info.Name,
+				labels:    labels,
+			}, o.buckets)
+		},
+	}
+}
+
+func (m *HistogramMap) Info() *Info {
+	return m.info
+}
+
+func (m *HistogramMap) WithLabel(values ...string) *Histogram {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	key := hash(values)
+	h, ok := m.histograms[key]
+	if !ok {
+		h = m.newHistogram(values)
+		m.histograms[key] = h
+	}
+	return h
+}
+
+func (m *HistogramMap) FindOne(query *Query) (*Histogram, bool) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	for _, h := range m.histograms {
+		if h.Info().Match(query) {
+			return h, true
+		}
+	}
+	return nil, false
+}
+
+func (m *HistogramMap) Collect(ch chan<- Metric) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	for _, h := range m.histograms {
+		ch <- h
+	}
+}
+
+func (m *HistogramMap) Reset() {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	for _, h := range m.histograms {
+		h.Reset()
+	}
+}
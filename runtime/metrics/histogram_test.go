@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{
+			name: "observe",
+			f: func(t *testing.T) {
+				h := NewHistogram(WithName("foo"), WithBuckets(1, 0.1, 0.5))
+				h.Observe(0.05)
+				h.Observe(0.3)
+				h.Observe(0.5)
+				h.Observe(2)
+
+				require.Equal(t, uint64(4), h.Count())
+				require.Equal(t, 2.85, h.Sum())
+				require.Equal(t, []Bucket{
+					{UpperBound: 0.1, Count: 1},
+					{UpperBound: 0.5, Count: 3},
+					{UpperBound: 1, Count: 3},
+				}, h.Buckets())
+			},
+		},
+		{
+			name: "default buckets",
+			f: func(t *testing.T) {
+				h := NewHistogram(WithName("foo"))
+				require.Len(t, h.Buckets(), len(DefBuckets))
+			},
+		},
+		{
+			name: "reset",
+			f: func(t *testing.T) {
+				h := NewHistogram(WithName("foo"))
+				h.Observe(1)
+				h.Reset()
+				require.Equal(t, uint64(0), h.Count())
+				require.Equal(t, uint64(0), h.Buckets()[len(DefBuckets)-1].Count)
+			},
+		},
+		{
+			name: "json",
+			f: func(t *testing.T) {
+				h := NewHistogram(WithFQName("http", "duration"), WithBuckets(1))
+				h.Observe(0.5)
+				b, err := json.Marshal(h)
+				require.NoError(t, err)
+				require.Equal(t, `{"name":"http_duration","count":1,"sum":0.5,"buckets":[{"le":1,"count":1}]}`, string(b))
+			},
+		},
+		{
+			name: "map with labels",
+			f: func(t *testing.T) {
+				m := NewHistogramMap(WithFQName("http", "duration"), WithLabelNames("service"))
+				m.WithLabel("foo").Observe(1)
+				m.WithLabel("foo").Observe(2)
+				m.WithLabel("bar").Observe(3)
+
+				h, ok := m.FindOne(&Query{Labels: []*Label{{Name: "service", Value: "foo"}}})
+				require.True(t, ok)
+				require.Equal(t, uint64(2), h.Count())
+				require.Equal(t, `http_duration{service="foo"}`, h.Info().String())
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.f(t)
+		})
+	}
+}
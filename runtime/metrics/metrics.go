@@ -83,6 +83,7 @@ type options struct {
 	name       string
 	labels     []*Label
 	labelNames []string
+	buckets    []float64
 }
 
 func WithName(name string) Options {
@@ -97,6 +98,13 @@ func WithLabelNames(names ...string) Options {
 	}
 }
 
+// WithBuckets sets the upper bounds of histogram buckets. If not set, DefBuckets is used.
+func WithBuckets(buckets ...float64) Options {
+	return func(o *options) {
+		o.buckets = buckets
+	}
+}
+
 func WithFQName(namespace, name string) Options {
 	return func(o *options) {
 		o.namespace = namespace
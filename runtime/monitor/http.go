@@ -11,6 +11,7 @@ type Http struct {
 	RequestCounter      *metrics.CounterMap
 	RequestErrorCounter *metrics.CounterMap
 	LastRequest         *metrics.GaugeMap
+	RequestDuration     *metrics.HistogramMap
 }
 
 func NewHttp() *Http {
@@ -23,22 +24,27 @@ func NewHttp() *Http {
 	httpLastRequest := metrics.NewGaugeMap(
 		metrics.WithFQName("http", "request_timestamp"),
 		metrics.WithLabelNames("service", "endpoint", "method"))
+	httpRequestDuration := metrics.NewHistogramMap(
+		metrics.WithFQName("http", "request_duration_seconds"),
+		metrics.WithLabelNames("service", "endpoint", "method"))
 
 	return &Http{
 		RequestCounter:      httpRequestCounter,
 		RequestErrorCounter: httpRequestErrorCounter,
 		LastRequest:         httpLastRequest,
+		RequestDuration:     httpRequestDuration,
 	}
 }
 
 func (h *Http) Metrics() []metrics.Metric {
-	return []metrics.Metric{h.RequestCounter, h.RequestErrorCounter, h.LastRequest}
+	return []metrics.Metric{h.RequestCounter, h.RequestErrorCounter, h.LastRequest, h.RequestDuration}
 }
 
 func (h *Http) Reset() {
 	h.RequestCounter.Reset()
 	h.RequestErrorCounter.Reset()
 	h.LastRequest.Reset()
+	h.RequestDuration.Reset()
 }
 
 func NewHttpContext(ctx context.Context, http *Http) context.Context {
@@ -13,6 +13,11 @@ type Kafka struct {
 	Lags            *metrics.GaugeMap
 	Commits         *metrics.GaugeMap
 	LastRebalancing *metrics.GaugeMap
+	ProduceDuration *metrics.HistogramMap
+	FetchDuration   *metrics.HistogramMap
+	// log compaction of topics with cleanup policy compact
+	Compactions      *metrics.CounterMap
+	CompactedRecords *metrics.CounterMap
 }
 
 func NewKafka() *Kafka {
@@ -34,18 +39,36 @@ func NewKafka() *Kafka {
 			metrics.WithFQName("kafka", "rebalance_timestamp"),
 			metrics.WithLabelNames("service", "group"),
 		)
+	produceDuration := metrics.NewHistogramMap(
+		metrics.WithFQName("kafka", "produce_duration_seconds"),
+		metrics.WithLabelNames("service"))
+	// fetch requests wait up to MaxWaitTime for new messages
+	fetchDuration := metrics.NewHistogramMap(
+		metrics.WithFQName("kafka", "fetch_duration_seconds"),
+		metrics.WithLabelNames("service"),
+		metrics.WithBuckets(.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30))
+	compactions := metrics.NewCounterMap(
+		metrics.WithFQName("kafka", "log_compactions_total"),
+		metrics.WithLabelNames("service", "topic"))
+	compactedRecords := metrics.NewCounterMap(
+		metrics.WithFQName("kafka", "log_compacted_records_total"),
+		metrics.WithLabelNames("service", "topic"))
 
 	return &Kafka{
-		Messages:        messages,
-		LastMessage:     lastMessage,
-		Lags:            lag,
-		LastRebalancing: lastRebalancing,
-		Commits:         commits,
+		Messages:         messages,
+		LastMessage:      lastMessage,
+		Lags:             lag,
+		LastRebalancing:  lastRebalancing,
+		Commits:          commits,
+		ProduceDuration:  produceDuration,
+		FetchDuration:    fetchDuration,
+		Compactions:      compactions,
+		CompactedRecords: compactedRecords,
 	}
 }
 
 func (k *Kafka) Metrics() []metrics.Metric {
-	return []metrics.Metric{k.Messages, k.LastMessage, k.Lags, k.Commits, k.LastRebalancing}
+	return []metrics.Metric{k.Messages, k.LastMessage, k.Lags, k.Commits, k.LastRebalancing, k.ProduceDuration, k.FetchDuration, k.Compactions, k.CompactedRecords}
 }
 
 func (k *Kafka) Reset() {
@@ -54,6 +77,10 @@ func (k *Kafka) Reset() {
 	k.Lags.Reset()
 	k.Commits.Reset()
 	k.LastRebalancing.Reset()
+	k.ProduceDuration.Reset()
+	k.FetchDuration.Reset()
+	k.Compactions.Reset()
+	k.CompactedRecords.Reset()
 }
 
 func NewKafkaContext(ctx context.Context, kafka *Kafka) context.Context {
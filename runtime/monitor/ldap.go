@@ -8,9 +8,10 @@ import (
 var ldapKey = contextKey("ldap")
 
 type Ldap struct {
-	Errors         *metrics.CounterMap
-	RequestCounter *metrics.CounterMap
-	LastRequest    *metrics.GaugeMap
+	Errors          *metrics.CounterMap
+	RequestCounter  *metrics.CounterMap
+	LastRequest     *metrics.GaugeMap
+	RequestDuration *metrics.HistogramMap
 }
 
 func NewLdap() *Ldap {
@@ -23,22 +24,27 @@ func NewLdap() *Ldap {
 	lastRequest := metrics.NewGaugeMap(
 		metrics.WithFQName("ldap", "request_timestamp"),
 		metrics.WithLabelNames("service"))
+	requestDuration := metrics.NewHistogramMap(
+		metrics.WithFQName("ldap", "request_duration_seconds"),
+		metrics.WithLabelNames("service", "operation"))
 
 	return &Ldap{
-		RequestCounter: requests,
-		Errors:         errors,
-		LastRequest:    lastRequest,
+		RequestCounter:  requests,
+		Errors:          errors,
+		LastRequest:     lastRequest,
+		RequestDuration: requestDuration,
 	}
 }
 
 func (l *Ldap) Metrics() []metrics.Metric {
-	return []metrics.Metric{l.RequestCounter, l.Errors, l.LastRequest}
+	return []metrics.Metric{l.RequestCounter, l.Errors, l.LastRequest, l.RequestDuration}
 }
 
 func (l *Ldap) Reset() {
 	l.RequestCounter.Reset()
 	l.Errors.Reset()
 	l.LastRequest.Reset()
+	l.RequestDuration.Reset()
 }
 
 func NewLdapContext(ctx context.Context, ldap *Ldap) context.Context {
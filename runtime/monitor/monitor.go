@@ -13,6 +13,8 @@ type Monitor struct {
 	MemoryUsage *metrics.Gauge   `json:"memstats_alloc_bytes"`
 	JobCounter  *metrics.Counter `json:"job_counter"`
 
+	EventHandlerDuration *metrics.HistogramMap `json:"-"`
+
 	Http  *Http  `json:"http"`
 	Kafka *Kafka `json:"kafka"`
 	Mqtt  *Mqtt  `json:"mqtt"`
@@ -30,6 +32,9 @@ func New() *Monitor {
 	startTime := metrics.NewGauge(metrics.WithFQName("app", "start_timestamp"))
 	memoryUsage := metrics.NewGauge(metrics.WithFQName("app", "memory_usage_bytes"))
 	jobCounter := metrics.NewCounter(metrics.WithFQName("app", "job_run_total"))
+	eventHandlerDuration := metrics.NewHistogramMap(
+		metrics.WithFQName("app", "event_handler_duration_seconds"),
+		metrics.WithLabelNames("event"))
 
 	h := NewHttp()
 	k := NewKafka()
@@ -41,6 +46,7 @@ func New() *Monitor {
 		startTime,
 		memoryUsage,
 		jobCounter,
+		eventHandlerDuration,
 	}
 	collection = append(collection, h.Metrics()...)
 	collection = append(collection, k.Metrics()...)
@@ -49,16 +55,17 @@ func New() *Monitor {
 	collection = append(collection, s.Metrics()...)
 
 	mon := &Monitor{
-		RefreshRateSeconds: 5,
-		StartTime:          startTime,
-		MemoryUsage:        memoryUsage,
-		JobCounter:         jobCounter,
-		Http:               h,
-		Kafka:              k,
-		Mqtt:               m,
-		Ldap:               l,
-		Mail:               s,
-		metrics:            collection,
+		RefreshRateSeconds:   5,
+		StartTime:            startTime,
+		MemoryUsage:          memoryUsage,
+		JobCounter:           jobCounter,
+		EventHandlerDuration: eventHandlerDuration,
+		Http:                 h,
+		Kafka:                k,
+		Mqtt:                 m,
+		Ldap:                 l,
+		Mail:                 s,
+		metrics:              collection,
 	}
 
 	mon.StartTime.Set(float64(time.Now().Unix()))
@@ -84,6 +91,7 @@ func (m *Monitor) Reset() {
 	m.Kafka.Reset()
 	m.Mail.Reset()
 	m.Ldap.Reset()
+	m.EventHandlerDuration.Reset()
 }
 
 func (m *Monitor) update() {
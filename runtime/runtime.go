@@ -9,7 +9,11 @@ import (
 	"mokapi/runtime/search"
 	"mokapi/safe"
 	"mokapi/version"
+	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -22,6 +26,7 @@ type App struct {
 	Kafka     *KafkaStore
 	Mqtt      *MqttStore
 	Mail      *MailStore
+	WebSocket *WebSocketStore
 
 	Monitor *monitor.Monitor
 	Events  *events.StoreManager
@@ -34,7 +39,15 @@ type App struct {
 	reader dynamic.Reader
 	hook   *LogHook
 
-	Configs map[string]*dynamic.Config
+	Configs      map[string]*dynamic.Config
+	configErrors map[string]ConfigError
+}
+
+// ConfigError describes a config file that could not be parsed
+type ConfigError struct {
+	Url   string    `json:"url"`
+	Error string    `json:"error"`
+	Time  time.Time `json:"time"`
 }
 
 func New(cfg *static.Config, reader dynamic.Reader) *App {
@@ -49,23 +62,27 @@ func New(cfg *static.Config, reader dynamic.Reader) *App {
 	em.SetStore(int(cfg.Event.Store["default"].Size), events.NewTraits().WithNamespace("kafka"))
 	em.SetStore(int(cfg.Event.Store["default"].Size), events.NewTraits().WithNamespace("ldap"))
 	em.SetStore(int(cfg.Event.Store["default"].Size), events.NewTraits().WithNamespace("mail"))
+	em.SetStore(int(cfg.Event.Store["default"].Size), events.NewTraits().WithNamespace("websocket"))
 	em.SetStore(int(cfg.Event.Store["default"].Size), events.NewTraits().WithNamespace("job"))
 	em.SetStore(int(cfg.Event.Store["default"].Size), events.NewTraits().WithNamespace("logs"))
+	configureEventStorage(em, cfg.Event.Storage)
 
 	app := &App{
-		Version:     version.BuildVersion,
-		BuildTime:   version.BuildTime,
-		Monitor:     m,
-		Events:      em,
-		Configs:     map[string]*dynamic.Config{},
-		Http:        &HttpStore{cfg: cfg, index: index, events: em, reader: reader},
-		Kafka:       &KafkaStore{monitor: m, cfg: cfg, index: index, events: em, reader: reader},
-		Mqtt:        &MqttStore{monitor: m, cfg: cfg, index: index, sm: em, events: em, reader: reader},
-		Ldap:        &LdapStore{cfg: cfg, events: em, index: index},
-		Mail:        &MailStore{cfg: cfg, sm: em, index: index},
-		cfg:         cfg,
-		searchIndex: index,
-		reader:      reader,
+		Version:      version.BuildVersion,
+		BuildTime:    version.BuildTime,
+		Monitor:      m,
+		Events:       em,
+		Configs:      map[string]*dynamic.Config{},
+		configErrors: map[string]ConfigError{},
+		Http:         &HttpStore{cfg: cfg, index: index, events: em, reader: reader},
+		Kafka:        &KafkaStore{monitor: m, cfg: cfg, index: index, events: em, reader: reader},
+		Mqtt:         &MqttStore{monitor: m, cfg: cfg, index: index, sm: em, events: em, reader: reader},
+		Ldap:         &LdapStore{cfg: cfg, events: em, index: index},
+		Mail:         &MailStore{cfg: cfg, sm: em, index: index},
+		WebSocket:    &WebSocketStore{cfg: cfg, events: em, reader: reader},
+		cfg:          cfg,
+		searchIndex:  index,
+		reader:       reader,
 	}
 
 	return app
@@ -73,12 +90,45 @@ func New(cfg *static.Config, reader dynamic.Reader) *App {
 
 func (a *App) Start(p *safe.Pool) {
 	go a.searchIndex.start(p)
+	if err := a.Events.Restore(); err != nil {
+		log.Errorf("failed to restore events: %v", err)
+	}
 }
 
 func (a *App) Stop() {
 	if a.hook != nil {
 		a.hook.Disable()
 	}
+	if err := a.Events.Close(); err != nil {
+		log.Errorf("failed to close event storage: %v", err)
+	}
+}
+
+func configureEventStorage(em *events.StoreManager, cfg static.EventStorage) {
+	switch strings.ToLower(cfg.Type) {
+	case "", "memory":
+		return
+	case "file":
+	default:
+		log.Errorf("unsupported event storage type '%v': events are kept in memory only", cfg.Type)
+		return
+	}
+
+	var maxAge time.Duration
+	if cfg.MaxAge != "" {
+		var err error
+		maxAge, err = time.ParseDuration(cfg.MaxAge)
+		if err != nil {
+			log.Errorf("invalid event storage max age '%v': %v", cfg.MaxAge, err)
+		}
+	}
+
+	s, err := events.NewFileStorage(cfg.Path, maxAge, cfg.MaxSize)
+	if err != nil {
+		log.Errorf("failed to open event storage: %v; events are kept in memory only", err)
+		return
+	}
+	em.SetStorage(s)
 }
 
 func (a *App) EnableLogHook() {
@@ -89,6 +139,7 @@ func (a *App) EnableLogHook() {
 func (a *App) UpdateConfig(e dynamic.ConfigEvent) {
 	a.m.Lock()
 
+	delete(a.configErrors, e.Config.Info.Key())
 	if e.Event == dynamic.Delete {
 		delete(a.Configs, e.Config.Info.Key())
 	} else {
@@ -105,6 +156,33 @@ func (a *App) UpdateConfig(e dynamic.ConfigEvent) {
 	}
 }
 
+// SetConfigError records that the given config could not be parsed.
+// The error is removed with the next successful update of the config.
+func (a *App) SetConfigError(c *dynamic.Config, err error) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	a.configErrors[c.Info.Key()] = ConfigError{
+		Url:   filepath.ToSlash(c.Info.Path()),
+		Error: err.Error(),
+		Time:  time.Now(),
+	}
+}
+
+func (a *App) ConfigErrors() []ConfigError {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	result := make([]ConfigError, 0, len(a.configErrors))
+	for _, e := range a.configErrors {
+		result = append(result, e)
+	}
+	slices.SortFunc(result, func(a, b ConfigError) int {
+		return strings.Compare(a.Url, b.Url)
+	})
+	return result
+}
+
 func (a *App) FindConfig(key string) *dynamic.Config {
 	c, ok := a.Configs[key]
 	if ok {
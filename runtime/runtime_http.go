@@ -4,6 +4,7 @@ import (
 	"mokapi/config/dynamic"
 	"mokapi/config/static"
 	"mokapi/engine/common"
+	"mokapi/oauth2"
 	"mokapi/providers/openapi"
 	"mokapi/runtime/events"
 	"mokapi/runtime/monitor"
@@ -27,9 +28,14 @@ type HttpStore struct {
 
 type HttpInfo struct {
 	*openapi.Config
-	configs   map[string]*dynamic.Config
-	seenPaths map[string]bool
-	m         sync.Mutex
+	configs        map[string]*dynamic.Config
+	seenPaths      map[string]bool
+	strictSecurity bool
+	// codes are the authorization codes issued by the built-in OAuth2
+	// server. They outlive handlers, which are created per server URL
+	// and on every config change.
+	codes *oauth2.CodeStore
+	m     sync.Mutex
 }
 
 type httpHandler struct {
@@ -70,9 +76,11 @@ func (s *HttpStore) Add(c *dynamic.Config) *HttpInfo {
 	hc, ok := s.infos[name]
 	if !ok {
 		hc = &HttpInfo{
-			Config:    cfg,
-			configs:   map[string]*dynamic.Config{},
-			seenPaths: map[string]bool{},
+			Config:         cfg,
+			configs:        map[string]*dynamic.Config{},
+			seenPaths:      map[string]bool{},
+			strictSecurity: s.cfg.Http.StrictSecurity,
+			codes:          oauth2.NewCodeStore(),
 		}
 		s.infos[cfg.Info.Name] = hc
 	}
@@ -140,9 +148,10 @@ func (s *HttpStore) Remove(c *dynamic.Config) {
 	}
 }
 
-func (c *HttpInfo) Handler(http *monitor.Http, emitter common.EventEmitter, eh events.Handler) openapi.Handler {
+func (c *HttpInfo) Handler(http *monitor.Http, emitter common.EventEmitter, eh events.Handler, opts ...openapi.HandlerOptions) openapi.Handler {
 	cfg := c.Config
-	h := openapi.NewHandler(cfg, emitter, eh)
+	opts = append([]openapi.HandlerOptions{openapi.WithStrictSecurity(c.strictSecurity), openapi.WithCodeStore(c.codes)}, opts...)
+	h := openapi.NewHandler(cfg, emitter, eh, opts...)
 	return &httpHandler{http: http, next: h}
 }
 
@@ -13,9 +13,9 @@ import (
 	"mokapi/runtime/monitor"
 	"mokapi/runtime/search"
 	"mokapi/sortedmap"
+	"net/url"
 	"path/filepath"
 	"sort"
-	"strings"
 	"sync"
 
 	log "github.com/sirupsen/logrus"
@@ -92,7 +92,14 @@ func (s *KafkaStore) Add(c *dynamic.Config, emitter common.EventEmitter) (*Kafka
 	ki, ok := s.infos[name]
 	if !ok {
 		log.Debugf("starting Kafka Store with topics: %v", len(cfg.Channels))
-		ki = newKafkaInfo(store.NewEmpty(emitter, s.events, s.monitor.Kafka), s.updateEventStore)
+		st := store.NewEmpty(emitter, s.events, s.monitor.Kafka)
+		if s.cfg.Kafka.DataDir != "" {
+			dir := filepath.Join(s.cfg.Kafka.DataDir, url.PathEscape(name))
+			if err := st.SetDataDir(dir); err != nil {
+				log.Errorf("kafka: %v; data of cluster '%v' is kept in memory only", err, name)
+			}
+		}
+		ki = newKafkaInfo(st, s.updateEventStore)
 		log.Debugf("end Kafka Store with topics: %v", len(cfg.Channels))
 		ki.Config = cfg
 		s.infos[cfg.Info.Name] = ki
@@ -164,6 +171,9 @@ func (c *KafkaInfo) addConfig(config *dynamic.Config, reader dynamic.Reader) {
 
 func (c *KafkaInfo) update(reader dynamic.Reader) {
 	if len(c.configs) == 0 {
+		if c.Store != nil {
+			c.Store.Close()
+		}
 		c.Config = nil
 		c.Store = nil
 		return
@@ -295,7 +305,7 @@ func HasKafkaBroker(c *dynamic.Config) (*asyncapi3.Config, bool) {
 		if s.Value == nil {
 			continue
 		}
-		if strings.ToLower(s.Value.Protocol) == "kafka" {
+		if s.Value.IsKafka() {
 			return cfg, true
 		}
 	}
@@ -12,6 +12,7 @@ import (
 	"mokapi/runtime/search"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -33,6 +34,7 @@ type LdapInfo struct {
 }
 
 type ldapHandler struct {
+	name string
 	ldap *monitor.Ldap
 	next ldap.Handler
 }
@@ -170,7 +172,7 @@ func (c *LdapInfo) update() {
 }
 
 func (c *LdapInfo) Handler(ldap *monitor.Ldap, eh events.Handler) ldap.Handler {
-	return &ldapHandler{ldap: ldap, next: directory.NewHandler(c.Config, c.eventEmitter, eh)}
+	return &ldapHandler{name: c.Info.Name, ldap: ldap, next: directory.NewHandler(c.Config, c.eventEmitter, eh)}
 }
 
 func (c *LdapInfo) Configs() []*dynamic.Config {
@@ -183,9 +185,38 @@ func (c *LdapInfo) Configs() []*dynamic.Config {
 
 func (h *ldapHandler) ServeLDAP(rw ldap.ResponseWriter, r *ldap.Request) {
 	r.Context = monitor.NewLdapContext(r.Context, h.ldap)
-	r.Context = context.WithValue(r.Context, "time", time.Now())
+	start := time.Now()
+	r.Context = context.WithValue(r.Context, "time", start)
 
 	h.next.ServeLDAP(rw, r)
+
+	h.ldap.RequestDuration.WithLabel(h.name, ldapOperation(r.Message)).Observe(time.Since(start).Seconds())
+}
+
+func ldapOperation(msg ldap.Message) string {
+	switch m := msg.(type) {
+	case *ldap.BindRequest:
+		return "bind"
+	case *ldap.SearchRequest:
+		return "search"
+	case *ldap.ModifyRequest:
+		return "modify"
+	case *ldap.AddRequest:
+		return "add"
+	case *ldap.DeleteRequest:
+		return "delete"
+	case *ldap.ModifyDNRequest:
+		return "modifyDN"
+	case *ldap.CompareRequest:
+		return "compare"
+	case *ldap.ExtendedRequest:
+		if name, ok := ldap.ExtendedOperationText[m.Name]; ok {
+			return strings.ToLower(name)
+		}
+		return "extended"
+	default:
+		return "unknown"
+	}
 }
 
 func (h *ldapHandler) Unbind(ctx context.Context) {
@@ -195,6 +226,13 @@ func (h *ldapHandler) Unbind(ctx context.Context) {
 	h.next.Unbind(ctx)
 }
 
+func (h *ldapHandler) StartTLS(ctx context.Context, res *ldap.ExtendedResponse) {
+	ctx = monitor.NewLdapContext(ctx, h.ldap)
+	ctx = context.WithValue(ctx, "time", time.Now())
+
+	h.next.StartTLS(ctx, res)
+}
+
 func IsLdapConfig(c *dynamic.Config) (*directory.Config, bool) {
 	li, ok := c.Data.(*directory.Config)
 	return li, ok
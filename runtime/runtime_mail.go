@@ -6,6 +6,7 @@ import (
 	"mokapi/config/static"
 	engine "mokapi/engine/common"
 	"mokapi/imap"
+	"mokapi/pop3"
 	"mokapi/providers/mail"
 	"mokapi/runtime/events"
 	"mokapi/runtime/monitor"
@@ -22,6 +23,7 @@ import (
 type MailHandler interface {
 	smtp.Handler
 	imap.Handler
+	pop3.Handler
 }
 
 type MailStore struct {
@@ -279,6 +281,22 @@ func (h *mailHandler) Idle(w imap.UpdateWriter, done chan struct{}, ctx context.
 	return h.next.Idle(w, done, ctx)
 }
 
+func (h *mailHandler) Authorize(username string, verify func(password string) bool, ctx context.Context) error {
+	return h.next.Authorize(username, verify, ctx)
+}
+
+func (h *mailHandler) Maildrop(ctx context.Context) ([]pop3.Message, error) {
+	return h.next.Maildrop(ctx)
+}
+
+func (h *mailHandler) UpdateMaildrop(deleted []string, ctx context.Context) error {
+	return h.next.UpdateMaildrop(deleted, ctx)
+}
+
+func (h *mailHandler) CloseSession(ctx context.Context) {
+	h.next.CloseSession(ctx)
+}
+
 func getSmtpConfig(c *dynamic.Config) *mail.Config {
 	return c.Data.(*mail.Config)
 }
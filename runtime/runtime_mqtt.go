@@ -14,7 +14,6 @@ import (
 	"mokapi/sortedmap"
 	"path/filepath"
 	"sort"
-	"strings"
 	"sync"
 
 	log "github.com/sirupsen/logrus"
@@ -245,7 +244,7 @@ func HasMqttServer(c *dynamic.Config) (*asyncapi3.Config, bool) {
 		if s.Value == nil {
 			continue
 		}
-		if strings.ToLower(s.Value.Protocol) == "mqtt" {
+		if s.Value.IsMqtt() {
 			return cfg, true
 		}
 	}
@@ -0,0 +1,235 @@
+package runtime
+
+import (
+	"fmt"
+	"mokapi/config/dynamic"
+	"mokapi/config/static"
+	"mokapi/engine/common"
+	"mokapi/lib"
+	"mokapi/providers/asyncapi3"
+	"mokapi/providers/asyncapi3/websocket/store"
+	"mokapi/providers/openapi"
+	"mokapi/runtime/events"
+	"mokapi/websocket"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type WebSocketStore struct {
+	infos  map[string]*WebSocketInfo
+	cfg    *static.Config
+	events *events.StoreManager
+	reader dynamic.Reader
+	m      sync.RWMutex
+}
+
+type WebSocketInfo struct {
+	*asyncapi3.Config
+	*store.Store
+	configs map[string]*dynamic.Config
+}
+
+type webSocketHandler struct {
+	store *store.Store
+}
+
+func (s *WebSocketStore) Get(name string) *WebSocketInfo {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	return s.infos[name]
+}
+
+func (s *WebSocketStore) List() []*WebSocketInfo {
+	if s == nil {
+		return nil
+	}
+
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	var list []*WebSocketInfo
+	for _, v := range s.infos {
+		list = append(list, v)
+	}
+	return list
+}
+
+func (s *WebSocketStore) Add(c *dynamic.Config, emitter common.EventEmitter) (*WebSocketInfo, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if len(s.infos) == 0 {
+		s.infos = make(map[string]*WebSocketInfo)
+	}
+	cfg, err := getWebSocketConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	name := cfg.Info.Name
+	wi, ok := s.infos[name]
+
+	eventStore, hasStoreConfig := s.cfg.Event.Store[name]
+	if !hasStoreConfig {
+		eventStore = s.cfg.Event.Store["default"]
+	}
+
+	if !ok {
+		traits := events.NewTraits().WithNamespace("websocket").WithName(name)
+		s.events.ResetStores(traits)
+		s.events.SetStore(int(eventStore.Size), traits)
+
+		wi = &WebSocketInfo{
+			configs: map[string]*dynamic.Config{},
+			Store:   store.New(cfg, emitter, s.events),
+		}
+		s.infos[name] = wi
+	}
+	wi.configs[c.Info.Url.String()] = c
+	wi.update(s.reader)
+
+	return wi, nil
+}
+
+func (s *WebSocketStore) Remove(c *dynamic.Config) {
+	cfg, err := getWebSocketConfig(c)
+	if err != nil {
+		return
+	}
+	name := cfg.Info.Name
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	wi, ok := s.infos[name]
+	if !ok {
+		return
+	}
+	delete(wi.configs, c.Info.Url.String())
+	if len(wi.configs) == 0 {
+		wi.Store.Close()
+		delete(s.infos, name)
+		s.events.ResetStores(events.NewTraits().WithNamespace("websocket").WithName(name))
+		return
+	}
+	wi.update(s.reader)
+}
+
+// Send sends the data to clients connected to the channel. If api is
+// empty, the channel is searched in all AsyncAPI specifications.
+func (s *WebSocketStore) Send(args *common.WebSocketSendArgs) (int, error) {
+	var infos []*WebSocketInfo
+	if args.Api != "" {
+		wi := s.Get(args.Api)
+		if wi == nil {
+			return 0, fmt.Errorf("websocket API '%v' not found", args.Api)
+		}
+		infos = append(infos, wi)
+	} else {
+		infos = s.List()
+	}
+
+	mt := websocket.TextMessage
+	if args.Binary {
+		mt = websocket.BinaryMessage
+	}
+
+	n := 0
+	for _, wi := range infos {
+		n += wi.Store.Broadcast(args.Channel, args.Data, mt, args.SessionId)
+	}
+	return n, nil
+}
+
+func (c *WebSocketInfo) update(reader dynamic.Reader) {
+	var keys []string
+	for k := range c.configs {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return filepath.Base(keys[i]) < filepath.Base(keys[j])
+	})
+
+	cfg := &asyncapi3.Config{}
+	for i, k := range keys {
+		p, err := getWebSocketConfig(c.configs[k])
+		if err != nil {
+			log.Errorf("patch %v failed: %v", c.configs[k].Info.Url, err)
+			continue
+		}
+		if i == 0 {
+			*cfg = *p
+		} else {
+			log.Infof("applying patch for %s: %s", cfg.Info.Name, k)
+			cfg.Patch(p)
+		}
+	}
+
+	if len(c.configs) > 1 {
+		err := cfg.Parse(&dynamic.Config{Data: cfg}, reader)
+		if err != nil {
+			log.Errorf("failed to parse config: %s", err)
+		}
+	}
+
+	c.Config = cfg
+	c.Store.Update(cfg)
+}
+
+// Handler returns an HTTP handler that serves the WebSocket channels
+func (c *WebSocketInfo) Handler() openapi.Handler {
+	return &webSocketHandler{store: c.Store}
+}
+
+func (h *webSocketHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) *openapi.HttpError {
+	ch, ok := h.store.Match(r.URL.Path)
+	if !ok {
+		return &openapi.HttpError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("no WebSocket channel found for %v", lib.GetUrl(r)),
+			Traits:     events.NewTraits().WithNamespace("http"),
+		}
+	}
+	h.store.ServeHTTP(rw, r, ch)
+	return nil
+}
+
+func HasWebSocketServer(c *dynamic.Config) (*asyncapi3.Config, bool) {
+	cfg, ok := IsAsyncApiConfig(c)
+	if !ok {
+		return nil, false
+	}
+	for it := cfg.Servers.Iter(); it.Next(); {
+		s := it.Value()
+		if s.Value == nil {
+			continue
+		}
+		if s.Value.IsWebSocket() {
+			return cfg, true
+		}
+	}
+	return cfg, false
+}
+
+func getWebSocketConfig(c *dynamic.Config) (*asyncapi3.Config, error) {
+	cfg, ok := IsAsyncApiConfig(c)
+	if !ok {
+		return nil, fmt.Errorf("unexpected config type %T", c.Data)
+	}
+	return cfg, nil
+}
+
+func (s *WebSocketStore) Len() int {
+	if s == nil {
+		return 0
+	}
+
+	s.m.RLock()
+	defer s.m.RUnlock()
+	return len(s.infos)
+}
@@ -0,0 +1,253 @@
+package sasl
+
+import (
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	Plain       = "PLAIN"
+	ScramSha256 = "SCRAM-SHA-256"
+	ScramSha512 = "SCRAM-SHA-512"
+)
+
+const scramIterations = 4096
+
+// ScramCredentials returns the password of the given user or an error
+// if the user is unknown.
+type ScramCredentials func(username string) (password string, err error)
+
+type scramServer struct {
+	hash        func() hash.Hash
+	credentials ScramCredentials
+	step        int
+	hasNext     bool
+
+	clientFirstBare string
+	serverFirst     string
+	nonce           string
+	saltedPassword  []byte
+}
+
+func NewScramSha256Server(credentials ScramCredentials) Server {
+	return &scramServer{hash: sha256.New, credentials: credentials, hasNext: true}
+}
+
+func NewScramSha512Server(credentials ScramCredentials) Server {
+	return &scramServer{hash: sha512.New, credentials: credentials, hasNext: true}
+}
+
+func (s *scramServer) Next(response []byte) (challenge []byte, err error) {
+	switch s.step {
+	case 0:
+		challenge, err = s.handleClientFirst(string(response))
+	case 1:
+		challenge, err = s.handleClientFinal(string(response))
+	default:
+		err = errors.New("unexpected SCRAM message")
+	}
+	if err != nil {
+		s.hasNext = false
+		return nil, err
+	}
+	s.step++
+	return
+}
+
+func (s *scramServer) HasNext() bool {
+	return s.hasNext
+}
+
+func (s *scramServer) handleClientFirst(msg string) ([]byte, error) {
+	// gs2-header: channel binding flag and optional authzid
+	parts := strings.SplitN(msg, ",", 3)
+	if len(parts) != 3 {
+		return nil, errors.New("invalid SCRAM client-first-message")
+	}
+	switch {
+	case parts[0] == "n" || parts[0] == "y":
+	case strings.HasPrefix(parts[0], "p="):
+		return nil, errors.New("SCRAM channel binding is not supported")
+	default:
+		return nil, errors.New("invalid SCRAM gs2 header")
+	}
+
+	s.clientFirstBare = parts[2]
+	attrs := parseScramAttributes(s.clientFirstBare)
+	username, ok := attrs["n"]
+	if !ok {
+		return nil, errors.New("SCRAM username is missing")
+	}
+	username = unescapeScramName(username)
+	clientNonce, ok := attrs["r"]
+	if !ok || clientNonce == "" {
+		return nil, errors.New("SCRAM client nonce is missing")
+	}
+
+	password, err := s.credentials(username)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	_, _ = rand.Read(salt)
+	s.saltedPassword, err = pbkdf2.Key(s.hash, password, salt, scramIterations, s.hash().Size())
+	if err != nil {
+		return nil, err
+	}
+
+	s.nonce = clientNonce + newScramNonce()
+	s.serverFirst = fmt.Sprintf("r=%s,s=%s,i=%d", s.nonce, base64.StdEncoding.EncodeToString(salt), scramIterations)
+	return []byte(s.serverFirst), nil
+}
+
+func (s *scramServer) handleClientFinal(msg string) ([]byte, error) {
+	i := strings.LastIndex(msg, ",p=")
+	if i < 0 {
+		return nil, errors.New("SCRAM client proof is missing")
+	}
+	withoutProof := msg[:i]
+	attrs := parseScramAttributes(msg)
+	if attrs["r"] != s.nonce {
+		return nil, errors.New("SCRAM nonce mismatch")
+	}
+	proof, err := base64.StdEncoding.DecodeString(attrs["p"])
+	if err != nil {
+		return nil, errors.New("invalid SCRAM client proof")
+	}
+
+	authMessage := s.clientFirstBare + "," + s.serverFirst + "," + withoutProof
+	clientKey := scramHmac(s.hash, s.saltedPassword, "Client Key")
+	storedKey := scramHash(s.hash, clientKey)
+	clientSignature := scramHmac(s.hash, storedKey, authMessage)
+	if len(proof) != len(clientSignature) {
+		return nil, errors.New("invalid SCRAM client proof")
+	}
+	for i := range proof {
+		proof[i] ^= clientSignature[i]
+	}
+	if subtle.ConstantTimeCompare(scramHash(s.hash, proof), storedKey) != 1 {
+		return nil, errors.New("invalid username or password")
+	}
+
+	s.hasNext = false
+	serverKey := scramHmac(s.hash, s.saltedPassword, "Server Key")
+	serverSignature := scramHmac(s.hash, serverKey, authMessage)
+	return []byte("v=" + base64.StdEncoding.EncodeToString(serverSignature)), nil
+}
+
+type scramClient struct {
+	hash     func() hash.Hash
+	username string
+	password string
+	step     int
+
+	clientFirstBare string
+	serverSignature []byte
+}
+
+func NewScramSha256Client(username, password string) Client {
+	return &scramClient{hash: sha256.New, username: username, password: password}
+}
+
+func NewScramSha512Client(username, password string) Client {
+	return &scramClient{hash: sha512.New, username: username, password: password}
+}
+
+func (c *scramClient) Next(challenge []byte) (response []byte, err error) {
+	defer func() { c.step++ }()
+
+	switch c.step {
+	case 0:
+		c.clientFirstBare = fmt.Sprintf("n=%s,r=%s", escapeScramName(c.username), newScramNonce())
+		return []byte("n,," + c.clientFirstBare), nil
+	case 1:
+		attrs := parseScramAttributes(string(challenge))
+		salt, err := base64.StdEncoding.DecodeString(attrs["s"])
+		if err != nil {
+			return nil, errors.New("invalid SCRAM salt")
+		}
+		iterations, err := strconv.Atoi(attrs["i"])
+		if err != nil {
+			return nil, errors.New("invalid SCRAM iteration count")
+		}
+		saltedPassword, err := pbkdf2.Key(c.hash, c.password, salt, iterations, c.hash().Size())
+		if err != nil {
+			return nil, err
+		}
+
+		withoutProof := fmt.Sprintf("c=%s,r=%s", base64.StdEncoding.EncodeToString([]byte("n,,")), attrs["r"])
+		authMessage := c.clientFirstBare + "," + string(challenge) + "," + withoutProof
+		clientKey := scramHmac(c.hash, saltedPassword, "Client Key")
+		clientSignature := scramHmac(c.hash, scramHash(c.hash, clientKey), authMessage)
+		for i := range clientKey {
+			clientKey[i] ^= clientSignature[i]
+		}
+		c.serverSignature = scramHmac(c.hash, scramHmac(c.hash, saltedPassword, "Server Key"), authMessage)
+		return []byte(withoutProof + ",p=" + base64.StdEncoding.EncodeToString(clientKey)), nil
+	case 2:
+		attrs := parseScramAttributes(string(challenge))
+		if e, ok := attrs["e"]; ok {
+			return nil, errors.New(e)
+		}
+		v, err := base64.StdEncoding.DecodeString(attrs["v"])
+		if err != nil || !hmac.Equal(v, c.serverSignature) {
+			return nil, errors.New("invalid SCRAM server signature")
+		}
+		return nil, nil
+	default:
+		return nil, errors.New("unexpected SCRAM message")
+	}
+}
+
+func (c *scramClient) HasNext() bool {
+	return c.step < 3
+}
+
+func parseScramAttributes(s string) map[string]string {
+	attrs := map[string]string{}
+	for _, kv := range strings.Split(s, ",") {
+		if len(kv) < 2 || kv[1] != '=' {
+			continue
+		}
+		attrs[kv[:1]] = kv[2:]
+	}
+	return attrs
+}
+
+func escapeScramName(s string) string {
+	return strings.NewReplacer("=", "=3D", ",", "=2C").Replace(s)
+}
+
+func unescapeScramName(s string) string {
+	return strings.NewReplacer("=2C", ",", "=3D", "=").Replace(s)
+}
+
+func newScramNonce() string {
+	b := make([]byte, 18)
+	_, _ = rand.Read(b)
+	return base64.RawStdEncoding.EncodeToString(b)
+}
+
+func scramHmac(h func() hash.Hash, key []byte, s string) []byte {
+	m := hmac.New(h, key)
+	m.Write([]byte(s))
+	return m.Sum(nil)
+}
+
+func scramHash(h func() hash.Hash, b []byte) []byte {
+	m := h()
+	m.Write(b)
+	return m.Sum(nil)
+}
@@ -0,0 +1,95 @@
+package sasl_test
+
+import (
+	"fmt"
+	"mokapi/sasl"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScram(t *testing.T) {
+	credentials := func(username string) (string, error) {
+		if username == "alice" {
+			return "secret", nil
+		}
+		return "", fmt.Errorf("unknown user %v", username)
+	}
+
+	testcases := []struct {
+		name   string
+		client sasl.Client
+		server sasl.Server
+		test   func(t *testing.T, c sasl.Client, s sasl.Server)
+	}{
+		{
+			name:   "SCRAM-SHA-256",
+			client: sasl.NewScramSha256Client("alice", "secret"),
+			server: sasl.NewScramSha256Server(credentials),
+			test: func(t *testing.T, c sasl.Client, s sasl.Server) {
+				require.NoError(t, authenticate(c, s))
+				require.False(t, s.HasNext())
+				require.False(t, c.HasNext())
+			},
+		},
+		{
+			name:   "SCRAM-SHA-512",
+			client: sasl.NewScramSha512Client("alice", "secret"),
+			server: sasl.NewScramSha512Server(credentials),
+			test: func(t *testing.T, c sasl.Client, s sasl.Server) {
+				require.NoError(t, authenticate(c, s))
+				require.False(t, s.HasNext())
+			},
+		},
+		{
+			name:   "wrong password",
+			client: sasl.NewScramSha256Client("alice", "foo"),
+			server: sasl.NewScramSha256Server(credentials),
+			test: func(t *testing.T, c sasl.Client, s sasl.Server) {
+				require.EqualError(t, authenticate(c, s), "invalid username or password")
+			},
+		},
+		{
+			name:   "unknown user",
+			client: sasl.NewScramSha256Client("bob", "secret"),
+			server: sasl.NewScramSha256Server(credentials),
+			test: func(t *testing.T, c sasl.Client, s sasl.Server) {
+				require.EqualError(t, authenticate(c, s), "unknown user bob")
+			},
+		},
+		{
+			name:   "mechanism mismatch",
+			client: sasl.NewScramSha512Client("alice", "secret"),
+			server: sasl.NewScramSha256Server(credentials),
+			test: func(t *testing.T, c sasl.Client, s sasl.Server) {
+				require.EqualError(t, authenticate(c, s), "invalid SCRAM client proof")
+			},
+		},
+	}
+
+	t.Parallel()
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.test(t, tc.client, tc.server)
+		})
+	}
+}
+
+func authenticate(c sasl.Client, s sasl.Server) error {
+	var challenge []byte
+	for c.HasNext() {
+		response, err := c.Next(challenge)
+		if err != nil {
+			return err
+		}
+		if !s.HasNext() {
+			return nil
+		}
+		challenge, err = s.Next(response)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
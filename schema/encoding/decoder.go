@@ -29,6 +29,7 @@ func init() {
 	RegisterDecoder(&BinaryDecoder{})
 	RegisterDecoder(&TextDecoder{})
 	RegisterDecoder(&AvroDecoder{})
+	RegisterDecoder(&ProtobufDecoder{})
 }
 
 func RegisterDecoder(d Decoder) {
@@ -0,0 +1,26 @@
+package encoding
+
+import (
+	"mokapi/media"
+)
+
+type ProtobufDecoder struct {
+}
+
+func (d *ProtobufDecoder) IsSupporting(contentType media.ContentType) bool {
+	return IsProtobuf(contentType)
+}
+
+func (d *ProtobufDecoder) Decode(b []byte, state *DecodeState) (i interface{}, err error) {
+	return state.parser.Parse(b)
+}
+
+// IsProtobuf reports whether the content type describes protobuf binary data
+func IsProtobuf(contentType media.ContentType) bool {
+	switch contentType.Key() {
+	case "application/x-protobuf", "application/protobuf", "application/vnd.google.protobuf":
+		return true
+	default:
+		return false
+	}
+}
@@ -5,33 +5,39 @@ import (
 	"mokapi/schema/json/schema"
 	"strconv"
 	"strings"
-
-	"github.com/brianvoe/gofakeit/v6"
 )
 
 func addresses() []*Node {
 	return []*Node{
 		{
-			Name: "address",
-			Fake: fakeAddress,
+			Name:    "address",
+			Fake:    fakeAddress,
+			Locales: supportedLocales,
 			Children: append([]*Node{
 				{
-					Name: "co",
-					Fake: fakePersonName,
+					Name:    "co",
+					Fake:    fakePersonName,
+					Locales: supportedLocales,
 				},
 				{
-					Name: "line1",
-					Fake: fakePersonName,
+					Name:    "line1",
+					Fake:    fakePersonName,
+					Locales: supportedLocales,
 				},
 				{
-					Name: "line2",
-					Fake: fakeStreet,
+					Name:    "line2",
+					Fake:    fakeStreet,
+					Locales: supportedLocales,
 				},
 				{
 					Name: "line3",
 					Fake: func(r *Request) (interface{}, error) {
-						return fmt.Sprintf("%v %v %v", gofakeit.City(), gofakeit.StateAbr(), gofakeit.Zip()), nil
+						if l := r.locale; l != nil {
+							return l.cityLine(r, l.CityFormat, ""), nil
+						}
+						return fmt.Sprintf("%v %v %v", r.g.fake.City(), r.g.fake.StateAbr(), r.g.fake.Zip()), nil
 					},
+					Locales: supportedLocales,
 				},
 				{
 					Name: "floor",
@@ -56,28 +62,33 @@ func addresses() []*Node {
 			Name: "co",
 			Children: []*Node{
 				{
-					Name: "address",
-					Fake: fakePersonName,
+					Name:    "address",
+					Fake:    fakePersonName,
+					Locales: supportedLocales,
 				},
 			},
 		},
 		{
-			Name: "street",
-			Fake: fakeStreet,
+			Name:    "street",
+			Fake:    fakeStreet,
+			Locales: supportedLocales,
 		},
 		{
 			Name:       "city",
 			Attributes: []string{"city", "locality"},
 			Fake:       fakeCity,
+			Locales:    supportedLocales,
 		},
 		{
 			Name:       "zip",
 			Attributes: []string{"zip", "postcode", "postal"},
 			Fake:       fakePostcode,
+			Locales:    supportedLocales,
 			Children: []*Node{
 				{
-					Name: "code",
-					Fake: fakePostcode,
+					Name:    "code",
+					Fake:    fakePostcode,
+					Locales: supportedLocales,
 				},
 			},
 		},
@@ -85,10 +96,12 @@ func addresses() []*Node {
 			Name:       "house",
 			Attributes: []string{"house", "building"},
 			Fake:       fakeHouseNumber,
+			Locales:    supportedLocales,
 			Children: []*Node{
 				{
-					Name: "number",
-					Fake: fakeHouseNumber,
+					Name:    "number",
+					Fake:    fakeHouseNumber,
+					Locales: supportedLocales,
 				},
 			},
 		},
@@ -96,7 +109,12 @@ func addresses() []*Node {
 }
 
 func fakeStreet(r *Request) (any, error) {
-	v := gofakeit.Street()
+	var v string
+	if l := r.locale; l != nil {
+		v = l.street(r)
+	} else {
+		v = r.g.fake.Street()
+	}
 	r.Context.Values["street"] = v
 	return v, nil
 }
@@ -106,9 +124,12 @@ func fakeCity(r *Request) (any, error) {
 	var err error
 	s := r.Schema
 	if s.IsAny() || s.IsString() {
-		v = gofakeit.City()
+		if l := r.locale; l != nil {
+			return l.city(r).Name, nil
+		}
+		v = r.g.fake.City()
 	} else if s.IsInteger() {
-		v, err = newPostCode(s)
+		v, err = newPostCode(r, s)
 	} else {
 		return nil, NotSupported
 	}
@@ -120,16 +141,22 @@ func fakeCity(r *Request) (any, error) {
 }
 
 func fakePostcode(r *Request) (any, error) {
-	return newPostCode(r.Schema)
+	return newPostCode(r, r.Schema)
 }
 
-func newPostCode(s *schema.Schema) (any, error) {
+func newPostCode(r *Request, s *schema.Schema) (any, error) {
 	if s == nil || s.IsAny() {
 		s = &schema.Schema{Type: []string{"string"}}
 	}
 	if s.Pattern != "" {
 		return nil, NotSupported
 	}
+	if l := r.locale; l != nil && s.IsString() {
+		code := fillPattern(r, l.city(r).Postcode)
+		if (s.MinLength == nil || len(code) >= *s.MinLength) && (s.MaxLength == nil || len(code) <= *s.MaxLength) {
+			return code, nil
+		}
+	}
 	minLength := 4
 	maxLength := 6
 	if s.IsInteger() {
@@ -152,10 +179,10 @@ func newPostCode(s *schema.Schema) (any, error) {
 	if minLength == maxLength {
 		n = minLength
 	} else {
-		n = gofakeit.Number(minLength, maxLength)
+		n = r.g.fake.Number(minLength, maxLength)
 	}
 
-	code := gofakeit.Numerify(strings.Repeat("#", n))
+	code := r.g.fake.Numerify(strings.Repeat("#", n))
 	if s.IsInteger() {
 		codeN, _ := strconv.ParseInt(code, 10, 32)
 		return int(codeN), nil
@@ -166,8 +193,26 @@ func newPostCode(s *schema.Schema) (any, error) {
 	return code, nil
 }
 
-func fakeAddress(_ *Request) (interface{}, error) {
-	addr := gofakeit.Address()
+func fakeAddress(r *Request) (interface{}, error) {
+	if l := r.locale; l != nil {
+		street := l.street(r)
+		c := l.city(r)
+		zip := fillPattern(r, c.Postcode)
+		return map[string]interface{}{
+			"address": strings.NewReplacer(
+				"{street}", street, "{zip}", zip, "{city}", c.Name, "{state}", c.State,
+			).Replace(l.AddressFormat),
+			"street":    street,
+			"city":      c.Name,
+			"state":     c.State,
+			"zip":       zip,
+			"country":   l.Country,
+			"latitude":  r.g.fake.Latitude(),
+			"longitude": r.g.fake.Longitude(),
+		}, nil
+	}
+
+	addr := r.g.fake.Address()
 	return map[string]interface{}{
 		"address":   addr.Address,
 		"street":    addr.Street,
@@ -182,7 +227,7 @@ func fakeAddress(_ *Request) (interface{}, error) {
 
 func fakeHouseNumber(r *Request) (any, error) {
 	if r.Schema.IsNumber() || r.Schema.IsString() {
-		n, err := fakeIntegerWithRange(r.Schema, 1, 100)
+		n, err := fakeIntegerWithRange(r, r.Schema, 1, 100)
 		if err != nil {
 			return nil, err
 		}
@@ -191,16 +236,19 @@ func fakeHouseNumber(r *Request) (any, error) {
 		}
 		return n, nil
 	}
-	return gofakeit.StreetNumber(), nil
+	if l := r.locale; l != nil {
+		return fillPattern(r, pick(r, l.HouseNumbers)), nil
+	}
+	return r.g.fake.StreetNumber(), nil
 }
 
-func fakeFloor(_ *Request) (any, error) {
-	index := gofakeit.Number(0, len(floor)-1)
+func fakeFloor(r *Request) (any, error) {
+	index := r.g.fake.Number(0, len(floor)-1)
 	return floor[index], nil
 }
 
-func fakeRoom(_ *Request) (any, error) {
-	index := gofakeit.Number(0, len(room)-1)
+func fakeRoom(r *Request) (any, error) {
+	index := r.g.fake.Number(0, len(room)-1)
 	return room[index], nil
 }
 
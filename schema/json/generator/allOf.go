@@ -2,7 +2,6 @@ package generator
 
 import (
 	"fmt"
-	"github.com/brianvoe/gofakeit/v6"
 	"mokapi/schema/json/parser"
 	"mokapi/schema/json/schema"
 	"slices"
@@ -28,7 +27,7 @@ func (r *resolver) allOf(req *Request) (*faker, error) {
 	f := func() (any, error) {
 		var result interface{}
 		var worked []*schema.Schema
-		index := gofakeit.Number(0, len(sharedType)-1)
+		index := req.g.fake.Number(0, len(sharedType)-1)
 		selectedType := schema.Types{sharedType[index]}
 
 		for _, one := range s.AllOf {
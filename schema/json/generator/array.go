@@ -7,7 +7,6 @@ import (
 	"mokapi/schema/json/schema"
 	"reflect"
 
-	"github.com/brianvoe/gofakeit/v6"
 	"github.com/jinzhu/inflection"
 )
 
@@ -32,7 +31,7 @@ func (r *resolver) resolveArray(req *Request) (*faker, error) {
 			path = append(path, getPathFromRef(s.Items.Ref))
 		}
 		if len(s.Items.Enum) > 0 && (s.UniqueItems != nil && *s.UniqueItems) {
-			index := gofakeit.Number(0, len(s.Items.Enum)-1)
+			index := req.g.fake.Number(0, len(s.Items.Enum)-1)
 			item = newFaker(func() (any, error) {
 				index = (index + 1) % len(s.Items.Enum)
 				return s.Items.Enum[index], nil
@@ -86,7 +85,7 @@ func fakeArray(r *Request, fakeItem *faker) (interface{}, error) {
 		return nil, fmt.Errorf("invalid schema: maxItems must be greater than minItems")
 	}
 
-	length := gofakeit.Number(minItems, maxItems)
+	length := r.g.fake.Number(minItems, maxItems)
 	if s.Items != nil && s.Items.Boolean != nil && !*s.Items.Boolean {
 		// disallows extra items in the tuple.
 		length = 0
@@ -140,7 +139,7 @@ func fakeArray(r *Request, fakeItem *faker) (interface{}, error) {
 		containsFaker = newFaker(func() (any, error) {
 			return fakeBySchema(r.WithSchema(s.Contains))
 		})
-		containsNum = gofakeit.Number(minContains, maxContains) - containsNum
+		containsNum = r.g.fake.Number(minContains, maxContains) - containsNum
 		// Shuffle the array so the contains items are randomly distributed.
 		shuffleItems = true
 	}
@@ -1,7 +1,6 @@
 package generator
 
 import (
-	"github.com/brianvoe/gofakeit/v6"
 	"strings"
 )
 
@@ -14,7 +13,7 @@ func colors() []*Node {
 				{
 					Name: "name",
 					Fake: func(r *Request) (any, error) {
-						return gofakeit.Color(), nil
+						return r.g.fake.Color(), nil
 					},
 				},
 			},
@@ -25,21 +24,21 @@ func colors() []*Node {
 func fakeColor(r *Request) (any, error) {
 	s := r.Schema
 	if s == nil {
-		return gofakeit.Color(), nil
+		return r.g.fake.Color(), nil
 	}
 	if s.MaxLength != nil && *s.MaxLength == 7 {
-		return gofakeit.HexColor(), nil
+		return r.g.fake.HexColor(), nil
 	}
 
 	if len(s.Examples) > 0 {
 		str, ok := s.Examples[0].Value.(string)
 		if ok && strings.HasPrefix(str, "#") {
-			return gofakeit.HexColor(), nil
+			return r.g.fake.HexColor(), nil
 		}
 	}
 
 	if s.IsString() || s.IsAny() {
-		return gofakeit.Color(), nil
+		return r.g.fake.Color(), nil
 	}
 
 	return nil, NotSupported
@@ -1,16 +1,16 @@
 package generator
 
-import "github.com/brianvoe/gofakeit/v6"
-
 func companyNodes() []*Node {
 	return []*Node{
 		{
-			Name: "company",
-			Fake: fakeCompany,
+			Name:    "company",
+			Fake:    fakeCompany,
+			Locales: supportedLocales,
 			Children: []*Node{
 				{
-					Name: "name",
-					Fake: fakeCompany,
+					Name:    "name",
+					Fake:    fakeCompany,
+					Locales: supportedLocales,
 				},
 			},
 		},
@@ -19,12 +19,14 @@ func companyNodes() []*Node {
 			Fake: fakeIndustry,
 		},
 		{
-			Name: "organization",
-			Fake: fakeCompany,
+			Name:    "organization",
+			Fake:    fakeCompany,
+			Locales: supportedLocales,
 			Children: []*Node{
 				{
-					Name: "name",
-					Fake: fakeCompany,
+					Name:    "name",
+					Fake:    fakeCompany,
+					Locales: supportedLocales,
 				},
 			},
 		},
@@ -33,13 +35,16 @@ func companyNodes() []*Node {
 
 func fakeCompany(r *Request) (any, error) {
 	if r.Schema.IsString() || r.Schema.IsAny() {
-		return gofakeit.Company(), nil
+		if l := r.locale; l != nil {
+			return l.company(r), nil
+		}
+		return r.g.fake.Company(), nil
 	}
 	return nil, NotSupported
 }
 
-func fakeIndustry(_ *Request) (any, error) {
-	index := gofakeit.Number(0, len(industry)-1)
+func fakeIndustry(r *Request) (any, error) {
+	index := r.g.fake.Number(0, len(industry)-1)
 	return industry[index], nil
 }
 
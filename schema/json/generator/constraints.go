@@ -1,8 +1,6 @@
 package generator
 
-import (
-	"github.com/brianvoe/gofakeit/v6"
-)
+import ()
 
 func applyConstraints(r *Request) (fakeFunc, bool) {
 	if r.Schema == nil {
@@ -33,6 +31,6 @@ func pickEnumValue(r *Request) interface{} {
 		}()
 	}
 
-	v = r.Schema.Enum[gofakeit.Number(0, len(r.Schema.Enum)-1)]
+	v = r.Schema.Enum[r.g.fake.Number(0, len(r.Schema.Enum)-1)]
 	return v
 }
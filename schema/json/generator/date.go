@@ -2,8 +2,6 @@ package generator
 
 import (
 	"time"
-
-	"github.com/brianvoe/gofakeit/v6"
 )
 
 func dates() []*Node {
@@ -153,9 +151,9 @@ func fakePastDate(pastYears int) func(r *Request) (any, error) {
 }
 
 func fakeDateWithYearRange(r *Request, min time.Time, maxYear int) (any, error) {
-	year := gofakeit.IntRange(min.Year(), maxYear)
+	year := r.g.fake.IntRange(min.Year(), maxYear)
 	minMonth := int(min.Month())
-	month := gofakeit.Number(minMonth, 12)
+	month := r.g.fake.Number(minMonth, 12)
 	minDay := min.Day() + 1
 	if minDay > maxDayInMonth[month-1] {
 		minDay = 1
@@ -168,12 +166,12 @@ func fakeDateWithYearRange(r *Request, min time.Time, maxYear int) (any, error)
 			month += 1
 		}
 	}
-	day := gofakeit.Number(minDay, maxDayInMonth[month-1])
+	day := r.g.fake.Number(minDay, maxDayInMonth[month-1])
 
-	hour := gofakeit.Number(0, 23)
-	minute := gofakeit.Number(0, 59)
-	second := gofakeit.Number(0, 59)
-	nanosecond := gofakeit.Number(0, 999999999)
+	hour := r.g.fake.Number(0, 23)
+	minute := r.g.fake.Number(0, 59)
+	second := r.g.fake.Number(0, 59)
+	nanosecond := r.g.fake.Number(0, 999999999)
 
 	d := time.Date(year, time.Month(month), day, hour, minute, second, nanosecond, time.UTC)
 	if r.Schema != nil && r.Schema.Format == "date-time" {
@@ -171,7 +171,7 @@ func TestStringDate(t *testing.T) {
 }
 
 func TestFakeDateWithYearRange_NewYearsEve(t *testing.T) {
-	r, err := fakeDateWithYearRange(&Request{}, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), 2027)
+	r, err := fakeDateWithYearRange(&Request{g: g}, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), 2027)
 	require.NoError(t, err)
 	require.NotEqual(t, "", r)
 	require.True(t, strings.HasPrefix(r.(string), "2027"))
@@ -2,8 +2,12 @@ package generator
 
 import (
 	"fmt"
-	"github.com/brianvoe/gofakeit/v6"
 	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 )
 
 func newEmailNode() *Node {
@@ -33,7 +37,28 @@ func fakeEmail(r *Request) (interface{}, error) {
 		if last == nil {
 			last, _ = fakeLastname(r)
 		}
-		return strings.ToLower(fmt.Sprintf("%s.%s@%s", first, last, gofakeit.DomainName())), nil
+		localPart, ok := toAscii(fmt.Sprintf("%s.%s", first, last))
+		if ok {
+			return strings.ToLower(fmt.Sprintf("%s@%s", localPart, r.g.fake.DomainName())), nil
+		}
+	}
+	return r.g.fake.Email(), nil
+}
+
+var umlauts = strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "Ä", "Ae", "Ö", "Oe", "Ü", "Ue", "ß", "ss")
+
+// toAscii removes diacritics from localized names, e.g. Müller -> Mueller
+// and Chloé -> Chloe. It returns false for names in other scripts.
+func toAscii(s string) (string, bool) {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, umlauts.Replace(s))
+	if err != nil {
+		return "", false
+	}
+	for _, c := range result {
+		if c > unicode.MaxASCII {
+			return "", false
+		}
 	}
-	return gofakeit.Email(), nil
+	return result, true
 }
@@ -46,3 +46,20 @@ func TestStringEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestToAscii(t *testing.T) {
+	for _, tc := range []struct {
+		in  string
+		out string
+		ok  bool
+	}{
+		{in: "Müller", out: "Mueller", ok: true},
+		{in: "Chloé.Lefèvre", out: "Chloe.Lefevre", ok: true},
+		{in: "Étienne", out: "Etienne", ok: true},
+		{in: "佐藤", ok: false},
+	} {
+		out, ok := toAscii(tc.in)
+		require.Equal(t, tc.ok, ok, tc.in)
+		require.Equal(t, tc.out, out, tc.in)
+	}
+}
@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"mokapi/schema/json/schema"
 	"mokapi/sortedmap"
-
-	"github.com/brianvoe/gofakeit/v6"
 )
 
 func fakeBySchemaNode() *Node {
@@ -41,13 +39,13 @@ func fakeBySchema(r *Request) (interface{}, error) {
 	if s != nil && len(s.Type) > 1 {
 		t = s.Type
 		if s.IsNullable() {
-			n := gofakeit.Float32Range(0, 1)
+			n := r.g.fake.Float32Range(0, 1)
 			if n > 0.05 {
 				t = removeNull(s.Type)
 			}
 		}
 
-		index := gofakeit.Number(0, len(t)-1)
+		index := r.g.fake.Number(0, len(t)-1)
 		t = schema.Types{t[index]}
 		c := *s
 		c.Type = t
@@ -66,11 +64,11 @@ func fakeBySchema(r *Request) (interface{}, error) {
 		}
 		return fakeArray(r, newFaker(items))
 	case t.IsBool():
-		return gofakeit.Bool(), nil
+		return r.g.fake.Bool(), nil
 	case t.IsNumber():
 		return fakeNumber(r)
 	case t.IsInteger():
-		return fakeInteger(r.Schema)
+		return fakeInteger(r, r.Schema)
 	case t.IsNullable():
 		return nil, nil
 	case t.IsNullable():
@@ -84,7 +82,7 @@ func fakeBySchema(r *Request) (interface{}, error) {
 	// we choose a random type and set it to a copy of the current schema.
 	i := inferTypeFromKeywords(s)
 	if i == "" {
-		w, _ := gofakeit.Weighted(types, weightTypes)
+		w, _ := r.g.fake.Weighted(types, weightTypes)
 		i = w.(string)
 	}
 	var c schema.Schema
@@ -102,7 +100,7 @@ func fakeObject(r *Request) (interface{}, error) {
 	if s.Properties == nil {
 		s.Properties = &schema.Schemas{LinkedHashMap: sortedmap.LinkedHashMap[string, *schema.Schema]{}}
 		propertyNameParser := propertyNamesParser(s)
-		length := numProperties(0, 10, s)
+		length := numProperties(r, 0, 10, s)
 
 		if length == 0 {
 			return map[string]interface{}{}, nil
@@ -114,7 +112,7 @@ func fakeObject(r *Request) (interface{}, error) {
 				name = s.Required[i]
 			} else {
 				var err error
-				name, err = newPropertyName(propertyNameParser)
+				name, err = newPropertyName(r, propertyNameParser)
 				if err != nil {
 					continue
 				}
@@ -145,7 +143,7 @@ func removeNull(slice schema.Types) schema.Types {
 
 func selectExample(r *Request) (any, error) {
 	items := r.examples
-	start := gofakeit.Number(0, len(items)-1)
+	start := r.g.fake.Number(0, len(items)-1)
 	for i := 0; i < len(items); i++ {
 		index := (start + i) % len(items)
 		item := items[index]
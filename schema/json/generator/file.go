@@ -2,7 +2,6 @@ package generator
 
 import (
 	"fmt"
-	"github.com/brianvoe/gofakeit/v6"
 	"strings"
 )
 
@@ -33,13 +32,13 @@ func fakeFileName(r *Request) (any, error) {
 	if err != nil {
 		return nil, err
 	}
-	return fmt.Sprintf("%s.%s", strings.ToLower(name.(string)), gofakeit.FileExtension()), nil
+	return fmt.Sprintf("%s.%s", strings.ToLower(name.(string)), r.g.fake.FileExtension()), nil
 }
 
 func fakeFileType(r *Request) (any, error) {
-	return gofakeit.FileMimeType(), nil
+	return r.g.fake.FileMimeType(), nil
 }
 
 func fakeFileSize(r *Request) (any, error) {
-	return fakeIntegerWithRange(r.Schema, 0, 100000)
+	return fakeIntegerWithRange(r, r.Schema, 0, 100000)
 }
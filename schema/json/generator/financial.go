@@ -6,7 +6,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/brianvoe/gofakeit/v6"
 	"github.com/brianvoe/gofakeit/v6/data"
 )
 
@@ -59,6 +58,11 @@ func financials() []*Node {
 				},
 			},
 		},
+		{
+			Name:    "iban",
+			Fake:    fakeIban,
+			Locales: supportedLocales,
+		},
 	}
 }
 
@@ -75,7 +79,7 @@ func fakeCurrency(r *Request) (any, error) {
 }
 
 func fakeCurrencyCode(r *Request) (any, error) {
-	v := gofakeit.CurrencyShort()
+	v := r.g.fake.CurrencyShort()
 	r.Context.Values["currency"] = v
 	return v, nil
 }
@@ -85,7 +89,7 @@ func fakeCurrencyName(r *Request) (any, error) {
 		ensureCurrencies()
 		return currencies[code.(string)].name, nil
 	}
-	return gofakeit.Currency(), nil
+	return r.g.fake.Currency(), nil
 }
 
 func ensureCurrencies() {
@@ -108,10 +112,17 @@ func fakePriceValue(r *Request) (any, error) {
 		s = &schema.Schema{Type: []string{"number"}}
 	}
 	if s.IsInteger() {
-		return fakeIntegerWithRange(s, 0, 1000000)
+		return fakeIntegerWithRange(r, s, 0, 1000000)
 	}
 	minValue, maxValue := getRangeWithDefault(s, 0, 1000000)
-	return gofakeit.Price(minValue, maxValue), nil
+	return r.g.fake.Price(minValue, maxValue), nil
+}
+
+func fakeIban(r *Request) (any, error) {
+	if !r.Schema.IsAny() && !r.Schema.IsString() {
+		return nil, NotSupported
+	}
+	return iban(r, r.locale), nil
 }
 
 func fakeCreditCard(r *Request) (any, error) {
@@ -137,10 +148,10 @@ func fakeCreditCard(r *Request) (any, error) {
 			maxLength = len(strconv.Itoa(int(*s.Maximum)))
 		}
 	}
-	n := gofakeit.Number(minLength, maxLength) - 1
+	n := r.g.fake.Number(minLength, maxLength) - 1
 	// major industry identifier
-	mii := gofakeit.Number(1, 9)
-	result := fmt.Sprintf("%d%s", mii, gofakeit.Numerify(strings.Repeat("#", n)))
+	mii := r.g.fake.Number(1, 9)
+	result := fmt.Sprintf("%d%s", mii, r.g.fake.Numerify(strings.Repeat("#", n)))
 
 	if s.IsString() {
 		return result, nil
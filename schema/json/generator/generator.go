@@ -1,8 +1,10 @@
 package generator
 
 import (
+	"hash/fnv"
 	"math/rand"
 	"mokapi/config/static"
+	"sync"
 	"time"
 
 	"github.com/brianvoe/gofakeit/v6"
@@ -12,7 +14,9 @@ var types = []any{"string", "number", "integer", "boolean", "array", "object", "
 var weightTypes = []float32{1, 1, 1, 1, 0.2, 0.2, 0.05}
 
 type generator struct {
+	seed int64
 	rand *rand.Rand
+	fake *gofakeit.Faker
 	cfg  static.DataGen
 
 	root *Node
@@ -20,19 +24,48 @@ type generator struct {
 
 var g *generator
 
+var (
+	// m guards the seed sources of requests without seed
+	m sync.Mutex
+	// seeds provides the seeds of requests without seed
+	seeds = rand.New(rand.NewSource(time.Now().UnixNano()))
+	// nextSeedValue is used by the next request without seed if not zero
+	nextSeedValue int64
+)
+
 func init() {
 	g = &generator{
-		rand: rand.New(rand.NewSource(time.Now().Unix())),
+		fake: gofakeit.New(0),
 		root: buildTree(),
 	}
+	// the shared generator uses gofakeit's global faker so that Seed
+	// affects both
+	gofakeit.SetGlobalFaker(g.fake)
 }
 
 func SetConfig(cfg static.DataGen) {
 	g.cfg = cfg
 }
 
+// Deterministic reports whether data should be generated with a seed
+// derived from the request
+func Deterministic() bool {
+	return g.cfg.Deterministic
+}
+
 func New(r *Request) (interface{}, error) {
-	r.g = g
+	// nested requests share the random sources of the request
+	switch {
+	case r.Seed != 0 && (r.g == nil || r.g.seed != r.Seed):
+		r.g = g.withSeed(r.Seed)
+	case r.g == nil:
+		r.g = g.withSeed(nextSeed())
+	}
+	if l, ok := findLocale(r.Locale); ok {
+		r.locale = l
+	} else {
+		r.locale, _ = findLocale(r.g.cfg.Locale)
+	}
 	if r.Context == nil {
 		r.Context = newContext()
 	}
@@ -44,7 +77,61 @@ func New(r *Request) (interface{}, error) {
 	return f.fake()
 }
 
+// Seed makes data generation reproducible: the next request without seed
+// generates the same data as a request with the given seed, and the seeds of
+// the following requests are derived from it.
 func Seed(seed int64) {
-	gofakeit.Seed(seed)
-	g.rand.Seed(seed)
+	m.Lock()
+	defer m.Unlock()
+
+	nextSeedValue = seed
+	seeds = rand.New(rand.NewSource(seed))
+	// the global faker may have been replaced, so link it again
+	g.fake = gofakeit.New(seed)
+	gofakeit.SetGlobalFaker(g.fake)
+}
+
+// nextSeed returns the seed for a request without seed, so that each
+// request has its own random sources
+func nextSeed() int64 {
+	m.Lock()
+	defer m.Unlock()
+
+	if seed := nextSeedValue; seed != 0 {
+		nextSeedValue = 0
+		return seed
+	}
+	for {
+		if seed := seeds.Int63(); seed != 0 {
+			return seed
+		}
+	}
+}
+
+// withSeed returns a generator with its own random sources. It is used
+// per request, so concurrent requests do not affect each other's data.
+func (g *generator) withSeed(seed int64) *generator {
+	return &generator{
+		seed: seed,
+		rand: rand.New(rand.NewSource(seed)),
+		fake: gofakeit.New(seed),
+		cfg:  g.cfg,
+		root: g.root,
+	}
+}
+
+// SeedFromKey derives a non-zero seed from the given parts, e.g. API
+// name and request path. The same parts always return the same seed.
+func SeedFromKey(parts ...string) int64 {
+	h := fnv.New64a()
+	for _, p := range parts {
+		_, _ = h.Write([]byte(p))
+		// separator so that ("ab", "c") and ("a", "bc") differ
+		_, _ = h.Write([]byte{0})
+	}
+	seed := int64(h.Sum64() &^ (1 << 63))
+	if seed == 0 {
+		seed = 1
+	}
+	return seed
 }
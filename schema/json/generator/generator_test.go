@@ -47,6 +47,7 @@ func TestGenerator(t *testing.T) {
 
 func TestFakeNotDefined(t *testing.T) {
 	gofakeit.Seed(1234567)
+	generator.Seed(1234567)
 
 	root := generator.FindByName(generator.RootName)
 	require.NotNil(t, root)
@@ -66,3 +67,35 @@ func TestFakeNotDefined(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, int64(337128), v)
 }
+
+func TestRequest_Seed(t *testing.T) {
+	s := schematest.New("object",
+		schematest.WithProperty("id", schematest.New("integer")),
+		schematest.WithProperty("name", schematest.New("string")),
+		schematest.WithProperty("tags", schematest.New("array", schematest.WithItems("string"))),
+	)
+	newRequest := func(seed int64) *generator.Request {
+		r := generator.NewRequest([]string{"pets"}, s, nil)
+		r.Seed = seed
+		return r
+	}
+
+	v1, err := generator.New(newRequest(42))
+	require.NoError(t, err)
+	// a seeded request must not depend on the shared random source
+	generator.Seed(1)
+	v2, err := generator.New(newRequest(42))
+	require.NoError(t, err)
+	require.Equal(t, v1, v2)
+
+	v3, err := generator.New(newRequest(43))
+	require.NoError(t, err)
+	require.NotEqual(t, v1, v3)
+}
+
+func TestSeedFromKey(t *testing.T) {
+	require.Equal(t, generator.SeedFromKey("petstore", "/pets/{id}", "id", "12"), generator.SeedFromKey("petstore", "/pets/{id}", "id", "12"))
+	require.NotEqual(t, generator.SeedFromKey("petstore", "/pets/{id}", "id", "12"), generator.SeedFromKey("petstore", "/pets/{id}", "id", "13"))
+	require.NotEqual(t, generator.SeedFromKey("ab", "c"), generator.SeedFromKey("a", "bc"))
+	require.Greater(t, generator.SeedFromKey(), int64(0))
+}
@@ -1,7 +1,6 @@
 package generator
 
 import (
-	"github.com/brianvoe/gofakeit/v6"
 	"strings"
 )
 
@@ -30,12 +29,12 @@ func fakeId(r *Request) (interface{}, error) {
 		}
 
 		if minLength <= 37 && maxLength >= 37 {
-			return gofakeit.UUID(), nil
+			return r.g.fake.UUID(), nil
 		}
-		n := gofakeit.Number(minLength, maxLength)
-		return gofakeit.Numerify(strings.Repeat("#", n)), nil
+		n := r.g.fake.Number(minLength, maxLength)
+		return r.g.fake.Numerify(strings.Repeat("#", n)), nil
 	} else if s.IsInteger() || s.IsAny() {
-		return fakeIntegerWithRange(s, 1, 100000)
+		return fakeIntegerWithRange(r, s, 1, 100000)
 	}
 
 	return nil, NotSupported
@@ -121,6 +121,7 @@ func TestId(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			gofakeit.Seed(1234567)
+			Seed(1234567)
 
 			v, err := New(tc.req)
 			tc.test(t, v, err)
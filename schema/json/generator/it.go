@@ -3,7 +3,6 @@ package generator
 import (
 	"crypto/sha1"
 	"fmt"
-	"github.com/brianvoe/gofakeit/v6"
 	"strings"
 	"time"
 )
@@ -60,16 +59,16 @@ func newErrorNode() *Node {
 }
 
 func fakeError(r *Request) (interface{}, error) {
-	return gofakeit.Error().Error(), nil
+	return r.g.fake.Error().Error(), nil
 }
 
 func newHashNode() *Node {
 	return &Node{Name: "hash", Fake: fakeHash}
 }
 
-func fakeHash(_ *Request) (interface{}, error) {
+func fakeHash(r *Request) (interface{}, error) {
 	hash := sha1.New()
-	s := gofakeit.SentenceSimple()
+	s := r.g.fake.SentenceSimple()
 	b := hash.Sum([]byte(s))
 	return fmt.Sprintf("%x", b), nil
 }
@@ -108,28 +107,28 @@ func fakeUsername(r *Request) (interface{}, error) {
 func fakeUser(r *Request) (interface{}, error) {
 	s := r.Schema
 	if s.IsString() {
-		return gofakeit.Username(), nil
+		return r.g.fake.Username(), nil
 	}
-	firstname := gofakeit.FirstName()
-	lastname := gofakeit.LastName()
+	firstname := r.g.fake.FirstName()
+	lastname := r.g.fake.LastName()
 	first := strings.ToLower(firstname)
 	last := strings.ToLower(lastname)
 	return map[string]interface{}{
 		"firstname": firstname,
 		"lastname":  lastname,
-		"gender":    gofakeit.Gender(),
-		"email":     fmt.Sprintf("%s.%s@%s", first, last, gofakeit.DomainName()),
+		"gender":    r.g.fake.Gender(),
+		"email":     fmt.Sprintf("%s.%s@%s", first, last, r.g.fake.DomainName()),
 		"username":  fmt.Sprintf("%c%s", first[0], last),
 	}, nil
 }
 
-func fakeRole(_ *Request) (interface{}, error) {
-	index := gofakeit.Number(0, len(roles)-1)
+func fakeRole(r *Request) (interface{}, error) {
+	index := r.g.fake.Number(0, len(roles)-1)
 	return roles[index], nil
 }
 
-func fakePermission(_ *Request) (interface{}, error) {
-	index := gofakeit.Number(0, len(permissions)-1)
+func fakePermission(r *Request) (interface{}, error) {
+	index := r.g.fake.Number(0, len(permissions)-1)
 	return permissions[index], nil
 }
 
@@ -139,7 +138,7 @@ func fakeLastLogin(r *Request) (interface{}, error) {
 }
 
 func fakePassword(r *Request) (interface{}, error) {
-	return gofakeit.Password(true, true, true, true, false, 11), nil
+	return r.g.fake.Password(true, true, true, true, false, 11), nil
 }
 
 var roles = []string{
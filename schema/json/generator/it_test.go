@@ -2,6 +2,7 @@ package generator
 
 import (
 	"mokapi/schema/json/schema/schematest"
+	"sync"
 	"testing"
 	"time"
 
@@ -175,3 +176,28 @@ func TestUser(t *testing.T) {
 		})
 	}
 }
+
+func TestIt_User_Seed(t *testing.T) {
+	newRequest := func() *Request {
+		return &Request{Path: []string{"user"}, Seed: 42}
+	}
+
+	v1, err := New(newRequest())
+	require.NoError(t, err)
+
+	// neither the shared sources nor concurrent requests may affect a seeded request
+	gofakeit.Seed(1)
+	Seed(1)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = New(&Request{Path: []string{"user"}})
+		}()
+	}
+	v2, err := New(newRequest())
+	wg.Wait()
+	require.NoError(t, err)
+	require.Equal(t, v1, v2)
+}
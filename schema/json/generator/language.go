@@ -1,8 +1,6 @@
 package generator
 
-import (
-	"github.com/brianvoe/gofakeit/v6"
-)
+import ()
 
 func languages() []*Node {
 	return []*Node{
@@ -18,16 +16,16 @@ func fakeLanguage(r *Request) (any, error) {
 	if s.MaxLength != nil {
 		if *s.MaxLength == 2 {
 			// ISO-639-1
-			return gofakeit.LanguageAbbreviation(), nil
+			return r.g.fake.LanguageAbbreviation(), nil
 		}
 		if *s.MaxLength == 5 {
 			// BCP-47
-			return gofakeit.LanguageBCP(), nil
+			return r.g.fake.LanguageBCP(), nil
 		}
 		if *s.MaxLength > 5 {
 			// BCP-47
-			return gofakeit.Language(), nil
+			return r.g.fake.Language(), nil
 		}
 	}
-	return gofakeit.LanguageAbbreviation(), nil
+	return r.g.fake.LanguageAbbreviation(), nil
 }
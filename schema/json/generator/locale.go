@@ -0,0 +1,213 @@
+package generator
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"path"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+const defaultLocale = "en"
+
+// defaultIban is used for locales without IBAN, e.g. en and ja
+const defaultIban = "GB@@@@##############"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// locale contains localized data sets. The default locale en has no
+// data set and uses the built-in data.
+type locale struct {
+	Country     string `json:"country"`
+	CountryCode string `json:"countryCode"`
+	NameFormat  string `json:"nameFormat"`
+	FirstNames  struct {
+		Female []string `json:"female"`
+		Male   []string `json:"male"`
+	} `json:"firstNames"`
+	LastNames     []string     `json:"lastNames"`
+	Streets       []string     `json:"streets"`
+	HouseNumbers  []string     `json:"houseNumbers"`
+	StreetFormat  string       `json:"streetFormat"`
+	CityFormat    string       `json:"cityFormat"`
+	AddressFormat string       `json:"addressFormat"`
+	Cities        []localeCity `json:"cities"`
+	Phone         struct {
+		CountryCode string   `json:"countryCode"`
+		Numbers     []string `json:"numbers"`
+	} `json:"phone"`
+	Iban      string   `json:"iban"`
+	Companies []string `json:"companies"`
+}
+
+type localeCity struct {
+	Name     string `json:"name"`
+	State    string `json:"state"`
+	Postcode string `json:"postcode"`
+}
+
+var (
+	locales = loadLocales()
+	// supportedLocales is listed in the faker tree on nodes using
+	// localized data
+	supportedLocales = localeNames()
+	localeMatcher    = newLocaleMatcher()
+)
+
+func loadLocales() map[string]*locale {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	m := map[string]*locale{}
+	for _, f := range files {
+		b, err := localeFiles.ReadFile(path.Join("locales", f.Name()))
+		if err != nil {
+			panic(err)
+		}
+		l := &locale{}
+		if err = json.Unmarshal(b, l); err != nil {
+			panic(fmt.Errorf("invalid locale %v: %w", f.Name(), err))
+		}
+		m[strings.TrimSuffix(f.Name(), ".json")] = l
+	}
+	return m
+}
+
+func localeNames() []string {
+	names := []string{defaultLocale}
+	for name := range locales {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func newLocaleMatcher() language.Matcher {
+	// the first tag is the fallback of the matcher
+	tags := []language.Tag{language.Make(defaultLocale)}
+	for _, name := range supportedLocales {
+		if name != defaultLocale {
+			tags = append(tags, language.Make(name))
+		}
+	}
+	return language.NewMatcher(tags)
+}
+
+// findLocale returns the locale for a language tag like de-CH or an
+// Accept-Language header value like "fr-CH, fr;q=0.9, en;q=0.8". The
+// returned locale is nil for en. ok is false if no supported language
+// was found.
+func findLocale(s string) (l *locale, ok bool) {
+	if s == "" {
+		return nil, false
+	}
+	tags, _, err := language.ParseAcceptLanguage(s)
+	if err != nil || len(tags) == 0 {
+		return nil, false
+	}
+	tag, _, confidence := localeMatcher.Match(tags...)
+	if confidence == language.No {
+		return nil, false
+	}
+	base, _ := tag.Base()
+	return locales[base.String()], true
+}
+
+// fillPattern replaces # with a digit, % with a non-zero digit and @
+// with an uppercase letter
+func fillPattern(r *Request, pattern string) string {
+	sb := strings.Builder{}
+	for _, c := range pattern {
+		switch c {
+		case '#':
+			sb.WriteByte(byte('0' + r.g.fake.Number(0, 9)))
+		case '%':
+			sb.WriteByte(byte('0' + r.g.fake.Number(1, 9)))
+		case '@':
+			sb.WriteByte(byte('A' + r.g.fake.Number(0, 25)))
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+func pick(r *Request, values []string) string {
+	return values[r.g.fake.Number(0, len(values)-1)]
+}
+
+func (l *locale) firstNames(sex string) []string {
+	if sex[0] == 'm' {
+		return l.FirstNames.Male
+	}
+	return l.FirstNames.Female
+}
+
+func (l *locale) city(r *Request) localeCity {
+	if name, ok := r.Context.Values["city"]; ok {
+		for _, c := range l.Cities {
+			if c.Name == name {
+				return c
+			}
+		}
+	}
+	c := l.Cities[r.g.fake.Number(0, len(l.Cities)-1)]
+	r.Context.Values["city"] = c.Name
+	return c
+}
+
+func (l *locale) street(r *Request) string {
+	return strings.NewReplacer(
+		"{street}", pick(r, l.Streets),
+		"{number}", fillPattern(r, pick(r, l.HouseNumbers)),
+	).Replace(l.StreetFormat)
+}
+
+func (l *locale) cityLine(r *Request, pattern string, street string) string {
+	c := l.city(r)
+	return strings.NewReplacer(
+		"{street}", street,
+		"{zip}", fillPattern(r, c.Postcode),
+		"{city}", c.Name,
+		"{state}", c.State,
+	).Replace(pattern)
+}
+
+func (l *locale) company(r *Request) string {
+	// a new last name for each placeholder
+	pattern := pick(r, l.Companies)
+	for strings.Contains(pattern, "{lastname}") {
+		pattern = strings.Replace(pattern, "{lastname}", pick(r, l.LastNames), 1)
+	}
+	return pattern
+}
+
+// iban returns an IBAN with valid check digits
+func iban(r *Request, l *locale) string {
+	pattern := defaultIban
+	if l != nil && l.Iban != "" {
+		pattern = l.Iban
+	}
+	country := pattern[:2]
+	bban := fillPattern(r, pattern[2:])
+
+	// ISO 13616: move country code and check digits 00 to the end,
+	// replace letters with numbers (A=10, B=11, ...) and compute mod 97
+	digits := strings.Builder{}
+	for _, c := range bban + country + "00" {
+		if c >= 'A' && c <= 'Z' {
+			digits.WriteString(fmt.Sprintf("%d", c-'A'+10))
+		} else {
+			digits.WriteRune(c)
+		}
+	}
+	n, _ := new(big.Int).SetString(digits.String(), 10)
+	check := 98 - new(big.Int).Mod(n, big.NewInt(97)).Int64()
+	return fmt.Sprintf("%s%02d%s", country, check, bban)
+}
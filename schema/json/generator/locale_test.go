@@ -0,0 +1,208 @@
+package generator
+
+import (
+	"math/big"
+	"mokapi/config/static"
+	"mokapi/schema/json/schema/schematest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocale(t *testing.T) {
+	testcases := []struct {
+		name string
+		req  *Request
+		test func(t *testing.T, v any, err error)
+	}{
+		{
+			name: "lastname de",
+			req:  &Request{Path: []string{"person", "lastname"}, Schema: schematest.New("string"), Locale: "de-DE"},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				require.Contains(t, locales["de"].LastNames, v)
+			},
+		},
+		{
+			name: "Accept-Language header",
+			req:  &Request{Path: []string{"person", "lastname"}, Schema: schematest.New("string"), Locale: "es-ES, fr-CH;q=0.9, en;q=0.8"},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				require.Contains(t, locales["fr"].LastNames, v)
+			},
+		},
+		{
+			name: "unsupported locale uses built-in data",
+			req:  &Request{Path: []string{"person", "lastname"}, Schema: schematest.New("string"), Locale: "es"},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				require.Contains(t, lastNames, v)
+			},
+		},
+		{
+			name: "person name ja",
+			req: &Request{
+				Path: []string{"person"},
+				Schema: schematest.New("object",
+					schematest.WithProperty("firstname", schematest.New("string")),
+					schematest.WithProperty("lastname", schematest.New("string")),
+					schematest.WithProperty("name", schematest.New("string")),
+				),
+				Locale: "ja",
+			},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				m := v.(map[string]any)
+				require.Equal(t, m["lastname"].(string)+" "+m["firstname"].(string), m["name"])
+			},
+		},
+		{
+			name: "email of localized person",
+			req: &Request{
+				Path: []string{"person"},
+				Schema: schematest.New("object",
+					schematest.WithProperty("firstname", schematest.New("string")),
+					schematest.WithProperty("lastname", schematest.New("string")),
+					schematest.WithProperty("email", schematest.New("string")),
+					schematest.WithRequired("firstname", "lastname", "email"),
+				),
+				Locale: "de",
+			},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				m := v.(map[string]any)
+				name, ok := toAscii(m["firstname"].(string) + "." + m["lastname"].(string))
+				require.True(t, ok)
+				require.True(t, strings.HasPrefix(m["email"].(string), strings.ToLower(name)+"@"))
+			},
+		},
+		{
+			name: "city and zip match",
+			req: &Request{
+				Path: []string{"address"},
+				Schema: schematest.New("object",
+					schematest.WithProperty("city", schematest.New("string")),
+					schematest.WithProperty("zip", schematest.New("string")),
+				),
+				Locale: "de",
+			},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				m := v.(map[string]any)
+				for _, c := range locales["de"].Cities {
+					if c.Name == m["city"] {
+						require.Regexp(t, "^"+strings.ReplaceAll(c.Postcode, "#", "[0-9]")+"$", m["zip"])
+						return
+					}
+				}
+				t.Fatalf("unknown city %v", m["city"])
+			},
+		},
+		{
+			name: "zip with max length uses built-in data",
+			req: &Request{
+				Path:   []string{"zip"},
+				Schema: schematest.New("string", schematest.WithMaxLength(4)),
+				Locale: "ja",
+			},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				require.Regexp(t, "^[0-9]{4}$", v)
+			},
+		},
+		{
+			name: "address fr",
+			req:  &Request{Path: []string{"address"}, Locale: "fr"},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				m := v.(map[string]any)
+				require.Equal(t, "France", m["country"])
+				require.Equal(t, m["street"].(string)+", "+m["zip"].(string)+" "+m["city"].(string), m["address"])
+			},
+		},
+		{
+			name: "phone de",
+			req:  &Request{Path: []string{"phone"}, Schema: schematest.New("string"), Locale: "de"},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				require.Regexp(t, `^\+49[0-9]{10,11}$`, v)
+			},
+		},
+		{
+			name: "company ja",
+			req:  &Request{Path: []string{"company"}, Schema: schematest.New("string"), Locale: "ja"},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				require.Contains(t, v, "会社")
+			},
+		},
+		{
+			name: "iban de",
+			req:  &Request{Path: []string{"iban"}, Locale: "de"},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				require.Regexp(t, `^DE[0-9]{20}$`, v)
+				requireValidIban(t, v.(string))
+			},
+		},
+		{
+			name: "iban fr",
+			req:  &Request{Path: []string{"iban"}, Locale: "fr"},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				require.Regexp(t, `^FR[0-9]{25}$`, v)
+				requireValidIban(t, v.(string))
+			},
+		},
+		{
+			name: "iban without locale",
+			req:  &Request{Path: []string{"iban"}},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				require.Regexp(t, `^GB[0-9]{2}[A-Z]{4}[0-9]{14}$`, v)
+				requireValidIban(t, v.(string))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			Seed(1234567)
+
+			v, err := New(tc.req)
+			tc.test(t, v, err)
+		})
+	}
+}
+
+func TestLocale_Config(t *testing.T) {
+	SetConfig(static.DataGen{Locale: "fr-FR"})
+	defer SetConfig(static.DataGen{})
+
+	v, err := New(&Request{Path: []string{"person", "lastname"}, Schema: schematest.New("string")})
+	require.NoError(t, err)
+	require.Contains(t, locales["fr"].LastNames, v)
+
+	// request overrides config
+	v, err = New(&Request{Path: []string{"person", "lastname"}, Schema: schematest.New("string"), Locale: "de"})
+	require.NoError(t, err)
+	require.Contains(t, locales["de"].LastNames, v)
+}
+
+func TestLocale_Tree(t *testing.T) {
+	require.Equal(t, []string{"de", "en", "fr", "ja"}, FindByName("lastname").Locales)
+	require.Equal(t, []string{"de", "en", "fr", "ja"}, FindByName("iban").Locales)
+	require.Nil(t, FindByName("industry").Locales)
+}
+
+func requireValidIban(t *testing.T, iban string) {
+	rearranged := iban[4:] + iban[:4]
+	digits := regexp.MustCompile("[A-Z]").ReplaceAllStringFunc(rearranged, func(s string) string {
+		return big.NewInt(int64(s[0]-'A') + 10).String()
+	})
+	n, ok := new(big.Int).SetString(digits, 10)
+	require.True(t, ok)
+	require.Equal(t, int64(1), new(big.Int).Mod(n, big.NewInt(97)).Int64())
+}
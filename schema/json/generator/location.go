@@ -1,7 +1,6 @@
 package generator
 
 import (
-	"github.com/brianvoe/gofakeit/v6"
 	"mokapi/schema/json/parser"
 	"strings"
 )
@@ -40,10 +39,10 @@ func fakeCountry(r *Request) (any, error) {
 		}
 
 		if max == 2 {
-			country := gofakeit.CountryAbr()
+			country := r.g.fake.CountryAbr()
 			v = country
 		} else if s.Pattern != "" {
-			country := gofakeit.CountryAbr()
+			country := r.g.fake.CountryAbr()
 			p := parser.Parser{Schema: s}
 			_, err := p.Parse(country)
 			if err == nil {
@@ -59,7 +58,7 @@ func fakeCountry(r *Request) (any, error) {
 		}
 	}
 	if v == "" {
-		v = gofakeit.Country()
+		v = r.g.fake.Country()
 	}
 
 	r.Context.Values["country"] = v
@@ -67,13 +66,13 @@ func fakeCountry(r *Request) (any, error) {
 }
 
 func fakeLongitude(r *Request) (any, error) {
-	v := gofakeit.Longitude()
+	v := r.g.fake.Longitude()
 	r.Context.Values["longitude"] = v
 	return v, nil
 }
 
 func fakeLatitude(r *Request) (any, error) {
-	v := gofakeit.Latitude()
+	v := r.g.fake.Latitude()
 	r.Context.Values["latitude"] = v
 	return v, nil
 }
@@ -1,8 +1,6 @@
 package generator
 
-import (
-	"github.com/brianvoe/gofakeit/v6"
-)
+import ()
 
 func newNameNode() *Node {
 	return &Node{Name: "name", Fake: fakeName}
@@ -40,7 +38,7 @@ func fakeName(r *Request) (interface{}, error) {
 	}
 
 	if len(collection) > 0 {
-		index := gofakeit.Number(0, len(collection)-1)
+		index := r.g.fake.Number(0, len(collection)-1)
 		return collection[index], nil
 	}
 	return nil, NotSupported
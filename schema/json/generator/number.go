@@ -5,8 +5,6 @@ import (
 	"math"
 	"mokapi/schema/json/schema"
 	"strings"
-
-	"github.com/brianvoe/gofakeit/v6"
 )
 
 const (
@@ -38,7 +36,7 @@ func numbers() []*Node {
 		{
 			Name: "quantity",
 			Fake: func(r *Request) (interface{}, error) {
-				return fakeIntegerWithRange(r.Schema, 0, 100)
+				return fakeIntegerWithRange(r, r.Schema, 0, 100)
 			},
 		},
 	}
@@ -49,13 +47,13 @@ func fakeYear(r *Request) (interface{}, error) {
 	if s.IsAny() {
 		s = &schema.Schema{Type: []string{"integer"}}
 	}
-	return fakeIntegerWithRange(s, 1900, 2199)
+	return fakeIntegerWithRange(r, s, 1900, 2199)
 }
 
-func fakeInteger(s *schema.Schema) (any, error) {
+func fakeInteger(r *Request, s *schema.Schema) (any, error) {
 	hasRange := hasNumberRange(s)
 	if !hasRange && s.MultipleOf == nil {
-		return int64(gofakeit.Number(defaultMin, defaultMax)), nil
+		return int64(r.g.fake.Number(defaultMin, defaultMax)), nil
 	}
 	if hasRange {
 		minValue, maxValue := getRangeWithDefault(s, defaultMin, defaultMax)
@@ -63,25 +61,25 @@ func fakeInteger(s *schema.Schema) (any, error) {
 			return nil, fmt.Errorf("%w in %s", err, s)
 		}
 		if s.MultipleOf != nil {
-			v, err := randomMultiple(int(minValue), int(maxValue), int(*s.MultipleOf))
+			v, err := randomMultiple(r, int(minValue), int(maxValue), int(*s.MultipleOf))
 			if err != nil {
 				return nil, err
 			}
 			return int64(v), nil
 		}
-		v := int64(math.Round(gofakeit.Float64Range(minValue, maxValue)))
+		v := int64(math.Round(r.g.fake.Float64Range(minValue, maxValue)))
 		return v, nil
 	}
 	minValue := 0
 	maxValue := 100
-	n := gofakeit.Number(minValue, maxValue)
+	n := r.g.fake.Number(minValue, maxValue)
 	v := n * int(*s.MultipleOf)
 	return int64(v), nil
 }
 
-func fakeIntegerWithRange(s *schema.Schema, min, max int) (any, error) {
+func fakeIntegerWithRange(r *Request, s *schema.Schema, min, max int) (any, error) {
 	if s.IsAny() {
-		return gofakeit.Number(min, max), nil
+		return r.g.fake.Number(min, max), nil
 	}
 
 	minValue, maxValue := getRangeWithDefault(s, float64(min), float64(max))
@@ -93,7 +91,7 @@ func fakeIntegerWithRange(s *schema.Schema, min, max int) (any, error) {
 	max = int(maxValue)
 
 	if s.MultipleOf != nil {
-		v, err := randomMultiple(min, max, int(*s.MultipleOf))
+		v, err := randomMultiple(r, min, max, int(*s.MultipleOf))
 		if err != nil {
 			return nil, err
 		}
@@ -102,7 +100,7 @@ func fakeIntegerWithRange(s *schema.Schema, min, max int) (any, error) {
 		}
 		return int64(v), nil
 	}
-	v := gofakeit.Number(min, max)
+	v := r.g.fake.Number(min, max)
 	if s.Format == "int32" {
 		return int32(v), nil
 	}
@@ -130,7 +128,7 @@ func fakeNumber(r *Request) (interface{}, error) {
 func newNumber(r *Request) (interface{}, error) {
 	s := r.Schema
 	if s == nil {
-		return gofakeit.Float64(), nil
+		return r.g.fake.Float64(), nil
 	}
 
 	if s.IsString() {
@@ -148,20 +146,20 @@ func newNumber(r *Request) (interface{}, error) {
 		if minLength == maxLength {
 			n = minLength
 		} else {
-			n = gofakeit.Number(minLength, maxLength)
+			n = r.g.fake.Number(minLength, maxLength)
 		}
-		return gofakeit.Numerify(strings.Repeat("#", n)), nil
+		return r.g.fake.Numerify(strings.Repeat("#", n)), nil
 	}
 	if s.IsInteger() {
-		return fakeInteger(s)
+		return fakeInteger(r, s)
 	}
 
 	hasRange := hasNumberRange(s)
 	if !hasRange && s.MultipleOf == nil {
 		if s.Format == "float" {
-			return float32(gofakeit.Float64Range(defaultMin, defaultMax)), nil
+			return float32(r.g.fake.Float64Range(defaultMin, defaultMax)), nil
 		}
-		return gofakeit.Float64Range(defaultMin, defaultMax), nil
+		return r.g.fake.Float64Range(defaultMin, defaultMax), nil
 	}
 	if hasRange {
 		minValue, maxValue := getRangeWithDefault(s, defaultMin, defaultMax)
@@ -169,7 +167,7 @@ func newNumber(r *Request) (interface{}, error) {
 			return nil, fmt.Errorf("%w in %s", err, s)
 		}
 		if s.MultipleOf != nil {
-			v, err := randomFloatMultiple(minValue, maxValue, *s.MultipleOf)
+			v, err := randomFloatMultiple(r, minValue, maxValue, *s.MultipleOf)
 			if err != nil {
 				return nil, err
 			}
@@ -178,7 +176,7 @@ func newNumber(r *Request) (interface{}, error) {
 			}
 			return v, nil
 		}
-		v := gofakeit.Float64Range(minValue, maxValue)
+		v := r.g.fake.Float64Range(minValue, maxValue)
 		if s.Format == "float" {
 			return float32(v), nil
 		}
@@ -186,7 +184,7 @@ func newNumber(r *Request) (interface{}, error) {
 	}
 	minValue := 0
 	maxValue := 100
-	n := gofakeit.Number(minValue, maxValue)
+	n := r.g.fake.Number(minValue, maxValue)
 	v := float64(n) * *s.MultipleOf
 	if s.Format == "float" {
 		return float32(v), nil
@@ -196,7 +194,7 @@ func newNumber(r *Request) (interface{}, error) {
 
 func fakeAge(r *Request) (interface{}, error) {
 	minValue, maxValue := getRangeWithDefault(r.Schema, 0, 100)
-	return int64(gofakeit.Number(int(minValue), int(maxValue))), nil
+	return int64(r.g.fake.Number(int(minValue), int(maxValue))), nil
 }
 
 func getRangeWithDefault(s *schema.Schema, min, max float64) (float64, float64) {
@@ -238,7 +236,7 @@ func hasNumberRange(s *schema.Schema) bool {
 	return s.Minimum != nil || s.Maximum != nil || s.ExclusiveMinimum != nil || s.ExclusiveMaximum != nil
 }
 
-func randomMultiple(min, max, multipleOf int) (int, error) {
+func randomMultiple(r *Request, min, max, multipleOf int) (int, error) {
 	// Adjust min and max to be aligned with multipleOf
 	adjustedMin := ((min + multipleOf - 1) / multipleOf) * multipleOf
 	adjustedMax := (max / multipleOf) * multipleOf
@@ -248,12 +246,12 @@ func randomMultiple(min, max, multipleOf int) (int, error) {
 	}
 
 	count := ((adjustedMax - adjustedMin) / multipleOf) + 1
-	n := gofakeit.Number(0, count)
+	n := r.g.fake.Number(0, count)
 
 	return adjustedMin + n*multipleOf, nil
 }
 
-func randomFloatMultiple(min, max, multipleOf float64) (float64, error) {
+func randomFloatMultiple(r *Request, min, max, multipleOf float64) (float64, error) {
 	start := math.Ceil(min / multipleOf)
 	end := math.Floor(max / multipleOf)
 
@@ -261,7 +259,7 @@ func randomFloatMultiple(min, max, multipleOf float64) (float64, error) {
 		return 0, fmt.Errorf("no valid multiple in range")
 	}
 
-	n := gofakeit.Number(0, int(end-start)+1) + int(start)
+	n := r.g.fake.Number(0, int(end-start)+1) + int(start)
 	return float64(n) * multipleOf, nil
 }
 
@@ -12,8 +12,6 @@ import (
 	"sort"
 	"strings"
 	"unicode"
-
-	"github.com/brianvoe/gofakeit/v6"
 )
 
 var (
@@ -110,7 +108,7 @@ func (r *resolver) resolveObject(req *Request) (*faker, error) {
 
 					n := len(result)
 					if n >= minProps {
-						n := gofakeit.Float64Range(0, 1)
+						n := req.g.fake.Float64Range(0, 1)
 						if n > req.g.cfg.OptionalPropertiesProbability() {
 							continue
 						}
@@ -273,10 +271,10 @@ func (r *resolver) fakeObject(req *Request) (*sortedmap.LinkedHashMap[string, *f
 			if err != nil {
 				return nil, fmt.Errorf("could not parse regex string: %v", pattern)
 			}
-			n := numPatterProperties()
+			n := numPatterProperties(req)
 			fmt.Printf("numPatterProperties: %v\n", n)
 			for i := 0; i < n; i++ {
-				gen := regexGenerator{ra: req.g.rand}
+				gen := regexGenerator{ra: req.g.rand, fake: req.g.fake}
 				gen.regexGenerate(re, len(pattern)*100)
 				propName := gen.sb.String()
 				ex := propertyFromExample(propName, req)
@@ -296,13 +294,13 @@ func (r *resolver) fakeObject(req *Request) (*sortedmap.LinkedHashMap[string, *f
 		// if additionalProperties=false no additional properties is allowed
 		// if additionalProperties=true we don't add random properties, it is not expected by users
 
-		length := numProperties(1, 10, s)
+		length := numProperties(req, 1, 10, s)
 		for i := 0; i < length; i++ {
 			f, err := r.resolve(req.WithSchema(s.AdditionalProperties), true)
 			if err != nil {
 				return nil, err
 			}
-			key, err := newPropertyName(propertyNameParser)
+			key, err := newPropertyName(req, propertyNameParser)
 			if err != nil {
 				continue
 			}
@@ -324,7 +322,7 @@ func (r *resolver) fakeObject(req *Request) (*sortedmap.LinkedHashMap[string, *f
 }
 
 func (r *resolver) fakeDictionary(req *Request) (*sortedmap.LinkedHashMap[string, *faker], error) {
-	length := numProperties(1, 10, req.Schema)
+	length := numProperties(req, 1, 10, req.Schema)
 	fakes := &sortedmap.LinkedHashMap[string, *faker]{}
 	propertyNameParser := propertyNamesParser(req.Schema)
 	for i := 0; i < length; i++ {
@@ -332,7 +330,7 @@ func (r *resolver) fakeDictionary(req *Request) (*sortedmap.LinkedHashMap[string
 		if err != nil {
 			return nil, err
 		}
-		key, err := newPropertyName(propertyNameParser)
+		key, err := newPropertyName(req, propertyNameParser)
 		if err != nil {
 			continue
 		}
@@ -341,11 +339,13 @@ func (r *resolver) fakeDictionary(req *Request) (*sortedmap.LinkedHashMap[string
 	return fakes, nil
 }
 
-func newPropertyName(propertyNameParser *parser.Parser) (string, error) {
-	key := gofakeit.Noun()
+func newPropertyName(r *Request, propertyNameParser *parser.Parser) (string, error) {
+	key := r.g.fake.Noun()
 	if _, err := propertyNameParser.Parse(key); err != nil {
 		var v any
-		v, err = New(NewRequest(nil, propertyNameParser.Schema, nil))
+		req := NewRequest(nil, propertyNameParser.Schema, nil)
+		req.g = r.g
+		v, err = New(req)
 		if err != nil {
 			return "", err
 		}
@@ -365,7 +365,7 @@ func firstLetterToLower(s string) string {
 	return string(r)
 }
 
-func numProperties(min, max int, s *schema.Schema) int {
+func numProperties(r *Request, min, max int, s *schema.Schema) int {
 	if s.MinProperties != nil {
 		min = *s.MinProperties
 	} else if s.Required != nil {
@@ -377,7 +377,7 @@ func numProperties(min, max int, s *schema.Schema) int {
 		if len(s.Required) > max {
 			max = len(s.Required)
 		} else {
-			n := gofakeit.Float32Range(0, 1)
+			n := r.g.fake.Float32Range(0, 1)
 			if n < 0.8 {
 				max = len(s.Required)
 			}
@@ -386,12 +386,12 @@ func numProperties(min, max int, s *schema.Schema) int {
 	if min == max {
 		return min
 	} else {
-		return gofakeit.Number(min, max)
+		return r.g.fake.Number(min, max)
 	}
 }
 
-func numPatterProperties() int {
-	n, err := gofakeit.Weighted(numPatternProperties, weightsPatternProperties)
+func numPatterProperties(r *Request) int {
+	n, err := r.g.fake.Weighted(numPatternProperties, weightsPatternProperties)
 	if err != nil {
 		return 1
 	}
@@ -527,7 +527,7 @@ func propertyFromExample(prop string, r *Request) []any {
 }
 
 func fakeByExample(r *Request) (*faker, error) {
-	v, ok := example(r.Schema)
+	v, ok := example(r, r.Schema)
 	if !ok {
 		return nil, NoMatchFound
 	}
@@ -554,12 +554,12 @@ func examplesFromRequest(r *Request) []any {
 	return result
 }
 
-func example(s *schema.Schema) (any, bool) {
+func example(r *Request, s *schema.Schema) (any, bool) {
 	if s == nil || len(s.Examples) == 0 {
 		return nil, false
 	}
 
-	index := gofakeit.Number(0, len(s.Examples)-1)
+	index := r.g.fake.Number(0, len(s.Examples)-1)
 	return s.Examples[index].Value, true
 }
 
@@ -668,7 +668,7 @@ func applyObjectAnyOf(req *Request, result map[string]any, changed *bool) error
 	}
 	if !isOneValid {
 		err = fakeWithRetries(10, func() error {
-			i := gofakeit.Number(0, len(base.AnyOf)-1)
+			i := req.g.fake.Number(0, len(base.AnyOf)-1)
 			as, err := extendBranchWithBase(base.AnyOf[i], base)
 			if err != nil {
 				return fmt.Errorf("cannot extend anyOf: %w", err)
@@ -755,7 +755,7 @@ func applyOneOf(req *Request, result map[string]any, changed *bool) error {
 		return nil
 	}
 
-	index := gofakeit.Number(0, len(base.OneOf)-1)
+	index := req.g.fake.Number(0, len(base.OneOf)-1)
 	var err error
 	for i := 0; i < len(base.OneOf); i++ {
 
@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"mokapi/schema/json/parser"
 	"mokapi/schema/json/schema"
-
-	"github.com/brianvoe/gofakeit/v6"
 )
 
 const maxOneOfTries = 20
@@ -14,7 +12,7 @@ func (r *resolver) oneOf(req *Request) (*faker, error) {
 	s := req.Schema
 	p := parser.Parser{}
 	f := func() (any, error) {
-		index := gofakeit.Number(0, len(s.OneOf)-1)
+		index := req.g.fake.Number(0, len(s.OneOf)-1)
 		var err error
 		for i := 0; i < len(s.OneOf); i++ {
 
@@ -24,7 +24,7 @@ func fakePattern(r *Request) (interface{}, error) {
 		}
 	}()
 
-	reg := regexGenerator{ra: r.g.rand}
+	reg := regexGenerator{ra: r.g.rand, fake: r.g.fake}
 	reg.regexGenerate(re, len(s.Pattern)*100)
 	if s.MinLength != nil {
 		minLength := *s.MinLength
@@ -43,6 +43,7 @@ func fakePattern(r *Request) (interface{}, error) {
 type regexGenerator struct {
 	sb      strings.Builder
 	ra      *rand.Rand
+	fake    *gofakeit.Faker
 	fillers []func(max int)
 }
 
@@ -184,13 +185,13 @@ func (g *regexGenerator) regexGenerate(re *syntax.Regexp, max int) {
 			g.regexGenerate(rs, max)
 		}
 	case syntax.OpAlternate: // matches alternation of Subs
-		g.regexGenerate(re.Sub[gofakeit.Number(0, len(re.Sub)-1)], max)
+		g.regexGenerate(re.Sub[g.fake.Number(0, len(re.Sub)-1)], max)
 	}
 }
 
 func (g *regexGenerator) opStar(re *syntax.Regexp, limit int) {
 	max := int(math.Min(float64(limit), float64(10)))
-	for i := 0; i < gofakeit.Number(0, max); i++ {
+	for i := 0; i < g.fake.Number(0, max); i++ {
 		for _, rs := range re.Sub {
 			g.regexGenerate(rs, limit)
 		}
@@ -199,7 +200,7 @@ func (g *regexGenerator) opStar(re *syntax.Regexp, limit int) {
 
 func (g *regexGenerator) opPlus(re *syntax.Regexp, min, limit int) {
 	max := int(math.Min(10, float64(limit)))
-	for i := 0; i < gofakeit.Number(min, max); i++ {
+	for i := 0; i < g.fake.Number(min, max); i++ {
 		for _, rs := range re.Sub {
 			g.regexGenerate(rs, limit)
 
@@ -223,7 +224,7 @@ func (g *regexGenerator) opRepeat(re *syntax.Regexp, limit int) int {
 }
 
 func (g *regexGenerator) opQuest(re *syntax.Regexp, limit int) bool {
-	n := gofakeit.Number(0, 1)
+	n := g.fake.Number(0, 1)
 	if n == 1 {
 		for _, rs := range re.Sub {
 			g.regexGenerate(rs, limit)
@@ -5,8 +5,6 @@ import (
 	"math"
 	"strings"
 	"time"
-
-	"github.com/brianvoe/gofakeit/v6"
 )
 
 var personal = []*Node{
@@ -16,19 +14,22 @@ var personal = []*Node{
 			"person",
 			"owner",
 		},
-		Fake: fakePerson,
+		Fake:    fakePerson,
+		Locales: supportedLocales,
 		Children: []*Node{
 			{
 				Name:       "name",
 				Attributes: []string{"name", "fullname"},
 				DependsOn:  []string{"firstname", "lastname"},
 				Fake:       fakePersonName,
+				Locales:    supportedLocales,
 			},
 			{
 				Name:      "firstname",
 				DependsOn: []string{"gender", "sex"},
 				Weight:    1.0,
 				Fake:      fakeFirstname,
+				Locales:   supportedLocales,
 			},
 			{
 				Name: "first",
@@ -38,12 +39,14 @@ var personal = []*Node{
 						DependsOn: []string{"gender", "sex"},
 						Weight:    0.5,
 						Fake:      fakeFirstname,
+						Locales:   supportedLocales,
 					},
 					{
 						Name:      "name2",
 						DependsOn: []string{"gender", "sex"},
 						Weight:    1.0,
 						Fake:      fakeMiddlename,
+						Locales:   supportedLocales,
 					},
 				},
 			},
@@ -55,6 +58,7 @@ var personal = []*Node{
 						DependsOn: []string{"gender", "sex"},
 						Weight:    0.5,
 						Fake:      fakeMiddlename,
+						Locales:   supportedLocales,
 					},
 				},
 			},
@@ -63,25 +67,29 @@ var personal = []*Node{
 				DependsOn: []string{"gender", "sex"},
 				Weight:    1.0,
 				Fake:      fakeMiddlename,
+				Locales:   supportedLocales,
 			},
 			{
 				Name:      "firstname2",
 				DependsOn: []string{"gender", "sex"},
 				Weight:    1.0,
 				Fake:      fakeMiddlename,
+				Locales:   supportedLocales,
 			},
 			{
-				Name:   "lastname",
-				Weight: 1.0,
-				Fake:   fakeLastname,
+				Name:    "lastname",
+				Weight:  1.0,
+				Fake:    fakeLastname,
+				Locales: supportedLocales,
 			},
 			{
 				Name: "last",
 				Children: []*Node{
 					{
-						Name:   "name",
-						Weight: 0.5,
-						Fake:   fakeLastname,
+						Name:    "name",
+						Weight:  0.5,
+						Fake:    fakeLastname,
+						Locales: supportedLocales,
 					},
 				},
 			},
@@ -138,22 +146,26 @@ var personal = []*Node{
 		Fake: fakeGender,
 	},
 	{
-		Name: "phone",
-		Fake: fakePhone,
+		Name:    "phone",
+		Fake:    fakePhone,
+		Locales: supportedLocales,
 		Children: []*Node{
 			{
-				Name: "number",
-				Fake: fakePhone,
+				Name:    "number",
+				Fake:    fakePhone,
+				Locales: supportedLocales,
 			},
 		},
 	},
 	{
-		Name: "fax",
-		Fake: fakePhone,
+		Name:    "fax",
+		Fake:    fakePhone,
+		Locales: supportedLocales,
 		Children: []*Node{
 			{
-				Name: "number",
-				Fake: fakePhone,
+				Name:    "number",
+				Fake:    fakePhone,
+				Locales: supportedLocales,
 			},
 		},
 	},
@@ -162,9 +174,10 @@ var personal = []*Node{
 		DependsOn: []string{"firstname", "lastname"},
 		Children: []*Node{
 			{
-				Name:   "phone",
-				Weight: 0.5,
-				Fake:   fakePhone,
+				Name:    "phone",
+				Weight:  0.5,
+				Fake:    fakePhone,
+				Locales: supportedLocales,
 			},
 			{
 				Name:   "email",
@@ -176,7 +189,8 @@ var personal = []*Node{
 				Fake: fakeContactType,
 			},
 		},
-		Fake: fakeContact,
+		Fake:    fakeContact,
+		Locales: supportedLocales,
 	},
 }
 
@@ -200,7 +214,10 @@ func fakePersonName(r *Request) (any, error) {
 	}
 
 	if middle != nil {
-		return fmt.Sprintf("%s %s %s", first, middle, last), nil
+		first = fmt.Sprintf("%s %s", first, middle)
+	}
+	if l := r.locale; l != nil {
+		return strings.NewReplacer("{firstname}", fmt.Sprint(first), "{lastname}", fmt.Sprint(last)).Replace(l.NameFormat), nil
 	}
 
 	return fmt.Sprintf("%s %s", first, last), nil
@@ -221,8 +238,11 @@ func fakeFirstname(r *Request) (any, error) {
 	if sex[0] == 'm' {
 		pool = maleFirstNames
 	}
+	if l := r.locale; l != nil {
+		pool = l.firstNames(sex)
+	}
 
-	index := gofakeit.Number(0, len(pool)-1)
+	index := r.g.fake.Number(0, len(pool)-1)
 	firstname := pool[index]
 	r.Context.Values["firstname"] = firstname
 	return firstname, nil
@@ -243,8 +263,11 @@ func fakeMiddlename(r *Request) (any, error) {
 	if sex[0] == 'm' {
 		pool = middleNamesMale
 	}
+	if l := r.locale; l != nil {
+		pool = l.firstNames(sex)
+	}
 
-	index := gofakeit.Number(0, len(pool)-1)
+	index := r.g.fake.Number(0, len(pool)-1)
 	middle := pool[index]
 	r.Context.Values["middlename"] = middle
 	return middle, nil
@@ -255,8 +278,12 @@ func fakeLastname(r *Request) (any, error) {
 		return v, nil
 	}
 
-	index := gofakeit.Number(0, len(lastNames)-1)
-	last := lastNames[index]
+	pool := lastNames
+	if l := r.locale; l != nil {
+		pool = l.LastNames
+	}
+	index := r.g.fake.Number(0, len(pool)-1)
+	last := pool[index]
 	r.Context.Values["lastname"] = last
 	return last, nil
 }
@@ -269,7 +296,7 @@ func fakeGender(r *Request) (any, error) {
 		return v, nil
 	}
 
-	v := gofakeit.Gender()
+	v := r.g.fake.Gender()
 
 	if r.Schema != nil && r.Schema.MaxLength != nil {
 		m := *r.Schema.MaxLength
@@ -286,7 +313,7 @@ func fakeGender(r *Request) (any, error) {
 
 func fakePersonAge(r *Request) (any, error) {
 	minValue, maxValue := getRangeWithDefault(r.Schema, 1, 100)
-	return gofakeit.Number(int(minValue), int(maxValue)), nil
+	return r.g.fake.Number(int(minValue), int(maxValue)), nil
 }
 
 func fakePerson(r *Request) (any, error) {
@@ -305,8 +332,14 @@ func fakePerson(r *Request) (any, error) {
 
 func fakePhone(r *Request) (any, error) {
 	s := r.Schema
+	if l := r.locale; l != nil {
+		phone := fmt.Sprintf("+%v%v", l.Phone.CountryCode, fillPattern(r, pick(r, l.Phone.Numbers)))
+		if (s == nil || s.MinLength == nil || len(phone) >= *s.MinLength) && (s == nil || s.MaxLength == nil || len(phone) <= *s.MaxLength) {
+			return phone, nil
+		}
+	}
 
-	countryCode := gofakeit.IntRange(1, 999)
+	countryCode := r.g.fake.IntRange(1, 999)
 	countryCodeLen := len(fmt.Sprintf("%v", countryCode))
 	maxValue := 15 - countryCodeLen
 	minValue := 4
@@ -316,8 +349,8 @@ func fakePhone(r *Request) (any, error) {
 	if s != nil && s.MaxLength != nil {
 		maxValue = *s.MaxLength - countryCodeLen - 1
 	}
-	nationalCodeLen := gofakeit.IntRange(minValue, maxValue)
-	return fmt.Sprintf("+%v%v", countryCode, gofakeit.Numerify(strings.Repeat("#", nationalCodeLen))), nil
+	nationalCodeLen := r.g.fake.IntRange(minValue, maxValue)
+	return fmt.Sprintf("+%v%v", countryCode, r.g.fake.Numerify(strings.Repeat("#", nationalCodeLen))), nil
 }
 
 func fakeContact(r *Request) (any, error) {
@@ -342,18 +375,18 @@ func fakeBirthday(r *Request) (any, error) {
 func fakeDateInPastWithMinYear(r *Request, minYear int) (any, error) {
 	now := time.Now()
 
-	year := gofakeit.Number(1940, time.Now().Year())
+	year := r.g.fake.Number(1940, time.Now().Year())
 	year = int(math.Max(float64(year), float64(minYear)))
-	month := gofakeit.Number(1, 12)
+	month := r.g.fake.Number(1, 12)
 	if year == now.Year() {
-		month = gofakeit.Number(1, int(now.Month()))
+		month = r.g.fake.Number(1, int(now.Month()))
 	}
 
-	day := gofakeit.Number(1, maxDayInMonth[month-1])
-	hour := gofakeit.Number(0, 23)
-	minute := gofakeit.Number(0, 59)
-	second := gofakeit.Number(0, 59)
-	nanosecond := gofakeit.Number(0, 999999999)
+	day := r.g.fake.Number(1, maxDayInMonth[month-1])
+	hour := r.g.fake.Number(0, 23)
+	minute := r.g.fake.Number(0, 59)
+	second := r.g.fake.Number(0, 59)
+	nanosecond := r.g.fake.Number(0, 999999999)
 
 	d := time.Date(year, time.Month(month), day, hour, minute, second, nanosecond, time.UTC)
 	if r.Schema != nil && r.Schema.Format == "date-time" {
@@ -363,8 +396,8 @@ func fakeDateInPastWithMinYear(r *Request, minYear int) (any, error) {
 	return d.Format("2006-01-02"), nil
 }
 
-func fakeContactType(_ *Request) (any, error) {
-	index := gofakeit.Number(0, len(contactTypes)-1)
+func fakeContactType(r *Request) (any, error) {
+	index := r.g.fake.Number(0, len(contactTypes)-1)
 	return contactTypes[index], nil
 }
 
@@ -389,7 +422,7 @@ func fakePersonTitle(r *Request) (any, error) {
 		pool = maleTitles
 	}
 
-	index := gofakeit.Number(0, len(pool)-1)
+	index := r.g.fake.Number(0, len(pool)-1)
 	title := pool[index]
 	r.Context.Values["title"] = title
 	return title, nil
@@ -1,7 +1,5 @@
 package generator
 
-import "github.com/brianvoe/gofakeit/v6"
-
 func pets() []*Node {
 	return []*Node{
 		{
@@ -48,7 +46,7 @@ func fakePetName(r *Request) (any, error) {
 		return v, nil
 	}
 
-	v := gofakeit.PetName()
+	v := r.g.fake.PetName()
 	r.Context.Values["pet"] = v
 	return v, nil
 }
@@ -58,7 +56,7 @@ func fakePetCategory(r *Request) (any, error) {
 		return v, nil
 	}
 
-	index := gofakeit.Number(0, len(petCategory)-1)
+	index := r.g.fake.Number(0, len(petCategory)-1)
 	v := petCategory[index]
 	r.Context.Values["category"] = v
 	return v, nil
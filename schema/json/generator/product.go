@@ -1,7 +1,5 @@
 package generator
 
-import "github.com/brianvoe/gofakeit/v6"
-
 func products() []*Node {
 	return []*Node{
 		{
@@ -29,17 +27,17 @@ func products() []*Node {
 }
 
 func fakeProductName(r *Request) (any, error) {
-	return gofakeit.ProductName(), nil
+	return r.g.fake.ProductName(), nil
 }
 
 func fakeProductDescription(r *Request) (any, error) {
-	return gofakeit.ProductDescription(), nil
+	return r.g.fake.ProductDescription(), nil
 }
 
 func fakeProductCategory(r *Request) (any, error) {
-	return gofakeit.ProductCategory(), nil
+	return r.g.fake.ProductCategory(), nil
 }
 
 func fakeProductMaterial(r *Request) (any, error) {
-	return gofakeit.ProductMaterial(), nil
+	return r.g.fake.ProductMaterial(), nil
 }
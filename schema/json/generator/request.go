@@ -8,8 +8,15 @@ type Request struct {
 	Path    []string       `json:"path"`
 	Schema  *schema.Schema `json:"schema"`
 	Context *Context       `json:"context"`
+	// Seed makes the generated data reproducible. If zero, the
+	// shared random source is used.
+	Seed int64 `json:"seed,omitempty"`
+	// Locale is a language tag or an Accept-Language header value,
+	// e.g. de-DE. If empty or not supported, the configured locale is used.
+	Locale string `json:"locale,omitempty"`
 
 	g        *generator
+	locale   *locale
 	examples []any
 }
 
@@ -22,6 +29,7 @@ func NewRequest(path []string, s *schema.Schema, ctx map[string]any) *Request {
 		Path:    path,
 		Schema:  s,
 		Context: &Context{Values: ctx},
+		g:       g.withSeed(nextSeed()),
 	}
 }
 
@@ -53,6 +61,8 @@ func (r *Request) With(path []string, s *schema.Schema, example []any) *Request
 		Path:     path,
 		Schema:   s,
 		g:        r.g,
+		Locale:   r.Locale,
+		locale:   r.locale,
 		Context:  r.Context,
 		examples: example,
 	}
@@ -8,8 +8,6 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
-
-	"github.com/brianvoe/gofakeit/v6"
 )
 
 type resolver struct {
@@ -38,7 +36,7 @@ func (r *resolver) resolve(req *Request, fallback bool) (*faker, error) {
 	}
 
 	s := req.Schema
-	if f, ok := nullable(s); ok {
+	if f, ok := nullable(req, s); ok {
 		return f, nil
 	}
 
@@ -62,7 +60,7 @@ func (r *resolver) resolve(req *Request, fallback bool) (*faker, error) {
 		inferType := inferTypeFromKeywords(s)
 		switch {
 		case s.IsObject() && s.IsArray():
-			n := gofakeit.Number(0, 1)
+			n := req.g.fake.Number(0, 1)
 			if n == 0 {
 				return r.resolveObject(req)
 			}
@@ -74,7 +72,7 @@ func (r *resolver) resolve(req *Request, fallback bool) (*faker, error) {
 		default:
 			switch {
 			case len(s.AnyOf) > 0:
-				i := gofakeit.Number(0, len(s.AnyOf)-1)
+				i := req.g.fake.Number(0, len(s.AnyOf)-1)
 				return r.resolve(req.WithSchema(s.AnyOf[i]), fallback)
 			case len(s.AllOf) > 0:
 				allOf, err := intersectSchemas(s.AllOf...)
@@ -217,9 +215,9 @@ func (e *RecursionGuard) Error() string {
 	return fmt.Sprintf("recursion in object path found but schema does not allow null: %v", e.s)
 }
 
-func nullable(s *schema.Schema) (*faker, bool) {
+func nullable(r *Request, s *schema.Schema) (*faker, bool) {
 	if s != nil && s.IsNullable() {
-		n := gofakeit.Float32Range(0, 1)
+		n := r.g.fake.Float32Range(0, 1)
 		if n < 0.05 {
 			return newFaker(func() (any, error) {
 				return nil, nil
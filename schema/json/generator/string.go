@@ -1,10 +1,7 @@
 package generator
 
 import (
-	"mokapi/schema/json/schema"
 	"time"
-
-	"github.com/brianvoe/gofakeit/v6"
 )
 
 var (
@@ -26,7 +23,7 @@ func fakeString(r *Request) (interface{}, error) {
 	}
 	if s.MinLength == nil && s.MaxLength == nil {
 		if s.Format != "" {
-			return fakeFormat(s)
+			return fakeFormat(r)
 		}
 	}
 
@@ -43,55 +40,56 @@ func fakeString(r *Request) (interface{}, error) {
 		maxLength = *s.MaxLength
 	}
 
-	length := gofakeit.IntRange(minLength, maxLength)
+	length := r.g.fake.IntRange(minLength, maxLength)
 	result := make([]rune, length)
 	for i := 0; i < length; i++ {
-		c, _ := gofakeit.Weighted(categories, weights)
+		c, _ := r.g.fake.Weighted(categories, weights)
 
 		switch c {
 		case 0:
-			n := gofakeit.IntRange(0, len(lowerChars)-1)
+			n := r.g.fake.IntRange(0, len(lowerChars)-1)
 			result[i] = rune(lowerChars[n])
 		case 1:
-			n := gofakeit.IntRange(0, len(upperChars)-1)
+			n := r.g.fake.IntRange(0, len(upperChars)-1)
 			result[i] = rune(upperChars[n])
 		case 2:
-			n := gofakeit.IntRange(0, len(numericChars)-1)
+			n := r.g.fake.IntRange(0, len(numericChars)-1)
 			result[i] = rune(numericChars[n])
 		case 3:
 			result[i] = ' '
 		case 4:
-			n := gofakeit.IntRange(0, len(specialChars)-1)
+			n := r.g.fake.IntRange(0, len(specialChars)-1)
 			result[i] = rune(specialChars[n])
 		}
 	}
 	return string(result), nil
 }
 
-func fakeFormat(s *schema.Schema) (interface{}, error) {
+func fakeFormat(r *Request) (interface{}, error) {
+	s := r.Schema
 	switch s.Format {
 	case "date":
-		return date().Format("2006-01-02"), nil
+		return date(r).Format("2006-01-02"), nil
 	case "date-time":
-		return date().Format(time.RFC3339), nil
+		return date(r).Format(time.RFC3339), nil
 	case "time":
-		return date().Format("15:04:05Z07:00"), nil
+		return date(r).Format("15:04:05Z07:00"), nil
 	case "password":
-		return gofakeit.Generate("{password}"), nil
+		return r.g.fake.Generate("{password}"), nil
 	case "email":
-		return gofakeit.Generate("{email}"), nil
+		return r.g.fake.Generate("{email}"), nil
 	case "uuid":
-		return gofakeit.Generate("{uuid}"), nil
+		return r.g.fake.Generate("{uuid}"), nil
 	case "uri":
-		return gofakeit.Generate("{url}"), nil
+		return r.g.fake.Generate("{url}"), nil
 	case "hostname":
-		return gofakeit.Generate("{domainname}"), nil
+		return r.g.fake.Generate("{domainname}"), nil
 	case "ipv4":
-		return gofakeit.Generate("{ipv4address}"), nil
+		return r.g.fake.Generate("{ipv4address}"), nil
 	case "ipv6":
-		return gofakeit.Generate("{ipv6address}"), nil
+		return r.g.fake.Generate("{ipv6address}"), nil
 	default:
-		return gofakeit.Generate(s.Format), nil
+		return r.g.fake.Generate(s.Format), nil
 	}
 }
 
@@ -111,13 +109,13 @@ var maxDayInMonth = []int{
 }
 
 // gofakeit uses year range between 1900 and now
-func date() time.Time {
-	year := gofakeit.Number(1970, 2040)
-	month := gofakeit.Number(1, 12)
-	day := gofakeit.Number(1, maxDayInMonth[month-1])
-	hour := gofakeit.Number(0, 23)
-	minute := gofakeit.Number(0, 59)
-	second := gofakeit.Number(0, 59)
-	nanosecond := gofakeit.Number(0, 999999999)
+func date(r *Request) time.Time {
+	year := r.g.fake.Number(1970, 2040)
+	month := r.g.fake.Number(1, 12)
+	day := r.g.fake.Number(1, maxDayInMonth[month-1])
+	hour := r.g.fake.Number(0, 23)
+	minute := r.g.fake.Number(0, 59)
+	second := r.g.fake.Number(0, 59)
+	nanosecond := r.g.fake.Number(0, 999999999)
 	return time.Date(year, time.Month(month), day, hour, minute, second, nanosecond, time.UTC)
 }
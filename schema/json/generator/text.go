@@ -2,8 +2,6 @@ package generator
 
 import (
 	"math"
-
-	"github.com/brianvoe/gofakeit/v6"
 )
 
 func textNodes() []*Node {
@@ -39,7 +37,7 @@ func fakeDescription(r *Request) (interface{}, error) {
 		maxWords := int(math.Floor(float64(maxLength) / float64(avgWordLength)))
 
 		for ; maxWords > 0; maxWords-- {
-			v := gofakeit.Sentence(maxWords)
+			v := r.g.fake.Sentence(maxWords)
 			if len(v) >= minLength && len(v) <= maxLength {
 				return v, nil
 			}
@@ -47,7 +45,7 @@ func fakeDescription(r *Request) (interface{}, error) {
 
 		return nil, NotSupported
 	}
-	return gofakeit.Sentence(wordCount), nil
+	return r.g.fake.Sentence(wordCount), nil
 }
 
 var (
@@ -91,6 +89,6 @@ func fakeCategory(r *Request) (interface{}, error) {
 		}
 	}
 
-	index := gofakeit.Number(0, len(pool)-1)
+	index := r.g.fake.Number(0, len(pool)-1)
 	return pool[index], nil
 }
@@ -17,13 +17,15 @@ const RootName = "root"
 type fakeFunc func() (any, error)
 
 type Node struct {
-	Name       string                        `json:"name"`
-	Attributes []string                      `json:"attributes,omitempty"`
-	Weight     float64                       `json:"weight,omitempty"`
-	DependsOn  []string                      `json:"dependsOn,omitempty"`
-	Children   []*Node                       `json:"children,omitempty"`
-	Custom     bool                          `json:"custom,omitempty"`
-	Fake       func(r *Request) (any, error) `json:"-"`
+	Name       string   `json:"name"`
+	Attributes []string `json:"attributes,omitempty"`
+	Weight     float64  `json:"weight,omitempty"`
+	DependsOn  []string `json:"dependsOn,omitempty"`
+	Children   []*Node  `json:"children,omitempty"`
+	Custom     bool     `json:"custom,omitempty"`
+	// Locales lists the supported locales if the node uses localized data
+	Locales []string                      `json:"locales,omitempty"`
+	Fake    func(r *Request) (any, error) `json:"-"`
 }
 
 func NewNode(name string) *Node {
@@ -1,7 +1,5 @@
 package generator
 
-import "github.com/brianvoe/gofakeit/v6"
-
 func newUrlNode() *Node {
 	return &Node{Name: "url", Fake: fakeUrl}
 }
@@ -10,10 +8,10 @@ func newUriNode() *Node {
 	return &Node{Name: "uri", Fake: fakeUrl}
 }
 
-func fakeUrl(_ *Request) (interface{}, error) {
-	return gofakeit.URL(), nil
+func fakeUrl(r *Request) (interface{}, error) {
+	return r.g.fake.URL(), nil
 }
 
-func fakeWebsite(_ *Request) (interface{}, error) {
-	return gofakeit.DomainName(), nil
+func fakeWebsite(r *Request) (interface{}, error) {
+	return r.g.fake.DomainName(), nil
 }
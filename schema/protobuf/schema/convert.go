@@ -0,0 +1,121 @@
+package schema
+
+import (
+	"math"
+	json "mokapi/schema/json/schema"
+	"mokapi/sortedmap"
+)
+
+type JsonSchemaConverter struct {
+	history map[*Message]*json.Schema
+}
+
+// ConvertToJsonSchema converts a message to a JSON schema using the
+// JSON names of the fields. Each oneof is converted to a oneOf, so that
+// only one of its fields is set.
+func ConvertToJsonSchema(m *Message) *json.Schema {
+	c := &JsonSchemaConverter{history: make(map[*Message]*json.Schema)}
+	return c.Convert(m)
+}
+
+func (c *JsonSchemaConverter) Convert(m *Message) *json.Schema {
+	if js, ok := c.history[m]; ok {
+		return js
+	}
+
+	js := &json.Schema{
+		Type:  json.Types{"object"},
+		Title: m.FullName,
+	}
+	c.history[m] = js
+
+	oneofs := map[string]*json.Schema{}
+	for _, f := range m.Fields {
+		fs := c.convertField(f)
+		if f.Oneof == "" {
+			if js.Properties == nil {
+				js.Properties = &json.Schemas{LinkedHashMap: sortedmap.LinkedHashMap[string, *json.Schema]{}}
+			}
+			js.Properties.Set(f.JsonName, fs)
+			if f.Label == "required" {
+				js.Required = append(js.Required, f.JsonName)
+			}
+			continue
+		}
+
+		oneof, ok := oneofs[f.Oneof]
+		if !ok {
+			oneof = &json.Schema{}
+			oneofs[f.Oneof] = oneof
+		}
+		option := &json.Schema{
+			Type:       json.Types{"object"},
+			Properties: &json.Schemas{LinkedHashMap: sortedmap.LinkedHashMap[string, *json.Schema]{}},
+			Required:   []string{f.JsonName},
+		}
+		option.Properties.Set(f.JsonName, fs)
+		oneof.OneOf = append(oneof.OneOf, option)
+	}
+
+	switch len(m.Oneofs) {
+	case 0:
+	case 1:
+		js.OneOf = oneofs[m.Oneofs[0]].OneOf
+	default:
+		for _, name := range m.Oneofs {
+			if oneof, ok := oneofs[name]; ok {
+				js.AllOf = append(js.AllOf, oneof)
+			}
+		}
+	}
+
+	return js
+}
+
+func (c *JsonSchemaConverter) convertField(f *Field) *json.Schema {
+	value := c.convertType(f)
+	switch {
+	case f.IsMap():
+		return &json.Schema{Type: json.Types{"object"}, AdditionalProperties: value}
+	case f.IsRepeated():
+		return &json.Schema{Type: json.Types{"array"}, Items: value}
+	default:
+		return value
+	}
+}
+
+func (c *JsonSchemaConverter) convertType(f *Field) *json.Schema {
+	if f.Message != nil {
+		return c.Convert(f.Message)
+	}
+	if f.Enum != nil {
+		js := &json.Schema{Type: json.Types{"string"}, Title: f.Enum.FullName}
+		for _, name := range f.Enum.Names() {
+			js.Enum = append(js.Enum, name)
+		}
+		return js
+	}
+
+	switch f.Type {
+	case "double", "float":
+		return &json.Schema{Type: json.Types{"number"}, Format: f.Type}
+	case "int32", "sint32", "sfixed32":
+		return &json.Schema{Type: json.Types{"integer"}, Format: "int32"}
+	case "int64", "sint64", "sfixed64":
+		return &json.Schema{Type: json.Types{"integer"}, Format: "int64"}
+	case "uint32", "fixed32":
+		return &json.Schema{Type: json.Types{"integer"}, Minimum: toFloatPtr(0), Maximum: toFloatPtr(math.MaxUint32)}
+	case "uint64", "fixed64":
+		return &json.Schema{Type: json.Types{"integer"}, Minimum: toFloatPtr(0), Format: "int64"}
+	case "bool":
+		return &json.Schema{Type: json.Types{"boolean"}}
+	case "bytes":
+		return &json.Schema{Type: json.Types{"string"}, ContentEncoding: "base64"}
+	default:
+		return &json.Schema{Type: json.Types{"string"}}
+	}
+}
+
+func toFloatPtr(f float64) *float64 {
+	return &f
+}
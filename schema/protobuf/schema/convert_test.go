@@ -0,0 +1,96 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"mokapi/schema/json/generator"
+	"mokapi/schema/protobuf/schema"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertToJsonSchema(t *testing.T) {
+	testcases := []struct {
+		name  string
+		input string
+		test  func(t *testing.T, f *schema.File)
+	}{
+		{
+			name: "scalars",
+			input: `
+syntax = "proto3";
+message Foo {
+  string first_name = 1;
+  int32 i = 2;
+  uint64 u = 3;
+  double d = 4;
+  bool b = 5;
+  bytes data = 6;
+}`,
+			test: func(t *testing.T, f *schema.File) {
+				js := schema.ConvertToJsonSchema(f.Message("Foo"))
+				b, err := json.Marshal(js)
+				require.NoError(t, err)
+				require.Equal(t, `{"type":"object","properties":{"firstName":{"type":"string"},"i":{"type":"integer","format":"int32"},"u":{"type":"integer","minimum":0,"format":"int64"},"d":{"type":"number","format":"double"},"b":{"type":"boolean"},"data":{"type":"string","contentEncoding":"base64"}},"title":"Foo"}`, string(b))
+			},
+		},
+		{
+			name: "repeated, map and enum",
+			input: `
+syntax = "proto3";
+message Foo {
+  repeated string tags = 1;
+  map<string, Status> states = 2;
+}
+enum Status { OPEN = 0; CLOSED = 1; }`,
+			test: func(t *testing.T, f *schema.File) {
+				js := schema.ConvertToJsonSchema(f.Message("Foo"))
+				b, err := json.Marshal(js)
+				require.NoError(t, err)
+				require.Equal(t, `{"type":"object","properties":{"tags":{"type":"array","items":{"type":"string"}},"states":{"type":"object","additionalProperties":{"type":"string","enum":["OPEN","CLOSED"],"title":"Status"}}},"title":"Foo"}`, string(b))
+			},
+		},
+		{
+			name: "recursive message",
+			input: `
+syntax = "proto3";
+message Node { string name = 1; repeated Node children = 2; }`,
+			test: func(t *testing.T, f *schema.File) {
+				js := schema.ConvertToJsonSchema(f.Message("Node"))
+				require.Equal(t, js, js.Properties.Get("children").Items)
+			},
+		},
+		{
+			name: "generated data of oneof can be marshalled",
+			input: `
+syntax = "proto3";
+message Order {
+  string id = 1;
+  oneof payment {
+    string card = 2;
+    string iban = 3;
+  }
+  oneof delivery {
+    string address = 4;
+    string store = 5;
+  }
+}`,
+			test: func(t *testing.T, f *schema.File) {
+				m := f.Message("Order")
+				js := schema.ConvertToJsonSchema(m)
+				for i := 0; i < 10; i++ {
+					v, err := generator.New(&generator.Request{Schema: js})
+					require.NoError(t, err)
+					_, err = m.Marshal(v)
+					require.NoError(t, err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, parseTestProto(t, tc.input))
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package schema
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"mokapi/sortedmap"
+	"slices"
+)
+
+// Marshal validates the value against the message and returns its
+// protobuf binary encoding
+func (m *Message) Marshal(v any) ([]byte, error) {
+	p := Parser{Message: m}
+	normalized, err := p.parseFromInterface(v, m)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &writer{}
+	if err = w.writeMessage(normalized, m); err != nil {
+		return nil, err
+	}
+	return w.b, nil
+}
+
+type writer struct {
+	b []byte
+}
+
+func (w *writer) writeMessage(values *sortedmap.LinkedHashMap[string, any], m *Message) error {
+	for _, f := range m.Fields {
+		v, ok := values.Get(f.JsonName)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case f.IsMap():
+			entries := v.(map[string]any)
+			keys := make([]string, 0, len(entries))
+			for k := range entries {
+				keys = append(keys, k)
+			}
+			// deterministic output
+			slices.Sort(keys)
+			for _, k := range keys {
+				entry := &writer{}
+				if err := entry.writeMapKey(k, f.MapKey); err != nil {
+					return err
+				}
+				entry.writeTag(2, scalarWireType(f))
+				if err := entry.writeValue(entries[k], f); err != nil {
+					return err
+				}
+				w.writeTag(f.Number, wireLen)
+				w.writeBytes(entry.b)
+			}
+		case f.IsRepeated():
+			list := v.([]any)
+			if len(list) == 0 {
+				continue
+			}
+			if isPackable(f) && f.isPacked(m) {
+				packed := &writer{}
+				for _, item := range list {
+					if err := packed.writeValue(item, f); err != nil {
+						return err
+					}
+				}
+				w.writeTag(f.Number, wireLen)
+				w.writeBytes(packed.b)
+				continue
+			}
+			for _, item := range list {
+				w.writeTag(f.Number, scalarWireType(f))
+				if err := w.writeValue(item, f); err != nil {
+					return err
+				}
+			}
+		default:
+			if !f.hasPresence(m) && isDefault(v) {
+				continue
+			}
+			w.writeTag(f.Number, scalarWireType(f))
+			if err := w.writeValue(v, f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *writer) writeMapKey(k string, keyType string) error {
+	f := &Field{Type: keyType}
+	var v any = k
+	var err error
+	switch keyType {
+	case "string":
+	case "bool":
+		v = k == "true"
+	default:
+		v, err = (&Parser{}).parseValue(k, f)
+		if err != nil {
+			return err
+		}
+	}
+	w.writeTag(1, scalarWireType(f))
+	return w.writeValue(v, f)
+}
+
+func (w *writer) writeValue(v any, f *Field) error {
+	if f.Message != nil {
+		nested := &writer{}
+		if err := nested.writeMessage(v.(*sortedmap.LinkedHashMap[string, any]), f.Message); err != nil {
+			return err
+		}
+		w.writeBytes(nested.b)
+		return nil
+	}
+	if f.Enum != nil {
+		var n int64
+		switch e := v.(type) {
+		case string:
+			ev, _ := f.Enum.Value(e)
+			n = int64(ev.Number)
+		case int64:
+			n = e
+		}
+		w.writeVarint(uint64(n))
+		return nil
+	}
+
+	switch f.Type {
+	case "int32", "int64":
+		w.writeVarint(uint64(v.(int64)))
+	case "uint32", "uint64":
+		w.writeVarint(v.(uint64))
+	case "sint32", "sint64":
+		w.b = binary.AppendVarint(w.b, v.(int64))
+	case "bool":
+		if v.(bool) {
+			w.writeVarint(1)
+		} else {
+			w.writeVarint(0)
+		}
+	case "fixed32":
+		w.b = binary.LittleEndian.AppendUint32(w.b, uint32(v.(uint64)))
+	case "sfixed32":
+		w.b = binary.LittleEndian.AppendUint32(w.b, uint32(int32(v.(int64))))
+	case "float":
+		w.b = binary.LittleEndian.AppendUint32(w.b, math.Float32bits(v.(float32)))
+	case "fixed64":
+		w.b = binary.LittleEndian.AppendUint64(w.b, v.(uint64))
+	case "sfixed64":
+		w.b = binary.LittleEndian.AppendUint64(w.b, uint64(v.(int64)))
+	case "double":
+		w.b = binary.LittleEndian.AppendUint64(w.b, math.Float64bits(v.(float64)))
+	case "string":
+		w.writeBytes([]byte(v.(string)))
+	case "bytes":
+		w.writeBytes(v.([]byte))
+	default:
+		return fmt.Errorf("unsupported type '%v'", f.Type)
+	}
+	return nil
+}
+
+func (w *writer) writeTag(number int, wireType int) {
+	w.writeVarint(uint64(number)<<3 | uint64(wireType))
+}
+
+func (w *writer) writeVarint(n uint64) {
+	w.b = binary.AppendUvarint(w.b, n)
+}
+
+func (w *writer) writeBytes(b []byte) {
+	w.writeVarint(uint64(len(b)))
+	w.b = append(w.b, b...)
+}
+
+func isDefault(v any) bool {
+	switch val := v.(type) {
+	case int64:
+		return val == 0
+	case uint64:
+		return val == 0
+	case float32:
+		return val == 0 && !math.Signbit(float64(val))
+	case float64:
+		return val == 0 && !math.Signbit(val)
+	case bool:
+		return !val
+	case string:
+		return val == ""
+	case []byte:
+		return len(val) == 0
+	default:
+		return false
+	}
+}
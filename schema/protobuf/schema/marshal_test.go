@@ -0,0 +1,116 @@
+package schema_test
+
+import (
+	"mokapi/schema/protobuf/schema"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage_Marshal(t *testing.T) {
+	testcases := []struct {
+		name    string
+		message string
+		data    any
+		test    func(t *testing.T, b []byte, err error)
+	}{
+		{
+			name:    "varint",
+			message: "Test1",
+			data:    map[string]any{"a": 150},
+			test: func(t *testing.T, b []byte, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []byte{0x08, 0x96, 0x01}, b)
+			},
+		},
+		{
+			name:    "negative int32",
+			message: "Test1",
+			data:    map[string]any{"a": -1},
+			test: func(t *testing.T, b []byte, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []byte{0x08, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, b)
+			},
+		},
+		{
+			name:    "default value is not written",
+			message: "Test1",
+			data:    map[string]any{"a": 0},
+			test: func(t *testing.T, b []byte, err error) {
+				require.NoError(t, err)
+				require.Empty(t, b)
+			},
+		},
+		{
+			name:    "embedded message",
+			message: "Test3",
+			data:    map[string]any{"c": map[string]any{"a": 150}},
+			test: func(t *testing.T, b []byte, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []byte{0x1a, 0x03, 0x08, 0x96, 0x01}, b)
+			},
+		},
+		{
+			name:    "packed repeated",
+			message: "Test4",
+			data:    map[string]any{"d": []any{3, 270, 86942}},
+			test: func(t *testing.T, b []byte, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []byte{0x22, 0x06, 0x03, 0x8e, 0x02, 0x9e, 0xa7, 0x05}, b)
+			},
+		},
+		{
+			name:    "sint32",
+			message: "Scalars",
+			data:    map[string]any{"s": -2},
+			test: func(t *testing.T, b []byte, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []byte{0x08, 0x03}, b)
+			},
+		},
+		{
+			name:    "map",
+			message: "Order",
+			data:    map[string]any{"quantities": map[string]any{"a": 2}},
+			test: func(t *testing.T, b []byte, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []byte{0x12, 0x05, 0x0a, 0x01, 0x61, 0x10, 0x02}, b)
+			},
+		},
+		{
+			name:    "invalid value",
+			message: "Test2",
+			data:    map[string]any{"b": 12},
+			test: func(t *testing.T, b []byte, err error) {
+				require.EqualError(t, err, "message 'test.Test2': field 'b': expected string, got int")
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := parseTestProto(t, testProto)
+			b, err := f.Message(tc.message).Marshal(tc.data)
+			tc.test(t, b, err)
+		})
+	}
+}
+
+func TestMessage_Marshal_RoundTrip(t *testing.T) {
+	f := parseTestProto(t, testProto)
+	m := f.Message("Scalars")
+	b, err := m.Marshal(map[string]any{
+		"s":      -300,
+		"flag":   true,
+		"amount": 0.25,
+		"f":      4294967295,
+		"data":   []byte("foo"),
+		"status": "CLOSED",
+		"big":    -9007199254740993,
+	})
+	require.NoError(t, err)
+
+	v, err := (&schema.Parser{Message: m}).Parse(b)
+	require.NoError(t, err)
+	requireJson(t, `{"s":-300,"flag":true,"amount":0.25,"f":4294967295,"data":"Zm9v","status":"CLOSED","big":-9007199254740993}`, v)
+}
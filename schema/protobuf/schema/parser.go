@@ -0,0 +1,656 @@
+package schema
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"mokapi/sortedmap"
+	"reflect"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+)
+
+const (
+	wireVarint = 0
+	wireI64    = 1
+	wireLen    = 2
+	wireI32    = 5
+)
+
+// Parser decodes protobuf binary data or validates a value against a
+// message. Messages are returned as *sortedmap.LinkedHashMap using the
+// JSON names of the fields, enums as their names and bytes as []byte.
+type Parser struct {
+	Message *Message
+}
+
+func (p *Parser) Parse(data any) (any, error) {
+	if p.Message == nil {
+		return nil, fmt.Errorf("no protobuf message defined")
+	}
+	if b, ok := data.([]byte); ok {
+		return p.parseFromByte(b)
+	}
+	return p.parseFromInterface(data, p.Message)
+}
+
+func (p *Parser) parseFromByte(b []byte) (any, error) {
+	// A message cannot start with 0 because field number 0 is invalid.
+	// A leading 0 is the magic byte of the Confluent wire format
+	// followed by a 4-byte schema ID and the message indexes.
+	if len(b) > 0 && b[0] == 0 {
+		if len(b) < 5 {
+			return nil, fmt.Errorf("invalid wire format: missing schema ID")
+		}
+		b = b[5:]
+		count, n := binary.Varint(b)
+		if n <= 0 {
+			return nil, fmt.Errorf("invalid wire format: missing message indexes")
+		}
+		b = b[n:]
+		for i := int64(0); i < count; i++ {
+			_, n = binary.Varint(b)
+			if n <= 0 {
+				return nil, fmt.Errorf("invalid wire format: missing message indexes")
+			}
+			b = b[n:]
+		}
+	}
+	return decodeMessage(b, p.Message)
+}
+
+func decodeMessage(b []byte, m *Message) (*sortedmap.LinkedHashMap[string, any], error) {
+	values := map[*Field]any{}
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, fmt.Errorf("message '%v': invalid field tag", m.FullName)
+		}
+		b = b[n:]
+		number, wireType := int(tag>>3), int(tag&7)
+
+		f := m.fieldByNumber(number)
+		var raw []byte
+		var err error
+		raw, b, err = readField(b, wireType)
+		if err != nil {
+			if f == nil {
+				return nil, fmt.Errorf("message '%v': field number %v: %w", m.FullName, number, err)
+			}
+			return nil, fmt.Errorf("message '%v': field '%v': %w", m.FullName, f.Name, err)
+		}
+		if f == nil {
+			// unknown fields, for example of a newer schema version, are skipped
+			continue
+		}
+
+		switch {
+		case f.IsMap():
+			if wireType != wireLen {
+				return nil, fmt.Errorf("message '%v': field '%v': invalid wire type %v", m.FullName, f.Name, wireType)
+			}
+			entries, _ := values[f].(map[string]any)
+			if entries == nil {
+				entries = map[string]any{}
+				values[f] = entries
+			}
+			key, value, err := decodeMapEntry(raw, f)
+			if err != nil {
+				return nil, fmt.Errorf("message '%v': field '%v': %w", m.FullName, f.Name, err)
+			}
+			entries[key] = value
+		case f.IsRepeated():
+			list, _ := values[f].([]any)
+			if wireType == wireLen && isPackable(f) {
+				// packed repeated field
+				for len(raw) > 0 {
+					var item []byte
+					item, raw, err = readField(raw, scalarWireType(f))
+					if err != nil {
+						return nil, fmt.Errorf("message '%v': field '%v': %w", m.FullName, f.Name, err)
+					}
+					v, err := decodeValue(item, scalarWireType(f), f)
+					if err != nil {
+						return nil, fmt.Errorf("message '%v': field '%v': %w", m.FullName, f.Name, err)
+					}
+					list = append(list, v)
+				}
+			} else {
+				v, err := decodeValue(raw, wireType, f)
+				if err != nil {
+					return nil, fmt.Errorf("message '%v': field '%v': %w", m.FullName, f.Name, err)
+				}
+				list = append(list, v)
+			}
+			values[f] = list
+		default:
+			v, err := decodeValue(raw, wireType, f)
+			if err != nil {
+				return nil, fmt.Errorf("message '%v': field '%v': %w", m.FullName, f.Name, err)
+			}
+			if f.Oneof != "" {
+				// the last field of a oneof wins
+				for other := range values {
+					if other.Oneof == f.Oneof {
+						delete(values, other)
+					}
+				}
+			}
+			values[f] = v
+		}
+	}
+
+	result := sortedmap.NewLinkedHashMap()
+	for _, f := range m.Fields {
+		if v, ok := values[f]; ok {
+			result.Set(f.JsonName, v)
+		} else if f.Label == "required" {
+			return nil, fmt.Errorf("message '%v': missing required field '%v'", m.FullName, f.Name)
+		}
+	}
+	return result, nil
+}
+
+func decodeMapEntry(b []byte, f *Field) (string, any, error) {
+	keyField := &Field{Name: "key", Type: f.MapKey}
+	valueField := &Field{Name: "value", Type: f.Type, Message: f.Message, Enum: f.Enum}
+
+	var key, value any
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return "", nil, fmt.Errorf("invalid map entry")
+		}
+		b = b[n:]
+		wireType := int(tag & 7)
+
+		var raw []byte
+		var err error
+		raw, b, err = readField(b, wireType)
+		if err != nil {
+			return "", nil, err
+		}
+		switch tag >> 3 {
+		case 1:
+			key, err = decodeValue(raw, wireType, keyField)
+		case 2:
+			value, err = decodeValue(raw, wireType, valueField)
+		default:
+			err = fmt.Errorf("invalid map entry field number %v", tag>>3)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	if key == nil {
+		key = defaultValue(keyField)
+	}
+	if value == nil {
+		value = defaultValue(valueField)
+	}
+	return fmt.Sprintf("%v", key), value, nil
+}
+
+// readField returns the raw value of a field and the remaining bytes
+func readField(b []byte, wireType int) ([]byte, []byte, error) {
+	switch wireType {
+	case wireVarint:
+		_, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, nil, fmt.Errorf("invalid varint")
+		}
+		return b[:n], b[n:], nil
+	case wireI64:
+		if len(b) < 8 {
+			return nil, nil, fmt.Errorf("unexpected end of data")
+		}
+		return b[:8], b[8:], nil
+	case wireI32:
+		if len(b) < 4 {
+			return nil, nil, fmt.Errorf("unexpected end of data")
+		}
+		return b[:4], b[4:], nil
+	case wireLen:
+		l, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, nil, fmt.Errorf("invalid length")
+		}
+		b = b[n:]
+		if uint64(len(b)) < l {
+			return nil, nil, fmt.Errorf("unexpected end of data")
+		}
+		return b[:l], b[l:], nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported wire type %v", wireType)
+	}
+}
+
+func decodeValue(raw []byte, wireType int, f *Field) (any, error) {
+	expected := scalarWireType(f)
+	if wireType != expected {
+		return nil, fmt.Errorf("invalid wire type %v, expected %v", wireType, expected)
+	}
+
+	if f.Message != nil {
+		return decodeMessage(raw, f.Message)
+	}
+	if f.Enum != nil {
+		n, _ := binary.Uvarint(raw)
+		if v, ok := f.Enum.ValueByNumber(int32(n)); ok {
+			return v.Name, nil
+		}
+		// proto3 enums are open and may contain unknown values
+		return int64(int32(n)), nil
+	}
+
+	switch f.Type {
+	case "int32":
+		n, _ := binary.Uvarint(raw)
+		return int64(int32(n)), nil
+	case "int64":
+		n, _ := binary.Uvarint(raw)
+		return int64(n), nil
+	case "uint32", "uint64":
+		n, _ := binary.Uvarint(raw)
+		return n, nil
+	case "sint32", "sint64":
+		n, _ := binary.Varint(raw)
+		return n, nil
+	case "bool":
+		n, _ := binary.Uvarint(raw)
+		return n != 0, nil
+	case "fixed32":
+		return uint64(binary.LittleEndian.Uint32(raw)), nil
+	case "sfixed32":
+		return int64(int32(binary.LittleEndian.Uint32(raw))), nil
+	case "float":
+		return math.Float32frombits(binary.LittleEndian.Uint32(raw)), nil
+	case "fixed64":
+		return binary.LittleEndian.Uint64(raw), nil
+	case "sfixed64":
+		return int64(binary.LittleEndian.Uint64(raw)), nil
+	case "double":
+		return math.Float64frombits(binary.LittleEndian.Uint64(raw)), nil
+	case "string":
+		if !utf8.Valid(raw) {
+			return nil, fmt.Errorf("invalid UTF-8 string")
+		}
+		return string(raw), nil
+	case "bytes":
+		return slices.Clone(raw), nil
+	default:
+		return nil, fmt.Errorf("unsupported type '%v'", f.Type)
+	}
+}
+
+func (p *Parser) parseFromInterface(data any, m *Message) (*sortedmap.LinkedHashMap[string, any], error) {
+	input, err := toMap(data)
+	if err != nil {
+		return nil, fmt.Errorf("message '%v': %w", m.FullName, err)
+	}
+
+	result := sortedmap.NewLinkedHashMap()
+	oneofs := map[string]string{}
+	for _, f := range m.Fields {
+		v, ok := input[f.JsonName]
+		name := f.JsonName
+		if !ok {
+			v, ok = input[f.Name]
+			name = f.Name
+		}
+		if !ok || v == nil {
+			if f.Label == "required" {
+				return nil, fmt.Errorf("message '%v': missing required field '%v'", m.FullName, f.Name)
+			}
+			continue
+		}
+		delete(input, name)
+
+		if f.Oneof != "" {
+			if other, ok := oneofs[f.Oneof]; ok {
+				return nil, fmt.Errorf("message '%v': oneof '%v' has more than one field set: %v, %v", m.FullName, f.Oneof, other, f.Name)
+			}
+			oneofs[f.Oneof] = f.Name
+		}
+
+		v, err = p.parseField(v, f)
+		if err != nil {
+			return nil, fmt.Errorf("message '%v': field '%v': %w", m.FullName, f.Name, err)
+		}
+		result.Set(f.JsonName, v)
+	}
+
+	if len(input) > 0 {
+		var unknown []string
+		for name := range input {
+			unknown = append(unknown, name)
+		}
+		slices.Sort(unknown)
+		return nil, fmt.Errorf("message '%v': unknown fields %v", m.FullName, unknown)
+	}
+
+	return result, nil
+}
+
+func (p *Parser) parseField(v any, f *Field) (any, error) {
+	switch {
+	case f.IsMap():
+		entries, err := toMap(v)
+		if err != nil {
+			return nil, err
+		}
+		keyField := &Field{Type: f.MapKey}
+		result := map[string]any{}
+		for k, val := range entries {
+			if err = validateMapKey(k, keyField); err != nil {
+				return nil, fmt.Errorf("invalid map key '%v': %w", k, err)
+			}
+			if result[k], err = p.parseValue(val, f); err != nil {
+				return nil, fmt.Errorf("map key '%v': %w", k, err)
+			}
+		}
+		return result, nil
+	case f.IsRepeated():
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, fmt.Errorf("expected array, got %v", toTypeName(v))
+		}
+		var list []any
+		for i := 0; i < rv.Len(); i++ {
+			item, err := p.parseValue(rv.Index(i).Interface(), f)
+			if err != nil {
+				return nil, fmt.Errorf("index %v: %w", i, err)
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	default:
+		return p.parseValue(v, f)
+	}
+}
+
+func (p *Parser) parseValue(v any, f *Field) (any, error) {
+	if f.Message != nil {
+		return p.parseFromInterface(v, f.Message)
+	}
+	if f.Enum != nil {
+		if s, ok := v.(string); ok {
+			if _, found := f.Enum.Value(s); found {
+				return s, nil
+			}
+			return nil, fmt.Errorf("value '%v' is not one of the enum values %v", s, f.Enum.Names())
+		}
+		n, err := toInt64(v, math.MinInt32, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+		if ev, ok := f.Enum.ValueByNumber(int32(n)); ok {
+			return ev.Name, nil
+		}
+		return n, nil
+	}
+
+	switch f.Type {
+	case "int32", "sint32", "sfixed32":
+		return toInt64(v, math.MinInt32, math.MaxInt32)
+	case "int64", "sint64", "sfixed64":
+		return toInt64(v, math.MinInt64, math.MaxInt64)
+	case "uint32", "fixed32":
+		return toUint64(v, math.MaxUint32)
+	case "uint64", "fixed64":
+		return toUint64(v, math.MaxUint64)
+	case "float":
+		f64, err := toFloat64(v)
+		if err != nil {
+			return nil, err
+		}
+		if math.Abs(f64) > math.MaxFloat32 && !math.IsInf(f64, 0) {
+			return nil, fmt.Errorf("value %v out of range for float", v)
+		}
+		return float32(f64), nil
+	case "double":
+		return toFloat64(v)
+	case "bool":
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("expected bool, got %v", toTypeName(v))
+	case "string":
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("expected string, got %v", toTypeName(v))
+	case "bytes":
+		switch b := v.(type) {
+		case []byte:
+			return b, nil
+		case string:
+			// JSON represents bytes as base64
+			if decoded, err := base64.StdEncoding.DecodeString(b); err == nil {
+				return decoded, nil
+			}
+			return []byte(b), nil
+		}
+		return nil, fmt.Errorf("expected bytes, got %v", toTypeName(v))
+	default:
+		return nil, fmt.Errorf("unsupported type '%v'", f.Type)
+	}
+}
+
+// validateMapKey validates a map key which is always a string in JSON
+func validateMapKey(k string, f *Field) error {
+	switch f.Type {
+	case "string":
+		return nil
+	case "bool":
+		if k != "true" && k != "false" {
+			return fmt.Errorf("expected bool")
+		}
+		return nil
+	default:
+		_, err := (&Parser{}).parseValue(k, f)
+		return err
+	}
+}
+
+func toMap(v any) (map[string]any, error) {
+	switch m := v.(type) {
+	case map[string]any:
+		return maps.Clone(m), nil
+	case *sortedmap.LinkedHashMap[string, any]:
+		return m.ToMap(), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Map && rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected object, got %v", toTypeName(v))
+	}
+	// e.g. a struct or map[string]string
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	err = json.Unmarshal(b, &m)
+	return m, err
+}
+
+func toInt64(v any, min, max int64) (int64, error) {
+	var n int64
+	switch i := v.(type) {
+	case int:
+		n = int64(i)
+	case int8:
+		n = int64(i)
+	case int16:
+		n = int64(i)
+	case int32:
+		n = int64(i)
+	case int64:
+		n = i
+	case uint8:
+		n = int64(i)
+	case uint16:
+		n = int64(i)
+	case uint32:
+		n = int64(i)
+	case uint64:
+		if i > math.MaxInt64 {
+			return 0, fmt.Errorf("value %v out of range", v)
+		}
+		n = int64(i)
+	case float32:
+		return toInt64(float64(i), min, max)
+	case float64:
+		if i != math.Trunc(i) || i < math.MinInt64 || i > math.MaxInt64 {
+			return 0, fmt.Errorf("expected integer, got %v", v)
+		}
+		n = int64(i)
+	case string:
+		// proto3 JSON represents 64-bit integers as strings
+		var err error
+		n, err = strconv.ParseInt(i, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("expected integer, got string")
+		}
+	default:
+		return 0, fmt.Errorf("expected integer, got %v", toTypeName(v))
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("value %v out of range", v)
+	}
+	return n, nil
+}
+
+func toUint64(v any, max uint64) (uint64, error) {
+	var n uint64
+	switch i := v.(type) {
+	case uint:
+		n = uint64(i)
+	case uint8:
+		n = uint64(i)
+	case uint16:
+		n = uint64(i)
+	case uint32:
+		n = uint64(i)
+	case uint64:
+		n = i
+	case string:
+		var err error
+		n, err = strconv.ParseUint(i, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("expected unsigned integer, got string")
+		}
+	default:
+		signed, err := toInt64(v, 0, math.MaxInt64)
+		if err != nil {
+			return 0, err
+		}
+		n = uint64(signed)
+	}
+	if n > max {
+		return 0, fmt.Errorf("value %v out of range", v)
+	}
+	return n, nil
+}
+
+func toFloat64(v any) (float64, error) {
+	switch f := v.(type) {
+	case float64:
+		return f, nil
+	case float32:
+		return float64(f), nil
+	case string:
+		switch f {
+		case "NaN":
+			return math.NaN(), nil
+		case "Infinity":
+			return math.Inf(1), nil
+		case "-Infinity":
+			return math.Inf(-1), nil
+		}
+		return 0, fmt.Errorf("expected number, got string")
+	default:
+		n, err := toInt64(v, math.MinInt64, math.MaxInt64)
+		if err != nil {
+			return 0, fmt.Errorf("expected number, got %v", toTypeName(v))
+		}
+		return float64(n), nil
+	}
+}
+
+func toTypeName(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case []any:
+		return "array"
+	case map[string]any, *sortedmap.LinkedHashMap[string, any]:
+		return "object"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func isPackable(f *Field) bool {
+	return f.Message == nil && f.Type != "string" && f.Type != "bytes"
+}
+
+func scalarWireType(f *Field) int {
+	if f.Message != nil {
+		return wireLen
+	}
+	if f.Enum != nil {
+		return wireVarint
+	}
+	switch f.Type {
+	case "double", "fixed64", "sfixed64":
+		return wireI64
+	case "float", "fixed32", "sfixed32":
+		return wireI32
+	case "string", "bytes":
+		return wireLen
+	default:
+		return wireVarint
+	}
+}
+
+func defaultValue(f *Field) any {
+	if f.Message != nil {
+		return sortedmap.NewLinkedHashMap()
+	}
+	if f.Enum != nil {
+		if len(f.Enum.Values) > 0 {
+			return f.Enum.Values[0].Name
+		}
+		return int64(0)
+	}
+	switch f.Type {
+	case "uint32", "uint64", "fixed32", "fixed64":
+		return uint64(0)
+	case "float":
+		return float32(0)
+	case "double":
+		return float64(0)
+	case "bool":
+		return false
+	case "string":
+		return ""
+	case "bytes":
+		return []byte{}
+	default:
+		return int64(0)
+	}
+}
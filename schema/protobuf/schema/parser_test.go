@@ -0,0 +1,240 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"mokapi/config/dynamic"
+	"mokapi/schema/protobuf/schema"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testProto = `
+syntax = "proto3";
+package test;
+
+message Test1 { int32 a = 1; }
+message Test2 { string b = 2; }
+message Test3 { Test1 c = 3; }
+message Test4 { repeated int32 d = 4; }
+message Scalars {
+  sint32 s = 1;
+  bool flag = 2;
+  double amount = 3;
+  fixed32 f = 4;
+  bytes data = 5;
+  Status status = 6;
+  int64 big = 7;
+}
+message Order {
+  string id = 1;
+  map<string, int32> quantities = 2;
+  oneof payment {
+    string card = 3;
+    string iban = 4;
+  }
+}
+enum Status { OPEN = 0; CLOSED = 1; }
+`
+
+func TestParser_Parse(t *testing.T) {
+	testcases := []struct {
+		name    string
+		message string
+		data    any
+		test    func(t *testing.T, v any, err error)
+	}{
+		{
+			name:    "varint",
+			message: "Test1",
+			data:    []byte{0x08, 0x96, 0x01},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				requireJson(t, `{"a":150}`, v)
+			},
+		},
+		{
+			name:    "string",
+			message: "Test2",
+			data:    []byte{0x12, 0x07, 0x74, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x67},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				requireJson(t, `{"b":"testing"}`, v)
+			},
+		},
+		{
+			name:    "embedded message",
+			message: "Test3",
+			data:    []byte{0x1a, 0x03, 0x08, 0x96, 0x01},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				requireJson(t, `{"c":{"a":150}}`, v)
+			},
+		},
+		{
+			name:    "packed repeated",
+			message: "Test4",
+			data:    []byte{0x22, 0x06, 0x03, 0x8e, 0x02, 0x9e, 0xa7, 0x05},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				requireJson(t, `{"d":[3,270,86942]}`, v)
+			},
+		},
+		{
+			name:    "unpacked repeated",
+			message: "Test4",
+			data:    []byte{0x20, 0x03, 0x20, 0x8e, 0x02},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				requireJson(t, `{"d":[3,270]}`, v)
+			},
+		},
+		{
+			name:    "confluent wire format",
+			message: "Test1",
+			data:    []byte{0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x08, 0x96, 0x01},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				requireJson(t, `{"a":150}`, v)
+			},
+		},
+		{
+			name:    "unknown fields are skipped",
+			message: "Test1",
+			// field 2 varint, field 3 I64, field 4 LEN, field 5 I32 and field 1 = 150
+			data: []byte{
+				0x10, 0x01,
+				0x19, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+				0x22, 0x02, 0x61, 0x62,
+				0x2d, 0x01, 0x02, 0x03, 0x04,
+				0x08, 0x96, 0x01,
+			},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				requireJson(t, `{"a":150}`, v)
+			},
+		},
+		{
+			name:    "truncated unknown field",
+			message: "Test1",
+			data:    []byte{0x22, 0x05, 0x61},
+			test: func(t *testing.T, v any, err error) {
+				require.EqualError(t, err, "message 'test.Test1': field number 4: unexpected end of data")
+			},
+		},
+		{
+			name:    "invalid wire type",
+			message: "Test1",
+			data:    []byte{0x0a, 0x01, 0x01},
+			test: func(t *testing.T, v any, err error) {
+				require.EqualError(t, err, "message 'test.Test1': field 'a': invalid wire type 2, expected 0")
+			},
+		},
+		{
+			name:    "truncated",
+			message: "Test2",
+			data:    []byte{0x12, 0x07, 0x74},
+			test: func(t *testing.T, v any, err error) {
+				require.EqualError(t, err, "message 'test.Test2': field 'b': unexpected end of data")
+			},
+		},
+		{
+			name:    "map and oneof",
+			message: "Order",
+			data:    []byte{0x0a, 0x01, 0x31, 0x12, 0x05, 0x0a, 0x01, 0x61, 0x10, 0x02, 0x1a, 0x01, 0x78, 0x22, 0x01, 0x79},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				// last field of oneof wins
+				requireJson(t, `{"id":"1","quantities":{"a":2},"iban":"y"}`, v)
+			},
+		},
+		{
+			name:    "value",
+			message: "Scalars",
+			data: map[string]any{
+				"s":      -2,
+				"flag":   true,
+				"amount": 12.5,
+				"f":      float64(7),
+				"data":   "Zm9v",
+				"status": "CLOSED",
+				"big":    "9007199254740993",
+			},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				requireJson(t, `{"s":-2,"flag":true,"amount":12.5,"f":7,"data":"Zm9v","status":"CLOSED","big":9007199254740993}`, v)
+			},
+		},
+		{
+			name:    "enum as number",
+			message: "Scalars",
+			data:    map[string]any{"status": 1},
+			test: func(t *testing.T, v any, err error) {
+				require.NoError(t, err)
+				requireJson(t, `{"status":"CLOSED"}`, v)
+			},
+		},
+		{
+			name:    "invalid enum value",
+			message: "Scalars",
+			data:    map[string]any{"status": "FOO"},
+			test: func(t *testing.T, v any, err error) {
+				require.EqualError(t, err, "message 'test.Scalars': field 'status': value 'FOO' is not one of the enum values [OPEN CLOSED]")
+			},
+		},
+		{
+			name:    "int32 out of range",
+			message: "Test1",
+			data:    map[string]any{"a": int64(1) << 40},
+			test: func(t *testing.T, v any, err error) {
+				require.EqualError(t, err, "message 'test.Test1': field 'a': value 1099511627776 out of range")
+			},
+		},
+		{
+			name:    "unknown fields",
+			message: "Test1",
+			data:    map[string]any{"a": 1, "b": 2},
+			test: func(t *testing.T, v any, err error) {
+				require.EqualError(t, err, "message 'test.Test1': unknown fields [b]")
+			},
+		},
+		{
+			name:    "oneof with more than one field",
+			message: "Order",
+			data:    map[string]any{"card": "x", "iban": "y"},
+			test: func(t *testing.T, v any, err error) {
+				require.EqualError(t, err, "message 'test.Order': oneof 'payment' has more than one field set: card, iban")
+			},
+		},
+		{
+			name:    "not an object",
+			message: "Test1",
+			data:    "foo",
+			test: func(t *testing.T, v any, err error) {
+				require.EqualError(t, err, "message 'test.Test1': expected object, got string")
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := parseTestProto(t, testProto)
+			p := &schema.Parser{Message: f.Message(tc.message)}
+			v, err := p.Parse(tc.data)
+			tc.test(t, v, err)
+		})
+	}
+}
+
+func parseTestProto(t *testing.T, s string) *schema.File {
+	f, err := schema.Parse([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, f.Parse(&dynamic.Config{}, nil))
+	return f
+}
+
+func requireJson(t *testing.T, expected string, v any) {
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	require.JSONEq(t, expected, string(b))
+}
@@ -0,0 +1,602 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenSymbol
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+}
+
+type protoParser struct {
+	tokens []token
+	pos    int
+	file   *File
+}
+
+// Parse parses the content of a .proto file. Types are resolved by
+// File.Parse.
+func Parse(b []byte) (*File, error) {
+	tokens, err := tokenize(string(b))
+	if err != nil {
+		return nil, err
+	}
+	p := &protoParser{tokens: tokens, file: &File{Syntax: "proto2"}}
+	if err = p.parseFile(); err != nil {
+		return nil, err
+	}
+	return p.file, nil
+}
+
+func (p *protoParser) parseFile() error {
+	for {
+		t := p.next()
+		switch {
+		case t.kind == tokenEOF:
+			return nil
+		case t.value == ";":
+		case t.value == "syntax" || t.value == "edition":
+			if err := p.expect("="); err != nil {
+				return err
+			}
+			s, err := p.expectKind(tokenString)
+			if err != nil {
+				return err
+			}
+			if t.value == "syntax" && s.value != "proto2" && s.value != "proto3" {
+				return p.errorf(s, "unsupported syntax '%v'", s.value)
+			}
+			p.file.Syntax = s.value
+			if err = p.expect(";"); err != nil {
+				return err
+			}
+		case t.value == "package":
+			name, err := p.expectKind(tokenIdent)
+			if err != nil {
+				return err
+			}
+			p.file.Package = name.value
+			if err = p.expect(";"); err != nil {
+				return err
+			}
+		case t.value == "import":
+			if p.peek().value == "public" || p.peek().value == "weak" {
+				p.next()
+			}
+			s, err := p.expectKind(tokenString)
+			if err != nil {
+				return err
+			}
+			p.file.Imports = append(p.file.Imports, s.value)
+			if err = p.expect(";"); err != nil {
+				return err
+			}
+		case t.value == "option":
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		case t.value == "message":
+			m, err := p.parseMessage(p.file.Package)
+			if err != nil {
+				return err
+			}
+			p.file.Messages = append(p.file.Messages, m)
+		case t.value == "enum":
+			e, err := p.parseEnum(p.file.Package)
+			if err != nil {
+				return err
+			}
+			p.file.Enums = append(p.file.Enums, e)
+		case t.value == "service" || t.value == "extend":
+			if err := p.skipBlock(); err != nil {
+				return err
+			}
+		default:
+			return p.errorf(t, "unexpected '%v'", t.value)
+		}
+	}
+}
+
+func (p *protoParser) parseMessage(scope string) (*Message, error) {
+	name, err := p.expectKind(tokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	m := &Message{Name: name.value, FullName: qualify(scope, name.value), proto2: p.file.Syntax == "proto2"}
+	if err = p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		switch t.value {
+		case "}":
+			p.next()
+			return m, nil
+		case ";":
+			p.next()
+		case "message":
+			p.next()
+			nested, err := p.parseMessage(m.FullName)
+			if err != nil {
+				return nil, err
+			}
+			m.Messages = append(m.Messages, nested)
+		case "enum":
+			p.next()
+			e, err := p.parseEnum(m.FullName)
+			if err != nil {
+				return nil, err
+			}
+			m.Enums = append(m.Enums, e)
+		case "oneof":
+			p.next()
+			if err := p.parseOneof(m); err != nil {
+				return nil, err
+			}
+		case "option", "reserved", "extensions":
+			p.next()
+			if err := p.skipStatement(); err != nil {
+				return nil, err
+			}
+		case "extend":
+			p.next()
+			if err := p.skipBlock(); err != nil {
+				return nil, err
+			}
+		default:
+			if t.kind == tokenEOF {
+				return nil, p.errorf(t, "unexpected end of file in message '%v'", m.Name)
+			}
+			f, err := p.parseField()
+			if err != nil {
+				return nil, err
+			}
+			m.Fields = append(m.Fields, f)
+		}
+	}
+}
+
+func (p *protoParser) parseOneof(m *Message) error {
+	name, err := p.expectKind(tokenIdent)
+	if err != nil {
+		return err
+	}
+	m.Oneofs = append(m.Oneofs, name.value)
+	if err = p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		t := p.peek()
+		switch t.value {
+		case "}":
+			p.next()
+			return nil
+		case ";":
+			p.next()
+		case "option":
+			p.next()
+			if err = p.skipStatement(); err != nil {
+				return err
+			}
+		default:
+			if t.kind == tokenEOF {
+				return p.errorf(t, "unexpected end of file in oneof '%v'", name.value)
+			}
+			f, err := p.parseField()
+			if err != nil {
+				return err
+			}
+			f.Oneof = name.value
+			m.Fields = append(m.Fields, f)
+		}
+	}
+}
+
+func (p *protoParser) parseField() (*Field, error) {
+	f := &Field{}
+	t := p.next()
+	switch t.value {
+	case "optional", "required", "repeated":
+		f.Label = t.value
+		t = p.next()
+	case "group":
+		return nil, p.errorf(t, "groups are not supported")
+	}
+
+	if t.value == "map" && p.peek().value == "<" {
+		p.next()
+		key, err := p.expectKind(tokenIdent)
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(","); err != nil {
+			return nil, err
+		}
+		value, err := p.expectKind(tokenIdent)
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(">"); err != nil {
+			return nil, err
+		}
+		f.Label = "repeated"
+		f.MapKey = key.value
+		f.Type = value.value
+	} else if t.kind == tokenIdent {
+		f.Type = t.value
+	} else {
+		return nil, p.errorf(t, "expected field type, got '%v'", t.value)
+	}
+
+	name, err := p.expectKind(tokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	f.Name = name.value
+	f.JsonName = jsonName(name.value)
+	if err = p.expect("="); err != nil {
+		return nil, err
+	}
+	n, err := p.expectKind(tokenNumber)
+	if err != nil {
+		return nil, err
+	}
+	number, err := parseInt(n.value)
+	if err != nil || number < 1 {
+		return nil, p.errorf(n, "invalid field number '%v'", n.value)
+	}
+	f.Number = int(number)
+
+	if p.peek().value == "[" {
+		p.next()
+		if err = p.parseFieldOptions(f); err != nil {
+			return nil, err
+		}
+	}
+	return f, p.expect(";")
+}
+
+func (p *protoParser) parseFieldOptions(f *Field) error {
+	for {
+		name, err := p.optionName()
+		if err != nil {
+			return err
+		}
+		if err = p.expect("="); err != nil {
+			return err
+		}
+		value, err := p.optionValue()
+		if err != nil {
+			return err
+		}
+		switch name {
+		case "json_name":
+			f.JsonName = value
+		case "packed":
+			packed := value == "true"
+			f.Packed = &packed
+		}
+
+		t := p.next()
+		switch t.value {
+		case ",":
+		case "]":
+			return nil
+		default:
+			return p.errorf(t, "expected ',' or ']', got '%v'", t.value)
+		}
+	}
+}
+
+func (p *protoParser) parseEnum(scope string) (*Enum, error) {
+	name, err := p.expectKind(tokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	e := &Enum{Name: name.value, FullName: qualify(scope, name.value)}
+	if err = p.expect("{"); err != nil {
+		return nil, err
+	}
+	for {
+		t := p.next()
+		switch {
+		case t.value == "}":
+			return e, nil
+		case t.value == ";":
+		case t.value == "option" || t.value == "reserved":
+			if err = p.skipStatement(); err != nil {
+				return nil, err
+			}
+		case t.kind == tokenIdent:
+			if err = p.expect("="); err != nil {
+				return nil, err
+			}
+			sign := ""
+			if p.peek().value == "-" {
+				p.next()
+				sign = "-"
+			}
+			n, err := p.expectKind(tokenNumber)
+			if err != nil {
+				return nil, err
+			}
+			number, err := parseInt(sign + n.value)
+			if err != nil {
+				return nil, p.errorf(n, "invalid enum value '%v'", n.value)
+			}
+			e.Values = append(e.Values, &EnumValue{Name: t.value, Number: int32(number)})
+			if p.peek().value == "[" {
+				if err = p.skipUntil("]"); err != nil {
+					return nil, err
+				}
+			}
+			if err = p.expect(";"); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, p.errorf(t, "unexpected '%v' in enum '%v'", t.value, e.Name)
+		}
+	}
+}
+
+func (p *protoParser) optionName() (string, error) {
+	var sb strings.Builder
+	for {
+		t := p.peek()
+		switch {
+		case t.value == "=":
+			if sb.Len() == 0 {
+				return "", p.errorf(t, "expected option name")
+			}
+			return sb.String(), nil
+		case t.kind == tokenIdent || t.value == "(" || t.value == ")":
+			sb.WriteString(p.next().value)
+		default:
+			return "", p.errorf(t, "unexpected '%v' in option name", t.value)
+		}
+	}
+}
+
+func (p *protoParser) optionValue() (string, error) {
+	t := p.peek()
+	switch {
+	case t.value == "{":
+		return "", p.skipUntil("}")
+	case t.value == "-" || t.value == "+":
+		p.next()
+		n, err := p.expectKind(tokenNumber)
+		return t.value + n.value, err
+	case t.kind == tokenEOF || t.kind == tokenSymbol:
+		return "", p.errorf(t, "expected option value, got '%v'", t.value)
+	default:
+		return p.next().value, nil
+	}
+}
+
+// skipStatement skips all tokens until the end of the current statement
+func (p *protoParser) skipStatement() error {
+	depth := 0
+	for {
+		t := p.next()
+		switch t.value {
+		case "{":
+			depth++
+		case "}":
+			depth--
+		case ";":
+			if depth == 0 {
+				return nil
+			}
+		}
+		if t.kind == tokenEOF {
+			return p.errorf(t, "unexpected end of file")
+		}
+	}
+}
+
+// skipBlock skips all tokens until the end of the next block, e.g. a service definition
+func (p *protoParser) skipBlock() error {
+	for {
+		t := p.next()
+		if t.kind == tokenEOF {
+			return p.errorf(t, "unexpected end of file")
+		}
+		if t.value == "{" {
+			p.pos--
+			return p.skipUntil("}")
+		}
+	}
+}
+
+// skipUntil skips a bracketed expression starting at the current token
+func (p *protoParser) skipUntil(closing string) error {
+	opening := p.next().value
+	depth := 1
+	for depth > 0 {
+		t := p.next()
+		switch t.value {
+		case opening:
+			depth++
+		case closing:
+			depth--
+		}
+		if t.kind == tokenEOF {
+			return p.errorf(t, "missing '%v'", closing)
+		}
+	}
+	return nil
+}
+
+func (p *protoParser) next() token {
+	t := p.peek()
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *protoParser) peek() token {
+	if p.pos >= len(p.tokens) {
+		line := 0
+		if len(p.tokens) > 0 {
+			line = p.tokens[len(p.tokens)-1].line
+		}
+		return token{kind: tokenEOF, line: line}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *protoParser) expect(s string) error {
+	t := p.next()
+	if t.value != s || t.kind == tokenString {
+		return p.errorf(t, "expected '%v', got '%v'", s, t.value)
+	}
+	return nil
+}
+
+func (p *protoParser) expectKind(kind tokenKind) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, p.errorf(t, "expected %v, got '%v'", kind, t.value)
+	}
+	return t, nil
+}
+
+func (p *protoParser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("line %v: %v", t.line, fmt.Sprintf(format, args...))
+}
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenIdent:
+		return "identifier"
+	case tokenNumber:
+		return "number"
+	case tokenString:
+		return "string"
+	case tokenSymbol:
+		return "symbol"
+	default:
+		return "end of file"
+	}
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	line := 1
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case unicode.IsSpace(c):
+			i++
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i+1 >= len(runes) {
+				return nil, fmt.Errorf("line %v: unterminated comment", line)
+			}
+			i += 2
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			for i < len(runes) && runes[i] != c {
+				if runes[i] == '\\' {
+					i++
+				}
+				if i < len(runes) && runes[i] == '\n' {
+					return nil, fmt.Errorf("line %v: unterminated string", line)
+				}
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("line %v: unterminated string", line)
+			}
+			quoted := string(runes[start : i+1])
+			if c == '\'' {
+				quoted = `"` + strings.ReplaceAll(quoted[1:len(quoted)-1], `"`, `\"`) + `"`
+			}
+			value, err := strconv.Unquote(quoted)
+			if err != nil {
+				return nil, fmt.Errorf("line %v: invalid string %v", line, quoted)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, line: line})
+			i++
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '.' ||
+				((runes[i] == '-' || runes[i] == '+') && (runes[i-1] == 'e' || runes[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), line: line})
+		case unicode.IsLetter(c) || c == '_' || c == '.':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: string(runes[start:i]), line: line})
+		default:
+			tokens = append(tokens, token{kind: tokenSymbol, value: string(c), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func parseInt(s string) (int64, error) {
+	return strconv.ParseInt(s, 0, 64)
+}
+
+func qualify(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+// jsonName converts a field name to lowerCamelCase like protoc does
+func jsonName(name string) string {
+	var sb strings.Builder
+	upper := false
+	for _, c := range name {
+		if c == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			sb.WriteRune(unicode.ToUpper(c))
+			upper = false
+		} else {
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,232 @@
+package schema_test
+
+import (
+	"mokapi/config/dynamic"
+	"mokapi/config/dynamic/dynamictest"
+	"mokapi/schema/protobuf/schema"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testcases := []struct {
+		name  string
+		input string
+		test  func(t *testing.T, f *schema.File, err error)
+	}{
+		{
+			name: "message",
+			input: `
+syntax = "proto3";
+package shop.v1;
+
+option go_package = "example.com/shop";
+
+// an order
+message Order {
+  string id = 1;
+  repeated Item items = 2 [json_name = "lineItems"];
+  map<string, int32> quantities = 3;
+  optional string note = 4;
+  Status status = 5;
+  oneof payment {
+    string card = 6;
+    string iban = 7;
+  }
+  reserved 8, 9 to 11;
+  /* nested types */
+  message Item {
+    string sku = 1;
+    repeated int64 prices = 2 [packed = false];
+  }
+}
+
+enum Status {
+  option allow_alias = true;
+  STATUS_UNSPECIFIED = 0;
+  OPEN = 1;
+  CLOSED = 2 [deprecated = true];
+}
+
+service OrderService {
+  rpc Get (Order) returns (Order) {
+    option (google.api.http) = { get: "/orders/{id}" };
+  }
+}
+`,
+			test: func(t *testing.T, f *schema.File, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "proto3", f.Syntax)
+				require.Equal(t, "shop.v1", f.Package)
+				require.Len(t, f.Messages, 1)
+				m := f.Messages[0]
+				require.Equal(t, "shop.v1.Order", m.FullName)
+				require.Len(t, m.Fields, 7)
+				require.Equal(t, "lineItems", m.Field("items").JsonName)
+				require.Equal(t, "repeated", m.Field("items").Label)
+				require.Equal(t, "string", m.Field("quantities").MapKey)
+				require.Equal(t, "int32", m.Field("quantities").Type)
+				require.Equal(t, "optional", m.Field("note").Label)
+				require.Equal(t, "payment", m.Field("iban").Oneof)
+				require.Equal(t, []string{"payment"}, m.Oneofs)
+				require.Equal(t, "shop.v1.Order.Item", m.Messages[0].FullName)
+				require.False(t, *m.Messages[0].Field("prices").Packed)
+				require.Equal(t, []string{"STATUS_UNSPECIFIED", "OPEN", "CLOSED"}, f.Enums[0].Names())
+			},
+		},
+		{
+			name:  "json name",
+			input: `message Foo { string first_name = 1; }`,
+			test: func(t *testing.T, f *schema.File, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "proto2", f.Syntax)
+				require.Equal(t, "firstName", f.Messages[0].Fields[0].JsonName)
+			},
+		},
+		{
+			name:  "negative enum value",
+			input: `enum Foo { A = 0; B = -1; C = 0x10; }`,
+			test: func(t *testing.T, f *schema.File, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int32(-1), f.Enums[0].Values[1].Number)
+				require.Equal(t, int32(16), f.Enums[0].Values[2].Number)
+			},
+		},
+		{
+			name:  "missing semicolon",
+			input: "message Foo {\n  string name = 1\n}",
+			test: func(t *testing.T, f *schema.File, err error) {
+				require.EqualError(t, err, "line 3: expected ';', got '}'")
+			},
+		},
+		{
+			name:  "invalid field number",
+			input: `message Foo { string name = 0; }`,
+			test: func(t *testing.T, f *schema.File, err error) {
+				require.EqualError(t, err, "line 1: invalid field number '0'")
+			},
+		},
+		{
+			name:  "unsupported syntax",
+			input: `syntax = "proto4";`,
+			test: func(t *testing.T, f *schema.File, err error) {
+				require.EqualError(t, err, "line 1: unsupported syntax 'proto4'")
+			},
+		},
+		{
+			name:  "unterminated message",
+			input: `message Foo { string name = 1;`,
+			test: func(t *testing.T, f *schema.File, err error) {
+				require.EqualError(t, err, "line 1: unexpected end of file in message 'Foo'")
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := schema.Parse([]byte(tc.input))
+			tc.test(t, f, err)
+		})
+	}
+}
+
+func TestFile_Parse(t *testing.T) {
+	testcases := []struct {
+		name   string
+		input  string
+		reader dynamic.Reader
+		test   func(t *testing.T, f *schema.File, err error)
+	}{
+		{
+			name: "resolve nested and outer types",
+			input: `
+syntax = "proto3";
+package shop;
+message Order {
+  Item item = 1;
+  Status status = 2;
+  message Item { Status status = 1; }
+}
+enum Status { OPEN = 0; }
+`,
+			test: func(t *testing.T, f *schema.File, err error) {
+				require.NoError(t, err)
+				order := f.Message("Order")
+				require.Equal(t, "shop.Order.Item", order.Field("item").Message.FullName)
+				require.Equal(t, "shop.Status", order.Field("status").Enum.FullName)
+				require.Equal(t, "shop.Status", f.Message("Order.Item").Field("status").Enum.FullName)
+				require.Equal(t, order, f.Message("shop.Order"))
+			},
+		},
+		{
+			name: "well-known type",
+			input: `
+syntax = "proto3";
+import "google/protobuf/timestamp.proto";
+message Order { google.protobuf.Timestamp created = 1; }
+`,
+			test: func(t *testing.T, f *schema.File, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "google.protobuf.Timestamp", f.Message("Order").Field("created").Message.FullName)
+			},
+		},
+		{
+			name: "import",
+			input: `
+syntax = "proto3";
+package shop;
+import "common.proto";
+message Order { common.Money total = 1; }
+`,
+			reader: &dynamictest.Reader{Data: map[string]*dynamic.Config{
+				"file:///proto/common.proto": {
+					Info: dynamictest.NewConfigInfo(dynamictest.WithUrl("file:///proto/common.proto")),
+					Raw:  []byte(`syntax = "proto3"; package common; message Money { int64 units = 1; string currency = 2; }`),
+				},
+			}},
+			test: func(t *testing.T, f *schema.File, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "common.Money", f.Message("Order").Field("total").Message.FullName)
+			},
+		},
+		{
+			name:  "unknown type",
+			input: `message Order { Item item = 1; }`,
+			test: func(t *testing.T, f *schema.File, err error) {
+				require.EqualError(t, err, "parsing file file:///proto/order.proto: message 'Order': type 'Item' of field 'item' not found")
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, _ := url.Parse("file:///proto/order.proto")
+			c := &dynamic.Config{
+				Info: dynamic.ConfigInfo{Url: u},
+				Raw:  []byte(tc.input),
+			}
+			err := dynamic.Parse(c, tc.reader)
+			f, _ := c.Data.(*schema.File)
+			tc.test(t, f, err)
+		})
+	}
+}
+
+func TestFile_Resolve(t *testing.T) {
+	f, err := schema.Parse([]byte(`package shop; message Order {} message Item {}`))
+	require.NoError(t, err)
+	require.NoError(t, f.Parse(&dynamic.Config{}, nil))
+
+	v, err := f.Resolve("")
+	require.NoError(t, err)
+	require.Equal(t, f, v)
+
+	v, err = f.Resolve("Item")
+	require.NoError(t, err)
+	require.Equal(t, "shop.Item", v.(*schema.Message).FullName)
+
+	_, err = f.Resolve("Foo")
+	require.EqualError(t, err, "message 'Foo' not found")
+}
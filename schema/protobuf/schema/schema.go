@@ -0,0 +1,275 @@
+package schema
+
+import (
+	"fmt"
+	"mokapi/config/dynamic"
+	json "mokapi/schema/json/schema"
+	"strings"
+)
+
+// File is a parsed .proto file
+type File struct {
+	Syntax   string
+	Package  string
+	Imports  []string
+	Messages []*Message
+	Enums    []*Enum
+
+	// types contains all messages and enums by full name
+	// including the types of imported files
+	types map[string]any
+}
+
+type Message struct {
+	Name     string
+	FullName string
+	Fields   []*Field
+	Oneofs   []string
+	Messages []*Message
+	Enums    []*Enum
+
+	// proto2 uses explicit field presence
+	proto2 bool
+}
+
+type Field struct {
+	Name     string
+	JsonName string
+	Number   int
+	// Type is a scalar type like int32 or the name of a message or enum
+	Type  string
+	Label string
+	Oneof string
+
+	// MapKey is the scalar key type of a map field. The value is described by Type.
+	MapKey string
+
+	Packed *bool
+
+	Message *Message
+	Enum    *Enum
+}
+
+type Enum struct {
+	Name     string
+	FullName string
+	Values   []*EnumValue
+}
+
+type EnumValue struct {
+	Name   string
+	Number int32
+}
+
+func (f *File) Parse(config *dynamic.Config, reader dynamic.Reader) error {
+	f.types = map[string]any{}
+	for _, imp := range f.Imports {
+		var imported *File
+		var err error
+		if wk, ok := wellKnownFiles[imp]; ok {
+			imported = wk
+		} else {
+			ref := dynamic.Reference[*File]{Ref: imp}
+			imported, err = ref.Resolve(config, reader)
+			if err != nil {
+				return fmt.Errorf("import '%v' failed: %w", imp, err)
+			}
+		}
+		for name, t := range imported.types {
+			f.types[name] = t
+		}
+	}
+
+	for _, m := range f.Messages {
+		f.register(m)
+	}
+	for _, e := range f.Enums {
+		f.types[e.FullName] = e
+	}
+
+	for _, m := range f.Messages {
+		if err := f.link(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Resolve returns a message by its name, e.g. Order, Order.Item or
+// shop.Order
+func (f *File) Resolve(token string) (interface{}, error) {
+	if token == "" {
+		return f, nil
+	}
+
+	candidates := []string{token}
+	if f.Package != "" {
+		candidates = append([]string{fmt.Sprintf("%v.%v", f.Package, token)}, candidates...)
+	}
+	for _, name := range candidates {
+		if m, ok := f.types[name].(*Message); ok {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("message '%v' not found", token)
+}
+
+func (f *File) Message(name string) *Message {
+	m, _ := f.Resolve(name)
+	if msg, ok := m.(*Message); ok {
+		return msg
+	}
+	return nil
+}
+
+func (f *File) register(m *Message) {
+	f.types[m.FullName] = m
+	for _, nested := range m.Messages {
+		f.register(nested)
+	}
+	for _, e := range m.Enums {
+		f.types[e.FullName] = e
+	}
+}
+
+func (f *File) link(m *Message) error {
+	for _, field := range m.Fields {
+		if isScalar(field.Type) {
+			continue
+		}
+		t, ok := f.lookup(field.Type, m.FullName)
+		if !ok {
+			return fmt.Errorf("message '%v': type '%v' of field '%v' not found", m.FullName, field.Type, field.Name)
+		}
+		switch v := t.(type) {
+		case *Message:
+			field.Message = v
+		case *Enum:
+			field.Enum = v
+		}
+	}
+	for _, nested := range m.Messages {
+		if err := f.link(nested); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup resolves a type name relative to the given scope following the
+// protobuf scoping rules: the innermost scope is searched first.
+func (f *File) lookup(name, scope string) (any, bool) {
+	if strings.HasPrefix(name, ".") {
+		t, ok := f.types[name[1:]]
+		return t, ok
+	}
+	for {
+		fullName := name
+		if scope != "" {
+			fullName = scope + "." + name
+		}
+		if t, ok := f.types[fullName]; ok {
+			return t, true
+		}
+		if scope == "" {
+			return nil, false
+		}
+		i := strings.LastIndex(scope, ".")
+		if i < 0 {
+			scope = ""
+		} else {
+			scope = scope[:i]
+		}
+	}
+}
+
+// Parse is part of the schema interface. Types of a message are
+// resolved when its .proto file is parsed.
+func (m *Message) Parse(_ *dynamic.Config, _ dynamic.Reader) error {
+	return nil
+}
+
+func (m *Message) ConvertTo(i any) (any, error) {
+	if _, ok := i.(*json.Schema); ok {
+		return ConvertToJsonSchema(m), nil
+	}
+	return nil, fmt.Errorf("unsupported schema convert to %T", i)
+}
+
+func (m *Message) Field(name string) *Field {
+	for _, f := range m.Fields {
+		if f.Name == name || f.JsonName == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (m *Message) fieldByNumber(n int) *Field {
+	for _, f := range m.Fields {
+		if f.Number == n {
+			return f
+		}
+	}
+	return nil
+}
+
+func (f *Field) IsRepeated() bool {
+	return f.Label == "repeated" && f.MapKey == ""
+}
+
+func (f *Field) IsMap() bool {
+	return f.MapKey != ""
+}
+
+// hasPresence reports whether a field is written even if it has its
+// default value.
+func (f *Field) hasPresence(m *Message) bool {
+	return m.proto2 || f.Label == "optional" || f.Oneof != "" || f.Message != nil
+}
+
+func (f *Field) isPacked(m *Message) bool {
+	if f.Packed != nil {
+		return *f.Packed
+	}
+	// proto3 packs repeated scalar numeric fields by default
+	return !m.proto2
+}
+
+func (e *Enum) Value(name string) (*EnumValue, bool) {
+	for _, v := range e.Values {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func (e *Enum) ValueByNumber(n int32) (*EnumValue, bool) {
+	for _, v := range e.Values {
+		if v.Number == n {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func (e *Enum) Names() []string {
+	var names []string
+	for _, v := range e.Values {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+func isScalar(t string) bool {
+	switch t {
+	case "double", "float",
+		"int32", "int64", "uint32", "uint64", "sint32", "sint64",
+		"fixed32", "fixed64", "sfixed32", "sfixed64",
+		"bool", "string", "bytes":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"embed"
+	"io/fs"
+	"mokapi/config/dynamic"
+	"strings"
+)
+
+//go:embed wellknown
+var wellKnownFS embed.FS
+
+// wellKnownFiles contains the well-known types like google/protobuf/timestamp.proto
+// which can be imported without providing the file
+var wellKnownFiles map[string]*File
+
+func init() {
+	wellKnownFiles = loadWellKnownFiles()
+	dynamic.RegisterFileParser(".proto", func(b []byte) (any, error) {
+		return Parse(b)
+	})
+}
+
+func loadWellKnownFiles() map[string]*File {
+	files := map[string]*File{}
+	err := fs.WalkDir(wellKnownFS, "wellknown", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := wellKnownFS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		f, err := Parse(b)
+		if err != nil {
+			return err
+		}
+		if err = f.Parse(nil, nil); err != nil {
+			return err
+		}
+		files[strings.TrimPrefix(path, "wellknown/")] = f
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return files
+}
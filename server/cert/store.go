@@ -1,6 +1,7 @@
 package cert
 
 import (
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
@@ -8,12 +9,27 @@ import (
 	"fmt"
 	"mokapi/config/static"
 	"path"
+	"sync"
 )
 
 type Store struct {
 	Certificates map[string]*tls.Certificate
 	CaCert       *x509.Certificate
 	CaKey        *rsa.PrivateKey
+
+	signingKey     *rsa.PrivateKey
+	signingKeyErr  error
+	signingKeyOnce sync.Once
+}
+
+// SigningKey returns the key which signs the tokens of the built-in OAuth2
+// authorization servers. It is generated on first use, so the private key
+// of the CA is not used for tokens.
+func (store *Store) SigningKey() (*rsa.PrivateKey, error) {
+	store.signingKeyOnce.Do(func() {
+		store.signingKey, store.signingKeyErr = rsa.GenerateKey(rand.Reader, 2048)
+	})
+	return store.signingKey, store.signingKeyErr
 }
 
 func NewStore(config *static.Config) (*Store, error) {
@@ -8,6 +8,7 @@ import (
 	"mokapi/config/dynamic/provider/file"
 	"mokapi/config/dynamic/provider/git"
 	"mokapi/config/dynamic/provider/http"
+	"mokapi/config/dynamic/provider/memory"
 	"mokapi/config/dynamic/provider/npm"
 	"mokapi/config/static"
 	"mokapi/safe"
@@ -20,7 +21,9 @@ import (
 
 type ConfigWatcher struct {
 	providers map[string]dynamic.Provider
+	memory    *memory.Provider
 	listener  []dynamic.ConfigListener
+	onError   []func(c *dynamic.Config, err error)
 	configs   map[string]*entry
 	cfg       *static.Config
 	m         sync.Mutex
@@ -44,10 +47,17 @@ func NewConfigWatcher(cfg *static.Config) *ConfigWatcher {
 	w.providers["https"] = h
 	w.providers["git"] = git.New(cfg.Providers.Git)
 	w.providers["npm"] = npm.New(cfg.Providers.Npm)
+	w.memory = memory.New(w)
+	w.providers[memory.Scheme] = w.memory
 
 	return w
 }
 
+// Memory returns the provider for configs uploaded at runtime
+func (w *ConfigWatcher) Memory() *memory.Provider {
+	return w.memory
+}
+
 func (w *ConfigWatcher) Read(u *url.URL, v any) (*dynamic.Config, error) {
 	p, ok := w.providers[u.Scheme]
 	if !ok {
@@ -153,6 +163,12 @@ func (w *ConfigWatcher) AddListener(f func(e dynamic.ConfigEvent)) {
 	w.listener = append(w.listener, f)
 }
 
+// AddErrorListener adds a function that is called when a config could
+// not be parsed or is invalid
+func (w *ConfigWatcher) AddErrorListener(f func(c *dynamic.Config, err error)) {
+	w.onError = append(w.onError, f)
+}
+
 func (w *ConfigWatcher) addOrUpdate(evt dynamic.ConfigEvent) error {
 	c := evt.Config
 
@@ -236,6 +252,7 @@ func (w *ConfigWatcher) configChanged(evt dynamic.ConfigEvent) {
 	if err != nil {
 		log.Errorf("parse error %v: %v", c.Info.Path(), err)
 		e.m.Unlock()
+		w.invokeErrorListeners(c, err)
 		return
 	}
 
@@ -248,6 +265,7 @@ func (w *ConfigWatcher) configChanged(evt dynamic.ConfigEvent) {
 	if err = dynamic.Validate(c); err != nil {
 		e.m.Unlock()
 		log.Errorf("skipping file %v: %v", c.Info.Path(), err)
+		w.invokeErrorListeners(c, err)
 		return
 	}
 
@@ -260,6 +278,12 @@ func (w *ConfigWatcher) configChanged(evt dynamic.ConfigEvent) {
 	}
 }
 
+func (w *ConfigWatcher) invokeErrorListeners(c *dynamic.Config, err error) {
+	for _, l := range w.onError {
+		l(c, err)
+	}
+}
+
 func (w *ConfigWatcher) remove(evt dynamic.ConfigEvent) {
 	w.m.Lock()
 	key := getConfigKey(evt.Config.Info.Url)
@@ -74,6 +74,11 @@ func (m *HttpManager) removeService(name string) {
 }
 
 func (m *HttpManager) Update(e dynamic.ConfigEvent) {
+	if _, ok := runtime.IsAsyncApiConfig(e.Config); ok {
+		m.updateWebSocket(e)
+		return
+	}
+
 	cfg, ok := runtime.IsHttpConfig(e.Config)
 	if !ok {
 		return
@@ -97,6 +102,15 @@ func (m *HttpManager) Update(e dynamic.ConfigEvent) {
 		m.cleanupRemovedServers(info, servers)
 	}
 
+	var opts []openapi.HandlerOptions
+	if m.certStore != nil {
+		if key, err := m.certStore.SigningKey(); err != nil {
+			log.Errorf("unable to create signing key for OAuth2 tokens: %v", err)
+		} else {
+			opts = append(opts, openapi.WithSigningKey(key))
+		}
+	}
+
 	for _, s := range info.Servers {
 		u, err := parseUrl(s.Url)
 		if err != nil {
@@ -104,7 +118,7 @@ func (m *HttpManager) Update(e dynamic.ConfigEvent) {
 			continue
 		}
 
-		err = m.AddService(cfg.Info.Name, u, info.Handler(m.app.Monitor.Http, m.eventEmitter, m.app.Events))
+		err = m.AddService(cfg.Info.Name, u, info.Handler(m.app.Monitor.Http, m.eventEmitter, m.app.Events, opts...))
 		if err != nil {
 			log.Warnf("unable to add '%v' on %v: %v", cfg.Info.Name, s.Url, err.Error())
 			continue
@@ -115,6 +129,55 @@ func (m *HttpManager) Update(e dynamic.ConfigEvent) {
 	log.Debugf("processed %v", e.Config.Info.Path())
 }
 
+// updateWebSocket hosts the channels of AsyncAPI servers with protocol
+// ws or wss on the HTTP listeners
+func (m *HttpManager) updateWebSocket(e dynamic.ConfigEvent) {
+	cfg, ok := runtime.HasWebSocketServer(e.Config)
+	if cfg == nil {
+		return
+	}
+	name := cfg.Info.Name
+	if !ok || e.Event == dynamic.Delete {
+		if m.app.WebSocket.Get(name) == nil {
+			return
+		}
+		m.app.WebSocket.Remove(e.Config)
+		if m.app.WebSocket.Get(name) == nil {
+			m.removeService(name)
+			m.stopEmptyServers()
+		}
+		return
+	}
+
+	info, err := m.app.WebSocket.Add(e.Config, m.eventEmitter)
+	if err != nil {
+		log.Errorf("add WebSocket config %v failed: %v", e.Config.Info.Url, err)
+		return
+	}
+
+	for it := info.Servers.Iter(); it.Next(); {
+		s := it.Value()
+		if s == nil || s.Value == nil || !s.Value.IsWebSocket() {
+			continue
+		}
+		scheme := "http"
+		if s.Value.IsSecure() {
+			scheme = "https"
+		}
+		u, err := parseUrl(fmt.Sprintf("%v://%v%v", scheme, s.Value.Host, s.Value.Pathname))
+		if err != nil {
+			log.Errorf("url syntax error %v: %v", e.Config.Info.Url, err.Error())
+			continue
+		}
+		if err = m.AddService(name, u, info.Handler()); err != nil {
+			log.Warnf("unable to add WebSocket '%v' on %v: %v", name, u, err.Error())
+		}
+	}
+
+	m.stopEmptyServers()
+	log.Debugf("processed %v", e.Config.Info.Path())
+}
+
 func (m *HttpManager) Stop() {
 	for _, server := range m.servers {
 		server.Stop()
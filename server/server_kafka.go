@@ -6,6 +6,7 @@ import (
 	"mokapi/providers/asyncapi3"
 	"mokapi/runtime"
 	"mokapi/runtime/monitor"
+	"mokapi/server/cert"
 	"mokapi/server/service"
 	"mokapi/sortedmap"
 	"net/url"
@@ -22,23 +23,26 @@ type Broker interface {
 }
 
 type kafkaCluster struct {
-	brokers map[string]Broker
-	cfg     *runtime.KafkaInfo
-	monitor *monitor.Kafka
+	brokers   map[string]Broker
+	cfg       *runtime.KafkaInfo
+	monitor   *monitor.Kafka
+	certStore *cert.Store
 }
 
 type KafkaManager struct {
-	clusters map[string]*kafkaCluster
-	emitter  common.EventEmitter
-	app      *runtime.App
-	m        sync.Mutex
+	clusters  map[string]*kafkaCluster
+	emitter   common.EventEmitter
+	certStore *cert.Store
+	app       *runtime.App
+	m         sync.Mutex
 }
 
-func NewKafkaManager(emitter common.EventEmitter, app *runtime.App) *KafkaManager {
+func NewKafkaManager(emitter common.EventEmitter, store *cert.Store, app *runtime.App) *KafkaManager {
 	return &KafkaManager{
-		clusters: map[string]*kafkaCluster{},
-		emitter:  emitter,
-		app:      app,
+		clusters:  map[string]*kafkaCluster{},
+		emitter:   emitter,
+		certStore: store,
+		app:       app,
 	}
 }
 
@@ -84,7 +88,7 @@ func (m *KafkaManager) getOrCreateCluster(cfg *runtime.KafkaInfo) *kafkaCluster
 	c, ok := m.clusters[cfg.Info.Name]
 	if !ok {
 		log.Infof("adding new kafka cluster '%v'", cfg.Info.Name)
-		c = &kafkaCluster{cfg: cfg, brokers: make(map[string]Broker), monitor: m.app.Monitor.Kafka}
+		c = &kafkaCluster{cfg: cfg, brokers: make(map[string]Broker), monitor: m.app.Monitor.Kafka, certStore: m.certStore}
 		m.clusters[cfg.Info.Name] = c
 	}
 	return c
@@ -136,7 +140,11 @@ func (c *kafkaCluster) updateBrokers(cfg *runtime.KafkaInfo, old *sortedmap.Link
 		broker, found := c.brokers[port]
 		if !found {
 			log.Infof("adding new kafka broker '%v' on port %v to cluster '%v'", name, port, cfg.Info.Name)
-			broker = service.NewKafkaBroker(port, cfg.Handler(c.monitor))
+			var store *cert.Store
+			if server.Value.IsSecure() {
+				store = c.certStore
+			}
+			broker = service.NewKafkaBroker(port, cfg.Handler(c.monitor), store)
 			broker.Start()
 		}
 		c.brokers[port] = broker
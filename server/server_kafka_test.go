@@ -34,7 +34,7 @@ func TestKafkaServer(t *testing.T) {
 	)
 
 	cfg := &static.Config{}
-	m := NewKafkaManager(nil, runtime.New(cfg, &dynamictest.Reader{}))
+	m := NewKafkaManager(nil, nil, runtime.New(cfg, &dynamictest.Reader{}))
 	defer m.Stop()
 	m.UpdateConfig(dynamic.ConfigEvent{Config: &dynamic.Config{Info: dynamic.ConfigInfo{Url: MustParseUrl("foo.yml")}, Data: c}})
 
@@ -394,7 +394,7 @@ func TestKafkaServer_Update(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			cfg := &static.Config{}
-			m := NewKafkaManager(nil, runtime.New(cfg, &dynamictest.Reader{}))
+			m := NewKafkaManager(nil, nil, runtime.New(cfg, &dynamictest.Reader{}))
 			defer m.Stop()
 
 			tc.test(t, m)
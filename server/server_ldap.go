@@ -1,6 +1,7 @@
 package server
 
 import (
+	"crypto/tls"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"mokapi/config/dynamic"
@@ -68,7 +69,18 @@ func (m *LdapDirectoryManager) UpdateConfig(e dynamic.ConfigEvent) {
 }
 
 func (m *LdapDirectoryManager) start(cfg *runtime.LdapInfo) {
-	s := &ldap.Server{Addr: cfg.Address, Handler: cfg.Handler(m.app.Monitor.Ldap, m.app.Events)}
+	addr, implicitTls := cfg.ListenAddress()
+	s := &ldap.Server{Addr: addr, Handler: cfg.Handler(m.app.Monitor.Ldap, m.app.Events)}
+	if m.certStore != nil {
+		s.TLSConfig = &tls.Config{
+			GetCertificate:     m.certStore.GetCertificate,
+			InsecureSkipVerify: true,
+		}
+		s.TlsMode = ldap.TlsStartTls
+		if implicitTls {
+			s.TlsMode = ldap.TlsImplicit
+		}
+	}
 	m.servers[cfg.Info.Name] = s
 	log.Infof("adding LDAP host '%v' on binding %v", cfg.Info.Name, s.Addr)
 	go func() {
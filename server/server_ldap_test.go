@@ -9,6 +9,7 @@ import (
 	"mokapi/ldap"
 	"mokapi/providers/directory"
 	"mokapi/runtime"
+	"mokapi/sortedmap"
 	"mokapi/try"
 	"net/url"
 	"testing"
@@ -60,6 +61,35 @@ func TestLdapDirectory(t *testing.T) {
 				require.NoError(t, err)
 			},
 		},
+		{
+			name: "who am i after bind",
+			test: func(t *testing.T, m *LdapDirectoryManager, app *runtime.App) {
+				entries := &sortedmap.LinkedHashMap[string, directory.Entry]{}
+				entries.Set("cn=alice", directory.Entry{Dn: "cn=alice", Attributes: map[string][]string{"userPassword": {"secret"}}})
+				m.UpdateConfig(dynamic.ConfigEvent{
+					Config: newConfig("foo", &directory.Config{
+						Info:    directory.Info{Name: "foo"},
+						Address: fmt.Sprintf(":%v", try.GetFreePort()),
+						Entries: entries,
+					}),
+				})
+
+				c := ldap.NewClient(app.Ldap.Get("foo").Address)
+				require.NoError(t, c.Dial())
+				_, err := c.Bind("cn=alice", "secret")
+				require.NoError(t, err)
+				res, err := c.WhoAmI()
+				require.NoError(t, err)
+				require.Equal(t, "dn:cn=alice", string(res.Value))
+
+				res, err = c.PasswordModify(&ldap.PasswordModifyRequest{NewPassword: "changed"})
+				require.NoError(t, err)
+				require.Equal(t, ldap.Success, res.ResultCode)
+				r, err := c.Bind("cn=alice", "changed")
+				require.NoError(t, err)
+				require.Equal(t, ldap.Success, r.Result)
+			},
+		},
 		{
 			name: "update ldap event",
 			test: func(t *testing.T, m *LdapDirectoryManager, app *runtime.App) {
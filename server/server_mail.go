@@ -5,6 +5,7 @@ import (
 	"mokapi/config/dynamic"
 	engine "mokapi/engine/common"
 	"mokapi/imap"
+	"mokapi/pop3"
 	"mokapi/providers/mail"
 	"mokapi/runtime"
 	"mokapi/server/cert"
@@ -133,6 +134,34 @@ func (m *MailManager) startServers(cfg *runtime.MailInfo) error {
 			s := service.NewImapServer(port, h, m.certStore, imap.Implicit)
 			s.Start()
 			servers[port] = s
+		case "pop3":
+			if port == "" {
+				port = "110"
+			}
+
+			addr := fmt.Sprintf(":%v", port)
+			if _, ok = servers[addr]; ok {
+				continue
+			}
+
+			log.Infof("adding new POP3 host on %v", port)
+			s := service.NewPop3Server(port, h, m.certStore, pop3.StartTls)
+			s.Start()
+			servers[port] = s
+		case "pop3s":
+			if port == "" {
+				port = "995"
+			}
+
+			addr := fmt.Sprintf(":%v", port)
+			if _, ok = servers[addr]; ok {
+				continue
+			}
+
+			log.Infof("adding new POP3S host on %v", port)
+			s := service.NewPop3Server(port, h, m.certStore, pop3.Implicit)
+			s.Start()
+			servers[port] = s
 		}
 	}
 	return nil
@@ -154,6 +183,8 @@ func (m *MailManager) removeService(name string) {
 			log.Infof("removing service '%v' on IMAP binding %v", name, s.Addr())
 		case *service.ImapServer:
 			log.Infof("removing service '%v' on SMTP binding %v", name, s.Addr())
+		case *service.Pop3Server:
+			log.Infof("removing service '%v' on POP3 binding %v", name, s.Addr())
 		}
 		server.Stop()
 	}
@@ -181,6 +212,10 @@ func (m *MailManager) cleanupRemovedServers(cfg *runtime.MailInfo, old map[strin
 				port = "143"
 			case "imaps":
 				port = "993"
+			case "pop3":
+				port = "110"
+			case "pop3s":
+				port = "995"
 			}
 		}
 
@@ -201,6 +236,12 @@ func (m *MailManager) cleanupRemovedServers(cfg *runtime.MailInfo, old map[strin
 					s.Stop()
 					delete(m.servers, addr)
 				}
+			case *service.Pop3Server:
+				if s.Addr() == addr {
+					log.Infof("removing '%v' on POP3 binding %v", cfg.Info.Name, addr)
+					s.Stop()
+					delete(m.servers, addr)
+				}
 			}
 		}
 	}
@@ -5,6 +5,7 @@ import (
 	"mokapi/engine/common"
 	"mokapi/runtime"
 	"mokapi/runtime/monitor"
+	"mokapi/server/cert"
 	"mokapi/server/service"
 	"sync"
 
@@ -12,22 +13,25 @@ import (
 )
 
 type MqttManager struct {
-	clusters map[string]*mqttCluster
-	emitter  common.EventEmitter
-	app      *runtime.App
-	m        sync.Mutex
+	clusters  map[string]*mqttCluster
+	emitter   common.EventEmitter
+	certStore *cert.Store
+	app       *runtime.App
+	m         sync.Mutex
 }
 
 type mqttCluster struct {
-	brokers map[string]Broker
-	cfg     *runtime.MqttInfo
+	brokers   map[string]Broker
+	cfg       *runtime.MqttInfo
+	certStore *cert.Store
 }
 
-func NewMqttManager(emitter common.EventEmitter, app *runtime.App) *MqttManager {
+func NewMqttManager(emitter common.EventEmitter, store *cert.Store, app *runtime.App) *MqttManager {
 	return &MqttManager{
-		clusters: map[string]*mqttCluster{},
-		emitter:  emitter,
-		app:      app,
+		clusters:  map[string]*mqttCluster{},
+		emitter:   emitter,
+		certStore: store,
+		app:       app,
 	}
 }
 
@@ -72,7 +76,7 @@ func (m *MqttManager) getOrCreateCluster(cfg *runtime.MqttInfo) *mqttCluster {
 
 	c, ok := m.clusters[cfg.Info.Name]
 	if !ok {
-		c = &mqttCluster{cfg: cfg, brokers: make(map[string]Broker)}
+		c = &mqttCluster{cfg: cfg, brokers: make(map[string]Broker), certStore: m.certStore}
 		m.clusters[cfg.Info.Name] = c
 	}
 	return c
@@ -101,7 +105,7 @@ func (c *mqttCluster) updateBrokers(cfg *runtime.MqttInfo, monitor *monitor.Mqtt
 	for it := cfg.Servers.Iter(); it.Next(); {
 		name := it.Key()
 		server := it.Value()
-		if server == nil || server.Value == nil || server.Value.Protocol != "mqtt" {
+		if server == nil || server.Value == nil || !server.Value.IsMqtt() {
 			continue
 		}
 		port, err := getPortFromUrl(server.Value.Host, "1883")
@@ -115,7 +119,11 @@ func (c *mqttCluster) updateBrokers(cfg *runtime.MqttInfo, monitor *monitor.Mqtt
 			delete(brokers, port)
 		} else {
 			log.Infof("adding new MQTT broker '%v' on port %v to '%v'", name, port, cfg.Info.Name)
-			broker = service.NewMqttBroker(port, cfg.Handler(monitor))
+			var store *cert.Store
+			if server.Value.IsSecure() {
+				store = c.certStore
+			}
+			broker = service.NewMqttBroker(port, cfg.Handler(monitor), store)
 			broker.Start()
 		}
 		c.brokers[port] = broker
@@ -115,7 +115,7 @@ func TestMqttServer(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			m := NewMqttManager(nil, runtime.New(&static.Config{}, &dynamictest.Reader{}))
+			m := NewMqttManager(nil, nil, runtime.New(&static.Config{}, &dynamictest.Reader{}))
 			defer m.Stop()
 
 			tc.test(t, m)
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"crypto/tls"
 	"fmt"
 	"mokapi/kafka"
+	"mokapi/server/cert"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -12,10 +14,16 @@ type KafkaBroker struct {
 	server *kafka.Server
 }
 
-func NewKafkaBroker(port string, handler kafka.Handler) *KafkaBroker {
+func NewKafkaBroker(port string, handler kafka.Handler, store *cert.Store) *KafkaBroker {
 	b := &KafkaBroker{
 		server: &kafka.Server{Addr: fmt.Sprintf(":%v", port), Handler: handler},
 	}
+	if store != nil {
+		b.server.TLSConfig = &tls.Config{
+			GetCertificate:     store.GetCertificate,
+			InsecureSkipVerify: true,
+		}
+	}
 	return b
 }
 
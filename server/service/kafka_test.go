@@ -1,10 +1,14 @@
 package service
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"mokapi/config/static"
 	"mokapi/kafka"
 	"mokapi/kafka/apiVersion"
 	"mokapi/kafka/kafkatest"
+	"mokapi/server/cert"
 	"mokapi/try"
 	"testing"
 
@@ -21,7 +25,7 @@ func TestKafkaBroker(t *testing.T) {
 		err := rw.Write(&apiVersion.Response{ApiKeys: []apiVersion.ApiKeyResponse{{ApiKey: kafka.ApiVersions, MinVersion: 1, MaxVersion: 2}}})
 		require.NoError(t, err)
 	})
-	b := NewKafkaBroker(fmt.Sprintf("%v", port), handler)
+	b := NewKafkaBroker(fmt.Sprintf("%v", port), handler, nil)
 	b.Start()
 	defer b.Stop()
 
@@ -32,5 +36,28 @@ func TestKafkaBroker(t *testing.T) {
 	require.Equal(t, kafka.None, r.ErrorCode)
 	require.True(t, called, "handler should be called")
 	require.Equal(t, fmt.Sprintf(":%v", port), b.Addr())
+}
+
+func TestKafkaBroker_Tls(t *testing.T) {
+	t.Parallel()
+	store, err := cert.NewStore(&static.Config{})
+	require.NoError(t, err)
+
+	port := try.GetFreePort()
+	handler := kafka.HandlerFunc(func(rw kafka.ResponseWriter, req *kafka.Request) {
+		err := rw.Write(&apiVersion.Response{ApiKeys: []apiVersion.ApiKeyResponse{{ApiKey: kafka.ApiVersions, MinVersion: 1, MaxVersion: 2}}})
+		require.NoError(t, err)
+	})
+	b := NewKafkaBroker(fmt.Sprintf("%v", port), handler, store)
+	b.Start()
+	defer b.Stop()
 
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(cert.DefaultRootCert())
+	client := kafkatest.NewClient(fmt.Sprintf("localhost:%v", port), "test")
+	client.TLSConfig = &tls.Config{RootCAs: rootCAs}
+	defer client.Close()
+	r, err := client.ApiVersion(3, &apiVersion.Request{})
+	require.NoError(t, err)
+	require.Equal(t, kafka.None, r.ErrorCode)
 }
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"crypto/tls"
 	"fmt"
 	"mokapi/mqtt"
+	"mokapi/server/cert"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -12,10 +14,16 @@ type MqttBroker struct {
 	server *mqtt.Server
 }
 
-func NewMqttBroker(port string, handler mqtt.Handler) *MqttBroker {
+func NewMqttBroker(port string, handler mqtt.Handler, store *cert.Store) *MqttBroker {
 	b := &MqttBroker{
 		server: &mqtt.Server{Addr: fmt.Sprintf(":%v", port), Handler: handler},
 	}
+	if store != nil {
+		b.server.TLSConfig = &tls.Config{
+			GetCertificate:     store.GetCertificate,
+			InsecureSkipVerify: true,
+		}
+	}
 	return b
 }
 
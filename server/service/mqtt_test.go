@@ -1,9 +1,13 @@
 package service
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"mokapi/config/static"
 	"mokapi/mqtt"
 	"mokapi/mqtt/mqtttest"
+	"mokapi/server/cert"
 	"mokapi/try"
 	"testing"
 
@@ -27,7 +31,7 @@ func TestMqttBroker(t *testing.T) {
 			},
 		})
 	})
-	b := NewMqttBroker(fmt.Sprintf("%v", port), handler)
+	b := NewMqttBroker(fmt.Sprintf("%v", port), handler, nil)
 	b.Start()
 	defer b.Stop()
 
@@ -51,3 +55,37 @@ func TestMqttBroker(t *testing.T) {
 	require.True(t, called, "handler should be called")
 	require.Equal(t, fmt.Sprintf(":%v", port), b.Addr())
 }
+
+func TestMqttBroker_Tls(t *testing.T) {
+	t.Parallel()
+	store, err := cert.NewStore(&static.Config{})
+	require.NoError(t, err)
+
+	port := try.GetFreePort()
+	handler := mqtt.HandlerFunc(func(rw mqtt.MessageWriter, req *mqtt.Message) {
+		_ = rw.Write(&mqtt.Message{
+			Header:  &mqtt.Header{Type: mqtt.CONNACK},
+			Payload: &mqtt.ConnectResponse{ReasonCode: mqtt.Success},
+		})
+	})
+	b := NewMqttBroker(fmt.Sprintf("%v", port), handler, store)
+	b.Start()
+	defer b.Stop()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(cert.DefaultRootCert())
+	client := mqtttest.Client{Addr: fmt.Sprintf("localhost:%v", port), TLSConfig: &tls.Config{RootCAs: rootCAs}}
+	defer client.Close()
+	res, err := client.Send(&mqtt.Message{
+		Header: &mqtt.Header{Type: mqtt.CONNECT},
+		Payload: &mqtt.ConnectRequest{
+			Protocol:     "MQTT",
+			Version:      4,
+			CleanSession: true,
+			KeepAlive:    60,
+			ClientId:     "client-foo",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, mqtt.CONNACK, res.Header.Type)
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"crypto/tls"
+	"fmt"
+	"mokapi/pop3"
+	"mokapi/server/cert"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+type Pop3Server struct {
+	server *pop3.Server
+}
+
+func NewPop3Server(port string, handler pop3.Handler, store *cert.Store, tlsMode pop3.TlsMode) *Pop3Server {
+	s := &Pop3Server{
+		server: &pop3.Server{
+			Addr:    fmt.Sprintf(":%s", port),
+			Handler: handler,
+			TlsMode: tlsMode,
+			TLSConfig: &tls.Config{
+				GetCertificate:     store.GetCertificate,
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+	return s
+}
+
+func (s *Pop3Server) Addr() string {
+	return s.server.Addr
+}
+
+func (s *Pop3Server) Start() {
+	go func() {
+		err := s.server.ListenAndServe()
+		if !errors.Is(err, pop3.ErrServerClosed) {
+			log.Error(err)
+		}
+	}()
+}
+
+func (s *Pop3Server) Stop() {
+	s.server.Close()
+}
@@ -0,0 +1,89 @@
+package service_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"mokapi/config/static"
+	"mokapi/pop3"
+	"mokapi/providers/mail"
+	"mokapi/server/cert"
+	"mokapi/server/service"
+	"mokapi/try"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPop3Server(t *testing.T) {
+	testcases := []struct {
+		name    string
+		h       *mail.Handler
+		cert    func() *cert.Store
+		tlsMode pop3.TlsMode
+		test    func(t *testing.T, c *pop3.Client)
+	}{
+		{
+			name: "StartTLS",
+			h:    &mail.Handler{},
+			cert: func() *cert.Store {
+				s, _ := cert.NewStore(&static.Config{})
+				return s
+			},
+			tlsMode: pop3.StartTls,
+			test: func(t *testing.T, c *pop3.Client) {
+				_, err := c.Dial()
+				require.NoError(t, err)
+				_, lines, err := c.SendMulti("CAPA")
+				require.NoError(t, err)
+				require.Contains(t, lines, "STLS")
+			},
+		},
+		{
+			name: "Implicit",
+			h:    &mail.Handler{},
+			cert: func() *cert.Store {
+				s, _ := cert.NewStore(&static.Config{})
+				return s
+			},
+			tlsMode: pop3.Implicit,
+			test: func(t *testing.T, c *pop3.Client) {
+				rootCAs := x509.NewCertPool()
+				rootCAs.AddCert(cert.DefaultRootCert())
+				cfg := &tls.Config{
+					RootCAs: rootCAs,
+				}
+
+				res, err := c.DialTls(cfg)
+				require.NoError(t, err)
+				require.Contains(t, res, "+OK Mokapi POP3 server ready")
+				_, lines, err := c.SendMulti("CAPA")
+				require.NoError(t, err)
+				require.NotContains(t, lines, "STLS")
+			},
+		},
+	}
+
+	t.Parallel()
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			port := fmt.Sprintf("%v", try.GetFreePort())
+			s := service.NewPop3Server(port, tc.h, tc.cert(), tc.tlsMode)
+			s.Start()
+			defer s.Stop()
+
+			c := pop3.NewClient(fmt.Sprintf("localhost:%v", port))
+			defer c.Close()
+
+			tc.test(t, c)
+		})
+	}
+}
+
+func TestPop3Server_Addr(t *testing.T) {
+	s := service.NewPop3Server("1234", nil, nil, pop3.None)
+	require.Equal(t, ":1234", s.Addr())
+}
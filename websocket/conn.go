@@ -0,0 +1,303 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"unicode/utf8"
+)
+
+// MessageType is the type of data message as defined in RFC 6455
+type MessageType byte
+
+const (
+	continuationFrame byte = 0x0
+	TextMessage            = MessageType(0x1)
+	BinaryMessage          = MessageType(0x2)
+	closeFrame        byte = 0x8
+	pingFrame         byte = 0x9
+	pongFrame         byte = 0xA
+)
+
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseInvalidPayload  = 1007
+	ClosePolicyViolation = 1008
+	CloseMessageTooBig   = 1009
+	closeNoStatus        = 1005
+)
+
+// DefaultMaxMessageSize is the maximum size of a message read from a peer
+const DefaultMaxMessageSize = 16 << 20
+
+// CloseError is returned by ReadMessage when the peer closed the connection
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("websocket: close %d", e.Code)
+	}
+	return fmt.Sprintf("websocket: close %d: %s", e.Code, e.Reason)
+}
+
+// Conn is a WebSocket connection. A single goroutine may read while
+// other goroutines write concurrently.
+type Conn struct {
+	MaxMessageSize int64
+
+	conn     net.Conn
+	r        *bufio.Reader
+	isServer bool
+	closed   bool
+	wm       sync.Mutex
+}
+
+func newConn(conn net.Conn, r *bufio.Reader, isServer bool) *Conn {
+	if r == nil {
+		r = bufio.NewReader(conn)
+	}
+	return &Conn{
+		MaxMessageSize: DefaultMaxMessageSize,
+		conn:           conn,
+		r:              r,
+		isServer:       isServer,
+	}
+}
+
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+func (c *Conn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+// ReadMessage returns the next data message. Fragmented messages are
+// reassembled and control frames are handled transparently.
+func (c *Conn) ReadMessage() (MessageType, []byte, error) {
+	var messageType MessageType
+	var data []byte
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case pingFrame:
+			if err = c.writeFrame(pongFrame, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case pongFrame:
+			continue
+		case closeFrame:
+			ce := &CloseError{Code: closeNoStatus}
+			if len(payload) >= 2 {
+				ce.Code = int(binary.BigEndian.Uint16(payload))
+				ce.Reason = string(payload[2:])
+			}
+			_ = c.writeClose(ce.Code, "")
+			_ = c.conn.Close()
+			return 0, nil, ce
+		case continuationFrame:
+			if messageType == 0 {
+				return 0, nil, c.fail(CloseProtocolError, "unexpected continuation frame")
+			}
+		default:
+			if messageType != 0 {
+				return 0, nil, c.fail(CloseProtocolError, "expected continuation frame")
+			}
+			messageType = MessageType(opcode)
+			if messageType != TextMessage && messageType != BinaryMessage {
+				return 0, nil, c.fail(CloseProtocolError, fmt.Sprintf("unknown opcode %d", opcode))
+			}
+		}
+
+		if int64(len(data)+len(payload)) > c.MaxMessageSize {
+			return 0, nil, c.fail(CloseMessageTooBig, "message too big")
+		}
+		data = append(data, payload...)
+
+		if fin {
+			if messageType == TextMessage && !utf8.Valid(data) {
+				return 0, nil, c.fail(CloseInvalidPayload, "invalid UTF-8 in text message")
+			}
+			return messageType, data, nil
+		}
+	}
+}
+
+// WriteMessage writes a single data message
+func (c *Conn) WriteMessage(messageType MessageType, data []byte) error {
+	if messageType != TextMessage && messageType != BinaryMessage {
+		return fmt.Errorf("websocket: unsupported message type %d", messageType)
+	}
+	return c.writeFrame(byte(messageType), data)
+}
+
+// Ping sends a ping frame. The pong is consumed by ReadMessage.
+func (c *Conn) Ping(data []byte) error {
+	return c.writeFrame(pingFrame, data)
+}
+
+// Close sends a close frame with the given code and closes the
+// underlying connection.
+func (c *Conn) Close(code int, reason string) error {
+	err := c.writeClose(code, reason)
+	if cerr := c.conn.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (c *Conn) writeClose(code int, reason string) error {
+	c.wm.Lock()
+	defer c.wm.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	var payload []byte
+	if code != closeNoStatus {
+		payload = make([]byte, 2+len(reason))
+		binary.BigEndian.PutUint16(payload, uint16(code))
+		copy(payload[2:], reason)
+	}
+	return c.writeFrameLocked(closeFrame, payload)
+}
+
+func (c *Conn) fail(code int, reason string) error {
+	_ = c.Close(code, reason)
+	return &CloseError{Code: code, Reason: reason}
+}
+
+func (c *Conn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.r, header[:]); err != nil {
+		return
+	}
+	fin = header[0]&0x80 != 0
+	if header[0]&0x70 != 0 {
+		err = c.fail(CloseProtocolError, "reserved bits must be 0")
+		return
+	}
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	if masked != c.isServer {
+		if c.isServer {
+			err = c.fail(CloseProtocolError, "client frame must be masked")
+		} else {
+			err = c.fail(CloseProtocolError, "server frame must not be masked")
+		}
+		return
+	}
+
+	length := int64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var b [2]byte
+		if _, err = io.ReadFull(c.r, b[:]); err != nil {
+			return
+		}
+		length = int64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		if _, err = io.ReadFull(c.r, b[:]); err != nil {
+			return
+		}
+		length = int64(binary.BigEndian.Uint64(b[:]))
+	}
+
+	isControl := opcode&0x8 != 0
+	if isControl && (length > 125 || !fin) {
+		err = c.fail(CloseProtocolError, "invalid control frame")
+		return
+	}
+	if length < 0 || length > c.MaxMessageSize {
+		err = c.fail(CloseMessageTooBig, "message too big")
+		return
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.r, mask[:]); err != nil {
+			return
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.r, payload); err != nil {
+		return
+	}
+	if masked {
+		maskBytes(mask, payload)
+	}
+	return
+}
+
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	c.wm.Lock()
+	defer c.wm.Unlock()
+
+	if c.closed {
+		return errors.New("websocket: connection closed")
+	}
+	return c.writeFrameLocked(opcode, payload)
+}
+
+func (c *Conn) writeFrameLocked(opcode byte, payload []byte) error {
+	b := make([]byte, 0, 14+len(payload))
+	b = append(b, 0x80|opcode)
+
+	var maskBit byte
+	if !c.isServer {
+		maskBit = 0x80
+	}
+
+	length := len(payload)
+	switch {
+	case length <= 125:
+		b = append(b, maskBit|byte(length))
+	case length <= 0xFFFF:
+		b = append(b, maskBit|126)
+		b = binary.BigEndian.AppendUint16(b, uint16(length))
+	default:
+		b = append(b, maskBit|127)
+		b = binary.BigEndian.AppendUint64(b, uint64(length))
+	}
+
+	if c.isServer {
+		b = append(b, payload...)
+	} else {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		b = append(b, mask[:]...)
+		start := len(b)
+		b = append(b, payload...)
+		maskBytes(mask, b[start:])
+	}
+
+	_, err := c.conn.Write(b)
+	return err
+}
+
+func maskBytes(mask [4]byte, b []byte) {
+	for i := range b {
+		b[i] ^= mask[i%4]
+	}
+}
@@ -0,0 +1,107 @@
+package websocket_test
+
+import (
+	"errors"
+	"mokapi/websocket"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConn(t *testing.T) {
+	testcases := []struct {
+		name    string
+		handler func(t *testing.T, conn *websocket.Conn)
+		test    func(t *testing.T, conn *websocket.Conn)
+	}{
+		{
+			name: "echo text message",
+			handler: func(t *testing.T, conn *websocket.Conn) {
+				mt, b, err := conn.ReadMessage()
+				require.NoError(t, err)
+				require.NoError(t, conn.WriteMessage(mt, b))
+			},
+			test: func(t *testing.T, conn *websocket.Conn) {
+				require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+				mt, b, err := conn.ReadMessage()
+				require.NoError(t, err)
+				require.Equal(t, websocket.TextMessage, mt)
+				require.Equal(t, "hello", string(b))
+			},
+		},
+		{
+			name: "large binary message",
+			handler: func(t *testing.T, conn *websocket.Conn) {
+				mt, b, err := conn.ReadMessage()
+				require.NoError(t, err)
+				require.NoError(t, conn.WriteMessage(mt, b))
+			},
+			test: func(t *testing.T, conn *websocket.Conn) {
+				data := []byte(strings.Repeat("a", 70000))
+				require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, data))
+				mt, b, err := conn.ReadMessage()
+				require.NoError(t, err)
+				require.Equal(t, websocket.BinaryMessage, mt)
+				require.Equal(t, data, b)
+			},
+		},
+		{
+			name: "ping is answered while reading",
+			handler: func(t *testing.T, conn *websocket.Conn) {
+				_, b, err := conn.ReadMessage()
+				require.NoError(t, err)
+				require.NoError(t, conn.WriteMessage(websocket.TextMessage, b))
+			},
+			test: func(t *testing.T, conn *websocket.Conn) {
+				require.NoError(t, conn.Ping([]byte("ping")))
+				require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("after ping")))
+				_, b, err := conn.ReadMessage()
+				require.NoError(t, err)
+				require.Equal(t, "after ping", string(b))
+			},
+		},
+		{
+			name: "close by server",
+			handler: func(t *testing.T, conn *websocket.Conn) {
+				require.NoError(t, conn.Close(websocket.ClosePolicyViolation, "bye"))
+			},
+			test: func(t *testing.T, conn *websocket.Conn) {
+				_, _, err := conn.ReadMessage()
+				var ce *websocket.CloseError
+				require.True(t, errors.As(err, &ce))
+				require.Equal(t, websocket.ClosePolicyViolation, ce.Code)
+				require.Equal(t, "bye", ce.Reason)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			done := make(chan struct{})
+			s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				defer close(done)
+				conn, err := websocket.Upgrade(rw, r, nil)
+				require.NoError(t, err)
+				tc.handler(t, conn)
+			}))
+			defer s.Close()
+
+			conn, res, err := websocket.Dial("ws"+strings.TrimPrefix(s.URL, "http"), nil)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+			tc.test(t, conn)
+			<-done
+			_ = conn.Close(websocket.CloseNormal, "")
+		})
+	}
+}
+
+func TestUpgrade_InvalidRequest(t *testing.T) {
+	rr := httptest.NewRecorder()
+	_, err := websocket.Upgrade(rr, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+	require.Error(t, err)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,163 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// HandshakeError is returned by Upgrade if the request is not a valid
+// WebSocket handshake. The status code is already written to the client.
+type HandshakeError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HandshakeError) Error() string {
+	return fmt.Sprintf("websocket: %s", e.Message)
+}
+
+// IsUpgradeRequest reports whether the client requests a WebSocket connection
+func IsUpgradeRequest(r *http.Request) bool {
+	return headerContains(r.Header, "Connection", "upgrade") &&
+		headerContains(r.Header, "Upgrade", "websocket")
+}
+
+// Upgrade upgrades the HTTP server connection to the WebSocket protocol
+func Upgrade(rw http.ResponseWriter, r *http.Request, header http.Header) (*Conn, error) {
+	if r.Method != http.MethodGet {
+		return nil, handshakeError(rw, http.StatusMethodNotAllowed, "request method is not GET")
+	}
+	if !IsUpgradeRequest(r) {
+		return nil, handshakeError(rw, http.StatusBadRequest, "not a websocket upgrade request")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		rw.Header().Set("Sec-WebSocket-Version", "13")
+		return nil, handshakeError(rw, http.StatusUpgradeRequired, "unsupported version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if b, err := base64.StdEncoding.DecodeString(key); err != nil || len(b) != 16 {
+		return nil, handshakeError(rw, http.StatusBadRequest, "invalid Sec-WebSocket-Key")
+	}
+
+	// the response controller also supports wrapped response writers
+	netConn, brw, err := http.NewResponseController(rw).Hijack()
+	if err != nil {
+		return nil, handshakeError(rw, http.StatusInternalServerError, err.Error())
+	}
+
+	var sb strings.Builder
+	sb.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	sb.WriteString("Upgrade: websocket\r\n")
+	sb.WriteString("Connection: Upgrade\r\n")
+	sb.WriteString("Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n")
+	for k, values := range header {
+		for _, v := range values {
+			sb.WriteString(fmt.Sprintf("%s: %s\r\n", k, v))
+		}
+	}
+	sb.WriteString("\r\n")
+
+	if _, err := netConn.Write([]byte(sb.String())); err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+	return newConn(netConn, brw.Reader, true), nil
+}
+
+// Dial opens a client connection to a ws or wss URL
+func Dial(rawUrl string, header http.Header) (*Conn, *http.Response, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var netConn net.Conn
+	switch u.Scheme {
+	case "ws":
+		netConn, err = net.Dial("tcp", hostWithPort(u, "80"))
+	case "wss":
+		netConn, err = tls.Dial("tcp", hostWithPort(u, "443"), &tls.Config{ServerName: u.Hostname()})
+	default:
+		return nil, nil, fmt.Errorf("websocket: unsupported scheme '%v'", u.Scheme)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b := make([]byte, 16)
+	if _, err = rand.Read(b); err != nil {
+		_ = netConn.Close()
+		return nil, nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(b)
+
+	req := &http.Request{
+		Method: http.MethodGet,
+		URL:    &url.URL{Scheme: "http", Host: u.Host, Path: u.Path, RawQuery: u.RawQuery},
+		Header: http.Header{},
+		Host:   u.Host,
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	if err = req.Write(netConn); err != nil {
+		_ = netConn.Close()
+		return nil, nil, err
+	}
+
+	br := bufio.NewReader(netConn)
+	res, err := http.ReadResponse(br, req)
+	if err != nil {
+		_ = netConn.Close()
+		return nil, nil, err
+	}
+	if res.StatusCode != http.StatusSwitchingProtocols || res.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		_ = netConn.Close()
+		return nil, res, fmt.Errorf("websocket: bad handshake: %v", res.Status)
+	}
+
+	return newConn(netConn, br, false), res, nil
+}
+
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func handshakeError(rw http.ResponseWriter, status int, msg string) error {
+	http.Error(rw, http.StatusText(status), status)
+	return &HandshakeError{StatusCode: status, Message: msg}
+}
+
+func headerContains(h http.Header, name, value string) bool {
+	for _, v := range h.Values(name) {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hostWithPort(u *url.URL, defaultPort string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), defaultPort)
+}
@@ -12,6 +12,8 @@ export function useLdap() {
             case 'ModifyDN':
             case 'Compare':
                 return data.request.dn
+            case 'PasswordModify':
+                return data.request.userIdentity
         }
     }
 
@@ -118,6 +118,7 @@ declare interface KafkaMessageData {
   sequenceNumber: number
   clientId: string
   script: string
+  compression?: string
 }
 
 declare interface KafkaHeader { [name: string]: KafkaHeaderValue }
@@ -151,8 +152,8 @@ declare interface KafkaClient {
 
 declare interface KafkaRequestLog {
   header: KafkaRequestHeader
-  request:  KafkaJoinGroupRequest | KafkaSyncGroupRequest | KafkaFindCoordinatorRequest | KafkaInitProducerIdRequest
-  response: KafkaJoinGroupResponse | KafkaSyncGroupResponse | KafkaFindCoordinatorResponse | KafkaInitProducerIdResponse
+  request:  KafkaJoinGroupRequest | KafkaSyncGroupRequest | KafkaFindCoordinatorRequest | KafkaInitProducerIdRequest | KafkaLeaveGroupRequest | KafkaDeleteGroupsRequest
+  response: KafkaJoinGroupResponse | KafkaSyncGroupResponse | KafkaFindCoordinatorResponse | KafkaInitProducerIdResponse | KafkaLeaveGroupResponse | KafkaDeleteGroupsResponse
 }
 
 declare interface KafkaResponseError {
@@ -196,6 +197,29 @@ declare interface KafkaSyncGroupResponse extends KafkaResponseError {
   assignment: KafkaGroupAssignment
 }
 
+declare interface KafkaLeaveGroupRequest {
+  groupName: string
+  members: string[]
+}
+
+declare interface KafkaLeaveGroupResponse extends KafkaResponseError {
+  members: KafkaLeaveGroupMember[]
+}
+
+declare interface KafkaLeaveGroupMember {
+  memberId: string
+  errorCode?: string
+}
+
+declare interface KafkaDeleteGroupsRequest {
+  groups: string[]
+}
+
+declare interface KafkaDeleteGroupsResponse {
+  // group name: error code
+  groups: { [name: string]: string }
+}
+
 declare interface KafkaGroupAssignment {
   version: number
   // topic: partition index
@@ -3,8 +3,8 @@ declare interface LdapService extends Service {
 }
 
 declare interface LdapEventData {
-    request: LdapBindRequest | LdapUnbindRequest | LdapSearchRequest | LdapModifyRequest | LdapAddRequest | LdapDeleteRequest | LdapModifDNRequest | LdapCompareRequest
-    response: LdapSearchResponse | LdapResponse
+    request: LdapBindRequest | LdapUnbindRequest | LdapSearchRequest | LdapModifyRequest | LdapAddRequest | LdapDeleteRequest | LdapModifDNRequest | LdapCompareRequest | LdapExtendedRequest
+    response: LdapSearchResponse | LdapResponse | LdapExtendedResponse
     duration: number
     actions: Action[]
 }
@@ -89,4 +89,19 @@ declare interface LdapCompareRequest {
 	dn: string
 	attribute: string
     value: string
+}
+
+declare interface LdapExtendedRequest {
+    operation: 'WhoAmI' | 'PasswordModify' | 'StartTLS'
+    name: string
+    userIdentity?: string
+    oldPassword?: string
+    newPassword?: string
+}
+
+declare interface LdapExtendedResponse {
+    status: string
+    matchedDn: string
+    message: string
+    value?: string
 }
\ No newline at end of file
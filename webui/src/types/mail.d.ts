@@ -51,6 +51,15 @@ declare interface SmtpEventData {
     duration: number
     error: string
     actions: Action[]
+    session?: MailSession
+}
+
+declare interface MailSession {
+    protocol: string
+    client: string
+    mailbox: string
+    retrieved?: string[]
+    deleted?: string[]
 }
 
 declare interface MessageInfo {
@@ -61,8 +61,12 @@ declare interface MqttMessageData {
   topic: string
   message: MqttMessage;
   messageId: string
+  qos: number
+  retain: boolean
+  userProperties?: { [name: string]: string }
   clientId: string
   script: string
+  actions: Action[]
 }
 
 declare interface MqttMessage {